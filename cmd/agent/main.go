@@ -24,12 +24,23 @@ import (
 	"github.com/memohai/memoh/internal/boot"
 	"github.com/memohai/memoh/internal/bots"
 	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/dingtalk"
 	"github.com/memohai/memoh/internal/channel/adapters/discord"
+	"github.com/memohai/memoh/internal/channel/adapters/email"
 	"github.com/memohai/memoh/internal/channel/adapters/feishu"
 	"github.com/memohai/memoh/internal/channel/adapters/local"
+	"github.com/memohai/memoh/internal/channel/adapters/matrix"
 	"github.com/memohai/memoh/internal/channel/adapters/telegram"
+	"github.com/memohai/memoh/internal/channel/adapters/webhook"
+	"github.com/memohai/memoh/internal/channel/adapters/wecom"
+	"github.com/memohai/memoh/internal/channel/approval"
+	"github.com/memohai/memoh/internal/channel/bridge"
+	"github.com/memohai/memoh/internal/channel/handoff"
 	"github.com/memohai/memoh/internal/channel/identities"
 	"github.com/memohai/memoh/internal/channel/inbound"
+	"github.com/memohai/memoh/internal/channel/moderation"
+	"github.com/memohai/memoh/internal/channel/outbox"
+	"github.com/memohai/memoh/internal/channel/ratelimit"
 	"github.com/memohai/memoh/internal/channel/route"
 	"github.com/memohai/memoh/internal/config"
 	ctr "github.com/memohai/memoh/internal/containerd"
@@ -41,6 +52,7 @@ import (
 	"github.com/memohai/memoh/internal/handlers"
 	"github.com/memohai/memoh/internal/healthcheck"
 	channelchecker "github.com/memohai/memoh/internal/healthcheck/checkers/channel"
+	containerchecker "github.com/memohai/memoh/internal/healthcheck/checkers/container"
 	mcpchecker "github.com/memohai/memoh/internal/healthcheck/checkers/mcp"
 	"github.com/memohai/memoh/internal/inbox"
 	"github.com/memohai/memoh/internal/logger"
@@ -66,6 +78,7 @@ import (
 	"github.com/memohai/memoh/internal/searchproviders"
 	"github.com/memohai/memoh/internal/server"
 	"github.com/memohai/memoh/internal/settings"
+	"github.com/memohai/memoh/internal/speech"
 	"github.com/memohai/memoh/internal/storage/providers/containerfs"
 	"github.com/memohai/memoh/internal/subagent"
 	"github.com/memohai/memoh/internal/version"
@@ -163,19 +176,28 @@ func runServe() {
 			bind.NewService,
 			event.NewHub,
 			inbox.NewService,
+			bridge.NewService,
 
 			// services requiring provide functions
 			provideRouteService,
 			provideMessageService,
 			provideMediaService,
+			provideSpeechService,
+			provideModerationService,
+			provideHandoffService,
+			provideApprovalService,
+			provideRateLimitService,
 
 			// channel infrastructure
 			local.NewRouteHub,
 			provideChannelRegistry,
 			channel.NewStore,
+			provideInboundJobStore,
+			provideOutboundDeliveryStore,
 			provideChannelRouter,
 			provideChannelManager,
 			provideChannelLifecycleService,
+			provideOutboxService,
 
 			// conversation flow
 			provideChatResolver,
@@ -206,9 +228,22 @@ func runServe() {
 			provideServerHandler(handlers.NewSubagentHandler),
 			provideServerHandler(handlers.NewChannelHandler),
 			provideServerHandler(feishu.NewWebhookServerHandler),
+			provideServerHandler(telegram.NewWebhookServerHandler),
+			provideServerHandler(webhook.NewWebhookServerHandler),
+			provideServerHandler(wecom.NewWebhookServerHandler),
+			provideServerHandler(dingtalk.NewWebhookServerHandler),
 			provideServerHandler(provideUsersHandler),
 			provideServerHandler(handlers.NewMCPHandler),
 			provideServerHandler(handlers.NewInboxHandler),
+			provideServerHandler(handlers.NewChannelInboundHandler),
+			provideServerHandler(handlers.NewChannelDeliveryHandler),
+			provideServerHandler(handlers.NewChannelRouteHandler),
+			provideServerHandler(handlers.NewChannelBridgeHandler),
+			provideServerHandler(handlers.NewChannelModerationHandler),
+			provideServerHandler(handlers.NewChannelHandoffHandler),
+			provideServerHandler(handlers.NewChannelOutboxHandler),
+			provideServerHandler(handlers.NewChannelApprovalHandler),
+			provideServerHandler(handlers.NewChannelRateLimitHandler),
 			provideServerHandler(provideCLIHandler),
 			provideServerHandler(provideWebHandler),
 
@@ -219,7 +254,11 @@ func runServe() {
 			startScheduleService,
 			startHeartbeatService,
 			startChannelManager,
+			startOutboxDispatcher,
 			startContainerReconciliation,
+			startOOMWatcher,
+			startEgressWatcher,
+			startDiskWatcher,
 			startServer,
 		),
 		fx.WithLogger(func(logger *slog.Logger) fxevent.Logger {
@@ -412,6 +451,20 @@ func provideChannelRegistry(log *slog.Logger, hub *local.RouteHub, mediaService
 	feishuAdapter := feishu.NewFeishuAdapter(log)
 	feishuAdapter.SetAssetOpener(mediaService)
 	registry.MustRegister(feishuAdapter)
+
+	matrixAdapter := matrix.NewMatrixAdapter(log)
+	matrixAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(matrixAdapter)
+	emailAdapter := email.NewEmailAdapter(log)
+	emailAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(emailAdapter)
+	registry.MustRegister(webhook.NewWebhookAdapter(log))
+	wecomAdapter := wecom.NewWeComAdapter(log)
+	wecomAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(wecomAdapter)
+	dingtalkAdapter := dingtalk.NewDingTalkAdapter(log)
+	dingtalkAdapter.SetAssetOpener(mediaService)
+	registry.MustRegister(dingtalkAdapter)
   
 	registry.MustRegister(local.NewCLIAdapter(hub))
 	registry.MustRegister(local.NewWebAdapter(hub))
@@ -431,21 +484,89 @@ func provideChannelRouter(
 	preauthService *preauth.Service,
 	bindService *bind.Service,
 	mediaService *media.Service,
+	speechService *speech.Service,
 	inboxService *inbox.Service,
+	bridgeService *bridge.Service,
+	handoffService *handoff.Service,
+	rateLimitService *ratelimit.Service,
+	modelsService *models.Service,
+	queries *dbsqlc.Queries,
 	rc *boot.RuntimeConfig,
 ) *inbound.ChannelInboundProcessor {
 	processor := inbound.NewChannelInboundProcessor(log, registry, routeService, msgService, resolver, identityService, botService, policyService, preauthService, bindService, rc.JwtSecret, 5*time.Minute)
 	processor.SetMediaService(mediaService)
+	processor.SetTranscriber(speechService)
+	processor.SetSynthesizer(speechService)
+	processor.SetTriggerClassifier(&lazyLLMClient{
+		modelsService: modelsService,
+		queries:       queries,
+		timeout:       10 * time.Second,
+		logger:        log,
+	})
 	processor.SetStreamObserver(local.NewRouteHubBroadcaster(hub))
 	processor.SetInboxService(inboxService)
+	processor.SetBridge(bridgeService)
+	processor.SetHandoff(handoffService)
+	processor.SetTurnLimiter(rateLimitService)
 	return processor
 }
 
-func provideChannelManager(log *slog.Logger, registry *channel.Registry, channelStore *channel.Store, channelRouter *inbound.ChannelInboundProcessor) *channel.Manager {
+func provideModerationService(log *slog.Logger, queries *dbsqlc.Queries, modelsService *models.Service, policyService *policy.Service, identityService *identities.Service) *moderation.Service {
+	svc := moderation.NewService(log, queries, policyService, identityService)
+	svc.SetClassifier(&lazyLLMClient{
+		modelsService: modelsService,
+		queries:       queries,
+		timeout:       10 * time.Second,
+		logger:        log,
+	})
+	return svc
+}
+
+func provideHandoffService(log *slog.Logger, queries *dbsqlc.Queries, msgService *message.DBService, memoryService *memory.Service) *handoff.Service {
+	return handoff.NewService(log, queries, msgService, memoryService)
+}
+
+func provideOutboxService(log *slog.Logger, queries *dbsqlc.Queries, channelManager *channel.Manager, registry *channel.Registry, settingsService *settings.Service, approvalService *approval.Service) *outbox.Service {
+	svc := outbox.NewService(log, queries, channelManager, registry, settingsService)
+	approvalService.SetOutbox(svc)
+	return svc
+}
+
+func provideApprovalService(log *slog.Logger, queries *dbsqlc.Queries, policyService *policy.Service, identityService *identities.Service) *approval.Service {
+	return approval.NewService(log, queries, policyService, identityService)
+}
+
+func provideRateLimitService(log *slog.Logger, queries *dbsqlc.Queries, policyService *policy.Service, settingsService *settings.Service) *ratelimit.Service {
+	return ratelimit.NewService(log, queries, policyService, settingsService)
+}
+
+func provideInboundJobStore(log *slog.Logger, queries *dbsqlc.Queries, channelStore *channel.Store) *channel.InboundJobStore {
+	return channel.NewInboundJobStore(log, queries, channelStore)
+}
+
+func provideOutboundDeliveryStore(log *slog.Logger, queries *dbsqlc.Queries) *channel.OutboundDeliveryDBStore {
+	return channel.NewOutboundDeliveryDBStore(log, queries)
+}
+
+func provideChannelManager(log *slog.Logger, cfg config.Config, registry *channel.Registry, channelStore *channel.Store, channelRouter *inbound.ChannelInboundProcessor, inboundJobs *channel.InboundJobStore, deliveries *channel.OutboundDeliveryDBStore, bridgeService *bridge.Service, moderationService *moderation.Service, handoffService *handoff.Service, approvalService *approval.Service, rateLimitService *ratelimit.Service) *channel.Manager {
 	mgr := channel.NewManager(log, registry, channelStore, channelRouter)
+	mgr.SetInboundQueue(inboundJobs)
+	mgr.SetOutboundDeliveryStore(deliveries)
+	mgr.SetInboundWorkers(cfg.Channel.InboundWorkers)
+	bridgeService.SetSender(mgr)
+	moderationService.SetSender(mgr)
+	handoffService.SetSender(mgr)
+	approvalService.SetSender(mgr)
+	rateLimitService.SetSender(mgr)
 	if mw := channelRouter.IdentityMiddleware(); mw != nil {
 		mgr.Use(mw)
 	}
+	// Approval answers are recognized by the owner's identity, so they follow identity resolution.
+	mgr.Use(approvalService.Middleware())
+	// Moderation runs after identity resolution so it can exempt the bot owner.
+	mgr.Use(moderationService.Middleware())
+	// Rate limits follow moderation so dropped spam does not count against a sender's quota.
+	mgr.Use(rateLimitService.Middleware())
 	return mgr
 }
 
@@ -461,12 +582,15 @@ func provideContainerdHandler(log *slog.Logger, service ctr.Service, manager *mc
 	return handlers.NewContainerdHandler(log, service, manager, cfg.MCP, cfg.Containerd.Namespace, rc.ContainerBackend, botService, accountService, policyService, queries)
 }
 
-func provideToolGatewayService(log *slog.Logger, cfg config.Config, channelManager *channel.Manager, registry *channel.Registry, routeService *route.DBService, scheduleService *schedule.Service, memoryService *memory.Service, chatService *conversation.Service, accountService *accounts.Service, settingsService *settings.Service, searchProviderService *searchproviders.Service, manager *mcp.Manager, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, mediaService *media.Service, inboxService *inbox.Service) *mcp.ToolGatewayService {
+func provideToolGatewayService(log *slog.Logger, cfg config.Config, channelManager *channel.Manager, registry *channel.Registry, routeService *route.DBService, msgService *message.DBService, scheduleService *schedule.Service, memoryService *memory.Service, chatService *conversation.Service, accountService *accounts.Service, settingsService *settings.Service, searchProviderService *searchproviders.Service, manager *mcp.Manager, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, mediaService *media.Service, inboxService *inbox.Service, outboxService *outbox.Service, approvalService *approval.Service) *mcp.ToolGatewayService {
 	var assetResolver mcpmessage.AssetResolver
 	if mediaService != nil {
 		assetResolver = &mediaAssetResolverAdapter{media: mediaService}
 	}
-	messageExec := mcpmessage.NewExecutor(log, channelManager, channelManager, registry, assetResolver)
+	messageExec := mcpmessage.NewExecutor(log, channelManager, channelManager, channelManager, registry, assetResolver)
+	messageExec.SetSentMessageLookup(msgService)
+	messageExec.SetScheduler(outboxService)
+	messageExec.SetOutboundGuard(approvalService)
 	contactsExec := mcpcontacts.NewExecutor(log, routeService)
 	scheduleExec := mcpschedule.NewExecutor(log, scheduleService)
 	memoryExec := mcpmemory.NewExecutor(log, memoryService, chatService, accountService)
@@ -521,6 +645,10 @@ func provideMediaService(log *slog.Logger, cfg config.Config) (*media.Service, e
 	return media.NewService(log, provider), nil
 }
 
+func provideSpeechService(log *slog.Logger, queries *dbsqlc.Queries, mediaService *media.Service) *speech.Service {
+	return speech.NewService(log, queries, mediaService)
+}
+
 func provideUsersHandler(log *slog.Logger, accountService *accounts.Service, identityService *identities.Service, botService *bots.Service, routeService *route.DBService, channelStore *channel.Store, channelLifecycle *channel.Lifecycle, channelManager *channel.Manager, registry *channel.Registry) *handlers.UsersHandler {
 	return handlers.NewUsersHandler(log, accountService, identityService, botService, routeService, channelStore, channelLifecycle, channelManager, registry)
 }
@@ -601,6 +729,20 @@ func startChannelManager(lc fx.Lifecycle, channelManager *channel.Manager) {
 	})
 }
 
+func startOutboxDispatcher(lc fx.Lifecycle, outboxService *outbox.Service) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			outboxService.Start(ctx)
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
 func startContainerReconciliation(lc fx.Lifecycle, containerdHandler *handlers.ContainerdHandler, _ *mcp.ToolGatewayService) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -610,7 +752,64 @@ func startContainerReconciliation(lc fx.Lifecycle, containerdHandler *handlers.C
 	})
 }
 
-func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutdowner fx.Shutdowner, cfg config.Config, queries *dbsqlc.Queries, botService *bots.Service, containerdHandler *handlers.ContainerdHandler, mcpConnService *mcp.ConnectionService, toolGateway *mcp.ToolGatewayService, channelManager *channel.Manager) {
+// startOOMWatcher records OOM kills of bot containers so they show up in bot checks.
+func startOOMWatcher(lc fx.Lifecycle, logger *slog.Logger, manager *mcp.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				if err := manager.WatchOOM(ctx); err != nil {
+					logger.Info("container OOM events unavailable", slog.Any("error", err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// startEgressWatcher records connections denied by bot egress policies.
+func startEgressWatcher(lc fx.Lifecycle, logger *slog.Logger, manager *mcp.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				if err := manager.WatchEgress(ctx); err != nil {
+					logger.Info("container egress denial log unavailable", slog.Any("error", err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// startDiskWatcher enforces the disk quota of running bot containers.
+func startDiskWatcher(lc fx.Lifecycle, logger *slog.Logger, manager *mcp.Manager) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				if err := manager.WatchDisk(ctx); err != nil {
+					logger.Warn("container disk quota watcher stopped", slog.Any("error", err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutdowner fx.Shutdowner, cfg config.Config, queries *dbsqlc.Queries, botService *bots.Service, containerdHandler *handlers.ContainerdHandler, manager *mcp.Manager, mcpConnService *mcp.ConnectionService, toolGateway *mcp.ToolGatewayService, channelManager *channel.Manager) {
 	fmt.Printf("Starting Memoh Agent %s\n", version.GetInfo())
 
 	lc.Append(fx.Hook{
@@ -625,6 +824,9 @@ func startServer(lc fx.Lifecycle, logger *slog.Logger, srv *server.Server, shutd
 			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(
 				channelchecker.NewChecker(logger, channelManager),
 			))
+			botService.AddRuntimeChecker(healthcheck.NewRuntimeCheckerAdapter(
+				containerchecker.NewChecker(logger, manager),
+			))
 
 			go func() {
 				if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -765,7 +967,25 @@ func (c *lazyLLMClient) DetectLanguage(ctx context.Context, text string) (string
 	return client.DetectLanguage(ctx, text)
 }
 
-func (c *lazyLLMClient) resolve(ctx context.Context) (memory.LLM, error) {
+// IsAddressed classifies group messages for the "smart" trigger mode with the bot's memory model.
+func (c *lazyLLMClient) IsAddressed(ctx context.Context, botID, text string, names []string) (bool, error) {
+	client, err := c.resolve(memory.WithBotID(ctx, botID))
+	if err != nil {
+		return false, err
+	}
+	return client.IsAddressed(ctx, text, names)
+}
+
+// IsFlagged runs the moderation model check with the bot's memory model.
+func (c *lazyLLMClient) IsFlagged(ctx context.Context, botID, text string) (bool, string, error) {
+	client, err := c.resolve(memory.WithBotID(ctx, botID))
+	if err != nil {
+		return false, "", err
+	}
+	return client.Moderate(ctx, text)
+}
+
+func (c *lazyLLMClient) resolve(ctx context.Context) (*memory.LLMClient, error) {
 	if c.modelsService == nil || c.queries == nil {
 		return nil, fmt.Errorf("models service not configured")
 	}
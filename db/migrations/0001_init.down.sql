@@ -1,3 +1,20 @@
+DROP TABLE IF EXISTS bot_egress_denials;
+DROP TABLE IF EXISTS bot_egress_policies;
+DROP TABLE IF EXISTS bot_container_resources;
+DROP TABLE IF EXISTS channel_rate_usage;
+DROP TABLE IF EXISTS channel_rate_limit_policies;
+DROP TABLE IF EXISTS channel_outbound_approvals;
+DROP TABLE IF EXISTS channel_outbound_policies;
+DROP TABLE IF EXISTS channel_outbox_messages;
+DROP TABLE IF EXISTS channel_handoffs;
+DROP TABLE IF EXISTS channel_moderation_events;
+DROP TABLE IF EXISTS channel_moderation_policies;
+DROP TABLE IF EXISTS channel_bridge_messages;
+DROP TABLE IF EXISTS channel_bridge_routes;
+DROP TABLE IF EXISTS channel_bridges;
+DROP TABLE IF EXISTS media_transcriptions;
+DROP TABLE IF EXISTS channel_outbound_deliveries;
+DROP TABLE IF EXISTS channel_inbound_jobs;
 DROP TABLE IF EXISTS bot_history_message_assets;
 DROP TABLE IF EXISTS media_assets;
 DROP TABLE IF EXISTS bot_storage_bindings;
@@ -90,7 +90,7 @@ CREATE TABLE IF NOT EXISTS models (
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   CONSTRAINT models_provider_model_id_unique UNIQUE (llm_provider_id, model_id),
-  CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding')),
+  CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding', 'transcription', 'speech')),
   CONSTRAINT models_dimensions_check CHECK (type != 'embedding' OR dimensions IS NOT NULL),
   CONSTRAINT models_client_type_check CHECK (client_type IS NULL OR client_type IN ('openai-responses', 'openai-completions', 'anthropic-messages', 'google-generative-ai')),
   CONSTRAINT models_chat_client_type_check CHECK (type != 'chat' OR client_type IS NOT NULL)
@@ -132,6 +132,11 @@ CREATE TABLE IF NOT EXISTS bots (
   heartbeat_interval INTEGER NOT NULL DEFAULT 30,
   heartbeat_prompt TEXT NOT NULL DEFAULT '',
   heartbeat_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
+  transcription_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
+  speech_model_id UUID REFERENCES models(id) ON DELETE SET NULL,
+  speech_voice TEXT NOT NULL DEFAULT 'alloy',
+  voice_reply_enabled BOOLEAN NOT NULL DEFAULT false,
+  timezone TEXT NOT NULL DEFAULT 'UTC',
   metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
   created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
   updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
@@ -426,3 +431,232 @@ CREATE TABLE IF NOT EXISTS bot_heartbeat_logs (
 );
 
 CREATE INDEX IF NOT EXISTS idx_heartbeat_logs_bot_started ON bot_heartbeat_logs(bot_id, started_at DESC);
+
+-- channel_inbound_jobs: durable inbound queue with retry and dead-letter state.
+CREATE TABLE IF NOT EXISTS channel_inbound_jobs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_config_id TEXT NOT NULL DEFAULT '',
+  channel_type TEXT NOT NULL,
+  external_message_id TEXT NOT NULL DEFAULT '',
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'done', 'dead')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  available_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  locked_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  route_key TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_channel_inbound_jobs_external_message
+  ON channel_inbound_jobs(bot_id, channel_type, external_message_id)
+  WHERE external_message_id <> '';
+CREATE INDEX IF NOT EXISTS idx_channel_inbound_jobs_available ON channel_inbound_jobs(available_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX IF NOT EXISTS idx_channel_inbound_jobs_dead ON channel_inbound_jobs(bot_id, updated_at DESC) WHERE status = 'dead';
+
+-- channel_outbound_deliveries: outbound delivery outcomes for inspection and resend.
+CREATE TABLE IF NOT EXISTS channel_outbound_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_config_id TEXT NOT NULL DEFAULT '',
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  status TEXT NOT NULL CHECK (status IN ('sent', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  chunks_total INTEGER NOT NULL DEFAULT 0,
+  chunks_sent INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  platform_message_ids JSONB NOT NULL DEFAULT '[]'::jsonb
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_deliveries_bot ON channel_outbound_deliveries(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_deliveries_failed ON channel_outbound_deliveries(bot_id, updated_at DESC) WHERE status = 'failed';
+
+-- media_transcriptions: speech-to-text results cached by content hash.
+CREATE TABLE IF NOT EXISTS media_transcriptions (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  content_hash TEXT NOT NULL,
+  model_id UUID NOT NULL REFERENCES models(id) ON DELETE CASCADE,
+  text TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, content_hash, model_id)
+);
+
+-- channel_bridges: routes linked so user messages are relayed between them.
+CREATE TABLE IF NOT EXISTS channel_bridges (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  name TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_bridges_bot ON channel_bridges(bot_id);
+
+CREATE TABLE IF NOT EXISTS channel_bridge_routes (
+  bridge_id UUID NOT NULL REFERENCES channel_bridges(id) ON DELETE CASCADE,
+  route_id UUID NOT NULL REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  relay_outgoing BOOLEAN NOT NULL DEFAULT true,
+  relay_incoming BOOLEAN NOT NULL DEFAULT true,
+  outgoing_filter JSONB NOT NULL DEFAULT '{}'::jsonb,
+  incoming_filter JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bridge_id, route_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_bridge_routes_route ON channel_bridge_routes(route_id);
+
+CREATE TABLE IF NOT EXISTS channel_bridge_messages (
+  bridge_id UUID NOT NULL REFERENCES channel_bridges(id) ON DELETE CASCADE,
+  source_route_id UUID NOT NULL REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  source_message_id TEXT NOT NULL,
+  target_route_id UUID NOT NULL REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  target_message_id TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (target_route_id, target_message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_bridge_messages_source ON channel_bridge_messages(source_route_id, source_message_id);
+
+-- channel_moderation: per-bot inbound moderation policy and the events it produced.
+CREATE TABLE IF NOT EXISTS channel_moderation_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS channel_moderation_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  conversation_id TEXT NOT NULL DEFAULT '',
+  sender_subject_id TEXT NOT NULL,
+  sender_name TEXT NOT NULL DEFAULT '',
+  message_id TEXT NOT NULL DEFAULT '',
+  rule TEXT NOT NULL,
+  action TEXT NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  content TEXT NOT NULL DEFAULT '',
+  muted_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_moderation_events_bot_created ON channel_moderation_events(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_channel_moderation_events_mutes ON channel_moderation_events(bot_id, channel_type, sender_subject_id, muted_until)
+  WHERE muted_until IS NOT NULL;
+
+-- channel_handoffs: routes taken over by a human operator.
+CREATE TABLE IF NOT EXISTS channel_handoffs (
+  route_id UUID PRIMARY KEY REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  started_by TEXT NOT NULL DEFAULT '',
+  reason TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ,
+  last_reply_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_handoffs_bot ON channel_handoffs(bot_id);
+
+-- channel_outbox_messages: scheduled outbound messages delivered by the outbox dispatcher.
+CREATE TABLE IF NOT EXISTS channel_outbox_messages (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  source TEXT NOT NULL DEFAULT 'api',
+  created_by TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'canceled')),
+  send_at TIMESTAMPTZ NOT NULL,
+  available_at TIMESTAMPTZ NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  locked_until TIMESTAMPTZ,
+  platform_message_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbox_messages_due ON channel_outbox_messages(available_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS idx_channel_outbox_messages_bot ON channel_outbox_messages(bot_id, send_at DESC);
+
+-- channel_outbound_policies / channel_outbound_approvals: owner approval for proactive messages.
+CREATE TABLE IF NOT EXISTS channel_outbound_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS channel_outbound_approvals (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  send_at TIMESTAMPTZ,
+  requested_by TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+  decided_by TEXT NOT NULL DEFAULT '',
+  decided_at TIMESTAMPTZ,
+  outbox_message_id UUID,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_approvals_bot_created ON channel_outbound_approvals(bot_id, created_at DESC);
+
+-- channel_rate_limit_policies / channel_rate_usage: per-role inbound rate limits and daily per-sender counters.
+CREATE TABLE IF NOT EXISTS channel_rate_limit_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS channel_rate_usage (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_identity_id UUID NOT NULL REFERENCES channel_identities(id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  channel_type TEXT NOT NULL DEFAULT '',
+  display_name TEXT NOT NULL DEFAULT '',
+  role TEXT NOT NULL DEFAULT 'guest',
+  messages INTEGER NOT NULL DEFAULT 0,
+  turns INTEGER NOT NULL DEFAULT 0,
+  limited INTEGER NOT NULL DEFAULT 0,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, day, channel_identity_id)
+);
+
+-- bot_container_resources: per-bot overrides of the default container resource limits.
+CREATE TABLE IF NOT EXISTS bot_container_resources (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  resources JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- bot_egress_policies / bot_egress_denials: per-bot container egress modes and the outbound connections they denied.
+CREATE TABLE IF NOT EXISTS bot_egress_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS bot_egress_denials (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  destination TEXT NOT NULL,
+  port INTEGER NOT NULL DEFAULT 0,
+  protocol TEXT NOT NULL DEFAULT '',
+  hits INTEGER NOT NULL DEFAULT 1,
+  first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, destination, port, protocol)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_egress_denials_bot_last_seen ON bot_egress_denials(bot_id, last_seen_at DESC);
@@ -0,0 +1,7 @@
+-- 0018_channel_inbound_jobs (down)
+-- Remove durable inbound job queue.
+
+DROP INDEX IF EXISTS idx_channel_inbound_jobs_dead;
+DROP INDEX IF EXISTS idx_channel_inbound_jobs_available;
+DROP INDEX IF EXISTS idx_channel_inbound_jobs_external_message;
+DROP TABLE IF EXISTS channel_inbound_jobs;
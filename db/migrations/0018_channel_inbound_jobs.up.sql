@@ -0,0 +1,24 @@
+-- 0018_channel_inbound_jobs
+-- Add durable inbound job queue with retry and dead-letter state.
+
+CREATE TABLE IF NOT EXISTS channel_inbound_jobs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_config_id TEXT NOT NULL DEFAULT '',
+  channel_type TEXT NOT NULL,
+  external_message_id TEXT NOT NULL DEFAULT '',
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'done', 'dead')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  available_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  locked_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_channel_inbound_jobs_external_message
+  ON channel_inbound_jobs(bot_id, channel_type, external_message_id)
+  WHERE external_message_id <> '';
+CREATE INDEX IF NOT EXISTS idx_channel_inbound_jobs_available ON channel_inbound_jobs(available_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX IF NOT EXISTS idx_channel_inbound_jobs_dead ON channel_inbound_jobs(bot_id, updated_at DESC) WHERE status = 'dead';
@@ -0,0 +1,6 @@
+-- 0019_channel_outbound_deliveries (down)
+-- Remove outbound delivery records.
+
+DROP INDEX IF EXISTS idx_channel_outbound_deliveries_failed;
+DROP INDEX IF EXISTS idx_channel_outbound_deliveries_bot;
+DROP TABLE IF EXISTS channel_outbound_deliveries;
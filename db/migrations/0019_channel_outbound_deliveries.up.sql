@@ -0,0 +1,21 @@
+-- 0019_channel_outbound_deliveries
+-- Record outbound delivery outcomes so failed replies can be inspected and resent.
+
+CREATE TABLE IF NOT EXISTS channel_outbound_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_config_id TEXT NOT NULL DEFAULT '',
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  status TEXT NOT NULL CHECK (status IN ('sent', 'failed')),
+  attempts INTEGER NOT NULL DEFAULT 0,
+  chunks_total INTEGER NOT NULL DEFAULT 0,
+  chunks_sent INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_deliveries_bot ON channel_outbound_deliveries(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_deliveries_failed ON channel_outbound_deliveries(bot_id, updated_at DESC) WHERE status = 'failed';
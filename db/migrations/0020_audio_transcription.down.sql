@@ -0,0 +1,8 @@
+-- 0020_audio_transcription (down)
+-- Remove transcription models and the transcript cache.
+
+DROP TABLE IF EXISTS media_transcriptions;
+ALTER TABLE bots DROP COLUMN IF EXISTS transcription_model_id;
+DELETE FROM models WHERE type = 'transcription';
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_type_check;
+ALTER TABLE models ADD CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding'));
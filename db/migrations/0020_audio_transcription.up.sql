@@ -0,0 +1,16 @@
+-- 0020_audio_transcription
+-- Add transcription models, a per-bot transcription model and a transcript cache keyed by content hash.
+
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_type_check;
+ALTER TABLE models ADD CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding', 'transcription'));
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS transcription_model_id UUID REFERENCES models(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS media_transcriptions (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  content_hash TEXT NOT NULL,
+  model_id UUID NOT NULL REFERENCES models(id) ON DELETE CASCADE,
+  text TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, content_hash, model_id)
+);
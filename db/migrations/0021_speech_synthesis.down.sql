@@ -0,0 +1,9 @@
+-- 0021_speech_synthesis (down)
+-- Remove speech models and voice reply settings.
+
+ALTER TABLE bots DROP COLUMN IF EXISTS voice_reply_enabled;
+ALTER TABLE bots DROP COLUMN IF EXISTS speech_voice;
+ALTER TABLE bots DROP COLUMN IF EXISTS speech_model_id;
+DELETE FROM models WHERE type = 'speech';
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_type_check;
+ALTER TABLE models ADD CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding', 'transcription'));
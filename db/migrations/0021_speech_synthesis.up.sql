@@ -0,0 +1,9 @@
+-- 0021_speech_synthesis
+-- Add speech (text-to-speech) models and per-bot voice reply settings.
+
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_type_check;
+ALTER TABLE models ADD CONSTRAINT models_type_check CHECK (type IN ('chat', 'embedding', 'transcription', 'speech'));
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS speech_model_id UUID REFERENCES models(id) ON DELETE SET NULL;
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS speech_voice TEXT NOT NULL DEFAULT 'alloy';
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS voice_reply_enabled BOOLEAN NOT NULL DEFAULT false;
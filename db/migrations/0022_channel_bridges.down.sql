@@ -0,0 +1,6 @@
+-- 0022_channel_bridges (down)
+-- Remove channel bridges.
+
+DROP TABLE IF EXISTS channel_bridge_messages;
+DROP TABLE IF EXISTS channel_bridge_routes;
+DROP TABLE IF EXISTS channel_bridges;
@@ -0,0 +1,39 @@
+-- 0022_channel_bridges
+-- Link channel routes into bridges that relay user messages between them, and map relayed
+-- copies to their originals for replies and loop prevention.
+
+CREATE TABLE IF NOT EXISTS channel_bridges (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  name TEXT NOT NULL DEFAULT '',
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_bridges_bot ON channel_bridges(bot_id);
+
+CREATE TABLE IF NOT EXISTS channel_bridge_routes (
+  bridge_id UUID NOT NULL REFERENCES channel_bridges(id) ON DELETE CASCADE,
+  route_id UUID NOT NULL REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  relay_outgoing BOOLEAN NOT NULL DEFAULT true,
+  relay_incoming BOOLEAN NOT NULL DEFAULT true,
+  outgoing_filter JSONB NOT NULL DEFAULT '{}'::jsonb,
+  incoming_filter JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bridge_id, route_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_bridge_routes_route ON channel_bridge_routes(route_id);
+
+CREATE TABLE IF NOT EXISTS channel_bridge_messages (
+  bridge_id UUID NOT NULL REFERENCES channel_bridges(id) ON DELETE CASCADE,
+  source_route_id UUID NOT NULL REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  source_message_id TEXT NOT NULL,
+  target_route_id UUID NOT NULL REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  target_message_id TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (target_route_id, target_message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_bridge_messages_source ON channel_bridge_messages(source_route_id, source_message_id);
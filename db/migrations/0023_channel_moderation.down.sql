@@ -0,0 +1,5 @@
+-- 0023_channel_moderation (down)
+-- Remove channel moderation.
+
+DROP TABLE IF EXISTS channel_moderation_events;
+DROP TABLE IF EXISTS channel_moderation_policies;
@@ -0,0 +1,29 @@
+-- 0023_channel_moderation
+-- Per-bot moderation policy for inbound channel messages, and a log of the messages it
+-- acted on. A mute is an event with muted_until in the future.
+
+CREATE TABLE IF NOT EXISTS channel_moderation_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS channel_moderation_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  conversation_id TEXT NOT NULL DEFAULT '',
+  sender_subject_id TEXT NOT NULL,
+  sender_name TEXT NOT NULL DEFAULT '',
+  message_id TEXT NOT NULL DEFAULT '',
+  rule TEXT NOT NULL,
+  action TEXT NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  content TEXT NOT NULL DEFAULT '',
+  muted_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_moderation_events_bot_created ON channel_moderation_events(bot_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_channel_moderation_events_mutes ON channel_moderation_events(bot_id, channel_type, sender_subject_id, muted_until)
+  WHERE muted_until IS NOT NULL;
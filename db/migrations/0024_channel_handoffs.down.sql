@@ -0,0 +1,4 @@
+-- 0024_channel_handoffs (down)
+-- Remove channel handoffs.
+
+DROP TABLE IF EXISTS channel_handoffs;
@@ -0,0 +1,16 @@
+-- 0024_channel_handoffs
+-- Human takeover of a channel route: while a handoff is active the bot stays silent on
+-- the route and its owner replies as the bot. A handoff without expires_at lasts until
+-- it is released.
+
+CREATE TABLE IF NOT EXISTS channel_handoffs (
+  route_id UUID PRIMARY KEY REFERENCES bot_channel_routes(id) ON DELETE CASCADE,
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  started_by TEXT NOT NULL DEFAULT '',
+  reason TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ,
+  last_reply_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_handoffs_bot ON channel_handoffs(bot_id);
@@ -0,0 +1,5 @@
+-- 0025_channel_outbox (down)
+-- Remove scheduled outbound messages and the bot timezone.
+
+DROP TABLE IF EXISTS channel_outbox_messages;
+ALTER TABLE bots DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,29 @@
+-- 0025_channel_outbox
+-- Scheduled outbound messages. Each row is a concrete message to a channel target that
+-- the outbox dispatcher delivers once send_at has passed. Bots gain a timezone used to
+-- interpret local send times.
+
+ALTER TABLE bots ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+CREATE TABLE IF NOT EXISTS channel_outbox_messages (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  source TEXT NOT NULL DEFAULT 'api',
+  created_by TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'canceled')),
+  send_at TIMESTAMPTZ NOT NULL,
+  available_at TIMESTAMPTZ NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  locked_until TIMESTAMPTZ,
+  platform_message_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbox_messages_due ON channel_outbox_messages(available_at) WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS idx_channel_outbox_messages_bot ON channel_outbox_messages(bot_id, send_at DESC);
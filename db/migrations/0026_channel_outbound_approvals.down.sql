@@ -0,0 +1,5 @@
+-- 0026_channel_outbound_approvals (down)
+-- Remove the outbound policy and held messages.
+
+DROP TABLE IF EXISTS channel_outbound_approvals;
+DROP TABLE IF EXISTS channel_outbound_policies;
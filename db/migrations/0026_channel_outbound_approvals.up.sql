@@ -0,0 +1,27 @@
+-- 0026_channel_outbound_approvals
+-- Per-bot outbound policy for messages the bot sends on its own to other conversations,
+-- and the messages held until the owner approves or denies them.
+
+CREATE TABLE IF NOT EXISTS channel_outbound_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS channel_outbound_approvals (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  channel_type TEXT NOT NULL,
+  target TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  send_at TIMESTAMPTZ,
+  requested_by TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+  decided_by TEXT NOT NULL DEFAULT '',
+  decided_at TIMESTAMPTZ,
+  outbox_message_id UUID,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_channel_outbound_approvals_bot_created ON channel_outbound_approvals(bot_id, created_at DESC);
@@ -0,0 +1,5 @@
+-- 0027_channel_rate_limits (down)
+-- Remove the rate limit policies and usage counters.
+
+DROP TABLE IF EXISTS channel_rate_usage;
+DROP TABLE IF EXISTS channel_rate_limit_policies;
@@ -0,0 +1,4 @@
+-- 0028_bot_container_resources (down)
+-- Remove the per-bot container resource overrides.
+
+DROP TABLE IF EXISTS bot_container_resources;
@@ -0,0 +1,8 @@
+-- 0028_bot_container_resources
+-- Per-bot overrides of the instance default container resource limits.
+
+CREATE TABLE IF NOT EXISTS bot_container_resources (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  resources JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
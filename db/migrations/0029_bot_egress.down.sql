@@ -0,0 +1,5 @@
+-- 0029_bot_egress (down)
+-- Remove the egress policies and denied connection log.
+
+DROP TABLE IF EXISTS bot_egress_denials;
+DROP TABLE IF EXISTS bot_egress_policies;
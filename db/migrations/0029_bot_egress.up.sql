@@ -0,0 +1,22 @@
+-- 0029_bot_egress
+-- Per-bot network egress policies of bot containers and the outbound connections they
+-- denied.
+
+CREATE TABLE IF NOT EXISTS bot_egress_policies (
+  bot_id UUID PRIMARY KEY REFERENCES bots(id) ON DELETE CASCADE,
+  policy JSONB NOT NULL DEFAULT '{}'::jsonb,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS bot_egress_denials (
+  bot_id UUID NOT NULL REFERENCES bots(id) ON DELETE CASCADE,
+  destination TEXT NOT NULL,
+  port INTEGER NOT NULL DEFAULT 0,
+  protocol TEXT NOT NULL DEFAULT '',
+  hits INTEGER NOT NULL DEFAULT 1,
+  first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (bot_id, destination, port, protocol)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_egress_denials_bot_last_seen ON bot_egress_denials(bot_id, last_seen_at DESC);
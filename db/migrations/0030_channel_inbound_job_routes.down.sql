@@ -0,0 +1,4 @@
+-- 0030_channel_inbound_job_routes (rollback)
+-- Remove route_key column from channel_inbound_jobs.
+
+ALTER TABLE channel_inbound_jobs DROP COLUMN IF EXISTS route_key;
@@ -0,0 +1,5 @@
+-- 0030_channel_inbound_job_routes
+-- Record the conversation of each inbound job so workers can leave jobs of busy
+-- conversations unclaimed.
+
+ALTER TABLE channel_inbound_jobs ADD COLUMN IF NOT EXISTS route_key TEXT NOT NULL DEFAULT '';
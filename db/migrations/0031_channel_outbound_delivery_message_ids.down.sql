@@ -0,0 +1,4 @@
+-- 0031_channel_outbound_delivery_message_ids (rollback)
+-- Remove platform_message_ids column from channel_outbound_deliveries.
+
+ALTER TABLE channel_outbound_deliveries DROP COLUMN IF EXISTS platform_message_ids;
@@ -0,0 +1,5 @@
+-- 0031_channel_outbound_delivery_message_ids
+-- Record the platform message IDs of each outbound delivery so tools can only edit
+-- or delete messages the bot sent.
+
+ALTER TABLE channel_outbound_deliveries ADD COLUMN IF NOT EXISTS platform_message_ids JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,90 @@
+-- name: CreateChannelBridge :one
+INSERT INTO channel_bridges (bot_id, name, enabled)
+VALUES (sqlc.arg(bot_id), sqlc.arg(name), sqlc.arg(enabled))
+RETURNING *;
+
+-- name: GetChannelBridgeByID :one
+SELECT * FROM channel_bridges
+WHERE id = $1;
+
+-- name: ListChannelBridgesByBot :many
+SELECT * FROM channel_bridges
+WHERE bot_id = $1
+ORDER BY created_at ASC;
+
+-- name: UpdateChannelBridge :one
+UPDATE channel_bridges
+SET name = sqlc.arg(name),
+    enabled = sqlc.arg(enabled),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteChannelBridge :exec
+DELETE FROM channel_bridges
+WHERE id = $1;
+
+-- name: AddChannelBridgeRoute :exec
+INSERT INTO channel_bridge_routes (bridge_id, route_id, relay_outgoing, relay_incoming, outgoing_filter, incoming_filter)
+VALUES (
+  sqlc.arg(bridge_id),
+  sqlc.arg(route_id),
+  sqlc.arg(relay_outgoing),
+  sqlc.arg(relay_incoming),
+  sqlc.arg(outgoing_filter),
+  sqlc.arg(incoming_filter)
+);
+
+-- name: DeleteChannelBridgeRoutes :exec
+DELETE FROM channel_bridge_routes
+WHERE bridge_id = $1;
+
+-- name: ListChannelBridgeRoutes :many
+SELECT
+  br.bridge_id,
+  br.route_id,
+  br.relay_outgoing,
+  br.relay_incoming,
+  br.outgoing_filter,
+  br.incoming_filter,
+  r.channel_type AS platform,
+  r.external_conversation_id AS conversation_id,
+  r.external_thread_id AS thread_id,
+  r.default_reply_target AS reply_target
+FROM channel_bridge_routes br
+JOIN bot_channel_routes r ON r.id = br.route_id
+WHERE br.bridge_id = $1
+ORDER BY br.created_at ASC;
+
+-- name: ListEnabledChannelBridgeIDsByRoute :many
+SELECT br.bridge_id
+FROM channel_bridge_routes br
+JOIN channel_bridges b ON b.id = br.bridge_id
+WHERE br.route_id = $1
+  AND b.enabled = TRUE
+  AND br.relay_outgoing = TRUE
+ORDER BY b.created_at ASC;
+
+-- name: CreateChannelBridgeMessage :exec
+INSERT INTO channel_bridge_messages (bridge_id, source_route_id, source_message_id, target_route_id, target_message_id)
+VALUES (
+  sqlc.arg(bridge_id),
+  sqlc.arg(source_route_id),
+  sqlc.arg(source_message_id),
+  sqlc.arg(target_route_id),
+  sqlc.arg(target_message_id)
+)
+ON CONFLICT (target_route_id, target_message_id) DO NOTHING;
+
+-- name: GetChannelBridgeMessageByTarget :one
+SELECT * FROM channel_bridge_messages
+WHERE target_route_id = sqlc.arg(target_route_id)
+  AND target_message_id = sqlc.arg(target_message_id);
+
+-- name: GetChannelBridgeMessageCopy :one
+SELECT target_message_id FROM channel_bridge_messages
+WHERE source_route_id = sqlc.arg(source_route_id)
+  AND source_message_id = sqlc.arg(source_message_id)
+  AND target_route_id = sqlc.arg(target_route_id)
+ORDER BY created_at ASC
+LIMIT 1;
@@ -0,0 +1,37 @@
+-- name: UpsertChannelHandoff :one
+INSERT INTO channel_handoffs (route_id, bot_id, started_by, reason, expires_at)
+VALUES (
+  sqlc.arg(route_id),
+  sqlc.arg(bot_id),
+  sqlc.arg(started_by),
+  sqlc.arg(reason),
+  sqlc.narg(expires_at)
+)
+ON CONFLICT (route_id) DO UPDATE
+SET started_by = EXCLUDED.started_by,
+    reason = EXCLUDED.reason,
+    expires_at = EXCLUDED.expires_at,
+    last_reply_at = NULL,
+    created_at = now()
+RETURNING *;
+
+-- name: GetActiveChannelHandoff :one
+SELECT * FROM channel_handoffs
+WHERE route_id = $1
+  AND (expires_at IS NULL OR expires_at > now());
+
+-- name: ListActiveChannelHandoffsByBot :many
+SELECT * FROM channel_handoffs
+WHERE bot_id = $1
+  AND (expires_at IS NULL OR expires_at > now())
+ORDER BY created_at DESC;
+
+-- name: SetChannelHandoffLastReply :exec
+UPDATE channel_handoffs
+SET last_reply_at = sqlc.arg(last_reply_at)
+WHERE route_id = sqlc.arg(route_id);
+
+-- name: DeleteActiveChannelHandoff :execrows
+DELETE FROM channel_handoffs
+WHERE route_id = $1
+  AND (expires_at IS NULL OR expires_at > now());
@@ -0,0 +1,78 @@
+-- name: EnqueueChannelInboundJob :one
+INSERT INTO channel_inbound_jobs (bot_id, channel_config_id, channel_type, external_message_id, payload, route_key)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_config_id), sqlc.arg(channel_type), sqlc.arg(external_message_id), sqlc.arg(payload), sqlc.arg(route_key))
+ON CONFLICT (bot_id, channel_type, external_message_id) WHERE external_message_id <> '' DO NOTHING
+RETURNING *;
+
+-- name: ClaimChannelInboundJob :one
+UPDATE channel_inbound_jobs
+SET status = 'processing',
+    attempts = attempts + 1,
+    locked_until = sqlc.arg(locked_until),
+    updated_at = now()
+WHERE id = (
+  SELECT j.id FROM channel_inbound_jobs j
+  WHERE ((j.status = 'pending' AND j.available_at <= now())
+     OR (j.status = 'processing' AND j.locked_until < now()))
+    AND NOT (j.route_key = ANY(sqlc.arg(busy_routes)::text[]))
+  ORDER BY j.available_at ASC, j.created_at ASC
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RenewChannelInboundJobLeases :exec
+UPDATE channel_inbound_jobs
+SET locked_until = sqlc.arg(locked_until),
+    updated_at = now()
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+  AND status = 'processing';
+
+-- name: CompleteChannelInboundJob :exec
+UPDATE channel_inbound_jobs
+SET status = 'done',
+    last_error = '',
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: RetryChannelInboundJob :exec
+UPDATE channel_inbound_jobs
+SET status = 'pending',
+    available_at = sqlc.arg(available_at),
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: DeadLetterChannelInboundJob :exec
+UPDATE channel_inbound_jobs
+SET status = 'dead',
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: ListDeadChannelInboundJobs :many
+SELECT * FROM channel_inbound_jobs
+WHERE bot_id = sqlc.arg(bot_id)
+  AND status = 'dead'
+ORDER BY updated_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- name: ReplayChannelInboundJob :execrows
+UPDATE channel_inbound_jobs
+SET status = 'pending',
+    attempts = 0,
+    available_at = now(),
+    last_error = '',
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id)
+  AND status = 'dead';
+
+-- name: DeleteCompletedChannelInboundJobs :exec
+DELETE FROM channel_inbound_jobs
+WHERE status = 'done'
+  AND updated_at < sqlc.arg(before);
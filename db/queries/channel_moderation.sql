@@ -0,0 +1,63 @@
+-- name: GetChannelModerationPolicy :one
+SELECT * FROM channel_moderation_policies
+WHERE bot_id = $1;
+
+-- name: UpsertChannelModerationPolicy :one
+INSERT INTO channel_moderation_policies (bot_id, policy)
+VALUES (sqlc.arg(bot_id), sqlc.arg(policy))
+ON CONFLICT (bot_id) DO UPDATE
+SET policy = EXCLUDED.policy,
+    updated_at = now()
+RETURNING *;
+
+-- name: CreateChannelModerationEvent :one
+INSERT INTO channel_moderation_events (
+  bot_id,
+  channel_type,
+  conversation_id,
+  sender_subject_id,
+  sender_name,
+  message_id,
+  rule,
+  action,
+  reason,
+  content,
+  muted_until
+)
+VALUES (
+  sqlc.arg(bot_id),
+  sqlc.arg(channel_type),
+  sqlc.arg(conversation_id),
+  sqlc.arg(sender_subject_id),
+  sqlc.arg(sender_name),
+  sqlc.arg(message_id),
+  sqlc.arg(rule),
+  sqlc.arg(action),
+  sqlc.arg(reason),
+  sqlc.arg(content),
+  sqlc.narg(muted_until)
+)
+RETURNING *;
+
+-- name: ListChannelModerationEvents :many
+SELECT * FROM channel_moderation_events
+WHERE bot_id = sqlc.arg(bot_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- name: GetActiveChannelModerationMute :one
+SELECT muted_until FROM channel_moderation_events
+WHERE bot_id = sqlc.arg(bot_id)
+  AND channel_type = sqlc.arg(channel_type)
+  AND sender_subject_id = sqlc.arg(sender_subject_id)
+  AND muted_until > now()
+ORDER BY muted_until DESC
+LIMIT 1;
+
+-- name: LiftChannelModerationMutes :execrows
+UPDATE channel_moderation_events
+SET muted_until = now()
+WHERE bot_id = sqlc.arg(bot_id)
+  AND channel_type = sqlc.arg(channel_type)
+  AND sender_subject_id = sqlc.arg(sender_subject_id)
+  AND muted_until > now();
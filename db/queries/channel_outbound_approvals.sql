@@ -0,0 +1,43 @@
+-- name: GetChannelOutboundPolicy :one
+SELECT * FROM channel_outbound_policies
+WHERE bot_id = $1;
+
+-- name: UpsertChannelOutboundPolicy :one
+INSERT INTO channel_outbound_policies (bot_id, policy)
+VALUES (sqlc.arg(bot_id), sqlc.arg(policy))
+ON CONFLICT (bot_id) DO UPDATE
+SET policy = EXCLUDED.policy,
+    updated_at = now()
+RETURNING *;
+
+-- name: CreateChannelOutboundApproval :one
+INSERT INTO channel_outbound_approvals (bot_id, channel_type, target, payload, send_at, requested_by, expires_at)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_type), sqlc.arg(target), sqlc.arg(payload), sqlc.narg(send_at), sqlc.arg(requested_by), sqlc.arg(expires_at))
+RETURNING *;
+
+-- name: GetChannelOutboundApproval :one
+SELECT * FROM channel_outbound_approvals
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id);
+
+-- name: ListChannelOutboundApprovals :many
+SELECT * FROM channel_outbound_approvals
+WHERE bot_id = sqlc.arg(bot_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- name: DecideChannelOutboundApproval :one
+UPDATE channel_outbound_approvals
+SET status = sqlc.arg(status),
+    decided_by = sqlc.arg(decided_by),
+    decided_at = now()
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id)
+  AND status = 'pending'
+  AND expires_at > now()
+RETURNING *;
+
+-- name: SetChannelOutboundApprovalOutboxMessage :exec
+UPDATE channel_outbound_approvals
+SET outbox_message_id = sqlc.arg(outbox_message_id)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,31 @@
+-- name: InsertChannelOutboundDelivery :one
+INSERT INTO channel_outbound_deliveries (bot_id, channel_config_id, channel_type, target, payload, status, attempts, chunks_total, chunks_sent, last_error, platform_message_ids)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_config_id), sqlc.arg(channel_type), sqlc.arg(target), sqlc.arg(payload), sqlc.arg(status), sqlc.arg(attempts), sqlc.arg(chunks_total), sqlc.arg(chunks_sent), sqlc.arg(last_error), sqlc.arg(platform_message_ids))
+RETURNING *;
+
+-- name: UpdateChannelOutboundDelivery :exec
+UPDATE channel_outbound_deliveries
+SET status = sqlc.arg(status),
+    attempts = sqlc.arg(attempts),
+    chunks_sent = sqlc.arg(chunks_sent),
+    last_error = sqlc.arg(last_error),
+    platform_message_ids = sqlc.arg(platform_message_ids),
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: GetChannelOutboundDelivery :one
+SELECT * FROM channel_outbound_deliveries
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id);
+
+-- name: ListChannelOutboundDeliveries :many
+SELECT * FROM channel_outbound_deliveries
+WHERE bot_id = sqlc.arg(bot_id)
+  AND (sqlc.arg(status)::text = '' OR status = sqlc.arg(status)::text)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- name: DeleteSentChannelOutboundDeliveries :exec
+DELETE FROM channel_outbound_deliveries
+WHERE status = 'sent'
+  AND updated_at < sqlc.arg(before);
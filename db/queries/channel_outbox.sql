@@ -0,0 +1,68 @@
+-- name: CreateChannelOutboxMessage :one
+INSERT INTO channel_outbox_messages (bot_id, channel_type, target, payload, source, created_by, send_at, available_at)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_type), sqlc.arg(target), sqlc.arg(payload), sqlc.arg(source), sqlc.arg(created_by), sqlc.arg(send_at), sqlc.arg(send_at))
+RETURNING *;
+
+-- name: GetChannelOutboxMessage :one
+SELECT * FROM channel_outbox_messages
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id);
+
+-- name: ListChannelOutboxMessages :many
+SELECT * FROM channel_outbox_messages
+WHERE bot_id = sqlc.arg(bot_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY send_at ASC, created_at ASC
+LIMIT sqlc.arg(max_count);
+
+-- name: CancelChannelOutboxMessage :execrows
+UPDATE channel_outbox_messages
+SET status = 'canceled',
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND bot_id = sqlc.arg(bot_id)
+  AND status = 'pending';
+
+-- name: ClaimChannelOutboxMessage :one
+UPDATE channel_outbox_messages
+SET status = 'sending',
+    attempts = attempts + 1,
+    locked_until = sqlc.arg(locked_until),
+    updated_at = now()
+WHERE id = (
+  SELECT m.id FROM channel_outbox_messages m
+  WHERE (m.status = 'pending' AND m.available_at <= now())
+     OR (m.status = 'sending' AND m.locked_until < now())
+  ORDER BY m.available_at ASC, m.created_at ASC
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteChannelOutboxMessage :exec
+UPDATE channel_outbox_messages
+SET status = 'sent',
+    platform_message_ids = sqlc.arg(platform_message_ids),
+    last_error = '',
+    locked_until = NULL,
+    sent_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: RetryChannelOutboxMessage :exec
+UPDATE channel_outbox_messages
+SET status = 'pending',
+    available_at = sqlc.arg(available_at),
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
+
+-- name: FailChannelOutboxMessage :exec
+UPDATE channel_outbox_messages
+SET status = 'failed',
+    last_error = sqlc.arg(last_error),
+    locked_until = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id);
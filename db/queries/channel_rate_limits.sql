@@ -0,0 +1,48 @@
+-- name: GetChannelRateLimitPolicy :one
+SELECT * FROM channel_rate_limit_policies
+WHERE bot_id = $1;
+
+-- name: UpsertChannelRateLimitPolicy :one
+INSERT INTO channel_rate_limit_policies (bot_id, policy)
+VALUES (sqlc.arg(bot_id), sqlc.arg(policy))
+ON CONFLICT (bot_id) DO UPDATE
+SET policy = EXCLUDED.policy,
+    updated_at = now()
+RETURNING *;
+
+-- name: RecordChannelRateMessage :exec
+INSERT INTO channel_rate_usage (bot_id, channel_identity_id, day, channel_type, display_name, role, messages)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_identity_id), sqlc.arg(day), sqlc.arg(channel_type), sqlc.arg(display_name), sqlc.arg(role), 1)
+ON CONFLICT (bot_id, day, channel_identity_id) DO UPDATE
+SET messages = channel_rate_usage.messages + 1,
+    channel_type = EXCLUDED.channel_type,
+    display_name = EXCLUDED.display_name,
+    role = EXCLUDED.role,
+    last_seen_at = now();
+
+-- name: RecordChannelRateTurn :one
+-- Counts a turn unless the sender already used max_turns today (0 means no limit).
+-- Returns no row when the turn is over the limit.
+INSERT INTO channel_rate_usage (bot_id, channel_identity_id, day, channel_type, display_name, role, turns)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_identity_id), sqlc.arg(day), sqlc.arg(channel_type), sqlc.arg(display_name), sqlc.arg(role), 1)
+ON CONFLICT (bot_id, day, channel_identity_id) DO UPDATE
+SET turns = channel_rate_usage.turns + 1,
+    last_seen_at = now()
+WHERE sqlc.arg(max_turns)::int <= 0
+   OR channel_rate_usage.turns < sqlc.arg(max_turns)::int
+RETURNING *;
+
+-- name: RecordChannelRateLimited :one
+INSERT INTO channel_rate_usage (bot_id, channel_identity_id, day, channel_type, display_name, role, limited)
+VALUES (sqlc.arg(bot_id), sqlc.arg(channel_identity_id), sqlc.arg(day), sqlc.arg(channel_type), sqlc.arg(display_name), sqlc.arg(role), 1)
+ON CONFLICT (bot_id, day, channel_identity_id) DO UPDATE
+SET limited = channel_rate_usage.limited + 1,
+    last_seen_at = now()
+RETURNING limited;
+
+-- name: ListChannelRateUsage :many
+SELECT * FROM channel_rate_usage
+WHERE bot_id = sqlc.arg(bot_id)
+  AND day = sqlc.arg(day)
+ORDER BY turns DESC, messages DESC, last_seen_at DESC
+LIMIT sqlc.arg(max_count);
@@ -52,3 +52,14 @@ WHERE bot_id = sqlc.arg(bot_id);
 
 -- name: ListAutoStartContainers :many
 SELECT * FROM containers WHERE auto_start = true ORDER BY updated_at DESC;
+
+-- name: GetBotContainerResources :one
+SELECT * FROM bot_container_resources WHERE bot_id = sqlc.arg(bot_id);
+
+-- name: UpsertBotContainerResources :one
+INSERT INTO bot_container_resources (bot_id, resources)
+VALUES (sqlc.arg(bot_id), sqlc.arg(resources))
+ON CONFLICT (bot_id) DO UPDATE
+SET resources = EXCLUDED.resources,
+    updated_at = now()
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: GetBotEgressPolicy :one
+SELECT * FROM bot_egress_policies WHERE bot_id = sqlc.arg(bot_id);
+
+-- name: ListBotEgressPolicies :many
+SELECT * FROM bot_egress_policies ORDER BY updated_at DESC;
+
+-- name: UpsertBotEgressPolicy :one
+INSERT INTO bot_egress_policies (bot_id, policy)
+VALUES (sqlc.arg(bot_id), sqlc.arg(policy))
+ON CONFLICT (bot_id) DO UPDATE
+SET policy = EXCLUDED.policy,
+    updated_at = now()
+RETURNING *;
+
+-- name: RecordBotEgressDenial :exec
+INSERT INTO bot_egress_denials (bot_id, destination, port, protocol, first_seen_at, last_seen_at)
+VALUES (sqlc.arg(bot_id), sqlc.arg(destination), sqlc.arg(port), sqlc.arg(protocol), sqlc.arg(seen_at), sqlc.arg(seen_at))
+ON CONFLICT (bot_id, destination, port, protocol) DO UPDATE
+SET hits = bot_egress_denials.hits + 1,
+    last_seen_at = EXCLUDED.last_seen_at;
+
+-- name: ListBotEgressDenials :many
+SELECT * FROM bot_egress_denials
+WHERE bot_id = sqlc.arg(bot_id)
+ORDER BY last_seen_at DESC
+LIMIT sqlc.arg(max_count);
@@ -1,3 +1,11 @@
+-- name: CountRecentLifecycleEvents :one
+-- Counts the events of one type recorded for a container since a point in time.
+SELECT count(*)::int AS event_count, max(created_at)::timestamptz AS last_at
+FROM lifecycle_events
+WHERE container_id = sqlc.arg(container_id)
+  AND event_type = sqlc.arg(event_type)
+  AND created_at >= sqlc.arg(since);
+
 -- name: InsertLifecycleEvent :exec
 INSERT INTO lifecycle_events (id, container_id, event_type, payload)
 VALUES (
@@ -108,6 +108,7 @@ LEFT JOIN channel_identities ci ON ci.id = m.sender_channel_identity_id
 WHERE m.bot_id = sqlc.arg(bot_id)
   AND m.created_at >= sqlc.arg(created_at)
   AND (m.metadata->>'trigger_mode' IS NULL OR m.metadata->>'trigger_mode' != 'passive_sync')
+  AND m.metadata->>'deleted_at' IS NULL
 ORDER BY m.created_at ASC;
 
 -- name: ListMessagesBefore :many
@@ -160,3 +161,96 @@ LIMIT sqlc.arg(max_count);
 -- name: DeleteMessagesByBot :exec
 DELETE FROM bot_history_messages
 WHERE bot_id = sqlc.arg(bot_id);
+
+-- name: LinkLatestAssistantMessageExternalIDs :execrows
+UPDATE bot_history_messages
+SET source_message_id = sqlc.arg(external_message_id)::text,
+    metadata = metadata || jsonb_build_object('platform_message_ids', sqlc.arg(platform_message_ids)::jsonb)
+WHERE id = (
+  SELECT m.id
+  FROM bot_history_messages m
+  WHERE m.bot_id = sqlc.arg(bot_id)
+    AND m.route_id = sqlc.arg(route_id)
+    AND m.role = 'assistant'
+    AND m.source_message_id IS NULL
+    AND m.created_at >= sqlc.arg(since)
+  ORDER BY m.created_at DESC
+  LIMIT 1
+);
+
+-- name: GetLatestAssistantExternalMessageID :one
+SELECT m.source_message_id AS external_message_id
+FROM bot_history_messages m
+JOIN bot_channel_routes r ON r.id = m.route_id
+WHERE m.bot_id = sqlc.arg(bot_id)
+  AND m.role = 'assistant'
+  AND m.channel_type = sqlc.arg(platform)::text
+  AND r.default_reply_target = sqlc.arg(reply_target)::text
+  AND m.source_message_id IS NOT NULL
+ORDER BY m.created_at DESC
+LIMIT 1;
+
+-- name: IsBotOutboundMessage :one
+SELECT (
+  EXISTS (
+    SELECT 1
+    FROM bot_history_messages m
+    JOIN bot_channel_routes r ON r.id = m.route_id
+    WHERE m.bot_id = sqlc.arg(bot_id)
+      AND m.role = 'assistant'
+      AND m.channel_type = sqlc.arg(platform)::text
+      AND r.default_reply_target = sqlc.arg(target)::text
+      AND (
+        m.source_message_id = sqlc.arg(external_message_id)::text
+        OR m.metadata->'platform_message_ids' @> jsonb_build_array(sqlc.arg(external_message_id)::text)
+      )
+  )
+  OR EXISTS (
+    SELECT 1
+    FROM channel_outbound_deliveries d
+    WHERE d.bot_id = sqlc.arg(bot_id)
+      AND d.channel_type = sqlc.arg(platform)::text
+      AND d.target = sqlc.arg(target)::text
+      AND d.platform_message_ids @> jsonb_build_array(sqlc.arg(external_message_id)::text)
+  )
+)::boolean AS is_outbound;
+
+-- name: EditMessageBySource :execrows
+UPDATE bot_history_messages
+SET content = sqlc.arg(content),
+    metadata = metadata || jsonb_build_object(
+      'edited_at', sqlc.arg(edited_at)::timestamptz,
+      'original_content', COALESCE(metadata->'original_content', content)
+    )
+WHERE bot_id = sqlc.arg(bot_id)
+  AND channel_type = sqlc.arg(platform)::text
+  AND source_message_id = sqlc.arg(external_message_id)::text
+  AND role = 'user';
+
+-- name: SoftDeleteMessagesBySource :execrows
+UPDATE bot_history_messages
+SET metadata = metadata || jsonb_build_object('deleted_at', sqlc.arg(deleted_at)::timestamptz)
+WHERE bot_id = sqlc.arg(bot_id)
+  AND channel_type = sqlc.arg(platform)::text
+  AND (
+    source_message_id = sqlc.arg(external_message_id)::text
+    OR metadata->'platform_message_ids' @> jsonb_build_array(sqlc.arg(external_message_id)::text)
+  )
+  AND metadata->>'deleted_at' IS NULL;
+
+-- name: GetMessageMetadataBySource :one
+SELECT m.id, m.metadata
+FROM bot_history_messages m
+WHERE m.bot_id = sqlc.arg(bot_id)
+  AND m.channel_type = sqlc.arg(platform)::text
+  AND (
+    m.source_message_id = sqlc.arg(external_message_id)::text
+    OR m.metadata->'platform_message_ids' @> jsonb_build_array(sqlc.arg(external_message_id)::text)
+  )
+ORDER BY m.created_at DESC
+LIMIT 1;
+
+-- name: UpdateMessageMetadata :exec
+UPDATE bot_history_messages
+SET metadata = sqlc.arg(metadata)
+WHERE id = sqlc.arg(id);
@@ -15,12 +15,19 @@ SELECT
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
   heartbeat_models.id AS heartbeat_model_id,
+  transcription_models.id AS transcription_model_id,
+  speech_models.id AS speech_model_id,
+  bots.speech_voice,
+  bots.voice_reply_enabled,
+  bots.timezone,
   search_providers.id AS search_provider_id
 FROM bots
 LEFT JOIN models AS chat_models ON chat_models.id = bots.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = bots.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = bots.embedding_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = bots.heartbeat_model_id
+LEFT JOIN models AS transcription_models ON transcription_models.id = bots.transcription_model_id
+LEFT JOIN models AS speech_models ON speech_models.id = bots.speech_model_id
 LEFT JOIN search_providers ON search_providers.id = bots.search_provider_id
 WHERE bots.id = $1;
 
@@ -41,10 +48,15 @@ WITH updated AS (
       memory_model_id = COALESCE(sqlc.narg(memory_model_id)::uuid, bots.memory_model_id),
       embedding_model_id = COALESCE(sqlc.narg(embedding_model_id)::uuid, bots.embedding_model_id),
       heartbeat_model_id = COALESCE(sqlc.narg(heartbeat_model_id)::uuid, bots.heartbeat_model_id),
+      transcription_model_id = COALESCE(sqlc.narg(transcription_model_id)::uuid, bots.transcription_model_id),
+      speech_model_id = COALESCE(sqlc.narg(speech_model_id)::uuid, bots.speech_model_id),
+      speech_voice = COALESCE(sqlc.narg(speech_voice)::text, bots.speech_voice),
+      voice_reply_enabled = COALESCE(sqlc.narg(voice_reply_enabled)::boolean, bots.voice_reply_enabled),
+      timezone = COALESCE(sqlc.narg(timezone)::text, bots.timezone),
       search_provider_id = COALESCE(sqlc.narg(search_provider_id)::uuid, bots.search_provider_id),
       updated_at = now()
   WHERE bots.id = sqlc.arg(id)
-  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.heartbeat_model_id, bots.search_provider_id
+  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.heartbeat_model_id, bots.transcription_model_id, bots.speech_model_id, bots.speech_voice, bots.voice_reply_enabled, bots.timezone, bots.search_provider_id
 )
 SELECT
   updated.id AS bot_id,
@@ -62,12 +74,19 @@ SELECT
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
   heartbeat_models.id AS heartbeat_model_id,
+  transcription_models.id AS transcription_model_id,
+  speech_models.id AS speech_model_id,
+  updated.speech_voice,
+  updated.voice_reply_enabled,
+  updated.timezone,
   search_providers.id AS search_provider_id
 FROM updated
 LEFT JOIN models AS chat_models ON chat_models.id = updated.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = updated.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = updated.embedding_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = updated.heartbeat_model_id
+LEFT JOIN models AS transcription_models ON transcription_models.id = updated.transcription_model_id
+LEFT JOIN models AS speech_models ON speech_models.id = updated.speech_model_id
 LEFT JOIN search_providers ON search_providers.id = updated.search_provider_id;
 
 -- name: DeleteSettingsByBotID :exec
@@ -86,6 +105,11 @@ SET max_context_load_time = 1440,
     memory_model_id = NULL,
     embedding_model_id = NULL,
     heartbeat_model_id = NULL,
+    transcription_model_id = NULL,
+    speech_model_id = NULL,
+    speech_voice = 'alloy',
+    voice_reply_enabled = false,
+    timezone = 'UTC',
     search_provider_id = NULL,
     updated_at = now()
 WHERE id = $1;
@@ -0,0 +1,12 @@
+-- name: GetMediaTranscription :one
+SELECT text FROM media_transcriptions
+WHERE bot_id = sqlc.arg(bot_id)
+  AND content_hash = sqlc.arg(content_hash)
+  AND model_id = sqlc.arg(model_id);
+
+-- name: UpsertMediaTranscription :exec
+INSERT INTO media_transcriptions (bot_id, content_hash, model_id, text)
+VALUES (sqlc.arg(bot_id), sqlc.arg(content_hash), sqlc.arg(model_id), sqlc.arg(text))
+ON CONFLICT (bot_id, content_hash, model_id) DO UPDATE SET
+  text = EXCLUDED.text,
+  created_at = now();
@@ -36,6 +36,18 @@ export const en = [
       {
         text: 'telegram platform',
         link: '/getting-started/platform-telegram.md'
+      },
+      {
+        text: 'webhook platform',
+        link: '/getting-started/platform-webhook.md'
+      },
+      {
+        text: 'wecom platform',
+        link: '/getting-started/platform-wecom.md'
+      },
+      {
+        text: 'dingtalk platform',
+        link: '/getting-started/platform-dingtalk.md'
       }
     ]
   },
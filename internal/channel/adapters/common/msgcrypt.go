@@ -0,0 +1,120 @@
+package common
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const msgCryptBlockSize = 32
+
+var (
+	// ErrCallbackSignature is returned when a callback signature does not match.
+	ErrCallbackSignature = errors.New("callback signature mismatch")
+	// ErrCallbackReceiver is returned when a decrypted payload belongs to another receiver.
+	ErrCallbackReceiver = errors.New("callback receiver mismatch")
+)
+
+// CallbackCrypto implements the AES-CBC callback encryption shared by WeCom and DingTalk:
+// the signature is SHA1 over the sorted token, timestamp, nonce and ciphertext, and the
+// plaintext is random(16) | len(4, big endian) | message | receiverID, PKCS#7 padded to 32 bytes.
+type CallbackCrypto struct {
+	token      string
+	key        []byte
+	receiverID string
+}
+
+// NewCallbackCrypto creates a CallbackCrypto from the 43-character encoding AES key.
+// receiverID is the corp ID (WeCom) or app key / corp ID (DingTalk) appended to each message.
+func NewCallbackCrypto(token, encodingAESKey, receiverID string) (*CallbackCrypto, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodingAESKey) + "=")
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("invalid encoding aes key")
+	}
+	return &CallbackCrypto{token: token, key: key, receiverID: receiverID}, nil
+}
+
+// Signature computes the callback signature for the given values.
+func (c *CallbackCrypto) Signature(timestamp, nonce, encrypted string) string {
+	parts := []string{c.token, timestamp, nonce, encrypted}
+	sort.Strings(parts)
+	sum := sha1.Sum([]byte(strings.Join(parts, "")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks a callback signature in constant time.
+func (c *CallbackCrypto) Verify(signature, timestamp, nonce, encrypted string) error {
+	expected := c.Signature(timestamp, nonce, encrypted)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.TrimSpace(signature))) != 1 {
+		return ErrCallbackSignature
+	}
+	return nil
+}
+
+// Decrypt decodes a base64 ciphertext and returns the message after checking the receiver ID.
+func (c *CallbackCrypto) Decrypt(encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted))
+	if err != nil {
+		return nil, fmt.Errorf("decode callback ciphertext: %w", err)
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid callback ciphertext length")
+	}
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, c.key[:aes.BlockSize]).CryptBlocks(plain, data)
+	pad := int(plain[len(plain)-1])
+	if pad < 1 || pad > msgCryptBlockSize || pad > len(plain) {
+		return nil, fmt.Errorf("invalid callback padding")
+	}
+	plain = plain[:len(plain)-pad]
+	if len(plain) < 20 {
+		return nil, fmt.Errorf("callback plaintext too short")
+	}
+	size := int(binary.BigEndian.Uint32(plain[16:20]))
+	if size < 0 || 20+size > len(plain) {
+		return nil, fmt.Errorf("invalid callback message length")
+	}
+	msg := plain[20 : 20+size]
+	if c.receiverID != "" && string(plain[20+size:]) != c.receiverID {
+		return nil, ErrCallbackReceiver
+	}
+	return msg, nil
+}
+
+// Encrypt returns the base64 ciphertext of msg, used for callback responses.
+func (c *CallbackCrypto) Encrypt(msg []byte) (string, error) {
+	var buf bytes.Buffer
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	buf.Write(random)
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(msg)))
+	buf.Write(size[:])
+	buf.Write(msg)
+	buf.WriteString(c.receiverID)
+	pad := msgCryptBlockSize - buf.Len()%msgCryptBlockSize
+	buf.Write(bytes.Repeat([]byte{byte(pad)}, pad))
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, buf.Len())
+	cipher.NewCBCEncrypter(block, c.key[:aes.BlockSize]).CryptBlocks(out, buf.Bytes())
+	return base64.StdEncoding.EncodeToString(out), nil
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+const testEncodingAESKey = "jWmYm7qr5nMoAUwZRjGtBxmz3KA1tkAj3ykkR6q2B2C"
+
+func TestCallbackCryptoRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCallbackCrypto("token", testEncodingAESKey, "corp-1")
+	if err != nil {
+		t.Fatalf("new crypto: %v", err)
+	}
+	encrypted, err := c.Encrypt([]byte("<xml>hello</xml>"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	plain, err := c.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if string(plain) != "<xml>hello</xml>" {
+		t.Fatalf("unexpected plaintext %q", plain)
+	}
+
+	sig := c.Signature("1700000000", "nonce", encrypted)
+	if err := c.Verify(sig, "1700000000", "nonce", encrypted); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := c.Verify(sig, "1700000001", "nonce", encrypted); !errors.Is(err, ErrCallbackSignature) {
+		t.Fatalf("expected signature mismatch, got %v", err)
+	}
+
+	other, _ := NewCallbackCrypto("token", testEncodingAESKey, "corp-2")
+	if _, err := other.Decrypt(encrypted); !errors.Is(err, ErrCallbackReceiver) {
+		t.Fatalf("expected receiver mismatch, got %v", err)
+	}
+}
+
+func TestCallbackCryptoRejectsInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewCallbackCrypto("token", "short", "corp"); err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+}
+
+func TestCallbackCryptoKnownSignature(t *testing.T) {
+	t.Parallel()
+
+	// Signature is SHA1 of the lexicographically sorted concatenation.
+	c, _ := NewCallbackCrypto("QDG6eK", testEncodingAESKey, "")
+	got := c.Signature("1409659813", "1372623149", "msg")
+	want := "3938781088826976a4d25769709448788e991904"
+	if got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,278 @@
+package dingtalk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenRefreshMargin renews cached access tokens slightly before DingTalk expires them.
+	tokenRefreshMargin  = 5 * time.Minute
+	maxAPIResponseBytes = 4 << 20
+	accessTokenHeader   = "x-acs-dingtalk-access-token"
+)
+
+// Legacy (oapi) error codes that mean the cached access token must be refreshed.
+const (
+	errcodeInvalidToken = 40014
+	errcodeExpiredToken = 42001
+)
+
+type cachedToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// apiError is returned when DingTalk rejects a request.
+type apiError struct {
+	Op         string
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("dingtalk %s failed: %s (code: %s)", e.Op, e.Message, e.Code)
+}
+
+func isTokenError(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	switch apiErr.Code {
+	case "InvalidAuthentication", fmt.Sprint(errcodeInvalidToken), fmt.Sprint(errcodeExpiredToken):
+		return true
+	}
+	return false
+}
+
+func tokenCacheKey(cfg Config) string {
+	return cfg.APIBaseURL + "|" + cfg.AppKey + "|" + cfg.AppSecret
+}
+
+// accessToken returns a cached application access token, fetching a new one when missing,
+// expired or forced. The same token authorizes both the v1.0 API and the legacy oapi endpoints.
+func (a *DingTalkAdapter) accessToken(ctx context.Context, cfg Config, force bool) (string, error) {
+	key := tokenCacheKey(cfg)
+	now := time.Now()
+	a.mu.Lock()
+	cached, ok := a.tokens[key]
+	a.mu.Unlock()
+	if ok && !force && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"appKey": cfg.AppKey, "appSecret": cfg.AppSecret})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.APIBaseURL+"/v1.0/oauth2/accessToken", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var body struct {
+		AccessToken string `json:"accessToken"`
+		ExpireIn    int    `json:"expireIn"`
+	}
+	if err := a.doAPI(req, "get token", &body); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(body.AccessToken) == "" {
+		return "", fmt.Errorf("dingtalk get token: empty accessToken")
+	}
+	ttl := time.Duration(body.ExpireIn)*time.Second - tokenRefreshMargin
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	a.mu.Lock()
+	a.tokens[key] = cachedToken{value: body.AccessToken, expiresAt: now.Add(ttl)}
+	a.mu.Unlock()
+	return body.AccessToken, nil
+}
+
+// callAPI sends a JSON request to the v1.0 API and decodes the response into out, retrying
+// once with a fresh token when the cached one is rejected.
+func (a *DingTalkAdapter) callAPI(ctx context.Context, cfg Config, op, method, path string, payload any, out any) error {
+	var raw []byte
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("dingtalk %s: encode request: %w", op, err)
+		}
+		raw = encoded
+	}
+	force := false
+	for attempt := 0; ; attempt++ {
+		token, err := a.accessToken(ctx, cfg, force)
+		if err != nil {
+			return err
+		}
+		var body io.Reader
+		if raw != nil {
+			body = bytes.NewReader(raw)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, cfg.APIBaseURL+path, body)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(accessTokenHeader, token)
+		if raw != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		err = a.doAPI(req, op, out)
+		if err != nil && isTokenError(err) && attempt == 0 {
+			force = true
+			continue
+		}
+		return err
+	}
+}
+
+// callOAPI sends a JSON request to a legacy oapi endpoint, which reports errors through errcode.
+func (a *DingTalkAdapter) callOAPI(ctx context.Context, cfg Config, op, path string, payload any, out any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("dingtalk %s: encode request: %w", op, err)
+	}
+	force := false
+	for attempt := 0; ; attempt++ {
+		token, err := a.accessToken(ctx, cfg, force)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.OAPIBaseURL+path+"?access_token="+url.QueryEscape(token), bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		err = a.doOAPI(req, op, out)
+		if err != nil && isTokenError(err) && attempt == 0 {
+			force = true
+			continue
+		}
+		return err
+	}
+}
+
+// uploadMedia uploads a file through the legacy media API and returns its media_id.
+func (a *DingTalkAdapter) uploadMedia(ctx context.Context, cfg Config, mediaType, name string, data []byte) (string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("media", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	force := false
+	for attempt := 0; ; attempt++ {
+		token, err := a.accessToken(ctx, cfg, force)
+		if err != nil {
+			return "", err
+		}
+		query := url.Values{}
+		query.Set("access_token", token)
+		query.Set("type", mediaType)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.OAPIBaseURL+"/media/upload?"+query.Encode(), bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		var body struct {
+			MediaID string `json:"media_id"`
+		}
+		err = a.doOAPI(req, "upload media", &body)
+		if err != nil && isTokenError(err) && attempt == 0 {
+			force = true
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(body.MediaID) == "" {
+			return "", fmt.Errorf("dingtalk upload media: empty media_id")
+		}
+		return body.MediaID, nil
+	}
+}
+
+func (a *DingTalkAdapter) doAPI(req *http.Request, op string, out any) error {
+	status, data, err := a.do(req)
+	if err != nil {
+		return fmt.Errorf("dingtalk %s: %w", op, err)
+	}
+	if status < 200 || status > 299 {
+		var body struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &body)
+		if body.Message == "" {
+			body.Message = http.StatusText(status)
+		}
+		return &apiError{Op: op, StatusCode: status, Code: body.Code, Message: body.Message}
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("dingtalk %s: decode response: %w", op, err)
+		}
+	}
+	return nil
+}
+
+func (a *DingTalkAdapter) doOAPI(req *http.Request, op string, out any) error {
+	status, data, err := a.do(req)
+	if err != nil {
+		return fmt.Errorf("dingtalk %s: %w", op, err)
+	}
+	if status != http.StatusOK {
+		return &apiError{Op: op, StatusCode: status, Message: http.StatusText(status)}
+	}
+	var body struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return fmt.Errorf("dingtalk %s: decode response: %w", op, err)
+	}
+	if body.ErrCode != 0 {
+		return &apiError{Op: op, StatusCode: status, Code: fmt.Sprint(body.ErrCode), Message: body.ErrMsg}
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("dingtalk %s: decode response: %w", op, err)
+		}
+	}
+	return nil
+}
+
+func (a *DingTalkAdapter) do(req *http.Request) (int, []byte, error) {
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIResponseBytes))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
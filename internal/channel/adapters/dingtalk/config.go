@@ -0,0 +1,164 @@
+package dingtalk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+const (
+	defaultAPIBaseURL  = "https://api.dingtalk.com"
+	defaultOAPIBaseURL = "https://oapi.dingtalk.com"
+
+	targetPrefixUser  = "user:"
+	targetPrefixGroup = "group:"
+)
+
+// Config holds the DingTalk application credentials extracted from a channel configuration.
+type Config struct {
+	AppKey      string
+	AppSecret   string
+	RobotCode   string
+	Token       string
+	AESKey      string
+	APIBaseURL  string
+	OAPIBaseURL string
+}
+
+// UserConfig holds the identifiers used to target a DingTalk user.
+type UserConfig struct {
+	UserID string
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"appKey":    cfg.AppKey,
+		"appSecret": cfg.AppSecret,
+	}
+	if cfg.RobotCode != cfg.AppKey {
+		result["robotCode"] = cfg.RobotCode
+	}
+	if cfg.Token != "" {
+		result["token"] = cfg.Token
+		result["aesKey"] = cfg.AESKey
+	}
+	if cfg.APIBaseURL != defaultAPIBaseURL {
+		result["apiBaseUrl"] = cfg.APIBaseURL
+	}
+	if cfg.OAPIBaseURL != defaultOAPIBaseURL {
+		result["oapiBaseUrl"] = cfg.OAPIBaseURL
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"user_id": cfg.UserID}, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	return targetPrefixUser + cfg.UserID, nil
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := strings.TrimSpace(criteria.Attribute("user_id")); value != "" && value == cfg.UserID {
+		return true
+	}
+	return criteria.SubjectID != "" && criteria.SubjectID == cfg.UserID
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := strings.TrimSpace(identity.Attribute("user_id")); value != "" {
+		result["user_id"] = value
+	} else if value := strings.TrimSpace(identity.SubjectID); value != "" {
+		result["user_id"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	cfg := Config{
+		AppKey:      strings.TrimSpace(channel.ReadString(raw, "appKey", "app_key", "clientId", "client_id")),
+		AppSecret:   strings.TrimSpace(channel.ReadString(raw, "appSecret", "app_secret", "clientSecret", "client_secret")),
+		RobotCode:   strings.TrimSpace(channel.ReadString(raw, "robotCode", "robot_code")),
+		Token:       strings.TrimSpace(channel.ReadString(raw, "token")),
+		AESKey:      strings.TrimSpace(channel.ReadString(raw, "aesKey", "aes_key", "encodingAESKey")),
+		APIBaseURL:  strings.TrimRight(strings.TrimSpace(channel.ReadString(raw, "apiBaseUrl", "api_base_url")), "/"),
+		OAPIBaseURL: strings.TrimRight(strings.TrimSpace(channel.ReadString(raw, "oapiBaseUrl", "oapi_base_url")), "/"),
+	}
+	if cfg.AppKey == "" || cfg.AppSecret == "" {
+		return Config{}, fmt.Errorf("dingtalk appKey and appSecret are required")
+	}
+	if cfg.RobotCode == "" {
+		cfg.RobotCode = cfg.AppKey
+	}
+	if (cfg.Token == "") != (cfg.AESKey == "") {
+		return Config{}, fmt.Errorf("dingtalk token and aesKey must be set together")
+	}
+	if cfg.Token != "" {
+		if _, err := cfg.crypto(); err != nil {
+			return Config{}, fmt.Errorf("dingtalk aesKey must be the 43-character key from the console")
+		}
+	}
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = defaultAPIBaseURL
+	}
+	if cfg.OAPIBaseURL == "" {
+		cfg.OAPIBaseURL = defaultOAPIBaseURL
+	}
+	for _, base := range []string{cfg.APIBaseURL, cfg.OAPIBaseURL} {
+		parsed, err := url.Parse(base)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return Config{}, fmt.Errorf("dingtalk API base URLs must be http(s) URLs")
+		}
+	}
+	return cfg, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id", "staffId", "staff_id"))
+	if userID == "" {
+		return UserConfig{}, fmt.Errorf("dingtalk user config requires user_id")
+	}
+	return UserConfig{UserID: userID}, nil
+}
+
+// normalizeTarget returns "user:<staffId>" or "group:<openConversationId>". Bare conversation IDs
+// (which start with "cid") are treated as groups and anything else as a user ID.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return ""
+	}
+	if strings.HasPrefix(value, targetPrefixUser) || strings.HasPrefix(value, targetPrefixGroup) {
+		return value
+	}
+	if strings.HasPrefix(value, "cid") {
+		return targetPrefixGroup + value
+	}
+	return targetPrefixUser + value
+}
+
+// crypto returns the event-subscription callback crypto; the receiver ID of an internal app is its app key.
+func (c Config) crypto() (*common.CallbackCrypto, error) {
+	return common.NewCallbackCrypto(c.Token, c.AESKey, c.AppKey)
+}
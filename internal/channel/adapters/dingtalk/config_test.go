@@ -0,0 +1,85 @@
+package dingtalk
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const testAESKey = "jWmYm7qr5nMoAUwZRjGtBxmz3KA1tkAj3ykkR6q2B2C"
+
+func validCredentials() map[string]any {
+	return map[string]any{
+		"appKey":    "ding-app",
+		"appSecret": "app-secret",
+		"token":     "cb-token",
+		"aesKey":    testAESKey,
+	}
+}
+
+func TestNormalizeConfig(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{"clientId": "ding-app", "client_secret": "app-secret", "apiBaseUrl": "https://proxy.example.com/"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["appKey"] != "ding-app" || got["appSecret"] != "app-secret" || got["apiBaseUrl"] != "https://proxy.example.com" {
+		t.Fatalf("unexpected config: %#v", got)
+	}
+	for _, key := range []string{"robotCode", "token", "aesKey", "oapiBaseUrl"} {
+		if _, ok := got[key]; ok {
+			t.Fatalf("expected %s to be omitted: %#v", key, got)
+		}
+	}
+	cfg, _ := parseConfig(got)
+	if cfg.RobotCode != "ding-app" {
+		t.Fatalf("expected robotCode to default to appKey, got %q", cfg.RobotCode)
+	}
+}
+
+func TestParseConfigRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	mutations := []func(map[string]any){
+		func(m map[string]any) { delete(m, "appKey") },
+		func(m map[string]any) { delete(m, "appSecret") },
+		func(m map[string]any) { delete(m, "aesKey") },
+		func(m map[string]any) { m["aesKey"] = "short" },
+		func(m map[string]any) { m["oapiBaseUrl"] = "ftp://example.com" },
+	}
+	for i, mutate := range mutations {
+		raw := validCredentials()
+		mutate(raw)
+		if _, err := parseConfig(raw); err == nil {
+			t.Fatalf("case %d: expected error for %#v", i, raw)
+		}
+	}
+}
+
+func TestUserConfigAndTargets(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := normalizeUserConfig(map[string]any{"staffId": "manager4220"})
+	if err != nil || cfg["user_id"] != "manager4220" {
+		t.Fatalf("unexpected user config: %#v err=%v", cfg, err)
+	}
+	target, err := resolveTarget(cfg)
+	if err != nil || target != "user:manager4220" {
+		t.Fatalf("unexpected target %q err=%v", target, err)
+	}
+	if !matchBinding(cfg, channel.BindingCriteria{SubjectID: "manager4220"}) {
+		t.Fatal("expected binding to match subject id")
+	}
+	cases := map[string]string{
+		"manager4220":       "user:manager4220",
+		"cidAbC123==":       "group:cidAbC123==",
+		"group:cidAbC123==": "group:cidAbC123==",
+		" ":                 "",
+	}
+	for input, want := range cases {
+		if got := normalizeTarget(input); got != want {
+			t.Fatalf("normalizeTarget(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package dingtalk implements the DingTalk enterprise robot channel adapter.
+package dingtalk
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for DingTalk.
+const Type channel.ChannelType = "dingtalk"
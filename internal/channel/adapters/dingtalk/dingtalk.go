@@ -0,0 +1,647 @@
+package dingtalk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+const (
+	inboundDedupTTL = 10 * time.Minute
+	// maxCardButtons is the largest sampleActionCard template (sampleActionCard5).
+	maxCardButtons = 5
+	// sendMessageScheme makes a card button post its value back into the chat as the user.
+	sendMessageScheme   = "dtmd://dingtalkclient/sendMessage?content="
+	processingEmotionID = "2659900"
+	processingEmotion   = "🤔Thinking"
+)
+
+// assetOpener reads stored asset bytes by content hash.
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// DingTalkAdapter implements the channel.Adapter, channel.Sender, and channel.Receiver interfaces for DingTalk.
+type DingTalkAdapter struct {
+	logger     *slog.Logger
+	httpClient *http.Client
+	mu         sync.Mutex
+	tokens     map[string]cachedToken // keyed by apiBaseUrl|appKey|appSecret
+	seen       map[string]time.Time   // keyed by configID|msgId
+	assets     assetOpener
+}
+
+// NewDingTalkAdapter creates a DingTalkAdapter with the given logger.
+func NewDingTalkAdapter(log *slog.Logger) *DingTalkAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &DingTalkAdapter{
+		logger:     log.With(slog.String("adapter", "dingtalk")),
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		tokens:     make(map[string]cachedToken),
+		seen:       make(map[string]time.Time),
+	}
+}
+
+// SetAssetOpener injects media asset reader for content_hash attachment delivery.
+func (a *DingTalkAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+// Type returns the DingTalk channel type.
+func (a *DingTalkAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the DingTalk channel metadata.
+func (a *DingTalkAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "DingTalk",
+		Capabilities: channel.ChannelCapabilities{
+			Text:           true,
+			Markdown:       true,
+			Attachments:    true,
+			Media:          true,
+			Buttons:        true,
+			BlockStreaming: true,
+			ChatTypes:      []string{"direct", "group"},
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: 4000,
+			ChunkerMode:    channel.ChunkerModeMarkdown,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"appKey":    {Type: channel.FieldString, Required: true, Title: "AppKey (Client ID)"},
+				"appSecret": {Type: channel.FieldSecret, Required: true, Title: "AppSecret (Client Secret)"},
+				"robotCode": {
+					Type:        channel.FieldString,
+					Title:       "Robot Code",
+					Description: "Defaults to the AppKey",
+				},
+				"token": {
+					Type:        channel.FieldSecret,
+					Title:       "Event Token",
+					Description: "Token for encrypted event subscription callbacks",
+				},
+				"aesKey": {
+					Type:        channel.FieldSecret,
+					Title:       "Event AES Key",
+					Description: "43-character aes_key for encrypted event subscription callbacks",
+				},
+				"apiBaseUrl": {
+					Type:    channel.FieldString,
+					Title:   "API Base URL",
+					Example: defaultAPIBaseURL,
+				},
+				"oapiBaseUrl": {
+					Type:    channel.FieldString,
+					Title:   "Legacy API Base URL",
+					Example: defaultOAPIBaseURL,
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"user_id": {Type: channel.FieldString, Required: true},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "user:staffId | group:openConversationId",
+			Hints: []channel.TargetHint{
+				{Label: "User ID", Example: "user:manager4220"},
+				{Label: "Group", Example: "group:cidxxxxxxxx"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a DingTalk channel configuration map.
+func (a *DingTalkAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a DingTalk user-binding configuration map.
+func (a *DingTalkAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a DingTalk delivery target string.
+func (a *DingTalkAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a DingTalk user-binding configuration.
+func (a *DingTalkAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a DingTalk user binding matches the given criteria.
+func (a *DingTalkAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a DingTalk user-binding config from an Identity.
+func (a *DingTalkAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// Connect validates the configuration. Robot messages arrive through WebhookHandler, so there
+// is no long-lived connection to maintain.
+func (a *DingTalkAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	if _, err := parseConfig(cfg.Credentials); err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+	if a.logger != nil {
+		a.logger.Info("callback inbound ready", slog.String("config_id", cfg.ID))
+	}
+	return channel.NewConnection(cfg, func(context.Context) error { return nil }), nil
+}
+
+// Send delivers an outbound message to a DingTalk user or group through the robot.
+func (a *DingTalkAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return err
+	}
+	target := normalizeTarget(msg.Target)
+	if target == "" {
+		return fmt.Errorf("dingtalk target is required")
+	}
+	if err := a.sendMessage(ctx, cfg, dingCfg, target, msg.Message); err != nil {
+		if a.logger != nil {
+			a.logger.Error("send failed", slog.String("config_id", cfg.ID), slog.String("target", target), slog.Any("error", err))
+		}
+		return err
+	}
+	return nil
+}
+
+// sendMessage sends attachments first and then the text, as an action card when actions are present.
+func (a *DingTalkAdapter) sendMessage(ctx context.Context, cfg channel.ChannelConfig, dingCfg Config, target string, msg channel.Message) error {
+	for _, att := range msg.Attachments {
+		if err := a.sendAttachment(ctx, cfg, dingCfg, target, att); err != nil {
+			return err
+		}
+	}
+	text := strings.TrimSpace(msg.PlainText())
+	if len(msg.Actions) > 0 {
+		msgKey, param := buildActionCard(text, msg.Actions)
+		return a.postMessage(ctx, dingCfg, target, msgKey, param)
+	}
+	if text == "" {
+		if len(msg.Attachments) == 0 {
+			return fmt.Errorf("message is required")
+		}
+		return nil
+	}
+	if msg.Format == channel.MessageFormatMarkdown {
+		return a.postMessage(ctx, dingCfg, target, "sampleMarkdown", map[string]string{"title": cardTitle(text), "text": text})
+	}
+	return a.postMessage(ctx, dingCfg, target, "sampleText", map[string]string{"content": text})
+}
+
+// postMessage sends one robot message to a user (batch one-to-one API) or a group.
+func (a *DingTalkAdapter) postMessage(ctx context.Context, cfg Config, target, msgKey string, param any) error {
+	encoded, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf("dingtalk send message: encode msgParam: %w", err)
+	}
+	payload := map[string]any{
+		"robotCode": cfg.RobotCode,
+		"msgKey":    msgKey,
+		"msgParam":  string(encoded),
+	}
+	if groupID, ok := strings.CutPrefix(target, targetPrefixGroup); ok {
+		payload["openConversationId"] = groupID
+		return a.callAPI(ctx, cfg, "send group message", http.MethodPost, "/v1.0/robot/groupMessages/send", payload, nil)
+	}
+	payload["userIds"] = []string{strings.TrimPrefix(target, targetPrefixUser)}
+	var body struct {
+		InvalidStaffIDList        []string `json:"invalidStaffIdList"`
+		FlowControlledStaffIDList []string `json:"flowControlledStaffIdList"`
+	}
+	if err := a.callAPI(ctx, cfg, "send message", http.MethodPost, "/v1.0/robot/oToMessages/batchSend", payload, &body); err != nil {
+		return err
+	}
+	if len(body.InvalidStaffIDList) > 0 {
+		return fmt.Errorf("dingtalk send message: invalid user %s", strings.Join(body.InvalidStaffIDList, ","))
+	}
+	if len(body.FlowControlledStaffIDList) > 0 {
+		return fmt.Errorf("dingtalk send message: rate limited for user %s", strings.Join(body.FlowControlledStaffIDList, ","))
+	}
+	return nil
+}
+
+// buildActionCard renders text and actions as a sampleActionCard template. Value actions become
+// buttons that send their value back into the conversation.
+func buildActionCard(text string, actions []channel.Action) (string, map[string]string) {
+	type button struct{ title, url string }
+	buttons := make([]button, 0, min(len(actions), maxCardButtons))
+	for _, action := range actions {
+		if len(buttons) == maxCardButtons {
+			break
+		}
+		label := strings.TrimSpace(action.Label)
+		target := strings.TrimSpace(action.URL)
+		if target == "" {
+			value := strings.TrimSpace(action.Value)
+			if value == "" {
+				value = label
+			}
+			if value == "" {
+				continue
+			}
+			target = sendMessageScheme + url.QueryEscape(value)
+		}
+		if label == "" {
+			label = strings.TrimSpace(action.Value)
+		}
+		if label == "" {
+			label = target
+		}
+		buttons = append(buttons, button{title: label, url: target})
+	}
+	param := map[string]string{
+		"title": cardTitle(text),
+		"text":  text,
+	}
+	switch len(buttons) {
+	case 0:
+		return "sampleMarkdown", param
+	case 1:
+		param["singleTitle"] = buttons[0].title
+		param["singleURL"] = buttons[0].url
+		return "sampleActionCard", param
+	}
+	for i, b := range buttons {
+		param[fmt.Sprintf("actionTitle%d", i+1)] = b.title
+		param[fmt.Sprintf("actionURL%d", i+1)] = b.url
+	}
+	return fmt.Sprintf("sampleActionCard%d", len(buttons)), param
+}
+
+// cardTitle derives the notification title shown in the chat list from the first line of text.
+func cardTitle(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	line = strings.TrimSpace(strings.TrimLeft(line, "#>*- "))
+	if runes := []rune(line); len(runes) > 30 {
+		line = string(runes[:29]) + "…"
+	}
+	if line == "" {
+		return "Message"
+	}
+	return line
+}
+
+func (a *DingTalkAdapter) sendAttachment(ctx context.Context, cfg channel.ChannelConfig, dingCfg Config, target string, att channel.Attachment) error {
+	mediaID := ""
+	name := strings.TrimSpace(att.Name)
+	mimeType := strings.TrimSpace(att.Mime)
+	if key := strings.TrimSpace(att.PlatformKey); key != "" && strings.EqualFold(strings.TrimSpace(att.SourcePlatform), Type.String()) &&
+		strings.HasPrefix(key, "@") {
+		// Media IDs start with "@"; inbound download codes cannot be re-sent and are re-uploaded below.
+		mediaID = key
+	} else {
+		data, resolvedMime, err := a.readAttachmentBytes(ctx, cfg, att)
+		if err != nil {
+			return err
+		}
+		if mimeType == "" {
+			mimeType = resolvedMime
+		}
+		if name == "" {
+			name = fileNameFromMime(mimeType, string(att.Type))
+		}
+		mediaID, err = a.uploadMedia(ctx, dingCfg, dingtalkMediaType(att, mimeType), name, data)
+		if err != nil {
+			return err
+		}
+	}
+	switch dingtalkMediaType(att, mimeType) {
+	case "image":
+		return a.postMessage(ctx, dingCfg, target, "sampleImageMsg", map[string]string{"photoURL": mediaID})
+	case "voice":
+		return a.postMessage(ctx, dingCfg, target, "sampleAudio", map[string]string{
+			"mediaId":  mediaID,
+			"duration": fmt.Sprint(max(att.DurationMs, 1000)),
+		})
+	default:
+		if name == "" {
+			name = fileNameFromMime(mimeType, string(att.Type))
+		}
+		ext := strings.TrimPrefix(path.Ext(name), ".")
+		if ext == "" {
+			ext = "file"
+		}
+		return a.postMessage(ctx, dingCfg, target, "sampleFile", map[string]string{
+			"mediaId":  mediaID,
+			"fileName": name,
+			"fileType": ext,
+		})
+	}
+}
+
+// dingtalkMediaType maps an attachment to a DingTalk media type. Robot voice messages must be
+// AMR or OGG; video is sent as a file since video messages also need a cover image.
+func dingtalkMediaType(att channel.Attachment, mimeType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	switch {
+	case att.Type == channel.AttachmentImage || (att.Type != channel.AttachmentFile && strings.HasPrefix(mimeType, "image/")):
+		return "image"
+	case strings.HasPrefix(mimeType, "audio/amr") || strings.HasPrefix(mimeType, "audio/ogg"):
+		return "voice"
+	default:
+		return "file"
+	}
+}
+
+// ResolveAttachment exchanges an inbound download code (the attachment platform key) for a
+// temporary URL and downloads the file.
+func (a *DingTalkAdapter) ResolveAttachment(ctx context.Context, cfg channel.ChannelConfig, attachment channel.Attachment) (channel.AttachmentPayload, error) {
+	downloadCode := strings.TrimSpace(attachment.PlatformKey)
+	if downloadCode == "" {
+		return channel.AttachmentPayload{}, fmt.Errorf("dingtalk attachment platform_key is required")
+	}
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	var body struct {
+		DownloadURL string `json:"downloadUrl"`
+	}
+	err = a.callAPI(ctx, dingCfg, "download message file", http.MethodPost, "/v1.0/robot/messageFiles/download", map[string]string{
+		"downloadCode": downloadCode,
+		"robotCode":    dingCfg.RobotCode,
+	}, &body)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	if strings.TrimSpace(body.DownloadURL) == "" {
+		return channel.AttachmentPayload{}, fmt.Errorf("dingtalk download message file: empty downloadUrl")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, body.DownloadURL, nil)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return channel.AttachmentPayload{}, fmt.Errorf("download dingtalk file: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return channel.AttachmentPayload{}, fmt.Errorf("download dingtalk file: status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > media.MaxAssetBytes {
+		_ = resp.Body.Close()
+		return channel.AttachmentPayload{}, fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	mimeType := strings.TrimSpace(attachment.Mime)
+	if mimeType == "" {
+		mimeType = stripMimeParams(resp.Header.Get("Content-Type"))
+	}
+	size := attachment.Size
+	if size == 0 && resp.ContentLength > 0 {
+		size = resp.ContentLength
+	}
+	return channel.AttachmentPayload{
+		Reader: resp.Body,
+		Mime:   mimeType,
+		Name:   strings.TrimSpace(attachment.Name),
+		Size:   size,
+	}, nil
+}
+
+// ProcessingStarted adds a "thinking" emotion to the inbound message while the reply is generated.
+func (a *DingTalkAdapter) ProcessingStarted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+	messageID := strings.TrimSpace(info.SourceMessageID)
+	conversationID := strings.TrimSpace(msg.Conversation.ID)
+	if messageID == "" || conversationID == "" {
+		return channel.ProcessingStatusHandle{}, nil
+	}
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	if err := a.callAPI(ctx, dingCfg, "add emotion", http.MethodPost, "/v1.0/robot/emotion/reply", emotionPayload(dingCfg, messageID, conversationID), nil); err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	return channel.ProcessingStatusHandle{Token: messageID}, nil
+}
+
+// ProcessingCompleted recalls the processing emotion before output is sent.
+func (a *DingTalkAdapter) ProcessingCompleted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+	messageID := strings.TrimSpace(handle.Token)
+	conversationID := strings.TrimSpace(msg.Conversation.ID)
+	if messageID == "" || conversationID == "" {
+		return nil
+	}
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	return a.callAPI(ctx, dingCfg, "recall emotion", http.MethodPost, "/v1.0/robot/emotion/recall", emotionPayload(dingCfg, messageID, conversationID), nil)
+}
+
+// ProcessingFailed recalls the processing emotion when chat processing fails.
+func (a *DingTalkAdapter) ProcessingFailed(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+	return a.ProcessingCompleted(ctx, cfg, msg, info, handle)
+}
+
+func emotionPayload(cfg Config, messageID, conversationID string) map[string]any {
+	return map[string]any{
+		"robotCode":          cfg.RobotCode,
+		"openMsgId":          messageID,
+		"openConversationId": conversationID,
+		"emotionType":        2,
+		"emotionName":        processingEmotion,
+		"textEmotion": map[string]string{
+			"emotionId":    processingEmotionID,
+			"emotionName":  processingEmotion,
+			"text":         processingEmotion,
+			"backgroundId": "im_bg_1",
+		},
+	}
+}
+
+// OpenStream opens a block-streaming session that sends the reply once it is complete.
+func (a *DingTalkAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	target = normalizeTarget(target)
+	if target == "" {
+		return nil, fmt.Errorf("dingtalk target is required")
+	}
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	return &dingtalkOutboundStream{
+		adapter: a,
+		cfg:     cfg,
+		dingCfg: dingCfg,
+		target:  target,
+	}, nil
+}
+
+func (a *DingTalkAdapter) isDuplicateInbound(key, msgID string) bool {
+	if strings.TrimSpace(msgID) == "" {
+		return false
+	}
+	now := time.Now().UTC()
+	expireBefore := now.Add(-inboundDedupTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for seenKey, seenAt := range a.seen {
+		if seenAt.Before(expireBefore) {
+			delete(a.seen, seenKey)
+		}
+	}
+	seenKey := key + "|" + msgID
+	if _, ok := a.seen[seenKey]; ok {
+		return true
+	}
+	a.seen[seenKey] = now
+	return false
+}
+
+func (a *DingTalkAdapter) readAttachmentBytes(ctx context.Context, cfg channel.ChannelConfig, att channel.Attachment) ([]byte, string, error) {
+	mimeType := strings.TrimSpace(att.Mime)
+	if hash := strings.TrimSpace(att.ContentHash); hash != "" && a.assets != nil {
+		botID := cfg.BotID
+		if att.Metadata != nil {
+			if bid, ok := att.Metadata["bot_id"].(string); ok && strings.TrimSpace(bid) != "" {
+				botID = strings.TrimSpace(bid)
+			}
+		}
+		reader, asset, err := a.assets.Open(ctx, botID, hash)
+		if err == nil {
+			data, readErr := io.ReadAll(io.LimitReader(reader, media.MaxAssetBytes+1))
+			_ = reader.Close()
+			if readErr == nil && len(data) > 0 {
+				if mimeType == "" {
+					mimeType = asset.Mime
+				}
+				return data, mimeType, nil
+			}
+		}
+	}
+	dataURL := strings.TrimSpace(att.Base64)
+	if dataURL == "" && strings.HasPrefix(strings.ToLower(strings.TrimSpace(att.URL)), "data:") {
+		dataURL = strings.TrimSpace(att.URL)
+	}
+	if dataURL != "" {
+		data, dataMime, err := decodeDataURL(dataURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode data url for dingtalk attachment: %w", err)
+		}
+		if mimeType == "" {
+			mimeType = dataMime
+		}
+		return data, mimeType, nil
+	}
+	urlRef := strings.TrimSpace(att.URL)
+	if strings.HasPrefix(urlRef, "http://") || strings.HasPrefix(urlRef, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlRef, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("download attachment: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("download attachment status: %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, media.MaxAssetBytes+1))
+		if err != nil {
+			return nil, "", err
+		}
+		if int64(len(data)) > media.MaxAssetBytes {
+			return nil, "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+		}
+		if mimeType == "" {
+			mimeType = stripMimeParams(resp.Header.Get("Content-Type"))
+		}
+		return data, mimeType, nil
+	}
+	return nil, "", fmt.Errorf("no usable attachment reference for dingtalk")
+}
+
+func decodeDataURL(dataURL string) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(dataURL, ",")
+	if !ok {
+		return nil, "", fmt.Errorf("malformed data url")
+	}
+	mimeType := stripMimeParams(strings.TrimPrefix(strings.TrimPrefix(header, "data:"), "DATA:"))
+	data, err := io.ReadAll(io.LimitReader(
+		base64.NewDecoder(base64.StdEncoding, strings.NewReader(payload)),
+		media.MaxAssetBytes+1,
+	))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mimeType, nil
+}
+
+func stripMimeParams(value string) string {
+	value = strings.TrimSpace(value)
+	if idx := strings.Index(value, ";"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
+
+func fileNameFromMime(mimeType, fallbackType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	switch {
+	case strings.HasPrefix(mimeType, "image/png"):
+		return "image.png"
+	case strings.HasPrefix(mimeType, "image/jpeg"):
+		return "image.jpg"
+	case strings.HasPrefix(mimeType, "image/gif"):
+		return "image.gif"
+	case strings.HasPrefix(mimeType, "audio/amr"):
+		return "voice.amr"
+	case strings.HasPrefix(mimeType, "audio/ogg"):
+		return "voice.ogg"
+	case strings.HasPrefix(mimeType, "audio/mpeg"):
+		return "audio.mp3"
+	case strings.HasPrefix(mimeType, "video/mp4"):
+		return "video.mp4"
+	case strings.HasPrefix(mimeType, "application/pdf"):
+		return "document.pdf"
+	}
+	if fallbackType == "" {
+		return "file"
+	}
+	return fallbackType
+}
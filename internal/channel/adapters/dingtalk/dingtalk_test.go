@@ -0,0 +1,282 @@
+package dingtalk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// stubAPI is a minimal DingTalk API server serving both the v1.0 and legacy oapi endpoints.
+type stubAPI struct {
+	mu          sync.Mutex
+	baseURL     string
+	tokenCalls  int
+	expireFirst bool
+	sent        []map[string]any
+	uploads     []string
+	emotions    []string
+}
+
+func (s *stubAPI) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	decode := func(r *http.Request) map[string]any {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode %s body: %v", r.URL.Path, err)
+		}
+		return body
+	}
+	mux.HandleFunc("/v1.0/oauth2/accessToken", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		if body["appSecret"] != "app-secret" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": "invalidClientSecret", "message": "bad secret"})
+			return
+		}
+		s.mu.Lock()
+		s.tokenCalls++
+		token := "tok-" + string(rune('0'+s.tokenCalls))
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"accessToken": token, "expireIn": 7200})
+	})
+	recordSend := func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		expire := s.expireFirst && r.Header.Get(accessTokenHeader) == "tok-1"
+		s.mu.Unlock()
+		if expire {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"code": "InvalidAuthentication", "message": "token expired"})
+			return
+		}
+		body := decode(r)
+		body["path"] = r.URL.Path
+		s.mu.Lock()
+		s.sent = append(s.sent, body)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"processQueryKey": "pq-1"})
+	}
+	mux.HandleFunc("/v1.0/robot/oToMessages/batchSend", recordSend)
+	mux.HandleFunc("/v1.0/robot/groupMessages/send", recordSend)
+	mux.HandleFunc("/media/upload", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("media")
+		if err != nil {
+			t.Errorf("read upload: %v", err)
+			return
+		}
+		_, _ = io.ReadAll(file)
+		s.mu.Lock()
+		s.uploads = append(s.uploads, r.URL.Query().Get("type")+":"+header.Filename)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"errcode": 0, "media_id": "@media-up"})
+	})
+	mux.HandleFunc("/v1.0/robot/messageFiles/download", func(w http.ResponseWriter, r *http.Request) {
+		if decode(r)["downloadCode"] != "code-in" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": "invalidParameter", "message": "bad downloadCode"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"downloadUrl": s.baseURL + "/files/photo"})
+	})
+	mux.HandleFunc("/files/photo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte("jpeg-bytes"))
+	})
+	recordEmotion := func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		s.mu.Lock()
+		s.emotions = append(s.emotions, r.URL.Path+":"+body["openMsgId"].(string))
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"result": true})
+	}
+	mux.HandleFunc("/v1.0/robot/emotion/reply", recordEmotion)
+	mux.HandleFunc("/v1.0/robot/emotion/recall", recordEmotion)
+	mux.HandleFunc("/topapi/user/listsimple", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"errcode": 0, "result": map[string]any{"list": []map[string]any{
+			{"userid": "zhangsan", "name": "Zhang San"},
+			{"userid": "lisi", "name": "Li Si"},
+		}}})
+	})
+	mux.HandleFunc("/topapi/v2/user/get", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		writeJSON(w, http.StatusOK, map[string]any{"errcode": 0, "result": map[string]any{
+			"userid": body["userid"], "name": "Zhang San", "avatar": "https://example.com/a.png",
+		}})
+	})
+	return mux
+}
+
+func newStubAdapter(t *testing.T, api *stubAPI) (*DingTalkAdapter, channel.ChannelConfig) {
+	t.Helper()
+	srv := httptest.NewServer(api.handler(t))
+	t.Cleanup(srv.Close)
+	api.baseURL = srv.URL
+	creds := validCredentials()
+	creds["apiBaseUrl"] = srv.URL
+	creds["oapiBaseUrl"] = srv.URL
+	return NewDingTalkAdapter(nil), channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: Type, Credentials: creds}
+}
+
+func decodeMsgParam(t *testing.T, sent map[string]any) map[string]any {
+	t.Helper()
+	var param map[string]any
+	if err := json.Unmarshal([]byte(sent["msgParam"].(string)), &param); err != nil {
+		t.Fatalf("decode msgParam: %v", err)
+	}
+	return param
+}
+
+func TestSendMarkdownRefreshesExpiredToken(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{expireFirst: true}
+	adapter, cfg := newStubAdapter(t, api)
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target:  "manager4220",
+		Message: channel.Message{Format: channel.MessageFormatMarkdown, Text: "## Report\n**done**"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if api.tokenCalls != 2 {
+		t.Fatalf("expected token refresh, got %d token calls", api.tokenCalls)
+	}
+	got := api.sent[0]
+	if got["path"] != "/v1.0/robot/oToMessages/batchSend" || got["msgKey"] != "sampleMarkdown" || got["robotCode"] != "ding-app" {
+		t.Fatalf("unexpected payload: %#v", got)
+	}
+	if users := got["userIds"].([]any); len(users) != 1 || users[0] != "manager4220" {
+		t.Fatalf("unexpected userIds: %#v", got["userIds"])
+	}
+	param := decodeMsgParam(t, got)
+	if param["title"] != "Report" || param["text"] != "## Report\n**done**" {
+		t.Fatalf("unexpected msgParam: %#v", param)
+	}
+}
+
+func TestSendActionsAsActionCard(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target: "group:cidAbC123==",
+		Message: channel.Message{
+			Text: "Approve the request?",
+			Actions: []channel.Action{
+				{Type: "button", Label: "Approve", Value: "approve #1"},
+				{Type: "link", Label: "Details", URL: "https://example.com/r/1"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	got := api.sent[0]
+	if got["path"] != "/v1.0/robot/groupMessages/send" || got["openConversationId"] != "cidAbC123==" || got["msgKey"] != "sampleActionCard2" {
+		t.Fatalf("unexpected payload: %#v", got)
+	}
+	param := decodeMsgParam(t, got)
+	if param["actionTitle1"] != "Approve" || param["actionURL1"] != "dtmd://dingtalkclient/sendMessage?content=approve+%231" {
+		t.Fatalf("unexpected first button: %#v", param)
+	}
+	if param["actionTitle2"] != "Details" || param["actionURL2"] != "https://example.com/r/1" {
+		t.Fatalf("unexpected second button: %#v", param)
+	}
+}
+
+func TestSendAttachmentUploadsMedia(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target: "user:manager4220",
+		Message: channel.Message{Attachments: []channel.Attachment{{
+			Type:   channel.AttachmentFile,
+			Name:   "report.pdf",
+			Base64: "data:application/pdf;base64,aGVsbG8=",
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(api.uploads) != 1 || api.uploads[0] != "file:report.pdf" {
+		t.Fatalf("unexpected uploads: %v", api.uploads)
+	}
+	param := decodeMsgParam(t, api.sent[0])
+	if api.sent[0]["msgKey"] != "sampleFile" || param["mediaId"] != "@media-up" || param["fileType"] != "pdf" {
+		t.Fatalf("unexpected payload: %#v %#v", api.sent[0], param)
+	}
+}
+
+func TestResolveAttachmentDownloadsFile(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	payload, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "code-in"})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	defer payload.Reader.Close()
+	data, _ := io.ReadAll(payload.Reader)
+	if string(data) != "jpeg-bytes" || payload.Mime != "image/jpeg" {
+		t.Fatalf("unexpected payload: %q %s", data, payload.Mime)
+	}
+	if _, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "missing"}); err == nil {
+		t.Fatal("expected error for unknown download code")
+	}
+}
+
+func TestProcessingStatusAddsAndRecallsEmotion(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	msg := channel.InboundMessage{Conversation: channel.Conversation{ID: "cidAbC123=="}}
+	info := channel.ProcessingStatusInfo{SourceMessageID: "msg-1"}
+	handle, err := adapter.ProcessingStarted(context.Background(), cfg, msg, info)
+	if err != nil || handle.Token != "msg-1" {
+		t.Fatalf("unexpected handle %q err=%v", handle.Token, err)
+	}
+	if err := adapter.ProcessingFailed(context.Background(), cfg, msg, info, handle, context.Canceled); err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	want := []string{"/v1.0/robot/emotion/reply:msg-1", "/v1.0/robot/emotion/recall:msg-1"}
+	if len(api.emotions) != 2 || api.emotions[0] != want[0] || api.emotions[1] != want[1] {
+		t.Fatalf("unexpected emotion calls: %v", api.emotions)
+	}
+}
+
+func TestDirectoryLookup(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	peers, err := adapter.ListPeers(context.Background(), cfg, channel.DirectoryQuery{Query: "li"})
+	if err != nil {
+		t.Fatalf("list peers: %v", err)
+	}
+	if len(peers) != 1 || peers[0].ID != "user:lisi" || peers[0].Name != "Li Si" {
+		t.Fatalf("unexpected peers: %#v", peers)
+	}
+	entry, err := adapter.ResolveEntry(context.Background(), cfg, "user:zhangsan", channel.DirectoryEntryUser)
+	if err != nil {
+		t.Fatalf("resolve entry: %v", err)
+	}
+	if entry.ID != "user:zhangsan" || entry.AvatarURL != "https://example.com/a.png" {
+		t.Fatalf("unexpected entry: %#v", entry)
+	}
+	group, err := adapter.ResolveEntry(context.Background(), cfg, "cidAbC123==", channel.DirectoryEntryGroup)
+	if err != nil || group.ID != "group:cidAbC123==" {
+		t.Fatalf("unexpected group entry: %#v err=%v", group, err)
+	}
+}
@@ -0,0 +1,138 @@
+package dingtalk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	defaultDirectoryLimit = 50
+	// maxDirectoryPageSize is the largest page /topapi/user/listsimple accepts.
+	maxDirectoryPageSize = 100
+	// rootDepartmentID is the top-level department of the organization.
+	rootDepartmentID = 1
+)
+
+func directoryLimit(n int) int {
+	if n <= 0 {
+		return defaultDirectoryLimit
+	}
+	if n > maxDirectoryPageSize {
+		return maxDirectoryPageSize
+	}
+	return n
+}
+
+type dingtalkUser struct {
+	UserID string `json:"userid"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+	Title  string `json:"title"`
+}
+
+// ListPeers lists users of the root department, optionally filtered by query.
+func (a *DingTalkAdapter) ListPeers(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Result struct {
+			List []dingtalkUser `json:"list"`
+		} `json:"result"`
+	}
+	err = a.callOAPI(ctx, dingCfg, "list users", "/topapi/user/listsimple", map[string]any{
+		"dept_id": rootDepartmentID,
+		"cursor":  0,
+		"size":    maxDirectoryPageSize,
+	}, &body)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	needle := strings.ToLower(strings.TrimSpace(query.Query))
+	entries := make([]channel.DirectoryEntry, 0, min(len(body.Result.List), limit))
+	for _, u := range body.Result.List {
+		if len(entries) >= limit {
+			break
+		}
+		e := dingtalkUserToEntry(u)
+		if needle != "" && !strings.Contains(strings.ToLower(e.Name+" "+u.UserID), needle) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// ListGroups returns nil: DingTalk robots cannot enumerate the groups they were added to.
+// Groups become known through inbound messages and can be addressed by openConversationId.
+func (a *DingTalkAdapter) ListGroups(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	return nil, nil
+}
+
+// ListGroupMembers returns nil: group membership is not exposed to robot applications.
+func (a *DingTalkAdapter) ListGroupMembers(ctx context.Context, cfg channel.ChannelConfig, groupID string, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	return nil, nil
+}
+
+// ResolveEntry resolves a staff ID to a user entry. Group IDs are returned as-is since there is
+// no robot API to look them up.
+func (a *DingTalkAdapter) ResolveEntry(ctx context.Context, cfg channel.ChannelConfig, input string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	switch kind {
+	case channel.DirectoryEntryUser:
+		userID := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), targetPrefixUser))
+		if userID == "" {
+			return channel.DirectoryEntry{}, fmt.Errorf("dingtalk resolve entry user: empty input")
+		}
+		var body struct {
+			Result dingtalkUser `json:"result"`
+		}
+		if err := a.callOAPI(ctx, dingCfg, "get user", "/topapi/v2/user/get", map[string]string{"userid": userID}, &body); err != nil {
+			return channel.DirectoryEntry{}, err
+		}
+		if strings.TrimSpace(body.Result.UserID) == "" {
+			body.Result.UserID = userID
+		}
+		return dingtalkUserToEntry(body.Result), nil
+	case channel.DirectoryEntryGroup:
+		groupID := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), targetPrefixGroup))
+		if groupID == "" {
+			return channel.DirectoryEntry{}, fmt.Errorf("dingtalk resolve entry group: empty input")
+		}
+		return channel.DirectoryEntry{
+			Kind:     channel.DirectoryEntryGroup,
+			ID:       targetPrefixGroup + groupID,
+			Name:     groupID,
+			Metadata: map[string]any{"conversation_id": groupID},
+		}, nil
+	default:
+		return channel.DirectoryEntry{}, fmt.Errorf("dingtalk resolve entry: unsupported kind %q", kind)
+	}
+}
+
+func dingtalkUserToEntry(u dingtalkUser) channel.DirectoryEntry {
+	userID := strings.TrimSpace(u.UserID)
+	name := strings.TrimSpace(u.Name)
+	if name == "" {
+		name = userID
+	}
+	metadata := map[string]any{"user_id": userID}
+	if title := strings.TrimSpace(u.Title); title != "" {
+		metadata["title"] = title
+	}
+	return channel.DirectoryEntry{
+		Kind:      channel.DirectoryEntryUser,
+		ID:        targetPrefixUser + userID,
+		Name:      name,
+		AvatarURL: strings.TrimSpace(u.Avatar),
+		Metadata:  metadata,
+	}
+}
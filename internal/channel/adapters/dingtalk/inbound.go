@@ -0,0 +1,211 @@
+package dingtalk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// robotSignatureWindow is how far the callback timestamp may drift from the local clock.
+const robotSignatureWindow = time.Hour
+
+var (
+	errMissingSignature = errors.New("missing dingtalk signature")
+	errStaleSignature   = errors.New("dingtalk signature timestamp out of range")
+	errInvalidSignature = errors.New("invalid dingtalk signature")
+)
+
+// robotMessage is the JSON body DingTalk posts to a robot's HTTP message endpoint.
+type robotMessage struct {
+	MsgID             string          `json:"msgId"`
+	MsgType           string          `json:"msgtype"`
+	CreateAt          int64           `json:"createAt"`
+	ConversationID    string          `json:"conversationId"`
+	ConversationType  string          `json:"conversationType"`
+	ConversationTitle string          `json:"conversationTitle"`
+	SenderID          string          `json:"senderId"`
+	SenderStaffID     string          `json:"senderStaffId"`
+	SenderNick        string          `json:"senderNick"`
+	IsInAtList        bool            `json:"isInAtList"`
+	RobotCode         string          `json:"robotCode"`
+	ChatbotUserID     string          `json:"chatbotUserId"`
+	Text              robotText       `json:"text"`
+	Content           json.RawMessage `json:"content"`
+}
+
+type robotText struct {
+	Content string `json:"content"`
+}
+
+// robotContent covers the content object of picture, file, audio, video and richText messages.
+type robotContent struct {
+	DownloadCode        string `json:"downloadCode"`
+	PictureDownloadCode string `json:"pictureDownloadCode"`
+	FileName            string `json:"fileName"`
+	Duration            int64  `json:"duration"`
+	Recognition         string `json:"recognition"`
+	RichText            []struct {
+		Text         string `json:"text"`
+		Type         string `json:"type"`
+		DownloadCode string `json:"downloadCode"`
+	} `json:"richText"`
+}
+
+// robotSign computes the signature DingTalk sends with robot callbacks:
+// base64(HMAC-SHA256(appSecret, timestamp + "\n" + appSecret)).
+func robotSign(appSecret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(appSecret))
+	mac.Write([]byte(timestamp + "\n" + appSecret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyRobotSignature checks the timestamp and sign headers of a robot callback.
+func verifyRobotSignature(appSecret, timestamp, sign string, now time.Time) error {
+	timestamp = strings.TrimSpace(timestamp)
+	sign = strings.TrimSpace(sign)
+	if timestamp == "" || sign == "" {
+		return errMissingSignature
+	}
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errInvalidSignature
+	}
+	drift := now.Sub(time.UnixMilli(ms))
+	if drift > robotSignatureWindow || drift < -robotSignatureWindow {
+		return errStaleSignature
+	}
+	if !hmac.Equal([]byte(robotSign(appSecret, timestamp)), []byte(sign)) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// buildInboundMessage maps a robot callback to an inbound channel message. It reports false for
+// message types the adapter does not handle.
+func buildInboundMessage(cfg channel.ChannelConfig, raw robotMessage, now time.Time) (channel.InboundMessage, bool) {
+	userID := strings.TrimSpace(raw.SenderStaffID)
+	if userID == "" {
+		userID = strings.TrimSpace(raw.SenderID)
+	}
+	conversationID := strings.TrimSpace(raw.ConversationID)
+	if userID == "" || conversationID == "" {
+		return channel.InboundMessage{}, false
+	}
+	var content robotContent
+	if len(raw.Content) > 0 {
+		_ = json.Unmarshal(raw.Content, &content)
+	}
+	msgType := strings.TrimSpace(raw.MsgType)
+	message := channel.Message{
+		ID:     strings.TrimSpace(raw.MsgID),
+		Format: channel.MessageFormatPlain,
+	}
+	newAttachment := func(kind channel.AttachmentType, code, name, mime string) channel.Attachment {
+		return channel.Attachment{
+			Type:           kind,
+			PlatformKey:    code,
+			SourcePlatform: Type.String(),
+			Name:           name,
+			Mime:           mime,
+			DurationMs:     content.Duration,
+			Metadata:       map[string]any{"msg_type": msgType, "message_id": message.ID},
+		}
+	}
+	switch msgType {
+	case "text":
+		message.Text = strings.TrimSpace(raw.Text.Content)
+	case "picture":
+		code := firstNonEmpty(content.DownloadCode, content.PictureDownloadCode)
+		if code != "" {
+			message.Attachments = append(message.Attachments, newAttachment(channel.AttachmentImage, code, "", "image/png"))
+		}
+	case "file":
+		if code := strings.TrimSpace(content.DownloadCode); code != "" {
+			message.Attachments = append(message.Attachments, newAttachment(channel.AttachmentFile, code, strings.TrimSpace(content.FileName), ""))
+		}
+	case "audio":
+		if code := strings.TrimSpace(content.DownloadCode); code != "" {
+			message.Attachments = append(message.Attachments, newAttachment(channel.AttachmentVoice, code, "", ""))
+		}
+		// DingTalk transcribes voice messages; use the transcript as text so the bot can answer without STT.
+		message.Text = strings.TrimSpace(content.Recognition)
+	case "video":
+		if code := strings.TrimSpace(content.DownloadCode); code != "" {
+			message.Attachments = append(message.Attachments, newAttachment(channel.AttachmentVideo, code, "", "video/mp4"))
+		}
+	case "richText":
+		lines := make([]string, 0, len(content.RichText))
+		for _, item := range content.RichText {
+			if text := strings.TrimSpace(item.Text); text != "" {
+				lines = append(lines, text)
+			}
+			if code := strings.TrimSpace(item.DownloadCode); code != "" && item.Type == "picture" {
+				message.Attachments = append(message.Attachments, newAttachment(channel.AttachmentImage, code, "", "image/png"))
+			}
+		}
+		message.Text = strings.Join(lines, "\n")
+	default:
+		return channel.InboundMessage{}, false
+	}
+	if strings.TrimSpace(message.Text) == "" && len(message.Attachments) == 0 {
+		return channel.InboundMessage{}, false
+	}
+
+	chatType := "direct"
+	replyTarget := targetPrefixUser + userID
+	if raw.ConversationType == "2" {
+		chatType = "group"
+		replyTarget = targetPrefixGroup + conversationID
+	}
+	receivedAt := now.UTC()
+	if raw.CreateAt > 0 {
+		receivedAt = time.UnixMilli(raw.CreateAt).UTC()
+	}
+	displayName := strings.TrimSpace(raw.SenderNick)
+	if displayName == "" {
+		displayName = userID
+	}
+	attributes := map[string]string{"user_id": userID}
+	if senderID := strings.TrimSpace(raw.SenderID); senderID != "" {
+		attributes["sender_id"] = senderID
+	}
+	return channel.InboundMessage{
+		Channel:     Type,
+		Message:     message,
+		BotID:       cfg.BotID,
+		ReplyTarget: replyTarget,
+		Sender: channel.Identity{
+			SubjectID:   userID,
+			DisplayName: displayName,
+			Attributes:  attributes,
+		},
+		Conversation: channel.Conversation{
+			ID:   conversationID,
+			Type: chatType,
+			Name: strings.TrimSpace(raw.ConversationTitle),
+		},
+		ReceivedAt: receivedAt,
+		Source:     "dingtalk",
+		Metadata: map[string]any{
+			"is_mentioned": raw.IsInAtList,
+			"msg_type":     msgType,
+			"robot_code":   strings.TrimSpace(raw.RobotCode),
+		},
+	}, true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package dingtalk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+type webhookConfigStore interface {
+	ListConfigsByType(ctx context.Context, channelType channel.ChannelType) ([]channel.ChannelConfig, error)
+}
+
+type webhookInboundManager interface {
+	HandleInbound(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error
+}
+
+const webhookMaxBodyBytes int64 = 1 << 20 // 1 MiB
+
+// WebhookHandler receives DingTalk robot message callbacks and encrypted event subscription callbacks.
+type WebhookHandler struct {
+	logger  *slog.Logger
+	store   webhookConfigStore
+	manager webhookInboundManager
+	adapter *DingTalkAdapter
+	now     func() time.Time
+}
+
+// NewWebhookHandler creates a public callback handler for DingTalk robots.
+func NewWebhookHandler(log *slog.Logger, store webhookConfigStore, manager webhookInboundManager) *WebhookHandler {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WebhookHandler{
+		logger:  log.With(slog.String("handler", "dingtalk_callback")),
+		store:   store,
+		manager: manager,
+		adapter: NewDingTalkAdapter(log),
+		now:     time.Now,
+	}
+}
+
+// NewWebhookServerHandler is a DI-friendly constructor for fx/dig, using concrete
+// channel types as parameters.
+func NewWebhookServerHandler(log *slog.Logger, store *channel.Store, manager *channel.Manager) *WebhookHandler {
+	return NewWebhookHandler(log, store, manager)
+}
+
+// Register registers callback routes.
+func (h *WebhookHandler) Register(e *echo.Echo) {
+	e.POST("/channels/dingtalk/callback/:config_id", h.Handle)
+}
+
+// Handle dispatches a callback: bodies carrying an "encrypt" field are event subscription
+// callbacks, anything else is a robot message signed with the app secret.
+func (h *WebhookHandler) Handle(c echo.Context) error {
+	if h.store == nil || h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "dingtalk callback dependencies not configured")
+	}
+	configID := strings.TrimSpace(c.Param("config_id"))
+	if configID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "config id is required")
+	}
+	cfg, err := h.findConfigByID(c.Request().Context(), configID)
+	if err != nil {
+		return err
+	}
+	if cfg.Disabled {
+		return echo.NewHTTPError(http.StatusForbidden, "channel config is disabled")
+	}
+	dingCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, webhookMaxBodyBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("read body: %v", err))
+	}
+	if int64(len(body)) > webhookMaxBodyBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("payload too large: max %d bytes", webhookMaxBodyBytes))
+	}
+	var envelope struct {
+		Encrypt string `json:"encrypt"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid dingtalk callback payload: %v", err))
+	}
+	if strings.TrimSpace(envelope.Encrypt) != "" {
+		return h.handleEvent(c, cfg, dingCfg, envelope.Encrypt)
+	}
+	return h.handleRobotMessage(c, cfg, dingCfg, body)
+}
+
+func (h *WebhookHandler) handleRobotMessage(c echo.Context, cfg channel.ChannelConfig, dingCfg Config, body []byte) error {
+	header := c.Request().Header
+	if err := verifyRobotSignature(dingCfg.AppSecret, header.Get("timestamp"), header.Get("sign"), h.now()); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	var raw robotMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid dingtalk robot message: %v", err))
+	}
+	if h.adapter.isDuplicateInbound(cfg.ID, raw.MsgID) {
+		return c.JSON(http.StatusOK, map[string]any{})
+	}
+	msg, ok := buildInboundMessage(cfg, raw, h.now())
+	if !ok {
+		if h.logger != nil {
+			h.logger.Debug("robot message ignored",
+				slog.String("config_id", cfg.ID),
+				slog.String("msg_type", raw.MsgType),
+			)
+		}
+		return c.JSON(http.StatusOK, map[string]any{})
+	}
+	if h.logger != nil {
+		h.logger.Info(
+			"inbound received",
+			slog.String("config_id", cfg.ID),
+			slog.String("message_id", msg.Message.ID),
+			slog.String("msg_type", raw.MsgType),
+			slog.String("chat_type", msg.Conversation.Type),
+			slog.String("user_id", msg.Sender.SubjectID),
+			slog.String("text", common.SummarizeText(msg.Message.Text)),
+			slog.Int("attachments", len(msg.Message.Attachments)),
+		)
+	}
+	if err := h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg); err != nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	return c.JSON(http.StatusOK, map[string]any{})
+}
+
+// handleEvent verifies and decrypts an event subscription callback and answers with the
+// encrypted "success" acknowledgement DingTalk expects, including for the check_url probe.
+func (h *WebhookHandler) handleEvent(c echo.Context, cfg channel.ChannelConfig, dingCfg Config, encrypted string) error {
+	if dingCfg.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "dingtalk token and aesKey are required for event callbacks")
+	}
+	crypto, err := dingCfg.crypto()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	signature := c.QueryParam("msg_signature")
+	if signature == "" {
+		signature = c.QueryParam("signature")
+	}
+	timestamp := c.QueryParam("timestamp")
+	nonce := c.QueryParam("nonce")
+	if err := crypto.Verify(signature, timestamp, nonce, encrypted); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	plain, err := crypto.Decrypt(encrypted)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	var event struct {
+		EventType string `json:"EventType"`
+	}
+	_ = json.Unmarshal(plain, &event)
+	if h.logger != nil {
+		h.logger.Info("event callback received", slog.String("config_id", cfg.ID), slog.String("event_type", event.EventType))
+	}
+	reply, err := crypto.Encrypt([]byte("success"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	replyTimestamp := strconv.FormatInt(h.now().UnixMilli(), 10)
+	return c.JSON(http.StatusOK, map[string]string{
+		"msg_signature": crypto.Signature(replyTimestamp, nonce, reply),
+		"timeStamp":     replyTimestamp,
+		"nonce":         nonce,
+		"encrypt":       reply,
+	})
+}
+
+func (h *WebhookHandler) findConfigByID(ctx context.Context, configID string) (channel.ChannelConfig, error) {
+	items, err := h.store.ListConfigsByType(ctx, Type)
+	if err != nil {
+		return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.ID) == configID {
+			return item, nil
+		}
+	}
+	return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusNotFound, "channel config not found")
+}
@@ -0,0 +1,163 @@
+package dingtalk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+var testNow = time.Unix(1_700_000_000, 0)
+
+type fakeStore struct {
+	configs []channel.ChannelConfig
+}
+
+func (s *fakeStore) ListConfigsByType(context.Context, channel.ChannelType) ([]channel.ChannelConfig, error) {
+	return s.configs, nil
+}
+
+type fakeManager struct {
+	mu   sync.Mutex
+	msgs []channel.InboundMessage
+}
+
+func (m *fakeManager) HandleInbound(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.msgs = append(m.msgs, msg)
+	return nil
+}
+
+func newTestHandler() (*WebhookHandler, *fakeManager) {
+	manager := &fakeManager{}
+	store := &fakeStore{configs: []channel.ChannelConfig{{ID: "cfg-1", BotID: "bot-1", ChannelType: Type, Credentials: validCredentials()}}}
+	h := NewWebhookHandler(nil, store, manager)
+	h.now = func() time.Time { return testNow }
+	return h, manager
+}
+
+func serve(t *testing.T, h *WebhookHandler, target string, header http.Header, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("config_id")
+	c.SetParamValues("cfg-1")
+	if err := h.Handle(c); err != nil {
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			rec.Code = he.Code
+			return rec
+		}
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rec
+}
+
+func signedHeader(secret string, at time.Time) http.Header {
+	ts := strconv.FormatInt(at.UnixMilli(), 10)
+	header := http.Header{}
+	header.Set("timestamp", ts)
+	header.Set("sign", robotSign(secret, ts))
+	return header
+}
+
+func TestWebhookHandlerRobotMessage(t *testing.T) {
+	t.Parallel()
+
+	h, manager := newTestHandler()
+	body := `{"msgId":"msg-1","msgtype":"text","createAt":1700000000000,"conversationId":"cidAbC123==","conversationType":"2",` +
+		`"conversationTitle":"Ops","senderId":"$:LWCP_v1:$abc","senderStaffId":"manager4220","senderNick":"Zhang San",` +
+		`"isInAtList":true,"robotCode":"ding-app","text":{"content":" hello "}}`
+
+	for i := 0; i < 2; i++ {
+		rec := serve(t, h, "/channels/dingtalk/callback/cfg-1", signedHeader("app-secret", testNow), body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+	}
+	if len(manager.msgs) != 1 {
+		t.Fatalf("expected retried callback to be deduplicated, got %d messages", len(manager.msgs))
+	}
+	msg := manager.msgs[0]
+	if msg.Message.Text != "hello" || msg.ReplyTarget != "group:cidAbC123==" || msg.Conversation.Type != "group" || msg.Sender.SubjectID != "manager4220" {
+		t.Fatalf("unexpected inbound: %#v", msg)
+	}
+	if msg.Metadata["is_mentioned"] != true {
+		t.Fatalf("expected is_mentioned metadata, got %#v", msg.Metadata)
+	}
+}
+
+func TestWebhookHandlerRejectsBadSignature(t *testing.T) {
+	t.Parallel()
+
+	h, manager := newTestHandler()
+	body := `{"msgId":"msg-2","msgtype":"text","conversationId":"cid1","conversationType":"1","senderStaffId":"u1","text":{"content":"hi"}}`
+	cases := []http.Header{
+		{},
+		signedHeader("wrong-secret", testNow),
+		signedHeader("app-secret", testNow.Add(-2*time.Hour)),
+	}
+	for i, header := range cases {
+		if rec := serve(t, h, "/channels/dingtalk/callback/cfg-1", header, body); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("case %d: expected 401, got %d", i, rec.Code)
+		}
+	}
+	if len(manager.msgs) != 0 {
+		t.Fatalf("expected no inbound messages, got %d", len(manager.msgs))
+	}
+}
+
+func TestWebhookHandlerEventCallback(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newTestHandler()
+	cfg, _ := parseConfig(validCredentials())
+	crypto, _ := cfg.crypto()
+	encrypted, err := crypto.Encrypt([]byte(`{"EventType":"check_url"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{}
+	query.Set("timestamp", "1700000000000")
+	query.Set("nonce", "n1")
+	query.Set("signature", crypto.Signature("1700000000000", "n1", encrypted))
+	body, _ := json.Marshal(map[string]string{"encrypt": encrypted})
+
+	rec := serve(t, h, "/channels/dingtalk/callback/cfg-1?"+query.Encode(), nil, string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+	var reply map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("decode reply: %v", err)
+	}
+	if err := crypto.Verify(reply["msg_signature"], reply["timeStamp"], reply["nonce"], reply["encrypt"]); err != nil {
+		t.Fatalf("reply signature: %v", err)
+	}
+	plain, err := crypto.Decrypt(reply["encrypt"])
+	if err != nil || string(plain) != "success" {
+		t.Fatalf("unexpected reply payload %q err=%v", plain, err)
+	}
+
+	query.Set("signature", "bad")
+	if rec := serve(t, h, "/channels/dingtalk/callback/cfg-1?"+query.Encode(), nil, string(body)); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	// Discord limits for message components.
+	discordMaxCustomID      = 100
+	discordMaxButtonLabel   = 80
+	discordMaxButtonsPerRow = 5
+	discordMaxActionRows    = 5
+)
+
+// buildDiscordComponents renders message actions as rows of buttons. Link actions become link
+// buttons; all other actions report their value as the button custom ID.
+func buildDiscordComponents(actions []channel.Action) []discordgo.MessageComponent {
+	buttons := make([]discordgo.MessageComponent, 0, len(actions))
+	for _, action := range actions {
+		label := strings.TrimSpace(action.Label)
+		link := strings.TrimSpace(action.URL)
+		value := strings.TrimSpace(action.Value)
+		if label == "" {
+			label = value
+		}
+		if label == "" {
+			label = link
+		}
+		if label == "" {
+			continue
+		}
+		label = truncateDiscordRunes(label, discordMaxButtonLabel)
+		if link != "" {
+			buttons = append(buttons, discordgo.Button{Label: label, Style: discordgo.LinkButton, URL: link})
+			continue
+		}
+		if value == "" {
+			value = label
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    label,
+			Style:    discordgo.PrimaryButton,
+			CustomID: truncateDiscordRunes(value, discordMaxCustomID),
+		})
+	}
+	if len(buttons) == 0 {
+		return nil
+	}
+	rows := make([]discordgo.MessageComponent, 0, (len(buttons)+discordMaxButtonsPerRow-1)/discordMaxButtonsPerRow)
+	for start := 0; start < len(buttons) && len(rows) < discordMaxActionRows; start += discordMaxButtonsPerRow {
+		end := min(start+discordMaxButtonsPerRow, len(buttons))
+		rows = append(rows, discordgo.ActionsRow{Components: buttons[start:end]})
+	}
+	return rows
+}
+
+// truncateDiscordRunes cuts a string to at most limit bytes without splitting a rune.
+func truncateDiscordRunes(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+// discordButtonLabel finds the label of the pressed button among the components of the message
+// that carried it.
+func discordButtonLabel(msg *discordgo.Message, customID string) string {
+	if msg == nil {
+		return ""
+	}
+	for _, component := range msg.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, inner := range row.Components {
+			if button, ok := inner.(*discordgo.Button); ok && button.CustomID == customID {
+				return strings.TrimSpace(button.Label)
+			}
+		}
+	}
+	return ""
+}
+
+// buildDiscordInteractionInboundMessage maps a button press on a bot message to an action event.
+func buildDiscordInteractionInboundMessage(cfg channel.ChannelConfig, i *discordgo.Interaction) (channel.InboundMessage, bool) {
+	if i == nil || i.Type != discordgo.InteractionMessageComponent || i.Message == nil {
+		return channel.InboundMessage{}, false
+	}
+	data, ok := i.Data.(discordgo.MessageComponentInteractionData)
+	if !ok {
+		return channel.InboundMessage{}, false
+	}
+	customID := strings.TrimSpace(data.CustomID)
+	if customID == "" {
+		return channel.InboundMessage{}, false
+	}
+	user := i.User
+	if i.Member != nil && i.Member.User != nil {
+		user = i.Member.User
+	}
+	if user == nil || user.Bot {
+		return channel.InboundMessage{}, false
+	}
+	chatType := "direct"
+	if i.GuildID != "" {
+		chatType = "guild"
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Event:   channel.InboundEventAction,
+		Message: channel.Message{ID: "interaction:" + i.ID},
+		Action: &channel.ActionEvent{
+			Value:     customID,
+			Label:     discordButtonLabel(i.Message, data.CustomID),
+			MessageID: i.Message.ID,
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: i.ChannelID,
+		Sender: channel.Identity{
+			SubjectID:   user.ID,
+			DisplayName: user.Username,
+			Attributes: map[string]string{
+				"user_id":  user.ID,
+				"username": user.Username,
+			},
+		},
+		Conversation: channel.Conversation{
+			ID:   i.ChannelID,
+			Type: chatType,
+		},
+		ReceivedAt: time.Now().UTC(),
+		Source:     "discord",
+		Metadata: map[string]any{
+			"guild_id":       i.GuildID,
+			"interaction_id": i.ID,
+		},
+	}, true
+}
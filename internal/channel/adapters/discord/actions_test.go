@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestBuildDiscordComponents(t *testing.T) {
+	t.Parallel()
+
+	actions := []channel.Action{
+		{Label: "Docs", URL: "https://example.com"},
+		{Value: strings.Repeat("v", 120)},
+	}
+	for i := 0; i < 5; i++ {
+		actions = append(actions, channel.Action{Label: "Option", Value: "opt"})
+	}
+	rows := buildDiscordComponents(actions)
+	if len(rows) != 2 {
+		t.Fatalf("expected two rows, got %d", len(rows))
+	}
+	first, ok := rows[0].(discordgo.ActionsRow)
+	if !ok || len(first.Components) != discordMaxButtonsPerRow {
+		t.Fatalf("unexpected first row: %#v", rows[0])
+	}
+	link := first.Components[0].(discordgo.Button)
+	if link.Style != discordgo.LinkButton || link.URL != "https://example.com" || link.CustomID != "" {
+		t.Fatalf("unexpected link button: %#v", link)
+	}
+	long := first.Components[1].(discordgo.Button)
+	if len(long.CustomID) != discordMaxCustomID || len(long.Label) != discordMaxButtonLabel {
+		t.Fatalf("expected truncated custom id and label, got %d/%d", len(long.CustomID), len(long.Label))
+	}
+	if buildDiscordComponents(nil) != nil {
+		t.Fatal("expected no components without actions")
+	}
+}
+
+func TestBuildDiscordInteractionInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	// Round-trip through JSON so the message components use the gateway representation.
+	raw := `{"id":"int-1","type":3,"guild_id":"g1","channel_id":"c1",` +
+		`"member":{"user":{"id":"u1","username":"alice"}},` +
+		`"message":{"id":"m1","channel_id":"c1","components":[{"type":1,"components":[{"type":2,"style":1,"label":"Approve","custom_id":"approve"}]}]},` +
+		`"data":{"custom_id":"approve","component_type":2}}`
+	var interaction discordgo.Interaction
+	if err := json.Unmarshal([]byte(raw), &interaction); err != nil {
+		t.Fatalf("decode interaction: %v", err)
+	}
+	msg, ok := buildDiscordInteractionInboundMessage(channel.ChannelConfig{BotID: "bot-1"}, &interaction)
+	if !ok {
+		t.Fatal("expected interaction to be accepted")
+	}
+	if !msg.IsAction() || msg.Action.Value != "approve" || msg.Action.Label != "Approve" || msg.Action.MessageID != "m1" {
+		t.Fatalf("unexpected action: %#v", msg.Action)
+	}
+	if msg.ReplyTarget != "c1" || msg.Conversation.Type != "guild" || msg.Sender.SubjectID != "u1" || msg.Message.ID != "interaction:int-1" {
+		t.Fatalf("unexpected inbound: %#v", msg)
+	}
+
+	interaction.Type = discordgo.InteractionApplicationCommand
+	if _, ok := buildDiscordInteractionInboundMessage(channel.ChannelConfig{}, &interaction); ok {
+		t.Fatal("expected non-component interaction to be ignored")
+	}
+}
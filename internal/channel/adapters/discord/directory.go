@@ -0,0 +1,334 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	defaultDirectoryLimit = 50
+	maxDirectoryLimit     = 200
+	// Discord returns at most 1000 members per page and 200 guilds per page.
+	discordMaxMembersPage = 1000
+	discordMaxGuildsPage  = 200
+)
+
+func directoryLimit(n int) int {
+	if n <= 0 {
+		return defaultDirectoryLimit
+	}
+	if n > maxDirectoryLimit {
+		return maxDirectoryLimit
+	}
+	return n
+}
+
+// ListPeers returns members of the guilds the bot is in, skipping bots. Members present in several
+// guilds are listed once.
+func (a *DiscordAdapter) ListPeers(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	session, err := a.directorySession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	guilds, err := session.UserGuilds(discordMaxGuildsPage, "", "", false)
+	if err != nil {
+		return nil, fmt.Errorf("discord list guilds: %w", err)
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	seen := make(map[string]struct{})
+	for _, g := range guilds {
+		if len(entries) >= limit || ctx.Err() != nil {
+			break
+		}
+		members, err := listDiscordGuildMembers(session, g.ID, query.Query, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if len(entries) >= limit {
+				break
+			}
+			if m.User == nil || m.User.Bot {
+				continue
+			}
+			if _, ok := seen[m.User.ID]; ok {
+				continue
+			}
+			seen[m.User.ID] = struct{}{}
+			entries = append(entries, discordMemberToEntry(m, g.ID))
+		}
+	}
+	return entries, nil
+}
+
+// ListGroups returns the text, announcement and forum channels of every guild the bot is in,
+// followed by the guild's active threads.
+func (a *DiscordAdapter) ListGroups(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	session, err := a.directorySession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	guilds, err := session.UserGuilds(discordMaxGuildsPage, "", "", false)
+	if err != nil {
+		return nil, fmt.Errorf("discord list guilds: %w", err)
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	add := func(ch *discordgo.Channel, guildName string) {
+		if len(entries) >= limit || !isDiscordDirectoryChannel(ch) {
+			return
+		}
+		e := discordChannelToEntry(ch, guildName)
+		if query.Query != "" && !strings.Contains(strings.ToLower(e.Name), strings.ToLower(query.Query)) {
+			return
+		}
+		entries = append(entries, e)
+	}
+	for _, g := range guilds {
+		if len(entries) >= limit || ctx.Err() != nil {
+			break
+		}
+		channels, err := session.GuildChannels(g.ID)
+		if err != nil {
+			return nil, fmt.Errorf("discord list guild channels: %w", err)
+		}
+		for _, ch := range channels {
+			add(ch, g.Name)
+		}
+		threads, err := session.GuildThreadsActive(g.ID)
+		if err != nil {
+			return nil, fmt.Errorf("discord list active threads: %w", err)
+		}
+		for _, ch := range threads.Threads {
+			add(ch, g.Name)
+		}
+	}
+	return entries, nil
+}
+
+// ListGroupMembers returns members of a guild. groupID may be a guild ID or the ID of one of its
+// channels; for direct message channels the recipients are returned.
+func (a *DiscordAdapter) ListGroupMembers(ctx context.Context, cfg channel.ChannelConfig, groupID string, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	session, err := a.directorySession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	groupID = parseDiscordChannelInput(groupID)
+	if groupID == "" {
+		return nil, fmt.Errorf("discord list group members: group id is required")
+	}
+	limit := directoryLimit(query.Limit)
+	guildID := groupID
+	if ch, err := discordChannelInfo(session, groupID); err == nil {
+		if ch.GuildID == "" {
+			entries := make([]channel.DirectoryEntry, 0, len(ch.Recipients))
+			for _, u := range ch.Recipients {
+				if len(entries) >= limit {
+					break
+				}
+				e := discordUserToEntry(u)
+				if query.Query != "" && !strings.Contains(strings.ToLower(e.Name+e.Handle), strings.ToLower(query.Query)) {
+					continue
+				}
+				entries = append(entries, e)
+			}
+			return entries, nil
+		}
+		guildID = ch.GuildID
+	}
+	members, err := listDiscordGuildMembers(session, guildID, query.Query, limit)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]channel.DirectoryEntry, 0, len(members))
+	for _, m := range members {
+		if m.User == nil {
+			continue
+		}
+		entries = append(entries, discordMemberToEntry(m, guildID))
+	}
+	return entries, nil
+}
+
+// ResolveEntry resolves a user ID (or <@id> mention) or a channel ID (or <#id> mention).
+func (a *DiscordAdapter) ResolveEntry(ctx context.Context, cfg channel.ChannelConfig, input string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	session, err := a.directorySession(cfg)
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	switch kind {
+	case channel.DirectoryEntryUser:
+		userID := parseDiscordUserInput(input)
+		if userID == "" {
+			return channel.DirectoryEntry{}, fmt.Errorf("discord resolve entry user: invalid input %q", input)
+		}
+		u, err := session.User(userID)
+		if err != nil {
+			return channel.DirectoryEntry{}, fmt.Errorf("discord get user: %w", err)
+		}
+		return discordUserToEntry(u), nil
+	case channel.DirectoryEntryGroup:
+		channelID := parseDiscordChannelInput(input)
+		if channelID == "" {
+			return channel.DirectoryEntry{}, fmt.Errorf("discord resolve entry group: invalid input %q", input)
+		}
+		ch, err := discordChannelInfo(session, channelID)
+		if err != nil {
+			return channel.DirectoryEntry{}, err
+		}
+		guildName := ""
+		if ch.GuildID != "" {
+			if g, err := session.State.Guild(ch.GuildID); err == nil {
+				guildName = g.Name
+			}
+		}
+		return discordChannelToEntry(ch, guildName), nil
+	default:
+		return channel.DirectoryEntry{}, fmt.Errorf("discord resolve entry: unsupported kind %q", kind)
+	}
+}
+
+func (a *DiscordAdapter) directorySession(cfg channel.ChannelConfig) (*discordgo.Session, error) {
+	discordCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	return a.getOrCreateSession(discordCfg.BotToken, cfg.ID)
+}
+
+// listDiscordGuildMembers searches members by name prefix when a query is given and lists the
+// first page of members otherwise.
+func listDiscordGuildMembers(session *discordgo.Session, guildID, query string, limit int) ([]*discordgo.Member, error) {
+	query = strings.TrimSpace(query)
+	if limit > discordMaxMembersPage {
+		limit = discordMaxMembersPage
+	}
+	if query != "" {
+		members, err := session.GuildMembersSearch(guildID, query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("discord search guild members: %w", err)
+		}
+		return members, nil
+	}
+	members, err := session.GuildMembers(guildID, "", limit)
+	if err != nil {
+		return nil, fmt.Errorf("discord list guild members: %w", err)
+	}
+	return members, nil
+}
+
+// isDiscordDirectoryChannel reports whether a channel can receive messages from the bot.
+func isDiscordDirectoryChannel(ch *discordgo.Channel) bool {
+	if ch == nil {
+		return false
+	}
+	switch ch.Type {
+	case discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews,
+		discordgo.ChannelTypeGuildForum, discordgo.ChannelTypeGuildMedia:
+		return true
+	}
+	return ch.IsThread()
+}
+
+// parseDiscordUserInput accepts a user ID or a <@id> / <@!id> mention.
+func parseDiscordUserInput(input string) string {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "<@") && strings.HasSuffix(input, ">") {
+		input = strings.TrimPrefix(strings.TrimSuffix(input[2:], ">"), "!")
+	}
+	return discordSnowflake(input)
+}
+
+// parseDiscordChannelInput accepts a channel ID or a <#id> mention.
+func parseDiscordChannelInput(input string) string {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "<#") && strings.HasSuffix(input, ">") {
+		input = strings.TrimSuffix(input[2:], ">")
+	}
+	return discordSnowflake(input)
+}
+
+// discordSnowflake returns input if it is a numeric Discord ID.
+func discordSnowflake(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return ""
+	}
+	for _, r := range input {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return input
+}
+
+func discordUserToEntry(u *discordgo.User) channel.DirectoryEntry {
+	if u == nil {
+		return channel.DirectoryEntry{Kind: channel.DirectoryEntryUser}
+	}
+	meta := map[string]any{"user_id": u.ID, "username": u.Username}
+	if u.Bot {
+		meta["bot"] = true
+	}
+	return channel.DirectoryEntry{
+		Kind:      channel.DirectoryEntryUser,
+		ID:        u.ID,
+		Name:      u.DisplayName(),
+		Handle:    "@" + u.Username,
+		AvatarURL: u.AvatarURL(""),
+		Metadata:  meta,
+	}
+}
+
+func discordMemberToEntry(m *discordgo.Member, guildID string) channel.DirectoryEntry {
+	e := discordUserToEntry(m.User)
+	if nick := strings.TrimSpace(m.Nick); nick != "" {
+		e.Name = nick
+	}
+	if guildID != "" {
+		e.Metadata["guild_id"] = guildID
+	}
+	return e
+}
+
+func discordChannelToEntry(ch *discordgo.Channel, guildName string) channel.DirectoryEntry {
+	kind := "text"
+	switch {
+	case ch.IsThread():
+		kind = "thread"
+	case isDiscordForum(ch):
+		kind = "forum"
+	case ch.Type == discordgo.ChannelTypeGuildNews:
+		kind = "news"
+	case ch.Type == discordgo.ChannelTypeDM || ch.Type == discordgo.ChannelTypeGroupDM:
+		kind = "direct"
+	}
+	name := strings.TrimSpace(ch.Name)
+	handle := ""
+	if name != "" && ch.GuildID != "" && !ch.IsThread() {
+		handle = "#" + name
+	}
+	meta := map[string]any{"channel_id": ch.ID, "type": kind}
+	if ch.GuildID != "" {
+		meta["guild_id"] = ch.GuildID
+	}
+	if guildName != "" {
+		meta["guild_name"] = guildName
+	}
+	if ch.ParentID != "" {
+		meta["parent_id"] = ch.ParentID
+	}
+	return channel.DirectoryEntry{
+		Kind:     channel.DirectoryEntryGroup,
+		ID:       ch.ID,
+		Name:     name,
+		Handle:   handle,
+		Metadata: meta,
+	}
+}
@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestParseDiscordDirectoryInput(t *testing.T) {
+	t.Parallel()
+
+	users := map[string]string{
+		"123":     "123",
+		"<@123>":  "123",
+		"<@!123>": "123",
+		" 456 ":   "456",
+		"@alice":  "",
+		"<#123>":  "",
+		"12a":     "",
+		"":        "",
+	}
+	for input, want := range users {
+		if got := parseDiscordUserInput(input); got != want {
+			t.Errorf("parseDiscordUserInput(%q) = %q, want %q", input, got, want)
+		}
+	}
+	channels := map[string]string{
+		"789":    "789",
+		"<#789>": "789",
+		"<@789>": "",
+		"#news":  "",
+	}
+	for input, want := range channels {
+		if got := parseDiscordChannelInput(input); got != want {
+			t.Errorf("parseDiscordChannelInput(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDiscordDirectoryEntries(t *testing.T) {
+	t.Parallel()
+
+	member := &discordgo.Member{
+		Nick: "Ali",
+		User: &discordgo.User{ID: "u-1", Username: "alice", GlobalName: "Alice"},
+	}
+	e := discordMemberToEntry(member, "g-1")
+	if e.Kind != channel.DirectoryEntryUser || e.ID != "u-1" || e.Name != "Ali" || e.Handle != "@alice" {
+		t.Fatalf("unexpected member entry: %+v", e)
+	}
+	if e.Metadata["guild_id"] != "g-1" {
+		t.Fatalf("expected guild_id metadata: %+v", e.Metadata)
+	}
+
+	forum := &discordgo.Channel{ID: "c-1", GuildID: "g-1", Name: "ideas", Type: discordgo.ChannelTypeGuildForum}
+	e = discordChannelToEntry(forum, "Guild")
+	if e.Kind != channel.DirectoryEntryGroup || e.Handle != "#ideas" || e.Metadata["type"] != "forum" || e.Metadata["guild_name"] != "Guild" {
+		t.Fatalf("unexpected forum entry: %+v", e)
+	}
+
+	thread := &discordgo.Channel{ID: "t-1", GuildID: "g-1", ParentID: "c-1", Name: "Post", Type: discordgo.ChannelTypeGuildPublicThread}
+	e = discordChannelToEntry(thread, "")
+	if e.Handle != "" || e.Metadata["type"] != "thread" || e.Metadata["parent_id"] != "c-1" {
+		t.Fatalf("unexpected thread entry: %+v", e)
+	}
+
+	if isDiscordDirectoryChannel(&discordgo.Channel{Type: discordgo.ChannelTypeGuildVoice}) {
+		t.Fatal("voice channels must not be listed")
+	}
+	if !isDiscordDirectoryChannel(thread) {
+		t.Fatal("threads must be listed")
+	}
+}
@@ -31,6 +31,7 @@ type DiscordAdapter struct {
 	sessions        map[string]*discordgo.Session // keyed by bot token
 	handlerRemovers map[string]func()             // keyed by bot token
 	seenMessages    map[string]time.Time          // keyed by token:messageID
+	openedThreads   map[string]openedThread       // keyed by token:target:name
 	assets          assetOpener
 }
 
@@ -43,6 +44,7 @@ func NewDiscordAdapter(log *slog.Logger) *DiscordAdapter {
 		sessions:        make(map[string]*discordgo.Session),
 		handlerRemovers: make(map[string]func()),
 		seenMessages:    make(map[string]time.Time),
+		openedThreads:   make(map[string]openedThread),
 	}
 }
 
@@ -70,6 +72,16 @@ func (a *DiscordAdapter) Descriptor() channel.Descriptor {
 			Streaming:      true,
 			BlockStreaming: true,
 			Reactions:      true,
+			Buttons:        true,
+			Edit:           true,
+			Unsend:         true,
+			Threads:        true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			// Discord allows 5 messages per 5 seconds per channel; discordgo also
+			// waits out 429 responses on its own.
+			RateLimitPerSecond: 1,
+			RateLimitBurst:     5,
 		},
 		ConfigSchema: channel.ConfigSchema{
 			Version: 1,
@@ -201,6 +213,15 @@ func (a *DiscordAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig,
 				"is_reply_to_bot": isReplyToBot,
 			},
 		}
+		if m.MessageReference != nil && m.MessageReference.MessageID != "" {
+			msg.Message.Reply = &channel.ReplyRef{Target: m.ChannelID, MessageID: m.MessageReference.MessageID}
+		}
+		if m.GuildID != "" {
+			if ch, err := discordChannelInfo(s, m.ChannelID); err == nil && ch.IsThread() {
+				parent, _ := discordChannelInfo(s, ch.ParentID)
+				applyDiscordThread(&msg, ch, parent)
+			}
+		}
 
 		if a.logger != nil {
 			a.logger.Info("inbound received",
@@ -219,7 +240,68 @@ func (a *DiscordAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig,
 		}()
 	})
 
-	a.swapHandlerRemover(discordCfg.BotToken, remove)
+	removeInteraction := session.AddHandler(func(s *discordgo.Session, ic *discordgo.InteractionCreate) {
+		if ctx.Err() != nil || ic.Interaction == nil {
+			return
+		}
+		msg, ok := buildDiscordInteractionInboundMessage(cfg, ic.Interaction)
+		if !ok {
+			return
+		}
+		// Acknowledge within Discord's three second window; the reply is sent as a new message.
+		if err := s.InteractionRespond(ic.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+		}); err != nil && a.logger != nil {
+			a.logger.Warn("acknowledge interaction failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		if a.isDuplicateInbound(discordCfg.BotToken, msg.Message.ID) {
+			return
+		}
+		if a.logger != nil {
+			a.logger.Info("action received",
+				slog.String("config_id", cfg.ID),
+				slog.String("user_id", msg.Sender.SubjectID),
+				slog.String("value", common.SummarizeText(msg.Action.Value)),
+			)
+		}
+		go func() {
+			if err := handler(ctx, cfg, msg); err != nil && a.logger != nil {
+				a.logger.Error("handle action failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+			}
+		}()
+	})
+
+	dispatchHistory := func(msg channel.InboundMessage, ok bool) {
+		if !ok || ctx.Err() != nil {
+			return
+		}
+		go func() {
+			if err := handler(ctx, cfg, msg); err != nil && a.logger != nil {
+				a.logger.Error("handle history update failed", slog.String("config_id", cfg.ID), slog.String("event", string(msg.Event)), slog.Any("error", err))
+			}
+		}()
+	}
+	removeUpdate := session.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageUpdate) {
+		dispatchHistory(buildDiscordEditedInboundMessage(cfg, m))
+	})
+	removeDelete := session.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageDelete) {
+		dispatchHistory(buildDiscordDeletedInboundMessage(cfg, m))
+	})
+	removeReactionAdd := session.AddHandler(func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+		dispatchHistory(buildDiscordReactionInboundMessage(cfg, r.MessageReaction, r.Member, s.State.User.ID, false))
+	})
+	removeReactionRemove := session.AddHandler(func(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
+		dispatchHistory(buildDiscordReactionInboundMessage(cfg, r.MessageReaction, nil, s.State.User.ID, true))
+	})
+
+	a.swapHandlerRemover(discordCfg.BotToken, func() {
+		remove()
+		removeInteraction()
+		removeUpdate()
+		removeDelete()
+		removeReactionAdd()
+		removeReactionRemove()
+	})
 
 	if err := session.Open(); err != nil {
 		return nil, fmt.Errorf("discord open connection: %w", err)
@@ -254,6 +336,10 @@ func (a *DiscordAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, ms
 	if channelID == "" {
 		return fmt.Errorf("discord target is required")
 	}
+	channelID, msg.Message.Reply, err = a.resolveOutboundChannel(session, discordCfg.BotToken, channelID, msg.Message.Thread, msg.Message.Reply)
+	if err != nil {
+		return err
+	}
 
 	// Get botID from config metadata if available
 	botID := ""
@@ -265,44 +351,65 @@ func (a *DiscordAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, ms
 }
 
 func (a *DiscordAdapter) sendDiscordMessage(ctx context.Context, session *discordgo.Session, channelID, botID string, msg channel.OutboundMessage) error {
-	content := truncateDiscordText(msg.Message.Text)
-
-	// Build message send parameters
-	messageSend := &discordgo.MessageSend{
-		Content: content,
-	}
+	// Long replies are split into several messages; the first one carries the reply
+	// reference, the last one the buttons and attachments.
+	parts := renderDiscordMessages(msg.Message.Text, msg.Message.Format)
+	if len(parts) == 0 {
+		parts = []discordMessagePart{{}}
+	}
+	last := len(parts) - 1
+	for i, part := range parts {
+		messageSend := &discordgo.MessageSend{
+			Content: part.content,
+			Embeds:  part.embeds,
+		}
 
-	if msg.Message.Reply != nil && msg.Message.Reply.MessageID != "" {
-		messageSend.Reference = &discordgo.MessageReference{
-			ChannelID: channelID,
-			MessageID: msg.Message.Reply.MessageID,
+		if i == 0 && msg.Message.Reply != nil && msg.Message.Reply.MessageID != "" {
+			messageSend.Reference = &discordgo.MessageReference{
+				ChannelID: channelID,
+				MessageID: msg.Message.Reply.MessageID,
+			}
 		}
-	}
 
-	// Add attachments if present
-	if len(msg.Message.Attachments) > 0 {
-		files := make([]*discordgo.File, 0, len(msg.Message.Attachments))
-		for _, att := range msg.Message.Attachments {
-			file := discordAttachmentToFile(ctx, att, a.assets)
-			if file != nil {
-				files = append(files, file)
+		if i == last {
+			messageSend.Components = buildDiscordComponents(msg.Message.Actions)
+
+			// Add attachments if present
+			if len(msg.Message.Attachments) > 0 {
+				files := make([]*discordgo.File, 0, len(msg.Message.Attachments))
+				for _, att := range msg.Message.Attachments {
+					file := discordAttachmentToFile(ctx, att, a.assets)
+					if file != nil {
+						files = append(files, file)
+					}
+				}
+				messageSend.Files = files
+
+				// Discord requires non-empty content when sending files only
+				if messageSend.Content == "" && len(messageSend.Files) > 0 {
+					messageSend.Content = "\u200b"
+				}
+			}
+
+			if messageSend.Content == "" && len(messageSend.Components) > 0 {
+				messageSend.Content = "\u200b"
 			}
 		}
-		messageSend.Files = files
 
-		// Discord requires non-empty content when sending files only
-		if messageSend.Content == "" && len(messageSend.Files) > 0 {
-			messageSend.Content = "\u200b"
+		// Validate: must have content, embeds or files
+		if messageSend.Content == "" && len(messageSend.Embeds) == 0 && len(messageSend.Files) == 0 {
+			return fmt.Errorf("cannot send empty message: no content and no valid attachments")
 		}
-	}
 
-	// Validate: must have content or files
-	if messageSend.Content == "" && len(messageSend.Files) == 0 {
-		return fmt.Errorf("cannot send empty message: no content and no valid attachments")
+		sent, err := session.ChannelMessageSendComplex(channelID, messageSend)
+		if err != nil {
+			return err
+		}
+		if sent != nil {
+			channel.ReportSentMessage(ctx, channelID, sent.ID)
+		}
 	}
-
-	_, err := session.ChannelMessageSendComplex(channelID, messageSend)
-	return err
+	return nil
 }
 
 func truncateDiscordText(text string) string {
@@ -429,11 +536,16 @@ func (a *DiscordAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConf
 		return nil, err
 	}
 
+	target, reply, err := a.resolveOutboundChannel(session, discordCfg.BotToken, target, opts.Thread, opts.Reply)
+	if err != nil {
+		return nil, err
+	}
+
 	return &discordOutboundStream{
 		adapter: a,
 		cfg:     cfg,
 		target:  target,
-		reply:   opts.Reply,
+		reply:   reply,
 		session: session,
 	}, nil
 }
@@ -618,3 +730,38 @@ func (a *DiscordAdapter) clearSessionState(token string) func() {
 	delete(a.sessions, token)
 	return remove
 }
+
+// Update replaces the text of a message the bot sent earlier (implements channel.MessageEditor).
+func (a *DiscordAdapter) Update(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, msg channel.Message) error {
+	discordCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+
+	session, err := a.getOrCreateSession(discordCfg.BotToken, cfg.ID)
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(msg.PlainText())
+	if text == "" {
+		return fmt.Errorf("discord edit requires text")
+	}
+	_, err = session.ChannelMessageEdit(target, messageID, truncateDiscordText(text))
+	return err
+}
+
+// Unsend deletes a message the bot sent earlier (implements channel.MessageEditor).
+func (a *DiscordAdapter) Unsend(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string) error {
+	discordCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+
+	session, err := a.getOrCreateSession(discordCfg.BotToken, cfg.ID)
+	if err != nil {
+		return err
+	}
+
+	return session.ChannelMessageDelete(target, messageID)
+}
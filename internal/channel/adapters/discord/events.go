@@ -0,0 +1,103 @@
+package discord
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// buildDiscordEditedInboundMessage maps a user's message edit to a message_edited event.
+// Updates without an edit timestamp (such as link embeds being resolved) are ignored.
+func buildDiscordEditedInboundMessage(cfg channel.ChannelConfig, m *discordgo.MessageUpdate) (channel.InboundMessage, bool) {
+	if m == nil || m.Message == nil || m.Author == nil || m.Author.Bot || m.EditedTimestamp == nil {
+		return channel.InboundMessage{}, false
+	}
+	text := strings.TrimSpace(m.Content)
+	if text == "" {
+		return channel.InboundMessage{}, false
+	}
+	if m.BeforeUpdate != nil && strings.TrimSpace(m.BeforeUpdate.Content) == text {
+		return channel.InboundMessage{}, false
+	}
+	msg := discordHistoryEvent(cfg, channel.InboundEventMessageEdited, m.ChannelID, m.GuildID, m.Author.ID, m.Author.Username)
+	msg.Message = channel.Message{
+		ID:     m.ID,
+		Format: channel.MessageFormatPlain,
+		Text:   text,
+	}
+	msg.ReceivedAt = m.EditedTimestamp.UTC()
+	return msg, true
+}
+
+// buildDiscordDeletedInboundMessage maps a message deletion to a message_deleted event.
+// Discord does not report who deleted the message.
+func buildDiscordDeletedInboundMessage(cfg channel.ChannelConfig, m *discordgo.MessageDelete) (channel.InboundMessage, bool) {
+	if m == nil || m.Message == nil || strings.TrimSpace(m.ID) == "" {
+		return channel.InboundMessage{}, false
+	}
+	msg := discordHistoryEvent(cfg, channel.InboundEventMessageDeleted, m.ChannelID, m.GuildID, "", "")
+	msg.Message = channel.Message{ID: m.ID}
+	return msg, true
+}
+
+// buildDiscordReactionInboundMessage maps a reaction change to a reaction_added or
+// reaction_removed event. Reactions of the bot itself are ignored.
+func buildDiscordReactionInboundMessage(cfg channel.ChannelConfig, r *discordgo.MessageReaction, member *discordgo.Member, selfID string, removed bool) (channel.InboundMessage, bool) {
+	if r == nil || strings.TrimSpace(r.MessageID) == "" || strings.TrimSpace(r.UserID) == "" || r.UserID == selfID {
+		return channel.InboundMessage{}, false
+	}
+	emoji := r.Emoji.APIName()
+	if emoji == "" {
+		return channel.InboundMessage{}, false
+	}
+	username := ""
+	if member != nil && member.User != nil {
+		if member.User.Bot {
+			return channel.InboundMessage{}, false
+		}
+		username = member.User.Username
+	}
+	event := channel.InboundEventReactionAdded
+	if removed {
+		event = channel.InboundEventReactionRemoved
+	}
+	msg := discordHistoryEvent(cfg, event, r.ChannelID, r.GuildID, r.UserID, username)
+	msg.Reaction = &channel.ReactionEvent{Emoji: emoji, MessageID: r.MessageID}
+	return msg, true
+}
+
+func discordHistoryEvent(cfg channel.ChannelConfig, event channel.InboundEventType, channelID, guildID, userID, username string) channel.InboundMessage {
+	chatType := "direct"
+	if guildID != "" {
+		chatType = "guild"
+	}
+	var sender channel.Identity
+	if userID != "" {
+		sender = channel.Identity{
+			SubjectID:   userID,
+			DisplayName: username,
+			Attributes: map[string]string{
+				"user_id":  userID,
+				"username": username,
+			},
+		}
+	}
+	return channel.InboundMessage{
+		Channel:     Type,
+		Event:       event,
+		BotID:       cfg.BotID,
+		ReplyTarget: channelID,
+		Sender:      sender,
+		Conversation: channel.Conversation{
+			ID:   channelID,
+			Type: chatType,
+		},
+		ReceivedAt: time.Now().UTC(),
+		Source:     "discord",
+		Metadata: map[string]any{
+			"guild_id": guildID,
+		},
+	}
+}
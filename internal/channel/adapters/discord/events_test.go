@@ -0,0 +1,80 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestBuildDiscordEditedInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	cfg := channel.ChannelConfig{BotID: "bot-1"}
+	editedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	update := &discordgo.MessageUpdate{Message: &discordgo.Message{
+		ID:              "m-1",
+		ChannelID:       "c-1",
+		GuildID:         "g-1",
+		Content:         "new text",
+		Author:          &discordgo.User{ID: "u-1", Username: "alice"},
+		EditedTimestamp: &editedAt,
+	}}
+
+	msg, ok := buildDiscordEditedInboundMessage(cfg, update)
+	if !ok {
+		t.Fatal("expected edit event")
+	}
+	if msg.Event != channel.InboundEventMessageEdited || msg.Message.ID != "m-1" || msg.Message.Text != "new text" {
+		t.Fatalf("unexpected edit event: %+v", msg)
+	}
+	if msg.Sender.SubjectID != "u-1" || msg.Conversation.Type != "guild" || !msg.ReceivedAt.Equal(editedAt) {
+		t.Fatalf("unexpected edit metadata: %+v", msg)
+	}
+
+	update.EditedTimestamp = nil
+	if _, ok := buildDiscordEditedInboundMessage(cfg, update); ok {
+		t.Fatal("embed-only updates must be ignored")
+	}
+}
+
+func TestBuildDiscordReactionInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	cfg := channel.ChannelConfig{BotID: "bot-1"}
+	reaction := &discordgo.MessageReaction{
+		UserID:    "u-1",
+		MessageID: "m-1",
+		ChannelID: "c-1",
+		Emoji:     discordgo.Emoji{Name: "party", ID: "99"},
+	}
+
+	msg, ok := buildDiscordReactionInboundMessage(cfg, reaction, nil, "self", true)
+	if !ok {
+		t.Fatal("expected reaction event")
+	}
+	if msg.Event != channel.InboundEventReactionRemoved || msg.Reaction == nil {
+		t.Fatalf("unexpected reaction event: %+v", msg)
+	}
+	if msg.Reaction.Emoji != "party:99" || msg.Reaction.MessageID != "m-1" {
+		t.Fatalf("unexpected reaction: %+v", msg.Reaction)
+	}
+	if _, ok := buildDiscordReactionInboundMessage(cfg, reaction, nil, "u-1", false); ok {
+		t.Fatal("the bot's own reactions must be ignored")
+	}
+}
+
+func TestBuildDiscordDeletedInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	msg, ok := buildDiscordDeletedInboundMessage(channel.ChannelConfig{BotID: "bot-1"}, &discordgo.MessageDelete{
+		Message: &discordgo.Message{ID: "m-1", ChannelID: "c-1"},
+	})
+	if !ok || msg.Event != channel.InboundEventMessageDeleted || msg.Message.ID != "m-1" {
+		t.Fatalf("unexpected delete event: %+v", msg)
+	}
+	if msg.Sender.SubjectID != "" {
+		t.Fatalf("deletions carry no sender: %+v", msg.Sender)
+	}
+}
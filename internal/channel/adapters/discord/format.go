@@ -0,0 +1,48 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/render"
+)
+
+const discordMaxMessageLength = 2000
+
+// discordMessagePart is one Discord message of a rendered reply.
+type discordMessagePart struct {
+	content string
+	embeds  []*discordgo.MessageEmbed
+}
+
+// renderDiscordMessages renders reply text for Discord. Markdown goes through the
+// shared renderer, which moves small tables into embeds, and text over the message
+// limit is split into several messages without breaking code blocks. Embeds travel
+// with the last message.
+func renderDiscordMessages(text string, format channel.MessageFormat) []discordMessagePart {
+	var embeds []*discordgo.MessageEmbed
+	if format == channel.MessageFormatMarkdown {
+		rendered := render.Discord(render.Parse(text))
+		text = rendered.Content
+		for _, embed := range rendered.Embeds {
+			fields := make([]*discordgo.MessageEmbedField, 0, len(embed.Fields))
+			for _, field := range embed.Fields {
+				fields = append(fields, &discordgo.MessageEmbedField{Name: field.Name, Value: field.Value, Inline: field.Inline})
+			}
+			embeds = append(embeds, &discordgo.MessageEmbed{Fields: fields})
+		}
+	}
+	chunks := render.SplitMarkdown(text, discordMaxMessageLength)
+	if len(chunks) == 0 {
+		if len(embeds) == 0 {
+			return nil
+		}
+		chunks = []string{""}
+	}
+	parts := make([]discordMessagePart, len(chunks))
+	for i, chunk := range chunks {
+		parts[i].content = chunk
+	}
+	parts[len(parts)-1].embeds = embeds
+	return parts
+}
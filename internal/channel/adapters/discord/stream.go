@@ -21,6 +21,7 @@ type discordOutboundStream struct {
     closed      atomic.Bool
     mu          sync.Mutex
     msgID       string
+    reportedID  string
     buffer      strings.Builder
     lastUpdate  time.Time
 }
@@ -38,6 +39,7 @@ func (s *discordOutboundStream) Push(ctx context.Context, event channel.StreamEv
         return ctx.Err()
     default:
     }
+	defer s.reportMessage(ctx)
 
     switch event.Type {
     case channel.StreamEventStatus:
@@ -61,17 +63,19 @@ func (s *discordOutboundStream) Push(ctx context.Context, event channel.StreamEv
         return nil
 
     case channel.StreamEventFinal:
+        var components []discordgo.MessageComponent
         if event.Final != nil && !event.Final.Message.IsEmpty() {
+            components = buildDiscordComponents(event.Final.Message.Actions)
             finalText := strings.TrimSpace(event.Final.Message.PlainText())
             if finalText != "" {
-				return s.finalizeMessage(finalText)
+				return s.finalizeMessage(ctx, finalText, event.Final.Message.Format, components...)
             }
         }
         s.mu.Lock()
         finalText := strings.TrimSpace(s.buffer.String())
         s.mu.Unlock()
         if finalText != "" {
-			return s.finalizeMessage(finalText)
+			return s.finalizeMessage(ctx, finalText, "", components...)
         }
         return nil
 
@@ -80,7 +84,7 @@ func (s *discordOutboundStream) Push(ctx context.Context, event channel.StreamEv
         if errText == "" {
             return nil
         }
-		return s.finalizeMessage("Error: " + errText)
+		return s.finalizeMessage(ctx, "Error: "+errText, "")
 
     case channel.StreamEventAttachment:
         if len(event.Attachments) == 0 {
@@ -91,13 +95,13 @@ func (s *discordOutboundStream) Push(ctx context.Context, event channel.StreamEv
         finalText := strings.TrimSpace(s.buffer.String())
         s.mu.Unlock()
         if finalText != "" {
-            if err := s.finalizeMessage(finalText); err != nil {
+            if err := s.finalizeMessage(ctx, finalText, ""); err != nil {
                 return err
             }
         }
         // Send attachments
         for _, att := range event.Attachments {
-            if err := s.sendAttachment(att); err != nil {
+            if err := s.sendAttachment(ctx, att); err != nil {
                 return err
             }
         }
@@ -178,37 +182,59 @@ func (s *discordOutboundStream) updateMessage() error {
     return nil
 }
 
-func (s *discordOutboundStream) finalizeMessage(text string) error {
-    s.mu.Lock()
-    defer s.mu.Unlock()
-
-	text = truncateDiscordText(text)
-
-    if s.msgID == "" {
-		var msg *discordgo.Message
-		var err error
-		if s.reply != nil && s.reply.MessageID != "" {
-			msg, err = s.session.ChannelMessageSendReply(s.target, text, &discordgo.MessageReference{
+// finalizeMessage writes the final reply, editing the streamed message into the
+// first part and sending any further parts as new messages. Buttons go with the
+// last part.
+func (s *discordOutboundStream) finalizeMessage(ctx context.Context, text string, format channel.MessageFormat, components ...discordgo.MessageComponent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := renderDiscordMessages(text, format)
+	last := len(parts) - 1
+	for i, part := range parts {
+		var partComponents []discordgo.MessageComponent
+		if i == last {
+			partComponents = components
+		}
+		if i == 0 && s.msgID != "" {
+			edit := discordgo.NewMessageEdit(s.target, s.msgID).SetContent(part.content)
+			if len(part.embeds) > 0 {
+				edit.SetEmbeds(part.embeds)
+			}
+			if len(partComponents) > 0 {
+				edit.Components = &partComponents
+			}
+			if _, err := s.session.ChannelMessageEditComplex(edit); err != nil {
+				return err
+			}
+			continue
+		}
+		messageSend := &discordgo.MessageSend{
+			Content:    part.content,
+			Embeds:     part.embeds,
+			Components: partComponents,
+		}
+		if i == 0 && s.reply != nil && s.reply.MessageID != "" {
+			messageSend.Reference = &discordgo.MessageReference{
 				ChannelID: s.target,
 				MessageID: s.reply.MessageID,
-			})
-		} else {
-			msg, err = s.session.ChannelMessageSend(s.target, text)
+			}
 		}
+		msg, err := s.session.ChannelMessageSendComplex(s.target, messageSend)
 		if err != nil {
 			return err
 		}
-		s.msgID = msg.ID
-		s.lastUpdate = time.Now()
-		return nil
-    }
-
-    _, err := s.session.ChannelMessageEdit(s.target, s.msgID, text)
-    return err
+		if i == 0 {
+			s.msgID = msg.ID
+			s.lastUpdate = time.Now()
+		} else {
+			channel.ReportSentMessage(ctx, s.target, msg.ID)
+		}
+	}
+	return nil
 }
 
-func (s *discordOutboundStream) sendAttachment(att channel.Attachment) error {
-	ctx := context.Background()
+func (s *discordOutboundStream) sendAttachment(ctx context.Context, att channel.Attachment) error {
 	file := discordAttachmentToFile(ctx, att, s.adapter.assets)
 	if file == nil {
 		return nil
@@ -226,6 +252,24 @@ func (s *discordOutboundStream) sendAttachment(att channel.Attachment) error {
 		}
 	}
 
-	_, err := s.session.ChannelMessageSendComplex(s.target, messageSend)
-	return err
+	sent, err := s.session.ChannelMessageSendComplex(s.target, messageSend)
+	if err != nil {
+		return err
+	}
+	if sent != nil {
+		channel.ReportSentMessage(ctx, s.target, sent.ID)
+	}
+	return nil
+}
+
+// reportMessage reports the streamed message once it has been created.
+func (s *discordOutboundStream) reportMessage(ctx context.Context) {
+	s.mu.Lock()
+	msgID := s.msgID
+	reported := msgID == "" || msgID == s.reportedID
+	s.reportedID = msgID
+	s.mu.Unlock()
+	if !reported {
+		channel.ReportSentMessage(ctx, s.target, msgID)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	// Discord limits thread names to 100 characters.
+	discordMaxThreadName = 100
+	// Threads opened by the bot archive after a day of inactivity.
+	discordThreadArchiveMinutes = 1440
+
+	openedThreadTTL = time.Minute
+)
+
+// openedThread records a thread the bot opened by name.
+type openedThread struct {
+	id string
+	at time.Time
+}
+
+// discordChannelInfo looks a channel up in the session state, falling back to the REST API.
+func discordChannelInfo(session *discordgo.Session, channelID string) (*discordgo.Channel, error) {
+	channelID = strings.TrimSpace(channelID)
+	if channelID == "" {
+		return nil, fmt.Errorf("discord channel id is required")
+	}
+	if session.State != nil {
+		if ch, err := session.State.Channel(channelID); err == nil && ch != nil {
+			return ch, nil
+		}
+	}
+	ch, err := session.Channel(channelID)
+	if err != nil {
+		return nil, fmt.Errorf("discord get channel: %w", err)
+	}
+	return ch, nil
+}
+
+// isDiscordForum reports whether a channel only accepts posts as threads.
+func isDiscordForum(ch *discordgo.Channel) bool {
+	return ch != nil && (ch.Type == discordgo.ChannelTypeGuildForum || ch.Type == discordgo.ChannelTypeGuildMedia)
+}
+
+// applyDiscordThread maps a message posted in a thread (or forum post) to a thread of the
+// parent channel's conversation. Replies keep targeting the thread channel itself.
+func applyDiscordThread(msg *channel.InboundMessage, thread, parent *discordgo.Channel) {
+	if msg == nil || thread == nil || !thread.IsThread() || strings.TrimSpace(thread.ParentID) == "" {
+		return
+	}
+	msg.Conversation.ID = thread.ParentID
+	msg.Conversation.ThreadID = thread.ID
+	msg.Message.Thread = &channel.ThreadRef{ID: thread.ID, Name: thread.Name}
+	msg.ReplyTarget = thread.ID
+	if msg.Metadata == nil {
+		msg.Metadata = map[string]any{}
+	}
+	msg.Metadata["thread_name"] = thread.Name
+	if isDiscordForum(parent) {
+		msg.Metadata["forum"] = true
+	}
+}
+
+// resolveOutboundChannel picks the channel a message is posted to. A thread ID posts into
+// that thread; a thread name opens a new thread on the replied-to message, as a forum post,
+// or as a standalone public thread. Threads opened here are remembered briefly so the
+// remaining chunks and attachments of the same reply land in the same thread.
+func (a *DiscordAdapter) resolveOutboundChannel(session *discordgo.Session, token, target string, thread *channel.ThreadRef, reply *channel.ReplyRef) (string, *channel.ReplyRef, error) {
+	target = strings.TrimSpace(target)
+	if thread == nil {
+		return target, reply, nil
+	}
+	if id := strings.TrimSpace(thread.ID); id != "" {
+		return id, reply, nil
+	}
+	name := truncateDiscordRunes(strings.TrimSpace(thread.Name), discordMaxThreadName)
+	if name == "" {
+		return target, reply, nil
+	}
+	key := token + ":" + target + ":" + name
+	if id := a.recentThread(key); id != "" {
+		return id, nil, nil
+	}
+	id, err := openDiscordThread(session, target, name, reply)
+	if err != nil {
+		return "", nil, err
+	}
+	a.rememberThread(key, id)
+	// The replied-to message lives in the parent channel, so the reference is dropped.
+	return id, nil, nil
+}
+
+// openDiscordThread starts a thread named name under target and returns its channel ID.
+func openDiscordThread(session *discordgo.Session, target, name string, reply *channel.ReplyRef) (string, error) {
+	if reply != nil && strings.TrimSpace(reply.MessageID) != "" {
+		ch, err := session.MessageThreadStartComplex(target, reply.MessageID, &discordgo.ThreadStart{
+			Name:                name,
+			AutoArchiveDuration: discordThreadArchiveMinutes,
+		})
+		if err != nil {
+			return "", fmt.Errorf("discord start thread: %w", err)
+		}
+		return ch.ID, nil
+	}
+	parent, err := discordChannelInfo(session, target)
+	if err != nil {
+		return "", err
+	}
+	if isDiscordForum(parent) {
+		ch, err := session.ForumThreadStart(target, name, discordThreadArchiveMinutes, name)
+		if err != nil {
+			return "", fmt.Errorf("discord start forum post: %w", err)
+		}
+		return ch.ID, nil
+	}
+	ch, err := session.ThreadStartComplex(target, &discordgo.ThreadStart{
+		Name:                name,
+		AutoArchiveDuration: discordThreadArchiveMinutes,
+		Type:                discordgo.ChannelTypeGuildPublicThread,
+	})
+	if err != nil {
+		return "", fmt.Errorf("discord start thread: %w", err)
+	}
+	return ch.ID, nil
+}
+
+func (a *DiscordAdapter) recentThread(key string) string {
+	expireBefore := time.Now().UTC().Add(-openedThreadTTL)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for k, opened := range a.openedThreads {
+		if opened.at.Before(expireBefore) {
+			delete(a.openedThreads, k)
+		}
+	}
+	return a.openedThreads[key].id
+}
+
+func (a *DiscordAdapter) rememberThread(key, id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.openedThreads[key] = openedThread{id: id, at: time.Now().UTC()}
+}
@@ -0,0 +1,62 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestApplyDiscordThread(t *testing.T) {
+	t.Parallel()
+
+	msg := channel.InboundMessage{
+		ReplyTarget:  "t-1",
+		Conversation: channel.Conversation{ID: "t-1", Type: "guild"},
+	}
+	thread := &discordgo.Channel{ID: "t-1", ParentID: "c-1", Name: "Ideas", Type: discordgo.ChannelTypeGuildPublicThread}
+	parent := &discordgo.Channel{ID: "c-1", Type: discordgo.ChannelTypeGuildForum}
+
+	applyDiscordThread(&msg, thread, parent)
+	if msg.Conversation.ID != "c-1" || msg.Conversation.ThreadID != "t-1" {
+		t.Fatalf("unexpected conversation: %+v", msg.Conversation)
+	}
+	if msg.ReplyTarget != "t-1" {
+		t.Fatalf("replies must stay in the thread, got %q", msg.ReplyTarget)
+	}
+	if msg.Message.Thread == nil || msg.Message.Thread.ID != "t-1" || msg.Message.Thread.Name != "Ideas" {
+		t.Fatalf("unexpected thread ref: %+v", msg.Message.Thread)
+	}
+	if msg.Metadata["forum"] != true || msg.Metadata["thread_name"] != "Ideas" {
+		t.Fatalf("unexpected metadata: %+v", msg.Metadata)
+	}
+
+	plain := channel.InboundMessage{Conversation: channel.Conversation{ID: "c-2"}}
+	applyDiscordThread(&plain, &discordgo.Channel{ID: "c-2", Type: discordgo.ChannelTypeGuildText}, nil)
+	if plain.Conversation.ID != "c-2" || plain.Conversation.ThreadID != "" || plain.Message.Thread != nil {
+		t.Fatalf("non-thread channels must be left alone: %+v", plain)
+	}
+}
+
+func TestResolveOutboundChannel(t *testing.T) {
+	t.Parallel()
+
+	adapter := NewDiscordAdapter(nil)
+	reply := &channel.ReplyRef{MessageID: "m-1"}
+
+	id, gotReply, err := adapter.resolveOutboundChannel(nil, "token", "c-1", nil, reply)
+	if err != nil || id != "c-1" || gotReply != reply {
+		t.Fatalf("without thread: id=%q reply=%v err=%v", id, gotReply, err)
+	}
+
+	id, gotReply, err = adapter.resolveOutboundChannel(nil, "token", "c-1", &channel.ThreadRef{ID: "t-1"}, reply)
+	if err != nil || id != "t-1" || gotReply != reply {
+		t.Fatalf("existing thread: id=%q reply=%v err=%v", id, gotReply, err)
+	}
+
+	adapter.rememberThread("token:c-1:Ideas", "t-2")
+	id, gotReply, err = adapter.resolveOutboundChannel(nil, "token", "c-1", &channel.ThreadRef{Name: "Ideas"}, reply)
+	if err != nil || id != "t-2" || gotReply != nil {
+		t.Fatalf("recently opened thread: id=%q reply=%v err=%v", id, gotReply, err)
+	}
+}
@@ -0,0 +1,243 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	securityTLS      = "tls"
+	securitySTARTTLS = "starttls"
+	securityNone     = "none"
+
+	defaultMailbox      = "INBOX"
+	defaultPollInterval = 60 * time.Second
+	minPollInterval     = 10 * time.Second
+)
+
+// Config holds the mailbox and SMTP credentials extracted from a channel configuration.
+type Config struct {
+	IMAPHost     string
+	IMAPPort     int
+	IMAPSecurity string
+	Username     string
+	Password     string
+	Mailbox      string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPSecurity string
+	SMTPUsername string
+	SMTPPassword string
+	FromAddress  string
+	FromName     string
+	PollInterval time.Duration
+}
+
+// UserConfig holds the address used to target an e-mail recipient.
+type UserConfig struct {
+	Address string
+}
+
+func (c Config) imapAddr() string {
+	return c.IMAPHost + ":" + strconv.Itoa(c.IMAPPort)
+}
+
+func (c Config) smtpAddr() string {
+	return c.SMTPHost + ":" + strconv.Itoa(c.SMTPPort)
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"imapHost":            cfg.IMAPHost,
+		"imapPort":            cfg.IMAPPort,
+		"imapSecurity":        cfg.IMAPSecurity,
+		"username":            cfg.Username,
+		"password":            cfg.Password,
+		"mailbox":             cfg.Mailbox,
+		"smtpHost":            cfg.SMTPHost,
+		"smtpPort":            cfg.SMTPPort,
+		"smtpSecurity":        cfg.SMTPSecurity,
+		"fromAddress":         cfg.FromAddress,
+		"pollIntervalSeconds": int(cfg.PollInterval / time.Second),
+	}
+	if cfg.SMTPUsername != cfg.Username {
+		result["smtpUsername"] = cfg.SMTPUsername
+	}
+	if cfg.SMTPPassword != cfg.Password {
+		result["smtpPassword"] = cfg.SMTPPassword
+	}
+	if cfg.FromName != "" {
+		result["fromName"] = cfg.FromName
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"address": cfg.Address}, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Address, nil
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := normalizeTarget(criteria.Attribute("email")); value != "" && value == cfg.Address {
+		return true
+	}
+	return normalizeTarget(criteria.SubjectID) == cfg.Address
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := normalizeTarget(identity.Attribute("email")); value != "" {
+		result["address"] = value
+	} else if value := normalizeTarget(identity.SubjectID); value != "" {
+		result["address"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	cfg := Config{
+		IMAPHost:     strings.TrimSpace(channel.ReadString(raw, "imapHost", "imap_host")),
+		Username:     strings.TrimSpace(channel.ReadString(raw, "username")),
+		Password:     channel.ReadString(raw, "password"),
+		Mailbox:      strings.TrimSpace(channel.ReadString(raw, "mailbox")),
+		SMTPHost:     strings.TrimSpace(channel.ReadString(raw, "smtpHost", "smtp_host")),
+		SMTPUsername: strings.TrimSpace(channel.ReadString(raw, "smtpUsername", "smtp_username")),
+		SMTPPassword: channel.ReadString(raw, "smtpPassword", "smtp_password"),
+		FromName:     strings.TrimSpace(channel.ReadString(raw, "fromName", "from_name")),
+	}
+	if cfg.IMAPHost == "" || cfg.SMTPHost == "" {
+		return Config{}, fmt.Errorf("email imapHost and smtpHost are required")
+	}
+	if cfg.Username == "" || cfg.Password == "" {
+		return Config{}, fmt.Errorf("email username and password are required")
+	}
+	var err error
+	if cfg.IMAPSecurity, err = parseSecurity(channel.ReadString(raw, "imapSecurity", "imap_security"), securityTLS); err != nil {
+		return Config{}, fmt.Errorf("email imapSecurity: %w", err)
+	}
+	if cfg.SMTPSecurity, err = parseSecurity(channel.ReadString(raw, "smtpSecurity", "smtp_security"), securitySTARTTLS); err != nil {
+		return Config{}, fmt.Errorf("email smtpSecurity: %w", err)
+	}
+	imapDefault := 993
+	if cfg.IMAPSecurity != securityTLS {
+		imapDefault = 143
+	}
+	if cfg.IMAPPort, err = parsePort(channel.ReadString(raw, "imapPort", "imap_port"), imapDefault); err != nil {
+		return Config{}, fmt.Errorf("email imapPort: %w", err)
+	}
+	smtpDefault := 587
+	switch cfg.SMTPSecurity {
+	case securityTLS:
+		smtpDefault = 465
+	case securityNone:
+		smtpDefault = 25
+	}
+	if cfg.SMTPPort, err = parsePort(channel.ReadString(raw, "smtpPort", "smtp_port"), smtpDefault); err != nil {
+		return Config{}, fmt.Errorf("email smtpPort: %w", err)
+	}
+	if cfg.Mailbox == "" {
+		cfg.Mailbox = defaultMailbox
+	}
+	if cfg.SMTPUsername == "" {
+		cfg.SMTPUsername = cfg.Username
+	}
+	if cfg.SMTPPassword == "" {
+		cfg.SMTPPassword = cfg.Password
+	}
+	from := strings.TrimSpace(channel.ReadString(raw, "fromAddress", "from_address"))
+	if from == "" {
+		from = cfg.Username
+	}
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return Config{}, fmt.Errorf("email fromAddress is invalid: %q", from)
+	}
+	cfg.FromAddress = strings.ToLower(addr.Address)
+	if cfg.FromName == "" {
+		cfg.FromName = strings.TrimSpace(addr.Name)
+	}
+	cfg.PollInterval = defaultPollInterval
+	if value := strings.TrimSpace(channel.ReadString(raw, "pollIntervalSeconds", "poll_interval_seconds")); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return Config{}, fmt.Errorf("email pollIntervalSeconds must be a positive integer")
+		}
+		cfg.PollInterval = max(time.Duration(seconds)*time.Second, minPollInterval)
+	}
+	return cfg, nil
+}
+
+func parseSecurity(raw, fallback string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	switch value {
+	case "":
+		return fallback, nil
+	case securityTLS, "ssl":
+		return securityTLS, nil
+	case securitySTARTTLS:
+		return securitySTARTTLS, nil
+	case securityNone, "plain":
+		return securityNone, nil
+	default:
+		return "", fmt.Errorf("unsupported value %q", raw)
+	}
+}
+
+func parsePort(raw string, fallback int) (int, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return fallback, nil
+	}
+	port, err := strconv.Atoi(value)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", raw)
+	}
+	return port, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	address := normalizeTarget(channel.ReadString(raw, "address", "email"))
+	if address == "" {
+		return UserConfig{}, fmt.Errorf("email user config requires address")
+	}
+	return UserConfig{Address: address}, nil
+}
+
+// normalizeTarget returns the lower-cased bare address, accepting "mailto:" and "Name <addr>" forms.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	value = strings.TrimPrefix(value, "mailto:")
+	value = strings.TrimPrefix(value, "email:")
+	if value == "" {
+		return ""
+	}
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(addr.Address)
+}
@@ -0,0 +1,146 @@
+package email
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestNormalizeConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{
+		"imap_host": "imap.example.com",
+		"smtp_host": "smtp.example.com",
+		"username":  "Bot@Example.com",
+		"password":  "secret",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["imapPort"] != 993 || got["imapSecurity"] != securityTLS {
+		t.Fatalf("unexpected imap defaults: %#v", got)
+	}
+	if got["smtpPort"] != 587 || got["smtpSecurity"] != securitySTARTTLS {
+		t.Fatalf("unexpected smtp defaults: %#v", got)
+	}
+	if got["mailbox"] != defaultMailbox || got["fromAddress"] != "bot@example.com" {
+		t.Fatalf("unexpected mailbox/from defaults: %#v", got)
+	}
+	if got["pollIntervalSeconds"] != int(defaultPollInterval/time.Second) {
+		t.Fatalf("unexpected poll interval: %#v", got["pollIntervalSeconds"])
+	}
+	if _, ok := got["smtpUsername"]; ok {
+		t.Fatalf("expected smtpUsername to be omitted when equal to username: %#v", got)
+	}
+}
+
+func TestParseConfigOverrides(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig(map[string]any{
+		"imapHost":            "imap.example.com",
+		"imapSecurity":        "none",
+		"smtpHost":            "smtp.example.com",
+		"smtpSecurity":        "ssl",
+		"username":            "bot",
+		"password":            "secret",
+		"smtpUsername":        "relay",
+		"fromAddress":         "Memoh <memoh@example.com>",
+		"pollIntervalSeconds": 1,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.IMAPPort != 143 || cfg.SMTPPort != 465 || cfg.SMTPSecurity != securityTLS {
+		t.Fatalf("unexpected ports: %#v", cfg)
+	}
+	if cfg.SMTPUsername != "relay" || cfg.SMTPPassword != "secret" {
+		t.Fatalf("unexpected smtp credentials: %#v", cfg)
+	}
+	if cfg.FromAddress != "memoh@example.com" || cfg.FromName != "Memoh" {
+		t.Fatalf("unexpected from: %#v", cfg)
+	}
+	if cfg.PollInterval != minPollInterval {
+		t.Fatalf("expected poll interval clamped to %s, got %s", minPollInterval, cfg.PollInterval)
+	}
+}
+
+func TestParseConfigRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	base := func() map[string]any {
+		return map[string]any{
+			"imapHost": "imap.example.com",
+			"smtpHost": "smtp.example.com",
+			"username": "bot@example.com",
+			"password": "secret",
+		}
+	}
+	cases := []func(map[string]any){
+		func(m map[string]any) { delete(m, "imapHost") },
+		func(m map[string]any) { delete(m, "password") },
+		func(m map[string]any) { m["imapSecurity"] = "magic" },
+		func(m map[string]any) { m["smtpPort"] = "70000" },
+		func(m map[string]any) { m["username"] = "bot"; m["fromAddress"] = "" },
+		func(m map[string]any) { m["pollIntervalSeconds"] = "-5" },
+	}
+	for i, mutate := range cases {
+		raw := base()
+		mutate(raw)
+		if _, err := parseConfig(raw); err == nil {
+			t.Fatalf("case %d: expected error for %#v", i, raw)
+		}
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"alice@example.com":         "alice@example.com",
+		"mailto:Alice@Example.com":  "alice@example.com",
+		"email:alice@example.com":   "alice@example.com",
+		"Alice <alice@example.com>": "alice@example.com",
+		"  ":                        "",
+		"not an address":            "",
+	}
+	for input, want := range cases {
+		if got := normalizeTarget(input); got != want {
+			t.Fatalf("normalizeTarget(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestUserConfigAndBinding(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeUserConfig(map[string]any{"email": "Alice <ALICE@example.com>"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["address"] != "alice@example.com" {
+		t.Fatalf("unexpected user config: %#v", got)
+	}
+	target, err := resolveTarget(got)
+	if err != nil || target != "alice@example.com" {
+		t.Fatalf("unexpected target %q err=%v", target, err)
+	}
+	if !matchBinding(got, channel.BindingCriteria{SubjectID: "alice@example.com"}) {
+		t.Fatal("expected binding to match subject id")
+	}
+	if !matchBinding(got, channel.BindingCriteria{Attributes: map[string]string{"email": "Alice@Example.com"}}) {
+		t.Fatal("expected binding to match email attribute")
+	}
+	if matchBinding(got, channel.BindingCriteria{SubjectID: "bob@example.com"}) {
+		t.Fatal("expected binding not to match another address")
+	}
+	built := buildUserConfig(channel.Identity{SubjectID: "x", Attributes: map[string]string{"email": "alice@example.com"}})
+	if built["address"] != "alice@example.com" {
+		t.Fatalf("unexpected built config: %#v", built)
+	}
+	if _, err := normalizeUserConfig(map[string]any{}); err == nil {
+		t.Fatal("expected error for empty user config")
+	}
+}
@@ -0,0 +1,7 @@
+// Package email implements the e-mail channel adapter (IMAP inbound, SMTP outbound).
+package email
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for e-mail.
+const Type channel.ChannelType = "email"
@@ -0,0 +1,620 @@
+package email
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+	"github.com/memohai/memoh/internal/media"
+)
+
+const (
+	inboundDedupTTL     = 10 * time.Minute
+	threadCacheTTL      = 7 * 24 * time.Hour
+	defaultSubject      = "Message"
+	maxGeneratedSubject = 78
+)
+
+// assetOpener reads stored asset bytes by content hash.
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// threadInfo remembers the headers of a received message so replies can be threaded.
+type threadInfo struct {
+	subject    string
+	references []string
+	storedAt   time.Time
+}
+
+// EmailAdapter implements the channel.Adapter, channel.Sender, and channel.Receiver interfaces for e-mail.
+type EmailAdapter struct {
+	logger       *slog.Logger
+	mu           sync.Mutex
+	seen         map[string]time.Time  // keyed by configID|messageID
+	threads      map[string]threadInfo // keyed by Message-ID
+	assets       assetOpener
+	httpDownload *http.Client
+}
+
+// NewEmailAdapter creates an EmailAdapter with the given logger.
+func NewEmailAdapter(log *slog.Logger) *EmailAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &EmailAdapter{
+		logger:       log.With(slog.String("adapter", "email")),
+		seen:         make(map[string]time.Time),
+		threads:      make(map[string]threadInfo),
+		httpDownload: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// SetAssetOpener injects the media asset reader for storage-first file delivery.
+func (a *EmailAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+// Type returns the e-mail channel type.
+func (a *EmailAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the e-mail channel metadata.
+func (a *EmailAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Email",
+		Capabilities: channel.ChannelCapabilities{
+			Text:           true,
+			Markdown:       true,
+			Attachments:    true,
+			Media:          true,
+			Reply:          true,
+			Threads:        true,
+			BlockStreaming: true,
+			ChatTypes:      []string{"direct"},
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: 100000,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"imapHost": {
+					Type:     channel.FieldString,
+					Required: true,
+					Title:    "IMAP Host",
+					Example:  "imap.example.com",
+				},
+				"imapPort": {
+					Type:        channel.FieldNumber,
+					Title:       "IMAP Port",
+					Description: "Defaults to 993 for TLS, 143 otherwise",
+				},
+				"imapSecurity": {
+					Type:  channel.FieldEnum,
+					Title: "IMAP Security",
+					Enum:  []string{securityTLS, securitySTARTTLS, securityNone},
+				},
+				"username": {
+					Type:     channel.FieldString,
+					Required: true,
+					Title:    "Username",
+					Example:  "bot@example.com",
+				},
+				"password": {
+					Type:     channel.FieldSecret,
+					Required: true,
+					Title:    "Password",
+				},
+				"mailbox": {
+					Type:        channel.FieldString,
+					Title:       "Mailbox",
+					Description: "Folder watched for new mail; unread messages are processed and marked as read",
+					Example:     defaultMailbox,
+				},
+				"smtpHost": {
+					Type:     channel.FieldString,
+					Required: true,
+					Title:    "SMTP Host",
+					Example:  "smtp.example.com",
+				},
+				"smtpPort": {
+					Type:        channel.FieldNumber,
+					Title:       "SMTP Port",
+					Description: "Defaults to 587 for STARTTLS, 465 for TLS, 25 otherwise",
+				},
+				"smtpSecurity": {
+					Type:  channel.FieldEnum,
+					Title: "SMTP Security",
+					Enum:  []string{securitySTARTTLS, securityTLS, securityNone},
+				},
+				"smtpUsername": {
+					Type:        channel.FieldString,
+					Title:       "SMTP Username",
+					Description: "Defaults to the IMAP username",
+				},
+				"smtpPassword": {
+					Type:        channel.FieldSecret,
+					Title:       "SMTP Password",
+					Description: "Defaults to the IMAP password",
+				},
+				"fromAddress": {
+					Type:        channel.FieldString,
+					Title:       "From Address",
+					Description: "Defaults to the username",
+				},
+				"fromName": {
+					Type:  channel.FieldString,
+					Title: "From Name",
+				},
+				"pollIntervalSeconds": {
+					Type:        channel.FieldNumber,
+					Title:       "Poll Interval",
+					Description: "Seconds between mailbox checks when the server does not support IDLE",
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"address": {Type: channel.FieldString, Required: true},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "address",
+			Hints: []channel.TargetHint{
+				{Label: "Address", Example: "alice@example.com"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes an e-mail channel configuration map.
+func (a *EmailAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes an e-mail user-binding configuration map.
+func (a *EmailAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes an e-mail delivery target string.
+func (a *EmailAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from an e-mail user-binding configuration.
+func (a *EmailAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether an e-mail user binding matches the given criteria.
+func (a *EmailAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs an e-mail user-binding config from an Identity.
+func (a *EmailAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+func (a *EmailAdapter) dispatchInbound(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler, msg channel.InboundMessage) {
+	if a.logger != nil {
+		a.logger.Info(
+			"inbound received",
+			slog.String("config_id", cfg.ID),
+			slog.String("from", msg.Sender.SubjectID),
+			slog.String("message_id", msg.Message.ID),
+			slog.String("thread_id", msg.Conversation.ThreadID),
+			slog.String("text", common.SummarizeText(msg.Message.Text)),
+			slog.Int("attachments", len(msg.Message.Attachments)),
+		)
+	}
+	go func() {
+		if err := handler(ctx, cfg, msg); err != nil && a.logger != nil {
+			a.logger.Error("handle inbound failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+	}()
+}
+
+func (a *EmailAdapter) isDuplicateInbound(key, messageID string) bool {
+	if strings.TrimSpace(messageID) == "" {
+		return false
+	}
+	now := time.Now().UTC()
+	expireBefore := now.Add(-inboundDedupTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for seenKey, seenAt := range a.seen {
+		if seenAt.Before(expireBefore) {
+			delete(a.seen, seenKey)
+		}
+	}
+	seenKey := key + "|" + messageID
+	if _, ok := a.seen[seenKey]; ok {
+		return true
+	}
+	a.seen[seenKey] = now
+	return false
+}
+
+func (a *EmailAdapter) rememberThread(messageID, subject string, references []string) {
+	if strings.TrimSpace(messageID) == "" {
+		return
+	}
+	now := time.Now().UTC()
+	expireBefore := now.Add(-threadCacheTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for id, info := range a.threads {
+		if info.storedAt.Before(expireBefore) {
+			delete(a.threads, id)
+		}
+	}
+	a.threads[messageID] = threadInfo{
+		subject:    subject,
+		references: append([]string(nil), references...),
+		storedAt:   now,
+	}
+}
+
+func (a *EmailAdapter) lookupThread(messageID string) (threadInfo, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	info, ok := a.threads[messageID]
+	return info, ok
+}
+
+// buildInboundMessage maps a parsed e-mail to an inbound channel message.
+// Mail sent by the bot itself and automated mail (auto-replies, mailing lists) is skipped.
+func (a *EmailAdapter) buildInboundMessage(cfg channel.ChannelConfig, emailCfg Config, parsed parsedEmail, receivedAt time.Time) (channel.InboundMessage, bool) {
+	replyTo := parsed.replyAddress()
+	if replyTo == nil || strings.TrimSpace(replyTo.Address) == "" {
+		return channel.InboundMessage{}, false
+	}
+	sender := parsed.From
+	if sender == nil {
+		sender = replyTo
+	}
+	senderAddress := strings.ToLower(strings.TrimSpace(sender.Address))
+	if senderAddress == emailCfg.FromAddress || parsed.AutoSubmitted {
+		return channel.InboundMessage{}, false
+	}
+	text := parsed.bodyText()
+	isReply := len(parsed.InReplyTo) > 0 || len(parsed.References) > 0
+	if text == "" && len(parsed.Attachments) == 0 {
+		return channel.InboundMessage{}, false
+	}
+	if !isReply && parsed.Subject != "" {
+		text = strings.TrimSpace("Subject: " + parsed.Subject + "\n\n" + text)
+	}
+
+	threadID := parsed.threadID()
+	address := strings.ToLower(strings.TrimSpace(replyTo.Address))
+	displayName := strings.TrimSpace(sender.Name)
+	if displayName == "" {
+		displayName = senderAddress
+	}
+	if !parsed.Date.IsZero() {
+		receivedAt = parsed.Date
+	}
+	metadata := map[string]any{
+		"subject":    parsed.Subject,
+		"message_id": parsed.MessageID,
+	}
+	if len(parsed.InReplyTo) > 0 {
+		metadata["in_reply_to"] = parsed.InReplyTo[0]
+	}
+	if len(parsed.References) > 0 {
+		metadata["references"] = strings.Join(parsed.References, " ")
+	}
+
+	msg := channel.Message{
+		ID:          parsed.MessageID,
+		Format:      channel.MessageFormatPlain,
+		Text:        text,
+		Attachments: parsed.Attachments,
+		Metadata:    metadata,
+	}
+	if threadID != "" {
+		msg.Thread = &channel.ThreadRef{ID: threadID}
+	}
+	if len(parsed.InReplyTo) > 0 {
+		msg.Reply = &channel.ReplyRef{Target: address, MessageID: parsed.InReplyTo[0]}
+	}
+
+	references := parsed.References
+	if len(references) == 0 && len(parsed.InReplyTo) > 0 {
+		references = parsed.InReplyTo
+	}
+	a.rememberThread(parsed.MessageID, parsed.Subject, references)
+
+	return channel.InboundMessage{
+		Channel:     Type,
+		Message:     msg,
+		BotID:       cfg.BotID,
+		ReplyTarget: address,
+		Sender: channel.Identity{
+			SubjectID:   senderAddress,
+			DisplayName: displayName,
+			Attributes: map[string]string{
+				"email": senderAddress,
+				"name":  strings.TrimSpace(sender.Name),
+			},
+		},
+		Conversation: channel.Conversation{
+			ID:       address,
+			Type:     "direct",
+			Name:     displayName,
+			ThreadID: threadID,
+		},
+		ReceivedAt: receivedAt.UTC(),
+		Source:     "email",
+		Metadata:   metadata,
+	}, true
+}
+
+// Send delivers an outbound message as a single e-mail, threading it under the replied message when known.
+func (a *EmailAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return err
+	}
+	to := normalizeTarget(msg.Target)
+	if to == "" {
+		return fmt.Errorf("email target is required")
+	}
+	_, err = a.sendMessage(ctx, cfg, emailCfg, to, msg.Message)
+	return err
+}
+
+// sendMessage composes and delivers one e-mail and returns its Message-ID.
+func (a *EmailAdapter) sendMessage(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, to string, msg channel.Message) (string, error) {
+	text := strings.TrimSpace(msg.PlainText())
+	if text == "" && len(msg.Attachments) == 0 {
+		return "", fmt.Errorf("message is required")
+	}
+	out := outgoingEmail{
+		To:       to,
+		Text:     text,
+		Markdown: msg.Format == channel.MessageFormatMarkdown || msg.Format == "",
+	}
+	a.applyThreading(&out, msg)
+	for _, att := range msg.Attachments {
+		data, mime, err := a.readAttachmentBytes(ctx, cfg, att)
+		if err != nil {
+			return "", err
+		}
+		if mime == "" {
+			mime = "application/octet-stream"
+		}
+		name := strings.TrimSpace(att.Name)
+		if name == "" {
+			name = fileNameFromMime(mime, string(att.Type))
+		}
+		out.Attachments = append(out.Attachments, outgoingAttachment{Name: name, Mime: mime, Data: data})
+	}
+	raw, messageID, err := composeEmail(emailCfg, out, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("compose email: %w", err)
+	}
+	if err := sendSMTP(ctx, emailCfg, to, raw); err != nil {
+		if a.logger != nil {
+			a.logger.Error("send failed", slog.String("config_id", cfg.ID), slog.String("to", to), slog.Any("error", err))
+		}
+		return "", err
+	}
+	return messageID, nil
+}
+
+// applyThreading fills the subject and In-Reply-To/References headers from the reply and thread refs.
+func (a *EmailAdapter) applyThreading(out *outgoingEmail, msg channel.Message) {
+	var (
+		replyID  string
+		threadID string
+		subject  string
+		refs     []string
+	)
+	if msg.Reply != nil {
+		replyID = strings.TrimSpace(msg.Reply.MessageID)
+	}
+	if msg.Thread != nil {
+		threadID = strings.TrimSpace(msg.Thread.ID)
+	}
+	if replyID != "" {
+		if info, ok := a.lookupThread(replyID); ok {
+			subject = info.subject
+			refs = append(refs, info.references...)
+		}
+	}
+	if subject == "" && threadID != "" {
+		if info, ok := a.lookupThread(threadID); ok {
+			subject = info.subject
+		}
+	}
+	if len(refs) == 0 && threadID != "" && threadID != replyID {
+		refs = append(refs, threadID)
+	}
+	if replyID != "" {
+		refs = append(refs, replyID)
+		out.InReplyTo = replyID
+	} else if threadID != "" {
+		out.InReplyTo = threadID
+	}
+	out.References = refs
+	if out.InReplyTo != "" {
+		out.Subject = replySubject(subject)
+	}
+	if override := strings.TrimSpace(channel.ReadString(msg.Metadata, "subject")); override != "" {
+		out.Subject = override
+	}
+	if out.Subject == "" {
+		out.Subject = subjectFromText(out.Text)
+	}
+}
+
+// subjectFromText derives a subject line from the first line of a proactive message.
+func subjectFromText(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	line = strings.TrimSpace(strings.TrimLeft(line, "#*> "))
+	if line == "" {
+		return defaultSubject
+	}
+	runes := []rune(line)
+	if len(runes) > maxGeneratedSubject {
+		return strings.TrimSpace(string(runes[:maxGeneratedSubject-1])) + "…"
+	}
+	return line
+}
+
+func (a *EmailAdapter) readAttachmentBytes(ctx context.Context, cfg channel.ChannelConfig, att channel.Attachment) ([]byte, string, error) {
+	mime := strings.TrimSpace(att.Mime)
+	if hash := strings.TrimSpace(att.ContentHash); hash != "" && a.assets != nil {
+		botID := cfg.BotID
+		if att.Metadata != nil {
+			if bid, ok := att.Metadata["bot_id"].(string); ok && strings.TrimSpace(bid) != "" {
+				botID = strings.TrimSpace(bid)
+			}
+		}
+		reader, asset, err := a.assets.Open(ctx, botID, hash)
+		if err == nil {
+			data, readErr := io.ReadAll(io.LimitReader(reader, media.MaxAssetBytes+1))
+			_ = reader.Close()
+			if readErr == nil && len(data) > 0 {
+				if mime == "" {
+					mime = asset.Mime
+				}
+				return data, mime, nil
+			}
+		}
+	}
+	dataURL := strings.TrimSpace(att.Base64)
+	if dataURL == "" && strings.HasPrefix(strings.ToLower(strings.TrimSpace(att.URL)), "data:") {
+		dataURL = strings.TrimSpace(att.URL)
+	}
+	if dataURL != "" {
+		data, dataMime, err := decodeDataURL(dataURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode data url for email attachment: %w", err)
+		}
+		if mime == "" {
+			mime = dataMime
+		}
+		return data, mime, nil
+	}
+	urlRef := strings.TrimSpace(att.URL)
+	if strings.HasPrefix(urlRef, "http://") || strings.HasPrefix(urlRef, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlRef, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, err := a.httpDownload.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("download attachment: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("download attachment status: %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, media.MaxAssetBytes+1))
+		if err != nil {
+			return nil, "", err
+		}
+		if int64(len(data)) > media.MaxAssetBytes {
+			return nil, "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+		}
+		if mime == "" {
+			mime = stripMimeParams(resp.Header.Get("Content-Type"))
+		}
+		return data, mime, nil
+	}
+	return nil, "", fmt.Errorf("no usable attachment reference for email")
+}
+
+func decodeDataURL(dataURL string) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(dataURL, ",")
+	if !ok {
+		return nil, "", fmt.Errorf("malformed data url")
+	}
+	mime := stripMimeParams(strings.TrimPrefix(strings.TrimPrefix(header, "data:"), "DATA:"))
+	data, err := io.ReadAll(io.LimitReader(
+		base64.NewDecoder(base64.StdEncoding, strings.NewReader(payload)),
+		media.MaxAssetBytes+1,
+	))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mime, nil
+}
+
+func stripMimeParams(value string) string {
+	value = strings.TrimSpace(value)
+	if idx := strings.Index(value, ";"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
+
+func fileNameFromMime(mime, fallbackType string) string {
+	mime = strings.ToLower(strings.TrimSpace(mime))
+	switch {
+	case strings.HasPrefix(mime, "image/png"):
+		return "image.png"
+	case strings.HasPrefix(mime, "image/jpeg"):
+		return "image.jpg"
+	case strings.HasPrefix(mime, "image/gif"):
+		return "image.gif"
+	case strings.HasPrefix(mime, "image/webp"):
+		return "image.webp"
+	case strings.HasPrefix(mime, "audio/ogg"):
+		return "audio.ogg"
+	case strings.HasPrefix(mime, "audio/mpeg"):
+		return "audio.mp3"
+	case strings.HasPrefix(mime, "video/mp4"):
+		return "video.mp4"
+	case strings.HasPrefix(mime, "application/pdf"):
+		return "document.pdf"
+	}
+	if fallbackType == "" {
+		return "file"
+	}
+	return fallbackType
+}
+
+// OpenStream opens a block-streaming session that buffers the reply and sends it as one e-mail on completion.
+func (a *EmailAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	to := normalizeTarget(target)
+	if to == "" {
+		return nil, fmt.Errorf("email target is required")
+	}
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &emailOutboundStream{
+		adapter:  a,
+		cfg:      cfg,
+		emailCfg: emailCfg,
+		to:       to,
+		reply:    opts.Reply,
+		thread:   opts.Thread,
+	}, nil
+}
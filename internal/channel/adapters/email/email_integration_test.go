@@ -0,0 +1,57 @@
+package email
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// TestEmailAdapter_Integration logs in to a real mailbox and sends a message to itself.
+// Required env: EMAIL_IMAP_HOST, EMAIL_SMTP_HOST, EMAIL_USERNAME, EMAIL_PASSWORD.
+func TestEmailAdapter_Integration(t *testing.T) {
+	imapHost := os.Getenv("EMAIL_IMAP_HOST")
+	smtpHost := os.Getenv("EMAIL_SMTP_HOST")
+	username := os.Getenv("EMAIL_USERNAME")
+	password := os.Getenv("EMAIL_PASSWORD")
+	if imapHost == "" || smtpHost == "" || username == "" || password == "" {
+		t.Skip("skipping integration test: EMAIL_IMAP_HOST, EMAIL_SMTP_HOST, EMAIL_USERNAME or EMAIL_PASSWORD not set")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	adapter := NewEmailAdapter(logger)
+	cfg := channel.ChannelConfig{
+		ID:          "integration-test-bot",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"imapHost": imapHost,
+			"smtpHost": smtpHost,
+			"username": username,
+			"password": password,
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	conn, err := adapter.Connect(ctx, cfg, func(context.Context, channel.ChannelConfig, channel.InboundMessage) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = conn.Stop(context.Background()) }()
+
+	err = adapter.Send(ctx, cfg, channel.OutboundMessage{
+		Target: username,
+		Message: channel.Message{
+			Format: channel.MessageFormatMarkdown,
+			Text:   "Integration test from **memoh** at " + time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
@@ -0,0 +1,346 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// fakeSMTP is a minimal SMTP server that records delivered messages.
+type fakeSMTP struct {
+	mu       sync.Mutex
+	messages [][]byte
+	rcpts    []string
+	received chan struct{}
+}
+
+func newFakeSMTP(t *testing.T) (*fakeSMTP, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen smtp: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	s := &fakeSMTP{received: make(chan struct{}, 8)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var buf bytes.Buffer
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				buf.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, buf.Bytes())
+			s.mu.Unlock()
+			s.received <- struct{}{}
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *fakeSMTP) last() ([]byte, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messages[len(s.messages)-1], s.rcpts[len(s.rcpts)-1]
+}
+
+func newTestIMAP(t *testing.T, messages ...string) (*memory.Mailbox, int) {
+	t.Helper()
+	be := memory.New()
+	user, err := be.Login(nil, "username", "password")
+	if err != nil {
+		t.Fatalf("memory login: %v", err)
+	}
+	mbox, err := user.GetMailbox("INBOX")
+	if err != nil {
+		t.Fatalf("get mailbox: %v", err)
+	}
+	for _, raw := range messages {
+		if err := mbox.CreateMessage(nil, time.Now(), bytes.NewBufferString(raw)); err != nil {
+			t.Fatalf("append message: %v", err)
+		}
+	}
+	srv := server.New(be)
+	srv.AllowInsecureAuth = true
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen imap: %v", err)
+	}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return mbox.(*memory.Mailbox), ln.Addr().(*net.TCPAddr).Port
+}
+
+func testChannelConfig(imapPort, smtpPort int) channel.ChannelConfig {
+	return channel.ChannelConfig{
+		ID:          "email-test",
+		BotID:       "bot-1",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"imapHost":     "127.0.0.1",
+			"imapPort":     strconv.Itoa(imapPort),
+			"imapSecurity": securityNone,
+			"smtpHost":     "127.0.0.1",
+			"smtpPort":     strconv.Itoa(smtpPort),
+			"smtpSecurity": securityNone,
+			"username":     "username",
+			"password":     "password",
+			"fromAddress":  "Memoh <bot@example.com>",
+		},
+	}
+}
+
+func TestEmailAdapterReceiveAndReply(t *testing.T) {
+	t.Parallel()
+
+	incoming := "From: Alice <alice@example.com>\r\n" +
+		"To: bot@example.com\r\n" +
+		"Subject: Hello there\r\n" +
+		"Message-ID: <hello-1@example.com>\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"Can you summarize the plan?"
+	ownMail := "From: bot@example.com\r\n" +
+		"To: alice@example.com\r\n" +
+		"Subject: Re: Hello there\r\n" +
+		"Message-ID: <own-1@example.com>\r\n" +
+		"\r\n" +
+		"sent by the bot"
+	mbox, imapPort := newTestIMAP(t, incoming, ownMail)
+	smtp, smtpPort := newFakeSMTP(t)
+	cfg := testChannelConfig(imapPort, smtpPort)
+	adapter := NewEmailAdapter(nil)
+
+	got := make(chan channel.InboundMessage, 4)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	conn, err := adapter.Connect(ctx, cfg, func(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+		got <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	var msg channel.InboundMessage
+	select {
+	case msg = <-got:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for inbound message")
+	}
+	if msg.Message.ID != "hello-1@example.com" || msg.Conversation.ThreadID != "hello-1@example.com" {
+		t.Fatalf("unexpected message ids: %#v", msg)
+	}
+	if msg.Message.Text != "Subject: Hello there\n\nCan you summarize the plan?" {
+		t.Fatalf("unexpected text %q", msg.Message.Text)
+	}
+	if msg.ReplyTarget != "alice@example.com" || msg.Sender.DisplayName != "Alice" || msg.Conversation.Type != "direct" {
+		t.Fatalf("unexpected routing: %#v", msg)
+	}
+
+	if err := conn.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case extra := <-got:
+		t.Fatalf("expected own mail to be skipped, got %#v", extra)
+	default:
+	}
+	for _, m := range mbox.Messages[1:] {
+		if !hasFlag(m.Flags, imap.SeenFlag) {
+			t.Fatalf("expected message %d to be marked seen, flags=%v", m.Uid, m.Flags)
+		}
+	}
+
+	stream, err := adapter.OpenStream(ctx, cfg, msg.ReplyTarget, channel.StreamOptions{
+		Reply:  &channel.ReplyRef{Target: msg.ReplyTarget, MessageID: msg.Message.ID},
+		Thread: msg.Message.Thread,
+	})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	for _, delta := range []string{"The plan ", "is **ready**."} {
+		if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: delta}); err != nil {
+			t.Fatalf("push delta: %v", err)
+		}
+	}
+	if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventFinal}); err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	_ = stream.Close(ctx)
+
+	select {
+	case <-smtp.received:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for smtp delivery")
+	}
+	raw, rcpt := smtp.last()
+	if rcpt != "alice@example.com" {
+		t.Fatalf("unexpected recipient %q", rcpt)
+	}
+	parsed, err := parseEmail(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse sent mail: %v", err)
+	}
+	if parsed.Subject != "Re: Hello there" {
+		t.Fatalf("unexpected subject %q", parsed.Subject)
+	}
+	if len(parsed.InReplyTo) != 1 || parsed.InReplyTo[0] != "hello-1@example.com" {
+		t.Fatalf("unexpected In-Reply-To %v", parsed.InReplyTo)
+	}
+	if len(parsed.References) != 1 || parsed.References[0] != "hello-1@example.com" {
+		t.Fatalf("unexpected References %v", parsed.References)
+	}
+	if strings.TrimSpace(parsed.Text) != "The plan is **ready**." || !strings.Contains(parsed.HTML, "<strong>ready</strong>") {
+		t.Fatalf("unexpected bodies text=%q html=%q", parsed.Text, parsed.HTML)
+	}
+}
+
+func TestEmailAdapterSendProactive(t *testing.T) {
+	t.Parallel()
+
+	smtp, smtpPort := newFakeSMTP(t)
+	cfg := testChannelConfig(1, smtpPort)
+	adapter := NewEmailAdapter(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := adapter.Send(ctx, cfg, channel.OutboundMessage{
+		Target: "mailto:bob@example.com",
+		Message: channel.Message{
+			Format: channel.MessageFormatPlain,
+			Text:   "Reminder: standup at 10\nSee you there.",
+			Attachments: []channel.Attachment{{
+				Type:   channel.AttachmentFile,
+				Name:   "agenda.txt",
+				Base64: "data:text/plain;base64,YWdlbmRh",
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	raw, rcpt := smtp.last()
+	if rcpt != "bob@example.com" {
+		t.Fatalf("unexpected recipient %q", rcpt)
+	}
+	parsed, err := parseEmail(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse sent mail: %v", err)
+	}
+	if parsed.Subject != "Reminder: standup at 10" || len(parsed.InReplyTo) != 0 {
+		t.Fatalf("unexpected headers: %#v", parsed)
+	}
+	if parsed.HTML != "" {
+		t.Fatalf("expected no html part for plain text, got %q", parsed.HTML)
+	}
+	if len(parsed.Attachments) != 1 || parsed.Attachments[0].Name != "agenda.txt" {
+		t.Fatalf("unexpected attachments: %#v", parsed.Attachments)
+	}
+}
+
+func TestBuildInboundMessageSkipsAutomatedMail(t *testing.T) {
+	t.Parallel()
+
+	adapter := NewEmailAdapter(nil)
+	cfg := channel.ChannelConfig{ID: "cfg", BotID: "bot"}
+	emailCfg := Config{FromAddress: "bot@example.com"}
+	parsed, err := parseEmail(strings.NewReader("From: list@example.com\r\nList-Id: <news.example.com>\r\nMessage-ID: <n1@example.com>\r\n\r\nnewsletter"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, ok := adapter.buildInboundMessage(cfg, emailCfg, parsed, time.Now()); ok {
+		t.Fatal("expected mailing list mail to be skipped")
+	}
+
+	parsed, err = parseEmail(strings.NewReader(multipartReply))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	msg, ok := adapter.buildInboundMessage(cfg, emailCfg, parsed, time.Now())
+	if !ok {
+		t.Fatal("expected reply to be accepted")
+	}
+	if msg.Message.Text != "Looks good, thanks!" || msg.Conversation.ThreadID != "root-0@example.com" {
+		t.Fatalf("unexpected reply mapping: %#v", msg)
+	}
+	if msg.Message.Reply == nil || msg.Message.Reply.MessageID != "bot-1@example.com" {
+		t.Fatalf("unexpected reply ref: %#v", msg.Message.Reply)
+	}
+
+	out := outgoingEmail{Text: "ok"}
+	adapter.applyThreading(&out, channel.Message{
+		Reply:  &channel.ReplyRef{MessageID: "reply-2@example.com"},
+		Thread: &channel.ThreadRef{ID: "root-0@example.com"},
+	})
+	if out.Subject != "Re: Weekly report" || out.InReplyTo != "reply-2@example.com" {
+		t.Fatalf("unexpected threading: %#v", out)
+	}
+	wantRefs := []string{"root-0@example.com", "bot-1@example.com", "reply-2@example.com"}
+	if strings.Join(out.References, " ") != strings.Join(wantRefs, " ") {
+		t.Fatalf("unexpected references %v", out.References)
+	}
+}
+
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
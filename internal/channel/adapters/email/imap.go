@@ -0,0 +1,266 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	imapDialTimeout  = 30 * time.Second
+	imapCommandGrace = time.Minute
+	imapMinBackoff   = time.Second
+	imapMaxBackoff   = 5 * time.Minute
+	imapFetchBuffer  = 8
+)
+
+// Connect logs in to the IMAP mailbox and watches it for unread mail, using IDLE when the
+// server supports it and polling otherwise.
+func (a *EmailAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	if a.logger != nil {
+		a.logger.Info("start", slog.String("config_id", cfg.ID))
+	}
+	emailCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+	session, err := openMailbox(emailCfg)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("imap login failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+	connCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.runMailbox(connCtx, cfg, emailCfg, session, handler)
+	}()
+	stop := func(stopCtx context.Context) error {
+		if a.logger != nil {
+			a.logger.Info("stop", slog.String("config_id", cfg.ID))
+		}
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	}
+	return channel.NewConnection(cfg, stop), nil
+}
+
+// mailboxSession is a logged-in IMAP client with the mailbox selected.
+type mailboxSession struct {
+	client *client.Client
+	notify <-chan struct{}
+}
+
+// openMailbox dials the IMAP server, authenticates and selects the configured mailbox.
+func openMailbox(cfg Config) (*mailboxSession, error) {
+	dialer := &net.Dialer{Timeout: imapDialTimeout}
+	tlsConfig := &tls.Config{ServerName: cfg.IMAPHost, MinVersion: tls.VersionTLS12}
+	var (
+		c   *client.Client
+		err error
+	)
+	if cfg.IMAPSecurity == securityTLS {
+		c, err = client.DialWithDialerTLS(dialer, cfg.imapAddr(), tlsConfig)
+	} else {
+		c, err = client.DialWithDialer(dialer, cfg.imapAddr())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("imap dial: %w", err)
+	}
+	notify := watchUpdates(c)
+	// Idle restarts at least once per poll interval, so the command timeout only has to cover that.
+	c.Timeout = cfg.PollInterval + imapCommandGrace
+	if cfg.IMAPSecurity == securitySTARTTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			_ = c.Logout()
+			return nil, fmt.Errorf("imap starttls: %w", err)
+		}
+	}
+	if err := c.Login(cfg.Username, cfg.Password); err != nil {
+		_ = c.Logout()
+		return nil, fmt.Errorf("imap login: %w", err)
+	}
+	if _, err := c.Select(cfg.Mailbox, false); err != nil {
+		_ = c.Logout()
+		return nil, fmt.Errorf("imap select %s: %w", cfg.Mailbox, err)
+	}
+	return &mailboxSession{client: c, notify: notify}, nil
+}
+
+// watchUpdates drains unilateral server updates until logout, because the client blocks when
+// Updates is full. Mailbox changes are coalesced into a single wake-up signal.
+func watchUpdates(c *client.Client) <-chan struct{} {
+	updates := make(chan client.Update, 16)
+	notify := make(chan struct{}, 1)
+	c.Updates = updates
+	go func() {
+		for {
+			select {
+			case <-c.LoggedOut():
+				return
+			case update := <-updates:
+				if _, ok := update.(*client.MailboxUpdate); !ok {
+					continue
+				}
+				select {
+				case notify <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return notify
+}
+
+// runMailbox serves IMAP sessions until ctx is done, reconnecting with backoff when a session fails.
+func (a *EmailAdapter) runMailbox(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, session *mailboxSession, handler channel.InboundHandler) {
+	backoff := imapMinBackoff
+	for {
+		if session != nil {
+			err := a.serveSession(ctx, cfg, emailCfg, session, handler)
+			_ = session.client.Logout()
+			session = nil
+			if ctx.Err() != nil {
+				return
+			}
+			if a.logger != nil {
+				a.logger.Warn("imap session ended", slog.String("config_id", cfg.ID), slog.Any("error", err))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		next, err := openMailbox(emailCfg)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Warn("imap reconnect failed", slog.String("config_id", cfg.ID), slog.Duration("retry_in", backoff), slog.Any("error", err))
+			}
+			backoff = min(backoff*2, imapMaxBackoff)
+			continue
+		}
+		backoff = imapMinBackoff
+		session = next
+	}
+}
+
+// serveSession processes unread mail, then waits for new mail and repeats.
+func (a *EmailAdapter) serveSession(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, session *mailboxSession, handler channel.InboundHandler) error {
+	for {
+		if err := a.processUnseen(ctx, cfg, emailCfg, session.client, handler); err != nil {
+			return err
+		}
+		if err := waitForMail(ctx, session.client, session.notify, emailCfg.PollInterval); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// waitForMail idles until the server reports a mailbox change, the poll interval elapses, or ctx is done.
+func waitForMail(ctx context.Context, c *client.Client, notify <-chan struct{}, pollInterval time.Duration) error {
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Idle(stop, &client.IdleOptions{PollInterval: pollInterval})
+	}()
+	timer := time.NewTimer(pollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-notify:
+	case <-timer.C:
+	case err := <-done:
+		if err == nil {
+			err = fmt.Errorf("imap idle ended unexpectedly")
+		}
+		return err
+	}
+	close(stop)
+	return <-done
+}
+
+// processUnseen fetches unread messages, marks them as read and dispatches the ones addressed to the bot.
+func (a *EmailAdapter) processUnseen(ctx context.Context, cfg channel.ChannelConfig, emailCfg Config, c *client.Client, handler channel.InboundHandler) error {
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return fmt.Errorf("imap search: %w", err)
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	items := []imap.FetchItem{imap.FetchUid, imap.FetchInternalDate, section.FetchItem()}
+	messages := make(chan *imap.Message, imapFetchBuffer)
+	fetchDone := make(chan error, 1)
+	go func() {
+		fetchDone <- c.UidFetch(seqset, items, messages)
+	}()
+
+	// Commands cannot be issued while the fetch is streaming, so messages are collected first.
+	processed := new(imap.SeqSet)
+	var inbound []channel.InboundMessage
+	for m := range messages {
+		processed.AddNum(m.Uid)
+		body := m.GetBody(section)
+		if body == nil {
+			continue
+		}
+		parsed, err := parseEmail(body)
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Warn("parse email failed", slog.String("config_id", cfg.ID), slog.Any("uid", m.Uid), slog.Any("error", err))
+			}
+			continue
+		}
+		if parsed.MessageID == "" {
+			parsed.MessageID = fmt.Sprintf("%d.%d@%s", m.Uid, m.InternalDate.Unix(), emailCfg.IMAPHost)
+		}
+		msg, ok := a.buildInboundMessage(cfg, emailCfg, parsed, m.InternalDate)
+		if !ok {
+			continue
+		}
+		if a.isDuplicateInbound(cfg.ID, parsed.MessageID) {
+			continue
+		}
+		inbound = append(inbound, msg)
+	}
+	if err := <-fetchDone; err != nil {
+		return fmt.Errorf("imap fetch: %w", err)
+	}
+	if !processed.Empty() {
+		flags := []interface{}{imap.SeenFlag}
+		if err := c.UidStore(processed, imap.FormatFlagsOp(imap.AddFlags, true), flags, nil); err != nil {
+			return fmt.Errorf("imap mark seen: %w", err)
+		}
+	}
+	for _, msg := range inbound {
+		a.dispatchInbound(ctx, cfg, handler, msg)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package email
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "github.com/emersion/go-message/charset" // register non-UTF-8 charsets for MIME decoding
+	"github.com/emersion/go-message/mail"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+// parsedEmail is the subset of a MIME message used to build an inbound channel message.
+type parsedEmail struct {
+	MessageID     string
+	InReplyTo     []string
+	References    []string
+	Subject       string
+	From          *mail.Address
+	ReplyTo       *mail.Address
+	Date          time.Time
+	Text          string
+	HTML          string
+	Attachments   []channel.Attachment
+	AutoSubmitted bool
+}
+
+// threadID returns the root message of the conversation: the first References entry,
+// then In-Reply-To, and finally the message itself for a new thread.
+func (p parsedEmail) threadID() string {
+	if len(p.References) > 0 {
+		return p.References[0]
+	}
+	if len(p.InReplyTo) > 0 {
+		return p.InReplyTo[0]
+	}
+	return p.MessageID
+}
+
+// replyAddress returns the address replies should be sent to.
+func (p parsedEmail) replyAddress() *mail.Address {
+	if p.ReplyTo != nil {
+		return p.ReplyTo
+	}
+	return p.From
+}
+
+func parseEmail(r io.Reader) (parsedEmail, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil && mr == nil {
+		return parsedEmail{}, fmt.Errorf("read email: %w", err)
+	}
+	defer mr.Close()
+
+	var parsed parsedEmail
+	header := mr.Header
+	parsed.MessageID, _ = header.MessageID()
+	parsed.InReplyTo, _ = header.MsgIDList("In-Reply-To")
+	parsed.References, _ = header.MsgIDList("References")
+	parsed.Subject, _ = header.Subject()
+	parsed.Subject = strings.TrimSpace(parsed.Subject)
+	parsed.Date, _ = header.Date()
+	if from, err := header.AddressList("From"); err == nil && len(from) > 0 {
+		parsed.From = from[0]
+	}
+	if replyTo, err := header.AddressList("Reply-To"); err == nil && len(replyTo) > 0 {
+		parsed.ReplyTo = replyTo[0]
+	}
+	if value := strings.ToLower(strings.TrimSpace(header.Get("Auto-Submitted"))); value != "" && value != "no" {
+		parsed.AutoSubmitted = true
+	}
+	if strings.TrimSpace(header.Get("List-Id")) != "" || strings.EqualFold(strings.TrimSpace(header.Get("Precedence")), "bulk") {
+		parsed.AutoSubmitted = true
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return parsed, fmt.Errorf("read email part: %w", err)
+		}
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, params, _ := h.ContentType()
+			switch {
+			case contentType == "text/plain" && parsed.Text == "":
+				data, _ := io.ReadAll(io.LimitReader(part.Body, media.MaxAssetBytes))
+				parsed.Text = string(data)
+			case contentType == "text/html" && parsed.HTML == "":
+				data, _ := io.ReadAll(io.LimitReader(part.Body, media.MaxAssetBytes))
+				parsed.HTML = string(data)
+			case strings.HasPrefix(contentType, "text/"):
+				// Additional alternative bodies are ignored.
+			default:
+				name := params["name"]
+				if att, ok := readAttachment(part.Body, contentType, name); ok {
+					parsed.Attachments = append(parsed.Attachments, att)
+				}
+			}
+		case *mail.AttachmentHeader:
+			contentType, _, _ := h.ContentType()
+			name, _ := h.Filename()
+			if att, ok := readAttachment(part.Body, contentType, name); ok {
+				parsed.Attachments = append(parsed.Attachments, att)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// readAttachment buffers a MIME part into a base64 data URL attachment for ingestion.
+func readAttachment(body io.Reader, contentType, name string) (channel.Attachment, bool) {
+	data, err := io.ReadAll(io.LimitReader(body, media.MaxAssetBytes+1))
+	if err != nil || len(data) == 0 || int64(len(data)) > media.MaxAssetBytes {
+		return channel.Attachment{}, false
+	}
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			name = "attachment" + exts[0]
+		} else {
+			name = "attachment"
+		}
+	}
+	return channel.Attachment{
+		Type:           attachmentTypeFromMime(contentType),
+		Base64:         "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data),
+		SourcePlatform: Type.String(),
+		Name:           name,
+		Mime:           contentType,
+		Size:           int64(len(data)),
+	}, true
+}
+
+func attachmentTypeFromMime(contentType string) channel.AttachmentType {
+	switch {
+	case contentType == "image/gif":
+		return channel.AttachmentGIF
+	case strings.HasPrefix(contentType, "image/"):
+		return channel.AttachmentImage
+	case strings.HasPrefix(contentType, "audio/"):
+		return channel.AttachmentAudio
+	case strings.HasPrefix(contentType, "video/"):
+		return channel.AttachmentVideo
+	default:
+		return channel.AttachmentFile
+	}
+}
+
+// bodyText returns the plain-text body, deriving it from HTML when no text part exists,
+// with quoted reply history removed.
+func (p parsedEmail) bodyText() string {
+	text := p.Text
+	if strings.TrimSpace(text) == "" && p.HTML != "" {
+		text = htmlToText(p.HTML)
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if len(p.InReplyTo) > 0 || len(p.References) > 0 {
+		text = stripQuotedReply(text)
+	}
+	return strings.TrimSpace(text)
+}
+
+var (
+	reHTMLDrop      = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	reHTMLBreak     = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6]|blockquote)>`)
+	reHTMLTag       = regexp.MustCompile(`<[^>]+>`)
+	reBlankLines    = regexp.MustCompile(`\n{3,}`)
+	reQuoteHeaderEN = regexp.MustCompile(`^On .+wrote:\s*$`)
+)
+
+func htmlToText(value string) string {
+	value = reHTMLDrop.ReplaceAllString(value, "")
+	value = reHTMLBreak.ReplaceAllString(value, "\n")
+	value = reHTMLTag.ReplaceAllString(value, "")
+	value = html.UnescapeString(value)
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return reBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+// stripQuotedReply drops the quoted history that mail clients append below a reply.
+func stripQuotedReply(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if reQuoteHeaderEN.MatchString(trimmed) || trimmed == "-----Original Message-----" {
+			return strings.Join(lines[:i], "\n")
+		}
+		if strings.HasPrefix(trimmed, ">") && onlyQuotesRemain(lines[i:]) {
+			return strings.Join(lines[:i], "\n")
+		}
+	}
+	return text
+}
+
+func onlyQuotesRemain(lines []string) bool {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, ">") {
+			return false
+		}
+	}
+	return true
+}
+
+// replySubject prefixes a subject with "Re:" unless it already has one.
+func replySubject(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return ""
+	}
+	if len(subject) >= 3 && strings.EqualFold(subject[:3], "re:") {
+		return subject
+	}
+	return "Re: " + subject
+}
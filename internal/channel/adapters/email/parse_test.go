@@ -0,0 +1,191 @@
+package email
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const multipartReply = "From: Alice <alice@example.com>\r\n" +
+	"To: bot@example.com\r\n" +
+	"Subject: Re: Weekly report\r\n" +
+	"Date: Mon, 02 Mar 2026 10:00:00 +0000\r\n" +
+	"Message-ID: <reply-2@example.com>\r\n" +
+	"In-Reply-To: <bot-1@example.com>\r\n" +
+	"References: <root-0@example.com> <bot-1@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"alt\"\r\n" +
+	"\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Looks good, thanks!\r\n" +
+	"\r\n" +
+	"On Mon, Mar 2, 2026 at 9:00 AM Bot <bot@example.com> wrote:\r\n" +
+	"> Here is the report.\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Looks good, thanks!</p>\r\n" +
+	"--alt--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Disposition: attachment; filename=\"chart.png\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0KGgo=\r\n" +
+	"--outer--\r\n"
+
+func TestParseEmailMultipartReply(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := parseEmail(strings.NewReader(multipartReply))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.MessageID != "reply-2@example.com" {
+		t.Fatalf("unexpected message id %q", parsed.MessageID)
+	}
+	if parsed.threadID() != "root-0@example.com" {
+		t.Fatalf("expected thread root from references, got %q", parsed.threadID())
+	}
+	if got := parsed.bodyText(); got != "Looks good, thanks!" {
+		t.Fatalf("expected quoted history stripped, got %q", got)
+	}
+	if parsed.From == nil || parsed.From.Address != "alice@example.com" || parsed.From.Name != "Alice" {
+		t.Fatalf("unexpected from: %#v", parsed.From)
+	}
+	if len(parsed.Attachments) != 1 {
+		t.Fatalf("expected one attachment, got %d", len(parsed.Attachments))
+	}
+	att := parsed.Attachments[0]
+	if att.Type != channel.AttachmentImage || att.Name != "chart.png" || att.Mime != "image/png" {
+		t.Fatalf("unexpected attachment: %#v", att)
+	}
+	if !strings.HasPrefix(att.Base64, "data:image/png;base64,") || att.Size != 8 {
+		t.Fatalf("unexpected attachment payload: %#v", att)
+	}
+	if parsed.AutoSubmitted {
+		t.Fatal("expected personal mail not to be marked auto-submitted")
+	}
+}
+
+func TestParseEmailHTMLOnlyAndAutoSubmitted(t *testing.T) {
+	t.Parallel()
+
+	raw := "From: noreply@example.com\r\n" +
+		"Subject: Out of office\r\n" +
+		"Auto-Submitted: auto-replied\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"\r\n" +
+		"<html><head><style>p{}</style></head><body><p>I am away &amp; back Monday.</p><p>Regards</p></body></html>"
+	parsed, err := parseEmail(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !parsed.AutoSubmitted {
+		t.Fatal("expected auto-submitted mail to be flagged")
+	}
+	if got := parsed.bodyText(); got != "I am away & back Monday.\nRegards" {
+		t.Fatalf("unexpected html body text %q", got)
+	}
+	if parsed.threadID() != "" {
+		t.Fatalf("expected empty thread id without message id, got %q", parsed.threadID())
+	}
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Sure.\n\n-----Original Message-----\nFrom: bot": "Sure.\n",
+		"Yes\n> quoted\n> more":                          "Yes",
+		"> inline quote\nmy answer":                      "> inline quote\nmy answer",
+	}
+	for input, want := range cases {
+		if got := strings.TrimSpace(stripQuotedReply(input)); got != strings.TrimSpace(want) {
+			t.Fatalf("stripQuotedReply(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestReplySubject(t *testing.T) {
+	t.Parallel()
+
+	if got := replySubject("Hello"); got != "Re: Hello" {
+		t.Fatalf("unexpected subject %q", got)
+	}
+	if got := replySubject("RE: Hello"); got != "RE: Hello" {
+		t.Fatalf("expected existing prefix kept, got %q", got)
+	}
+	if got := replySubject(" "); got != "" {
+		t.Fatalf("expected empty subject, got %q", got)
+	}
+}
+
+func TestComposeEmailRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{FromAddress: "bot@example.com", FromName: "Memoh"}
+	raw, messageID, err := composeEmail(cfg, outgoingEmail{
+		To:         "alice@example.com",
+		Subject:    "Re: Weekly report",
+		Text:       "**Done**\n\n- item",
+		Markdown:   true,
+		InReplyTo:  "reply-2@example.com",
+		References: []string{"root-0@example.com", "reply-2@example.com"},
+		Attachments: []outgoingAttachment{
+			{Name: "notes.txt", Mime: "text/plain", Data: []byte("hello")},
+		},
+	}, time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("compose: %v", err)
+	}
+	if !strings.HasSuffix(messageID, "@example.com") {
+		t.Fatalf("unexpected message id %q", messageID)
+	}
+	for _, want := range []string{
+		"In-Reply-To: <reply-2@example.com>",
+		"References: <root-0@example.com> <reply-2@example.com>",
+		"Auto-Submitted: auto-replied",
+		"text/html",
+	} {
+		if !bytes.Contains(raw, []byte(want)) {
+			t.Fatalf("expected %q in composed message:\n%s", want, raw)
+		}
+	}
+
+	parsed, err := parseEmail(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse composed: %v", err)
+	}
+	if parsed.MessageID != messageID || parsed.Subject != "Re: Weekly report" {
+		t.Fatalf("unexpected round trip headers: %#v", parsed)
+	}
+	if !strings.Contains(parsed.HTML, "<strong>Done</strong>") {
+		t.Fatalf("expected rendered html, got %q", parsed.HTML)
+	}
+	if len(parsed.Attachments) != 1 || parsed.Attachments[0].Name != "notes.txt" {
+		t.Fatalf("unexpected attachments: %#v", parsed.Attachments)
+	}
+}
+
+func TestSubjectFromText(t *testing.T) {
+	t.Parallel()
+
+	if got := subjectFromText("# Daily summary\n\nbody"); got != "Daily summary" {
+		t.Fatalf("unexpected subject %q", got)
+	}
+	if got := subjectFromText(""); got != defaultSubject {
+		t.Fatalf("expected default subject, got %q", got)
+	}
+	if got := subjectFromText(strings.Repeat("a", 200)); len([]rune(got)) != maxGeneratedSubject {
+		t.Fatalf("expected truncated subject, got %d runes", len([]rune(got)))
+	}
+}
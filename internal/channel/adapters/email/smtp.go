@@ -0,0 +1,179 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-message/mail"
+
+	"github.com/memohai/memoh/internal/channel/render"
+)
+
+const smtpDialTimeout = 30 * time.Second
+
+// outgoingEmail is a composed reply ready for SMTP delivery.
+type outgoingEmail struct {
+	To          string
+	Subject     string
+	Text        string
+	Markdown    bool
+	InReplyTo   string
+	References  []string
+	Attachments []outgoingAttachment
+}
+
+type outgoingAttachment struct {
+	Name string
+	Mime string
+	Data []byte
+}
+
+// composeEmail renders the message as multipart MIME and returns the raw bytes and generated Message-ID.
+// Markdown bodies are sent as multipart/alternative with an HTML rendering.
+func composeEmail(cfg Config, out outgoingEmail, now time.Time) ([]byte, string, error) {
+	var header mail.Header
+	header.SetDate(now)
+	header.SetAddressList("From", []*mail.Address{{Name: cfg.FromName, Address: cfg.FromAddress}})
+	header.SetAddressList("To", []*mail.Address{{Address: out.To}})
+	header.SetSubject(out.Subject)
+	messageID := generateMessageID(cfg.FromAddress)
+	header.SetMessageID(messageID)
+	if out.InReplyTo != "" {
+		header.SetMsgIDList("In-Reply-To", []string{out.InReplyTo})
+		header.Set("Auto-Submitted", "auto-replied")
+	}
+	if len(out.References) > 0 {
+		header.SetMsgIDList("References", out.References)
+	}
+
+	var buf bytes.Buffer
+	mw, err := mail.CreateWriter(&buf, header)
+	if err != nil {
+		return nil, "", err
+	}
+	iw, err := mw.CreateInline()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := writeInlinePart(iw, "text/plain", out.Text); err != nil {
+		return nil, "", err
+	}
+	if out.Markdown {
+		if formatted := render.HTML(render.Parse(out.Text)); formatted != "" {
+			if err := writeInlinePart(iw, "text/html", formatted); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := iw.Close(); err != nil {
+		return nil, "", err
+	}
+	for _, att := range out.Attachments {
+		var ah mail.AttachmentHeader
+		ah.Set("Content-Type", att.Mime)
+		ah.SetFilename(att.Name)
+		w, err := mw.CreateAttachment(ah)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := w.Write(att.Data); err != nil {
+			return nil, "", err
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), messageID, nil
+}
+
+func writeInlinePart(iw *mail.InlineWriter, contentType, body string) error {
+	var h mail.InlineHeader
+	h.Set("Content-Type", contentType+"; charset=utf-8")
+	w, err := iw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, body); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func generateMessageID(from string) string {
+	domain := "localhost"
+	if idx := strings.LastIndex(from, "@"); idx >= 0 && idx < len(from)-1 {
+		domain = from[idx+1:]
+	}
+	nonce := make([]byte, 12)
+	_, _ = rand.Read(nonce)
+	return fmt.Sprintf("%d.%s@%s", time.Now().UnixNano(), hex.EncodeToString(nonce), domain)
+}
+
+// sendSMTP delivers a raw message using the configured transport security.
+func sendSMTP(ctx context.Context, cfg Config, to string, raw []byte) error {
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	tlsConfig := &tls.Config{ServerName: cfg.SMTPHost, MinVersion: tls.VersionTLS12}
+	if cfg.SMTPSecurity == securityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", cfg.smtpAddr())
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", cfg.smtpAddr())
+	}
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(2 * time.Minute))
+	}
+	client, err := smtp.NewClient(conn, cfg.SMTPHost)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+	if cfg.SMTPSecurity == securitySTARTTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && cfg.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(cfg.FromAddress); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data close: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,124 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// emailOutboundStream buffers deltas and sends the whole reply as one e-mail on the final event,
+// since mail cannot be edited after delivery.
+type emailOutboundStream struct {
+	adapter     *EmailAdapter
+	cfg         channel.ChannelConfig
+	emailCfg    Config
+	to          string
+	reply       *channel.ReplyRef
+	thread      *channel.ThreadRef
+	closed      atomic.Bool
+	mu          sync.Mutex
+	buf         strings.Builder
+	attachments []channel.Attachment
+	sent        bool
+}
+
+func (s *emailOutboundStream) flush(ctx context.Context, text string, format channel.MessageFormat, attachments []channel.Attachment) error {
+	s.mu.Lock()
+	if s.sent {
+		s.mu.Unlock()
+		return nil
+	}
+	s.sent = true
+	s.mu.Unlock()
+	text = strings.TrimSpace(text)
+	if text == "" && len(attachments) == 0 {
+		return nil
+	}
+	msg := channel.Message{
+		Format:      format,
+		Text:        text,
+		Attachments: attachments,
+		Thread:      s.thread,
+		Reply:       s.reply,
+	}
+	_, err := s.adapter.sendMessage(ctx, s.cfg, s.emailCfg, s.to, msg)
+	return err
+}
+
+func (s *emailOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil {
+		return fmt.Errorf("email stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("email stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch event.Type {
+	case channel.StreamEventToolCallStart:
+		s.mu.Lock()
+		if s.buf.Len() > 0 && !strings.HasSuffix(s.buf.String(), "\n\n") {
+			s.buf.WriteString("\n\n")
+		}
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventAttachment:
+		s.mu.Lock()
+		s.attachments = append(s.attachments, event.Attachments...)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.buf.WriteString(event.Delta)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		text := s.buf.String()
+		attachments := append([]channel.Attachment(nil), s.attachments...)
+		s.mu.Unlock()
+		format := channel.MessageFormatMarkdown
+		if event.Final != nil && !event.Final.Message.IsEmpty() {
+			msg := event.Final.Message
+			if strings.TrimSpace(text) == "" {
+				text = msg.PlainText()
+			}
+			if msg.Format != "" {
+				format = msg.Format
+			}
+			attachments = append(attachments, msg.Attachments...)
+		}
+		return s.flush(ctx, text, format, attachments)
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		return s.flush(ctx, "Error: "+errText, channel.MessageFormatPlain, nil)
+	default:
+		return nil
+	}
+}
+
+func (s *emailOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	s.closed.Store(true)
+	return nil
+}
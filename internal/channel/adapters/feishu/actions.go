@@ -0,0 +1,159 @@
+package feishu
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher/callback"
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Keys of the button value object echoed back in card.action.trigger callbacks.
+const (
+	feishuActionValueKey    = "action"
+	feishuActionLabelKey    = "label"
+	feishuActionChatTypeKey = "chat_type"
+)
+
+// feishuReceiveChatType infers the conversation type of a delivery target. Inbound direct
+// messages reply to open_id/user_id, so chat_id targets are treated as group chats.
+func feishuReceiveChatType(receiveType string) string {
+	if receiveType == larkim.ReceiveIdTypeChatId {
+		return "group"
+	}
+	return "p2p"
+}
+
+// feishuCardActionElements renders message actions as a card action element. Link actions open
+// their URL; other actions carry their value, label and the chat type so the callback can be
+// routed like an ordinary inbound message.
+func feishuCardActionElements(actions []channel.Action, chatType string) []map[string]any {
+	buttons := make([]map[string]any, 0, len(actions))
+	for _, action := range actions {
+		label := strings.TrimSpace(action.Label)
+		link := strings.TrimSpace(action.URL)
+		value := strings.TrimSpace(action.Value)
+		if label == "" {
+			label = value
+		}
+		if label == "" {
+			label = link
+		}
+		if label == "" {
+			continue
+		}
+		button := map[string]any{
+			"tag":  "button",
+			"text": map[string]any{"tag": "plain_text", "content": label},
+		}
+		if link != "" {
+			button["type"] = "default"
+			button["url"] = link
+		} else {
+			if value == "" {
+				value = label
+			}
+			button["type"] = "primary"
+			button["value"] = map[string]any{
+				feishuActionValueKey:    value,
+				feishuActionLabelKey:    label,
+				feishuActionChatTypeKey: chatType,
+			}
+		}
+		buttons = append(buttons, button)
+	}
+	if len(buttons) == 0 {
+		return nil
+	}
+	return []map[string]any{{"tag": "action", "actions": buttons}}
+}
+
+// extractFeishuCardAction maps a card button callback to an action event.
+func extractFeishuCardAction(event *callback.CardActionTriggerEvent) (channel.InboundMessage, bool) {
+	if event == nil || event.Event == nil || event.Event.Action == nil || event.Event.Operator == nil || event.Event.Context == nil {
+		return channel.InboundMessage{}, false
+	}
+	req := event.Event
+	value := strings.TrimSpace(feishuActionString(req.Action.Value, feishuActionValueKey))
+	if value == "" {
+		return channel.InboundMessage{}, false
+	}
+	chatID := strings.TrimSpace(req.Context.OpenChatID)
+	openID := strings.TrimSpace(req.Operator.OpenID)
+	userID := ""
+	if req.Operator.UserID != nil {
+		userID = strings.TrimSpace(*req.Operator.UserID)
+	}
+	subjectID := openID
+	if subjectID == "" {
+		subjectID = userID
+	}
+	if subjectID == "" {
+		return channel.InboundMessage{}, false
+	}
+	attrs := map[string]string{}
+	if userID != "" {
+		attrs["user_id"] = userID
+	}
+	if openID != "" {
+		attrs["open_id"] = openID
+	}
+	chatType := strings.TrimSpace(feishuActionString(req.Action.Value, feishuActionChatTypeKey))
+	if chatType == "" {
+		chatType = "p2p"
+	}
+	replyTo := subjectID
+	if chatType != "p2p" && chatID != "" {
+		replyTo = "chat_id:" + chatID
+	}
+	messageID := strings.TrimSpace(req.Context.OpenMessageID)
+	eventID := ""
+	if event.EventV2Base != nil && event.EventV2Base.Header != nil {
+		eventID = strings.TrimSpace(event.EventV2Base.Header.EventID)
+	}
+	if eventID == "" {
+		eventID = messageID + ":" + subjectID + ":" + value
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Event:   channel.InboundEventAction,
+		Message: channel.Message{ID: "card_action:" + eventID},
+		Action: &channel.ActionEvent{
+			Value:     value,
+			Label:     strings.TrimSpace(feishuActionString(req.Action.Value, feishuActionLabelKey)),
+			MessageID: messageID,
+		},
+		ReplyTarget: replyTo,
+		Sender: channel.Identity{
+			SubjectID:  subjectID,
+			Attributes: attrs,
+		},
+		Conversation: channel.Conversation{
+			ID:   chatID,
+			Type: chatType,
+		},
+		ReceivedAt: time.Now().UTC(),
+		Source:     "feishu",
+	}, true
+}
+
+func feishuActionString(value map[string]any, key string) string {
+	if value == nil {
+		return ""
+	}
+	switch v := value[key].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	}
+}
@@ -0,0 +1,58 @@
+package feishu
+
+import (
+	"encoding/json"
+	"testing"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestFeishuCardActionElements(t *testing.T) {
+	t.Parallel()
+
+	elements := feishuCardActionElements([]channel.Action{
+		{Label: "Yes", Value: "yes"},
+		{Label: "Docs", URL: "https://example.com"},
+		{},
+	}, feishuReceiveChatType(larkim.ReceiveIdTypeChatId))
+	if len(elements) != 1 {
+		t.Fatalf("expected one action element, got %d", len(elements))
+	}
+	buttons := elements[0]["actions"].([]map[string]any)
+	if len(buttons) != 2 {
+		t.Fatalf("expected two buttons, got %d", len(buttons))
+	}
+	value := buttons[0]["value"].(map[string]any)
+	if value[feishuActionValueKey] != "yes" || value[feishuActionChatTypeKey] != "group" {
+		t.Fatalf("unexpected button value: %#v", value)
+	}
+	if buttons[1]["url"] != "https://example.com" || buttons[1]["value"] != nil {
+		t.Fatalf("unexpected link button: %#v", buttons[1])
+	}
+	if feishuCardActionElements(nil, "p2p") != nil {
+		t.Fatal("expected no elements without actions")
+	}
+}
+
+func TestBuildFeishuStreamCardContentWithButtons(t *testing.T) {
+	t.Parallel()
+
+	payload, err := buildFeishuStreamCardContent("Pick one", feishuCardActionElements([]channel.Action{{Label: "A", Value: "a"}}, "p2p")...)
+	if err != nil {
+		t.Fatalf("build card: %v", err)
+	}
+	var card struct {
+		Elements []struct {
+			Tag     string           `json:"tag"`
+			Actions []map[string]any `json:"actions"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal([]byte(payload), &card); err != nil {
+		t.Fatalf("decode card: %v", err)
+	}
+	if len(card.Elements) != 2 || card.Elements[1].Tag != "action" || len(card.Elements[1].Actions) != 1 {
+		t.Fatalf("unexpected card elements: %s", payload)
+	}
+}
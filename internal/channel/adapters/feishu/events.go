@@ -0,0 +1,117 @@
+package feishu
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// extractFeishuReactionCreated maps an im.message.reaction.created_v1 event to a reaction_added event.
+func extractFeishuReactionCreated(event *larkim.P2MessageReactionCreatedV1) (channel.InboundMessage, bool) {
+	if event == nil || event.Event == nil {
+		return channel.InboundMessage{}, false
+	}
+	e := event.Event
+	return buildFeishuReactionInbound(e.MessageId, e.ReactionType, e.OperatorType, e.UserId, e.ActionTime, false)
+}
+
+// extractFeishuReactionDeleted maps an im.message.reaction.deleted_v1 event to a reaction_removed event.
+func extractFeishuReactionDeleted(event *larkim.P2MessageReactionDeletedV1) (channel.InboundMessage, bool) {
+	if event == nil || event.Event == nil {
+		return channel.InboundMessage{}, false
+	}
+	e := event.Event
+	return buildFeishuReactionInbound(e.MessageId, e.ReactionType, e.OperatorType, e.UserId, e.ActionTime, true)
+}
+
+// buildFeishuReactionInbound builds a reaction event. Reactions made by apps, including the
+// processing status reactions of this adapter, are ignored.
+func buildFeishuReactionInbound(messageID *string, reaction *larkim.Emoji, operatorType *string, user *larkim.UserId, actionTime *string, removed bool) (channel.InboundMessage, bool) {
+	if !strings.EqualFold(feishuString(operatorType), "user") || user == nil {
+		return channel.InboundMessage{}, false
+	}
+	id := feishuString(messageID)
+	emoji := ""
+	if reaction != nil {
+		emoji = feishuString(reaction.EmojiType)
+	}
+	if id == "" || emoji == "" {
+		return channel.InboundMessage{}, false
+	}
+	openID := feishuString(user.OpenId)
+	userID := feishuString(user.UserId)
+	subjectID := openID
+	if subjectID == "" {
+		subjectID = userID
+	}
+	if subjectID == "" {
+		return channel.InboundMessage{}, false
+	}
+	attrs := map[string]string{}
+	if userID != "" {
+		attrs["user_id"] = userID
+	}
+	if openID != "" {
+		attrs["open_id"] = openID
+	}
+	event := channel.InboundEventReactionAdded
+	if removed {
+		event = channel.InboundEventReactionRemoved
+	}
+	return channel.InboundMessage{
+		Channel:     Type,
+		Event:       event,
+		Reaction:    &channel.ReactionEvent{Emoji: emoji, MessageID: id},
+		ReplyTarget: subjectID,
+		Sender: channel.Identity{
+			SubjectID:  subjectID,
+			Attributes: attrs,
+		},
+		ReceivedAt: feishuMillisTime(feishuString(actionTime)),
+		Source:     "feishu",
+	}, true
+}
+
+// extractFeishuRecalled maps an im.message.recalled_v1 event to a message_deleted event.
+func extractFeishuRecalled(event *larkim.P2MessageRecalledV1) (channel.InboundMessage, bool) {
+	if event == nil || event.Event == nil {
+		return channel.InboundMessage{}, false
+	}
+	id := feishuString(event.Event.MessageId)
+	if id == "" {
+		return channel.InboundMessage{}, false
+	}
+	chatID := feishuString(event.Event.ChatId)
+	replyTo := ""
+	if chatID != "" {
+		replyTo = "chat_id:" + chatID
+	}
+	return channel.InboundMessage{
+		Channel:      Type,
+		Event:        channel.InboundEventMessageDeleted,
+		Message:      channel.Message{ID: id},
+		ReplyTarget:  replyTo,
+		Conversation: channel.Conversation{ID: chatID},
+		ReceivedAt:   feishuMillisTime(feishuString(event.Event.RecallTime)),
+		Source:       "feishu",
+	}, true
+}
+
+func feishuString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
+
+// feishuMillisTime parses a millisecond timestamp string, falling back to now.
+func feishuMillisTime(raw string) time.Time {
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil && ms > 0 {
+		return time.UnixMilli(ms).UTC()
+	}
+	return time.Now().UTC()
+}
@@ -0,0 +1,56 @@
+package feishu
+
+import (
+	"testing"
+
+	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func strPtr(value string) *string { return &value }
+
+func TestExtractFeishuReactionCreated(t *testing.T) {
+	t.Parallel()
+
+	event := &larkim.P2MessageReactionCreatedV1{Event: &larkim.P2MessageReactionCreatedV1Data{
+		MessageId:    strPtr("om_1"),
+		ReactionType: &larkim.Emoji{EmojiType: strPtr("THUMBSUP")},
+		OperatorType: strPtr("user"),
+		UserId:       &larkim.UserId{OpenId: strPtr("ou_1")},
+		ActionTime:   strPtr("1700000000000"),
+	}}
+	msg, ok := extractFeishuReactionCreated(event)
+	if !ok {
+		t.Fatal("expected reaction event")
+	}
+	if msg.Event != channel.InboundEventReactionAdded || msg.Reaction.Emoji != "THUMBSUP" || msg.Reaction.MessageID != "om_1" {
+		t.Fatalf("unexpected reaction event: %+v", msg)
+	}
+	if msg.Sender.SubjectID != "ou_1" || msg.ReceivedAt.UnixMilli() != 1700000000000 {
+		t.Fatalf("unexpected sender or time: %+v", msg)
+	}
+
+	event.Event.OperatorType = strPtr("app")
+	if _, ok := extractFeishuReactionCreated(event); ok {
+		t.Fatal("app reactions must be ignored")
+	}
+}
+
+func TestExtractFeishuRecalled(t *testing.T) {
+	t.Parallel()
+
+	msg, ok := extractFeishuRecalled(&larkim.P2MessageRecalledV1{Event: &larkim.P2MessageRecalledV1Data{
+		MessageId: strPtr("om_2"),
+		ChatId:    strPtr("oc_1"),
+	}})
+	if !ok || msg.Event != channel.InboundEventMessageDeleted || msg.Message.ID != "om_2" {
+		t.Fatalf("unexpected recall event: %+v", msg)
+	}
+	if msg.Conversation.ID != "oc_1" || msg.ReplyTarget != "chat_id:oc_1" {
+		t.Fatalf("unexpected conversation: %+v", msg)
+	}
+	if _, ok := extractFeishuRecalled(&larkim.P2MessageRecalledV1{}); ok {
+		t.Fatal("empty events must be ignored")
+	}
+}
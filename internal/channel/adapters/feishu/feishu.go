@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,12 +16,14 @@ import (
 	lark "github.com/larksuite/oapi-sdk-go/v3"
 	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher/callback"
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 	larkws "github.com/larksuite/oapi-sdk-go/v3/ws"
 
 	attachmentpkg "github.com/memohai/memoh/internal/attachment"
 	"github.com/memohai/memoh/internal/channel"
 	"github.com/memohai/memoh/internal/channel/adapters/common"
+	"github.com/memohai/memoh/internal/channel/render"
 	"github.com/memohai/memoh/internal/media"
 )
 
@@ -137,6 +140,14 @@ func (a *FeishuAdapter) Descriptor() channel.Descriptor {
 			Reply:          true,
 			Streaming:      true,
 			BlockStreaming: true,
+			Buttons:        true,
+			Edit:           true,
+			Unsend:         true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			// Feishu allows 5 messages per second to the same chat.
+			RateLimitPerSecond: 5,
+			RateLimitBurst:     5,
 		},
 		ConfigSchema: channel.ConfigSchema{
 			Version: 2,
@@ -453,15 +464,56 @@ func (a *FeishuAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig,
 			}()
 			return nil
 		})
+		eventDispatcher.OnP2CardActionTrigger(func(_ context.Context, event *callback.CardActionTriggerEvent) (*callback.CardActionTriggerResponse, error) {
+			if connCtx.Err() != nil {
+				return nil, nil
+			}
+			msg, ok := extractFeishuCardAction(event)
+			if !ok {
+				return nil, nil
+			}
+			msg.BotID = cfg.BotID
+			if a.logger != nil {
+				a.logger.Info(
+					"action received",
+					slog.String("config_id", cfg.ID),
+					slog.String("message_id", msg.Action.MessageID),
+					slog.String("route_key", msg.RoutingKey()),
+					slog.String("value", common.SummarizeText(msg.Action.Value)),
+				)
+			}
+			go func() {
+				if err := handler(connCtx, cfg, msg); err != nil && a.logger != nil {
+					a.logger.Error("handle action failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+				}
+			}()
+			return nil, nil
+		})
 		eventDispatcher.OnP2MessageReadV1(func(_ context.Context, _ *larkim.P2MessageReadV1) error {
 			return nil
 		})
-		// Ignore reaction lifecycle events explicitly to avoid SDK "not found handler" noise logs.
-		// These events are expected because the adapter uses reactions for processing status.
-		eventDispatcher.OnP2MessageReactionCreatedV1(func(_ context.Context, _ *larkim.P2MessageReactionCreatedV1) error {
+		dispatchHistory := func(msg channel.InboundMessage, ok bool) {
+			if !ok || connCtx.Err() != nil {
+				return
+			}
+			msg.BotID = cfg.BotID
+			go func() {
+				if err := handler(connCtx, cfg, msg); err != nil && a.logger != nil {
+					a.logger.Error("handle history update failed", slog.String("config_id", cfg.ID), slog.String("event", string(msg.Event)), slog.Any("error", err))
+				}
+			}()
+		}
+		// Reactions of the adapter itself (processing status) are filtered by the extractors.
+		eventDispatcher.OnP2MessageReactionCreatedV1(func(_ context.Context, event *larkim.P2MessageReactionCreatedV1) error {
+			dispatchHistory(extractFeishuReactionCreated(event))
+			return nil
+		})
+		eventDispatcher.OnP2MessageReactionDeletedV1(func(_ context.Context, event *larkim.P2MessageReactionDeletedV1) error {
+			dispatchHistory(extractFeishuReactionDeleted(event))
 			return nil
 		})
-		eventDispatcher.OnP2MessageReactionDeletedV1(func(_ context.Context, _ *larkim.P2MessageReactionDeletedV1) error {
+		eventDispatcher.OnP2MessageRecalledV1(func(_ context.Context, event *larkim.P2MessageRecalledV1) error {
+			dispatchHistory(extractFeishuRecalled(event))
 			return nil
 		})
 		return larkws.NewClient(
@@ -528,7 +580,7 @@ func (a *FeishuAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg
 
 	if len(msg.Message.Attachments) > 0 {
 		for _, att := range msg.Message.Attachments {
-			if err := a.sendAttachment(ctx, client, receiveID, receiveType, cfg.BotID, att); err != nil {
+			if err := a.sendAttachment(ctx, client, msg.Target, receiveID, receiveType, cfg.BotID, att); err != nil {
 				return err
 			}
 		}
@@ -538,7 +590,19 @@ func (a *FeishuAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg
 	var msgType string
 	var content string
 
-	if len(msg.Message.Parts) > 1 {
+	if buttons := feishuCardActionElements(msg.Message.Actions, feishuReceiveChatType(receiveType)); len(buttons) > 0 {
+		msgType = larkim.MsgTypeInteractive
+		cardContent, cardErr := buildFeishuStreamCardContent(strings.TrimSpace(msg.Message.PlainText()), buttons...)
+		if cardErr != nil {
+			return cardErr
+		}
+		content = cardContent
+	} else if msg.Message.Format == channel.MessageFormatMarkdown && len(msg.Message.Parts) <= 1 && strings.TrimSpace(msg.Message.PlainText()) != "" {
+		msgType, content, err = buildFeishuMarkdownContent(strings.TrimSpace(msg.Message.PlainText()))
+		if err != nil {
+			return err
+		}
+	} else if len(msg.Message.Parts) > 1 {
 		msgType = larkim.MsgTypePost
 		postContent, postErr := a.buildPostContent(msg.Message)
 		if postErr != nil {
@@ -579,11 +643,11 @@ func (a *FeishuAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg
 				Build()).
 			Build()
 		resp, err := client.Im.V1.Message.Reply(ctx, replyReq)
-		return a.handleReplyResponse(cfg.ID, resp, err)
+		return a.handleReplyResponse(ctx, cfg.ID, msg.Target, resp, err)
 	}
 
 	resp, err := client.Im.V1.Message.Create(ctx, req)
-	return a.handleResponse(cfg.ID, resp, err)
+	return a.handleResponse(ctx, cfg.ID, msg.Target, resp, err)
 }
 
 // OpenStream opens a Feishu streaming session.
@@ -619,7 +683,7 @@ func (a *FeishuAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfi
 	}, nil
 }
 
-func (a *FeishuAdapter) handleReplyResponse(configID string, resp *larkim.ReplyMessageResp, err error) error {
+func (a *FeishuAdapter) handleReplyResponse(ctx context.Context, configID, target string, resp *larkim.ReplyMessageResp, err error) error {
 	if err != nil {
 		if a.logger != nil {
 			a.logger.Error("reply failed", slog.String("config_id", configID), slog.Any("error", err))
@@ -636,15 +700,22 @@ func (a *FeishuAdapter) handleReplyResponse(configID string, resp *larkim.ReplyM
 		if a.logger != nil {
 			a.logger.Error("reply failed", slog.String("config_id", configID), slog.Int("code", code), slog.String("msg", msg))
 		}
-		return fmt.Errorf("feishu reply failed: %s (code: %d)", msg, code)
+		err := fmt.Errorf("feishu reply failed: %s (code: %d)", msg, code)
+		if resp != nil {
+			return feishuRateLimitError(resp.ApiResp, code, err)
+		}
+		return err
 	}
 	if a.logger != nil {
 		a.logger.Info("reply success", slog.String("config_id", configID))
 	}
+	if resp.Data != nil && resp.Data.MessageId != nil {
+		channel.ReportSentMessage(ctx, target, *resp.Data.MessageId)
+	}
 	return nil
 }
 
-func (a *FeishuAdapter) handleResponse(configID string, resp *larkim.CreateMessageResp, err error) error {
+func (a *FeishuAdapter) handleResponse(ctx context.Context, configID, target string, resp *larkim.CreateMessageResp, err error) error {
 	if err != nil {
 		if a.logger != nil {
 			a.logger.Error("send failed", slog.String("config_id", configID), slog.Any("error", err))
@@ -661,15 +732,22 @@ func (a *FeishuAdapter) handleResponse(configID string, resp *larkim.CreateMessa
 		if a.logger != nil {
 			a.logger.Error("send failed", slog.String("config_id", configID), slog.Int("code", code), slog.String("msg", msg))
 		}
-		return fmt.Errorf("feishu send failed: %s (code: %d)", msg, code)
+		err := fmt.Errorf("feishu send failed: %s (code: %d)", msg, code)
+		if resp != nil {
+			return feishuRateLimitError(resp.ApiResp, code, err)
+		}
+		return err
 	}
 	if a.logger != nil {
 		a.logger.Info("send success", slog.String("config_id", configID))
 	}
+	if resp.Data != nil && resp.Data.MessageId != nil {
+		channel.ReportSentMessage(ctx, target, *resp.Data.MessageId)
+	}
 	return nil
 }
 
-func (a *FeishuAdapter) sendAttachment(ctx context.Context, client *lark.Client, receiveID, receiveType, botID string, att channel.Attachment) error {
+func (a *FeishuAdapter) sendAttachment(ctx context.Context, client *lark.Client, target, receiveID, receiveType, botID string, att channel.Attachment) error {
 	var msgType string
 	var contentMap map[string]string
 	sourcePlatform := strings.TrimSpace(att.SourcePlatform)
@@ -758,7 +836,7 @@ func (a *FeishuAdapter) sendAttachment(ctx context.Context, client *lark.Client,
 		Build()
 
 	sendResp, err := client.Im.V1.Message.Create(ctx, req)
-	return a.handleResponse("", sendResp, err)
+	return a.handleResponse(ctx, "", target, sendResp, err)
 }
 
 func (a *FeishuAdapter) resolveAttachmentUploadReader(ctx context.Context, att channel.Attachment, fallbackBotID string) (io.ReadCloser, string, string, error) {
@@ -925,6 +1003,18 @@ func resolveFeishuFileType(name, mime string) string {
 	}
 }
 
+// buildFeishuMarkdownContent renders markdown as a post message, or as an interactive
+// card when it has tables, which only cards can display.
+func buildFeishuMarkdownContent(text string) (string, string, error) {
+	doc := render.Parse(text)
+	if doc.HasTable() {
+		content, err := buildFeishuStreamCardContent(text)
+		return larkim.MsgTypeInteractive, content, err
+	}
+	content, err := render.FeishuPost(doc)
+	return larkim.MsgTypePost, content, err
+}
+
 func (a *FeishuAdapter) buildPostContent(msg channel.Message) (string, error) {
 	type postContent struct {
 		ZhCn struct {
@@ -1017,3 +1107,81 @@ func (a *FeishuAdapter) buildPostContent(msg channel.Message) (string, error) {
 	payload, err := json.Marshal(pc)
 	return string(payload), err
 }
+
+// Feishu error codes for request and message frequency limits.
+const (
+	feishuCodeRateLimited       = 99991400
+	feishuCodeMessageRateLimits = 230020
+)
+
+// feishuRateLimitError marks frequency limit failures so the channel manager
+// waits for the reset time the gateway reports before retrying.
+func feishuRateLimitError(apiResp *larkcore.ApiResp, code int, err error) error {
+	if code != feishuCodeRateLimited && code != feishuCodeMessageRateLimits {
+		return err
+	}
+	retryAfter := time.Second
+	if apiResp != nil {
+		if seconds, parseErr := strconv.Atoi(strings.TrimSpace(apiResp.Header.Get("x-ogw-ratelimit-reset"))); parseErr == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return channel.NewRateLimitError(err, retryAfter)
+}
+
+// Update replaces the content of a message the bot sent earlier (implements channel.MessageEditor).
+// Streamed replies are interactive cards and are patched; plain text messages are edited in place.
+func (a *FeishuAdapter) Update(ctx context.Context, cfg channel.ChannelConfig, _ string, messageID string, msg channel.Message) error {
+	feishuCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(msg.PlainText())
+	if text == "" {
+		return fmt.Errorf("feishu edit requires text")
+	}
+	client := lark.NewClient(feishuCfg.AppID, feishuCfg.AppSecret, lark.WithOpenBaseUrl(feishuCfg.openBaseURL()))
+	cardContent, err := buildFeishuStreamCardContent(text)
+	if err != nil {
+		return err
+	}
+	patchResp, err := client.Im.V1.Message.Patch(ctx, larkim.NewPatchMessageReqBuilder().
+		MessageId(messageID).
+		Body(larkim.NewPatchMessageReqBodyBuilder().Content(cardContent).Build()).
+		Build())
+	if err == nil && patchResp != nil && patchResp.Success() {
+		return nil
+	}
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal text content: %w", err)
+	}
+	resp, err := client.Im.V1.Message.Update(ctx, larkim.NewUpdateMessageReqBuilder().
+		MessageId(messageID).
+		Body(larkim.NewUpdateMessageReqBodyBuilder().MsgType(larkim.MsgTypeText).Content(string(payload)).Build()).
+		Build())
+	if err != nil {
+		return err
+	}
+	if !resp.Success() {
+		return feishuRateLimitError(resp.ApiResp, resp.Code, fmt.Errorf("feishu edit failed: %s (code: %d)", resp.Msg, resp.Code))
+	}
+	return nil
+}
+
+// Unsend recalls a message the bot sent earlier (implements channel.MessageEditor).
+func (a *FeishuAdapter) Unsend(ctx context.Context, cfg channel.ChannelConfig, _ string, messageID string) error {
+	feishuCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	client := lark.NewClient(feishuCfg.AppID, feishuCfg.AppSecret, lark.WithOpenBaseUrl(feishuCfg.openBaseURL()))
+	resp, err := client.Im.V1.Message.Delete(ctx, larkim.NewDeleteMessageReqBuilder().MessageId(messageID).Build())
+	if err != nil {
+		return err
+	}
+	if !resp.Success() {
+		return feishuRateLimitError(resp.ApiResp, resp.Code, fmt.Errorf("feishu recall failed: %s (code: %d)", resp.Msg, resp.Code))
+	}
+	return nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 
 	"github.com/memohai/memoh/internal/channel"
@@ -763,3 +766,20 @@ func TestFeishuProcessingCompletedNoopWithoutToken(t *testing.T) {
 		t.Fatalf("expected no error for empty token, got: %v", err)
 	}
 }
+
+func TestFeishuRateLimitError(t *testing.T) {
+	t.Parallel()
+
+	base := errors.New("feishu send failed")
+	apiResp := &larkcore.ApiResp{Header: http.Header{}}
+	apiResp.Header.Set("x-ogw-ratelimit-reset", "3")
+	if got := channel.RetryAfterHint(feishuRateLimitError(apiResp, feishuCodeRateLimited, base)); got != 3*time.Second {
+		t.Fatalf("expected reset header to be honored, got %v", got)
+	}
+	if got := channel.RetryAfterHint(feishuRateLimitError(nil, feishuCodeMessageRateLimits, base)); got != time.Second {
+		t.Fatalf("expected default retry-after, got %v", got)
+	}
+	if got := feishuRateLimitError(apiResp, 230001, base); got != base {
+		t.Fatalf("other codes must pass through, got %v", got)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 
 	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/render"
 )
 
 const (
@@ -114,7 +115,8 @@ func (s *feishuOutboundStream) Push(ctx context.Context, event channel.StreamEve
 			if err := s.ensureCard(ctx, feishuStreamThinkingText); err != nil {
 				return err
 			}
-			if err := s.patchCard(ctx, finalText); err != nil {
+			buttons := feishuCardActionElements(msg.Actions, feishuReceiveChatType(s.receiveType))
+			if err := s.patchCard(ctx, finalText, buttons...); err != nil {
 				return err
 			}
 		}
@@ -193,6 +195,7 @@ func (s *feishuOutboundStream) ensureCard(ctx context.Context, text string) erro
 			return fmt.Errorf("feishu stream reply failed: empty message id")
 		}
 		s.cardMessageID = strings.TrimSpace(*replyResp.Data.MessageId)
+		channel.ReportSentMessage(ctx, s.target, s.cardMessageID)
 		s.lastPatched = normalizeFeishuStreamText(text)
 		s.lastPatchedAt = time.Now()
 		return nil
@@ -221,20 +224,21 @@ func (s *feishuOutboundStream) ensureCard(ctx context.Context, text string) erro
 		return fmt.Errorf("feishu stream create failed: empty message id")
 	}
 	s.cardMessageID = strings.TrimSpace(*createResp.Data.MessageId)
+	channel.ReportSentMessage(ctx, s.target, s.cardMessageID)
 	s.lastPatched = normalizeFeishuStreamText(text)
 	s.lastPatchedAt = time.Now()
 	return nil
 }
 
-func (s *feishuOutboundStream) patchCard(ctx context.Context, text string) error {
+func (s *feishuOutboundStream) patchCard(ctx context.Context, text string, extraElements ...map[string]any) error {
 	if strings.TrimSpace(s.cardMessageID) == "" {
 		return fmt.Errorf("feishu stream card message not initialized")
 	}
 	contentText := normalizeFeishuStreamText(text)
-	if contentText == s.lastPatched {
+	if contentText == s.lastPatched && len(extraElements) == 0 {
 		return nil
 	}
-	content, err := buildFeishuStreamCardContent(contentText)
+	content, err := buildFeishuStreamCardContent(contentText, extraElements...)
 	if err != nil {
 		return err
 	}
@@ -302,29 +306,21 @@ func extractReadableFromJSON(text string) string {
 	return text
 }
 
-func buildFeishuStreamCardContent(text string) (string, error) {
+func buildFeishuStreamCardContent(text string, extraElements ...map[string]any) (string, error) {
 	content := normalizeFeishuStreamText(extractReadableFromJSON(text))
 	body := processFeishuCardMarkdown(content)
+	// The shared renderer turns tables into card table components.
+	elements := render.FeishuCardElements(render.Parse(body))
+	if len(elements) == 0 {
+		elements = []map[string]any{{"tag": "markdown", "content": body}}
+	}
 	card := map[string]any{
 		"config": map[string]any{
 			"wide_screen_mode": true,
 			"enable_forward":   true,
 			"update_multi":     true,
 		},
-		"elements": []map[string]any{
-			{
-				"tag": "div",
-				"fields": []map[string]any{
-					{
-						"is_short": false,
-						"text": map[string]any{
-							"tag":     "lark_md",
-							"content": body,
-						},
-					},
-				},
-			},
-		},
+		"elements": append(elements, extraElements...),
 	}
 	data, err := json.Marshal(card)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4"
 	larkevent "github.com/larksuite/oapi-sdk-go/v3/event"
 	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher"
+	"github.com/larksuite/oapi-sdk-go/v3/event/dispatcher/callback"
 	larkim "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 
 	"github.com/memohai/memoh/internal/channel"
@@ -113,6 +114,32 @@ func (h *WebhookHandler) Handle(c echo.Context) error {
 		return h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg)
 	})
 
+	eventDispatcher.OnP2CardActionTrigger(func(_ context.Context, event *callback.CardActionTriggerEvent) (*callback.CardActionTriggerResponse, error) {
+		msg, ok := extractFeishuCardAction(event)
+		if !ok {
+			return nil, nil
+		}
+		msg.BotID = cfg.BotID
+		return nil, h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg)
+	})
+
+	handleHistory := func(msg channel.InboundMessage, ok bool) error {
+		if !ok {
+			return nil
+		}
+		msg.BotID = cfg.BotID
+		return h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg)
+	}
+	eventDispatcher.OnP2MessageReactionCreatedV1(func(_ context.Context, event *larkim.P2MessageReactionCreatedV1) error {
+		return handleHistory(extractFeishuReactionCreated(event))
+	})
+	eventDispatcher.OnP2MessageReactionDeletedV1(func(_ context.Context, event *larkim.P2MessageReactionDeletedV1) error {
+		return handleHistory(extractFeishuReactionDeleted(event))
+	})
+	eventDispatcher.OnP2MessageRecalledV1(func(_ context.Context, event *larkim.P2MessageRecalledV1) error {
+		return handleHistory(extractFeishuRecalled(event))
+	})
+
 	resp := eventDispatcher.Handle(c.Request().Context(), &larkevent.EventReq{
 		Header:     c.Request().Header,
 		Body:       payload,
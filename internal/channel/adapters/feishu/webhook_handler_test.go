@@ -157,6 +157,54 @@ func TestWebhookHandler_EventCallbackDispatchesInbound(t *testing.T) {
 	}
 }
 
+func TestWebhookHandler_CardActionDispatchesActionEvent(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeWebhookStore{
+		configs: []channel.ChannelConfig{
+			{
+				ID:          "cfg-1",
+				BotID:       "bot-1",
+				ChannelType: Type,
+				Credentials: map[string]any{
+					"app_id":             "app",
+					"app_secret":         "secret",
+					"verification_token": "verify-token",
+					"inbound_mode":       "webhook",
+				},
+			},
+		},
+	}
+	manager := &fakeWebhookManager{}
+	h := NewWebhookHandler(nil, store, manager)
+
+	e := echo.New()
+	body := `{"schema":"2.0","header":{"event_id":"evt_2","event_type":"card.action.trigger","token":"verify-token"},"event":{"operator":{"open_id":"ou_user_1","user_id":"u_user_1"},"action":{"tag":"button","value":{"action":"approve","label":"Approve","chat_type":"group"}},"context":{"open_message_id":"om_card","open_chat_id":"oc_1"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/channels/feishu/webhook/cfg-1", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("config_id")
+	c.SetParamValues("cfg-1")
+
+	if err := h.Handle(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", rec.Code)
+	}
+	if len(manager.calls) != 1 {
+		t.Fatalf("expected one inbound call, got %d", len(manager.calls))
+	}
+	got := manager.calls[0].msg
+	if !got.IsAction() || got.Action.Value != "approve" || got.Action.Label != "Approve" || got.Action.MessageID != "om_card" {
+		t.Fatalf("unexpected action: %#v", got.Action)
+	}
+	if got.BotID != "bot-1" || got.ReplyTarget != "chat_id:oc_1" || got.Conversation.Type != "group" || got.Sender.SubjectID != "ou_user_1" {
+		t.Fatalf("unexpected inbound: %#v", got)
+	}
+}
+
 func TestWebhookHandler_EventCallbackUsesExternalIdentityForMentionFilter(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,427 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	matrixClientPrefix = "/_matrix/client/v3"
+	matrixMediaPrefix  = "/_matrix/media/v3"
+	// Authenticated media endpoints (spec v1.11); homeservers that predate them fall back to matrixMediaPrefix.
+	matrixAuthMediaPrefix = "/_matrix/client/v1/media"
+)
+
+// matrixError is the standard error body returned by the client-server API.
+type matrixError struct {
+	Status       int    `json:"-"`
+	ErrCode      string `json:"errcode"`
+	Message      string `json:"error"`
+	RetryAfterMs int64  `json:"retry_after_ms"`
+}
+
+func (e *matrixError) Error() string {
+	if e.ErrCode != "" {
+		return fmt.Sprintf("matrix api error: %s: %s (status %d)", e.ErrCode, e.Message, e.Status)
+	}
+	return fmt.Sprintf("matrix api error: status %d", e.Status)
+}
+
+func isMatrixRateLimited(err error) bool {
+	var apiErr *matrixError
+	return errors.As(err, &apiErr) && (apiErr.Status == http.StatusTooManyRequests || apiErr.ErrCode == "M_LIMIT_EXCEEDED")
+}
+
+func getMatrixRetryAfter(err error) time.Duration {
+	var apiErr *matrixError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfterMs <= 0 {
+		return 0
+	}
+	return time.Duration(apiErr.RetryAfterMs) * time.Millisecond
+}
+
+func isMatrixNotFound(err error) bool {
+	var apiErr *matrixError
+	return errors.As(err, &apiErr) && (apiErr.Status == http.StatusNotFound || apiErr.ErrCode == "M_UNRECOGNIZED" || apiErr.ErrCode == "M_NOT_FOUND")
+}
+
+// matrixClient is a minimal client-server API client scoped to one access token.
+type matrixClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	txnSeq  atomic.Int64
+}
+
+func newMatrixClient(cfg Config) *matrixClient {
+	return &matrixClient{
+		baseURL: strings.TrimRight(cfg.HomeserverURL, "/"),
+		token:   cfg.AccessToken,
+		// Long-poll syncs hold the connection open; each request carries its own deadline via context.
+		http: &http.Client{},
+	}
+}
+
+func (c *matrixClient) nextTxnID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36) + "." + strconv.FormatInt(c.txnSeq.Add(1), 10)
+}
+
+func (c *matrixClient) do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("matrix encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return decodeMatrixError(resp)
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("matrix decode response: %w", err)
+	}
+	return nil
+}
+
+func decodeMatrixError(resp *http.Response) error {
+	apiErr := &matrixError{Status: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(data, apiErr)
+	if apiErr.RetryAfterMs <= 0 {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After"))); err == nil && seconds > 0 {
+			apiErr.RetryAfterMs = int64(seconds) * 1000
+		}
+	}
+	return apiErr
+}
+
+func escapePath(segment string) string {
+	return url.PathEscape(segment)
+}
+
+// whoami returns the user ID owning the access token.
+func (c *matrixClient) whoami(ctx context.Context) (string, error) {
+	var out struct {
+		UserID string `json:"user_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/account/whoami", nil, nil, &out); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(out.UserID) == "" {
+		return "", fmt.Errorf("matrix whoami: empty user_id")
+	}
+	return out.UserID, nil
+}
+
+type matrixProfile struct {
+	DisplayName string `json:"displayname"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (c *matrixClient) profile(ctx context.Context, userID string) (matrixProfile, error) {
+	var out matrixProfile
+	err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/profile/"+escapePath(userID), nil, nil, &out)
+	return out, err
+}
+
+// matrixEvent is a room event as delivered by /sync, /event and /relations.
+type matrixEvent struct {
+	Type           string          `json:"type"`
+	EventID        string          `json:"event_id"`
+	Sender         string          `json:"sender"`
+	StateKey       *string         `json:"state_key,omitempty"`
+	OriginServerTS int64           `json:"origin_server_ts"`
+	Content        json.RawMessage `json:"content"`
+	Redacts        string          `json:"redacts,omitempty"`
+}
+
+type matrixTimeline struct {
+	Events    []matrixEvent `json:"events"`
+	Limited   bool          `json:"limited"`
+	PrevBatch string        `json:"prev_batch"`
+}
+
+type matrixJoinedRoom struct {
+	Timeline matrixTimeline `json:"timeline"`
+	State    struct {
+		Events []matrixEvent `json:"events"`
+	} `json:"state"`
+	Summary struct {
+		JoinedMemberCount  *int `json:"m.joined_member_count,omitempty"`
+		InvitedMemberCount *int `json:"m.invited_member_count,omitempty"`
+	} `json:"summary"`
+}
+
+type matrixSyncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join   map[string]matrixJoinedRoom `json:"join"`
+		Invite map[string]json.RawMessage  `json:"invite"`
+	} `json:"rooms"`
+}
+
+func (c *matrixClient) sync(ctx context.Context, since string, timeout time.Duration, filter string) (matrixSyncResponse, error) {
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	query.Set("timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	var out matrixSyncResponse
+	err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/sync", query, nil, &out)
+	return out, err
+}
+
+func (c *matrixClient) joinRoom(ctx context.Context, roomIDOrAlias string) (string, error) {
+	var out struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, matrixClientPrefix+"/join/"+escapePath(roomIDOrAlias), nil, map[string]any{}, &out); err != nil {
+		return "", err
+	}
+	return out.RoomID, nil
+}
+
+func (c *matrixClient) sendEvent(ctx context.Context, roomID, eventType string, content any) (string, error) {
+	var out struct {
+		EventID string `json:"event_id"`
+	}
+	path := matrixClientPrefix + "/rooms/" + escapePath(roomID) + "/send/" + escapePath(eventType) + "/" + escapePath(c.nextTxnID())
+	if err := c.do(ctx, http.MethodPut, path, nil, content, &out); err != nil {
+		return "", err
+	}
+	return out.EventID, nil
+}
+
+func (c *matrixClient) redact(ctx context.Context, roomID, eventID, reason string) error {
+	body := map[string]any{}
+	if reason != "" {
+		body["reason"] = reason
+	}
+	path := matrixClientPrefix + "/rooms/" + escapePath(roomID) + "/redact/" + escapePath(eventID) + "/" + escapePath(c.nextTxnID())
+	return c.do(ctx, http.MethodPut, path, nil, body, nil)
+}
+
+func (c *matrixClient) getEvent(ctx context.Context, roomID, eventID string) (matrixEvent, error) {
+	var out matrixEvent
+	err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/rooms/"+escapePath(roomID)+"/event/"+escapePath(eventID), nil, nil, &out)
+	return out, err
+}
+
+// relations lists child events of eventID with the given relation and event type.
+func (c *matrixClient) relations(ctx context.Context, roomID, eventID, relType, eventType string) ([]matrixEvent, error) {
+	var out struct {
+		Chunk []matrixEvent `json:"chunk"`
+	}
+	path := "/_matrix/client/v1/rooms/" + escapePath(roomID) + "/relations/" + escapePath(eventID) + "/" + escapePath(relType) + "/" + escapePath(eventType)
+	query := url.Values{"limit": []string{"100"}}
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Chunk, nil
+}
+
+type matrixMember struct {
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (c *matrixClient) joinedMembers(ctx context.Context, roomID string) (map[string]matrixMember, error) {
+	var out struct {
+		Joined map[string]matrixMember `json:"joined"`
+	}
+	if err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/rooms/"+escapePath(roomID)+"/joined_members", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Joined, nil
+}
+
+func (c *matrixClient) joinedRooms(ctx context.Context) ([]string, error) {
+	var out struct {
+		JoinedRooms []string `json:"joined_rooms"`
+	}
+	if err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/joined_rooms", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.JoinedRooms, nil
+}
+
+// roomName returns the room's m.room.name, falling back to its canonical alias.
+func (c *matrixClient) roomName(ctx context.Context, roomID string) string {
+	var name struct {
+		Name string `json:"name"`
+	}
+	if err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/rooms/"+escapePath(roomID)+"/state/m.room.name/", nil, nil, &name); err == nil && strings.TrimSpace(name.Name) != "" {
+		return strings.TrimSpace(name.Name)
+	}
+	var alias struct {
+		Alias string `json:"alias"`
+	}
+	if err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/rooms/"+escapePath(roomID)+"/state/m.room.canonical_alias/", nil, nil, &alias); err == nil {
+		return strings.TrimSpace(alias.Alias)
+	}
+	return ""
+}
+
+func (c *matrixClient) resolveAlias(ctx context.Context, alias string) (string, error) {
+	var out struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/directory/room/"+escapePath(alias), nil, nil, &out); err != nil {
+		return "", err
+	}
+	return out.RoomID, nil
+}
+
+// directRooms returns the m.direct account data mapping user IDs to DM room IDs.
+func (c *matrixClient) directRooms(ctx context.Context, selfID string) (map[string][]string, error) {
+	out := map[string][]string{}
+	err := c.do(ctx, http.MethodGet, matrixClientPrefix+"/user/"+escapePath(selfID)+"/account_data/m.direct", nil, nil, &out)
+	if err != nil && isMatrixNotFound(err) {
+		return map[string][]string{}, nil
+	}
+	return out, err
+}
+
+func (c *matrixClient) setDirectRooms(ctx context.Context, selfID string, rooms map[string][]string) error {
+	return c.do(ctx, http.MethodPut, matrixClientPrefix+"/user/"+escapePath(selfID)+"/account_data/m.direct", nil, rooms, nil)
+}
+
+func (c *matrixClient) createDirectRoom(ctx context.Context, userID string) (string, error) {
+	var out struct {
+		RoomID string `json:"room_id"`
+	}
+	body := map[string]any{
+		"is_direct": true,
+		"invite":    []string{userID},
+		"preset":    "trusted_private_chat",
+	}
+	if err := c.do(ctx, http.MethodPost, matrixClientPrefix+"/createRoom", nil, body, &out); err != nil {
+		return "", err
+	}
+	return out.RoomID, nil
+}
+
+func (c *matrixClient) setTyping(ctx context.Context, roomID, userID string, typing bool, timeout time.Duration) error {
+	body := map[string]any{"typing": typing}
+	if typing {
+		body["timeout"] = timeout.Milliseconds()
+	}
+	return c.do(ctx, http.MethodPut, matrixClientPrefix+"/rooms/"+escapePath(roomID)+"/typing/"+escapePath(userID), nil, body, nil)
+}
+
+// upload stores content in the homeserver media repository and returns its mxc:// URI.
+func (c *matrixClient) upload(ctx context.Context, data []byte, contentType, filename string) (string, error) {
+	endpoint := c.baseURL + matrixMediaPrefix + "/upload"
+	if filename != "" {
+		endpoint += "?" + url.Values{"filename": []string{filename}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", decodeMatrixError(resp)
+	}
+	var out struct {
+		ContentURI string `json:"content_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("matrix decode upload response: %w", err)
+	}
+	if out.ContentURI == "" {
+		return "", fmt.Errorf("matrix upload: empty content_uri")
+	}
+	return out.ContentURI, nil
+}
+
+// download opens an mxc:// URI, preferring authenticated media and falling back to the legacy endpoint.
+func (c *matrixClient) download(ctx context.Context, mxcURI string) (*http.Response, error) {
+	server, mediaID, err := parseMXC(mxcURI)
+	if err != nil {
+		return nil, err
+	}
+	suffix := "/download/" + escapePath(server) + "/" + escapePath(mediaID)
+	resp, err := c.get(ctx, c.baseURL+matrixAuthMediaPrefix+suffix)
+	if err == nil {
+		return resp, nil
+	}
+	if !isMatrixNotFound(err) {
+		return nil, err
+	}
+	return c.get(ctx, c.baseURL+matrixMediaPrefix+suffix)
+}
+
+func (c *matrixClient) get(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, decodeMatrixError(resp)
+	}
+	return resp, nil
+}
+
+func parseMXC(uri string) (string, string, error) {
+	value := strings.TrimSpace(uri)
+	if !strings.HasPrefix(value, "mxc://") {
+		return "", "", fmt.Errorf("matrix media reference is not an mxc uri: %q", uri)
+	}
+	value = strings.TrimPrefix(value, "mxc://")
+	server, mediaID, ok := strings.Cut(value, "/")
+	if !ok || server == "" || mediaID == "" {
+		return "", "", fmt.Errorf("matrix media reference is invalid: %q", uri)
+	}
+	return server, mediaID, nil
+}
@@ -0,0 +1,191 @@
+package matrix
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Config holds the Matrix account credentials extracted from a channel configuration.
+type Config struct {
+	HomeserverURL string
+	AccessToken   string
+	UserID        string
+	AutoJoin      bool
+}
+
+// UserConfig holds the identifiers used to target a Matrix user or room.
+type UserConfig struct {
+	UserID string
+	RoomID string
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"homeserverUrl": cfg.HomeserverURL,
+		"accessToken":   cfg.AccessToken,
+		"autoJoin":      cfg.AutoJoin,
+	}
+	if cfg.UserID != "" {
+		result["userId"] = cfg.UserID
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{}
+	if cfg.UserID != "" {
+		result["user_id"] = cfg.UserID
+	}
+	if cfg.RoomID != "" {
+		result["room_id"] = cfg.RoomID
+	}
+	return result, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	if cfg.RoomID != "" {
+		return cfg.RoomID, nil
+	}
+	if cfg.UserID != "" {
+		return cfg.UserID, nil
+	}
+	return "", fmt.Errorf("matrix binding is incomplete")
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := strings.TrimSpace(criteria.Attribute("user_id")); value != "" && value == cfg.UserID {
+		return true
+	}
+	if value := strings.TrimSpace(criteria.Attribute("room_id")); value != "" && value == cfg.RoomID {
+		return true
+	}
+	if criteria.SubjectID != "" && (criteria.SubjectID == cfg.UserID || criteria.SubjectID == cfg.RoomID) {
+		return true
+	}
+	return false
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := strings.TrimSpace(identity.Attribute("user_id")); value != "" {
+		result["user_id"] = value
+	} else if value := strings.TrimSpace(identity.SubjectID); strings.HasPrefix(value, "@") {
+		result["user_id"] = value
+	}
+	if value := strings.TrimSpace(identity.Attribute("room_id")); value != "" {
+		result["room_id"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	homeserver := strings.TrimSpace(channel.ReadString(raw, "homeserverUrl", "homeserver_url", "homeserver"))
+	token := strings.TrimSpace(channel.ReadString(raw, "accessToken", "access_token"))
+	if homeserver == "" || token == "" {
+		return Config{}, fmt.Errorf("matrix homeserverUrl and accessToken are required")
+	}
+	if !strings.Contains(homeserver, "://") {
+		homeserver = "https://" + homeserver
+	}
+	parsed, err := url.Parse(homeserver)
+	if err != nil || parsed.Host == "" {
+		return Config{}, fmt.Errorf("matrix homeserverUrl is invalid: %q", homeserver)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return Config{}, fmt.Errorf("matrix homeserverUrl must use http or https")
+	}
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id"))
+	if userID != "" && !isMatrixUserID(userID) {
+		return Config{}, fmt.Errorf("matrix userId is invalid: %q", userID)
+	}
+	autoJoin := true
+	switch strings.ToLower(strings.TrimSpace(channel.ReadString(raw, "autoJoin", "auto_join"))) {
+	case "false", "0", "no", "off":
+		autoJoin = false
+	}
+	return Config{
+		HomeserverURL: strings.TrimRight(parsed.String(), "/"),
+		AccessToken:   token,
+		UserID:        userID,
+		AutoJoin:      autoJoin,
+	}, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id"))
+	roomID := strings.TrimSpace(channel.ReadString(raw, "roomId", "room_id"))
+	if userID == "" && roomID == "" {
+		return UserConfig{}, fmt.Errorf("matrix user config requires user_id or room_id")
+	}
+	return UserConfig{UserID: userID, RoomID: roomID}, nil
+}
+
+// normalizeTarget accepts room IDs (!room:server), room aliases (#alias:server)
+// and user IDs (@user:server), optionally prefixed with "matrix:" or given as matrix.to links.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return ""
+	}
+	value = strings.TrimPrefix(value, "matrix:")
+	for _, prefix := range []string{"https://matrix.to/#/", "http://matrix.to/#/"} {
+		if strings.HasPrefix(value, prefix) {
+			value = strings.TrimPrefix(value, prefix)
+			if idx := strings.IndexAny(value, "?/"); idx >= 0 {
+				value = value[:idx]
+			}
+			if unescaped, err := url.PathUnescape(value); err == nil {
+				value = unescaped
+			}
+		}
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	switch value[0] {
+	case '!', '#', '@':
+		return value
+	}
+	return ""
+}
+
+func isMatrixUserID(value string) bool {
+	return strings.HasPrefix(value, "@") && strings.Contains(value, ":")
+}
+
+func isMatrixRoomID(value string) bool {
+	return strings.HasPrefix(value, "!") && strings.Contains(value, ":")
+}
+
+func isMatrixRoomAlias(value string) bool {
+	return strings.HasPrefix(value, "#") && strings.Contains(value, ":")
+}
+
+// localpart returns the user name portion of a Matrix user ID ("@alice:example.org" -> "alice").
+func localpart(userID string) string {
+	value := strings.TrimPrefix(strings.TrimSpace(userID), "@")
+	if idx := strings.Index(value, ":"); idx >= 0 {
+		value = value[:idx]
+	}
+	return value
+}
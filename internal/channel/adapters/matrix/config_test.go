@@ -0,0 +1,115 @@
+package matrix
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestNormalizeConfig(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{
+		"homeserver_url": "matrix.example.org/",
+		"access_token":   "token",
+		"auto_join":      "false",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["homeserverUrl"] != "https://matrix.example.org" || got["accessToken"] != "token" {
+		t.Fatalf("unexpected matrix config: %#v", got)
+	}
+	if got["autoJoin"] != false {
+		t.Fatalf("expected autoJoin false, got %#v", got["autoJoin"])
+	}
+	if _, ok := got["userId"]; ok {
+		t.Fatalf("expected userId to be omitted: %#v", got)
+	}
+}
+
+func TestNormalizeConfigDefaultsAutoJoin(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{
+		"homeserverUrl": "http://localhost:8008",
+		"accessToken":   "token",
+		"userId":        "@bot:localhost",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["autoJoin"] != true || got["userId"] != "@bot:localhost" {
+		t.Fatalf("unexpected matrix config: %#v", got)
+	}
+}
+
+func TestNormalizeConfigRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []map[string]any{
+		{},
+		{"homeserverUrl": "https://matrix.example.org"},
+		{"homeserverUrl": "ftp://matrix.example.org", "accessToken": "token"},
+		{"homeserverUrl": "https://matrix.example.org", "accessToken": "token", "userId": "bot"},
+	}
+	for _, raw := range cases {
+		if _, err := normalizeConfig(raw); err == nil {
+			t.Fatalf("expected error for %#v", raw)
+		}
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	t.Parallel()
+
+	target, err := resolveTarget(map[string]any{"user_id": "@alice:example.org", "room_id": "!room:example.org"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if target != "!room:example.org" {
+		t.Fatalf("expected room id to win, got %q", target)
+	}
+	target, err = resolveTarget(map[string]any{"user_id": "@alice:example.org"})
+	if err != nil || target != "@alice:example.org" {
+		t.Fatalf("unexpected target %q err %v", target, err)
+	}
+	if _, err := resolveTarget(map[string]any{}); err == nil {
+		t.Fatalf("expected error for empty binding")
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"!room:example.org":                            "!room:example.org",
+		"matrix:@alice:example.org":                    "@alice:example.org",
+		"#general:example.org":                         "#general:example.org",
+		"https://matrix.to/#/%23general:example.org":   "#general:example.org",
+		"https://matrix.to/#/@alice:example.org?via=x": "@alice:example.org",
+		"alice": "",
+		"  ":    "",
+	}
+	for input, want := range cases {
+		if got := normalizeTarget(input); got != want {
+			t.Fatalf("normalizeTarget(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestMatchBinding(t *testing.T) {
+	t.Parallel()
+
+	raw := map[string]any{"user_id": "@alice:example.org"}
+	if !matchBinding(raw, channel.BindingCriteria{SubjectID: "@alice:example.org"}) {
+		t.Fatalf("expected subject match")
+	}
+	if matchBinding(raw, channel.BindingCriteria{SubjectID: "@bob:example.org"}) {
+		t.Fatalf("unexpected match")
+	}
+	built := buildUserConfig(channel.Identity{SubjectID: "@alice:example.org"})
+	if built["user_id"] != "@alice:example.org" {
+		t.Fatalf("unexpected user config: %#v", built)
+	}
+}
@@ -0,0 +1,7 @@
+// Package matrix implements the Matrix channel adapter on top of the client-server API.
+package matrix
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for Matrix.
+const Type channel.ChannelType = "matrix"
@@ -0,0 +1,208 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	defaultDirectoryLimit = 50
+	maxDirectoryLimit     = 200
+)
+
+func directoryLimit(n int) int {
+	if n <= 0 {
+		return defaultDirectoryLimit
+	}
+	if n > maxDirectoryLimit {
+		return maxDirectoryLimit
+	}
+	return n
+}
+
+// ListPeers returns users sharing a joined room with the bot.
+func (a *MatrixAdapter) ListPeers(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := acct.client.joinedRooms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("matrix list joined rooms: %w", err)
+	}
+	limit := directoryLimit(query.Limit)
+	seen := map[string]struct{}{}
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	for _, roomID := range rooms {
+		members, err := acct.client.joinedMembers(ctx, roomID)
+		if err != nil {
+			continue
+		}
+		for _, userID := range sortedMemberIDs(members) {
+			if userID == acct.selfID {
+				continue
+			}
+			if _, ok := seen[userID]; ok {
+				continue
+			}
+			seen[userID] = struct{}{}
+			e := memberToEntry(userID, members[userID])
+			if !matchesDirectoryQuery(e, query.Query) {
+				continue
+			}
+			entries = append(entries, e)
+			if len(entries) >= limit {
+				return entries, nil
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ListGroups returns rooms the bot has joined.
+func (a *MatrixAdapter) ListGroups(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := acct.client.joinedRooms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("matrix list joined rooms: %w", err)
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	for _, roomID := range rooms {
+		e := channel.DirectoryEntry{
+			Kind: channel.DirectoryEntryGroup,
+			ID:   roomID,
+			Name: acct.client.roomName(ctx, roomID),
+		}
+		if !matchesDirectoryQuery(e, query.Query) {
+			continue
+		}
+		entries = append(entries, e)
+		if len(entries) >= limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// ListGroupMembers returns the joined members of a room.
+func (a *MatrixAdapter) ListGroupMembers(ctx context.Context, cfg channel.ChannelConfig, groupID string, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	roomID, err := a.resolveGroupID(ctx, acct, groupID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := acct.client.joinedMembers(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("matrix list room members: %w", err)
+	}
+	limit := directoryLimit(query.Limit)
+	entries := make([]channel.DirectoryEntry, 0, limit)
+	for _, userID := range sortedMemberIDs(members) {
+		e := memberToEntry(userID, members[userID])
+		if !matchesDirectoryQuery(e, query.Query) {
+			continue
+		}
+		entries = append(entries, e)
+		if len(entries) >= limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// ResolveEntry resolves a user ID via the profile API, or a room ID/alias via the room directory.
+func (a *MatrixAdapter) ResolveEntry(ctx context.Context, cfg channel.ChannelConfig, input string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	value := normalizeTarget(input)
+	if value == "" {
+		return channel.DirectoryEntry{}, fmt.Errorf("matrix resolve entry: invalid input %q", input)
+	}
+	switch kind {
+	case channel.DirectoryEntryUser:
+		if !isMatrixUserID(value) {
+			return channel.DirectoryEntry{}, fmt.Errorf("matrix resolve entry: %q is not a user id", input)
+		}
+		profile, err := acct.client.profile(ctx, value)
+		if err != nil {
+			return channel.DirectoryEntry{}, fmt.Errorf("matrix get profile: %w", err)
+		}
+		return memberToEntry(value, matrixMember{DisplayName: profile.DisplayName, AvatarURL: profile.AvatarURL}), nil
+	case channel.DirectoryEntryGroup:
+		roomID, err := a.resolveGroupID(ctx, acct, value)
+		if err != nil {
+			return channel.DirectoryEntry{}, err
+		}
+		e := channel.DirectoryEntry{
+			Kind: channel.DirectoryEntryGroup,
+			ID:   roomID,
+			Name: acct.client.roomName(ctx, roomID),
+		}
+		if isMatrixRoomAlias(value) {
+			e.Handle = value
+		}
+		return e, nil
+	default:
+		return channel.DirectoryEntry{}, fmt.Errorf("matrix resolve entry: unsupported kind %q", kind)
+	}
+}
+
+func (a *MatrixAdapter) resolveGroupID(ctx context.Context, acct *matrixAccount, groupID string) (string, error) {
+	value := normalizeTarget(groupID)
+	switch {
+	case isMatrixRoomID(value):
+		return value, nil
+	case isMatrixRoomAlias(value):
+		roomID, err := acct.client.resolveAlias(ctx, value)
+		if err != nil {
+			return "", fmt.Errorf("matrix resolve alias %s: %w", value, err)
+		}
+		return roomID, nil
+	default:
+		return "", fmt.Errorf("matrix: invalid room id %q", groupID)
+	}
+}
+
+func memberToEntry(userID string, member matrixMember) channel.DirectoryEntry {
+	name := strings.TrimSpace(member.DisplayName)
+	if name == "" {
+		name = localpart(userID)
+	}
+	return channel.DirectoryEntry{
+		Kind:      channel.DirectoryEntryUser,
+		ID:        userID,
+		Name:      name,
+		Handle:    userID,
+		AvatarURL: strings.TrimSpace(member.AvatarURL),
+	}
+}
+
+func sortedMemberIDs(members map[string]matrixMember) []string {
+	ids := make([]string, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func matchesDirectoryQuery(e channel.DirectoryEntry, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(e.ID+" "+e.Name+" "+e.Handle), query)
+}
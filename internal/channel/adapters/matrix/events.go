@@ -0,0 +1,128 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// matrixReactionTTL bounds how long a reaction is remembered so that its redaction can
+// be reported as a removal. Reactions redacted later, or after a restart, are not reported.
+const matrixReactionTTL = 24 * time.Hour
+
+// matrixReaction is a reaction seen on the timeline, keyed by its own event ID.
+type matrixReaction struct {
+	emoji     string
+	messageID string
+	sender    string
+	seenAt    time.Time
+}
+
+// matrixReactionContent is the content of an m.reaction event.
+type matrixReactionContent struct {
+	RelatesTo *matrixRelatesTo `json:"m.relates_to,omitempty"`
+}
+
+// matrixRedactionContent carries the redacted event ID in room versions 11 and later.
+type matrixRedactionContent struct {
+	Redacts string `json:"redacts,omitempty"`
+}
+
+// buildReactionInboundMessage maps an m.reaction annotation to a reaction_added event and
+// the redaction of a known reaction to a reaction_removed event.
+func (a *MatrixAdapter) buildReactionInboundMessage(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, roomID string, ev matrixEvent) (channel.InboundMessage, bool) {
+	switch ev.Type {
+	case matrixEventReaction:
+		var content matrixReactionContent
+		if err := json.Unmarshal(ev.Content, &content); err != nil {
+			return channel.InboundMessage{}, false
+		}
+		rel := content.RelatesTo
+		if rel == nil || rel.RelType != matrixRelAnnotation || strings.TrimSpace(rel.EventID) == "" || strings.TrimSpace(rel.Key) == "" {
+			return channel.InboundMessage{}, false
+		}
+		reaction := matrixReaction{
+			emoji:     strings.TrimSpace(rel.Key),
+			messageID: strings.TrimSpace(rel.EventID),
+			sender:    ev.Sender,
+		}
+		acct.rememberReaction(ev.EventID, reaction)
+		return a.reactionInboundMessage(ctx, cfg, acct, roomID, channel.InboundEventReactionAdded, reaction), true
+	case matrixEventRedaction:
+		redacts := strings.TrimSpace(ev.Redacts)
+		if redacts == "" {
+			var content matrixRedactionContent
+			if err := json.Unmarshal(ev.Content, &content); err == nil {
+				redacts = strings.TrimSpace(content.Redacts)
+			}
+		}
+		reaction, ok := acct.takeReaction(redacts)
+		if !ok {
+			return channel.InboundMessage{}, false
+		}
+		return a.reactionInboundMessage(ctx, cfg, acct, roomID, channel.InboundEventReactionRemoved, reaction), true
+	default:
+		return channel.InboundMessage{}, false
+	}
+}
+
+func (a *MatrixAdapter) reactionInboundMessage(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, roomID string, event channel.InboundEventType, reaction matrixReaction) channel.InboundMessage {
+	info := acct.roomInfo(ctx, roomID)
+	return channel.InboundMessage{
+		Channel:     Type,
+		Event:       event,
+		Reaction:    &channel.ReactionEvent{Emoji: reaction.emoji, MessageID: reaction.messageID},
+		BotID:       cfg.BotID,
+		ReplyTarget: roomID,
+		Sender:      matrixIdentity(info, reaction.sender),
+		Conversation: channel.Conversation{
+			ID:   roomID,
+			Type: matrixChatType(info),
+			Name: info.name,
+		},
+		ReceivedAt: time.Now().UTC(),
+		Source:     "matrix",
+	}
+}
+
+func (a *MatrixAdapter) dispatchHistory(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler, msg channel.InboundMessage) {
+	go func() {
+		if err := handler(ctx, cfg, msg); err != nil && a.logger != nil {
+			a.logger.Error("handle history update failed", slog.String("config_id", cfg.ID), slog.String("event", string(msg.Event)), slog.Any("error", err))
+		}
+	}()
+}
+
+func (acct *matrixAccount) rememberReaction(eventID string, reaction matrixReaction) {
+	if strings.TrimSpace(eventID) == "" {
+		return
+	}
+	now := time.Now()
+	reaction.seenAt = now
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	for id, seen := range acct.reactions {
+		if now.Sub(seen.seenAt) > matrixReactionTTL {
+			delete(acct.reactions, id)
+		}
+	}
+	acct.reactions[eventID] = reaction
+}
+
+func (acct *matrixAccount) takeReaction(eventID string) (matrixReaction, bool) {
+	if eventID == "" {
+		return matrixReaction{}, false
+	}
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	reaction, ok := acct.reactions[eventID]
+	if !ok {
+		return matrixReaction{}, false
+	}
+	delete(acct.reactions, eventID)
+	return reaction, time.Since(reaction.seenAt) <= matrixReactionTTL
+}
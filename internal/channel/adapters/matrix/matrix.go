@@ -0,0 +1,1259 @@
+package matrix
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+	"github.com/memohai/memoh/internal/channel/render"
+	"github.com/memohai/memoh/internal/media"
+)
+
+const (
+	matrixSyncTimeout    = 30 * time.Second
+	matrixSyncGrace      = 15 * time.Second
+	matrixSyncMinBackoff = time.Second
+	matrixSyncMaxBackoff = time.Minute
+	matrixRoomInfoTTL    = 5 * time.Minute
+	matrixTypingTimeout  = 30 * time.Second
+	inboundDedupTTL      = 10 * time.Minute
+
+	matrixEventMessage   = "m.room.message"
+	matrixEventReaction  = "m.reaction"
+	matrixEventRedaction = "m.room.redaction"
+	matrixEventEncrypted = "m.room.encrypted"
+	matrixEventMember    = "m.room.member"
+	matrixEventRoomName  = "m.room.name"
+
+	matrixRelThread     = "m.thread"
+	matrixRelReplace    = "m.replace"
+	matrixRelAnnotation = "m.annotation"
+
+	matrixHTMLFormat = "org.matrix.custom.html"
+)
+
+// matrixSyncFilter keeps /sync payloads small: no presence or account data, lazy-loaded members.
+const matrixSyncFilter = `{"presence":{"not_types":["*"]},"account_data":{"not_types":["*"]},"room":{"state":{"lazy_load_members":true},"timeline":{"limit":50},"ephemeral":{"not_types":["*"]},"account_data":{"not_types":["*"]}}}`
+
+// assetOpener reads stored asset bytes by content hash.
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// matrixRoomInfo caches room metadata used to classify and label inbound conversations.
+type matrixRoomInfo struct {
+	name        string
+	memberCount int
+	members     map[string]matrixMember
+	fetchedAt   time.Time
+}
+
+// matrixAccount holds the client and cached state for one homeserver access token.
+type matrixAccount struct {
+	client *matrixClient
+	selfID string
+	mu     sync.Mutex
+	rooms  map[string]matrixRoomInfo
+	direct map[string]string // user ID -> DM room ID
+	// reactions maps recent reaction event IDs to their reaction, so redactions can be reported.
+	reactions map[string]matrixReaction
+}
+
+// MatrixAdapter implements the channel.Adapter, channel.Sender, and channel.Receiver interfaces for Matrix.
+type MatrixAdapter struct {
+	logger       *slog.Logger
+	mu           sync.Mutex
+	accounts     map[string]*matrixAccount // keyed by homeserver|token
+	seenEvents   map[string]time.Time      // keyed by homeserver|token|eventID
+	assets       assetOpener
+	httpDownload *http.Client
+}
+
+// NewMatrixAdapter creates a MatrixAdapter with the given logger.
+func NewMatrixAdapter(log *slog.Logger) *MatrixAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &MatrixAdapter{
+		logger:       log.With(slog.String("adapter", "matrix")),
+		accounts:     make(map[string]*matrixAccount),
+		seenEvents:   make(map[string]time.Time),
+		httpDownload: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// SetAssetOpener injects the media asset reader for storage-first file delivery.
+func (a *MatrixAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+// Type returns the Matrix channel type.
+func (a *MatrixAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the Matrix channel metadata.
+func (a *MatrixAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Matrix",
+		Capabilities: channel.ChannelCapabilities{
+			Text:        true,
+			Markdown:    true,
+			RichText:    true,
+			Attachments: true,
+			Media:       true,
+			Reactions:   true,
+			Reply:       true,
+			Threads:     true,
+			Streaming:   true,
+			Edit:        true,
+			Unsend:      true,
+			ChatTypes:   []string{"direct", "group"},
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: 16000,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"homeserverUrl": {
+					Type:        channel.FieldString,
+					Required:    true,
+					Title:       "Homeserver URL",
+					Description: "Client-server API base URL of the homeserver",
+					Example:     "https://matrix.example.org",
+				},
+				"accessToken": {
+					Type:     channel.FieldSecret,
+					Required: true,
+					Title:    "Access Token",
+				},
+				"userId": {
+					Type:        channel.FieldString,
+					Title:       "User ID",
+					Description: "Bot user ID; discovered via whoami when empty",
+					Example:     "@memoh:example.org",
+				},
+				"autoJoin": {
+					Type:        channel.FieldBool,
+					Title:       "Auto Join",
+					Description: "Accept room invites automatically",
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"user_id": {Type: channel.FieldString},
+				"room_id": {Type: channel.FieldString},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "!room_id:server | #alias:server | @user:server",
+			Hints: []channel.TargetHint{
+				{Label: "Room ID", Example: "!abcdef:example.org"},
+				{Label: "Room Alias", Example: "#general:example.org"},
+				{Label: "User ID", Example: "@alice:example.org"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a Matrix channel configuration map.
+func (a *MatrixAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a Matrix user-binding configuration map.
+func (a *MatrixAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a Matrix delivery target string.
+func (a *MatrixAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a Matrix user-binding configuration.
+func (a *MatrixAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a Matrix user binding matches the given criteria.
+func (a *MatrixAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a Matrix user-binding config from an Identity.
+func (a *MatrixAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// DiscoverSelf retrieves the bot's own identity from the homeserver.
+func (a *MatrixAdapter) DiscoverSelf(ctx context.Context, credentials map[string]any) (map[string]any, string, error) {
+	cfg, err := parseConfig(credentials)
+	if err != nil {
+		return nil, "", err
+	}
+	client := newMatrixClient(cfg)
+	userID, err := client.whoami(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("matrix discover self: %w", err)
+	}
+	identity := map[string]any{
+		"user_id": userID,
+	}
+	if profile, err := client.profile(ctx, userID); err == nil {
+		if name := strings.TrimSpace(profile.DisplayName); name != "" {
+			identity["name"] = name
+		}
+		if avatar := strings.TrimSpace(profile.AvatarURL); avatar != "" {
+			identity["avatar_url"] = avatar
+		}
+	}
+	return identity, userID, nil
+}
+
+func accountKey(cfg Config) string {
+	return cfg.HomeserverURL + "|" + cfg.AccessToken
+}
+
+// account returns the cached account for the channel config, resolving the bot user ID on first use.
+func (a *MatrixAdapter) account(ctx context.Context, cfg channel.ChannelConfig) (*matrixAccount, Config, error) {
+	matrixCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, Config{}, err
+	}
+	key := accountKey(matrixCfg)
+	a.mu.Lock()
+	acct, ok := a.accounts[key]
+	if !ok {
+		acct = &matrixAccount{
+			client:    newMatrixClient(matrixCfg),
+			rooms:     make(map[string]matrixRoomInfo),
+			direct:    make(map[string]string),
+			reactions: make(map[string]matrixReaction),
+		}
+		a.accounts[key] = acct
+	}
+	a.mu.Unlock()
+
+	acct.mu.Lock()
+	selfID := acct.selfID
+	acct.mu.Unlock()
+	if selfID != "" {
+		return acct, matrixCfg, nil
+	}
+	selfID = matrixCfg.UserID
+	if selfID == "" {
+		selfID = strings.TrimSpace(channel.ReadString(cfg.SelfIdentity, "user_id", "userId"))
+	}
+	if selfID == "" {
+		selfID, err = acct.client.whoami(ctx)
+		if err != nil {
+			return nil, Config{}, fmt.Errorf("matrix whoami: %w", err)
+		}
+	}
+	acct.mu.Lock()
+	acct.selfID = selfID
+	acct.mu.Unlock()
+	return acct, matrixCfg, nil
+}
+
+// Connect starts a /sync long-poll loop and forwards room messages to the handler.
+func (a *MatrixAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	if a.logger != nil {
+		a.logger.Info("start", slog.String("config_id", cfg.ID))
+	}
+	acct, matrixCfg, err := a.account(ctx, cfg)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("init account failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+	connCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.runSync(connCtx, cfg, matrixCfg, acct, handler)
+	}()
+	stop := func(stopCtx context.Context) error {
+		if a.logger != nil {
+			a.logger.Info("stop", slog.String("config_id", cfg.ID))
+		}
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	}
+	return channel.NewConnection(cfg, stop), nil
+}
+
+// runSync drives the /sync loop. The first sync only records the stream position so that
+// history from before the connection is not replayed as new inbound messages.
+func (a *MatrixAdapter) runSync(ctx context.Context, cfg channel.ChannelConfig, matrixCfg Config, acct *matrixAccount, handler channel.InboundHandler) {
+	since := ""
+	initial := true
+	backoff := matrixSyncMinBackoff
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+		timeout := matrixSyncTimeout
+		if initial {
+			timeout = 0
+		}
+		reqCtx, cancel := context.WithTimeout(ctx, timeout+matrixSyncGrace)
+		resp, err := acct.client.sync(reqCtx, since, timeout, matrixSyncFilter)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			wait := backoff
+			if retryAfter := getMatrixRetryAfter(err); retryAfter > 0 {
+				wait = retryAfter
+			}
+			if a.logger != nil {
+				a.logger.Warn("sync failed", slog.String("config_id", cfg.ID), slog.Duration("retry_in", wait), slog.Any("error", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			backoff = min(backoff*2, matrixSyncMaxBackoff)
+			continue
+		}
+		backoff = matrixSyncMinBackoff
+		since = resp.NextBatch
+		if matrixCfg.AutoJoin {
+			a.acceptInvites(ctx, cfg, acct, resp.Rooms.Invite)
+		}
+		if initial {
+			initial = false
+			continue
+		}
+		for roomID, room := range resp.Rooms.Join {
+			a.handleJoinedRoom(ctx, cfg, matrixCfg, acct, handler, roomID, room)
+		}
+	}
+}
+
+func (a *MatrixAdapter) acceptInvites(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, invites map[string]json.RawMessage) {
+	for roomID := range invites {
+		if _, err := acct.client.joinRoom(ctx, roomID); err != nil {
+			if a.logger != nil {
+				a.logger.Warn("join invited room failed", slog.String("config_id", cfg.ID), slog.String("room_id", roomID), slog.Any("error", err))
+			}
+			continue
+		}
+		if a.logger != nil {
+			a.logger.Info("joined invited room", slog.String("config_id", cfg.ID), slog.String("room_id", roomID))
+		}
+	}
+}
+
+func (a *MatrixAdapter) handleJoinedRoom(ctx context.Context, cfg channel.ChannelConfig, matrixCfg Config, acct *matrixAccount, handler channel.InboundHandler, roomID string, room matrixJoinedRoom) {
+	if room.Summary.JoinedMemberCount != nil {
+		acct.updateMemberCount(roomID, *room.Summary.JoinedMemberCount)
+	}
+	for _, ev := range room.State.Events {
+		if ev.Type == matrixEventMember || ev.Type == matrixEventRoomName {
+			acct.invalidateRoom(roomID)
+		}
+	}
+	for _, ev := range room.Timeline.Events {
+		switch ev.Type {
+		case matrixEventMember, matrixEventRoomName:
+			acct.invalidateRoom(roomID)
+			continue
+		case matrixEventEncrypted:
+			if a.logger != nil {
+				a.logger.Warn("skip encrypted event: end-to-end encryption is not supported",
+					slog.String("config_id", cfg.ID),
+					slog.String("room_id", roomID),
+					slog.String("event_id", ev.EventID),
+				)
+			}
+			continue
+		case matrixEventReaction, matrixEventRedaction:
+			if ev.Sender == acct.selfID || a.isDuplicateInbound(accountKey(matrixCfg), ev.EventID) {
+				continue
+			}
+			if msg, ok := a.buildReactionInboundMessage(ctx, cfg, acct, roomID, ev); ok {
+				a.dispatchHistory(ctx, cfg, handler, msg)
+			}
+			continue
+		case matrixEventMessage:
+		default:
+			continue
+		}
+		if ev.Sender == acct.selfID {
+			continue
+		}
+		if a.isDuplicateInbound(accountKey(matrixCfg), ev.EventID) {
+			continue
+		}
+		msg, ok := a.buildInboundMessage(ctx, cfg, acct, roomID, ev)
+		if !ok {
+			continue
+		}
+		a.dispatchInbound(ctx, cfg, handler, msg)
+	}
+}
+
+func (a *MatrixAdapter) dispatchInbound(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler, msg channel.InboundMessage) {
+	if a.logger != nil {
+		a.logger.Info(
+			"inbound received",
+			slog.String("config_id", cfg.ID),
+			slog.String("chat_type", msg.Conversation.Type),
+			slog.String("room_id", msg.Conversation.ID),
+			slog.String("thread_id", msg.Conversation.ThreadID),
+			slog.String("user_id", msg.Sender.SubjectID),
+			slog.String("text", common.SummarizeText(msg.Message.Text)),
+			slog.Int("attachments", len(msg.Message.Attachments)),
+		)
+	}
+	go func() {
+		if err := handler(ctx, cfg, msg); err != nil && a.logger != nil {
+			a.logger.Error("handle inbound failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+	}()
+}
+
+func (a *MatrixAdapter) isDuplicateInbound(key, eventID string) bool {
+	if strings.TrimSpace(eventID) == "" {
+		return false
+	}
+	now := time.Now().UTC()
+	expireBefore := now.Add(-inboundDedupTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for seenKey, seenAt := range a.seenEvents {
+		if seenAt.Before(expireBefore) {
+			delete(a.seenEvents, seenKey)
+		}
+	}
+	seenKey := key + "|" + eventID
+	if _, ok := a.seenEvents[seenKey]; ok {
+		return true
+	}
+	a.seenEvents[seenKey] = now
+	return false
+}
+
+func (a *MatrixAdapter) buildInboundMessage(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, roomID string, ev matrixEvent) (channel.InboundMessage, bool) {
+	var content matrixMessageContent
+	if err := json.Unmarshal(ev.Content, &content); err != nil {
+		return channel.InboundMessage{}, false
+	}
+	// Edits are delivered as separate events; only original messages trigger a reply.
+	if content.RelatesTo != nil && content.RelatesTo.RelType == matrixRelReplace {
+		return channel.InboundMessage{}, false
+	}
+	text, attachments := inboundContent(content)
+	if text == "" && len(attachments) == 0 {
+		return channel.InboundMessage{}, false
+	}
+
+	threadID := ""
+	var replyRef *channel.ReplyRef
+	if rel := content.RelatesTo; rel != nil {
+		if rel.RelType == matrixRelThread {
+			threadID = strings.TrimSpace(rel.EventID)
+		}
+		if rel.InReplyTo != nil && rel.InReplyTo.EventID != "" && !(threadID != "" && rel.IsFallingBack) {
+			replyRef = &channel.ReplyRef{Target: roomID, MessageID: rel.InReplyTo.EventID}
+		}
+	}
+
+	info := acct.roomInfo(ctx, roomID)
+
+	isReplyToBot := false
+	if replyRef != nil {
+		isReplyToBot = acct.isOwnEvent(ctx, roomID, replyRef.MessageID)
+	}
+	if !isReplyToBot && threadID != "" {
+		isReplyToBot = acct.isOwnEvent(ctx, roomID, threadID)
+	}
+	meta := map[string]any{
+		"is_mentioned":    isMatrixBotMentioned(content, acct.selfID),
+		"is_reply_to_bot": isReplyToBot,
+		"event_id":        ev.EventID,
+		"msgtype":         content.MsgType,
+	}
+	var thread *channel.ThreadRef
+	if threadID != "" {
+		thread = &channel.ThreadRef{ID: threadID}
+	}
+	receivedAt := time.Now().UTC()
+	if ev.OriginServerTS > 0 {
+		receivedAt = time.UnixMilli(ev.OriginServerTS).UTC()
+	}
+	return channel.InboundMessage{
+		Channel: Type,
+		Message: channel.Message{
+			ID:          ev.EventID,
+			Format:      channel.MessageFormatPlain,
+			Text:        text,
+			Attachments: attachments,
+			Thread:      thread,
+			Reply:       replyRef,
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: roomID,
+		Sender:      matrixIdentity(info, ev.Sender),
+		Conversation: channel.Conversation{
+			ID:       roomID,
+			Type:     matrixChatType(info),
+			Name:     info.name,
+			ThreadID: threadID,
+		},
+		ReceivedAt: receivedAt,
+		Source:     "matrix",
+		Metadata:   meta,
+	}, true
+}
+
+// matrixChatType classifies a room with at most two members as a direct chat.
+func matrixChatType(info matrixRoomInfo) string {
+	if info.memberCount > 0 && info.memberCount <= 2 {
+		return "direct"
+	}
+	return "group"
+}
+
+// matrixIdentity builds the sender identity, preferring the room display name.
+func matrixIdentity(info matrixRoomInfo, userID string) channel.Identity {
+	displayName := localpart(userID)
+	if member, ok := info.members[userID]; ok && strings.TrimSpace(member.DisplayName) != "" {
+		displayName = strings.TrimSpace(member.DisplayName)
+	}
+	return channel.Identity{
+		SubjectID:   userID,
+		DisplayName: displayName,
+		Attributes: map[string]string{
+			"user_id":  userID,
+			"username": localpart(userID),
+		},
+	}
+}
+
+// inboundContent extracts the text body and attachments from a message event.
+func inboundContent(content matrixMessageContent) (string, []channel.Attachment) {
+	body := content.Body
+	if content.RelatesTo != nil && content.RelatesTo.InReplyTo != nil {
+		body = stripReplyFallback(body)
+	}
+	body = strings.TrimSpace(body)
+	attType := channel.AttachmentType("")
+	switch content.MsgType {
+	case "m.text", "m.notice", "m.emote", "":
+		return body, nil
+	case "m.image":
+		attType = channel.AttachmentImage
+	case "m.video":
+		attType = channel.AttachmentVideo
+	case "m.audio":
+		attType = channel.AttachmentAudio
+		if content.Voice != nil {
+			attType = channel.AttachmentVoice
+		}
+	case "m.file":
+		attType = channel.AttachmentFile
+	default:
+		return body, nil
+	}
+	if strings.TrimSpace(content.URL) == "" {
+		return body, nil
+	}
+	name := strings.TrimSpace(content.FileName)
+	caption := ""
+	if name == "" {
+		name = body
+	} else if body != name {
+		caption = body
+	}
+	att := channel.Attachment{
+		Type:           attType,
+		PlatformKey:    strings.TrimSpace(content.URL),
+		SourcePlatform: Type.String(),
+		Name:           name,
+		Caption:        caption,
+	}
+	if info := content.Info; info != nil {
+		att.Mime = strings.TrimSpace(info.MimeType)
+		att.Size = info.Size
+		att.Width = info.Width
+		att.Height = info.Height
+		att.DurationMs = info.Duration
+	}
+	return caption, []channel.Attachment{att}
+}
+
+// stripReplyFallback removes the quoted "> <@user> ..." block that clients prepend to replies.
+func stripReplyFallback(body string) string {
+	lines := strings.Split(body, "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], ">") {
+		i++
+	}
+	if i == 0 {
+		return body
+	}
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	return strings.Join(lines[i:], "\n")
+}
+
+func isMatrixBotMentioned(content matrixMessageContent, selfID string) bool {
+	if selfID == "" {
+		return false
+	}
+	if content.Mentions != nil {
+		for _, userID := range content.Mentions.UserIDs {
+			if userID == selfID {
+				return true
+			}
+		}
+	}
+	if strings.Contains(content.Body, selfID) {
+		return true
+	}
+	return strings.Contains(content.FormattedBody, "matrix.to/#/"+selfID)
+}
+
+func (acct *matrixAccount) isOwnEvent(ctx context.Context, roomID, eventID string) bool {
+	if strings.TrimSpace(eventID) == "" {
+		return false
+	}
+	ev, err := acct.client.getEvent(ctx, roomID, eventID)
+	if err != nil {
+		return false
+	}
+	return ev.Sender == acct.selfID
+}
+
+// roomInfo returns cached room metadata, refreshing it from the homeserver when stale.
+func (acct *matrixAccount) roomInfo(ctx context.Context, roomID string) matrixRoomInfo {
+	acct.mu.Lock()
+	info, ok := acct.rooms[roomID]
+	acct.mu.Unlock()
+	if ok && info.members != nil && time.Since(info.fetchedAt) < matrixRoomInfoTTL {
+		return info
+	}
+	members, err := acct.client.joinedMembers(ctx, roomID)
+	if err != nil {
+		return info
+	}
+	info = matrixRoomInfo{
+		name:        acct.client.roomName(ctx, roomID),
+		memberCount: len(members),
+		members:     members,
+		fetchedAt:   time.Now(),
+	}
+	acct.mu.Lock()
+	acct.rooms[roomID] = info
+	acct.mu.Unlock()
+	return info
+}
+
+func (acct *matrixAccount) updateMemberCount(roomID string, count int) {
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	info, ok := acct.rooms[roomID]
+	if !ok || info.memberCount == count {
+		return
+	}
+	info.memberCount = count
+	info.members = nil
+	acct.rooms[roomID] = info
+}
+
+func (acct *matrixAccount) invalidateRoom(roomID string) {
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	delete(acct.rooms, roomID)
+}
+
+// resolveRoomID maps a delivery target to a joined room ID. User IDs resolve to an
+// existing direct room from m.direct, or a newly created one.
+func (a *MatrixAdapter) resolveRoomID(ctx context.Context, acct *matrixAccount, target string) (string, error) {
+	target = normalizeTarget(target)
+	switch {
+	case target == "":
+		return "", fmt.Errorf("matrix target is required")
+	case isMatrixRoomID(target):
+		return target, nil
+	case isMatrixRoomAlias(target):
+		roomID, err := acct.client.resolveAlias(ctx, target)
+		if err != nil {
+			return "", fmt.Errorf("matrix resolve alias %s: %w", target, err)
+		}
+		return roomID, nil
+	case isMatrixUserID(target):
+		return a.directRoomFor(ctx, acct, target)
+	default:
+		return "", fmt.Errorf("matrix target is invalid: %q", target)
+	}
+}
+
+func (a *MatrixAdapter) directRoomFor(ctx context.Context, acct *matrixAccount, userID string) (string, error) {
+	acct.mu.Lock()
+	roomID := acct.direct[userID]
+	acct.mu.Unlock()
+	if roomID != "" {
+		return roomID, nil
+	}
+	direct, err := acct.client.directRooms(ctx, acct.selfID)
+	if err != nil {
+		return "", fmt.Errorf("matrix read m.direct: %w", err)
+	}
+	if rooms := direct[userID]; len(rooms) > 0 {
+		roomID = rooms[len(rooms)-1]
+	} else {
+		roomID, err = acct.client.createDirectRoom(ctx, userID)
+		if err != nil {
+			return "", fmt.Errorf("matrix create direct room: %w", err)
+		}
+		direct[userID] = append(direct[userID], roomID)
+		if err := acct.client.setDirectRooms(ctx, acct.selfID, direct); err != nil && a.logger != nil {
+			a.logger.Warn("update m.direct failed", slog.String("user_id", userID), slog.Any("error", err))
+		}
+	}
+	acct.mu.Lock()
+	acct.direct[userID] = roomID
+	acct.mu.Unlock()
+	return roomID, nil
+}
+
+// Send delivers an outbound message to a Matrix room, handling formatting, replies, threads and attachments.
+func (a *MatrixAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return err
+	}
+	roomID, err := a.resolveRoomID(ctx, acct, msg.Target)
+	if err != nil {
+		return err
+	}
+	_, err = a.sendMessage(ctx, cfg, acct, roomID, msg.Message)
+	return err
+}
+
+// sendMessage posts text followed by attachments and returns the created event IDs.
+func (a *MatrixAdapter) sendMessage(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, roomID string, msg channel.Message) ([]string, error) {
+	relates := buildRelatesTo(msg.Thread, msg.Reply)
+	eventIDs := make([]string, 0, 1+len(msg.Attachments))
+	text := strings.TrimSpace(msg.PlainText())
+	if text != "" {
+		content := buildTextContent(text, msg.Format)
+		content.RelatesTo = relates
+		eventID, err := acct.client.sendEvent(ctx, roomID, matrixEventMessage, content)
+		if err != nil {
+			return eventIDs, err
+		}
+		eventIDs = append(eventIDs, eventID)
+		channel.ReportSentMessage(ctx, roomID, eventID)
+	}
+	for _, att := range msg.Attachments {
+		content, err := a.buildAttachmentContent(ctx, cfg, acct, att)
+		if err != nil {
+			return eventIDs, err
+		}
+		content.RelatesTo = relates
+		eventID, err := acct.client.sendEvent(ctx, roomID, matrixEventMessage, content)
+		if err != nil {
+			return eventIDs, err
+		}
+		eventIDs = append(eventIDs, eventID)
+		channel.ReportSentMessage(ctx, roomID, eventID)
+	}
+	return eventIDs, nil
+}
+
+func buildTextContent(text string, format channel.MessageFormat) *matrixMessageContent {
+	content := &matrixMessageContent{
+		MsgType: "m.text",
+		Body:    text,
+	}
+	if format == channel.MessageFormatMarkdown || format == channel.MessageFormatRich {
+		if formatted := render.HTML(render.Parse(text)); formatted != "" {
+			content.Format = matrixHTMLFormat
+			content.FormattedBody = formatted
+		}
+	}
+	return content
+}
+
+// buildRelatesTo maps channel thread and reply references to an m.relates_to block.
+// Thread replies use the m.thread relation with a reply fallback for clients without thread support.
+func buildRelatesTo(thread *channel.ThreadRef, reply *channel.ReplyRef) *matrixRelatesTo {
+	replyID := ""
+	if reply != nil {
+		replyID = strings.TrimSpace(reply.MessageID)
+	}
+	if thread != nil && strings.TrimSpace(thread.ID) != "" {
+		rootID := strings.TrimSpace(thread.ID)
+		inReplyTo := replyID
+		if inReplyTo == "" {
+			inReplyTo = rootID
+		}
+		return &matrixRelatesTo{
+			RelType:       matrixRelThread,
+			EventID:       rootID,
+			IsFallingBack: true,
+			InReplyTo:     &matrixInReplyTo{EventID: inReplyTo},
+		}
+	}
+	if replyID != "" {
+		return &matrixRelatesTo{InReplyTo: &matrixInReplyTo{EventID: replyID}}
+	}
+	return nil
+}
+
+func (a *MatrixAdapter) buildAttachmentContent(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, att channel.Attachment) (*matrixMessageContent, error) {
+	mxcURI, mime, size, err := a.uploadAttachment(ctx, cfg, acct, att)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(att.Name)
+	if name == "" {
+		name = fileNameFromMime(mime, string(att.Type))
+	}
+	content := &matrixMessageContent{
+		MsgType:  attachmentMsgType(att.Type),
+		Body:     name,
+		FileName: name,
+		URL:      mxcURI,
+		Info: &matrixMediaInfo{
+			MimeType: mime,
+			Size:     size,
+			Width:    att.Width,
+			Height:   att.Height,
+			Duration: att.DurationMs,
+		},
+	}
+	if caption := strings.TrimSpace(att.Caption); caption != "" {
+		content.Body = caption
+	}
+	if att.Type == channel.AttachmentVoice {
+		voice := json.RawMessage(`{}`)
+		content.Voice = &voice
+	}
+	return content, nil
+}
+
+func attachmentMsgType(t channel.AttachmentType) string {
+	switch t {
+	case channel.AttachmentImage, channel.AttachmentGIF:
+		return "m.image"
+	case channel.AttachmentVideo:
+		return "m.video"
+	case channel.AttachmentAudio, channel.AttachmentVoice:
+		return "m.audio"
+	default:
+		return "m.file"
+	}
+}
+
+// uploadAttachment returns an mxc:// URI for the attachment, uploading bytes when needed.
+// Priority: existing mxc reference > ContentHash (storage) > base64 data URL > remote URL.
+func (a *MatrixAdapter) uploadAttachment(ctx context.Context, cfg channel.ChannelConfig, acct *matrixAccount, att channel.Attachment) (string, string, int64, error) {
+	for _, ref := range []string{att.PlatformKey, att.URL} {
+		if ref = strings.TrimSpace(ref); strings.HasPrefix(ref, "mxc://") {
+			return ref, strings.TrimSpace(att.Mime), att.Size, nil
+		}
+	}
+	data, mime, err := a.readAttachmentBytes(ctx, cfg, att)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if mime == "" {
+		mime = http.DetectContentType(data)
+	}
+	uri, err := acct.client.upload(ctx, data, mime, strings.TrimSpace(att.Name))
+	if err != nil {
+		return "", "", 0, fmt.Errorf("matrix upload attachment: %w", err)
+	}
+	return uri, mime, int64(len(data)), nil
+}
+
+func (a *MatrixAdapter) readAttachmentBytes(ctx context.Context, cfg channel.ChannelConfig, att channel.Attachment) ([]byte, string, error) {
+	mime := strings.TrimSpace(att.Mime)
+	if hash := strings.TrimSpace(att.ContentHash); hash != "" && a.assets != nil {
+		botID := cfg.BotID
+		if att.Metadata != nil {
+			if bid, ok := att.Metadata["bot_id"].(string); ok && strings.TrimSpace(bid) != "" {
+				botID = strings.TrimSpace(bid)
+			}
+		}
+		reader, asset, err := a.assets.Open(ctx, botID, hash)
+		if err == nil {
+			data, readErr := io.ReadAll(io.LimitReader(reader, media.MaxAssetBytes+1))
+			_ = reader.Close()
+			if int64(len(data)) > media.MaxAssetBytes {
+				return nil, "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+			}
+			if readErr == nil && len(data) > 0 {
+				if mime == "" {
+					mime = asset.Mime
+				}
+				return data, mime, nil
+			}
+		}
+	}
+	dataURL := strings.TrimSpace(att.Base64)
+	if dataURL == "" && strings.HasPrefix(strings.ToLower(strings.TrimSpace(att.URL)), "data:") {
+		dataURL = strings.TrimSpace(att.URL)
+	}
+	if dataURL != "" {
+		data, dataMime, err := decodeDataURL(dataURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode data url for matrix upload: %w", err)
+		}
+		if mime == "" {
+			mime = dataMime
+		}
+		return data, mime, nil
+	}
+	urlRef := strings.TrimSpace(att.URL)
+	if strings.HasPrefix(urlRef, "http://") || strings.HasPrefix(urlRef, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlRef, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, err := a.httpDownload.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("download attachment: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("download attachment status: %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, media.MaxAssetBytes+1))
+		if err != nil {
+			return nil, "", err
+		}
+		if int64(len(data)) > media.MaxAssetBytes {
+			return nil, "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+		}
+		if mime == "" {
+			mime = stripMimeParams(resp.Header.Get("Content-Type"))
+		}
+		return data, mime, nil
+	}
+	return nil, "", fmt.Errorf("no usable attachment reference for matrix")
+}
+
+func decodeDataURL(dataURL string) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(dataURL, ",")
+	if !ok {
+		return nil, "", fmt.Errorf("malformed data url")
+	}
+	mime := stripMimeParams(strings.TrimPrefix(strings.TrimPrefix(header, "data:"), "DATA:"))
+	data, err := io.ReadAll(io.LimitReader(
+		base64.NewDecoder(base64.StdEncoding, strings.NewReader(payload)),
+		media.MaxAssetBytes+1,
+	))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(data)) > media.MaxAssetBytes {
+		return nil, "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	return data, mime, nil
+}
+
+func stripMimeParams(value string) string {
+	value = strings.TrimSpace(value)
+	if idx := strings.Index(value, ";"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
+
+func fileNameFromMime(mime, fallbackType string) string {
+	mime = strings.ToLower(strings.TrimSpace(mime))
+	switch {
+	case strings.HasPrefix(mime, "image/png"):
+		return "image.png"
+	case strings.HasPrefix(mime, "image/jpeg"):
+		return "image.jpg"
+	case strings.HasPrefix(mime, "image/gif"):
+		return "image.gif"
+	case strings.HasPrefix(mime, "image/webp"):
+		return "image.webp"
+	case strings.HasPrefix(mime, "audio/ogg"):
+		return "audio.ogg"
+	case strings.HasPrefix(mime, "audio/mpeg"):
+		return "audio.mp3"
+	case strings.HasPrefix(mime, "video/mp4"):
+		return "video.mp4"
+	case strings.HasPrefix(mime, "application/pdf"):
+		return "document.pdf"
+	}
+	if fallbackType == "" {
+		return "file"
+	}
+	return fallbackType
+}
+
+// OpenStream opens a streaming session that posts a message and edits it in place with m.replace.
+func (a *MatrixAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("matrix target is required")
+	}
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	roomID, err := a.resolveRoomID(ctx, acct, target)
+	if err != nil {
+		return nil, err
+	}
+	return &matrixOutboundStream{
+		adapter: a,
+		cfg:     cfg,
+		acct:    acct,
+		roomID:  roomID,
+		reply:   opts.Reply,
+		thread:  opts.Thread,
+	}, nil
+}
+
+// Update edits a previously sent message using an m.replace relation.
+func (a *MatrixAdapter) Update(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, msg channel.Message) error {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	roomID, err := a.resolveRoomID(ctx, acct, target)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(msg.PlainText())
+	if text == "" {
+		return fmt.Errorf("matrix update requires text")
+	}
+	_, err = acct.client.sendEvent(ctx, roomID, matrixEventMessage, buildEditContent(messageID, text, msg.Format))
+	return err
+}
+
+// Unsend redacts a previously sent message.
+func (a *MatrixAdapter) Unsend(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string) error {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	roomID, err := a.resolveRoomID(ctx, acct, target)
+	if err != nil {
+		return err
+	}
+	return acct.client.redact(ctx, roomID, strings.TrimSpace(messageID), "")
+}
+
+func buildEditContent(eventID, text string, format channel.MessageFormat) *matrixMessageContent {
+	newContent := buildTextContent(text, format)
+	edit := &matrixMessageContent{
+		MsgType:    newContent.MsgType,
+		Body:       "* " + newContent.Body,
+		NewContent: newContent,
+		RelatesTo:  &matrixRelatesTo{RelType: matrixRelReplace, EventID: strings.TrimSpace(eventID)},
+	}
+	if newContent.FormattedBody != "" {
+		edit.Format = newContent.Format
+		edit.FormattedBody = "* " + newContent.FormattedBody
+	}
+	return edit
+}
+
+// React adds an emoji annotation to a message.
+func (a *MatrixAdapter) React(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, emoji string) error {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	roomID, err := a.resolveRoomID(ctx, acct, target)
+	if err != nil {
+		return err
+	}
+	_, err = acct.client.sendEvent(ctx, roomID, matrixEventReaction, map[string]any{
+		"m.relates_to": matrixRelatesTo{
+			RelType: matrixRelAnnotation,
+			EventID: strings.TrimSpace(messageID),
+			Key:     strings.TrimSpace(emoji),
+		},
+	})
+	return err
+}
+
+// Unreact removes the bot's emoji annotation by redacting the matching reaction event.
+func (a *MatrixAdapter) Unreact(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, emoji string) error {
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	roomID, err := a.resolveRoomID(ctx, acct, target)
+	if err != nil {
+		return err
+	}
+	events, err := acct.client.relations(ctx, roomID, strings.TrimSpace(messageID), matrixRelAnnotation, matrixEventReaction)
+	if err != nil {
+		return fmt.Errorf("matrix list reactions: %w", err)
+	}
+	emoji = strings.TrimSpace(emoji)
+	for _, ev := range events {
+		if ev.Sender != acct.selfID {
+			continue
+		}
+		var content struct {
+			RelatesTo matrixRelatesTo `json:"m.relates_to"`
+		}
+		if err := json.Unmarshal(ev.Content, &content); err != nil || content.RelatesTo.Key != emoji {
+			continue
+		}
+		if err := acct.client.redact(ctx, roomID, ev.EventID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessingStarted shows a typing indicator while the bot composes a reply.
+func (a *MatrixAdapter) ProcessingStarted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+	roomID := strings.TrimSpace(msg.ReplyTarget)
+	if roomID == "" {
+		return channel.ProcessingStatusHandle{}, nil
+	}
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	if err := acct.client.setTyping(ctx, roomID, acct.selfID, true, matrixTypingTimeout); err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	return channel.ProcessingStatusHandle{Token: roomID}, nil
+}
+
+// ProcessingCompleted clears the typing indicator.
+func (a *MatrixAdapter) ProcessingCompleted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+	return a.clearTyping(ctx, cfg, handle)
+}
+
+// ProcessingFailed clears the typing indicator.
+func (a *MatrixAdapter) ProcessingFailed(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+	return a.clearTyping(ctx, cfg, handle)
+}
+
+func (a *MatrixAdapter) clearTyping(ctx context.Context, cfg channel.ChannelConfig, handle channel.ProcessingStatusHandle) error {
+	roomID := strings.TrimSpace(handle.Token)
+	if roomID == "" {
+		return nil
+	}
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return acct.client.setTyping(ctx, roomID, acct.selfID, false, 0)
+}
+
+// ResolveAttachment downloads an mxc:// attachment from the homeserver media repository.
+func (a *MatrixAdapter) ResolveAttachment(ctx context.Context, cfg channel.ChannelConfig, attachment channel.Attachment) (channel.AttachmentPayload, error) {
+	ref := strings.TrimSpace(attachment.PlatformKey)
+	if ref == "" {
+		ref = strings.TrimSpace(attachment.URL)
+	}
+	if !strings.HasPrefix(ref, "mxc://") {
+		return channel.AttachmentPayload{}, fmt.Errorf("matrix attachment requires an mxc platform_key")
+	}
+	acct, _, err := a.account(ctx, cfg)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	resp, err := acct.client.download(ctx, ref)
+	if err != nil {
+		return channel.AttachmentPayload{}, fmt.Errorf("download attachment: %w", err)
+	}
+	if resp.ContentLength > media.MaxAssetBytes {
+		_ = resp.Body.Close()
+		return channel.AttachmentPayload{}, fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	mime := strings.TrimSpace(attachment.Mime)
+	if mime == "" {
+		mime = stripMimeParams(resp.Header.Get("Content-Type"))
+	}
+	size := attachment.Size
+	if size <= 0 && resp.ContentLength > 0 {
+		size = resp.ContentLength
+	}
+	return channel.AttachmentPayload{
+		Reader: resp.Body,
+		Mime:   mime,
+		Name:   strings.TrimSpace(attachment.Name),
+		Size:   size,
+	}, nil
+}
+
+// matrixMessageContent is the content of an m.room.message event.
+type matrixMessageContent struct {
+	MsgType       string                `json:"msgtype"`
+	Body          string                `json:"body"`
+	Format        string                `json:"format,omitempty"`
+	FormattedBody string                `json:"formatted_body,omitempty"`
+	URL           string                `json:"url,omitempty"`
+	FileName      string                `json:"filename,omitempty"`
+	Info          *matrixMediaInfo      `json:"info,omitempty"`
+	Voice         *json.RawMessage      `json:"org.matrix.msc3245.voice,omitempty"`
+	Mentions      *matrixMentions       `json:"m.mentions,omitempty"`
+	NewContent    *matrixMessageContent `json:"m.new_content,omitempty"`
+	RelatesTo     *matrixRelatesTo      `json:"m.relates_to,omitempty"`
+}
+
+type matrixMediaInfo struct {
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Width    int    `json:"w,omitempty"`
+	Height   int    `json:"h,omitempty"`
+	Duration int64  `json:"duration,omitempty"`
+}
+
+type matrixMentions struct {
+	UserIDs []string `json:"user_ids,omitempty"`
+	Room    bool     `json:"room,omitempty"`
+}
+
+type matrixRelatesTo struct {
+	RelType       string           `json:"rel_type,omitempty"`
+	EventID       string           `json:"event_id,omitempty"`
+	Key           string           `json:"key,omitempty"`
+	IsFallingBack bool             `json:"is_falling_back,omitempty"`
+	InReplyTo     *matrixInReplyTo `json:"m.in_reply_to,omitempty"`
+}
+
+type matrixInReplyTo struct {
+	EventID string `json:"event_id"`
+}
@@ -0,0 +1,79 @@
+package matrix
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// TestMatrixAdapter_Integration exercises send, stream edits, reactions and directory
+// lookups against a real homeserver (for example a local Synapse or Conduit instance).
+// Required env: MATRIX_HOMESERVER_URL, MATRIX_ACCESS_TOKEN, MATRIX_ROOM_ID.
+func TestMatrixAdapter_Integration(t *testing.T) {
+	homeserver := os.Getenv("MATRIX_HOMESERVER_URL")
+	token := os.Getenv("MATRIX_ACCESS_TOKEN")
+	roomID := os.Getenv("MATRIX_ROOM_ID")
+	if homeserver == "" || token == "" || roomID == "" {
+		t.Skip("skipping integration test: MATRIX_HOMESERVER_URL, MATRIX_ACCESS_TOKEN or MATRIX_ROOM_ID not set")
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	adapter := NewMatrixAdapter(logger)
+	cfg := channel.ChannelConfig{
+		ID:          "integration-test-bot",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"homeserverUrl": homeserver,
+			"accessToken":   token,
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	identity, externalID, err := adapter.DiscoverSelf(ctx, cfg.Credentials)
+	if err != nil {
+		t.Fatalf("discover self: %v", err)
+	}
+	t.Logf("bot identity: %s %#v", externalID, identity)
+
+	if err := adapter.Send(ctx, cfg, channel.OutboundMessage{
+		Target:  roomID,
+		Message: channel.Message{Format: channel.MessageFormatMarkdown, Text: "**integration** test message"},
+	}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	stream, err := adapter.OpenStream(ctx, cfg, roomID, channel.StreamOptions{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	for _, delta := range []string{"streaming ", "reply"} {
+		if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: delta}); err != nil {
+			t.Fatalf("push delta: %v", err)
+		}
+	}
+	if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventFinal}); err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	_ = stream.Close(ctx)
+
+	ms := stream.(*matrixOutboundStream)
+	if err := adapter.React(ctx, cfg, roomID, ms.eventID, "👍"); err != nil {
+		t.Fatalf("react: %v", err)
+	}
+	if err := adapter.Unreact(ctx, cfg, roomID, ms.eventID, "👍"); err != nil {
+		t.Fatalf("unreact: %v", err)
+	}
+
+	members, err := adapter.ListGroupMembers(ctx, cfg, roomID, channel.DirectoryQuery{})
+	if err != nil {
+		t.Fatalf("list members: %v", err)
+	}
+	if len(members) == 0 {
+		t.Fatalf("expected at least one room member")
+	}
+}
@@ -0,0 +1,389 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const testSelfID = "@bot:test"
+
+type sentEvent struct {
+	roomID    string
+	eventType string
+	content   map[string]any
+}
+
+// fakeHomeserver is a minimal in-memory stand-in for the client-server API.
+type fakeHomeserver struct {
+	mu      sync.Mutex
+	sent    []sentEvent
+	syncs   []string
+	syncN   int
+	members map[string]matrixMember
+}
+
+func newFakeHomeserver(t *testing.T) (*fakeHomeserver, *httptest.Server) {
+	t.Helper()
+	hs := &fakeHomeserver{
+		members: map[string]matrixMember{
+			testSelfID:    {DisplayName: "Bot"},
+			"@alice:test": {DisplayName: "Alice"},
+			"@carol:test": {},
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(hs.serve))
+	t.Cleanup(srv.Close)
+	return hs, srv
+}
+
+func (hs *fakeHomeserver) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(path, "/account/whoami"):
+		_, _ = io.WriteString(w, `{"user_id":"`+testSelfID+`"}`)
+	case strings.HasSuffix(path, "/sync"):
+		hs.mu.Lock()
+		n := hs.syncN
+		hs.syncN++
+		var body string
+		if n < len(hs.syncs) {
+			body = hs.syncs[n]
+		}
+		hs.mu.Unlock()
+		if body == "" {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	case strings.HasSuffix(path, "/joined_members"):
+		hs.mu.Lock()
+		data, _ := json.Marshal(map[string]any{"joined": hs.members})
+		hs.mu.Unlock()
+		_, _ = w.Write(data)
+	case strings.Contains(path, "/state/m.room.name"):
+		_, _ = io.WriteString(w, `{"name":"General"}`)
+	case strings.Contains(path, "/event/"):
+		_, _ = io.WriteString(w, `{"event_id":"$root","sender":"`+testSelfID+`","type":"m.room.message","content":{}}`)
+	case r.Method == http.MethodPut && strings.Contains(path, "/send/"):
+		parts := strings.Split(path, "/")
+		var content map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&content)
+		hs.mu.Lock()
+		hs.sent = append(hs.sent, sentEvent{roomID: unescape(parts[5]), eventType: parts[7], content: content})
+		id := len(hs.sent)
+		hs.mu.Unlock()
+		_, _ = fmt.Fprintf(w, `{"event_id":"$ev%d"}`, id)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"errcode":"M_UNRECOGNIZED","error":"unknown"}`)
+	}
+}
+
+func (hs *fakeHomeserver) sentEvents() []sentEvent {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return append([]sentEvent(nil), hs.sent...)
+}
+
+func unescape(s string) string {
+	s = strings.ReplaceAll(s, "%21", "!")
+	return strings.ReplaceAll(s, "%3A", ":")
+}
+
+func testConfig(srv *httptest.Server) channel.ChannelConfig {
+	return channel.ChannelConfig{
+		ID:          "cfg-1",
+		BotID:       "bot-1",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"homeserverUrl": srv.URL,
+			"accessToken":   "token",
+		},
+	}
+}
+
+func TestBuildInboundMessageMapsThreadAndMention(t *testing.T) {
+	t.Parallel()
+
+	_, srv := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	cfg := testConfig(srv)
+	acct, _, err := adapter.account(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("account: %v", err)
+	}
+	ev := matrixEvent{
+		Type:           matrixEventMessage,
+		EventID:        "$msg",
+		Sender:         "@alice:test",
+		OriginServerTS: 1700000000000,
+		Content: json.RawMessage(`{
+			"msgtype":"m.text",
+			"body":"hello bot",
+			"m.mentions":{"user_ids":["@bot:test"]},
+			"m.relates_to":{"rel_type":"m.thread","event_id":"$root","is_falling_back":true,"m.in_reply_to":{"event_id":"$prev"}}
+		}`),
+	}
+	msg, ok := adapter.buildInboundMessage(context.Background(), cfg, acct, "!room:test", ev)
+	if !ok {
+		t.Fatalf("expected inbound message")
+	}
+	if msg.Conversation.ThreadID != "$root" || msg.Message.Thread == nil || msg.Message.Thread.ID != "$root" {
+		t.Fatalf("unexpected thread mapping: %#v", msg.Conversation)
+	}
+	if msg.Message.Reply != nil {
+		t.Fatalf("thread fallback must not become a reply: %#v", msg.Message.Reply)
+	}
+	if msg.Conversation.Type != "group" || msg.Conversation.Name != "General" {
+		t.Fatalf("unexpected conversation: %#v", msg.Conversation)
+	}
+	if msg.Sender.DisplayName != "Alice" || msg.ReplyTarget != "!room:test" {
+		t.Fatalf("unexpected sender/target: %#v %q", msg.Sender, msg.ReplyTarget)
+	}
+	if msg.Metadata["is_mentioned"] != true || msg.Metadata["is_reply_to_bot"] != true {
+		t.Fatalf("unexpected metadata: %#v", msg.Metadata)
+	}
+}
+
+func TestBuildInboundMessageSkipsEdits(t *testing.T) {
+	t.Parallel()
+
+	_, srv := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	cfg := testConfig(srv)
+	acct, _, err := adapter.account(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("account: %v", err)
+	}
+	ev := matrixEvent{
+		Type:    matrixEventMessage,
+		EventID: "$edit",
+		Sender:  "@alice:test",
+		Content: json.RawMessage(`{"msgtype":"m.text","body":"* fixed","m.new_content":{"msgtype":"m.text","body":"fixed"},"m.relates_to":{"rel_type":"m.replace","event_id":"$msg"}}`),
+	}
+	if _, ok := adapter.buildInboundMessage(context.Background(), cfg, acct, "!room:test", ev); ok {
+		t.Fatalf("expected edit event to be skipped")
+	}
+}
+
+func TestBuildReactionInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	_, srv := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	cfg := testConfig(srv)
+	acct, _, err := adapter.account(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("account: %v", err)
+	}
+	added, ok := adapter.buildReactionInboundMessage(context.Background(), cfg, acct, "!room:test", matrixEvent{
+		Type:    matrixEventReaction,
+		EventID: "$react",
+		Sender:  "@alice:test",
+		Content: json.RawMessage(`{"m.relates_to":{"rel_type":"m.annotation","event_id":"$msg","key":"👍"}}`),
+	})
+	if !ok || added.Event != channel.InboundEventReactionAdded || added.Reaction == nil {
+		t.Fatalf("expected reaction_added event, got %#v", added)
+	}
+	if added.Reaction.Emoji != "👍" || added.Reaction.MessageID != "$msg" || added.Sender.DisplayName != "Alice" || added.Conversation.ID != "!room:test" {
+		t.Fatalf("unexpected reaction event: %#v", added)
+	}
+
+	removed, ok := adapter.buildReactionInboundMessage(context.Background(), cfg, acct, "!room:test", matrixEvent{
+		Type:    matrixEventRedaction,
+		EventID: "$redact",
+		Sender:  "@alice:test",
+		Content: json.RawMessage(`{"redacts":"$react"}`),
+	})
+	if !ok || removed.Event != channel.InboundEventReactionRemoved || removed.Reaction == nil || removed.Reaction.MessageID != "$msg" {
+		t.Fatalf("expected reaction_removed event, got %#v", removed)
+	}
+	if _, ok := adapter.buildReactionInboundMessage(context.Background(), cfg, acct, "!room:test", matrixEvent{
+		Type:    matrixEventRedaction,
+		EventID: "$redact2",
+		Sender:  "@alice:test",
+		Redacts: "$react",
+	}); ok {
+		t.Fatalf("expected redaction of an unknown reaction to be skipped")
+	}
+}
+
+func TestInboundContentMapsMedia(t *testing.T) {
+	t.Parallel()
+
+	var content matrixMessageContent
+	raw := `{"msgtype":"m.audio","body":"listen to this","filename":"note.ogg","url":"mxc://test/abc","info":{"mimetype":"audio/ogg","size":42,"duration":1500},"org.matrix.msc3245.voice":{}}`
+	if err := json.Unmarshal([]byte(raw), &content); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	text, atts := inboundContent(content)
+	if text != "listen to this" || len(atts) != 1 {
+		t.Fatalf("unexpected content: %q %#v", text, atts)
+	}
+	att := atts[0]
+	if att.Type != channel.AttachmentVoice || att.PlatformKey != "mxc://test/abc" || att.Name != "note.ogg" {
+		t.Fatalf("unexpected attachment: %#v", att)
+	}
+	if att.Mime != "audio/ogg" || att.Size != 42 || att.DurationMs != 1500 || att.SourcePlatform != "matrix" {
+		t.Fatalf("unexpected attachment metadata: %#v", att)
+	}
+}
+
+func TestStripReplyFallback(t *testing.T) {
+	t.Parallel()
+
+	body := "> <@alice:test> original\n> second line\n\nactual reply"
+	if got := stripReplyFallback(body); got != "actual reply" {
+		t.Fatalf("unexpected stripped body: %q", got)
+	}
+	if got := stripReplyFallback("no quote"); got != "no quote" {
+		t.Fatalf("unexpected body: %q", got)
+	}
+}
+
+func TestSendFormatsMarkdownIntoThread(t *testing.T) {
+	t.Parallel()
+
+	hs, srv := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	err := adapter.Send(context.Background(), testConfig(srv), channel.OutboundMessage{
+		Target: "!room:test",
+		Message: channel.Message{
+			Format: channel.MessageFormatMarkdown,
+			Text:   "**done**",
+			Thread: &channel.ThreadRef{ID: "$root"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	sent := hs.sentEvents()
+	if len(sent) != 1 {
+		t.Fatalf("expected one event, got %d", len(sent))
+	}
+	content := sent[0].content
+	if sent[0].roomID != "!room:test" || sent[0].eventType != matrixEventMessage {
+		t.Fatalf("unexpected event: %#v", sent[0])
+	}
+	if content["format"] != matrixHTMLFormat || content["formatted_body"] != "<p><strong>done</strong></p>" {
+		t.Fatalf("unexpected formatted content: %#v", content)
+	}
+	rel, _ := content["m.relates_to"].(map[string]any)
+	if rel["rel_type"] != matrixRelThread || rel["event_id"] != "$root" {
+		t.Fatalf("unexpected relation: %#v", rel)
+	}
+}
+
+func TestStreamEditsMessageInPlace(t *testing.T) {
+	t.Parallel()
+
+	hs, srv := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	stream, err := adapter.OpenStream(context.Background(), testConfig(srv), "!room:test", channel.StreamOptions{
+		Reply: &channel.ReplyRef{Target: "!room:test", MessageID: "$src"},
+	})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	ctx := context.Background()
+	if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "Hel"}); err != nil {
+		t.Fatalf("push delta: %v", err)
+	}
+	if err := stream.Push(ctx, channel.StreamEvent{Type: channel.StreamEventDelta, Delta: "lo"}); err != nil {
+		t.Fatalf("push delta: %v", err)
+	}
+	if err := stream.Push(ctx, channel.StreamEvent{
+		Type:  channel.StreamEventFinal,
+		Final: &channel.StreamFinalizePayload{Message: channel.Message{Format: channel.MessageFormatMarkdown, Text: "Hello"}},
+	}); err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	sent := hs.sentEvents()
+	if len(sent) != 2 {
+		t.Fatalf("expected initial message and one final edit, got %d", len(sent))
+	}
+	first := sent[0].content
+	rel, _ := first["m.relates_to"].(map[string]any)
+	reply, _ := rel["m.in_reply_to"].(map[string]any)
+	if reply["event_id"] != "$src" {
+		t.Fatalf("expected initial message to reply to source: %#v", first)
+	}
+	final := sent[1].content
+	rel, _ = final["m.relates_to"].(map[string]any)
+	if rel["rel_type"] != matrixRelReplace || rel["event_id"] != "$ev1" {
+		t.Fatalf("unexpected edit relation: %#v", final)
+	}
+	newContent, _ := final["m.new_content"].(map[string]any)
+	if newContent["body"] != "Hello" || newContent["formatted_body"] != "<p>Hello</p>" {
+		t.Fatalf("unexpected new content: %#v", newContent)
+	}
+}
+
+func TestConnectSkipsInitialHistory(t *testing.T) {
+	t.Parallel()
+
+	hs, srv := newFakeHomeserver(t)
+	hs.syncs = []string{
+		`{"next_batch":"s1","rooms":{"join":{"!room:test":{"timeline":{"events":[{"type":"m.room.message","event_id":"$old","sender":"@alice:test","content":{"msgtype":"m.text","body":"old"}}]}}}}}`,
+		`{"next_batch":"s2","rooms":{"join":{"!room:test":{"timeline":{"events":[
+			{"type":"m.room.message","event_id":"$own","sender":"@bot:test","content":{"msgtype":"m.text","body":"mine"}},
+			{"type":"m.room.encrypted","event_id":"$enc","sender":"@alice:test","content":{}},
+			{"type":"m.room.message","event_id":"$new","sender":"@alice:test","content":{"msgtype":"m.text","body":"new"}}
+		]}}}}}`,
+	}
+	adapter := NewMatrixAdapter(nil)
+	received := make(chan channel.InboundMessage, 4)
+	conn, err := adapter.Connect(context.Background(), testConfig(srv), func(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+		received <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = conn.Stop(stopCtx)
+	}()
+	select {
+	case msg := <-received:
+		if msg.Message.ID != "$new" || msg.Message.Text != "new" {
+			t.Fatalf("unexpected inbound message: %#v", msg.Message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for inbound message")
+	}
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected extra inbound message: %#v", msg.Message)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestListGroupMembers(t *testing.T) {
+	t.Parallel()
+
+	_, srv := newFakeHomeserver(t)
+	adapter := NewMatrixAdapter(nil)
+	entries, err := adapter.ListGroupMembers(context.Background(), testConfig(srv), "!room:test", channel.DirectoryQuery{Query: "ali"})
+	if err != nil {
+		t.Fatalf("list members: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "@alice:test" || entries[0].Name != "Alice" {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+}
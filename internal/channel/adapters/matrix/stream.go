@@ -0,0 +1,250 @@
+package matrix
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const matrixStreamEditThrottle = 2000 * time.Millisecond
+const matrixStreamPendingSuffix = " …"
+const matrixFinalEditMaxRetries = 3
+
+type matrixOutboundStream struct {
+	adapter      *MatrixAdapter
+	cfg          channel.ChannelConfig
+	acct         *matrixAccount
+	roomID       string
+	reply        *channel.ReplyRef
+	thread       *channel.ThreadRef
+	closed       atomic.Bool
+	mu           sync.Mutex
+	buf          strings.Builder
+	eventID      string
+	lastEdited   string
+	lastEditedAt time.Time
+}
+
+// ensureStreamMessage posts the initial message that later deltas edit in place.
+func (s *matrixOutboundStream) ensureStreamMessage(ctx context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.eventID != "" {
+		return nil
+	}
+	body := strings.TrimSpace(text)
+	if body == "" {
+		body = "…"
+	} else {
+		body += matrixStreamPendingSuffix
+	}
+	content := buildTextContent(body, channel.MessageFormatPlain)
+	content.RelatesTo = buildRelatesTo(s.thread, s.reply)
+	eventID, err := s.acct.client.sendEvent(ctx, s.roomID, matrixEventMessage, content)
+	if err != nil {
+		return err
+	}
+	s.eventID = eventID
+	s.lastEdited = body
+	s.lastEditedAt = time.Now()
+	channel.ReportSentMessage(ctx, s.roomID, eventID)
+	return nil
+}
+
+// editStreamMessage replaces the streamed message with interim text, throttled to avoid rate limits.
+func (s *matrixOutboundStream) editStreamMessage(ctx context.Context, text string) error {
+	s.mu.Lock()
+	eventID := s.eventID
+	lastEdited := s.lastEdited
+	lastEditedAt := s.lastEditedAt
+	s.mu.Unlock()
+	if eventID == "" {
+		return nil
+	}
+	body := strings.TrimSpace(text) + matrixStreamPendingSuffix
+	if body == lastEdited || time.Since(lastEditedAt) < matrixStreamEditThrottle {
+		return nil
+	}
+	_, err := s.acct.client.sendEvent(ctx, s.roomID, matrixEventMessage, buildEditContent(eventID, body, channel.MessageFormatPlain))
+	if err != nil {
+		if isMatrixRateLimited(err) {
+			d := getMatrixRetryAfter(err)
+			if d <= 0 {
+				d = matrixStreamEditThrottle
+			}
+			s.mu.Lock()
+			s.lastEditedAt = time.Now().Add(d)
+			s.mu.Unlock()
+			return nil
+		}
+		return err
+	}
+	s.mu.Lock()
+	s.lastEdited = body
+	s.lastEditedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// editStreamMessageFinal writes the final formatted content, retrying on rate limits.
+func (s *matrixOutboundStream) editStreamMessageFinal(ctx context.Context, text string, format channel.MessageFormat) error {
+	s.mu.Lock()
+	eventID := s.eventID
+	lastEdited := s.lastEdited
+	s.mu.Unlock()
+	if eventID == "" {
+		return nil
+	}
+	text = strings.TrimSpace(text)
+	if text == lastEdited && format == channel.MessageFormatPlain {
+		return nil
+	}
+	content := buildEditContent(eventID, text, format)
+	for attempt := range matrixFinalEditMaxRetries {
+		_, err := s.acct.client.sendEvent(ctx, s.roomID, matrixEventMessage, content)
+		if err == nil {
+			s.mu.Lock()
+			s.lastEdited = text
+			s.lastEditedAt = time.Now()
+			s.mu.Unlock()
+			return nil
+		}
+		if !isMatrixRateLimited(err) {
+			return err
+		}
+		d := getMatrixRetryAfter(err)
+		if d <= 0 {
+			d = time.Duration(attempt+1) * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+	return nil
+}
+
+func (s *matrixOutboundStream) resetStreamMessage() {
+	s.mu.Lock()
+	s.eventID = ""
+	s.lastEdited = ""
+	s.lastEditedAt = time.Time{}
+	s.buf.Reset()
+	s.mu.Unlock()
+}
+
+func (s *matrixOutboundStream) sendAttachments(ctx context.Context, attachments []channel.Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+	msg := channel.Message{Attachments: attachments, Thread: s.thread}
+	if _, err := s.adapter.sendMessage(ctx, s.cfg, s.acct, s.roomID, msg); err != nil && s.adapter.logger != nil {
+		s.adapter.logger.Warn("stream attachment send failed",
+			slog.String("config_id", s.cfg.ID),
+			slog.String("room_id", s.roomID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (s *matrixOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil || s.acct == nil {
+		return fmt.Errorf("matrix stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("matrix stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch event.Type {
+	case channel.StreamEventToolCallStart:
+		s.mu.Lock()
+		bufText := strings.TrimSpace(s.buf.String())
+		hasMsg := s.eventID != ""
+		s.mu.Unlock()
+		if hasMsg && bufText != "" {
+			_ = s.editStreamMessageFinal(ctx, bufText, channel.MessageFormatMarkdown)
+		}
+		s.resetStreamMessage()
+		return nil
+	case channel.StreamEventToolCallEnd:
+		s.resetStreamMessage()
+		return nil
+	case channel.StreamEventAttachment:
+		s.sendAttachments(ctx, event.Attachments)
+		return nil
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.buf.WriteString(event.Delta)
+		content := s.buf.String()
+		s.mu.Unlock()
+		if err := s.ensureStreamMessage(ctx, content); err != nil {
+			return err
+		}
+		return s.editStreamMessage(ctx, content)
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		finalText := strings.TrimSpace(s.buf.String())
+		s.mu.Unlock()
+		format := channel.MessageFormatMarkdown
+		var attachments []channel.Attachment
+		if event.Final != nil && !event.Final.Message.IsEmpty() {
+			msg := event.Final.Message
+			if finalText == "" {
+				finalText = strings.TrimSpace(msg.PlainText())
+			}
+			if msg.Format != "" {
+				format = msg.Format
+			}
+			attachments = msg.Attachments
+		}
+		if finalText != "" {
+			if err := s.ensureStreamMessage(ctx, finalText); err != nil {
+				return err
+			}
+			if err := s.editStreamMessageFinal(ctx, finalText, format); err != nil {
+				return err
+			}
+		}
+		s.sendAttachments(ctx, attachments)
+		return nil
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		display := "Error: " + errText
+		if err := s.ensureStreamMessage(ctx, display); err != nil {
+			return err
+		}
+		return s.editStreamMessageFinal(ctx, display, channel.MessageFormatPlain)
+	default:
+		return nil
+	}
+}
+
+func (s *matrixOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	s.closed.Store(true)
+	return nil
+}
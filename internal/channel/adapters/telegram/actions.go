@@ -0,0 +1,136 @@
+package telegram
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// telegramMaxCallbackData is the Bot API limit for inline button callback_data.
+const telegramMaxCallbackData = 64
+
+// buildTelegramInlineKeyboard renders message actions as an inline keyboard with one button per
+// row. Link actions open their URL; all other actions report their value as callback data.
+func buildTelegramInlineKeyboard(actions []channel.Action) *tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(actions))
+	for _, action := range actions {
+		label := strings.TrimSpace(action.Label)
+		link := strings.TrimSpace(action.URL)
+		value := strings.TrimSpace(action.Value)
+		if label == "" {
+			label = value
+		}
+		if label == "" {
+			label = link
+		}
+		if label == "" {
+			continue
+		}
+		if link != "" {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonURL(label, link)))
+			continue
+		}
+		if value == "" {
+			value = label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, telegramCallbackData(value))))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &markup
+}
+
+// telegramCallbackData truncates a value to the callback_data limit without splitting a rune.
+func telegramCallbackData(value string) string {
+	if len(value) <= telegramMaxCallbackData {
+		return value
+	}
+	cut := telegramMaxCallbackData
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut]
+}
+
+// telegramButtonLabel finds the label of the pressed button in the keyboard of the message
+// that carried it.
+func telegramButtonLabel(msg *tgbotapi.Message, data string) string {
+	if msg == nil || msg.ReplyMarkup == nil {
+		return ""
+	}
+	for _, row := range msg.ReplyMarkup.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil && *button.CallbackData == data {
+				return strings.TrimSpace(button.Text)
+			}
+		}
+	}
+	return ""
+}
+
+// buildTelegramCallbackInboundMessage maps a callback query from an inline keyboard button to an
+// action event. Callbacks from inline-mode messages carry no chat and are ignored.
+func buildTelegramCallbackInboundMessage(cfg channel.ChannelConfig, query *tgbotapi.CallbackQuery) (channel.InboundMessage, bool) {
+	if query == nil || query.Message == nil || query.Message.Chat == nil || query.From == nil {
+		return channel.InboundMessage{}, false
+	}
+	data := strings.TrimSpace(query.Data)
+	if data == "" {
+		return channel.InboundMessage{}, false
+	}
+	origin := query.Message
+	subjectID, displayName, attrs := resolveTelegramSender(&tgbotapi.Message{From: query.From, Chat: origin.Chat})
+	chatID := strconv.FormatInt(origin.Chat.ID, 10)
+	return channel.InboundMessage{
+		Channel: Type,
+		Event:   channel.InboundEventAction,
+		Message: channel.Message{ID: "callback:" + query.ID},
+		Action: &channel.ActionEvent{
+			Value:     data,
+			Label:     telegramButtonLabel(origin, query.Data),
+			MessageID: strconv.Itoa(origin.MessageID),
+		},
+		BotID:       cfg.BotID,
+		ReplyTarget: chatID,
+		Sender: channel.Identity{
+			SubjectID:   subjectID,
+			DisplayName: displayName,
+			Attributes:  attrs,
+		},
+		Conversation: channel.Conversation{
+			ID:   chatID,
+			Type: strings.TrimSpace(origin.Chat.Type),
+			Name: strings.TrimSpace(origin.Chat.Title),
+		},
+		ReceivedAt: time.Now().UTC(),
+		Source:     "telegram",
+		Metadata: map[string]any{
+			"callback_query_id": query.ID,
+		},
+	}, true
+}
+
+// answerTelegramCallback acknowledges a callback query so the client stops its loading indicator.
+func answerTelegramCallback(bot *tgbotapi.BotAPI, queryID string) error {
+	_, err := bot.Request(tgbotapi.NewCallback(queryID, ""))
+	return err
+}
+
+// setTelegramReplyMarkup attaches an inline keyboard to an already sent message.
+func setTelegramReplyMarkup(bot *tgbotapi.BotAPI, chatID int64, messageID int, markup *tgbotapi.InlineKeyboardMarkup) error {
+	if markup == nil {
+		return nil
+	}
+	_, err := bot.Request(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, *markup))
+	if err != nil && isTelegramMessageNotModified(err) {
+		return nil
+	}
+	return err
+}
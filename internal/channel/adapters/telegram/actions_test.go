@@ -0,0 +1,155 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestBuildTelegramInlineKeyboard(t *testing.T) {
+	t.Parallel()
+
+	keyboard := buildTelegramInlineKeyboard([]channel.Action{
+		{Type: "button", Label: "Yes", Value: "yes"},
+		{Type: "link", Label: "Docs", URL: "https://example.com"},
+		{Type: "button", Value: "later"},
+		{Type: "button"},
+	})
+	if keyboard == nil || len(keyboard.InlineKeyboard) != 3 {
+		t.Fatalf("expected three rows, got %#v", keyboard)
+	}
+	yes := keyboard.InlineKeyboard[0][0]
+	if yes.Text != "Yes" || yes.CallbackData == nil || *yes.CallbackData != "yes" {
+		t.Fatalf("unexpected callback button: %#v", yes)
+	}
+	docs := keyboard.InlineKeyboard[1][0]
+	if docs.URL == nil || *docs.URL != "https://example.com" || docs.CallbackData != nil {
+		t.Fatalf("unexpected url button: %#v", docs)
+	}
+	if later := keyboard.InlineKeyboard[2][0]; later.Text != "later" {
+		t.Fatalf("expected value as label fallback, got %#v", later)
+	}
+	if buildTelegramInlineKeyboard(nil) != nil {
+		t.Fatal("expected nil keyboard without actions")
+	}
+}
+
+func TestTelegramCallbackDataTruncatesOnRuneBoundary(t *testing.T) {
+	t.Parallel()
+
+	value := strings.Repeat("a", 63) + "é"
+	got := telegramCallbackData(value)
+	if got != strings.Repeat("a", 63) {
+		t.Fatalf("unexpected truncation: %q", got)
+	}
+	if telegramCallbackData("short") != "short" {
+		t.Fatal("short values must be kept")
+	}
+}
+
+func TestBuildTelegramCallbackInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	data := "approve"
+	query := &tgbotapi.CallbackQuery{
+		ID:   "cb-1",
+		From: &tgbotapi.User{ID: 7, UserName: "alice"},
+		Data: data,
+		Message: &tgbotapi.Message{
+			MessageID: 42,
+			Chat:      &tgbotapi.Chat{ID: -100123, Type: "supergroup", Title: "Ops"},
+			ReplyMarkup: &tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{
+				{{Text: "Approve", CallbackData: &data}},
+			}},
+		},
+	}
+	msg, ok := buildTelegramCallbackInboundMessage(channel.ChannelConfig{BotID: "bot-1"}, query)
+	if !ok {
+		t.Fatal("expected callback to be accepted")
+	}
+	if !msg.IsAction() || msg.Action.Value != "approve" || msg.Action.Label != "Approve" || msg.Action.MessageID != "42" {
+		t.Fatalf("unexpected action: %#v", msg.Action)
+	}
+	if msg.ReplyTarget != "-100123" || msg.Conversation.Type != "supergroup" || msg.Sender.SubjectID != "7" || msg.Sender.Attribute("username") != "alice" {
+		t.Fatalf("unexpected inbound: %#v", msg)
+	}
+
+	query.Message = nil
+	if _, ok := buildTelegramCallbackInboundMessage(channel.ChannelConfig{}, query); ok {
+		t.Fatal("expected inline-mode callback without message to be ignored")
+	}
+}
+
+// chatActionClient reports the API methods the bot calls, such as the typing refresh the
+// stream sends from its own goroutine.
+type chatActionClient struct {
+	methods chan string
+}
+
+func (c *chatActionClient) Do(req *http.Request) (*http.Response, error) {
+	c.methods <- req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":true}`)),
+	}, nil
+}
+
+func TestTelegramOutboundStream_FinalAttachesKeyboard(t *testing.T) {
+	adapter := NewTelegramAdapter(nil)
+	client := &chatActionClient{methods: make(chan string, 4)}
+	bot := &tgbotapi.BotAPI{Token: "fake", Client: client}
+	bot.SetAPIEndpoint(tgbotapi.APIEndpoint)
+	adapter.bots["fake"] = bot
+	s := &telegramOutboundStream{
+		adapter:      adapter,
+		cfg:          channel.ChannelConfig{ID: "test", Credentials: map[string]any{"bot_token": "fake"}},
+		target:       "1",
+		streamChatID: 1,
+		streamMsgID:  9,
+		lastEditedAt: time.Now().Add(-time.Minute),
+	}
+
+	origEdit := testEditFunc
+	origMarkup := testReplyMarkupFunc
+	testEditFunc = func(*tgbotapi.BotAPI, int64, int, string, string) error { return nil }
+	var gotMsgID int
+	var gotKeyboard *tgbotapi.InlineKeyboardMarkup
+	testReplyMarkupFunc = func(_ *tgbotapi.BotAPI, _ int64, msgID int, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+		gotMsgID = msgID
+		gotKeyboard = keyboard
+		return nil
+	}
+	defer func() {
+		testEditFunc = origEdit
+		testReplyMarkupFunc = origMarkup
+	}()
+
+	err := s.Push(context.Background(), channel.StreamEvent{
+		Type: channel.StreamEventFinal,
+		Final: &channel.StreamFinalizePayload{Message: channel.Message{
+			Text:    "Pick one",
+			Actions: []channel.Action{{Label: "A", Value: "a"}, {Label: "B", Value: "b"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	if gotMsgID != 9 || gotKeyboard == nil || len(gotKeyboard.InlineKeyboard) != 2 {
+		t.Fatalf("expected keyboard on streamed message, got msg=%d keyboard=%#v", gotMsgID, gotKeyboard)
+	}
+	select {
+	case method := <-client.methods:
+		if method != "sendChatAction" {
+			t.Fatalf("expected typing refresh, got %s", method)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the typing refresh")
+	}
+}
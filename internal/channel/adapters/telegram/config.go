@@ -1,15 +1,29 @@
 package telegram
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/memohai/memoh/internal/channel"
 )
 
+const (
+	inboundModePolling = "polling"
+	inboundModeWebhook = "webhook"
+
+	// webhookPathPrefix is where the webhook handler receives updates; the config ID is appended.
+	webhookPathPrefix = "/channels/telegram/webhook/"
+)
+
 // Config holds the Telegram bot credentials extracted from a channel configuration.
 type Config struct {
-	BotToken string
+	BotToken      string
+	InboundMode   string
+	WebhookURL    string
+	WebhookSecret string
 }
 
 // UserConfig holds the identifiers used to target a Telegram user or group.
@@ -20,13 +34,41 @@ type UserConfig struct {
 }
 
 func normalizeConfig(raw map[string]any) (map[string]any, error) {
-	cfg, err := parseConfig(raw)
+	cfg, err := parseConfig(withWebhookSecret(raw))
 	if err != nil {
 		return nil, err
 	}
-	return map[string]any{
-		"botToken": cfg.BotToken,
-	}, nil
+	result := map[string]any{
+		"botToken":    cfg.BotToken,
+		"inboundMode": cfg.InboundMode,
+	}
+	if cfg.InboundMode == inboundModeWebhook {
+		result["webhookUrl"] = cfg.WebhookURL
+		result["webhookSecret"] = cfg.WebhookSecret
+	}
+	return result, nil
+}
+
+// withWebhookSecret fills in a random webhook secret when webhook mode is selected
+// without one, so saving a config is enough to secure the endpoint.
+func withWebhookSecret(raw map[string]any) map[string]any {
+	mode, err := normalizeInboundMode(channel.ReadString(raw, "inboundMode", "inbound_mode"))
+	if err != nil || mode != inboundModeWebhook {
+		return raw
+	}
+	if strings.TrimSpace(channel.ReadString(raw, "webhookSecret", "webhook_secret")) != "" {
+		return raw
+	}
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return raw
+	}
+	result := make(map[string]any, len(raw)+1)
+	for key, value := range raw {
+		result[key] = value
+	}
+	result["webhookSecret"] = hex.EncodeToString(buf)
+	return result
 }
 
 func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
@@ -109,7 +151,55 @@ func parseConfig(raw map[string]any) (Config, error) {
 	if token == "" {
 		return Config{}, fmt.Errorf("telegram botToken is required")
 	}
-	return Config{BotToken: token}, nil
+	inboundMode, err := normalizeInboundMode(channel.ReadString(raw, "inboundMode", "inbound_mode"))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{BotToken: token, InboundMode: inboundMode}
+	if inboundMode != inboundModeWebhook {
+		return cfg, nil
+	}
+	cfg.WebhookURL = strings.TrimRight(strings.TrimSpace(channel.ReadString(raw, "webhookUrl", "webhook_url")), "/")
+	cfg.WebhookSecret = strings.TrimSpace(channel.ReadString(raw, "webhookSecret", "webhook_secret"))
+	parsed, err := url.Parse(cfg.WebhookURL)
+	if cfg.WebhookURL == "" || err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return Config{}, fmt.Errorf("telegram webhookUrl must be a public https URL in webhook mode")
+	}
+	if !isValidWebhookSecret(cfg.WebhookSecret) {
+		return Config{}, fmt.Errorf("telegram webhookSecret must be 1-256 characters of A-Z, a-z, 0-9, _ or -")
+	}
+	return cfg, nil
+}
+
+// webhookEndpoint returns the URL registered with setWebhook for the given config.
+func (c Config) webhookEndpoint(configID string) string {
+	return c.WebhookURL + webhookPathPrefix + url.PathEscape(configID)
+}
+
+func normalizeInboundMode(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", inboundModePolling:
+		return inboundModePolling, nil
+	case inboundModeWebhook:
+		return inboundModeWebhook, nil
+	default:
+		return "", fmt.Errorf("telegram inbound_mode must be polling or webhook")
+	}
+}
+
+// isValidWebhookSecret reports whether s is usable as a Bot API secret_token.
+func isValidWebhookSecret(s string) bool {
+	if len(s) == 0 || len(s) > 256 {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 func parseUserConfig(raw map[string]any) (UserConfig, error) {
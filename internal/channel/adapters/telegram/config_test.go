@@ -25,6 +25,60 @@ func TestNormalizeConfigRequiresToken(t *testing.T) {
 	}
 }
 
+func TestNormalizeConfigWebhookMode(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{
+		"bot_token":    "token-123",
+		"inbound_mode": "webhook",
+		"webhook_url":  "https://memoh.example.com/",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["inboundMode"] != inboundModeWebhook || got["webhookUrl"] != "https://memoh.example.com" {
+		t.Fatalf("unexpected config: %#v", got)
+	}
+	secret, _ := got["webhookSecret"].(string)
+	if !isValidWebhookSecret(secret) {
+		t.Fatalf("expected generated webhook secret, got %q", secret)
+	}
+	cfg, err := parseConfig(got)
+	if err != nil {
+		t.Fatalf("expected normalized config to parse, got %v", err)
+	}
+	if endpoint := cfg.webhookEndpoint("cfg-1"); endpoint != "https://memoh.example.com/channels/telegram/webhook/cfg-1" {
+		t.Fatalf("unexpected endpoint %q", endpoint)
+	}
+}
+
+func TestNormalizeConfigInboundModeValidation(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{"botToken": "token-123"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["inboundMode"] != inboundModePolling {
+		t.Fatalf("expected polling by default, got %#v", got["inboundMode"])
+	}
+	if _, ok := got["webhookUrl"]; ok {
+		t.Fatalf("expected no webhook fields in polling mode: %#v", got)
+	}
+
+	invalid := []map[string]any{
+		{"botToken": "token-123", "inboundMode": "push"},
+		{"botToken": "token-123", "inboundMode": "webhook"},
+		{"botToken": "token-123", "inboundMode": "webhook", "webhookUrl": "http://memoh.example.com"},
+		{"botToken": "token-123", "inboundMode": "webhook", "webhookUrl": "https://memoh.example.com", "webhookSecret": "bad secret!"},
+	}
+	for _, raw := range invalid {
+		if _, err := normalizeConfig(raw); err == nil {
+			t.Fatalf("expected error for %#v", raw)
+		}
+	}
+}
+
 func TestNormalizeUserConfig(t *testing.T) {
 	t.Parallel()
 
@@ -178,32 +178,3 @@ func TestFormatTelegramOutput(t *testing.T) {
 		})
 	}
 }
-
-func TestSplitCodeBlocks(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-		want  int // expected number of segments
-	}{
-		{name: "no code blocks", input: "hello world", want: 1},
-		{name: "one code block", input: "before```code```after", want: 3},
-		{name: "two code blocks", input: "a```b```c```d```e", want: 5},
-		{name: "unclosed code block", input: "before```unclosed", want: 1},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			segments := splitCodeBlocks(tt.input)
-			if len(segments) != tt.want {
-				t.Errorf("expected %d segments, got %d: %v", tt.want, len(segments), segments)
-			}
-		})
-	}
-}
-
-func TestTelegramEscapeHTML(t *testing.T) {
-	input := `a < b & c > d`
-	result := telegramEscapeHTML(input)
-	if result != "a &lt; b &amp; c &gt; d" {
-		t.Errorf("unexpected escape result: %s", result)
-	}
-}
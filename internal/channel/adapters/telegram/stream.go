@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,11 +21,14 @@ const telegramStreamPendingSuffix = "\n……"
 
 var testEditFunc func(bot *tgbotapi.BotAPI, chatID int64, msgID int, text string, parseMode string) error
 
+var testReplyMarkupFunc func(bot *tgbotapi.BotAPI, chatID int64, msgID int, keyboard *tgbotapi.InlineKeyboardMarkup) error
+
 type telegramOutboundStream struct {
 	adapter      *TelegramAdapter
 	cfg          channel.ChannelConfig
 	target       string
 	reply        *channel.ReplyRef
+	topicID      int
 	parseMode    string
 	closed       atomic.Bool
 	mu           sync.Mutex
@@ -44,7 +48,7 @@ func (s *telegramOutboundStream) getBot(ctx context.Context) (bot *tgbotapi.BotA
 	if err != nil {
 		return nil, err
 	}
-	return bot, nil
+	return withTelegramTopic(bot, s.topicID), nil
 }
 
 func (s *telegramOutboundStream) getBotAndReply(ctx context.Context) (bot *tgbotapi.BotAPI, replyTo int, err error) {
@@ -85,7 +89,7 @@ func (s *telegramOutboundStream) ensureStreamMessage(ctx context.Context, text s
 	} else {
 		text = strings.TrimSpace(text) + telegramStreamPendingSuffix
 	}
-	chatID, msgID, err := sendTelegramTextReturnMessage(bot, s.target, text, replyTo, s.parseMode)
+	chatID, msgID, err := sendTelegramTextReturnMessage(bot, s.target, text, replyTo, s.parseMode, nil)
 	if err != nil {
 		s.mu.Unlock()
 		return err
@@ -95,6 +99,7 @@ func (s *telegramOutboundStream) ensureStreamMessage(ctx context.Context, text s
 	s.lastEdited = text
 	s.lastEditedAt = time.Now()
 	s.mu.Unlock()
+	channel.ReportSentMessage(ctx, s.target, strconv.Itoa(msgID))
 	return nil
 }
 
@@ -201,6 +206,25 @@ func (s *telegramOutboundStream) editStreamMessageFinal(ctx context.Context, tex
 	return nil
 }
 
+// attachKeyboard adds the final message's buttons to the streamed message.
+func (s *telegramOutboundStream) attachKeyboard(ctx context.Context, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+	s.mu.Lock()
+	chatID := s.streamChatID
+	msgID := s.streamMsgID
+	s.mu.Unlock()
+	if msgID == 0 {
+		return nil
+	}
+	bot, err := s.getBot(ctx)
+	if err != nil {
+		return err
+	}
+	if testReplyMarkupFunc != nil {
+		return testReplyMarkupFunc(bot, chatID, msgID, keyboard)
+	}
+	return setTelegramReplyMarkup(bot, chatID, msgID, keyboard)
+}
+
 func (s *telegramOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
 	if s == nil || s.adapter == nil {
 		return fmt.Errorf("telegram stream not configured")
@@ -312,6 +336,11 @@ func (s *telegramOutboundStream) Push(ctx context.Context, event channel.StreamE
 		if err := s.editStreamMessageFinal(ctx, finalText); err != nil {
 			return err
 		}
+		if keyboard := buildTelegramInlineKeyboard(msg.Actions); keyboard != nil {
+			if err := s.attachKeyboard(ctx, keyboard); err != nil {
+				return err
+			}
+		}
 		if len(msg.Attachments) > 0 {
 			replyTo := parseReplyToMessageID(s.reply)
 			telegramCfg, err := parseConfig(s.cfg.Credentials)
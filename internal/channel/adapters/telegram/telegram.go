@@ -3,6 +3,7 @@ package telegram
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -23,11 +24,20 @@ import (
 )
 
 const telegramMaxMessageLength = 4096
+
+// telegramActionsFallbackText is sent when a message has buttons but no text, which Telegram rejects.
+const telegramActionsFallbackText = "Please choose:"
 const telegramMediaGroupCollectWindow = 700 * time.Millisecond
 
+const (
+	telegramPollTimeoutSeconds = 30
+	telegramPollRetryDelay     = 3 * time.Second
+)
+
 type telegramMediaGroupBuffer struct {
 	messages []*tgbotapi.Message
 	timer    *time.Timer
+	topicID  int
 }
 
 // assetOpener reads stored asset bytes by content hash.
@@ -37,10 +47,11 @@ type assetOpener interface {
 
 // TelegramAdapter implements the channel.Adapter, channel.Sender, and channel.Receiver interfaces for Telegram.
 type TelegramAdapter struct {
-	logger *slog.Logger
-	mu     sync.RWMutex
-	bots   map[string]*tgbotapi.BotAPI // keyed by bot token
-	assets assetOpener
+	logger   *slog.Logger
+	mu       sync.RWMutex
+	bots     map[string]*tgbotapi.BotAPI // keyed by bot token
+	webhooks map[string]*updateProcessor // keyed by config ID
+	assets   assetOpener
 }
 
 // NewTelegramAdapter creates a TelegramAdapter with the given logger.
@@ -49,8 +60,9 @@ func NewTelegramAdapter(log *slog.Logger) *TelegramAdapter {
 		log = slog.Default()
 	}
 	adapter := &TelegramAdapter{
-		logger: log.With(slog.String("adapter", "telegram")),
-		bots:   make(map[string]*tgbotapi.BotAPI),
+		logger:   log.With(slog.String("adapter", "telegram")),
+		bots:     make(map[string]*tgbotapi.BotAPI),
+		webhooks: make(map[string]*updateProcessor),
 	}
 	_ = tgbotapi.SetLogger(&slogBotLogger{log: adapter.logger})
 	return adapter
@@ -105,8 +117,17 @@ func (a *TelegramAdapter) Descriptor() channel.Descriptor {
 			Reply:          true,
 			Attachments:    true,
 			Media:          true,
+			Buttons:        true,
 			Streaming:      true,
 			BlockStreaming: true,
+			Edit:           true,
+			Unsend:         true,
+			Threads:        true,
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			// Telegram allows about one message per second to the same chat, with short bursts.
+			RateLimitPerSecond: 1,
+			RateLimitBurst:     3,
 		},
 		ConfigSchema: channel.ConfigSchema{
 			Version: 1,
@@ -116,6 +137,24 @@ func (a *TelegramAdapter) Descriptor() channel.Descriptor {
 					Required: true,
 					Title:    "Bot Token",
 				},
+				"inboundMode": {
+					Type:        channel.FieldEnum,
+					Title:       "Inbound Mode",
+					Description: "Choose long polling or a webhook registered with Telegram for inbound updates",
+					Enum:        []string{inboundModePolling, inboundModeWebhook},
+					Example:     inboundModePolling,
+				},
+				"webhookUrl": {
+					Type:        channel.FieldString,
+					Title:       "Webhook Base URL",
+					Description: "Public https URL of this server; updates are received at /channels/telegram/webhook/<config_id>",
+					Example:     "https://memoh.example.com",
+				},
+				"webhookSecret": {
+					Type:        channel.FieldSecret,
+					Title:       "Webhook Secret",
+					Description: "Secret token Telegram sends with each update; generated when left empty",
+				},
 			},
 		},
 		UserConfigSchema: channel.ConfigSchema{
@@ -166,7 +205,8 @@ func (a *TelegramAdapter) BuildUserConfig(identity channel.Identity) map[string]
 	return buildUserConfig(identity)
 }
 
-// Connect starts long-polling for Telegram updates and forwards messages to the handler.
+// Connect starts receiving Telegram updates, by long polling or through a webhook
+// depending on the configured inbound mode, and forwards messages to the handler.
 func (a *TelegramAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
 	if a.logger != nil {
 		a.logger.Info("start", slog.String("config_id", cfg.ID))
@@ -185,136 +225,129 @@ func (a *TelegramAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig
 		}
 		return nil, err
 	}
-	updateConfig := tgbotapi.NewUpdate(0)
-	updateConfig.Timeout = 30
-	updates := bot.GetUpdatesChan(updateConfig)
-	connCtx, cancel := context.WithCancel(ctx)
-	mediaGroups := make(map[string]*telegramMediaGroupBuffer)
-	var mediaGroupsMu sync.Mutex
-
-	var flushMediaGroup func(groupKey string)
-	flushMediaGroup = func(groupKey string) {
-		var batch []*tgbotapi.Message
-		mediaGroupsMu.Lock()
-		buffer, ok := mediaGroups[groupKey]
-		if ok {
-			delete(mediaGroups, groupKey)
-			batch = append(batch, buffer.messages...)
-		}
-		mediaGroupsMu.Unlock()
-		if !ok || len(batch) == 0 {
-			return
-		}
-		msg, ok := a.buildTelegramMediaGroupInboundMessage(bot, cfg, batch)
-		if !ok {
-			return
-		}
-		a.dispatchInbound(connCtx, cfg, handler, msg)
-	}
-	flushAllMediaGroups := func() {
-		mediaGroupsMu.Lock()
-		keys := make([]string, 0, len(mediaGroups))
-		for key, buffer := range mediaGroups {
-			keys = append(keys, key)
-			if buffer != nil && buffer.timer != nil {
-				buffer.timer.Stop()
-			}
-		}
-		mediaGroupsMu.Unlock()
-		for _, key := range keys {
-			flushMediaGroup(key)
-		}
+	if telegramCfg.InboundMode == inboundModeWebhook {
+		return a.connectWebhook(ctx, bot, cfg, telegramCfg, handler)
 	}
-	flushMediaGroupsByChat := func(chatID int64) {
-		if chatID == 0 {
-			return
-		}
-		mediaGroupsMu.Lock()
-		keys := make([]string, 0, len(mediaGroups))
-		for key, buffer := range mediaGroups {
-			if !isTelegramMediaGroupForChat(key, chatID) {
-				continue
-			}
-			keys = append(keys, key)
-			if buffer != nil && buffer.timer != nil {
-				buffer.timer.Stop()
-			}
-		}
-		mediaGroupsMu.Unlock()
-		for _, key := range keys {
-			flushMediaGroup(key)
-		}
+	// getUpdates is rejected while a webhook is set, e.g. after switching back from webhook mode.
+	if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil && a.logger != nil {
+		a.logger.Warn("delete webhook failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 	}
-	queueMediaGroup := func(msg *tgbotapi.Message) bool {
-		groupKey := telegramMediaGroupKey(msg)
-		if groupKey == "" {
-			return false
-		}
-		mediaGroupsMu.Lock()
-		buffer, ok := mediaGroups[groupKey]
-		if !ok {
-			buffer = &telegramMediaGroupBuffer{}
-			mediaGroups[groupKey] = buffer
-		}
-		buffer.messages = append(buffer.messages, msg)
-		if buffer.timer != nil {
-			buffer.timer.Stop()
+	connCtx, cancel := context.WithCancel(ctx)
+	processor := newUpdateProcessor(connCtx, a, bot, cfg, handler)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.pollUpdates(connCtx, bot, cfg, processor)
+		processor.flushAllMediaGroups()
+	}()
+
+	stop := func(_ context.Context) error {
+		if a.logger != nil {
+			a.logger.Info("stop", slog.String("config_id", cfg.ID))
 		}
-		buffer.timer = time.AfterFunc(telegramMediaGroupCollectWindow, func() {
-			flushMediaGroup(groupKey)
-		})
-		mediaGroupsMu.Unlock()
-		return true
+		cancel()
+		// Wait for the in-flight long poll to finish. Otherwise it keeps the old
+		// getUpdates session alive, causing "Conflict: terminated by other
+		// getUpdates request" when a new connection starts with the same bot token.
+		<-done
+		return nil
 	}
+	return channel.NewConnection(cfg, stop), nil
+}
 
-	go func() {
-		for {
-			select {
-			case <-connCtx.Done():
-				flushAllMediaGroups()
-				return
-			case update, ok := <-updates:
-				if !ok {
-					flushAllMediaGroups()
-					if a.logger != nil {
-						a.logger.Info("updates channel closed", slog.String("config_id", cfg.ID))
-					}
-					return
-				}
-				if update.Message == nil {
-					continue
-				}
-				if queueMediaGroup(update.Message) {
-					continue
-				}
-				flushMediaGroupsByChat(telegramChatID(update.Message))
-				msg, ok := a.buildTelegramInboundMessage(bot, cfg, update.Message)
-				if !ok {
-					continue
-				}
-				a.dispatchInbound(connCtx, cfg, handler, msg)
-			}
+// connectWebhook registers the webhook with Telegram and routes updates posted to
+// the webhook handler into a processor for this config until the connection stops.
+func (a *TelegramAdapter) connectWebhook(ctx context.Context, bot *tgbotapi.BotAPI, cfg channel.ChannelConfig, telegramCfg Config, handler channel.InboundHandler) (channel.Connection, error) {
+	connCtx, cancel := context.WithCancel(ctx)
+	processor := newUpdateProcessor(connCtx, a, bot, cfg, handler)
+	processor.secret = telegramCfg.WebhookSecret
+	a.mu.Lock()
+	a.webhooks[cfg.ID] = processor
+	a.mu.Unlock()
+
+	params := tgbotapi.Params{}
+	params["url"] = telegramCfg.webhookEndpoint(cfg.ID)
+	params["secret_token"] = telegramCfg.WebhookSecret
+	if _, err := bot.MakeRequest("setWebhook", params); err != nil {
+		a.removeWebhookProcessor(cfg.ID, processor)
+		cancel()
+		if a.logger != nil {
+			a.logger.Error("set webhook failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 		}
-	}()
+		return nil, fmt.Errorf("set telegram webhook: %w", err)
+	}
+	if a.logger != nil {
+		a.logger.Info("webhook registered", slog.String("config_id", cfg.ID), slog.String("url", params["url"]))
+	}
 
 	stop := func(_ context.Context) error {
 		if a.logger != nil {
 			a.logger.Info("stop", slog.String("config_id", cfg.ID))
 		}
-		bot.StopReceivingUpdates()
-		cancel()
-		// Drain remaining updates so the library's polling goroutine can
-		// finish writing and exit. Without this, the in-flight long-poll
-		// HTTP request keeps the old getUpdates session alive, causing
-		// "Conflict: terminated by other getUpdates request" when a new
-		// connection starts with the same bot token.
-		for range updates {
+		a.removeWebhookProcessor(cfg.ID, processor)
+		// Removing the webhook makes Telegram queue updates until the next connection
+		// instead of retrying deliveries nobody is listening for.
+		if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil && a.logger != nil {
+			a.logger.Warn("delete webhook failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 		}
+		cancel()
+		processor.flushAllMediaGroups()
 		return nil
 	}
 	return channel.NewConnection(cfg, stop), nil
 }
 
+// pollUpdates long-polls getUpdates until ctx is done. The client's own polling loop
+// is not used because it drops the forum topic fields of updates.
+func (a *TelegramAdapter) pollUpdates(ctx context.Context, bot *tgbotapi.BotAPI, cfg channel.ChannelConfig, processor *updateProcessor) {
+	offset := 0
+	for ctx.Err() == nil {
+		params := tgbotapi.Params{}
+		params.AddNonZero("offset", offset)
+		params.AddNonZero("timeout", telegramPollTimeoutSeconds)
+		resp, err := bot.MakeRequest("getUpdates", params)
+		var updates []telegramUpdate
+		if err == nil {
+			err = json.Unmarshal(resp.Result, &updates)
+		}
+		if err != nil {
+			if a.logger != nil {
+				a.logger.Warn("get updates failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(telegramPollRetryDelay):
+			}
+			continue
+		}
+		for _, update := range updates {
+			if update.UpdateID >= offset {
+				offset = update.UpdateID + 1
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			processor.handle(update)
+		}
+	}
+}
+
+func (a *TelegramAdapter) webhookProcessor(configID string) (*updateProcessor, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	processor, ok := a.webhooks[configID]
+	return processor, ok
+}
+
+// removeWebhookProcessor unregisters processor unless a newer connection has replaced it.
+func (a *TelegramAdapter) removeWebhookProcessor(configID string, processor *updateProcessor) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.webhooks[configID] == processor {
+		delete(a.webhooks, configID)
+	}
+}
+
 func telegramMediaGroupKey(msg *tgbotapi.Message) string {
 	if msg == nil {
 		return ""
@@ -350,6 +383,18 @@ func (a *TelegramAdapter) dispatchInbound(ctx context.Context, cfg channel.Chann
 	}()
 }
 
+func (a *TelegramAdapter) handleCallbackQuery(ctx context.Context, bot *tgbotapi.BotAPI, cfg channel.ChannelConfig, handler channel.InboundHandler, query *tgbotapi.CallbackQuery, topicID int) {
+	if err := answerTelegramCallback(bot, query.ID); err != nil && a.logger != nil {
+		a.logger.Warn("answer callback query failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+	}
+	msg, ok := buildTelegramCallbackInboundMessage(cfg, query)
+	if !ok {
+		return
+	}
+	applyTelegramTopic(&msg, topicID)
+	a.dispatchInbound(ctx, cfg, handler, msg)
+}
+
 func (a *TelegramAdapter) buildTelegramInboundMessage(bot *tgbotapi.BotAPI, cfg channel.ChannelConfig, raw *tgbotapi.Message) (channel.InboundMessage, bool) {
 	text := strings.TrimSpace(raw.Text)
 	caption := strings.TrimSpace(raw.Caption)
@@ -360,6 +405,24 @@ func (a *TelegramAdapter) buildTelegramInboundMessage(bot *tgbotapi.BotAPI, cfg
 	return a.toInboundTelegramMessage(bot, cfg, raw, text, attachments, nil)
 }
 
+// buildTelegramEditedInboundMessage maps an edited message to a message_edited event
+// carrying the new text. Attachments are not re-collected for edits.
+func (a *TelegramAdapter) buildTelegramEditedInboundMessage(bot *tgbotapi.BotAPI, cfg channel.ChannelConfig, raw *tgbotapi.Message) (channel.InboundMessage, bool) {
+	text := strings.TrimSpace(raw.Text)
+	if text == "" {
+		text = strings.TrimSpace(raw.Caption)
+	}
+	msg, ok := a.toInboundTelegramMessage(bot, cfg, raw, text, nil, nil)
+	if !ok {
+		return channel.InboundMessage{}, false
+	}
+	msg.Event = channel.InboundEventMessageEdited
+	if raw.EditDate > 0 {
+		msg.ReceivedAt = time.Unix(int64(raw.EditDate), 0).UTC()
+	}
+	return msg, true
+}
+
 func (a *TelegramAdapter) buildTelegramMediaGroupInboundMessage(
 	bot *tgbotapi.BotAPI,
 	cfg channel.ChannelConfig,
@@ -521,21 +584,31 @@ func (a *TelegramAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, m
 	if err != nil {
 		return err
 	}
+	bot = withTelegramTopic(bot, telegramTopicID(msg.Message.Thread))
 	if msg.Message.IsEmpty() {
 		return fmt.Errorf("message is required")
 	}
 	text := strings.TrimSpace(msg.Message.PlainText())
 	text, parseMode := formatTelegramOutput(text, msg.Message.Format)
 	replyTo := parseReplyToMessageID(msg.Message.Reply)
+	keyboard := buildTelegramInlineKeyboard(msg.Message.Actions)
+	if keyboard != nil && text == "" {
+		text = telegramActionsFallbackText
+	}
 	if len(msg.Message.Attachments) > 0 {
-		usedCaption := false
+		// The keyboard goes on a separate text message so it stays below all attachments.
+		usedCaption := text != "" && keyboard == nil
+		// Attachments delivered by an earlier attempt are not sent again on retry.
+		progress := channel.SendProgressFromContext(ctx)
 		for i, att := range msg.Message.Attachments {
+			if progress.AttachmentSent(i) {
+				continue
+			}
 			caption := ""
-			if !usedCaption && text != "" {
+			applyReply := replyTo
+			if i == 0 && usedCaption {
 				caption = text
-				usedCaption = true
 			}
-			applyReply := replyTo
 			if i > 0 {
 				applyReply = 0
 			}
@@ -543,15 +616,16 @@ func (a *TelegramAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, m
 				if a.logger != nil {
 					a.logger.Error("send attachment failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 				}
-				return err
+				return telegramRateLimitError(err)
 			}
+			progress.MarkAttachmentSent(i)
 		}
 		if text != "" && !usedCaption {
-			return sendTelegramText(bot, to, text, replyTo, parseMode)
+			return telegramRateLimitError(sendTelegramText(ctx, bot, to, text, replyTo, parseMode, keyboard))
 		}
 		return nil
 	}
-	return sendTelegramText(bot, to, text, replyTo, parseMode)
+	return telegramRateLimitError(sendTelegramText(ctx, bot, to, text, replyTo, parseMode, keyboard))
 }
 
 // OpenStream opens a Telegram streaming session.
@@ -572,6 +646,7 @@ func (a *TelegramAdapter) OpenStream(ctx context.Context, cfg channel.ChannelCon
 		cfg:       cfg,
 		target:    target,
 		reply:     opts.Reply,
+		topicID:   telegramTopicID(opts.Thread),
 		parseMode: "",
 	}, nil
 }
@@ -593,6 +668,9 @@ func resolveTelegramSender(msg *tgbotapi.Message) (string, string, map[string]st
 		if username != "" {
 			attrs["username"] = username
 		}
+		if msg.From.IsBot {
+			attrs["is_bot"] = "true"
+		}
 		displayName := strings.TrimSpace(msg.From.UserName)
 		if displayName == "" {
 			displayName = strings.TrimSpace(msg.From.FirstName + " " + msg.From.LastName)
@@ -645,13 +723,17 @@ func parseReplyToMessageID(reply *channel.ReplyRef) int {
 	return value
 }
 
-func sendTelegramText(bot *tgbotapi.BotAPI, target string, text string, replyTo int, parseMode string) error {
-	_, _, err := sendTelegramTextReturnMessage(bot, target, text, replyTo, parseMode)
+func sendTelegramText(ctx context.Context, bot *tgbotapi.BotAPI, target string, text string, replyTo int, parseMode string, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+	_, messageID, err := sendTelegramTextReturnMessage(bot, target, text, replyTo, parseMode, keyboard)
+	if err == nil {
+		channel.ReportSentMessage(ctx, target, strconv.Itoa(messageID))
+	}
 	return err
 }
 
 // sendTelegramTextReturnMessage sends a text message and returns the chat ID and message ID for later editing.
-func sendTelegramTextReturnMessage(bot *tgbotapi.BotAPI, target string, text string, replyTo int, parseMode string) (chatID int64, messageID int, err error) {
+// keyboard, when non-nil, is attached as the message's inline keyboard.
+func sendTelegramTextReturnMessage(bot *tgbotapi.BotAPI, target string, text string, replyTo int, parseMode string, keyboard *tgbotapi.InlineKeyboardMarkup) (chatID int64, messageID int, err error) {
 	text = truncateTelegramText(sanitizeTelegramText(text))
 	var sent tgbotapi.Message
 	if strings.HasPrefix(target, "@") {
@@ -660,6 +742,9 @@ func sendTelegramTextReturnMessage(bot *tgbotapi.BotAPI, target string, text str
 		if replyTo > 0 {
 			message.ReplyToMessageID = replyTo
 		}
+		if keyboard != nil {
+			message.ReplyMarkup = *keyboard
+		}
 		sent, err = bot.Send(message)
 		if err != nil {
 			return 0, 0, err
@@ -674,6 +759,9 @@ func sendTelegramTextReturnMessage(bot *tgbotapi.BotAPI, target string, text str
 		if replyTo > 0 {
 			message.ReplyToMessageID = replyTo
 		}
+		if keyboard != nil {
+			message.ReplyMarkup = *keyboard
+		}
 		sent, err = bot.Send(message)
 		if err != nil {
 			return 0, 0, err
@@ -738,6 +826,19 @@ func getTelegramRetryAfter(err error) time.Duration {
 	return 0
 }
 
+// telegramRateLimitError marks 429 responses so the channel manager waits for
+// retry_after before sending to the chat again.
+func telegramRateLimitError(err error) error {
+	if !isTelegramTooManyRequests(err) {
+		return err
+	}
+	retryAfter := getTelegramRetryAfter(err)
+	if retryAfter <= 0 {
+		retryAfter = time.Second
+	}
+	return channel.NewRateLimitError(err, retryAfter)
+}
+
 func sendTelegramAttachmentWithAssets(ctx context.Context, bot *tgbotapi.BotAPI, target string, att channel.Attachment, caption string, replyTo int, parseMode string, opener assetOpener) error {
 	return sendTelegramAttachmentImpl(ctx, bot, target, att, caption, replyTo, parseMode, opener)
 }
@@ -746,7 +847,7 @@ func sendTelegramAttachment(bot *tgbotapi.BotAPI, target string, att channel.Att
 	return sendTelegramAttachmentImpl(context.Background(), bot, target, att, caption, replyTo, parseMode, nil)
 }
 
-func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target string, att channel.Attachment, caption string, replyTo int, parseMode string, opener assetOpener) error {
+func sendTelegramAttachmentImpl(ctx context.Context, bot *tgbotapi.BotAPI, target string, att channel.Attachment, caption string, replyTo int, parseMode string, opener assetOpener) error {
 	urlRef := strings.TrimSpace(att.URL)
 	keyRef := strings.TrimSpace(att.PlatformKey)
 	sourcePlatform := strings.TrimSpace(att.SourcePlatform)
@@ -786,8 +887,8 @@ func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target
 		if replyTo > 0 {
 			photo.ReplyToMessageID = replyTo
 		}
-		_, err := bot.Send(photo)
-		return err
+		sent, err := bot.Send(photo)
+		return reportTelegramSent(ctx, target, sent, err)
 	case channel.AttachmentFile, "":
 		var document tgbotapi.DocumentConfig
 		if isChannel {
@@ -809,8 +910,8 @@ func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target
 		if replyTo > 0 {
 			document.ReplyToMessageID = replyTo
 		}
-		_, sendErr := bot.Send(document)
-		return sendErr
+		sent, sendErr := bot.Send(document)
+		return reportTelegramSent(ctx, target, sent, sendErr)
 	case channel.AttachmentAudio:
 		audio, err := buildTelegramAudio(target, file)
 		if err != nil {
@@ -821,8 +922,8 @@ func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target
 		if replyTo > 0 {
 			audio.ReplyToMessageID = replyTo
 		}
-		_, err = bot.Send(audio)
-		return err
+		sent, err := bot.Send(audio)
+		return reportTelegramSent(ctx, target, sent, err)
 	case channel.AttachmentVoice:
 		voice, err := buildTelegramVoice(target, file)
 		if err != nil {
@@ -833,8 +934,8 @@ func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target
 		if replyTo > 0 {
 			voice.ReplyToMessageID = replyTo
 		}
-		_, err = bot.Send(voice)
-		return err
+		sent, err := bot.Send(voice)
+		return reportTelegramSent(ctx, target, sent, err)
 	case channel.AttachmentVideo:
 		video, err := buildTelegramVideo(target, file)
 		if err != nil {
@@ -845,8 +946,8 @@ func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target
 		if replyTo > 0 {
 			video.ReplyToMessageID = replyTo
 		}
-		_, err = bot.Send(video)
-		return err
+		sent, err := bot.Send(video)
+		return reportTelegramSent(ctx, target, sent, err)
 	case channel.AttachmentGIF:
 		animation, err := buildTelegramAnimation(target, file)
 		if err != nil {
@@ -857,8 +958,8 @@ func sendTelegramAttachmentImpl(_ context.Context, bot *tgbotapi.BotAPI, target
 		if replyTo > 0 {
 			animation.ReplyToMessageID = replyTo
 		}
-		_, err = bot.Send(animation)
-		return err
+		sent, err := bot.Send(animation)
+		return reportTelegramSent(ctx, target, sent, err)
 	default:
 		return fmt.Errorf("unsupported attachment type: %s", att.Type)
 	}
@@ -1248,6 +1349,7 @@ func (a *TelegramAdapter) ProcessingStarted(ctx context.Context, cfg channel.Cha
 	if err != nil {
 		return channel.ProcessingStatusHandle{}, err
 	}
+	bot = withTelegramTopic(bot, telegramTopicID(msg.Message.Thread))
 	if err := sendTelegramTyping(bot, chatID); err != nil && a.logger != nil {
 		a.logger.Warn("send typing action failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
 	}
@@ -1318,3 +1420,54 @@ func (a *TelegramAdapter) Unreact(ctx context.Context, cfg channel.ChannelConfig
 	}
 	return clearTelegramReaction(bot, target, messageID)
 }
+
+// reportTelegramSent records the ID of a delivered message for the channel manager.
+func reportTelegramSent(ctx context.Context, target string, sent tgbotapi.Message, err error) error {
+	if err == nil && sent.MessageID != 0 {
+		channel.ReportSentMessage(ctx, target, strconv.Itoa(sent.MessageID))
+	}
+	return err
+}
+
+// Update replaces the text of a message the bot sent earlier (implements channel.MessageEditor).
+func (a *TelegramAdapter) Update(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string, msg channel.Message) error {
+	telegramCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	bot, err := a.getOrCreateBot(telegramCfg.BotToken, cfg.ID)
+	if err != nil {
+		return err
+	}
+	text, parseMode := formatTelegramOutput(strings.TrimSpace(msg.PlainText()), msg.Format)
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("telegram edit requires text")
+	}
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("chat_id", strings.TrimSpace(target))
+	params.AddNonEmpty("message_id", strings.TrimSpace(messageID))
+	params.AddNonEmpty("text", truncateTelegramText(sanitizeTelegramText(text)))
+	params.AddNonEmpty("parse_mode", parseMode)
+	_, err = bot.MakeRequest("editMessageText", params)
+	if err != nil && isTelegramMessageNotModified(err) {
+		return nil
+	}
+	return telegramRateLimitError(err)
+}
+
+// Unsend deletes a message the bot sent earlier (implements channel.MessageEditor).
+func (a *TelegramAdapter) Unsend(ctx context.Context, cfg channel.ChannelConfig, target string, messageID string) error {
+	telegramCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	bot, err := a.getOrCreateBot(telegramCfg.BotToken, cfg.ID)
+	if err != nil {
+		return err
+	}
+	params := tgbotapi.Params{}
+	params.AddNonEmpty("chat_id", strings.TrimSpace(target))
+	params.AddNonEmpty("message_id", strings.TrimSpace(messageID))
+	_, err = bot.MakeRequest("deleteMessage", params)
+	return telegramRateLimitError(err)
+}
@@ -255,6 +255,35 @@ func TestBuildTelegramMediaGroupInboundMessageAggregatesAttachments(t *testing.T
 	}
 }
 
+func TestBuildTelegramEditedInboundMessage(t *testing.T) {
+	t.Parallel()
+
+	adapter := NewTelegramAdapter(nil)
+	bot := &tgbotapi.BotAPI{Token: "test", Self: tgbotapi.User{ID: 1001, UserName: "memohbot"}}
+	raw := &tgbotapi.Message{
+		MessageID: 55,
+		Date:      1710000000,
+		EditDate:  1710000060,
+		Chat:      &tgbotapi.Chat{ID: 42, Type: "private"},
+		From:      &tgbotapi.User{ID: 10, UserName: "alice"},
+		Text:      "corrected text",
+	}
+
+	inbound, ok := adapter.buildTelegramEditedInboundMessage(bot, channel.ChannelConfig{BotID: "bot-1"}, raw)
+	if !ok {
+		t.Fatal("expected edited inbound message")
+	}
+	if inbound.Event != channel.InboundEventMessageEdited || !inbound.IsHistoryUpdate() {
+		t.Fatalf("unexpected event: %q", inbound.Event)
+	}
+	if inbound.Message.ID != "55" || inbound.Message.Text != "corrected text" {
+		t.Fatalf("unexpected message: %+v", inbound.Message)
+	}
+	if inbound.ReceivedAt.Unix() != 1710000060 {
+		t.Fatalf("expected edit date, got %s", inbound.ReceivedAt)
+	}
+}
+
 func TestIsTelegramMediaGroupForChat(t *testing.T) {
 	t.Parallel()
 
@@ -484,6 +513,25 @@ func TestGetTelegramRetryAfter(t *testing.T) {
 	}
 }
 
+func TestTelegramRateLimitError(t *testing.T) {
+	t.Parallel()
+
+	err := telegramRateLimitError(tgbotapi.Error{Code: 429, Message: "Too Many Requests", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 4}})
+	if got := channel.RetryAfterHint(err); got != 4*time.Second {
+		t.Fatalf("expected 4s retry-after, got %v", got)
+	}
+	if got := channel.RetryAfterHint(telegramRateLimitError(tgbotapi.Error{Code: 429})); got != time.Second {
+		t.Fatalf("expected default retry-after, got %v", got)
+	}
+	plain := tgbotapi.Error{Code: 400, Message: "Bad Request"}
+	if got := telegramRateLimitError(plain); got != error(plain) {
+		t.Fatalf("non-429 errors must pass through, got %v", got)
+	}
+	if telegramRateLimitError(nil) != nil {
+		t.Fatal("nil must stay nil")
+	}
+}
+
 func TestTruncateTelegramText(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// The pinned Bot API client predates forum topics, so message_thread_id is neither
+// decoded from updates nor accepted by its request configs. Updates are decoded a
+// second time for the topic fields, and outbound requests get the topic added by a
+// wrapping HTTP client.
+
+// telegramTopicFields holds the forum topic fields of a message.
+type telegramTopicFields struct {
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+}
+
+// topicID returns the forum topic of the message, or 0. message_thread_id alone is
+// also set for reply threads in regular groups, which are not topics.
+func (f *telegramTopicFields) topicID() int {
+	if f == nil || !f.IsTopicMessage {
+		return 0
+	}
+	return f.MessageThreadID
+}
+
+// telegramUpdate is a Bot API update together with the topic fields the client drops.
+type telegramUpdate struct {
+	tgbotapi.Update
+	topics struct {
+		Message       *telegramTopicFields `json:"message"`
+		EditedMessage *telegramTopicFields `json:"edited_message"`
+		CallbackQuery *struct {
+			Message *telegramTopicFields `json:"message"`
+		} `json:"callback_query"`
+	}
+}
+
+// UnmarshalJSON decodes the update and its topic fields.
+func (u *telegramUpdate) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &u.Update); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &u.topics)
+}
+
+func (u *telegramUpdate) messageTopicID() int {
+	return u.topics.Message.topicID()
+}
+
+func (u *telegramUpdate) editedMessageTopicID() int {
+	return u.topics.EditedMessage.topicID()
+}
+
+func (u *telegramUpdate) callbackTopicID() int {
+	if u.topics.CallbackQuery == nil {
+		return 0
+	}
+	return u.topics.CallbackQuery.Message.topicID()
+}
+
+// applyTelegramTopic maps a forum topic to a thread of the group's conversation.
+// Replies keep targeting the group chat; the topic travels as the thread.
+func applyTelegramTopic(msg *channel.InboundMessage, topicID int) {
+	if msg == nil || topicID <= 0 {
+		return
+	}
+	threadID := strconv.Itoa(topicID)
+	msg.Conversation.ThreadID = threadID
+	msg.Message.Thread = &channel.ThreadRef{ID: threadID}
+}
+
+// telegramTopicID parses the forum topic of an outbound thread reference, or 0.
+func telegramTopicID(thread *channel.ThreadRef) int {
+	if thread == nil {
+		return 0
+	}
+	topicID, err := strconv.Atoi(strings.TrimSpace(thread.ID))
+	if err != nil || topicID <= 0 {
+		return 0
+	}
+	return topicID
+}
+
+// withTelegramTopic returns a view of bot whose send requests are posted into the
+// given forum topic. The view shares the token and HTTP client of bot.
+func withTelegramTopic(bot *tgbotapi.BotAPI, topicID int) *tgbotapi.BotAPI {
+	if bot == nil || topicID <= 0 {
+		return bot
+	}
+	view := *bot
+	view.Client = &telegramTopicClient{base: bot.Client, topicID: strconv.Itoa(topicID)}
+	return &view
+}
+
+// telegramTopicClient adds message_thread_id to the query string of requests that
+// post into a chat. The Bot API reads parameters from the query string as well as
+// the body, which also covers multipart uploads.
+type telegramTopicClient struct {
+	base    tgbotapi.HTTPClient
+	topicID string
+}
+
+func (c *telegramTopicClient) Do(req *http.Request) (*http.Response, error) {
+	if req != nil && req.URL != nil && isTelegramTopicMethod(path.Base(req.URL.Path)) {
+		query := req.URL.Query()
+		query.Set("message_thread_id", c.topicID)
+		req.URL.RawQuery = query.Encode()
+	}
+	return c.base.Do(req)
+}
+
+func isTelegramTopicMethod(method string) bool {
+	return strings.HasPrefix(method, "send") || method == "copyMessage" || method == "forwardMessage"
+}
@@ -0,0 +1,110 @@
+package telegram
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestTelegramUpdateTopicIDs(t *testing.T) {
+	t.Parallel()
+
+	var topic telegramUpdate
+	if err := json.Unmarshal([]byte(`{"update_id":1,"message":{"message_id":5,"message_thread_id":77,"is_topic_message":true,"chat":{"id":-100,"type":"supergroup"},"text":"hi"}}`), &topic); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if topic.UpdateID != 1 || topic.Message == nil || topic.Message.Text != "hi" {
+		t.Fatalf("expected embedded update decoded, got %+v", topic.Update)
+	}
+	if got := topic.messageTopicID(); got != 77 {
+		t.Fatalf("expected topic 77, got %d", got)
+	}
+
+	// Replies in regular groups carry message_thread_id without being topic messages.
+	var reply telegramUpdate
+	if err := json.Unmarshal([]byte(`{"update_id":2,"message":{"message_id":6,"message_thread_id":5,"chat":{"id":-100,"type":"supergroup"},"text":"re"}}`), &reply); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := reply.messageTopicID(); got != 0 {
+		t.Fatalf("expected no topic for reply thread, got %d", got)
+	}
+
+	var callback telegramUpdate
+	if err := json.Unmarshal([]byte(`{"update_id":3,"callback_query":{"id":"q","data":"x","message":{"message_id":7,"message_thread_id":9,"is_topic_message":true}}}`), &callback); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := callback.callbackTopicID(); got != 9 {
+		t.Fatalf("expected callback topic 9, got %d", got)
+	}
+}
+
+func TestApplyTelegramTopic(t *testing.T) {
+	t.Parallel()
+
+	msg := channel.InboundMessage{Conversation: channel.Conversation{ID: "-100", Type: "supergroup"}, ReplyTarget: "-100"}
+	applyTelegramTopic(&msg, 77)
+	if msg.Conversation.ThreadID != "77" || msg.Message.Thread == nil || msg.Message.Thread.ID != "77" {
+		t.Fatalf("expected topic thread, got %+v", msg)
+	}
+	if msg.Conversation.ID != "-100" || msg.ReplyTarget != "-100" {
+		t.Fatalf("expected group chat kept as conversation and target, got %+v", msg)
+	}
+
+	plain := channel.InboundMessage{}
+	applyTelegramTopic(&plain, 0)
+	if plain.Conversation.ThreadID != "" || plain.Message.Thread != nil {
+		t.Fatalf("expected no thread, got %+v", plain)
+	}
+}
+
+type recordingTelegramClient struct {
+	requests []*http.Request
+}
+
+func (c *recordingTelegramClient) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":true}`)),
+	}, nil
+}
+
+func TestWithTelegramTopicAddsThreadToSendRequests(t *testing.T) {
+	t.Parallel()
+
+	client := &recordingTelegramClient{}
+	bot := &tgbotapi.BotAPI{Token: "test", Client: client}
+	bot.SetAPIEndpoint(tgbotapi.APIEndpoint)
+
+	if got := withTelegramTopic(bot, 0); got != bot {
+		t.Fatalf("expected bot unchanged without topic")
+	}
+	topicBot := withTelegramTopic(bot, telegramTopicID(&channel.ThreadRef{ID: "77"}))
+	if _, err := topicBot.MakeRequest("sendMessage", tgbotapi.Params{"chat_id": "-100", "text": "hi"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if _, err := topicBot.MakeRequest("editMessageText", tgbotapi.Params{"chat_id": "-100", "text": "hi"}); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if _, err := bot.MakeRequest("sendMessage", tgbotapi.Params{"chat_id": "-100", "text": "hi"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(client.requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(client.requests))
+	}
+	if got := client.requests[0].URL.Query().Get("message_thread_id"); got != "77" {
+		t.Fatalf("expected topic on sendMessage, got %q", got)
+	}
+	if got := client.requests[1].URL.Query().Get("message_thread_id"); got != "" {
+		t.Fatalf("expected no topic on editMessageText, got %q", got)
+	}
+	if got := client.requests[2].URL.Query().Get("message_thread_id"); got != "" {
+		t.Fatalf("expected original bot unaffected, got %q", got)
+	}
+}
@@ -0,0 +1,176 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// updateDedupTTL bounds how long update IDs are remembered. Telegram redelivers a
+// webhook update until it is answered, and a mode switch can replay pending updates.
+const updateDedupTTL = 10 * time.Minute
+
+// updateProcessor turns raw Bot API updates of one channel config into inbound
+// messages. It is shared by long polling and webhook delivery so both modes buffer
+// media groups and handle callbacks and edits the same way.
+type updateProcessor struct {
+	adapter *TelegramAdapter
+	bot     *tgbotapi.BotAPI
+	cfg     channel.ChannelConfig
+	handler channel.InboundHandler
+	ctx     context.Context
+	secret  string
+
+	mu          sync.Mutex
+	mediaGroups map[string]*telegramMediaGroupBuffer
+	seenUpdates map[int]time.Time
+}
+
+func newUpdateProcessor(ctx context.Context, a *TelegramAdapter, bot *tgbotapi.BotAPI, cfg channel.ChannelConfig, handler channel.InboundHandler) *updateProcessor {
+	return &updateProcessor{
+		adapter:     a,
+		bot:         bot,
+		cfg:         cfg,
+		handler:     handler,
+		ctx:         ctx,
+		mediaGroups: make(map[string]*telegramMediaGroupBuffer),
+		seenUpdates: make(map[int]time.Time),
+	}
+}
+
+// handle processes one update. Updates already seen within updateDedupTTL are dropped.
+func (p *updateProcessor) handle(update telegramUpdate) {
+	if p.isDuplicate(update.UpdateID) {
+		return
+	}
+	if update.CallbackQuery != nil {
+		p.adapter.handleCallbackQuery(p.ctx, p.bot, p.cfg, p.handler, update.CallbackQuery, update.callbackTopicID())
+		return
+	}
+	// The Bot API reports edits but neither deletions nor, in this
+	// client version, message reactions.
+	if update.EditedMessage != nil {
+		if msg, ok := p.adapter.buildTelegramEditedInboundMessage(p.bot, p.cfg, update.EditedMessage); ok {
+			applyTelegramTopic(&msg, update.editedMessageTopicID())
+			p.adapter.dispatchInbound(p.ctx, p.cfg, p.handler, msg)
+		}
+		return
+	}
+	if update.Message == nil {
+		return
+	}
+	topicID := update.messageTopicID()
+	if p.queueMediaGroup(update.Message, topicID) {
+		return
+	}
+	p.flushMediaGroupsByChat(telegramChatID(update.Message))
+	msg, ok := p.adapter.buildTelegramInboundMessage(p.bot, p.cfg, update.Message)
+	if !ok {
+		return
+	}
+	applyTelegramTopic(&msg, topicID)
+	p.adapter.dispatchInbound(p.ctx, p.cfg, p.handler, msg)
+}
+
+func (p *updateProcessor) isDuplicate(updateID int) bool {
+	if updateID <= 0 {
+		return false
+	}
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, seenAt := range p.seenUpdates {
+		if now.Sub(seenAt) > updateDedupTTL {
+			delete(p.seenUpdates, id)
+		}
+	}
+	if _, ok := p.seenUpdates[updateID]; ok {
+		return true
+	}
+	p.seenUpdates[updateID] = now
+	return false
+}
+
+func (p *updateProcessor) flushMediaGroup(groupKey string) {
+	var batch []*tgbotapi.Message
+	topicID := 0
+	p.mu.Lock()
+	buffer, ok := p.mediaGroups[groupKey]
+	if ok {
+		delete(p.mediaGroups, groupKey)
+		batch = append(batch, buffer.messages...)
+		topicID = buffer.topicID
+	}
+	p.mu.Unlock()
+	if !ok || len(batch) == 0 {
+		return
+	}
+	msg, ok := p.adapter.buildTelegramMediaGroupInboundMessage(p.bot, p.cfg, batch)
+	if !ok {
+		return
+	}
+	applyTelegramTopic(&msg, topicID)
+	p.adapter.dispatchInbound(p.ctx, p.cfg, p.handler, msg)
+}
+
+func (p *updateProcessor) flushAllMediaGroups() {
+	p.mu.Lock()
+	keys := make([]string, 0, len(p.mediaGroups))
+	for key, buffer := range p.mediaGroups {
+		keys = append(keys, key)
+		if buffer != nil && buffer.timer != nil {
+			buffer.timer.Stop()
+		}
+	}
+	p.mu.Unlock()
+	for _, key := range keys {
+		p.flushMediaGroup(key)
+	}
+}
+
+func (p *updateProcessor) flushMediaGroupsByChat(chatID int64) {
+	if chatID == 0 {
+		return
+	}
+	p.mu.Lock()
+	keys := make([]string, 0, len(p.mediaGroups))
+	for key, buffer := range p.mediaGroups {
+		if !isTelegramMediaGroupForChat(key, chatID) {
+			continue
+		}
+		keys = append(keys, key)
+		if buffer != nil && buffer.timer != nil {
+			buffer.timer.Stop()
+		}
+	}
+	p.mu.Unlock()
+	for _, key := range keys {
+		p.flushMediaGroup(key)
+	}
+}
+
+func (p *updateProcessor) queueMediaGroup(msg *tgbotapi.Message, topicID int) bool {
+	groupKey := telegramMediaGroupKey(msg)
+	if groupKey == "" {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	buffer, ok := p.mediaGroups[groupKey]
+	if !ok {
+		buffer = &telegramMediaGroupBuffer{topicID: topicID}
+		p.mediaGroups[groupKey] = buffer
+	}
+	buffer.messages = append(buffer.messages, msg)
+	if buffer.timer != nil {
+		buffer.timer.Stop()
+	}
+	buffer.timer = time.AfterFunc(telegramMediaGroupCollectWindow, func() {
+		p.flushMediaGroup(groupKey)
+	})
+	return true
+}
@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	webhookMaxBodyBytes int64 = 1 << 20 // 1 MiB
+
+	// webhookSecretHeader carries the secret_token passed to setWebhook.
+	webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+)
+
+// WebhookHandler receives Bot API updates for Telegram configs in webhook mode.
+type WebhookHandler struct {
+	logger  *slog.Logger
+	adapter *TelegramAdapter
+}
+
+// NewWebhookHandler creates a public webhook handler that feeds updates into the
+// adapter's active webhook connections.
+func NewWebhookHandler(log *slog.Logger, adapter *TelegramAdapter) *WebhookHandler {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WebhookHandler{
+		logger:  log.With(slog.String("handler", "telegram_webhook")),
+		adapter: adapter,
+	}
+}
+
+// NewWebhookServerHandler is a DI-friendly constructor for fx/dig that looks up the
+// registered Telegram adapter.
+func NewWebhookServerHandler(log *slog.Logger, registry *channel.Registry) *WebhookHandler {
+	var adapter *TelegramAdapter
+	if registry != nil {
+		if registered, ok := registry.Get(Type); ok {
+			adapter, _ = registered.(*TelegramAdapter)
+		}
+	}
+	return NewWebhookHandler(log, adapter)
+}
+
+// Register registers webhook routes.
+func (h *WebhookHandler) Register(e *echo.Echo) {
+	e.POST(webhookPathPrefix+":config_id", h.Handle)
+}
+
+// Handle verifies the secret token and hands the update to the config's connection.
+func (h *WebhookHandler) Handle(c echo.Context) error {
+	if h.adapter == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "telegram webhook dependencies not configured")
+	}
+	configID := strings.TrimSpace(c.Param("config_id"))
+	if configID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "config id is required")
+	}
+	processor, ok := h.adapter.webhookProcessor(configID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "telegram webhook is not active for this config")
+	}
+	secret := c.Request().Header.Get(webhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(processor.secret)) != 1 {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid secret token")
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, webhookMaxBodyBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("read body: %v", err))
+	}
+	if int64(len(body)) > webhookMaxBodyBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("payload too large: max %d bytes", webhookMaxBodyBytes))
+	}
+	var update telegramUpdate
+	if err := json.Unmarshal(body, &update); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid telegram update: %v", err))
+	}
+	if h.logger != nil {
+		h.logger.Debug("update received", slog.String("config_id", configID), slog.Int("update_id", update.UpdateID))
+	}
+	processor.handle(update)
+	return c.NoContent(http.StatusOK)
+}
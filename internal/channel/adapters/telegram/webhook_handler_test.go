@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type webhookInboundRecorder struct {
+	mu   sync.Mutex
+	msgs []channel.InboundMessage
+	done chan struct{}
+}
+
+func (r *webhookInboundRecorder) handle(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+	r.mu.Lock()
+	r.msgs = append(r.msgs, msg)
+	r.mu.Unlock()
+	r.done <- struct{}{}
+	return nil
+}
+
+func newWebhookTestHandler(t *testing.T) (*echo.Echo, *webhookInboundRecorder) {
+	t.Helper()
+	adapter := NewTelegramAdapter(nil)
+	recorder := &webhookInboundRecorder{done: make(chan struct{}, 4)}
+	bot := &tgbotapi.BotAPI{Token: "test", Self: tgbotapi.User{ID: 1001, UserName: "memohbot"}}
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: Type}
+	processor := newUpdateProcessor(context.Background(), adapter, bot, cfg, recorder.handle)
+	processor.secret = "s3cret"
+	adapter.webhooks[cfg.ID] = processor
+
+	e := echo.New()
+	NewWebhookHandler(nil, adapter).Register(e)
+	return e, recorder
+}
+
+func postWebhookUpdate(e *echo.Echo, configID, secret, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/channels/telegram/webhook/"+configID, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if secret != "" {
+		req.Header.Set(webhookSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+const webhookTestUpdate = `{"update_id":7,"message":{"message_id":55,"date":1710000000,"chat":{"id":42,"type":"private"},"from":{"id":10,"username":"alice"},"text":"hello"}}`
+
+func TestWebhookHandler_DispatchesUpdateOnce(t *testing.T) {
+	t.Parallel()
+
+	e, recorder := newWebhookTestHandler(t)
+	for i := 0; i < 2; i++ {
+		if rec := postWebhookUpdate(e, "cfg-1", "s3cret", webhookTestUpdate); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	select {
+	case <-recorder.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected inbound message to be dispatched")
+	}
+	select {
+	case <-recorder.done:
+		t.Fatal("expected redelivered update to be deduplicated")
+	case <-time.After(100 * time.Millisecond):
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if got := recorder.msgs[0].Message.Text; got != "hello" {
+		t.Fatalf("unexpected text %q", got)
+	}
+}
+
+func TestWebhookHandler_RejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	e, recorder := newWebhookTestHandler(t)
+	cases := []struct {
+		name     string
+		configID string
+		secret   string
+		body     string
+		want     int
+	}{
+		{name: "missing secret", configID: "cfg-1", body: webhookTestUpdate, want: http.StatusUnauthorized},
+		{name: "wrong secret", configID: "cfg-1", secret: "nope", body: webhookTestUpdate, want: http.StatusUnauthorized},
+		{name: "unknown config", configID: "cfg-2", secret: "s3cret", body: webhookTestUpdate, want: http.StatusNotFound},
+		{name: "invalid body", configID: "cfg-1", secret: "s3cret", body: "{", want: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		if rec := postWebhookUpdate(e, tc.configID, tc.secret, tc.body); rec.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rec.Code)
+		}
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.msgs) != 0 {
+		t.Fatalf("expected no dispatch, got %d", len(recorder.msgs))
+	}
+}
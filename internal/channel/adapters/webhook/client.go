@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	eventMessage = "message"
+	eventStream  = "stream"
+)
+
+// outboundPayload is the JSON body POSTed to the callback URL.
+type outboundPayload struct {
+	Event    string               `json:"event"`
+	ConfigID string               `json:"config_id"`
+	BotID    string               `json:"bot_id"`
+	Target   string               `json:"target"`
+	Message  *channel.Message     `json:"message,omitempty"`
+	StreamID string               `json:"stream_id,omitempty"`
+	Sequence int                  `json:"sequence,omitempty"`
+	Stream   *channel.StreamEvent `json:"stream,omitempty"`
+	Reply    *channel.ReplyRef    `json:"reply,omitempty"`
+	Thread   *channel.ThreadRef   `json:"thread,omitempty"`
+	SentAt   time.Time            `json:"sent_at"`
+}
+
+// callbackError is a non-2xx callback response.
+type callbackError struct {
+	Status     int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *callbackError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook callback status %d", e.Status)
+	}
+	return fmt.Sprintf("webhook callback status %d: %s", e.Status, e.Body)
+}
+
+func (e *callbackError) retryable() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= http.StatusInternalServerError
+}
+
+// deliver POSTs a signed payload to the callback URL once. Retries belong to the channel
+// manager; the delivery ID is taken from its SendProgress so it stays constant across
+// attempts. Throttling responses carry their Retry-After delay as a channel.RateLimitError.
+func (a *WebhookAdapter) deliver(ctx context.Context, cfg Config, payload outboundPayload) error {
+	if payload.SentAt.IsZero() {
+		payload.SentAt = time.Now().UTC()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	deliveryID := channel.SendProgressFromContext(ctx).DeliveryID()
+	if deliveryID == "" {
+		deliveryID = newDeliveryID()
+	}
+	err = a.post(ctx, cfg, payload.Event, deliveryID, body)
+	var ce *callbackError
+	if errors.As(err, &ce) && ce.retryable() && ce.RetryAfter > 0 {
+		return channel.NewRateLimitError(err, ce.RetryAfter)
+	}
+	return err
+}
+
+func (a *WebhookAdapter) post(ctx context.Context, cfg Config, event, deliveryID string, body []byte) error {
+	reqCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, cfg.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp, signature := sign(cfg.CallbackSecret, time.Now(), body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, signature)
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryID)
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook callback: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	ce := &callbackError{Status: resp.StatusCode, Body: string(bytes.TrimSpace(respBody))}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		ce.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return ce
+}
+
+func newDeliveryID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
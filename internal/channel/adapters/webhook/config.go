@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	streamModeEvents = "events"
+	streamModeFinal  = "final"
+
+	defaultTimeout = 10 * time.Second
+	maxTimeout     = 2 * time.Minute
+)
+
+// Config holds the callback endpoint and signing secrets extracted from a channel configuration.
+type Config struct {
+	CallbackURL    string
+	Secret         string
+	CallbackSecret string
+	StreamMode     string
+	Timeout        time.Duration
+}
+
+// UserConfig holds the identifiers used to target a user or conversation in the external system.
+type UserConfig struct {
+	UserID string
+	Target string
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"callbackUrl":    cfg.CallbackURL,
+		"secret":         cfg.Secret,
+		"streamMode":     cfg.StreamMode,
+		"timeoutSeconds": int(cfg.Timeout / time.Second),
+	}
+	if cfg.CallbackSecret != cfg.Secret {
+		result["callbackSecret"] = cfg.CallbackSecret
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{}
+	if cfg.UserID != "" {
+		result["user_id"] = cfg.UserID
+	}
+	if cfg.Target != "" {
+		result["target"] = cfg.Target
+	}
+	return result, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Target != "" {
+		return cfg.Target, nil
+	}
+	return cfg.UserID, nil
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if cfg.UserID != "" && cfg.UserID == strings.TrimSpace(criteria.SubjectID) {
+		return true
+	}
+	return cfg.Target != "" && cfg.Target == criteria.Attribute("conversation_id")
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := strings.TrimSpace(identity.SubjectID); value != "" {
+		result["user_id"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	cfg := Config{
+		CallbackURL:    strings.TrimSpace(channel.ReadString(raw, "callbackUrl", "callback_url")),
+		Secret:         strings.TrimSpace(channel.ReadString(raw, "secret")),
+		CallbackSecret: strings.TrimSpace(channel.ReadString(raw, "callbackSecret", "callback_secret")),
+		StreamMode:     strings.ToLower(strings.TrimSpace(channel.ReadString(raw, "streamMode", "stream_mode"))),
+		Timeout:        defaultTimeout,
+	}
+	if cfg.Secret == "" {
+		return Config{}, fmt.Errorf("webhook secret is required")
+	}
+	if cfg.CallbackURL == "" {
+		return Config{}, fmt.Errorf("webhook callbackUrl is required")
+	}
+	parsed, err := url.Parse(cfg.CallbackURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Config{}, fmt.Errorf("webhook callbackUrl must be an http(s) URL")
+	}
+	if cfg.CallbackSecret == "" {
+		cfg.CallbackSecret = cfg.Secret
+	}
+	switch cfg.StreamMode {
+	case "":
+		cfg.StreamMode = streamModeEvents
+	case streamModeEvents, streamModeFinal:
+	default:
+		return Config{}, fmt.Errorf("webhook streamMode must be %q or %q", streamModeEvents, streamModeFinal)
+	}
+	if value := strings.TrimSpace(channel.ReadString(raw, "timeoutSeconds", "timeout_seconds")); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return Config{}, fmt.Errorf("webhook timeoutSeconds must be a positive integer")
+		}
+		cfg.Timeout = min(time.Duration(n)*time.Second, maxTimeout)
+	}
+	return cfg, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	cfg := UserConfig{
+		UserID: strings.TrimSpace(channel.ReadString(raw, "userId", "user_id")),
+		Target: normalizeTarget(channel.ReadString(raw, "target", "conversation_id")),
+	}
+	if cfg.UserID == "" && cfg.Target == "" {
+		return UserConfig{}, fmt.Errorf("webhook user config requires user_id or target")
+	}
+	return cfg, nil
+}
+
+// normalizeTarget trims a target; targets are opaque identifiers defined by the external system.
+func normalizeTarget(raw string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(raw), "webhook:"))
+}
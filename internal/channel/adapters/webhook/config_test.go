@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestNormalizeConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeConfig(map[string]any{
+		"callback_url": "https://example.com/hook",
+		"secret":       "abc",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["callbackUrl"] != "https://example.com/hook" || got["secret"] != "abc" {
+		t.Fatalf("unexpected config: %#v", got)
+	}
+	if got["streamMode"] != streamModeEvents || got["timeoutSeconds"] != int(defaultTimeout/time.Second) {
+		t.Fatalf("unexpected defaults: %#v", got)
+	}
+	if _, ok := got["callbackSecret"]; ok {
+		t.Fatalf("expected callbackSecret to be omitted: %#v", got)
+	}
+}
+
+func TestParseConfigRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []map[string]any{
+		{},
+		{"callbackUrl": "https://example.com/hook"},
+		{"secret": "abc"},
+		{"secret": "abc", "callbackUrl": "ftp://example.com"},
+		{"secret": "abc", "callbackUrl": "https://example.com", "streamMode": "chunks"},
+		{"secret": "abc", "callbackUrl": "https://example.com", "timeoutSeconds": "0"},
+	}
+	for _, raw := range cases {
+		if _, err := parseConfig(raw); err == nil {
+			t.Fatalf("expected error for %#v", raw)
+		}
+	}
+}
+
+func TestUserConfigBinding(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeUserConfig(map[string]any{"user_id": "ha-user", "target": "webhook:house"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["user_id"] != "ha-user" || got["target"] != "house" {
+		t.Fatalf("unexpected user config: %#v", got)
+	}
+	target, err := resolveTarget(got)
+	if err != nil || target != "house" {
+		t.Fatalf("unexpected target %q err=%v", target, err)
+	}
+	if !matchBinding(got, channel.BindingCriteria{SubjectID: "ha-user"}) {
+		t.Fatal("expected binding to match subject id")
+	}
+	if matchBinding(got, channel.BindingCriteria{SubjectID: "other"}) {
+		t.Fatal("expected binding not to match other subject")
+	}
+	if _, err := normalizeUserConfig(map[string]any{}); err == nil {
+		t.Fatal("expected error for empty user config")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"a":1}`)
+	ts, sig := sign("k", now, body)
+	if err := verifySignature("k", ts, sig, body, now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+	if err := verifySignature("k", ts, "sha256=zz", body, now); err != errInvalidSignature {
+		t.Fatalf("expected invalid signature error, got %v", err)
+	}
+	if err := verifySignature("k", ts, sig, body, now.Add(10*time.Minute)); err != errStaleSignature {
+		t.Fatalf("expected stale signature error, got %v", err)
+	}
+	if err := verifySignature("k", "", sig, body, now); err != errMissingSignature {
+		t.Fatalf("expected missing signature error, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+// Package webhook implements a generic channel adapter that exchanges channel.Message JSON
+// with external systems over HMAC-signed HTTP requests.
+package webhook
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for signed webhooks.
+const Type channel.ChannelType = "webhook"
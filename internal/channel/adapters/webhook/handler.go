@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+type webhookConfigStore interface {
+	ListConfigsByType(ctx context.Context, channelType channel.ChannelType) ([]channel.ChannelConfig, error)
+}
+
+type webhookInboundManager interface {
+	HandleInbound(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error
+}
+
+const webhookMaxBodyBytes int64 = 20 << 20 // 20 MiB, room for base64 attachments
+
+// inboundPayload is the JSON body accepted on the inbound webhook URL.
+type inboundPayload struct {
+	EventID      string              `json:"event_id,omitempty"`
+	Message      channel.Message     `json:"message"`
+	Sender       inboundSender       `json:"sender"`
+	Conversation inboundConversation `json:"conversation"`
+	ReplyTarget  string              `json:"reply_target,omitempty"`
+	Metadata     map[string]any      `json:"metadata,omitempty"`
+}
+
+type inboundSender struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type inboundConversation struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Name     string `json:"name,omitempty"`
+	ThreadID string `json:"thread_id,omitempty"`
+}
+
+// WebhookHandler receives signed inbound messages for webhook channel configs.
+type WebhookHandler struct {
+	logger  *slog.Logger
+	store   webhookConfigStore
+	manager webhookInboundManager
+	adapter *WebhookAdapter
+	now     func() time.Time
+}
+
+// NewWebhookHandler creates a public handler for signed inbound webhook requests. The
+// adapter keeps the inbound dedup state.
+func NewWebhookHandler(log *slog.Logger, store webhookConfigStore, manager webhookInboundManager, adapter *WebhookAdapter) *WebhookHandler {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WebhookHandler{
+		logger:  log.With(slog.String("handler", "webhook_inbound")),
+		store:   store,
+		manager: manager,
+		adapter: adapter,
+		now:     time.Now,
+	}
+}
+
+// NewWebhookServerHandler is a DI-friendly constructor for fx/dig, using concrete
+// channel types as parameters and the adapter registered for the webhook type.
+func NewWebhookServerHandler(log *slog.Logger, store *channel.Store, manager *channel.Manager, registry *channel.Registry) *WebhookHandler {
+	var adapter *WebhookAdapter
+	if registry != nil {
+		if registered, ok := registry.Get(Type); ok {
+			adapter, _ = registered.(*WebhookAdapter)
+		}
+	}
+	return NewWebhookHandler(log, store, manager, adapter)
+}
+
+// Register registers the inbound webhook route.
+func (h *WebhookHandler) Register(e *echo.Echo) {
+	e.POST("/channels/webhook/:config_id", h.Handle)
+}
+
+// Handle verifies the request signature and forwards the message to the channel manager.
+// Requests are deduplicated on their signature, so a captured request cannot be replayed
+// while its timestamp is valid, and on event_id or message.id when the sender sets one.
+func (h *WebhookHandler) Handle(c echo.Context) error {
+	if h.store == nil || h.manager == nil || h.adapter == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "webhook dependencies not configured")
+	}
+	configID := strings.TrimSpace(c.Param("config_id"))
+	if configID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "config id is required")
+	}
+	cfg, err := h.findConfigByID(c.Request().Context(), configID)
+	if err != nil {
+		return err
+	}
+	if cfg.Disabled {
+		return echo.NewHTTPError(http.StatusForbidden, "channel config is disabled")
+	}
+	webhookCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, webhookMaxBodyBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("read body: %v", err))
+	}
+	if int64(len(body)) > webhookMaxBodyBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("payload too large: max %d bytes", webhookMaxBodyBytes))
+	}
+	header := c.Request().Header
+	if err := verifySignature(webhookCfg.Secret, header.Get(HeaderTimestamp), header.Get(HeaderSignature), body, h.now()); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+
+	var payload inboundPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid webhook payload: %v", err))
+	}
+	msg, err := buildInboundMessage(cfg, payload, h.now())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	eventID := strings.TrimSpace(payload.EventID)
+	if eventID == "" {
+		eventID = msg.Message.ID
+	}
+	dedupKeys := []string{"sig:" + strings.ToLower(strings.TrimSpace(header.Get(HeaderSignature)))}
+	if eventID != "" {
+		dedupKeys = append(dedupKeys, "event:"+eventID)
+	}
+	if !h.adapter.claimInbound(cfg.ID, dedupKeys...) {
+		return c.JSON(http.StatusOK, map[string]string{"status": "duplicate"})
+	}
+	if h.logger != nil {
+		h.logger.Info(
+			"inbound received",
+			slog.String("config_id", cfg.ID),
+			slog.String("chat_type", msg.Conversation.Type),
+			slog.String("conversation_id", msg.Conversation.ID),
+			slog.String("user_id", msg.Sender.SubjectID),
+			slog.String("text", common.SummarizeText(msg.Message.Text)),
+			slog.Int("attachments", len(msg.Message.Attachments)),
+		)
+	}
+	if err := h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg); err != nil {
+		h.adapter.releaseInbound(cfg.ID, dedupKeys...)
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	return c.JSON(http.StatusAccepted, map[string]string{"status": "accepted"})
+}
+
+// buildInboundMessage validates the payload and maps it to an inbound channel message.
+func buildInboundMessage(cfg channel.ChannelConfig, payload inboundPayload, now time.Time) (channel.InboundMessage, error) {
+	senderID := strings.TrimSpace(payload.Sender.ID)
+	if senderID == "" {
+		return channel.InboundMessage{}, errors.New("sender.id is required")
+	}
+	if payload.Message.IsEmpty() {
+		return channel.InboundMessage{}, errors.New("message is required")
+	}
+	conversationID := strings.TrimSpace(payload.Conversation.ID)
+	if conversationID == "" {
+		conversationID = senderID
+	}
+	chatType := strings.ToLower(strings.TrimSpace(payload.Conversation.Type))
+	switch chatType {
+	case "":
+		chatType = "direct"
+	case "direct", "group":
+	default:
+		return channel.InboundMessage{}, fmt.Errorf("unsupported conversation.type %q", payload.Conversation.Type)
+	}
+	threadID := strings.TrimSpace(payload.Conversation.ThreadID)
+	msg := payload.Message
+	if msg.Format == "" {
+		msg.Format = channel.MessageFormatPlain
+	}
+	if msg.Thread == nil && threadID != "" {
+		msg.Thread = &channel.ThreadRef{ID: threadID}
+	}
+	if threadID == "" && msg.Thread != nil {
+		threadID = strings.TrimSpace(msg.Thread.ID)
+	}
+	replyTarget := normalizeTarget(payload.ReplyTarget)
+	if replyTarget == "" {
+		replyTarget = conversationID
+	}
+	attributes := map[string]string{"conversation_id": conversationID}
+	for key, value := range payload.Sender.Attributes {
+		attributes[key] = value
+	}
+	displayName := strings.TrimSpace(payload.Sender.Name)
+	if displayName == "" {
+		displayName = senderID
+	}
+	return channel.InboundMessage{
+		Channel:     Type,
+		Message:     msg,
+		BotID:       cfg.BotID,
+		ReplyTarget: replyTarget,
+		Sender: channel.Identity{
+			SubjectID:   senderID,
+			DisplayName: displayName,
+			Attributes:  attributes,
+		},
+		Conversation: channel.Conversation{
+			ID:       conversationID,
+			Type:     chatType,
+			Name:     strings.TrimSpace(payload.Conversation.Name),
+			ThreadID: threadID,
+		},
+		ReceivedAt: now.UTC(),
+		Source:     "webhook",
+		Metadata:   payload.Metadata,
+	}, nil
+}
+
+func (h *WebhookHandler) findConfigByID(ctx context.Context, configID string) (channel.ChannelConfig, error) {
+	items, err := h.store.ListConfigsByType(ctx, Type)
+	if err != nil {
+		return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.ID) == configID {
+			return item, nil
+		}
+	}
+	return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusNotFound, "channel config not found")
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderSignature carries "sha256=<hex HMAC-SHA256 of timestamp + "." + body>".
+	HeaderSignature = "X-Memoh-Signature"
+	// HeaderTimestamp carries the Unix time in seconds used in the signature.
+	HeaderTimestamp = "X-Memoh-Timestamp"
+	// HeaderEvent names the outbound payload kind ("message" or "stream").
+	HeaderEvent = "X-Memoh-Event"
+	// HeaderDelivery is a unique ID per outbound delivery, stable across retries.
+	HeaderDelivery = "X-Memoh-Delivery"
+
+	signaturePrefix  = "sha256="
+	maxSignatureSkew = 5 * time.Minute
+)
+
+var (
+	errMissingSignature = errors.New("missing signature headers")
+	errStaleSignature   = errors.New("signature timestamp outside allowed window")
+	errInvalidSignature = errors.New("invalid signature")
+)
+
+// sign returns the timestamp and signature header values for body at the given time.
+func sign(secret string, ts time.Time, body []byte) (string, string) {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	return timestamp, signaturePrefix + hex.EncodeToString(computeMAC(secret, timestamp, body))
+}
+
+// verifySignature checks the signature headers of a request body against the shared secret.
+func verifySignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	timestamp = strings.TrimSpace(timestamp)
+	signature = strings.TrimSpace(signature)
+	if timestamp == "" || signature == "" {
+		return errMissingSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errInvalidSignature
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		return errStaleSignature
+	}
+	provided, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, signaturePrefix) {
+		return errInvalidSignature
+	}
+	if !hmac.Equal(provided, computeMAC(secret, timestamp, body)) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+func computeMAC(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
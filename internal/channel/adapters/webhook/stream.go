@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// streamFlushInterval bounds how long deltas are coalesced before they are forwarded.
+const streamFlushInterval = 500 * time.Millisecond
+
+// webhookOutboundStream forwards stream events to the callback URL. In "events" mode consecutive
+// deltas of the same phase are coalesced into one callback; in "final" mode only the completed
+// reply is delivered as a "message" event.
+type webhookOutboundStream struct {
+	adapter   *WebhookAdapter
+	cfg       channel.ChannelConfig
+	hookCfg   Config
+	target    string
+	streamID  string
+	reply     *channel.ReplyRef
+	thread    *channel.ThreadRef
+	flushTick time.Duration
+	closed    atomic.Bool
+
+	sendMu sync.Mutex
+	seq    int
+
+	mu           sync.Mutex
+	pending      strings.Builder
+	pendingPhase channel.StreamPhase
+	pendingSince time.Time
+	text         strings.Builder
+	attachments  []channel.Attachment
+	finished     bool
+}
+
+func (s *webhookOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil {
+		return fmt.Errorf("webhook stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("webhook stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if s.hookCfg.StreamMode == streamModeFinal {
+		return s.pushFinalMode(ctx, event)
+	}
+	return s.pushEventsMode(ctx, event)
+}
+
+func (s *webhookOutboundStream) pushEventsMode(ctx context.Context, event channel.StreamEvent) error {
+	if event.Type != channel.StreamEventDelta {
+		if err := s.flushDeltas(ctx); err != nil {
+			return err
+		}
+		return s.sendEvent(ctx, event)
+	}
+	if event.Delta == "" {
+		return nil
+	}
+	s.mu.Lock()
+	phaseChanged := s.pending.Len() > 0 && s.pendingPhase != event.Phase
+	s.mu.Unlock()
+	if phaseChanged {
+		if err := s.flushDeltas(ctx); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	if s.pending.Len() == 0 {
+		s.pendingPhase = event.Phase
+		s.pendingSince = time.Now()
+	}
+	s.pending.WriteString(event.Delta)
+	due := time.Since(s.pendingSince) >= s.flushTick
+	s.mu.Unlock()
+	if due {
+		return s.flushDeltas(ctx)
+	}
+	return nil
+}
+
+// flushDeltas forwards the coalesced deltas as a single delta event.
+func (s *webhookOutboundStream) flushDeltas(ctx context.Context) error {
+	s.mu.Lock()
+	if s.pending.Len() == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	event := channel.StreamEvent{
+		Type:  channel.StreamEventDelta,
+		Delta: s.pending.String(),
+		Phase: s.pendingPhase,
+	}
+	s.pending.Reset()
+	s.mu.Unlock()
+	return s.sendEvent(ctx, event)
+}
+
+func (s *webhookOutboundStream) sendEvent(ctx context.Context, event channel.StreamEvent) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	s.seq++
+	err := s.adapter.deliver(ctx, s.hookCfg, outboundPayload{
+		Event:    eventStream,
+		ConfigID: s.cfg.ID,
+		BotID:    s.cfg.BotID,
+		Target:   s.target,
+		StreamID: s.streamID,
+		Sequence: s.seq,
+		Stream:   &event,
+		Reply:    s.reply,
+		Thread:   s.thread,
+	})
+	if err != nil && s.adapter.logger != nil {
+		s.adapter.logger.Warn("stream callback failed",
+			slog.String("config_id", s.cfg.ID),
+			slog.String("stream_id", s.streamID),
+			slog.String("event", string(event.Type)),
+			slog.Any("error", err),
+		)
+	}
+	return err
+}
+
+func (s *webhookOutboundStream) pushFinalMode(ctx context.Context, event channel.StreamEvent) error {
+	switch event.Type {
+	case channel.StreamEventToolCallStart:
+		s.mu.Lock()
+		if s.text.Len() > 0 && !strings.HasSuffix(s.text.String(), "\n\n") {
+			s.text.WriteString("\n\n")
+		}
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventAttachment:
+		s.mu.Lock()
+		s.attachments = append(s.attachments, event.Attachments...)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.text.WriteString(event.Delta)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		text := strings.TrimSpace(s.text.String())
+		attachments := append([]channel.Attachment(nil), s.attachments...)
+		s.mu.Unlock()
+		format := channel.MessageFormatMarkdown
+		if event.Final != nil && !event.Final.Message.IsEmpty() {
+			msg := event.Final.Message
+			if text == "" {
+				text = strings.TrimSpace(msg.PlainText())
+			}
+			if msg.Format != "" {
+				format = msg.Format
+			}
+			attachments = append(attachments, msg.Attachments...)
+		}
+		return s.sendFinal(ctx, channel.Message{Format: format, Text: text, Attachments: attachments})
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		return s.sendFinal(ctx, channel.Message{Format: channel.MessageFormatPlain, Text: "Error: " + errText})
+	default:
+		return nil
+	}
+}
+
+func (s *webhookOutboundStream) sendFinal(ctx context.Context, msg channel.Message) error {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return nil
+	}
+	s.finished = true
+	s.mu.Unlock()
+	if msg.IsEmpty() {
+		return nil
+	}
+	msg.Reply = s.reply
+	msg.Thread = s.thread
+	return s.adapter.Send(ctx, s.cfg, channel.OutboundMessage{Target: s.target, Message: msg})
+}
+
+func (s *webhookOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if s.closed.Swap(true) {
+		return nil
+	}
+	if s.hookCfg.StreamMode == streamModeFinal {
+		return nil
+	}
+	return s.flushDeltas(ctx)
+}
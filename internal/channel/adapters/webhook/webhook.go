@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// inboundDedupTTL outlives the window in which a signature is accepted, so a captured
+// request cannot be replayed once its dedup entry expires.
+const inboundDedupTTL = 2*maxSignatureSkew + time.Minute
+
+// WebhookAdapter implements the channel.Adapter, channel.Sender, and channel.StreamSender interfaces
+// for signed HTTP webhooks. Inbound messages are received by WebhookHandler.
+type WebhookAdapter struct {
+	logger     *slog.Logger
+	httpClient *http.Client
+	mu         sync.Mutex
+	seen       map[string]time.Time // keyed by configID|dedup key
+}
+
+// NewWebhookAdapter creates a WebhookAdapter with the given logger.
+func NewWebhookAdapter(log *slog.Logger) *WebhookAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WebhookAdapter{
+		logger:     log.With(slog.String("adapter", "webhook")),
+		httpClient: &http.Client{},
+		seen:       make(map[string]time.Time),
+	}
+}
+
+// Type returns the webhook channel type.
+func (a *WebhookAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the webhook channel metadata.
+func (a *WebhookAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "Webhook",
+		Capabilities: channel.ChannelCapabilities{
+			Text:           true,
+			Markdown:       true,
+			RichText:       true,
+			Attachments:    true,
+			Media:          true,
+			Reply:          true,
+			Threads:        true,
+			Streaming:      true,
+			BlockStreaming: true,
+			ChatTypes:      []string{"direct", "group"},
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: 100000,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"callbackUrl": {
+					Type:        channel.FieldString,
+					Required:    true,
+					Title:       "Callback URL",
+					Description: "Replies and stream events are POSTed here",
+					Example:     "https://home.example.com/api/memoh",
+				},
+				"secret": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "Secret",
+					Description: "HMAC-SHA256 key used to verify inbound requests",
+				},
+				"callbackSecret": {
+					Type:        channel.FieldSecret,
+					Title:       "Callback Secret",
+					Description: "HMAC-SHA256 key used to sign callbacks; defaults to the inbound secret",
+				},
+				"streamMode": {
+					Type:        channel.FieldEnum,
+					Title:       "Stream Mode",
+					Description: "Forward every stream event, or only the final reply",
+					Enum:        []string{streamModeEvents, streamModeFinal},
+					Example:     streamModeEvents,
+				},
+				"timeoutSeconds": {
+					Type:  channel.FieldNumber,
+					Title: "Timeout",
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"user_id": {Type: channel.FieldString},
+				"target":  {Type: channel.FieldString},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "conversation_id",
+			Hints: []channel.TargetHint{
+				{Label: "Conversation ID", Example: "living-room"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a webhook channel configuration map.
+func (a *WebhookAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a webhook user-binding configuration map.
+func (a *WebhookAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a webhook delivery target string.
+func (a *WebhookAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a webhook user-binding configuration.
+func (a *WebhookAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a webhook user binding matches the given criteria.
+func (a *WebhookAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a webhook user-binding config from an Identity.
+func (a *WebhookAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// Connect validates the configuration. Inbound requests arrive through WebhookHandler, so there
+// is no long-lived connection to maintain.
+func (a *WebhookAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	if _, err := parseConfig(cfg.Credentials); err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+	if a.logger != nil {
+		a.logger.Info("webhook inbound ready", slog.String("config_id", cfg.ID))
+	}
+	return channel.NewConnection(cfg, func(context.Context) error { return nil }), nil
+}
+
+// Send POSTs a "message" event with the outbound message to the callback URL.
+func (a *WebhookAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	webhookCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return err
+	}
+	target := normalizeTarget(msg.Target)
+	if target == "" {
+		return fmt.Errorf("webhook target is required")
+	}
+	if msg.Message.IsEmpty() {
+		return fmt.Errorf("message is required")
+	}
+	message := msg.Message
+	err = a.deliver(ctx, webhookCfg, outboundPayload{
+		Event:    eventMessage,
+		ConfigID: cfg.ID,
+		BotID:    cfg.BotID,
+		Target:   target,
+		Message:  &message,
+		Reply:    message.Reply,
+		Thread:   message.Thread,
+	})
+	if err != nil && a.logger != nil {
+		a.logger.Error("callback failed", slog.String("config_id", cfg.ID), slog.String("target", target), slog.Any("error", err))
+	}
+	return err
+}
+
+// OpenStream opens a stream that forwards events to the callback URL according to the stream mode.
+func (a *WebhookAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	target = normalizeTarget(target)
+	if target == "" {
+		return nil, fmt.Errorf("webhook target is required")
+	}
+	webhookCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookOutboundStream{
+		adapter:   a,
+		cfg:       cfg,
+		hookCfg:   webhookCfg,
+		target:    target,
+		streamID:  newDeliveryID(),
+		reply:     opts.Reply,
+		thread:    opts.Thread,
+		flushTick: streamFlushInterval,
+	}, nil
+}
+
+// claimInbound records the dedup keys of an inbound request. It reports false when any
+// of them was already seen within inboundDedupTTL; empty keys are ignored.
+func (a *WebhookAdapter) claimInbound(configID string, keys ...string) bool {
+	now := time.Now().UTC()
+	expireBefore := now.Add(-inboundDedupTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for seenKey, seenAt := range a.seen {
+		if seenAt.Before(expireBefore) {
+			delete(a.seen, seenKey)
+		}
+	}
+	seenKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		seenKey := configID + "|" + key
+		if _, ok := a.seen[seenKey]; ok {
+			return false
+		}
+		seenKeys = append(seenKeys, seenKey)
+	}
+	for _, seenKey := range seenKeys {
+		a.seen[seenKey] = now
+	}
+	return true
+}
+
+// releaseInbound forgets the dedup keys of a request that could not be handled, so the
+// sender can retry it.
+func (a *WebhookAdapter) releaseInbound(configID string, keys ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			delete(a.seen, configID+"|"+key)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const testSecret = "s3cret"
+
+type fakeStore struct {
+	configs []channel.ChannelConfig
+}
+
+func (s *fakeStore) ListConfigsByType(context.Context, channel.ChannelType) ([]channel.ChannelConfig, error) {
+	return s.configs, nil
+}
+
+type fakeManager struct {
+	mu   sync.Mutex
+	msgs []channel.InboundMessage
+	err  error
+}
+
+func (m *fakeManager) HandleInbound(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.msgs = append(m.msgs, msg)
+	return m.err
+}
+
+func testConfig(callbackURL string) channel.ChannelConfig {
+	return channel.ChannelConfig{
+		ID:          "cfg-1",
+		BotID:       "bot-1",
+		ChannelType: Type,
+		Credentials: map[string]any{
+			"callbackUrl": callbackURL,
+			"secret":      testSecret,
+		},
+	}
+}
+
+func postInbound(t *testing.T, h *WebhookHandler, body string, sign func(*http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/channels/webhook/cfg-1", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if sign != nil {
+		sign(req)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("config_id")
+	c.SetParamValues("cfg-1")
+	if err := h.Handle(c); err != nil {
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			rec.Code = he.Code
+			return rec
+		}
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rec
+}
+
+func signWith(secret, body string, at time.Time) func(*http.Request) {
+	return func(req *http.Request) {
+		ts, sig := sign(secret, at, []byte(body))
+		req.Header.Set(HeaderTimestamp, ts)
+		req.Header.Set(HeaderSignature, sig)
+	}
+}
+
+func TestWebhookHandlerAcceptsSignedMessage(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeManager{}
+	h := NewWebhookHandler(nil, &fakeStore{configs: []channel.ChannelConfig{testConfig("https://example.com/hook")}}, manager, NewWebhookAdapter(nil))
+	body := `{"event_id":"evt-1","message":{"id":"m-1","text":"turn on the lights","attachments":[{"type":"image","url":"https://example.com/cam.jpg"}]},` +
+		`"sender":{"id":"ha-user","name":"Home Assistant","attributes":{"room":"kitchen"}},` +
+		`"conversation":{"id":"house","type":"group","name":"House","thread_id":"t-9"},"metadata":{"is_mentioned":true}}`
+
+	rec := postInbound(t, h, body, signWith(testSecret, body, time.Now()))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(manager.msgs) != 1 {
+		t.Fatalf("expected one inbound message, got %d", len(manager.msgs))
+	}
+	msg := manager.msgs[0]
+	if msg.Message.Text != "turn on the lights" || msg.Message.Format != channel.MessageFormatPlain || len(msg.Message.Attachments) != 1 {
+		t.Fatalf("unexpected message: %#v", msg.Message)
+	}
+	if msg.Sender.SubjectID != "ha-user" || msg.Sender.Attribute("room") != "kitchen" || msg.Sender.Attribute("conversation_id") != "house" {
+		t.Fatalf("unexpected sender: %#v", msg.Sender)
+	}
+	if msg.Conversation.ID != "house" || msg.Conversation.Type != "group" || msg.Conversation.ThreadID != "t-9" {
+		t.Fatalf("unexpected conversation: %#v", msg.Conversation)
+	}
+	if msg.ReplyTarget != "house" || msg.BotID != "bot-1" || msg.Message.Thread == nil || msg.Metadata["is_mentioned"] != true {
+		t.Fatalf("unexpected routing: %#v", msg)
+	}
+
+	rec = postInbound(t, h, body, signWith(testSecret, body, time.Now()))
+	if rec.Code != http.StatusOK || len(manager.msgs) != 1 {
+		t.Fatalf("expected duplicate event to be ignored, status=%d calls=%d", rec.Code, len(manager.msgs))
+	}
+}
+
+func TestWebhookHandlerRejectsReplayedRequest(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeManager{}
+	h := NewWebhookHandler(nil, &fakeStore{configs: []channel.ChannelConfig{testConfig("https://example.com/hook")}}, manager, NewWebhookAdapter(nil))
+	body := `{"message":{"text":"open the garage"},"sender":{"id":"u1"}}`
+	signer := signWith(testSecret, body, time.Now())
+
+	if rec := postInbound(t, h, body, signer); rec.Code != http.StatusAccepted {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	rec := postInbound(t, h, body, signer)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "duplicate") {
+		t.Fatalf("expected replay to be ignored, status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if rec := postInbound(t, h, body, signWith(testSecret, body, time.Now().Add(time.Second))); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected a newly signed request to be accepted, got %d", rec.Code)
+	}
+	if len(manager.msgs) != 2 {
+		t.Fatalf("expected two inbound messages, got %d", len(manager.msgs))
+	}
+}
+
+func TestWebhookHandlerAllowsRetryAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeManager{err: errors.New("queue full")}
+	h := NewWebhookHandler(nil, &fakeStore{configs: []channel.ChannelConfig{testConfig("https://example.com/hook")}}, manager, NewWebhookAdapter(nil))
+	body := `{"event_id":"evt-1","message":{"text":"hi"},"sender":{"id":"u1"}}`
+	signer := signWith(testSecret, body, time.Now())
+
+	if rec := postInbound(t, h, body, signer); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	manager.mu.Lock()
+	manager.err = nil
+	manager.mu.Unlock()
+	if rec := postInbound(t, h, body, signer); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected retry to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandlerRejectsBadSignatures(t *testing.T) {
+	t.Parallel()
+
+	manager := &fakeManager{}
+	h := NewWebhookHandler(nil, &fakeStore{configs: []channel.ChannelConfig{testConfig("https://example.com/hook")}}, manager, NewWebhookAdapter(nil))
+	body := `{"message":{"text":"hi"},"sender":{"id":"u1"}}`
+
+	cases := map[string]func(*http.Request){
+		"missing":   nil,
+		"wrong key": signWith("other", body, time.Now()),
+		"stale":     signWith(testSecret, body, time.Now().Add(-time.Hour)),
+		"tampered":  signWith(testSecret, body+" ", time.Now()),
+	}
+	for name, signer := range cases {
+		rec := postInbound(t, h, body, signer)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", name, rec.Code)
+		}
+	}
+	if len(manager.msgs) != 0 {
+		t.Fatalf("expected no inbound messages, got %d", len(manager.msgs))
+	}
+}
+
+func TestWebhookHandlerRejectsInvalidPayload(t *testing.T) {
+	t.Parallel()
+
+	h := NewWebhookHandler(nil, &fakeStore{configs: []channel.ChannelConfig{testConfig("https://example.com/hook")}}, &fakeManager{}, NewWebhookAdapter(nil))
+	for _, body := range []string{
+		`not json`,
+		`{"message":{"text":"hi"},"sender":{}}`,
+		`{"message":{},"sender":{"id":"u1"}}`,
+		`{"message":{"text":"hi"},"sender":{"id":"u1"},"conversation":{"type":"channel"}}`,
+	} {
+		rec := postInbound(t, h, body, signWith(testSecret, body, time.Now()))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("body %s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+type callbackRecorder struct {
+	mu       sync.Mutex
+	payloads []outboundPayload
+	headers  []http.Header
+	failures atomic.Int32
+}
+
+func newCallbackServer(t *testing.T, rec *callbackRecorder) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := verifySignature(testSecret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if rec.failures.Load() > 0 {
+			rec.failures.Add(-1)
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		var payload outboundPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rec.mu.Lock()
+		rec.payloads = append(rec.payloads, payload)
+		rec.headers = append(rec.headers, r.Header.Clone())
+		rec.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWebhookAdapterSendSignsWithStableDeliveryID(t *testing.T) {
+	t.Parallel()
+
+	rec := &callbackRecorder{}
+	rec.failures.Store(1)
+	srv := newCallbackServer(t, rec)
+	adapter := NewWebhookAdapter(nil)
+	cfg := testConfig(srv.URL)
+	ctx, progress := channel.WithSendProgress(context.Background())
+	msg := channel.OutboundMessage{
+		Target: "house",
+		Message: channel.Message{
+			Format: channel.MessageFormatMarkdown,
+			Text:   "Lights are **on**",
+			Reply:  &channel.ReplyRef{MessageID: "m-1"},
+		},
+	}
+
+	var ce *callbackError
+	if err := adapter.Send(ctx, cfg, msg); !errors.As(err, &ce) || ce.Status != http.StatusServiceUnavailable {
+		t.Fatalf("expected the first attempt to fail without retrying, got %v", err)
+	}
+	if err := adapter.Send(ctx, cfg, msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(rec.payloads) != 1 {
+		t.Fatalf("expected one delivered payload, got %d", len(rec.payloads))
+	}
+	got := rec.payloads[0]
+	if got.Event != eventMessage || got.Target != "house" || got.BotID != "bot-1" || got.Message == nil || got.Message.Text != "Lights are **on**" {
+		t.Fatalf("unexpected payload: %#v", got)
+	}
+	if got.Reply == nil || got.Reply.MessageID != "m-1" {
+		t.Fatalf("expected reply ref, got %#v", got.Reply)
+	}
+	if rec.headers[0].Get(HeaderEvent) != eventMessage || rec.headers[0].Get(HeaderDelivery) != progress.DeliveryID() {
+		t.Fatalf("unexpected headers: %v", rec.headers[0])
+	}
+}
+
+func TestWebhookAdapterSendReportsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	err := NewWebhookAdapter(nil).Send(context.Background(), testConfig(srv.URL), channel.OutboundMessage{
+		Target:  "house",
+		Message: channel.Message{Text: "hi"},
+	})
+	if got := channel.RetryAfterHint(err); got != 7*time.Second {
+		t.Fatalf("expected retry-after hint of 7s, got %s (%v)", got, err)
+	}
+}
+
+func TestWebhookAdapterSendDoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, "nope", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+
+	err := NewWebhookAdapter(nil).Send(context.Background(), testConfig(srv.URL), channel.OutboundMessage{
+		Target:  "house",
+		Message: channel.Message{Text: "hi"},
+	})
+	var ce *callbackError
+	if !errors.As(err, &ce) || ce.Status != http.StatusBadRequest {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestWebhookStreamEventsMode(t *testing.T) {
+	t.Parallel()
+
+	rec := &callbackRecorder{}
+	srv := newCallbackServer(t, rec)
+	adapter := NewWebhookAdapter(nil)
+	ctx := context.Background()
+
+	stream, err := adapter.OpenStream(ctx, testConfig(srv.URL), "house", channel.StreamOptions{Thread: &channel.ThreadRef{ID: "t-9"}})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	events := []channel.StreamEvent{
+		{Type: channel.StreamEventDelta, Delta: "think", Phase: channel.StreamPhaseReasoning},
+		{Type: channel.StreamEventDelta, Delta: "Hello "},
+		{Type: channel.StreamEventDelta, Delta: "world"},
+		{Type: channel.StreamEventFinal, Final: &channel.StreamFinalizePayload{Message: channel.Message{Text: "Hello world"}}},
+	}
+	for _, ev := range events {
+		if err := stream.Push(ctx, ev); err != nil {
+			t.Fatalf("push %s: %v", ev.Type, err)
+		}
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if len(rec.payloads) != 3 {
+		t.Fatalf("expected reasoning, coalesced delta and final callbacks, got %d", len(rec.payloads))
+	}
+	if rec.payloads[0].Stream.Phase != channel.StreamPhaseReasoning || rec.payloads[1].Stream.Delta != "Hello world" {
+		t.Fatalf("unexpected deltas: %#v %#v", rec.payloads[0].Stream, rec.payloads[1].Stream)
+	}
+	for i, p := range rec.payloads {
+		if p.Event != eventStream || p.Sequence != i+1 || p.StreamID != rec.payloads[0].StreamID || p.Thread == nil {
+			t.Fatalf("unexpected stream payload %d: %#v", i, p)
+		}
+	}
+	if rec.payloads[2].Stream.Type != channel.StreamEventFinal {
+		t.Fatalf("expected final event last, got %s", rec.payloads[2].Stream.Type)
+	}
+}
+
+func TestWebhookStreamFinalMode(t *testing.T) {
+	t.Parallel()
+
+	rec := &callbackRecorder{}
+	srv := newCallbackServer(t, rec)
+	adapter := NewWebhookAdapter(nil)
+	cfg := testConfig(srv.URL)
+	cfg.Credentials["streamMode"] = streamModeFinal
+	ctx := context.Background()
+
+	stream, err := adapter.OpenStream(ctx, cfg, "house", channel.StreamOptions{Reply: &channel.ReplyRef{MessageID: "m-1"}})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	for _, ev := range []channel.StreamEvent{
+		{Type: channel.StreamEventDelta, Delta: "Checking"},
+		{Type: channel.StreamEventToolCallStart},
+		{Type: channel.StreamEventDelta, Delta: "Done."},
+		{Type: channel.StreamEventAttachment, Attachments: []channel.Attachment{{Type: channel.AttachmentImage, URL: "https://example.com/a.png"}}},
+		{Type: channel.StreamEventFinal},
+	} {
+		if err := stream.Push(ctx, ev); err != nil {
+			t.Fatalf("push %s: %v", ev.Type, err)
+		}
+	}
+	_ = stream.Close(ctx)
+
+	if len(rec.payloads) != 1 {
+		t.Fatalf("expected a single message callback, got %d", len(rec.payloads))
+	}
+	got := rec.payloads[0]
+	if got.Event != eventMessage || got.Message == nil || got.Message.Text != "Checking\n\nDone." || len(got.Message.Attachments) != 1 {
+		t.Fatalf("unexpected final payload: %#v", got.Message)
+	}
+	if got.Reply == nil || got.Reply.MessageID != "m-1" {
+		t.Fatalf("expected reply ref, got %#v", got.Reply)
+	}
+}
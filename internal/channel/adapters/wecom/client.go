@@ -0,0 +1,295 @@
+package wecom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenRefreshMargin renews cached access tokens slightly before WeCom expires them.
+	tokenRefreshMargin  = 5 * time.Minute
+	maxAPIResponseBytes = 4 << 20
+)
+
+// Error codes that mean the cached access token must be refreshed.
+const (
+	errcodeInvalidToken = 40014
+	errcodeMissingToken = 41001
+	errcodeExpiredToken = 42001
+)
+
+type cachedToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+// apiError is returned when WeCom answers with a non-zero errcode.
+type apiError struct {
+	Op      string
+	Code    int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("wecom %s failed: %s (code: %d)", e.Op, e.Message, e.Code)
+}
+
+type apiStatus struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (s apiStatus) err(op string) error {
+	if s.ErrCode == 0 {
+		return nil
+	}
+	return &apiError{Op: op, Code: s.ErrCode, Message: s.ErrMsg}
+}
+
+func isTokenError(err error) bool {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case errcodeInvalidToken, errcodeMissingToken, errcodeExpiredToken:
+		return true
+	}
+	return false
+}
+
+func tokenCacheKey(cfg Config) string {
+	return cfg.APIBaseURL + "|" + cfg.CorpID + "|" + cfg.Secret
+}
+
+// accessToken returns a cached access token, fetching a new one when missing, expired or forced.
+func (a *WeComAdapter) accessToken(ctx context.Context, cfg Config, force bool) (string, error) {
+	key := tokenCacheKey(cfg)
+	now := time.Now()
+	a.mu.Lock()
+	cached, ok := a.tokens[key]
+	a.mu.Unlock()
+	if ok && !force && now.Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	query := url.Values{}
+	query.Set("corpid", cfg.CorpID)
+	query.Set("corpsecret", cfg.Secret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.APIBaseURL+"/cgi-bin/gettoken?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	var body struct {
+		apiStatus
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := a.doJSON(req, &body); err != nil {
+		return "", fmt.Errorf("wecom get token: %w", err)
+	}
+	if err := body.err("get token"); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(body.AccessToken) == "" {
+		return "", fmt.Errorf("wecom get token: empty access_token")
+	}
+	ttl := time.Duration(body.ExpiresIn)*time.Second - tokenRefreshMargin
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	a.mu.Lock()
+	a.tokens[key] = cachedToken{value: body.AccessToken, expiresAt: now.Add(ttl)}
+	a.mu.Unlock()
+	return body.AccessToken, nil
+}
+
+// callAPI sends a JSON request to a WeCom API path and decodes the response into out, retrying
+// once with a fresh token when the cached one is rejected.
+func (a *WeComAdapter) callAPI(ctx context.Context, cfg Config, op, method, path string, query url.Values, payload any, out any) error {
+	var raw []byte
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("wecom %s: encode request: %w", op, err)
+		}
+		raw = encoded
+	}
+	force := false
+	for attempt := 0; ; attempt++ {
+		token, err := a.accessToken(ctx, cfg, force)
+		if err != nil {
+			return err
+		}
+		values := url.Values{}
+		for key, items := range query {
+			values[key] = items
+		}
+		values.Set("access_token", token)
+		var body io.Reader
+		if raw != nil {
+			body = bytes.NewReader(raw)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, cfg.APIBaseURL+path+"?"+values.Encode(), body)
+		if err != nil {
+			return err
+		}
+		if raw != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		data, err := a.doRaw(req)
+		if err != nil {
+			return fmt.Errorf("wecom %s: %w", op, err)
+		}
+		var status apiStatus
+		if err := json.Unmarshal(data, &status); err != nil {
+			return fmt.Errorf("wecom %s: decode response: %w", op, err)
+		}
+		if err := status.err(op); err != nil {
+			if isTokenError(err) && attempt == 0 {
+				force = true
+				continue
+			}
+			return err
+		}
+		if out != nil {
+			if err := json.Unmarshal(data, out); err != nil {
+				return fmt.Errorf("wecom %s: decode response: %w", op, err)
+			}
+		}
+		return nil
+	}
+}
+
+// uploadMedia uploads a temporary media file and returns its media_id (valid for three days).
+func (a *WeComAdapter) uploadMedia(ctx context.Context, cfg Config, mediaType, name string, data []byte) (string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("media", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	force := false
+	for attempt := 0; ; attempt++ {
+		token, err := a.accessToken(ctx, cfg, force)
+		if err != nil {
+			return "", err
+		}
+		query := url.Values{}
+		query.Set("access_token", token)
+		query.Set("type", mediaType)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.APIBaseURL+"/cgi-bin/media/upload?"+query.Encode(), bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		var body struct {
+			apiStatus
+			MediaID string `json:"media_id"`
+		}
+		if err := a.doJSON(req, &body); err != nil {
+			return "", fmt.Errorf("wecom upload media: %w", err)
+		}
+		if err := body.err("upload media"); err != nil {
+			if isTokenError(err) && attempt == 0 {
+				force = true
+				continue
+			}
+			return "", err
+		}
+		if strings.TrimSpace(body.MediaID) == "" {
+			return "", fmt.Errorf("wecom upload media: empty media_id")
+		}
+		return body.MediaID, nil
+	}
+}
+
+// downloadMedia fetches a temporary media file. The caller closes the response body.
+func (a *WeComAdapter) downloadMedia(ctx context.Context, cfg Config, mediaID string) (*http.Response, error) {
+	force := false
+	for attempt := 0; ; attempt++ {
+		token, err := a.accessToken(ctx, cfg, force)
+		if err != nil {
+			return nil, err
+		}
+		query := url.Values{}
+		query.Set("access_token", token)
+		query.Set("media_id", mediaID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.APIBaseURL+"/cgi-bin/media/get?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("wecom download media: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("wecom download media: status %d", resp.StatusCode)
+		}
+		// Errors are reported as a JSON body instead of the file.
+		if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "application/json") {
+			data, readErr := io.ReadAll(io.LimitReader(resp.Body, maxAPIResponseBytes))
+			_ = resp.Body.Close()
+			if readErr != nil {
+				return nil, fmt.Errorf("wecom download media: %w", readErr)
+			}
+			var status apiStatus
+			if err := json.Unmarshal(data, &status); err != nil {
+				return nil, fmt.Errorf("wecom download media: decode response: %w", err)
+			}
+			err := status.err("download media")
+			if err == nil {
+				return nil, fmt.Errorf("wecom download media: unexpected json response")
+			}
+			if isTokenError(err) && attempt == 0 {
+				force = true
+				continue
+			}
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+func (a *WeComAdapter) doJSON(req *http.Request, out any) error {
+	data, err := a.doRaw(req)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func (a *WeComAdapter) doRaw(req *http.Request) ([]byte, error) {
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return data, nil
+}
@@ -0,0 +1,153 @@
+package wecom
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+const (
+	defaultAPIBaseURL = "https://qyapi.weixin.qq.com"
+
+	targetPrefixUser = "user:"
+	targetPrefixChat = "chat:"
+)
+
+// Config holds the WeCom application credentials extracted from a channel configuration.
+type Config struct {
+	CorpID         string
+	AgentID        int64
+	Secret         string
+	Token          string
+	EncodingAESKey string
+	APIBaseURL     string
+	ProcessingText string
+}
+
+// UserConfig holds the identifiers used to target a WeCom user.
+type UserConfig struct {
+	UserID string
+}
+
+func normalizeConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]any{
+		"corpId":         cfg.CorpID,
+		"agentId":        cfg.AgentID,
+		"secret":         cfg.Secret,
+		"token":          cfg.Token,
+		"encodingAESKey": cfg.EncodingAESKey,
+	}
+	if cfg.APIBaseURL != defaultAPIBaseURL {
+		result["apiBaseUrl"] = cfg.APIBaseURL
+	}
+	if cfg.ProcessingText != "" {
+		result["processingText"] = cfg.ProcessingText
+	}
+	return result, nil
+}
+
+func normalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"user_id": cfg.UserID}, nil
+}
+
+func resolveTarget(raw map[string]any) (string, error) {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return "", err
+	}
+	return targetPrefixUser + cfg.UserID, nil
+}
+
+func matchBinding(raw map[string]any, criteria channel.BindingCriteria) bool {
+	cfg, err := parseUserConfig(raw)
+	if err != nil {
+		return false
+	}
+	if value := strings.TrimSpace(criteria.Attribute("user_id")); value != "" && value == cfg.UserID {
+		return true
+	}
+	return criteria.SubjectID != "" && criteria.SubjectID == cfg.UserID
+}
+
+func buildUserConfig(identity channel.Identity) map[string]any {
+	result := map[string]any{}
+	if value := strings.TrimSpace(identity.Attribute("user_id")); value != "" {
+		result["user_id"] = value
+	} else if value := strings.TrimSpace(identity.SubjectID); value != "" {
+		result["user_id"] = value
+	}
+	return result
+}
+
+func parseConfig(raw map[string]any) (Config, error) {
+	cfg := Config{
+		CorpID:         strings.TrimSpace(channel.ReadString(raw, "corpId", "corp_id")),
+		Secret:         strings.TrimSpace(channel.ReadString(raw, "secret", "corpSecret", "corp_secret")),
+		Token:          strings.TrimSpace(channel.ReadString(raw, "token")),
+		EncodingAESKey: strings.TrimSpace(channel.ReadString(raw, "encodingAESKey", "encodingAesKey", "encoding_aes_key")),
+		APIBaseURL:     strings.TrimRight(strings.TrimSpace(channel.ReadString(raw, "apiBaseUrl", "api_base_url")), "/"),
+		ProcessingText: strings.TrimSpace(channel.ReadString(raw, "processingText", "processing_text")),
+	}
+	if cfg.CorpID == "" || cfg.Secret == "" {
+		return Config{}, fmt.Errorf("wecom corpId and secret are required")
+	}
+	agentID := strings.TrimSpace(channel.ReadString(raw, "agentId", "agent_id"))
+	if agentID == "" {
+		return Config{}, fmt.Errorf("wecom agentId is required")
+	}
+	id, err := strconv.ParseInt(agentID, 10, 64)
+	if err != nil || id <= 0 {
+		return Config{}, fmt.Errorf("wecom agentId must be a positive integer")
+	}
+	cfg.AgentID = id
+	if cfg.Token == "" || cfg.EncodingAESKey == "" {
+		return Config{}, fmt.Errorf("wecom token and encodingAESKey are required for callbacks")
+	}
+	if _, err := common.NewCallbackCrypto(cfg.Token, cfg.EncodingAESKey, cfg.CorpID); err != nil {
+		return Config{}, fmt.Errorf("wecom encodingAESKey must be the 43-character key from the console")
+	}
+	if cfg.APIBaseURL == "" {
+		cfg.APIBaseURL = defaultAPIBaseURL
+	}
+	parsed, err := url.Parse(cfg.APIBaseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Config{}, fmt.Errorf("wecom apiBaseUrl must be an http(s) URL")
+	}
+	return cfg, nil
+}
+
+func parseUserConfig(raw map[string]any) (UserConfig, error) {
+	userID := strings.TrimSpace(channel.ReadString(raw, "userId", "user_id"))
+	if userID == "" {
+		return UserConfig{}, fmt.Errorf("wecom user config requires user_id")
+	}
+	return UserConfig{UserID: userID}, nil
+}
+
+// normalizeTarget returns "user:<userid>" or "chat:<chatid>"; bare IDs are treated as user IDs.
+func normalizeTarget(raw string) string {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return ""
+	}
+	if strings.HasPrefix(value, targetPrefixUser) || strings.HasPrefix(value, targetPrefixChat) {
+		return value
+	}
+	return targetPrefixUser + value
+}
+
+func (c Config) crypto() (*common.CallbackCrypto, error) {
+	return common.NewCallbackCrypto(c.Token, c.EncodingAESKey, c.CorpID)
+}
@@ -0,0 +1,78 @@
+package wecom
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const testAESKey = "jWmYm7qr5nMoAUwZRjGtBxmz3KA1tkAj3ykkR6q2B2C"
+
+func validCredentials() map[string]any {
+	return map[string]any{
+		"corpId":         "ww-corp",
+		"agentId":        float64(1000002),
+		"secret":         "app-secret",
+		"token":          "cb-token",
+		"encodingAESKey": testAESKey,
+	}
+}
+
+func TestNormalizeConfig(t *testing.T) {
+	t.Parallel()
+
+	raw := validCredentials()
+	raw["api_base_url"] = "https://proxy.example.com/"
+	got, err := normalizeConfig(raw)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["corpId"] != "ww-corp" || got["agentId"] != int64(1000002) || got["apiBaseUrl"] != "https://proxy.example.com" {
+		t.Fatalf("unexpected config: %#v", got)
+	}
+	if _, ok := got["processingText"]; ok {
+		t.Fatalf("expected processingText to be omitted: %#v", got)
+	}
+}
+
+func TestParseConfigRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	mutations := []func(map[string]any){
+		func(m map[string]any) { delete(m, "corpId") },
+		func(m map[string]any) { delete(m, "secret") },
+		func(m map[string]any) { m["agentId"] = "abc" },
+		func(m map[string]any) { delete(m, "token") },
+		func(m map[string]any) { m["encodingAESKey"] = "short" },
+		func(m map[string]any) { m["apiBaseUrl"] = "ftp://example.com" },
+	}
+	for i, mutate := range mutations {
+		raw := validCredentials()
+		mutate(raw)
+		if _, err := parseConfig(raw); err == nil {
+			t.Fatalf("case %d: expected error for %#v", i, raw)
+		}
+	}
+}
+
+func TestUserConfigAndTargets(t *testing.T) {
+	t.Parallel()
+
+	got, err := normalizeUserConfig(map[string]any{"userId": "zhangsan"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	target, err := resolveTarget(got)
+	if err != nil || target != "user:zhangsan" {
+		t.Fatalf("unexpected target %q err=%v", target, err)
+	}
+	if !matchBinding(got, channel.BindingCriteria{SubjectID: "zhangsan"}) {
+		t.Fatal("expected binding to match subject id")
+	}
+	if matchBinding(got, channel.BindingCriteria{SubjectID: "lisi"}) {
+		t.Fatal("expected binding not to match other user")
+	}
+	if normalizeTarget("zhangsan") != "user:zhangsan" || normalizeTarget("chat:c1") != "chat:c1" || normalizeTarget(" ") != "" {
+		t.Fatal("unexpected target normalization")
+	}
+}
@@ -0,0 +1,7 @@
+// Package wecom implements the WeCom (WeChat Work) self-built application channel adapter.
+package wecom
+
+import "github.com/memohai/memoh/internal/channel"
+
+// Type is the registered ChannelType identifier for WeCom.
+const Type channel.ChannelType = "wecom"
@@ -0,0 +1,183 @@
+package wecom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+const (
+	defaultDirectoryLimit = 50
+	maxDirectoryLimit     = 500
+	// rootDepartmentID is the top-level department; listing it with fetch_child covers the corp.
+	rootDepartmentID = "1"
+)
+
+func directoryLimit(n int) int {
+	if n <= 0 {
+		return defaultDirectoryLimit
+	}
+	if n > maxDirectoryLimit {
+		return maxDirectoryLimit
+	}
+	return n
+}
+
+type wecomUser struct {
+	UserID string `json:"userid"`
+	Name   string `json:"name"`
+	Alias  string `json:"alias"`
+	Avatar string `json:"avatar"`
+}
+
+type wecomAppChat struct {
+	ChatID   string   `json:"chatid"`
+	Name     string   `json:"name"`
+	Owner    string   `json:"owner"`
+	UserList []string `json:"userlist"`
+}
+
+// ListPeers lists users visible to the application, optionally filtered by query.
+func (a *WeComAdapter) ListPeers(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("department_id", rootDepartmentID)
+	params.Set("fetch_child", "1")
+	var body struct {
+		UserList []wecomUser `json:"userlist"`
+	}
+	if err := a.callAPI(ctx, wecomCfg, "list users", http.MethodGet, "/cgi-bin/user/simplelist", params, nil, &body); err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	needle := strings.ToLower(strings.TrimSpace(query.Query))
+	entries := make([]channel.DirectoryEntry, 0, min(len(body.UserList), limit))
+	for _, u := range body.UserList {
+		if len(entries) >= limit {
+			break
+		}
+		e := wecomUserToEntry(u)
+		if needle != "" && !strings.Contains(strings.ToLower(e.Name+" "+u.UserID), needle) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// ListGroups returns nil: WeCom does not let applications enumerate chats, and application
+// chats can only be looked up by ID through ResolveEntry.
+func (a *WeComAdapter) ListGroups(ctx context.Context, cfg channel.ChannelConfig, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	return nil, nil
+}
+
+// ListGroupMembers lists members of an application chat created by this application.
+func (a *WeComAdapter) ListGroupMembers(ctx context.Context, cfg channel.ChannelConfig, groupID string, query channel.DirectoryQuery) ([]channel.DirectoryEntry, error) {
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	chat, err := a.getAppChat(ctx, wecomCfg, groupID)
+	if err != nil {
+		return nil, err
+	}
+	limit := directoryLimit(query.Limit)
+	needle := strings.ToLower(strings.TrimSpace(query.Query))
+	entries := make([]channel.DirectoryEntry, 0, min(len(chat.UserList), limit))
+	for _, userID := range chat.UserList {
+		if len(entries) >= limit {
+			break
+		}
+		if needle != "" && !strings.Contains(strings.ToLower(userID), needle) {
+			continue
+		}
+		entries = append(entries, channel.DirectoryEntry{
+			Kind:     channel.DirectoryEntryUser,
+			ID:       targetPrefixUser + userID,
+			Name:     userID,
+			Handle:   userID,
+			Metadata: map[string]any{"user_id": userID},
+		})
+	}
+	return entries, nil
+}
+
+// ResolveEntry resolves a user ID or application chat ID to a directory entry.
+func (a *WeComAdapter) ResolveEntry(ctx context.Context, cfg channel.ChannelConfig, input string, kind channel.DirectoryEntryKind) (channel.DirectoryEntry, error) {
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.DirectoryEntry{}, err
+	}
+	switch kind {
+	case channel.DirectoryEntryUser:
+		userID := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), targetPrefixUser))
+		if userID == "" {
+			return channel.DirectoryEntry{}, fmt.Errorf("wecom resolve entry user: empty input")
+		}
+		params := url.Values{}
+		params.Set("userid", userID)
+		var user wecomUser
+		if err := a.callAPI(ctx, wecomCfg, "get user", http.MethodGet, "/cgi-bin/user/get", params, nil, &user); err != nil {
+			return channel.DirectoryEntry{}, err
+		}
+		if strings.TrimSpace(user.UserID) == "" {
+			user.UserID = userID
+		}
+		return wecomUserToEntry(user), nil
+	case channel.DirectoryEntryGroup:
+		chat, err := a.getAppChat(ctx, wecomCfg, input)
+		if err != nil {
+			return channel.DirectoryEntry{}, err
+		}
+		return channel.DirectoryEntry{
+			Kind:     channel.DirectoryEntryGroup,
+			ID:       targetPrefixChat + chat.ChatID,
+			Name:     strings.TrimSpace(chat.Name),
+			Metadata: map[string]any{"chat_id": chat.ChatID, "owner": chat.Owner},
+		}, nil
+	default:
+		return channel.DirectoryEntry{}, fmt.Errorf("wecom resolve entry: unsupported kind %q", kind)
+	}
+}
+
+func (a *WeComAdapter) getAppChat(ctx context.Context, cfg Config, input string) (wecomAppChat, error) {
+	chatID := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), targetPrefixChat))
+	if chatID == "" {
+		return wecomAppChat{}, fmt.Errorf("wecom chat id is required")
+	}
+	params := url.Values{}
+	params.Set("chatid", chatID)
+	var body struct {
+		ChatInfo wecomAppChat `json:"chat_info"`
+	}
+	if err := a.callAPI(ctx, cfg, "get app chat", http.MethodGet, "/cgi-bin/appchat/get", params, nil, &body); err != nil {
+		return wecomAppChat{}, err
+	}
+	if strings.TrimSpace(body.ChatInfo.ChatID) == "" {
+		body.ChatInfo.ChatID = chatID
+	}
+	return body.ChatInfo, nil
+}
+
+func wecomUserToEntry(u wecomUser) channel.DirectoryEntry {
+	userID := strings.TrimSpace(u.UserID)
+	name := strings.TrimSpace(u.Name)
+	if name == "" {
+		name = userID
+	}
+	return channel.DirectoryEntry{
+		Kind:      channel.DirectoryEntryUser,
+		ID:        targetPrefixUser + userID,
+		Name:      name,
+		Handle:    strings.TrimSpace(u.Alias),
+		AvatarURL: strings.TrimSpace(u.Avatar),
+		Metadata:  map[string]any{"user_id": userID},
+	}
+}
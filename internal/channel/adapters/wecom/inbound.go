@@ -0,0 +1,149 @@
+package wecom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// encryptedEnvelope is the outer XML body of a callback POST.
+type encryptedEnvelope struct {
+	XMLName    xml.Name `xml:"xml"`
+	ToUserName string   `xml:"ToUserName"`
+	AgentID    string   `xml:"AgentID"`
+	Encrypt    string   `xml:"Encrypt"`
+}
+
+// callbackMessage is the decrypted application message or event.
+type callbackMessage struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	FromUserName string   `xml:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime"`
+	MsgType      string   `xml:"MsgType"`
+	Content      string   `xml:"Content"`
+	MsgID        string   `xml:"MsgId"`
+	AgentID      string   `xml:"AgentID"`
+	PicURL       string   `xml:"PicUrl"`
+	MediaID      string   `xml:"MediaId"`
+	Format       string   `xml:"Format"`
+	ThumbMediaID string   `xml:"ThumbMediaId"`
+	LocationX    string   `xml:"Location_X"`
+	LocationY    string   `xml:"Location_Y"`
+	Label        string   `xml:"Label"`
+	Title        string   `xml:"Title"`
+	Description  string   `xml:"Description"`
+	URL          string   `xml:"Url"`
+	Event        string   `xml:"Event"`
+	EventKey     string   `xml:"EventKey"`
+	TaskID       string   `xml:"TaskId"`
+}
+
+func parseCallbackMessage(data []byte) (callbackMessage, error) {
+	var msg callbackMessage
+	if err := xml.Unmarshal(data, &msg); err != nil {
+		return callbackMessage{}, fmt.Errorf("decode wecom message: %w", err)
+	}
+	return msg, nil
+}
+
+// dedupKey identifies a callback for duplicate suppression; WeCom retries unanswered callbacks.
+func (m callbackMessage) dedupKey() string {
+	if id := strings.TrimSpace(m.MsgID); id != "" {
+		return id
+	}
+	return strings.Join([]string{m.FromUserName, strconv.FormatInt(m.CreateTime, 10), m.MsgType, m.Event, m.EventKey}, ":")
+}
+
+// buildInboundMessage maps a decrypted callback message to an inbound channel message.
+// It reports false for events and message types the adapter does not handle.
+func buildInboundMessage(cfg channel.ChannelConfig, raw callbackMessage, now time.Time) (channel.InboundMessage, bool) {
+	userID := strings.TrimSpace(raw.FromUserName)
+	if userID == "" {
+		return channel.InboundMessage{}, false
+	}
+	msgType := strings.ToLower(strings.TrimSpace(raw.MsgType))
+	message := channel.Message{
+		ID:     strings.TrimSpace(raw.MsgID),
+		Format: channel.MessageFormatPlain,
+	}
+	attachmentMeta := map[string]any{"msg_type": msgType}
+	switch msgType {
+	case "text":
+		message.Text = strings.TrimSpace(raw.Content)
+	case "image":
+		message.Attachments = []channel.Attachment{{
+			Type:           channel.AttachmentImage,
+			PlatformKey:    strings.TrimSpace(raw.MediaID),
+			SourcePlatform: Type.String(),
+			Mime:           "image/jpeg",
+			Metadata:       attachmentMeta,
+		}}
+	case "voice":
+		format := strings.ToLower(strings.TrimSpace(raw.Format))
+		if format == "" {
+			format = "amr"
+		}
+		message.Attachments = []channel.Attachment{{
+			Type:           channel.AttachmentVoice,
+			PlatformKey:    strings.TrimSpace(raw.MediaID),
+			SourcePlatform: Type.String(),
+			Mime:           "audio/" + format,
+			Metadata:       attachmentMeta,
+		}}
+	case "video":
+		message.Attachments = []channel.Attachment{{
+			Type:           channel.AttachmentVideo,
+			PlatformKey:    strings.TrimSpace(raw.MediaID),
+			SourcePlatform: Type.String(),
+			Mime:           "video/mp4",
+			Metadata:       attachmentMeta,
+		}}
+	case "location":
+		label := strings.TrimSpace(raw.Label)
+		message.Text = strings.TrimSpace(fmt.Sprintf("[Location] %s (%s, %s)", label, strings.TrimSpace(raw.LocationX), strings.TrimSpace(raw.LocationY)))
+	case "link":
+		parts := []string{strings.TrimSpace(raw.Title), strings.TrimSpace(raw.Description), strings.TrimSpace(raw.URL)}
+		lines := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if part != "" {
+				lines = append(lines, part)
+			}
+		}
+		message.Text = strings.Join(lines, "\n")
+	default:
+		return channel.InboundMessage{}, false
+	}
+	if strings.TrimSpace(message.Text) == "" && len(message.Attachments) == 0 {
+		return channel.InboundMessage{}, false
+	}
+	receivedAt := now.UTC()
+	if raw.CreateTime > 0 {
+		receivedAt = time.Unix(raw.CreateTime, 0).UTC()
+	}
+	return channel.InboundMessage{
+		Channel:     Type,
+		Message:     message,
+		BotID:       cfg.BotID,
+		ReplyTarget: targetPrefixUser + userID,
+		Sender: channel.Identity{
+			SubjectID:   userID,
+			DisplayName: userID,
+			Attributes:  map[string]string{"user_id": userID},
+		},
+		Conversation: channel.Conversation{
+			ID:   userID,
+			Type: "direct",
+		},
+		ReceivedAt: receivedAt,
+		Source:     "wecom",
+		Metadata: map[string]any{
+			"agent_id": strings.TrimSpace(raw.AgentID),
+			"msg_type": msgType,
+		},
+	}, true
+}
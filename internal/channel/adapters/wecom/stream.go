@@ -0,0 +1,117 @@
+package wecom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// wecomOutboundStream buffers deltas and sends the reply on the final event.
+type wecomOutboundStream struct {
+	adapter     *WeComAdapter
+	cfg         channel.ChannelConfig
+	wecomCfg    Config
+	target      string
+	closed      atomic.Bool
+	mu          sync.Mutex
+	buf         strings.Builder
+	attachments []channel.Attachment
+	sent        bool
+}
+
+func (s *wecomOutboundStream) flush(ctx context.Context, msg channel.Message) error {
+	s.mu.Lock()
+	if s.sent {
+		s.mu.Unlock()
+		return nil
+	}
+	s.sent = true
+	s.mu.Unlock()
+	msg.Text = strings.TrimSpace(msg.Text)
+	if msg.Text == "" && len(msg.Attachments) == 0 && len(msg.Actions) == 0 {
+		return nil
+	}
+	_, err := s.adapter.sendMessage(ctx, s.cfg, s.wecomCfg, s.target, msg)
+	return err
+}
+
+func (s *wecomOutboundStream) Push(ctx context.Context, event channel.StreamEvent) error {
+	if s == nil || s.adapter == nil {
+		return fmt.Errorf("wecom stream not configured")
+	}
+	if s.closed.Load() {
+		return fmt.Errorf("wecom stream is closed")
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	switch event.Type {
+	case channel.StreamEventToolCallStart:
+		s.mu.Lock()
+		if s.buf.Len() > 0 && !strings.HasSuffix(s.buf.String(), "\n\n") {
+			s.buf.WriteString("\n\n")
+		}
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventAttachment:
+		s.mu.Lock()
+		s.attachments = append(s.attachments, event.Attachments...)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventDelta:
+		if event.Delta == "" || event.Phase == channel.StreamPhaseReasoning {
+			return nil
+		}
+		s.mu.Lock()
+		s.buf.WriteString(event.Delta)
+		s.mu.Unlock()
+		return nil
+	case channel.StreamEventFinal:
+		s.mu.Lock()
+		msg := channel.Message{
+			Format:      channel.MessageFormatMarkdown,
+			Text:        s.buf.String(),
+			Attachments: append([]channel.Attachment(nil), s.attachments...),
+		}
+		s.mu.Unlock()
+		if event.Final != nil && !event.Final.Message.IsEmpty() {
+			final := event.Final.Message
+			if strings.TrimSpace(msg.Text) == "" {
+				msg.Text = final.PlainText()
+			}
+			if final.Format != "" {
+				msg.Format = final.Format
+			}
+			msg.Attachments = append(msg.Attachments, final.Attachments...)
+			msg.Actions = final.Actions
+		}
+		return s.flush(ctx, msg)
+	case channel.StreamEventError:
+		errText := strings.TrimSpace(event.Error)
+		if errText == "" {
+			return nil
+		}
+		return s.flush(ctx, channel.Message{Format: channel.MessageFormatPlain, Text: "Error: " + errText})
+	default:
+		return nil
+	}
+}
+
+func (s *wecomOutboundStream) Close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	s.closed.Store(true)
+	return nil
+}
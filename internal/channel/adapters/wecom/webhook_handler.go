@@ -0,0 +1,181 @@
+package wecom
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/adapters/common"
+)
+
+type webhookConfigStore interface {
+	ListConfigsByType(ctx context.Context, channelType channel.ChannelType) ([]channel.ChannelConfig, error)
+}
+
+type webhookInboundManager interface {
+	HandleInbound(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error
+}
+
+const webhookMaxBodyBytes int64 = 1 << 20 // 1 MiB
+
+// WebhookHandler receives encrypted WeCom application callbacks.
+type WebhookHandler struct {
+	logger  *slog.Logger
+	store   webhookConfigStore
+	manager webhookInboundManager
+	adapter *WeComAdapter
+	now     func() time.Time
+}
+
+// NewWebhookHandler creates a public callback handler for WeCom applications.
+func NewWebhookHandler(log *slog.Logger, store webhookConfigStore, manager webhookInboundManager) *WebhookHandler {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WebhookHandler{
+		logger:  log.With(slog.String("handler", "wecom_callback")),
+		store:   store,
+		manager: manager,
+		adapter: NewWeComAdapter(log),
+		now:     time.Now,
+	}
+}
+
+// NewWebhookServerHandler is a DI-friendly constructor for fx/dig, using concrete
+// channel types as parameters.
+func NewWebhookServerHandler(log *slog.Logger, store *channel.Store, manager *channel.Manager) *WebhookHandler {
+	return NewWebhookHandler(log, store, manager)
+}
+
+// Register registers callback routes.
+func (h *WebhookHandler) Register(e *echo.Echo) {
+	e.GET("/channels/wecom/callback/:config_id", h.HandleVerify)
+	e.POST("/channels/wecom/callback/:config_id", h.Handle)
+}
+
+// HandleVerify answers the URL verification request sent when the callback URL is saved.
+func (h *WebhookHandler) HandleVerify(c echo.Context) error {
+	_, crypto, err := h.resolveConfig(c)
+	if err != nil {
+		return err
+	}
+	echostr := c.QueryParam("echostr")
+	if err := crypto.Verify(c.QueryParam("msg_signature"), c.QueryParam("timestamp"), c.QueryParam("nonce"), echostr); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	plain, err := crypto.Decrypt(echostr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return c.String(http.StatusOK, string(plain))
+}
+
+// Handle decrypts a message callback and forwards it to the channel manager.
+func (h *WebhookHandler) Handle(c echo.Context) error {
+	cfg, crypto, err := h.resolveConfig(c)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, webhookMaxBodyBytes+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("read body: %v", err))
+	}
+	if int64(len(body)) > webhookMaxBodyBytes {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("payload too large: max %d bytes", webhookMaxBodyBytes))
+	}
+	var envelope encryptedEnvelope
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid wecom callback payload: %v", err))
+	}
+	if strings.TrimSpace(envelope.Encrypt) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "wecom callback payload is not encrypted")
+	}
+	if err := crypto.Verify(c.QueryParam("msg_signature"), c.QueryParam("timestamp"), c.QueryParam("nonce"), envelope.Encrypt); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	plain, err := crypto.Decrypt(envelope.Encrypt)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	raw, err := parseCallbackMessage(plain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	// WeCom retries callbacks that are not answered within five seconds.
+	if h.adapter.isDuplicateInbound(cfg.ID, raw.dedupKey()) {
+		return c.String(http.StatusOK, "success")
+	}
+	msg, ok := buildInboundMessage(cfg, raw, h.now())
+	if !ok {
+		if h.logger != nil {
+			h.logger.Debug("callback ignored",
+				slog.String("config_id", cfg.ID),
+				slog.String("msg_type", raw.MsgType),
+				slog.String("event", raw.Event),
+			)
+		}
+		return c.String(http.StatusOK, "success")
+	}
+	if h.logger != nil {
+		h.logger.Info(
+			"inbound received",
+			slog.String("config_id", cfg.ID),
+			slog.String("message_id", msg.Message.ID),
+			slog.String("msg_type", raw.MsgType),
+			slog.String("user_id", msg.Sender.SubjectID),
+			slog.String("text", common.SummarizeText(msg.Message.Text)),
+			slog.Int("attachments", len(msg.Message.Attachments)),
+		)
+	}
+	if err := h.manager.HandleInbound(context.WithoutCancel(c.Request().Context()), cfg, msg); err != nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	return c.String(http.StatusOK, "success")
+}
+
+func (h *WebhookHandler) resolveConfig(c echo.Context) (channel.ChannelConfig, *common.CallbackCrypto, error) {
+	if h.store == nil || h.manager == nil {
+		return channel.ChannelConfig{}, nil, echo.NewHTTPError(http.StatusInternalServerError, "wecom callback dependencies not configured")
+	}
+	configID := strings.TrimSpace(c.Param("config_id"))
+	if configID == "" {
+		return channel.ChannelConfig{}, nil, echo.NewHTTPError(http.StatusBadRequest, "config id is required")
+	}
+	cfg, err := h.findConfigByID(c.Request().Context(), configID)
+	if err != nil {
+		return channel.ChannelConfig{}, nil, err
+	}
+	if cfg.Disabled {
+		return channel.ChannelConfig{}, nil, echo.NewHTTPError(http.StatusForbidden, "channel config is disabled")
+	}
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.ChannelConfig{}, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	crypto, err := wecomCfg.crypto()
+	if err != nil {
+		return channel.ChannelConfig{}, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return cfg, crypto, nil
+}
+
+func (h *WebhookHandler) findConfigByID(ctx context.Context, configID string) (channel.ChannelConfig, error) {
+	items, err := h.store.ListConfigsByType(ctx, Type)
+	if err != nil {
+		return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.ID) == configID {
+			return item, nil
+		}
+	}
+	return channel.ChannelConfig{}, echo.NewHTTPError(http.StatusNotFound, "channel config not found")
+}
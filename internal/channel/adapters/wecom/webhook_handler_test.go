@@ -0,0 +1,159 @@
+package wecom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+type fakeStore struct {
+	configs []channel.ChannelConfig
+}
+
+func (s *fakeStore) ListConfigsByType(context.Context, channel.ChannelType) ([]channel.ChannelConfig, error) {
+	return s.configs, nil
+}
+
+type fakeManager struct {
+	mu   sync.Mutex
+	msgs []channel.InboundMessage
+}
+
+func (m *fakeManager) HandleInbound(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.msgs = append(m.msgs, msg)
+	return nil
+}
+
+func newTestHandler() (*WebhookHandler, *fakeManager) {
+	manager := &fakeManager{}
+	store := &fakeStore{configs: []channel.ChannelConfig{{ID: "cfg-1", BotID: "bot-1", ChannelType: Type, Credentials: validCredentials()}}}
+	return NewWebhookHandler(nil, store, manager), manager
+}
+
+func serve(t *testing.T, h *WebhookHandler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("config_id")
+	c.SetParamValues("cfg-1")
+	handle := h.Handle
+	if method == http.MethodGet {
+		handle = h.HandleVerify
+	}
+	if err := handle(c); err != nil {
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			rec.Code = he.Code
+			return rec
+		}
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rec
+}
+
+func signedQuery(t *testing.T, encrypted string, extra url.Values) string {
+	t.Helper()
+	cfg, err := parseConfig(validCredentials())
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypto, _ := cfg.crypto()
+	query := url.Values{}
+	for k, v := range extra {
+		query[k] = v
+	}
+	query.Set("timestamp", "1700000000")
+	query.Set("nonce", "n1")
+	query.Set("msg_signature", crypto.Signature("1700000000", "n1", encrypted))
+	return query.Encode()
+}
+
+func encryptForTest(t *testing.T, plain string) string {
+	t.Helper()
+	cfg, _ := parseConfig(validCredentials())
+	crypto, _ := cfg.crypto()
+	encrypted, err := crypto.Encrypt([]byte(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encrypted
+}
+
+func TestWebhookHandlerVerifiesURL(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newTestHandler()
+	echostr := encryptForTest(t, "echo-123")
+	rec := serve(t, h, http.MethodGet, "/channels/wecom/callback/cfg-1?"+signedQuery(t, echostr, url.Values{"echostr": {echostr}}), "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "echo-123" {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(t, h, http.MethodGet, "/channels/wecom/callback/cfg-1?msg_signature=bad&timestamp=1&nonce=n&echostr="+url.QueryEscape(echostr), "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", rec.Code)
+	}
+}
+
+func TestWebhookHandlerDecryptsTextMessage(t *testing.T) {
+	t.Parallel()
+
+	h, manager := newTestHandler()
+	plain := `<xml><ToUserName><![CDATA[ww-corp]]></ToUserName><FromUserName><![CDATA[zhangsan]]></FromUserName>` +
+		`<CreateTime>1700000000</CreateTime><MsgType><![CDATA[text]]></MsgType><Content><![CDATA[你好]]></Content>` +
+		`<MsgId>1234567890</MsgId><AgentID>1000002</AgentID></xml>`
+	encrypted := encryptForTest(t, plain)
+	body := fmt.Sprintf(`<xml><ToUserName><![CDATA[ww-corp]]></ToUserName><AgentID><![CDATA[1000002]]></AgentID><Encrypt><![CDATA[%s]]></Encrypt></xml>`, encrypted)
+	target := "/channels/wecom/callback/cfg-1?" + signedQuery(t, encrypted, nil)
+
+	for i := 0; i < 2; i++ {
+		rec := serve(t, h, http.MethodPost, target, body)
+		if rec.Code != http.StatusOK || rec.Body.String() != "success" {
+			t.Fatalf("unexpected response %d %q", rec.Code, rec.Body.String())
+		}
+	}
+	if len(manager.msgs) != 1 {
+		t.Fatalf("expected retried callback to be deduplicated, got %d messages", len(manager.msgs))
+	}
+	msg := manager.msgs[0]
+	if msg.Message.Text != "你好" || msg.Message.ID != "1234567890" || msg.ReplyTarget != "user:zhangsan" || msg.Conversation.Type != "direct" {
+		t.Fatalf("unexpected inbound: %#v", msg)
+	}
+
+	rec := serve(t, h, http.MethodPost, "/channels/wecom/callback/cfg-1?msg_signature=bad&timestamp=1&nonce=n", body)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", rec.Code)
+	}
+}
+
+func TestBuildInboundMessageMedia(t *testing.T) {
+	t.Parallel()
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	msg, ok := buildInboundMessage(cfg, callbackMessage{FromUserName: "zhangsan", MsgType: "voice", MediaID: "m1", Format: "amr", MsgID: "1"}, time.Unix(1_700_000_000, 0))
+	if !ok || len(msg.Message.Attachments) != 1 {
+		t.Fatalf("expected voice attachment, got %#v", msg)
+	}
+	att := msg.Message.Attachments[0]
+	if att.Type != channel.AttachmentVoice || att.PlatformKey != "m1" || att.Mime != "audio/amr" || att.SourcePlatform != "wecom" {
+		t.Fatalf("unexpected attachment: %#v", att)
+	}
+	if _, ok := buildInboundMessage(cfg, callbackMessage{FromUserName: "zhangsan", MsgType: "event", Event: "enter_agent"}, time.Unix(1_700_000_000, 0)); ok {
+		t.Fatal("expected events to be ignored")
+	}
+}
@@ -0,0 +1,637 @@
+package wecom
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/media"
+)
+
+const (
+	inboundDedupTTL = 10 * time.Minute
+	// textChunkRunes keeps text and markdown messages under the 2048-byte API limit for CJK text.
+	textChunkRunes = 680
+	// maxCardButtons is the button limit of a button_interaction template card.
+	maxCardButtons = 6
+	maxCardTitle   = 26
+)
+
+// assetOpener reads stored asset bytes by content hash.
+type assetOpener interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+}
+
+// WeComAdapter implements the channel.Adapter, channel.Sender, and channel.Receiver interfaces for WeCom.
+type WeComAdapter struct {
+	logger     *slog.Logger
+	httpClient *http.Client
+	mu         sync.Mutex
+	tokens     map[string]cachedToken // keyed by apiBaseUrl|corpId|secret
+	seen       map[string]time.Time   // keyed by configID|msgID
+	assets     assetOpener
+}
+
+// NewWeComAdapter creates a WeComAdapter with the given logger.
+func NewWeComAdapter(log *slog.Logger) *WeComAdapter {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &WeComAdapter{
+		logger:     log.With(slog.String("adapter", "wecom")),
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		tokens:     make(map[string]cachedToken),
+		seen:       make(map[string]time.Time),
+	}
+}
+
+// SetAssetOpener injects media asset reader for content_hash attachment delivery.
+func (a *WeComAdapter) SetAssetOpener(opener assetOpener) {
+	a.assets = opener
+}
+
+// Type returns the WeCom channel type.
+func (a *WeComAdapter) Type() channel.ChannelType {
+	return Type
+}
+
+// Descriptor returns the WeCom channel metadata.
+func (a *WeComAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type:        Type,
+		DisplayName: "WeCom",
+		Capabilities: channel.ChannelCapabilities{
+			Text:           true,
+			Markdown:       true,
+			Attachments:    true,
+			Media:          true,
+			Buttons:        true,
+			BlockStreaming: true,
+			ChatTypes:      []string{"direct"},
+		},
+		OutboundPolicy: channel.OutboundPolicy{
+			TextChunkLimit: textChunkRunes,
+			ChunkerMode:    channel.ChunkerModeMarkdown,
+		},
+		ConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"corpId":  {Type: channel.FieldString, Required: true, Title: "Corp ID"},
+				"agentId": {Type: channel.FieldNumber, Required: true, Title: "Agent ID"},
+				"secret":  {Type: channel.FieldSecret, Required: true, Title: "Secret"},
+				"token": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "Callback Token",
+					Description: "Token from the application's API receive settings",
+				},
+				"encodingAESKey": {
+					Type:        channel.FieldSecret,
+					Required:    true,
+					Title:       "EncodingAESKey",
+					Description: "43-character key from the application's API receive settings",
+				},
+				"apiBaseUrl": {
+					Type:        channel.FieldString,
+					Title:       "API Base URL",
+					Description: "Override for private deployments or API proxies",
+					Example:     defaultAPIBaseURL,
+				},
+				"processingText": {
+					Type:        channel.FieldString,
+					Title:       "Processing Text",
+					Description: "Optional placeholder sent while a reply is generated and recalled afterwards",
+				},
+			},
+		},
+		UserConfigSchema: channel.ConfigSchema{
+			Version: 1,
+			Fields: map[string]channel.FieldSchema{
+				"user_id": {Type: channel.FieldString, Required: true},
+			},
+		},
+		TargetSpec: channel.TargetSpec{
+			Format: "user:userid | chat:chatid",
+			Hints: []channel.TargetHint{
+				{Label: "User ID", Example: "user:zhangsan"},
+				{Label: "App Chat ID", Example: "chat:wrOgQhDgAA"},
+			},
+		},
+	}
+}
+
+// NormalizeConfig validates and normalizes a WeCom channel configuration map.
+func (a *WeComAdapter) NormalizeConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeConfig(raw)
+}
+
+// NormalizeUserConfig validates and normalizes a WeCom user-binding configuration map.
+func (a *WeComAdapter) NormalizeUserConfig(raw map[string]any) (map[string]any, error) {
+	return normalizeUserConfig(raw)
+}
+
+// NormalizeTarget normalizes a WeCom delivery target string.
+func (a *WeComAdapter) NormalizeTarget(raw string) string {
+	return normalizeTarget(raw)
+}
+
+// ResolveTarget derives a delivery target from a WeCom user-binding configuration.
+func (a *WeComAdapter) ResolveTarget(userConfig map[string]any) (string, error) {
+	return resolveTarget(userConfig)
+}
+
+// MatchBinding reports whether a WeCom user binding matches the given criteria.
+func (a *WeComAdapter) MatchBinding(config map[string]any, criteria channel.BindingCriteria) bool {
+	return matchBinding(config, criteria)
+}
+
+// BuildUserConfig constructs a WeCom user-binding config from an Identity.
+func (a *WeComAdapter) BuildUserConfig(identity channel.Identity) map[string]any {
+	return buildUserConfig(identity)
+}
+
+// DiscoverSelf retrieves the application's name and logo from WeCom.
+func (a *WeComAdapter) DiscoverSelf(ctx context.Context, credentials map[string]any) (map[string]any, string, error) {
+	cfg, err := parseConfig(credentials)
+	if err != nil {
+		return nil, "", err
+	}
+	agentID := strconv.FormatInt(cfg.AgentID, 10)
+	var body struct {
+		Name          string `json:"name"`
+		SquareLogoURL string `json:"square_logo_url"`
+	}
+	query := url.Values{}
+	query.Set("agentid", agentID)
+	if err := a.callAPI(ctx, cfg, "get agent", http.MethodGet, "/cgi-bin/agent/get", query, nil, &body); err != nil {
+		return nil, "", fmt.Errorf("wecom discover self: %w", err)
+	}
+	identity := map[string]any{"agent_id": agentID}
+	if name := strings.TrimSpace(body.Name); name != "" {
+		identity["name"] = name
+	}
+	if avatar := strings.TrimSpace(body.SquareLogoURL); avatar != "" {
+		identity["avatar_url"] = avatar
+	}
+	return identity, agentID, nil
+}
+
+// Connect validates the configuration. Inbound messages arrive through WebhookHandler, so there
+// is no long-lived connection to maintain.
+func (a *WeComAdapter) Connect(ctx context.Context, cfg channel.ChannelConfig, handler channel.InboundHandler) (channel.Connection, error) {
+	if _, err := parseConfig(cfg.Credentials); err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return nil, err
+	}
+	if a.logger != nil {
+		a.logger.Info("callback inbound ready", slog.String("config_id", cfg.ID))
+	}
+	return channel.NewConnection(cfg, func(context.Context) error { return nil }), nil
+}
+
+// Send delivers an outbound message to a WeCom user or application chat.
+func (a *WeComAdapter) Send(ctx context.Context, cfg channel.ChannelConfig, msg channel.OutboundMessage) error {
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		if a.logger != nil {
+			a.logger.Error("decode config failed", slog.String("config_id", cfg.ID), slog.Any("error", err))
+		}
+		return err
+	}
+	target := normalizeTarget(msg.Target)
+	if target == "" {
+		return fmt.Errorf("wecom target is required")
+	}
+	if _, err := a.sendMessage(ctx, cfg, wecomCfg, target, msg.Message); err != nil {
+		if a.logger != nil {
+			a.logger.Error("send failed", slog.String("config_id", cfg.ID), slog.String("target", target), slog.Any("error", err))
+		}
+		return err
+	}
+	return nil
+}
+
+// sendMessage sends attachments first and then the text (as a card when actions are present).
+// It returns the msgid of the last message sent.
+func (a *WeComAdapter) sendMessage(ctx context.Context, cfg channel.ChannelConfig, wecomCfg Config, target string, msg channel.Message) (string, error) {
+	lastID := ""
+	for _, att := range msg.Attachments {
+		msgID, err := a.sendAttachment(ctx, cfg, wecomCfg, target, att)
+		if err != nil {
+			return "", err
+		}
+		lastID = msgID
+	}
+	text := strings.TrimSpace(msg.PlainText())
+	if len(msg.Actions) > 0 && strings.HasPrefix(target, targetPrefixUser) {
+		return a.postMessage(ctx, wecomCfg, target, "template_card", buildTemplateCard(text, msg.Actions))
+	}
+	if len(msg.Actions) > 0 {
+		// Application chats do not support template cards; list the actions in the text instead.
+		text = appendActionText(text, msg.Actions)
+	}
+	if text == "" {
+		if lastID == "" {
+			return "", fmt.Errorf("message is required")
+		}
+		return lastID, nil
+	}
+	msgType := "text"
+	chunks := channel.ChunkText(text, textChunkRunes)
+	if msg.Format == channel.MessageFormatMarkdown {
+		msgType = "markdown"
+		chunks = channel.ChunkMarkdownText(text, textChunkRunes)
+	}
+	for _, chunk := range chunks {
+		msgID, err := a.postMessage(ctx, wecomCfg, target, msgType, map[string]any{"content": chunk})
+		if err != nil {
+			return "", err
+		}
+		lastID = msgID
+	}
+	return lastID, nil
+}
+
+// postMessage sends one message through the application message API, or the app chat API for
+// chat targets, and returns its msgid.
+func (a *WeComAdapter) postMessage(ctx context.Context, cfg Config, target, msgType string, content map[string]any) (string, error) {
+	payload := map[string]any{
+		"msgtype": msgType,
+		msgType:   content,
+	}
+	path := "/cgi-bin/message/send"
+	if chatID, ok := strings.CutPrefix(target, targetPrefixChat); ok {
+		path = "/cgi-bin/appchat/send"
+		payload["chatid"] = chatID
+	} else {
+		payload["touser"] = strings.TrimPrefix(target, targetPrefixUser)
+		payload["agentid"] = cfg.AgentID
+	}
+	var body struct {
+		MsgID       string `json:"msgid"`
+		InvalidUser string `json:"invaliduser"`
+	}
+	if err := a.callAPI(ctx, cfg, "send message", http.MethodPost, path, nil, payload, &body); err != nil {
+		return "", err
+	}
+	if invalid := strings.TrimSpace(body.InvalidUser); invalid != "" {
+		return "", fmt.Errorf("wecom send message: invalid user %s", invalid)
+	}
+	return body.MsgID, nil
+}
+
+// buildTemplateCard renders text and actions as a button_interaction template card.
+func buildTemplateCard(text string, actions []channel.Action) map[string]any {
+	title, rest, _ := strings.Cut(text, "\n")
+	title = strings.TrimSpace(title)
+	rest = strings.TrimSpace(rest)
+	if runes := []rune(title); len(runes) > maxCardTitle {
+		rest = strings.TrimSpace(string(runes[maxCardTitle-1:]) + "\n" + rest)
+		title = string(runes[:maxCardTitle-1]) + "…"
+	}
+	buttons := make([]map[string]any, 0, min(len(actions), maxCardButtons))
+	for _, action := range actions {
+		if len(buttons) == maxCardButtons {
+			break
+		}
+		label := strings.TrimSpace(action.Label)
+		if label == "" {
+			label = strings.TrimSpace(action.Value)
+		}
+		if link := strings.TrimSpace(action.URL); link != "" {
+			if label == "" {
+				label = link
+			}
+			buttons = append(buttons, map[string]any{"type": 1, "text": label, "url": link, "style": 2})
+			continue
+		}
+		key := strings.TrimSpace(action.Value)
+		if key == "" {
+			key = label
+		}
+		if key == "" {
+			continue
+		}
+		buttons = append(buttons, map[string]any{"text": label, "key": key, "style": 1})
+	}
+	card := map[string]any{
+		"card_type":   "button_interaction",
+		"main_title":  map[string]any{"title": title},
+		"task_id":     uuid.NewString(),
+		"button_list": buttons,
+	}
+	if rest != "" {
+		card["sub_title_text"] = rest
+	}
+	return card
+}
+
+func appendActionText(text string, actions []channel.Action) string {
+	lines := make([]string, 0, len(actions))
+	for _, action := range actions {
+		label := strings.TrimSpace(action.Label)
+		if label == "" {
+			label = strings.TrimSpace(action.Value)
+		}
+		if link := strings.TrimSpace(action.URL); link != "" {
+			lines = append(lines, "- "+label+": "+link)
+		} else if label != "" {
+			lines = append(lines, "- "+label)
+		}
+	}
+	if len(lines) == 0 {
+		return text
+	}
+	return strings.TrimSpace(text + "\n\n" + strings.Join(lines, "\n"))
+}
+
+func (a *WeComAdapter) sendAttachment(ctx context.Context, cfg channel.ChannelConfig, wecomCfg Config, target string, att channel.Attachment) (string, error) {
+	mediaType := wecomMediaType(att)
+	mediaID := ""
+	sourcePlatform := strings.TrimSpace(att.SourcePlatform)
+	if key := strings.TrimSpace(att.PlatformKey); key != "" && strings.EqualFold(sourcePlatform, Type.String()) {
+		mediaID = key
+	} else {
+		data, mimeType, err := a.readAttachmentBytes(ctx, cfg, att)
+		if err != nil {
+			return "", err
+		}
+		if att.Mime == "" {
+			att.Mime = mimeType
+			mediaType = wecomMediaType(att)
+		}
+		name := strings.TrimSpace(att.Name)
+		if name == "" {
+			name = fileNameFromMime(mimeType, string(att.Type))
+		}
+		mediaID, err = a.uploadMedia(ctx, wecomCfg, mediaType, name, data)
+		if err != nil {
+			return "", err
+		}
+	}
+	return a.postMessage(ctx, wecomCfg, target, mediaType, map[string]any{"media_id": mediaID})
+}
+
+// wecomMediaType maps an attachment to a WeCom media type. Voice messages must be AMR, so other
+// audio is sent as a file.
+func wecomMediaType(att channel.Attachment) string {
+	mimeType := strings.ToLower(strings.TrimSpace(att.Mime))
+	switch {
+	case att.Type == channel.AttachmentImage || (att.Type != channel.AttachmentFile && strings.HasPrefix(mimeType, "image/")):
+		return "image"
+	case att.Type == channel.AttachmentVideo || (att.Type != channel.AttachmentFile && strings.HasPrefix(mimeType, "video/mp4")):
+		return "video"
+	case strings.HasPrefix(mimeType, "audio/amr"):
+		return "voice"
+	default:
+		return "file"
+	}
+}
+
+// ResolveAttachment downloads a temporary media file by its media_id (the attachment platform key).
+func (a *WeComAdapter) ResolveAttachment(ctx context.Context, cfg channel.ChannelConfig, attachment channel.Attachment) (channel.AttachmentPayload, error) {
+	mediaID := strings.TrimSpace(attachment.PlatformKey)
+	if mediaID == "" {
+		return channel.AttachmentPayload{}, fmt.Errorf("wecom attachment platform_key is required")
+	}
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	resp, err := a.downloadMedia(ctx, wecomCfg, mediaID)
+	if err != nil {
+		return channel.AttachmentPayload{}, err
+	}
+	if resp.ContentLength > media.MaxAssetBytes {
+		_ = resp.Body.Close()
+		return channel.AttachmentPayload{}, fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+	}
+	mimeType := strings.TrimSpace(attachment.Mime)
+	if mimeType == "" {
+		mimeType = stripMimeParams(resp.Header.Get("Content-Type"))
+	}
+	name := strings.TrimSpace(attachment.Name)
+	if name == "" {
+		if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+			name = strings.Trim(strings.TrimSpace(params["filename"]), `"`)
+		}
+	}
+	size := attachment.Size
+	if size == 0 && resp.ContentLength > 0 {
+		size = resp.ContentLength
+	}
+	return channel.AttachmentPayload{
+		Reader: resp.Body,
+		Mime:   mimeType,
+		Name:   name,
+		Size:   size,
+	}, nil
+}
+
+// ProcessingStarted sends the configured placeholder text, if any, while the reply is generated.
+func (a *WeComAdapter) ProcessingStarted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo) (channel.ProcessingStatusHandle, error) {
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	target := normalizeTarget(msg.ReplyTarget)
+	if wecomCfg.ProcessingText == "" || target == "" {
+		return channel.ProcessingStatusHandle{}, nil
+	}
+	msgID, err := a.postMessage(ctx, wecomCfg, target, "text", map[string]any{"content": wecomCfg.ProcessingText})
+	if err != nil {
+		return channel.ProcessingStatusHandle{}, err
+	}
+	return channel.ProcessingStatusHandle{Token: msgID}, nil
+}
+
+// ProcessingCompleted recalls the placeholder message before output is sent.
+func (a *WeComAdapter) ProcessingCompleted(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle) error {
+	msgID := strings.TrimSpace(handle.Token)
+	if msgID == "" {
+		return nil
+	}
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return err
+	}
+	return a.callAPI(ctx, wecomCfg, "recall message", http.MethodPost, "/cgi-bin/message/recall", nil, map[string]any{"msgid": msgID}, nil)
+}
+
+// ProcessingFailed recalls the placeholder message when chat processing fails.
+func (a *WeComAdapter) ProcessingFailed(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, info channel.ProcessingStatusInfo, handle channel.ProcessingStatusHandle, cause error) error {
+	return a.ProcessingCompleted(ctx, cfg, msg, info, handle)
+}
+
+// OpenStream opens a block-streaming session that sends the reply once it is complete,
+// since WeCom application messages cannot be edited.
+func (a *WeComAdapter) OpenStream(ctx context.Context, cfg channel.ChannelConfig, target string, opts channel.StreamOptions) (channel.OutboundStream, error) {
+	target = normalizeTarget(target)
+	if target == "" {
+		return nil, fmt.Errorf("wecom target is required")
+	}
+	wecomCfg, err := parseConfig(cfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	return &wecomOutboundStream{
+		adapter:  a,
+		cfg:      cfg,
+		wecomCfg: wecomCfg,
+		target:   target,
+	}, nil
+}
+
+func (a *WeComAdapter) isDuplicateInbound(key, msgID string) bool {
+	if strings.TrimSpace(msgID) == "" {
+		return false
+	}
+	now := time.Now().UTC()
+	expireBefore := now.Add(-inboundDedupTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for seenKey, seenAt := range a.seen {
+		if seenAt.Before(expireBefore) {
+			delete(a.seen, seenKey)
+		}
+	}
+	seenKey := key + "|" + msgID
+	if _, ok := a.seen[seenKey]; ok {
+		return true
+	}
+	a.seen[seenKey] = now
+	return false
+}
+
+func (a *WeComAdapter) readAttachmentBytes(ctx context.Context, cfg channel.ChannelConfig, att channel.Attachment) ([]byte, string, error) {
+	mimeType := strings.TrimSpace(att.Mime)
+	if hash := strings.TrimSpace(att.ContentHash); hash != "" && a.assets != nil {
+		botID := cfg.BotID
+		if att.Metadata != nil {
+			if bid, ok := att.Metadata["bot_id"].(string); ok && strings.TrimSpace(bid) != "" {
+				botID = strings.TrimSpace(bid)
+			}
+		}
+		reader, asset, err := a.assets.Open(ctx, botID, hash)
+		if err == nil {
+			data, readErr := io.ReadAll(io.LimitReader(reader, media.MaxAssetBytes+1))
+			_ = reader.Close()
+			if readErr == nil && len(data) > 0 {
+				if mimeType == "" {
+					mimeType = asset.Mime
+				}
+				return data, mimeType, nil
+			}
+		}
+	}
+	dataURL := strings.TrimSpace(att.Base64)
+	if dataURL == "" && strings.HasPrefix(strings.ToLower(strings.TrimSpace(att.URL)), "data:") {
+		dataURL = strings.TrimSpace(att.URL)
+	}
+	if dataURL != "" {
+		data, dataMime, err := decodeDataURL(dataURL)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode data url for wecom attachment: %w", err)
+		}
+		if mimeType == "" {
+			mimeType = dataMime
+		}
+		return data, mimeType, nil
+	}
+	urlRef := strings.TrimSpace(att.URL)
+	if strings.HasPrefix(urlRef, "http://") || strings.HasPrefix(urlRef, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlRef, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return nil, "", fmt.Errorf("download attachment: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, "", fmt.Errorf("download attachment status: %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, media.MaxAssetBytes+1))
+		if err != nil {
+			return nil, "", err
+		}
+		if int64(len(data)) > media.MaxAssetBytes {
+			return nil, "", fmt.Errorf("%w: max %d bytes", media.ErrAssetTooLarge, media.MaxAssetBytes)
+		}
+		if mimeType == "" {
+			mimeType = stripMimeParams(resp.Header.Get("Content-Type"))
+		}
+		return data, mimeType, nil
+	}
+	return nil, "", fmt.Errorf("no usable attachment reference for wecom")
+}
+
+func decodeDataURL(dataURL string) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(dataURL, ",")
+	if !ok {
+		return nil, "", fmt.Errorf("malformed data url")
+	}
+	mimeType := stripMimeParams(strings.TrimPrefix(strings.TrimPrefix(header, "data:"), "DATA:"))
+	data, err := io.ReadAll(io.LimitReader(
+		base64.NewDecoder(base64.StdEncoding, strings.NewReader(payload)),
+		media.MaxAssetBytes+1,
+	))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mimeType, nil
+}
+
+func stripMimeParams(value string) string {
+	value = strings.TrimSpace(value)
+	if idx := strings.Index(value, ";"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return value
+}
+
+func fileNameFromMime(mimeType, fallbackType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	switch {
+	case strings.HasPrefix(mimeType, "image/png"):
+		return "image.png"
+	case strings.HasPrefix(mimeType, "image/jpeg"):
+		return "image.jpg"
+	case strings.HasPrefix(mimeType, "image/gif"):
+		return "image.gif"
+	case strings.HasPrefix(mimeType, "audio/amr"):
+		return "voice.amr"
+	case strings.HasPrefix(mimeType, "audio/mpeg"):
+		return "audio.mp3"
+	case strings.HasPrefix(mimeType, "video/mp4"):
+		return "video.mp4"
+	case strings.HasPrefix(mimeType, "application/pdf"):
+		return "document.pdf"
+	}
+	if fallbackType == "" {
+		return "file"
+	}
+	return fallbackType
+}
@@ -0,0 +1,259 @@
+package wecom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// stubAPI is a minimal WeCom API server recording requests per path.
+type stubAPI struct {
+	mu          sync.Mutex
+	tokenCalls  int
+	expireFirst bool
+	sent        []map[string]any
+	uploads     []string
+	recalled    []string
+}
+
+func (s *stubAPI) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("/cgi-bin/gettoken", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("corpsecret") != "app-secret" {
+			writeJSON(w, map[string]any{"errcode": 40001, "errmsg": "invalid credential"})
+			return
+		}
+		s.mu.Lock()
+		s.tokenCalls++
+		token := "tok-" + string(rune('0'+s.tokenCalls))
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"errcode": 0, "access_token": token, "expires_in": 7200})
+	})
+	mux.HandleFunc("/cgi-bin/message/send", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		expire := s.expireFirst && r.URL.Query().Get("access_token") == "tok-1"
+		s.mu.Unlock()
+		if expire {
+			writeJSON(w, map[string]any{"errcode": errcodeExpiredToken, "errmsg": "access_token expired"})
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode send body: %v", err)
+		}
+		s.mu.Lock()
+		s.sent = append(s.sent, body)
+		n := len(s.sent)
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"errcode": 0, "msgid": "msg-" + string(rune('0'+n))})
+	})
+	mux.HandleFunc("/cgi-bin/media/upload", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("media")
+		if err != nil {
+			t.Errorf("read upload: %v", err)
+			return
+		}
+		_, _ = io.ReadAll(file)
+		s.mu.Lock()
+		s.uploads = append(s.uploads, r.URL.Query().Get("type")+":"+header.Filename)
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"errcode": 0, "type": r.URL.Query().Get("type"), "media_id": "media-up"})
+	})
+	mux.HandleFunc("/cgi-bin/media/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("media_id") != "media-in" {
+			writeJSON(w, map[string]any{"errcode": 40007, "errmsg": "invalid media_id"})
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Content-Disposition", `attachment; filename="photo.jpg"`)
+		_, _ = w.Write([]byte("jpeg-bytes"))
+	})
+	mux.HandleFunc("/cgi-bin/message/recall", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			MsgID string `json:"msgid"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		s.recalled = append(s.recalled, body.MsgID)
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"errcode": 0})
+	})
+	mux.HandleFunc("/cgi-bin/user/simplelist", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"errcode": 0, "userlist": []map[string]any{
+			{"userid": "zhangsan", "name": "Zhang San"},
+			{"userid": "lisi", "name": "Li Si"},
+		}})
+	})
+	mux.HandleFunc("/cgi-bin/user/get", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"errcode": 0, "userid": r.URL.Query().Get("userid"), "name": "Zhang San", "avatar": "https://example.com/a.png"})
+	})
+	return mux
+}
+
+func newStubAdapter(t *testing.T, api *stubAPI) (*WeComAdapter, channel.ChannelConfig) {
+	t.Helper()
+	srv := httptest.NewServer(api.handler(t))
+	t.Cleanup(srv.Close)
+	creds := validCredentials()
+	creds["apiBaseUrl"] = srv.URL
+	return NewWeComAdapter(nil), channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: Type, Credentials: creds}
+}
+
+func TestSendMarkdownRefreshesExpiredToken(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{expireFirst: true}
+	adapter, cfg := newStubAdapter(t, api)
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target:  "zhangsan",
+		Message: channel.Message{Format: channel.MessageFormatMarkdown, Text: "**hello**"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if api.tokenCalls != 2 {
+		t.Fatalf("expected token refresh, got %d token calls", api.tokenCalls)
+	}
+	if len(api.sent) != 1 {
+		t.Fatalf("expected one message, got %d", len(api.sent))
+	}
+	got := api.sent[0]
+	if got["msgtype"] != "markdown" || got["touser"] != "zhangsan" || got["agentid"] != float64(1000002) {
+		t.Fatalf("unexpected payload: %#v", got)
+	}
+	if got["markdown"].(map[string]any)["content"] != "**hello**" {
+		t.Fatalf("unexpected markdown: %#v", got["markdown"])
+	}
+}
+
+func TestSendActionsAsTemplateCard(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target: "user:zhangsan",
+		Message: channel.Message{
+			Text: "Approve the request?\nRequested by Li Si",
+			Actions: []channel.Action{
+				{Type: "button", Label: "Approve", Value: "approve"},
+				{Type: "link", Label: "Details", URL: "https://example.com/r/1"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	card := api.sent[0]["template_card"].(map[string]any)
+	if card["card_type"] != "button_interaction" || card["sub_title_text"] != "Requested by Li Si" {
+		t.Fatalf("unexpected card: %#v", card)
+	}
+	if card["main_title"].(map[string]any)["title"] != "Approve the request?" {
+		t.Fatalf("unexpected title: %#v", card["main_title"])
+	}
+	buttons := card["button_list"].([]any)
+	if len(buttons) != 2 || buttons[0].(map[string]any)["key"] != "approve" || buttons[1].(map[string]any)["url"] != "https://example.com/r/1" {
+		t.Fatalf("unexpected buttons: %#v", buttons)
+	}
+}
+
+func TestSendAttachmentUploadsMedia(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	err := adapter.Send(context.Background(), cfg, channel.OutboundMessage{
+		Target: "user:zhangsan",
+		Message: channel.Message{Attachments: []channel.Attachment{{
+			Type:   channel.AttachmentImage,
+			Base64: "data:image/png;base64,aGVsbG8=",
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(api.uploads) != 1 || api.uploads[0] != "image:image.png" {
+		t.Fatalf("unexpected uploads: %v", api.uploads)
+	}
+	if api.sent[0]["msgtype"] != "image" || api.sent[0]["image"].(map[string]any)["media_id"] != "media-up" {
+		t.Fatalf("unexpected payload: %#v", api.sent[0])
+	}
+}
+
+func TestResolveAttachmentDownloadsMedia(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	payload, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "media-in"})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	defer payload.Reader.Close()
+	data, _ := io.ReadAll(payload.Reader)
+	if string(data) != "jpeg-bytes" || payload.Mime != "image/jpeg" || payload.Name != "photo.jpg" {
+		t.Fatalf("unexpected payload: %q %s %s", data, payload.Mime, payload.Name)
+	}
+	if _, err := adapter.ResolveAttachment(context.Background(), cfg, channel.Attachment{PlatformKey: "missing"}); err == nil {
+		t.Fatal("expected error for unknown media")
+	}
+}
+
+func TestProcessingStatusSendsAndRecallsPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	msg := channel.InboundMessage{ReplyTarget: "user:zhangsan"}
+	handle, err := adapter.ProcessingStarted(context.Background(), cfg, msg, channel.ProcessingStatusInfo{})
+	if err != nil || handle.Token != "" {
+		t.Fatalf("expected no placeholder without processingText, got %q err=%v", handle.Token, err)
+	}
+
+	cfg.Credentials["processingText"] = "Thinking…"
+	handle, err = adapter.ProcessingStarted(context.Background(), cfg, msg, channel.ProcessingStatusInfo{})
+	if err != nil || handle.Token == "" {
+		t.Fatalf("expected placeholder, got %q err=%v", handle.Token, err)
+	}
+	if err := adapter.ProcessingCompleted(context.Background(), cfg, msg, channel.ProcessingStatusInfo{}, handle); err != nil {
+		t.Fatalf("completed: %v", err)
+	}
+	if len(api.recalled) != 1 || api.recalled[0] != handle.Token {
+		t.Fatalf("expected recall of %q, got %v", handle.Token, api.recalled)
+	}
+}
+
+func TestDirectoryLookup(t *testing.T) {
+	t.Parallel()
+
+	api := &stubAPI{}
+	adapter, cfg := newStubAdapter(t, api)
+	peers, err := adapter.ListPeers(context.Background(), cfg, channel.DirectoryQuery{Query: "li"})
+	if err != nil {
+		t.Fatalf("list peers: %v", err)
+	}
+	if len(peers) != 1 || peers[0].ID != "user:lisi" || peers[0].Name != "Li Si" {
+		t.Fatalf("unexpected peers: %#v", peers)
+	}
+	entry, err := adapter.ResolveEntry(context.Background(), cfg, "user:zhangsan", channel.DirectoryEntryUser)
+	if err != nil {
+		t.Fatalf("resolve entry: %v", err)
+	}
+	if entry.ID != "user:zhangsan" || entry.AvatarURL != "https://example.com/a.png" {
+		t.Fatalf("unexpected entry: %#v", entry)
+	}
+	groups, err := adapter.ListGroups(context.Background(), cfg, channel.DirectoryQuery{})
+	if err != nil || groups != nil {
+		t.Fatalf("expected no groups, got %#v err=%v", groups, err)
+	}
+}
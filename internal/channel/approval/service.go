@@ -0,0 +1,644 @@
+// Package approval applies a bot's outbound policy to messages it sends on its own to
+// conversations other than the one it is replying in. Depending on the policy such
+// messages are sent, refused, or held until the bot owner approves them from a DM.
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/identities"
+	"github.com/memohai/memoh/internal/channel/inbound"
+	"github.com/memohai/memoh/internal/channel/outbox"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+var (
+	// ErrInvalidPolicy is returned when a policy fails validation.
+	ErrInvalidPolicy = errors.New("invalid outbound policy")
+	// ErrInvalidRequest is returned when a decision is malformed.
+	ErrInvalidRequest = errors.New("invalid approval request")
+	// ErrNotFound is returned when the held message does not exist or belongs to another bot.
+	ErrNotFound = errors.New("approval not found")
+	// ErrNotPending is returned when deciding a message that was already decided or expired.
+	ErrNotPending = errors.New("approval is no longer pending")
+)
+
+const (
+	// approvalTTL is how long the owner has to answer before a held message expires.
+	approvalTTL = 24 * time.Hour
+	// approvalWait is how long a send waits for the owner before reporting the message
+	// as held.
+	approvalWait = 45 * time.Second
+	// actionPrefix starts the button values of approval prompts. Telegram limits
+	// callback data to 64 bytes, so values stay short.
+	actionPrefix = "approval:"
+
+	defaultListLimit = 50
+	maxListLimit     = 200
+	// maxPreviewRunes bounds the message excerpt shown to the owner.
+	maxPreviewRunes = 500
+)
+
+// messageSender delivers approval prompts; implemented by channel.Manager.
+type messageSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// outboxScheduler delivers approved messages and resolves the bot timezone.
+type outboxScheduler interface {
+	Schedule(ctx context.Context, botID string, source outbox.Source, createdBy string, req outbox.ScheduleRequest) (outbox.Message, error)
+	Location(ctx context.Context, botID string) *time.Location
+}
+
+// ownerResolver returns the owner user of a bot.
+type ownerResolver interface {
+	BotOwnerUserID(ctx context.Context, botID string) (string, error)
+}
+
+// userIdentityLister lists the channel identities linked to a user.
+type userIdentityLister interface {
+	ListUserChannelIdentities(ctx context.Context, userID string) ([]identities.ChannelIdentity, error)
+}
+
+// approvalQueries is the subset of sqlc queries used by Service.
+type approvalQueries interface {
+	GetChannelOutboundPolicy(ctx context.Context, botID pgtype.UUID) (sqlc.ChannelOutboundPolicy, error)
+	UpsertChannelOutboundPolicy(ctx context.Context, arg sqlc.UpsertChannelOutboundPolicyParams) (sqlc.ChannelOutboundPolicy, error)
+	CreateChannelOutboundApproval(ctx context.Context, arg sqlc.CreateChannelOutboundApprovalParams) (sqlc.ChannelOutboundApproval, error)
+	GetChannelOutboundApproval(ctx context.Context, arg sqlc.GetChannelOutboundApprovalParams) (sqlc.ChannelOutboundApproval, error)
+	ListChannelOutboundApprovals(ctx context.Context, arg sqlc.ListChannelOutboundApprovalsParams) ([]sqlc.ChannelOutboundApproval, error)
+	DecideChannelOutboundApproval(ctx context.Context, arg sqlc.DecideChannelOutboundApprovalParams) (sqlc.ChannelOutboundApproval, error)
+	SetChannelOutboundApprovalOutboxMessage(ctx context.Context, arg sqlc.SetChannelOutboundApprovalOutboxMessageParams) error
+}
+
+// Service stores outbound policies and held messages.
+type Service struct {
+	queries    approvalQueries
+	owners     ownerResolver
+	identities userIdentityLister
+	sender     messageSender
+	outbox     outboxScheduler
+	now        func() time.Time
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	waiters map[string]chan Approval
+}
+
+// NewService creates an approval service.
+func NewService(log *slog.Logger, queries *sqlc.Queries, owners ownerResolver, identityLister userIdentityLister) *Service {
+	if queries == nil {
+		return newService(log, nil, owners, identityLister)
+	}
+	return newService(log, queries, owners, identityLister)
+}
+
+func newService(log *slog.Logger, queries approvalQueries, owners ownerResolver, identityLister userIdentityLister) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		queries:    queries,
+		owners:     owners,
+		identities: identityLister,
+		now:        time.Now,
+		logger:     log.With(slog.String("service", "approval")),
+		waiters:    make(map[string]chan Approval),
+	}
+}
+
+// SetSender configures delivery of approval prompts. It is set after construction
+// because the channel manager runs the approval middleware.
+func (s *Service) SetSender(sender messageSender) {
+	if s == nil {
+		return
+	}
+	s.sender = sender
+}
+
+// SetOutbox configures delivery of approved messages. It is set after construction
+// because the outbox sends through the channel manager.
+func (s *Service) SetOutbox(scheduler outboxScheduler) {
+	if s == nil {
+		return
+	}
+	s.outbox = scheduler
+}
+
+// GetPolicy returns the outbound policy of a bot. Bots without one get an open policy.
+func (s *Service) GetPolicy(ctx context.Context, botID string) (Policy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Policy{}, err
+	}
+	row, err := s.queries.GetChannelOutboundPolicy(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Policy{}.normalize(), nil
+		}
+		return Policy{}, err
+	}
+	var policy Policy
+	if len(row.Policy) > 0 {
+		if err := json.Unmarshal(row.Policy, &policy); err != nil {
+			return Policy{}, fmt.Errorf("decode outbound policy: %w", err)
+		}
+	}
+	return policy.normalize(), nil
+}
+
+// UpdatePolicy validates and stores the outbound policy of a bot.
+func (s *Service) UpdatePolicy(ctx context.Context, botID string, policy Policy) (Policy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy = policy.normalize()
+	if err := policy.validate(); err != nil {
+		return Policy{}, fmt.Errorf("%w: %s", ErrInvalidPolicy, err.Error())
+	}
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return Policy{}, err
+	}
+	if _, err := s.queries.UpsertChannelOutboundPolicy(ctx, sqlc.UpsertChannelOutboundPolicyParams{
+		BotID:  pgBotID,
+		Policy: payload,
+	}); err != nil {
+		return Policy{}, err
+	}
+	return policy, nil
+}
+
+// Authorize checks a message against the bot's policy. In approval mode a message to an
+// unknown target is held, the owner is asked in a DM, and Authorize waits briefly for
+// the answer before reporting the message as pending.
+func (s *Service) Authorize(ctx context.Context, req Request) (Result, error) {
+	policy, err := s.GetPolicy(ctx, req.BotID)
+	if err != nil {
+		return Result{}, err
+	}
+	if policy.Mode == ModeOpen || policy.allows(req.Platform, req.Target) {
+		return Result{Outcome: OutcomeAllowed}, nil
+	}
+	if policy.Mode == ModeAllowlist {
+		return Result{
+			Outcome: OutcomeDenied,
+			Reason:  fmt.Sprintf("the bot's outbound policy does not allow messages to %s on %s", req.Target, req.Platform),
+		}, nil
+	}
+	if s.outbox == nil {
+		return Result{}, errors.New("approval outbox not configured")
+	}
+	item, err := s.hold(ctx, req)
+	if err != nil {
+		return Result{}, err
+	}
+	notified := s.notifyOwner(ctx, item)
+
+	decided, ok := s.wait(ctx, item.ID, approvalWait)
+	if !ok {
+		reason := "waiting for the owner to approve the message"
+		if !notified {
+			reason = "the owner could not be reached in a DM; the message waits for approval in the web UI"
+		}
+		return Result{Outcome: OutcomePending, Reason: reason, ApprovalID: item.ID}, nil
+	}
+	if decided.Status != StatusApproved {
+		return Result{Outcome: OutcomeDenied, Reason: "the owner denied the message", ApprovalID: item.ID}, nil
+	}
+	return Result{Outcome: OutcomeApproved, ApprovalID: item.ID, OutboxMessageID: decided.OutboxMessageID}, nil
+}
+
+func (s *Service) hold(ctx context.Context, req Request) (Approval, error) {
+	pgBotID, err := db.ParseUUID(req.BotID)
+	if err != nil {
+		return Approval{}, err
+	}
+	now := s.now()
+	var sendAt pgtype.Timestamptz
+	if strings.TrimSpace(req.SendAt) != "" || strings.TrimSpace(req.Delay) != "" {
+		at, err := outbox.ResolveSendAt(req.SendAt, req.Delay, s.outbox.Location(ctx, req.BotID), now)
+		if err != nil {
+			return Approval{}, err
+		}
+		sendAt = pgtype.Timestamptz{Time: at.UTC(), Valid: true}
+	}
+	payload, err := json.Marshal(req.Message)
+	if err != nil {
+		return Approval{}, fmt.Errorf("encode message: %w", err)
+	}
+	row, err := s.queries.CreateChannelOutboundApproval(ctx, sqlc.CreateChannelOutboundApprovalParams{
+		BotID:       pgBotID,
+		ChannelType: strings.TrimSpace(req.Platform),
+		Target:      strings.TrimSpace(req.Target),
+		Payload:     payload,
+		SendAt:      sendAt,
+		RequestedBy: strings.TrimSpace(req.RequestedBy),
+		ExpiresAt:   pgtype.Timestamptz{Time: now.Add(approvalTTL).UTC(), Valid: true},
+	})
+	if err != nil {
+		return Approval{}, err
+	}
+	return s.toApproval(row), nil
+}
+
+// Get returns a held message of a bot.
+func (s *Service) Get(ctx context.Context, botID, id string) (Approval, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Approval{}, err
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Approval{}, ErrNotFound
+	}
+	row, err := s.queries.GetChannelOutboundApproval(ctx, sqlc.GetChannelOutboundApprovalParams{ID: pgID, BotID: pgBotID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Approval{}, ErrNotFound
+		}
+		return Approval{}, err
+	}
+	return s.toApproval(row), nil
+}
+
+// List returns the most recent held messages of a bot.
+func (s *Service) List(ctx context.Context, botID string, limit int) ([]Approval, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxListLimit {
+		limit = defaultListLimit
+	}
+	rows, err := s.queries.ListChannelOutboundApprovals(ctx, sqlc.ListChannelOutboundApprovalsParams{
+		BotID:    pgBotID,
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Approval, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, s.toApproval(row))
+	}
+	return items, nil
+}
+
+// Decide approves or denies a held message. Approved messages are handed to the outbox;
+// DecisionAlways also adds the target to the allowlist.
+func (s *Service) Decide(ctx context.Context, botID, id, decidedBy string, decision Decision) (Approval, error) {
+	status := StatusApproved
+	switch decision {
+	case DecisionApprove, DecisionAlways:
+	case DecisionDeny:
+		status = StatusDenied
+	default:
+		return Approval{}, fmt.Errorf("%w: unknown decision %q", ErrInvalidRequest, decision)
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Approval{}, err
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Approval{}, ErrNotFound
+	}
+	row, err := s.queries.DecideChannelOutboundApproval(ctx, sqlc.DecideChannelOutboundApprovalParams{
+		Status:    string(status),
+		DecidedBy: strings.TrimSpace(decidedBy),
+		ID:        pgID,
+		BotID:     pgBotID,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return Approval{}, err
+		}
+		if _, err := s.Get(ctx, botID, id); err != nil {
+			return Approval{}, err
+		}
+		return Approval{}, ErrNotPending
+	}
+	item := s.toApproval(row)
+	if status == StatusApproved {
+		if err := s.deliver(ctx, &item); err != nil {
+			s.logger.Warn("deliver approved message failed", slog.String("approval_id", item.ID), slog.Any("error", err))
+		}
+	}
+	if decision == DecisionAlways {
+		if err := s.allowTarget(ctx, botID, item.Platform, item.Target); err != nil {
+			s.logger.Warn("add target to allowlist failed", slog.String("approval_id", item.ID), slog.Any("error", err))
+		}
+	}
+	s.signal(item)
+	return item, nil
+}
+
+// deliver hands an approved message to the outbox, keeping its send time when it is
+// still ahead.
+func (s *Service) deliver(ctx context.Context, item *Approval) error {
+	if s.outbox == nil {
+		return errors.New("approval outbox not configured")
+	}
+	req := outbox.ScheduleRequest{
+		Platform: item.Platform,
+		Target:   item.Target,
+		Message:  item.Message,
+	}
+	if item.SendAt != nil && item.SendAt.After(s.now()) {
+		req.SendAt = item.SendAt.Format(time.RFC3339)
+	}
+	msg, err := s.outbox.Schedule(ctx, item.BotID, outbox.SourceTool, item.RequestedBy, req)
+	if err != nil {
+		return err
+	}
+	item.OutboxMessageID = msg.ID
+	pgMsgID, err := db.ParseUUID(msg.ID)
+	if err != nil {
+		return err
+	}
+	pgID, err := db.ParseUUID(item.ID)
+	if err != nil {
+		return err
+	}
+	return s.queries.SetChannelOutboundApprovalOutboxMessage(ctx, sqlc.SetChannelOutboundApprovalOutboxMessageParams{
+		OutboxMessageID: pgMsgID,
+		ID:              pgID,
+	})
+}
+
+func (s *Service) allowTarget(ctx context.Context, botID, platform, target string) error {
+	policy, err := s.GetPolicy(ctx, botID)
+	if err != nil {
+		return err
+	}
+	if policy.allows(platform, target) {
+		return nil
+	}
+	policy.Allowlist = append(policy.Allowlist, AllowedTarget{Platform: platform, Target: target})
+	_, err = s.UpdatePolicy(ctx, botID, policy)
+	return err
+}
+
+func (s *Service) wait(ctx context.Context, id string, timeout time.Duration) (Approval, bool) {
+	ch := make(chan Approval, 1)
+	s.mu.Lock()
+	s.waiters[id] = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.waiters, id)
+		s.mu.Unlock()
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case item := <-ch:
+		return item, true
+	case <-timer.C:
+		return Approval{}, false
+	case <-ctx.Done():
+		return Approval{}, false
+	}
+}
+
+func (s *Service) signal(item Approval) {
+	s.mu.Lock()
+	ch, ok := s.waiters[item.ID]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case ch <- item:
+	default:
+	}
+}
+
+// Middleware returns a channel middleware that consumes the owner's presses on approval
+// prompt buttons. It must run after identity resolution so the owner is recognized.
+func (s *Service) Middleware() channel.Middleware {
+	return func(next channel.InboundHandler) channel.InboundHandler {
+		return func(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error {
+			if s.handleAction(ctx, cfg, msg) {
+				return nil
+			}
+			return next(ctx, cfg, msg)
+		}
+	}
+}
+
+// handleAction decides a held message from a button press and reports whether the
+// event was an approval answer.
+func (s *Service) handleAction(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) bool {
+	if s == nil || s.queries == nil || !msg.IsAction() {
+		return false
+	}
+	decision, id, ok := parseActionValue(msg.Action.Value)
+	if !ok {
+		return false
+	}
+	botID := strings.TrimSpace(msg.BotID)
+	if botID == "" {
+		botID = cfg.BotID
+	}
+	state, _ := inbound.IdentityStateFromContext(ctx)
+	if !s.isOwner(ctx, botID, state) {
+		s.logger.Warn("approval answer from non-owner ignored", slog.String("bot_id", botID), slog.String("approval_id", id))
+		return true
+	}
+	var text string
+	item, err := s.Decide(ctx, botID, id, state.Identity.UserID, decision)
+	switch {
+	case err == nil:
+		text = decisionNotice(item, decision)
+	case errors.Is(err, ErrNotPending), errors.Is(err, ErrNotFound):
+		text = "This request was already answered or has expired."
+	default:
+		s.logger.Warn("decide approval failed", slog.String("approval_id", id), slog.Any("error", err))
+		text = "Could not record your answer, please try again."
+	}
+	s.reply(ctx, botID, msg, text)
+	return true
+}
+
+func (s *Service) reply(ctx context.Context, botID string, msg channel.InboundMessage, text string) {
+	target := strings.TrimSpace(msg.ReplyTarget)
+	if s.sender == nil || target == "" {
+		return
+	}
+	if err := s.sender.Send(ctx, botID, msg.Channel, channel.SendRequest{
+		Target:  target,
+		Message: channel.Message{Text: text},
+	}); err != nil {
+		s.logger.Warn("send approval confirmation failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+func (s *Service) isOwner(ctx context.Context, botID string, state inbound.IdentityState) bool {
+	userID := strings.TrimSpace(state.Identity.UserID)
+	if userID == "" || s.owners == nil {
+		return false
+	}
+	ownerID, err := s.owners.BotOwnerUserID(ctx, botID)
+	if err != nil {
+		s.logger.Warn("resolve bot owner failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return false
+	}
+	return strings.TrimSpace(ownerID) == userID
+}
+
+// notifyOwner asks the bot owner about a held message in a DM, preferring their
+// identity on the platform the message is for. It reports whether a prompt was sent.
+func (s *Service) notifyOwner(ctx context.Context, item Approval) bool {
+	if s.sender == nil || s.owners == nil || s.identities == nil {
+		return false
+	}
+	ownerID, err := s.owners.BotOwnerUserID(ctx, item.BotID)
+	if err != nil || strings.TrimSpace(ownerID) == "" {
+		s.logger.Warn("resolve bot owner failed", slog.String("bot_id", item.BotID), slog.Any("error", err))
+		return false
+	}
+	identityList, err := s.identities.ListUserChannelIdentities(ctx, ownerID)
+	if err != nil {
+		s.logger.Warn("list owner identities failed", slog.String("bot_id", item.BotID), slog.Any("error", err))
+		return false
+	}
+	ordered := make([]identities.ChannelIdentity, 0, len(identityList))
+	for _, identity := range identityList {
+		if strings.EqualFold(identity.Channel, item.Platform) {
+			ordered = append(ordered, identity)
+		}
+	}
+	for _, identity := range identityList {
+		if !strings.EqualFold(identity.Channel, item.Platform) {
+			ordered = append(ordered, identity)
+		}
+	}
+	prompt := approvalPrompt(item, s.location(ctx, item.BotID))
+	for _, identity := range ordered {
+		err := s.sender.Send(ctx, item.BotID, channel.ChannelType(identity.Channel), channel.SendRequest{
+			ChannelIdentityID: identity.ID,
+			Message:           prompt,
+		})
+		if err == nil {
+			return true
+		}
+		s.logger.Warn("send approval prompt failed", slog.String("bot_id", item.BotID), slog.String("channel", identity.Channel), slog.Any("error", err))
+	}
+	return false
+}
+
+func (s *Service) location(ctx context.Context, botID string) *time.Location {
+	if s.outbox == nil {
+		return time.UTC
+	}
+	return s.outbox.Location(ctx, botID)
+}
+
+func (s *Service) toApproval(row sqlc.ChannelOutboundApproval) Approval {
+	item := Approval{
+		ID:          row.ID.String(),
+		BotID:       row.BotID.String(),
+		Platform:    row.ChannelType,
+		Target:      row.Target,
+		RequestedBy: row.RequestedBy,
+		Status:      Status(row.Status),
+		DecidedBy:   row.DecidedBy,
+		ExpiresAt:   db.TimeFromPg(row.ExpiresAt),
+		CreatedAt:   db.TimeFromPg(row.CreatedAt),
+	}
+	_ = json.Unmarshal(row.Payload, &item.Message)
+	if row.SendAt.Valid {
+		sendAt := row.SendAt.Time
+		item.SendAt = &sendAt
+	}
+	if row.DecidedAt.Valid {
+		decidedAt := row.DecidedAt.Time
+		item.DecidedAt = &decidedAt
+	}
+	if row.OutboxMessageID.Valid {
+		item.OutboxMessageID = row.OutboxMessageID.String()
+	}
+	if item.Status == StatusPending && !item.ExpiresAt.After(s.now()) {
+		item.Status = StatusExpired
+	}
+	return item
+}
+
+// approvalPrompt builds the DM asking the owner about a held message.
+func approvalPrompt(item Approval, loc *time.Location) channel.Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your bot wants to send a message to %s on %s", item.Target, item.Platform)
+	if item.SendAt != nil {
+		fmt.Fprintf(&b, " at %s", item.SendAt.In(loc).Format("2006-01-02 15:04 MST"))
+	}
+	b.WriteString(":\n\n")
+	text := strings.TrimSpace(item.Message.PlainText())
+	if runes := []rune(text); len(runes) > maxPreviewRunes {
+		text = string(runes[:maxPreviewRunes]) + "…"
+	}
+	if text == "" {
+		text = fmt.Sprintf("(%d attachment(s))", len(item.Message.Attachments))
+	}
+	b.WriteString(text)
+	fmt.Fprintf(&b, "\n\nThis request expires at %s.", item.ExpiresAt.In(loc).Format("2006-01-02 15:04 MST"))
+	return channel.Message{
+		Text: b.String(),
+		Actions: []channel.Action{
+			{Type: "button", Label: "Approve", Value: actionValue(DecisionApprove, item.ID)},
+			{Type: "button", Label: "Always allow", Value: actionValue(DecisionAlways, item.ID)},
+			{Type: "button", Label: "Deny", Value: actionValue(DecisionDeny, item.ID)},
+		},
+	}
+}
+
+func decisionNotice(item Approval, decision Decision) string {
+	switch decision {
+	case DecisionDeny:
+		return fmt.Sprintf("Denied. The message to %s was not sent.", item.Target)
+	case DecisionAlways:
+		return fmt.Sprintf("Approved. The message to %s is on its way, and %s on %s no longer needs approval.", item.Target, item.Target, item.Platform)
+	default:
+		return fmt.Sprintf("Approved. The message to %s is on its way.", item.Target)
+	}
+}
+
+func actionValue(decision Decision, id string) string {
+	return actionPrefix + string(decision) + ":" + id
+}
+
+// parseActionValue reads a button value written by actionValue.
+func parseActionValue(value string) (Decision, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(value), actionPrefix)
+	if !ok {
+		return "", "", false
+	}
+	raw, id, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", "", false
+	}
+	decision := Decision(raw)
+	switch decision {
+	case DecisionApprove, DecisionAlways, DecisionDeny:
+	default:
+		return "", "", false
+	}
+	if _, err := db.ParseUUID(id); err != nil {
+		return "", "", false
+	}
+	return decision, id, true
+}
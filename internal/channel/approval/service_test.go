@@ -0,0 +1,203 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/inbound"
+	"github.com/memohai/memoh/internal/channel/outbox"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+func TestPolicyAllows(t *testing.T) {
+	policy := Policy{Allowlist: []AllowedTarget{
+		{Platform: " Telegram ", Target: " 123 "},
+		{Platform: "telegram", Target: "123"},
+	}}.normalize()
+	if policy.Mode != ModeOpen {
+		t.Fatalf("expected default mode open, got %q", policy.Mode)
+	}
+	if len(policy.Allowlist) != 1 {
+		t.Fatalf("expected duplicate entries merged, got %+v", policy.Allowlist)
+	}
+	if !policy.allows("telegram", "123") || policy.allows("telegram", "456") || policy.allows("discord", "123") {
+		t.Fatalf("unexpected allowlist matching: %+v", policy.Allowlist)
+	}
+	if err := (Policy{Mode: "strict"}).normalize().validate(); err == nil {
+		t.Fatal("expected unknown mode to fail validation")
+	}
+	if err := (Policy{Mode: ModeApproval, Allowlist: []AllowedTarget{{Platform: "telegram"}}}).normalize().validate(); err == nil {
+		t.Fatal("expected entry without target to fail validation")
+	}
+}
+
+func TestApprovalPromptActions(t *testing.T) {
+	item := Approval{
+		ID:        "7b0e0a44-6d3c-4d7e-9a55-0c9a4a1b2c3d",
+		Platform:  "telegram",
+		Target:    "-100200",
+		Message:   channel.Message{Text: "hello group"},
+		ExpiresAt: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC),
+	}
+	prompt := approvalPrompt(item, time.UTC)
+	if len(prompt.Actions) != 3 {
+		t.Fatalf("expected 3 buttons, got %+v", prompt.Actions)
+	}
+	want := []Decision{DecisionApprove, DecisionAlways, DecisionDeny}
+	for i, action := range prompt.Actions {
+		if len(action.Value) > 64 {
+			t.Fatalf("button value %q exceeds callback data limit", action.Value)
+		}
+		decision, id, ok := parseActionValue(action.Value)
+		if !ok || decision != want[i] || id != item.ID {
+			t.Fatalf("button %d: parsed %q %q %v from %q", i, decision, id, ok, action.Value)
+		}
+	}
+	for _, value := range []string{"approve", "approval:maybe:" + item.ID, "approval:approve:not-a-uuid", "other:approve:" + item.ID} {
+		if _, _, ok := parseActionValue(value); ok {
+			t.Fatalf("expected %q to be rejected", value)
+		}
+	}
+}
+
+const (
+	testBotID      = "3f0c7d2e-8b1a-4c5d-9e6f-7a8b9c0d1e2f"
+	testApprovalID = "7b0e0a44-6d3c-4d7e-9a55-0c9a4a1b2c3d"
+	testOutboxID   = "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f"
+)
+
+type fakeApprovalQueries struct {
+	approvalQueries
+
+	mu      sync.Mutex
+	decided []sqlc.DecideChannelOutboundApprovalParams
+}
+
+func (q *fakeApprovalQueries) DecideChannelOutboundApproval(_ context.Context, arg sqlc.DecideChannelOutboundApprovalParams) (sqlc.ChannelOutboundApproval, error) {
+	q.mu.Lock()
+	q.decided = append(q.decided, arg)
+	q.mu.Unlock()
+	payload, _ := json.Marshal(channel.Message{Text: "hello group"})
+	botID, _ := db.ParseUUID(testBotID)
+	return sqlc.ChannelOutboundApproval{
+		ID:          arg.ID,
+		BotID:       botID,
+		ChannelType: "telegram",
+		Target:      "-100200",
+		Payload:     payload,
+		RequestedBy: "bot",
+		Status:      arg.Status,
+		DecidedBy:   arg.DecidedBy,
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}, nil
+}
+
+func (*fakeApprovalQueries) SetChannelOutboundApprovalOutboxMessage(context.Context, sqlc.SetChannelOutboundApprovalOutboxMessageParams) error {
+	return nil
+}
+
+type fakeOwnerResolver string
+
+func (o fakeOwnerResolver) BotOwnerUserID(context.Context, string) (string, error) {
+	return string(o), nil
+}
+
+type fakeOutbox struct {
+	scheduled chan outbox.ScheduleRequest
+}
+
+func (o *fakeOutbox) Schedule(_ context.Context, botID string, _ outbox.Source, _ string, req outbox.ScheduleRequest) (outbox.Message, error) {
+	o.scheduled <- req
+	return outbox.Message{ID: testOutboxID, BotID: botID}, nil
+}
+
+func (*fakeOutbox) Location(context.Context, string) *time.Location {
+	return time.UTC
+}
+
+type fakeSender struct {
+	sent chan channel.SendRequest
+}
+
+func (s *fakeSender) Send(_ context.Context, _ string, _ channel.ChannelType, req channel.SendRequest) error {
+	s.sent <- req
+	return nil
+}
+
+type recordingProcessor struct {
+	handled chan channel.InboundMessage
+}
+
+func (p *recordingProcessor) HandleInbound(_ context.Context, _ channel.ChannelConfig, msg channel.InboundMessage, _ channel.StreamReplySender) error {
+	p.handled <- msg
+	return nil
+}
+
+func TestOwnerApprovesThroughInboundQueue(t *testing.T) {
+	queries := &fakeApprovalQueries{}
+	svc := newService(nil, queries, fakeOwnerResolver("owner-1"), nil)
+	scheduler := &fakeOutbox{scheduled: make(chan outbox.ScheduleRequest, 1)}
+	sender := &fakeSender{sent: make(chan channel.SendRequest, 1)}
+	svc.SetOutbox(scheduler)
+	svc.SetSender(sender)
+
+	processor := &recordingProcessor{handled: make(chan channel.InboundMessage, 2)}
+	manager := channel.NewManager(nil, channel.NewRegistry(), nil, processor)
+	// Stands in for the identity middleware, which resolves the presser to the owner.
+	manager.Use(func(next channel.InboundHandler) channel.InboundHandler {
+		return func(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error {
+			state := inbound.IdentityState{Identity: inbound.InboundIdentity{BotID: msg.BotID, UserID: "owner-1"}}
+			return next(inbound.WithIdentityState(ctx, state), cfg, msg)
+		}
+	})
+	manager.Use(svc.Middleware())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := manager.HandleInbound(ctx, channel.ChannelConfig{ID: "cfg-1", BotID: testBotID, ChannelType: "telegram"}, channel.InboundMessage{
+		Channel:      "telegram",
+		BotID:        testBotID,
+		Event:        channel.InboundEventAction,
+		Action:       &channel.ActionEvent{Value: actionValue(DecisionApprove, testApprovalID)},
+		ReplyTarget:  "42",
+		Conversation: channel.Conversation{ID: "42", Type: "private"},
+	})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	select {
+	case req := <-scheduler.scheduled:
+		if req.Platform != "telegram" || req.Target != "-100200" || req.Message.Text != "hello group" {
+			t.Fatalf("unexpected scheduled message: %+v", req)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the approved message to be scheduled")
+	}
+	select {
+	case req := <-sender.sent:
+		if req.Target != "42" || req.Message.Text != decisionNotice(Approval{Target: "-100200"}, DecisionApprove) {
+			t.Fatalf("unexpected confirmation: %+v", req)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the confirmation")
+	}
+	queries.mu.Lock()
+	decided := queries.decided
+	queries.mu.Unlock()
+	if len(decided) != 1 || decided[0].Status != string(StatusApproved) || decided[0].DecidedBy != "owner-1" {
+		t.Fatalf("unexpected decisions: %+v", decided)
+	}
+	select {
+	case msg := <-processor.handled:
+		t.Fatalf("approval answer reached the processor: %+v", msg)
+	default:
+	}
+}
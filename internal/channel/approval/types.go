@@ -0,0 +1,168 @@
+package approval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Mode decides what happens to messages the bot sends on its own to a conversation
+// other than the one it is replying in.
+type Mode string
+
+const (
+	// ModeOpen sends to any target. It is the default.
+	ModeOpen Mode = "open"
+	// ModeAllowlist sends only to allowlisted targets and refuses the rest.
+	ModeAllowlist Mode = "allowlist"
+	// ModeApproval holds messages to targets outside the allowlist until the owner approves them.
+	ModeApproval Mode = "approval"
+)
+
+// AllowedTarget is a conversation the bot may message without asking.
+type AllowedTarget struct {
+	Platform string `json:"platform"`
+	Target   string `json:"target"`
+	Label    string `json:"label,omitempty"`
+}
+
+// Policy is the outbound policy of a bot. Replies to the conversation the bot is
+// currently talking in are never restricted.
+type Policy struct {
+	Mode      Mode            `json:"mode"`
+	Allowlist []AllowedTarget `json:"allowlist,omitempty"`
+}
+
+func (p Policy) normalize() Policy {
+	p.Mode = Mode(strings.ToLower(strings.TrimSpace(string(p.Mode))))
+	if p.Mode == "" {
+		p.Mode = ModeOpen
+	}
+	seen := make(map[string]struct{}, len(p.Allowlist))
+	items := make([]AllowedTarget, 0, len(p.Allowlist))
+	for _, item := range p.Allowlist {
+		item.Platform = strings.ToLower(strings.TrimSpace(item.Platform))
+		item.Target = strings.TrimSpace(item.Target)
+		item.Label = strings.TrimSpace(item.Label)
+		key := item.Platform + "\x00" + item.Target
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		items = append(items, item)
+	}
+	p.Allowlist = items
+	return p
+}
+
+func (p Policy) validate() error {
+	switch p.Mode {
+	case ModeOpen, ModeAllowlist, ModeApproval:
+	default:
+		return fmt.Errorf("unknown mode %q", p.Mode)
+	}
+	for _, item := range p.Allowlist {
+		if item.Platform == "" || item.Target == "" {
+			return fmt.Errorf("allowlist entries need a platform and a target")
+		}
+	}
+	return nil
+}
+
+// allows reports whether a target is on the allowlist.
+func (p Policy) allows(platform, target string) bool {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	target = strings.TrimSpace(target)
+	for _, item := range p.Allowlist {
+		if item.Platform == platform && item.Target == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is the state of a held message.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusDenied   Status = "denied"
+	// StatusExpired marks a held message the owner did not answer in time. It is
+	// derived from expires_at and never stored.
+	StatusExpired Status = "expired"
+)
+
+// Decision is the owner's answer to a held message.
+type Decision string
+
+const (
+	DecisionApprove Decision = "approve"
+	// DecisionAlways approves the message and adds its target to the allowlist.
+	DecisionAlways Decision = "always"
+	DecisionDeny   Decision = "deny"
+)
+
+// Approval is a message held until the owner approves or denies it.
+type Approval struct {
+	ID              string          `json:"id"`
+	BotID           string          `json:"bot_id"`
+	Platform        string          `json:"platform"`
+	Target          string          `json:"target"`
+	Message         channel.Message `json:"message"`
+	SendAt          *time.Time      `json:"send_at,omitempty"`
+	RequestedBy     string          `json:"requested_by,omitempty"`
+	Status          Status          `json:"status"`
+	DecidedBy       string          `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time      `json:"decided_at,omitempty"`
+	OutboxMessageID string          `json:"outbox_message_id,omitempty"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// DecideRequest answers a held message.
+type DecideRequest struct {
+	Decision Decision `json:"decision"`
+}
+
+// ListResponse wraps a list of held messages.
+type ListResponse struct {
+	Items []Approval `json:"items"`
+}
+
+// Request is a message the bot wants to send outside its current conversation.
+// SendAt and Delay follow outbox.ScheduleRequest.
+type Request struct {
+	BotID       string
+	Platform    string
+	Target      string
+	Message     channel.Message
+	SendAt      string
+	Delay       string
+	RequestedBy string
+}
+
+// Outcome is the result of checking a request against the policy.
+type Outcome string
+
+const (
+	// OutcomeAllowed lets the caller send the message itself.
+	OutcomeAllowed Outcome = "allowed"
+	// OutcomeDenied refuses the message.
+	OutcomeDenied Outcome = "denied"
+	// OutcomePending holds the message; it is delivered later if the owner approves.
+	OutcomePending Outcome = "pending"
+	// OutcomeApproved means the owner approved while the caller waited and the message
+	// was handed to the outbox for delivery.
+	OutcomeApproved Outcome = "approved"
+)
+
+// Result describes what happened to a request.
+type Result struct {
+	Outcome         Outcome
+	Reason          string
+	ApprovalID      string
+	OutboxMessageID string
+}
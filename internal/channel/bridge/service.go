@@ -0,0 +1,630 @@
+// Package bridge relays user messages between channel routes a bot owner has linked,
+// so one group spread across platforms sees the same conversation.
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+var (
+	// ErrInvalidBridge is returned when a create or update request fails validation.
+	ErrInvalidBridge = errors.New("invalid bridge")
+	// ErrBridgeNotFound is returned when the bridge does not exist.
+	ErrBridgeNotFound = errors.New("bridge not found")
+)
+
+// messageSender delivers relayed messages; implemented by channel.Manager.
+type messageSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// Service manages bridges and relays messages across them.
+type Service struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+	sender  messageSender
+	logger  *slog.Logger
+
+	// relaying holds the source messages being relayed by this process, so a retried
+	// inbound job does not race the relay it started before.
+	relayMu  sync.Mutex
+	relaying map[string]struct{}
+}
+
+// NewService creates a bridge service.
+func NewService(log *slog.Logger, pool *pgxpool.Pool, queries *sqlc.Queries) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		pool:     pool,
+		queries:  queries,
+		logger:   log.With(slog.String("service", "bridge")),
+		relaying: map[string]struct{}{},
+	}
+}
+
+// SetSender configures outbound delivery. It is set after construction because the
+// channel manager itself depends on the inbound processor that calls Relay.
+func (s *Service) SetSender(sender messageSender) {
+	if s == nil {
+		return
+	}
+	s.sender = sender
+}
+
+// Create links routes of a bot into a new bridge.
+func (s *Service) Create(ctx context.Context, botID string, req CreateRequest) (Bridge, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Bridge{}, err
+	}
+	if err := s.validateMembers(ctx, pgBotID, req.Routes); err != nil {
+		return Bridge{}, err
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return Bridge{}, fmt.Errorf("begin bridge tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	row, err := qtx.CreateChannelBridge(ctx, sqlc.CreateChannelBridgeParams{
+		BotID:   pgBotID,
+		Name:    strings.TrimSpace(req.Name),
+		Enabled: enabled,
+	})
+	if err != nil {
+		return Bridge{}, err
+	}
+	if err := addMembers(ctx, qtx, row.ID, req.Routes); err != nil {
+		return Bridge{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Bridge{}, fmt.Errorf("commit bridge tx: %w", err)
+	}
+	return s.Get(ctx, row.ID.String())
+}
+
+// Get returns a bridge with its members.
+func (s *Service) Get(ctx context.Context, id string) (Bridge, error) {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Bridge{}, err
+	}
+	row, err := s.queries.GetChannelBridgeByID(ctx, pgID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Bridge{}, ErrBridgeNotFound
+		}
+		return Bridge{}, err
+	}
+	return s.withMembers(ctx, row)
+}
+
+// List returns the bridges of a bot.
+func (s *Service) List(ctx context.Context, botID string) ([]Bridge, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListChannelBridgesByBot(ctx, pgBotID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Bridge, 0, len(rows))
+	for _, row := range rows {
+		item, err := s.withMembers(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Update renames, enables or disables a bridge, and replaces its members when Routes is set.
+func (s *Service) Update(ctx context.Context, id string, req UpdateRequest) (Bridge, error) {
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return Bridge{}, err
+	}
+	pgID, err := db.ParseUUID(current.ID)
+	if err != nil {
+		return Bridge{}, err
+	}
+	if req.Routes != nil {
+		pgBotID, err := db.ParseUUID(current.BotID)
+		if err != nil {
+			return Bridge{}, err
+		}
+		if err := s.validateMembers(ctx, pgBotID, req.Routes); err != nil {
+			return Bridge{}, err
+		}
+	}
+	name := current.Name
+	if req.Name != nil {
+		name = strings.TrimSpace(*req.Name)
+	}
+	enabled := current.Enabled
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return Bridge{}, fmt.Errorf("begin bridge tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := qtx.UpdateChannelBridge(ctx, sqlc.UpdateChannelBridgeParams{
+		Name:    name,
+		Enabled: enabled,
+		ID:      pgID,
+	}); err != nil {
+		return Bridge{}, err
+	}
+	if req.Routes != nil {
+		if err := qtx.DeleteChannelBridgeRoutes(ctx, pgID); err != nil {
+			return Bridge{}, err
+		}
+		if err := addMembers(ctx, qtx, pgID, req.Routes); err != nil {
+			return Bridge{}, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Bridge{}, fmt.Errorf("commit bridge tx: %w", err)
+	}
+	return s.Get(ctx, current.ID)
+}
+
+// Delete removes a bridge. Relayed messages already delivered are left in place.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return err
+	}
+	return s.queries.DeleteChannelBridge(ctx, pgID)
+}
+
+func (s *Service) validateMembers(ctx context.Context, pgBotID pgtype.UUID, members []MemberRequest) error {
+	seen := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		routeID := strings.TrimSpace(member.RouteID)
+		if routeID == "" {
+			return fmt.Errorf("%w: route_id is required", ErrInvalidBridge)
+		}
+		pgRouteID, err := db.ParseUUID(routeID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBridge, err)
+		}
+		key := pgRouteID.String()
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("%w: route %s listed twice", ErrInvalidBridge, routeID)
+		}
+		seen[key] = struct{}{}
+		route, err := s.queries.GetChatRouteByID(ctx, pgRouteID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: route %s not found", ErrInvalidBridge, routeID)
+			}
+			return err
+		}
+		if route.BotID != pgBotID {
+			return fmt.Errorf("%w: route %s belongs to another bot", ErrInvalidBridge, routeID)
+		}
+		for _, filter := range []Filter{member.OutgoingFilter, member.IncomingFilter} {
+			if err := filter.validate(); err != nil {
+				return fmt.Errorf("%w: route %s: %v", ErrInvalidBridge, routeID, err)
+			}
+		}
+	}
+	if len(seen) < 2 {
+		return fmt.Errorf("%w: a bridge links at least two routes", ErrInvalidBridge)
+	}
+	return nil
+}
+
+func addMembers(ctx context.Context, q *sqlc.Queries, bridgeID pgtype.UUID, members []MemberRequest) error {
+	for _, member := range members {
+		pgRouteID, err := db.ParseUUID(member.RouteID)
+		if err != nil {
+			return err
+		}
+		outgoing, err := json.Marshal(member.OutgoingFilter)
+		if err != nil {
+			return err
+		}
+		incoming, err := json.Marshal(member.IncomingFilter)
+		if err != nil {
+			return err
+		}
+		if err := q.AddChannelBridgeRoute(ctx, sqlc.AddChannelBridgeRouteParams{
+			BridgeID:       bridgeID,
+			RouteID:        pgRouteID,
+			RelayOutgoing:  member.RelayOutgoing == nil || *member.RelayOutgoing,
+			RelayIncoming:  member.RelayIncoming == nil || *member.RelayIncoming,
+			OutgoingFilter: outgoing,
+			IncomingFilter: incoming,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) withMembers(ctx context.Context, row sqlc.ChannelBridge) (Bridge, error) {
+	members, err := s.listMembers(ctx, row.ID)
+	if err != nil {
+		return Bridge{}, err
+	}
+	return Bridge{
+		ID:        row.ID.String(),
+		BotID:     row.BotID.String(),
+		Name:      row.Name,
+		Enabled:   row.Enabled,
+		Routes:    members,
+		CreatedAt: db.TimeFromPg(row.CreatedAt),
+		UpdatedAt: db.TimeFromPg(row.UpdatedAt),
+	}, nil
+}
+
+func (s *Service) listMembers(ctx context.Context, bridgeID pgtype.UUID) ([]Member, error) {
+	rows, err := s.queries.ListChannelBridgeRoutes(ctx, bridgeID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]Member, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, toMember(row))
+	}
+	return members, nil
+}
+
+func toMember(row sqlc.ListChannelBridgeRoutesRow) Member {
+	member := Member{
+		RouteID:        row.RouteID.String(),
+		Platform:       row.Platform,
+		ConversationID: row.ConversationID,
+		ThreadID:       db.TextToString(row.ThreadID),
+		ReplyTarget:    db.TextToString(row.ReplyTarget),
+		RelayOutgoing:  row.RelayOutgoing,
+		RelayIncoming:  row.RelayIncoming,
+	}
+	// Filters are validated on write; a malformed one falls back to the zero filter.
+	_ = json.Unmarshal(row.OutgoingFilter, &member.OutgoingFilter)
+	_ = json.Unmarshal(row.IncomingFilter, &member.IncomingFilter)
+	return member
+}
+
+// Relay posts a user message received on a route to every other route bridged with it.
+// Messages from bots and relayed copies echoed back by a platform are never relayed,
+// which keeps bridges from looping. Replies are mapped to the copy of the replied-to
+// message in each target route. Relaying the same message again only delivers to
+// routes that have no recorded copy yet, so retried inbound jobs do not post twice.
+func (s *Service) Relay(ctx context.Context, in RelayInput) error {
+	if s == nil || s.queries == nil || s.sender == nil {
+		return nil
+	}
+	messageID := strings.TrimSpace(in.Message.Message.ID)
+	if messageID == "" || isBotSender(in.Message.Sender) {
+		return nil
+	}
+	pgRouteID, err := db.ParseUUID(in.RouteID)
+	if err != nil {
+		return err
+	}
+	bridgeIDs, err := s.queries.ListEnabledChannelBridgeIDsByRoute(ctx, pgRouteID)
+	if err != nil {
+		return fmt.Errorf("list bridges: %w", err)
+	}
+	if len(bridgeIDs) == 0 {
+		return nil
+	}
+	if _, err := s.queries.GetChannelBridgeMessageByTarget(ctx, sqlc.GetChannelBridgeMessageByTargetParams{
+		TargetRouteID:   pgRouteID,
+		TargetMessageID: messageID,
+	}); err == nil {
+		return nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("lookup relayed message: %w", err)
+	}
+	sourceRouteID := pgRouteID.String()
+	release, ok := s.claimRelay(sourceRouteID, messageID)
+	if !ok {
+		return nil
+	}
+	defer release()
+	origin := s.replyOrigin(ctx, pgRouteID, in.Message.Message.Reply)
+
+	delivered := map[string]struct{}{sourceRouteID: {}}
+	var errs []error
+	for _, bridgeID := range bridgeIDs {
+		members, err := s.listMembers(ctx, bridgeID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		source, ok := findMember(members, sourceRouteID)
+		if !ok || !source.RelayOutgoing {
+			continue
+		}
+		for _, target := range members {
+			if _, done := delivered[target.RouteID]; done || !target.RelayIncoming {
+				continue
+			}
+			msg, ok := buildRelayMessage(in, source, target)
+			if !ok {
+				continue
+			}
+			delivered[target.RouteID] = struct{}{}
+			relayed, err := s.hasCopy(ctx, pgRouteID, messageID, target)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("relay to route %s: %w", target.RouteID, err))
+				continue
+			}
+			if relayed {
+				continue
+			}
+			msg.Reply = s.mapReply(ctx, origin, target)
+			if err := s.deliver(ctx, in.BotID, bridgeID, pgRouteID, messageID, target, msg); err != nil {
+				errs = append(errs, fmt.Errorf("relay to route %s: %w", target.RouteID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// claimRelay marks a source message as being relayed. It returns false when another
+// relay of the same message is still running.
+func (s *Service) claimRelay(routeID, messageID string) (func(), bool) {
+	key := routeID + "/" + messageID
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+	if s.relaying == nil {
+		s.relaying = map[string]struct{}{}
+	}
+	if _, busy := s.relaying[key]; busy {
+		return nil, false
+	}
+	s.relaying[key] = struct{}{}
+	return func() {
+		s.relayMu.Lock()
+		delete(s.relaying, key)
+		s.relayMu.Unlock()
+	}, true
+}
+
+// hasCopy reports whether a copy of the source message was already delivered to target.
+func (s *Service) hasCopy(ctx context.Context, sourceRouteID pgtype.UUID, sourceMessageID string, target Member) (bool, error) {
+	pgTargetRouteID, err := db.ParseUUID(target.RouteID)
+	if err != nil {
+		return false, err
+	}
+	_, err = s.queries.GetChannelBridgeMessageCopy(ctx, sqlc.GetChannelBridgeMessageCopyParams{
+		SourceRouteID:   sourceRouteID,
+		SourceMessageID: sourceMessageID,
+		TargetRouteID:   pgTargetRouteID,
+	})
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return false, fmt.Errorf("lookup relayed copy: %w", err)
+}
+
+func (s *Service) deliver(ctx context.Context, botID string, bridgeID, sourceRouteID pgtype.UUID, sourceMessageID string, target Member, msg channel.Message) error {
+	recorder := channel.NewSentMessageRecorder()
+	err := s.sender.Send(channel.WithSentMessageRecorder(ctx, recorder), botID, channel.ChannelType(target.Platform), channel.SendRequest{
+		Target:  targetAddress(target),
+		Message: msg,
+	})
+	// Record whatever was delivered, even after a partial failure, so echoes are still recognised.
+	pgTargetRouteID, parseErr := db.ParseUUID(target.RouteID)
+	if parseErr != nil {
+		return errors.Join(err, parseErr)
+	}
+	for _, id := range recorder.MessageIDs() {
+		if recordErr := s.queries.CreateChannelBridgeMessage(ctx, sqlc.CreateChannelBridgeMessageParams{
+			BridgeID:        bridgeID,
+			SourceRouteID:   sourceRouteID,
+			SourceMessageID: sourceMessageID,
+			TargetRouteID:   pgTargetRouteID,
+			TargetMessageID: id,
+		}); recordErr != nil {
+			s.logger.Warn("record relayed message failed", slog.String("route_id", target.RouteID), slog.Any("error", recordErr))
+		}
+	}
+	return err
+}
+
+// messageRef identifies a platform message within a route.
+type messageRef struct {
+	routeID   pgtype.UUID
+	messageID string
+}
+
+// replyOrigin resolves the message a reply points at to its original: when the
+// replied-to message is itself a relayed copy, the original is the source message.
+func (s *Service) replyOrigin(ctx context.Context, routeID pgtype.UUID, reply *channel.ReplyRef) *messageRef {
+	if reply == nil || strings.TrimSpace(reply.MessageID) == "" {
+		return nil
+	}
+	replyID := strings.TrimSpace(reply.MessageID)
+	row, err := s.queries.GetChannelBridgeMessageByTarget(ctx, sqlc.GetChannelBridgeMessageByTargetParams{
+		TargetRouteID:   routeID,
+		TargetMessageID: replyID,
+	})
+	if err == nil {
+		return &messageRef{routeID: row.SourceRouteID, messageID: row.SourceMessageID}
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Warn("resolve reply origin failed", slog.Any("error", err))
+	}
+	return &messageRef{routeID: routeID, messageID: replyID}
+}
+
+// mapReply returns the reply reference for a target route: the original message when
+// it lives there, otherwise its relayed copy. It returns nil when no counterpart exists.
+func (s *Service) mapReply(ctx context.Context, origin *messageRef, target Member) *channel.ReplyRef {
+	if origin == nil {
+		return nil
+	}
+	if origin.routeID.String() == target.RouteID {
+		return &channel.ReplyRef{Target: targetAddress(target), MessageID: origin.messageID}
+	}
+	pgTargetRouteID, err := db.ParseUUID(target.RouteID)
+	if err != nil {
+		return nil
+	}
+	copyID, err := s.queries.GetChannelBridgeMessageCopy(ctx, sqlc.GetChannelBridgeMessageCopyParams{
+		SourceRouteID:   origin.routeID,
+		SourceMessageID: origin.messageID,
+		TargetRouteID:   pgTargetRouteID,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("map reply failed", slog.String("route_id", target.RouteID), slog.Any("error", err))
+		}
+		return nil
+	}
+	return &channel.ReplyRef{Target: targetAddress(target), MessageID: copyID}
+}
+
+func findMember(members []Member, routeID string) (Member, bool) {
+	for _, member := range members {
+		if member.RouteID == routeID {
+			return member, true
+		}
+	}
+	return Member{}, false
+}
+
+func targetAddress(member Member) string {
+	if target := strings.TrimSpace(member.ReplyTarget); target != "" {
+		return target
+	}
+	return strings.TrimSpace(member.ConversationID)
+}
+
+func isBotSender(sender channel.Identity) bool {
+	return strings.EqualFold(sender.Attribute("is_bot"), "true")
+}
+
+// buildRelayMessage renders a message for one target route, applying the source's
+// outgoing and the target's incoming filters. It reports false when nothing is left to send.
+func buildRelayMessage(in RelayInput, source, target Member) (channel.Message, bool) {
+	text := strings.TrimSpace(in.Message.Message.PlainText())
+	senderID := strings.TrimSpace(in.Message.Sender.SubjectID)
+	if !source.OutgoingFilter.allows(senderID, text) || !target.IncomingFilter.allows(senderID, text) {
+		return channel.Message{}, false
+	}
+	var attachments []channel.Attachment
+	if !source.OutgoingFilter.TextOnly && !target.IncomingFilter.TextOnly {
+		attachments = portableAttachments(in.Attachments, in.BotID, target.Platform)
+	}
+	if text == "" && len(attachments) == 0 {
+		return channel.Message{}, false
+	}
+	prefix := fmt.Sprintf("[%s] %s", source.Platform, senderName(in.Message.Sender))
+	body := prefix
+	if text != "" {
+		body = prefix + ": " + text
+	}
+	msg := channel.Message{
+		Format:      channel.MessageFormatPlain,
+		Text:        body,
+		Attachments: attachments,
+	}
+	if threadID := strings.TrimSpace(target.ThreadID); threadID != "" {
+		msg.Thread = &channel.ThreadRef{ID: threadID}
+	}
+	return msg, true
+}
+
+func senderName(sender channel.Identity) string {
+	for _, candidate := range []string{sender.DisplayName, sender.Attribute("username"), sender.SubjectID} {
+		if name := strings.TrimSpace(candidate); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// portableAttachments keeps the attachments the target platform can deliver. Platform
+// file keys only work on the platform that issued them, so foreign keys are dropped and
+// stored media is used instead.
+func portableAttachments(attachments []channel.Attachment, botID, platform string) []channel.Attachment {
+	result := make([]channel.Attachment, 0, len(attachments))
+	for _, att := range attachments {
+		if !strings.EqualFold(strings.TrimSpace(att.SourcePlatform), platform) {
+			att.PlatformKey = ""
+			att.SourcePlatform = ""
+		}
+		if strings.TrimSpace(att.ContentHash) != "" {
+			metadata := make(map[string]any, len(att.Metadata)+1)
+			for k, v := range att.Metadata {
+				metadata[k] = v
+			}
+			if _, ok := metadata["bot_id"]; !ok {
+				metadata["bot_id"] = botID
+			}
+			att.Metadata = metadata
+		}
+		if strings.TrimSpace(att.ContentHash) == "" && !att.HasReference() && strings.TrimSpace(att.Base64) == "" {
+			continue
+		}
+		result = append(result, att)
+	}
+	return result
+}
+
+func (f Filter) allows(senderID, text string) bool {
+	if f.SkipCommands && strings.HasPrefix(text, "/") {
+		return false
+	}
+	for _, excluded := range f.ExcludeSenders {
+		if strings.TrimSpace(excluded) != "" && strings.EqualFold(strings.TrimSpace(excluded), senderID) {
+			return false
+		}
+	}
+	if pattern := strings.TrimSpace(f.Pattern); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(text) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f Filter) validate() error {
+	if pattern := strings.TrimSpace(f.Pattern); pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package bridge
+
+import (
+	"testing"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func relayInput(text string, attachments ...channel.Attachment) RelayInput {
+	return RelayInput{
+		BotID:   "bot-1",
+		RouteID: "route-tg",
+		Message: channel.InboundMessage{
+			Channel: channel.ChannelType("telegram"),
+			Message: channel.Message{ID: "42", Text: text},
+			Sender:  channel.Identity{SubjectID: "1001", DisplayName: "alice"},
+		},
+		Attachments: attachments,
+	}
+}
+
+func TestBuildRelayMessage(t *testing.T) {
+	source := Member{RouteID: "route-tg", Platform: "telegram", RelayOutgoing: true, RelayIncoming: true}
+	target := Member{RouteID: "route-dc", Platform: "discord", ThreadID: "thread-9", RelayOutgoing: true, RelayIncoming: true}
+
+	msg, ok := buildRelayMessage(relayInput("hello all"), source, target)
+	if !ok {
+		t.Fatalf("expected message to be relayed")
+	}
+	if msg.Text != "[telegram] alice: hello all" {
+		t.Fatalf("unexpected text %q", msg.Text)
+	}
+	if msg.Thread == nil || msg.Thread.ID != "thread-9" {
+		t.Fatalf("expected target thread, got %+v", msg.Thread)
+	}
+
+	photo := channel.Attachment{Type: channel.AttachmentImage, ContentHash: "abc", PlatformKey: "file-1", SourcePlatform: "telegram"}
+	msg, ok = buildRelayMessage(relayInput("", photo), source, target)
+	if !ok {
+		t.Fatalf("expected attachment-only message to be relayed")
+	}
+	if msg.Text != "[telegram] alice" || len(msg.Attachments) != 1 {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if got := msg.Attachments[0]; got.PlatformKey != "" || got.Metadata["bot_id"] != "bot-1" {
+		t.Fatalf("expected foreign platform key dropped and bot_id set, got %+v", got)
+	}
+}
+
+func TestBuildRelayMessageFilters(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   Filter
+		target   Filter
+		input    RelayInput
+		wantSend bool
+	}{
+		{name: "no filters", input: relayInput("hi"), wantSend: true},
+		{name: "skip commands", source: Filter{SkipCommands: true}, input: relayInput("/start"), wantSend: false},
+		{name: "excluded sender", target: Filter{ExcludeSenders: []string{"1001"}}, input: relayInput("hi"), wantSend: false},
+		{name: "pattern match", source: Filter{Pattern: `(?i)dinner`}, input: relayInput("Dinner at 7?"), wantSend: true},
+		{name: "pattern miss", source: Filter{Pattern: `(?i)dinner`}, input: relayInput("ok"), wantSend: false},
+		{name: "text only drops attachment-only", target: Filter{TextOnly: true}, input: relayInput("", channel.Attachment{ContentHash: "abc"}), wantSend: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := Member{Platform: "telegram", OutgoingFilter: tc.source}
+			target := Member{Platform: "discord", IncomingFilter: tc.target}
+			if _, ok := buildRelayMessage(tc.input, source, target); ok != tc.wantSend {
+				t.Fatalf("buildRelayMessage sent = %v, want %v", ok, tc.wantSend)
+			}
+		})
+	}
+}
+
+func TestPortableAttachments(t *testing.T) {
+	attachments := []channel.Attachment{
+		{Type: channel.AttachmentFile, PlatformKey: "tg-file", SourcePlatform: "telegram"},
+		{Type: channel.AttachmentImage, URL: "https://example.com/a.png"},
+		{Type: channel.AttachmentVoice, PlatformKey: "dc-file", SourcePlatform: "discord"},
+	}
+	got := portableAttachments(attachments, "bot-1", "discord")
+	if len(got) != 2 {
+		t.Fatalf("expected 2 portable attachments, got %+v", got)
+	}
+	if got[0].URL == "" || got[1].PlatformKey != "dc-file" {
+		t.Fatalf("unexpected attachments %+v", got)
+	}
+}
+
+func TestIsBotSender(t *testing.T) {
+	if !isBotSender(channel.Identity{Attributes: map[string]string{"is_bot": "true"}}) {
+		t.Fatalf("expected bot sender")
+	}
+	if isBotSender(channel.Identity{SubjectID: "1"}) {
+		t.Fatalf("expected human sender")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	if err := (Filter{Pattern: "("}).validate(); err == nil {
+		t.Fatalf("expected invalid pattern error")
+	}
+	if err := (Filter{Pattern: `^\w+`}).validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClaimRelay(t *testing.T) {
+	s := NewService(nil, nil, nil)
+	release, ok := s.claimRelay("route-tg", "42")
+	if !ok {
+		t.Fatalf("expected first relay to be claimed")
+	}
+	if _, ok := s.claimRelay("route-tg", "42"); ok {
+		t.Fatalf("expected a running relay of the same message to be skipped")
+	}
+	if _, ok := s.claimRelay("route-tg", "43"); !ok {
+		t.Fatalf("expected other messages to relay concurrently")
+	}
+	release()
+	if _, ok := s.claimRelay("route-tg", "42"); !ok {
+		t.Fatalf("expected the message to be claimable after release")
+	}
+}
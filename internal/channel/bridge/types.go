@@ -0,0 +1,83 @@
+package bridge
+
+import (
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Filter narrows which messages cross a bridge in one direction. The zero value lets
+// every message through.
+type Filter struct {
+	// SkipCommands drops messages starting with "/", which are meant for one side's bots.
+	SkipCommands bool `json:"skip_commands,omitempty"`
+	// TextOnly drops attachments; messages left without text are not relayed.
+	TextOnly bool `json:"text_only,omitempty"`
+	// Pattern, when set, relays only messages whose text matches this regular expression.
+	Pattern string `json:"pattern,omitempty"`
+	// ExcludeSenders lists platform sender IDs whose messages are not relayed.
+	ExcludeSenders []string `json:"exclude_senders,omitempty"`
+}
+
+// Member is a route linked into a bridge. RelayOutgoing relays messages posted in the
+// route to the other members; RelayIncoming posts the other members' messages into it.
+// OutgoingFilter and IncomingFilter apply to the respective direction.
+type Member struct {
+	RouteID        string `json:"route_id"`
+	Platform       string `json:"platform"`
+	ConversationID string `json:"conversation_id"`
+	ThreadID       string `json:"thread_id,omitempty"`
+	ReplyTarget    string `json:"reply_target,omitempty"`
+	RelayOutgoing  bool   `json:"relay_outgoing"`
+	RelayIncoming  bool   `json:"relay_incoming"`
+	OutgoingFilter Filter `json:"outgoing_filter"`
+	IncomingFilter Filter `json:"incoming_filter"`
+}
+
+// Bridge links two or more routes of a bot so user messages are relayed between them.
+type Bridge struct {
+	ID        string    `json:"id"`
+	BotID     string    `json:"bot_id"`
+	Name      string    `json:"name"`
+	Enabled   bool      `json:"enabled"`
+	Routes    []Member  `json:"routes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemberRequest links a route into a bridge. Omitted relay flags default to true.
+type MemberRequest struct {
+	RouteID        string `json:"route_id"`
+	RelayOutgoing  *bool  `json:"relay_outgoing,omitempty"`
+	RelayIncoming  *bool  `json:"relay_incoming,omitempty"`
+	OutgoingFilter Filter `json:"outgoing_filter"`
+	IncomingFilter Filter `json:"incoming_filter"`
+}
+
+// CreateRequest is the input for creating a bridge.
+type CreateRequest struct {
+	Name    string          `json:"name"`
+	Enabled *bool           `json:"enabled,omitempty"`
+	Routes  []MemberRequest `json:"routes"`
+}
+
+// UpdateRequest is the input for updating a bridge. Routes, when set, replaces all members.
+type UpdateRequest struct {
+	Name    *string         `json:"name,omitempty"`
+	Enabled *bool           `json:"enabled,omitempty"`
+	Routes  []MemberRequest `json:"routes,omitempty"`
+}
+
+// ListResponse wraps a list of bridges.
+type ListResponse struct {
+	Items []Bridge `json:"items"`
+}
+
+// RelayInput is a user message received on a route, with its attachments already
+// ingested into the bot's media store.
+type RelayInput struct {
+	BotID       string
+	RouteID     string
+	Message     channel.InboundMessage
+	Attachments []channel.Attachment
+}
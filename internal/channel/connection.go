@@ -178,10 +178,7 @@ func (m *Manager) ensureConnection(ctx context.Context, cfg ChannelConfig) error
 			slog.String("config_id", cfg.ID),
 		)
 	}
-	handler := m.handleInbound
-	for i := len(m.middlewares) - 1; i >= 0; i-- {
-		handler = m.middlewares[i](handler)
-	}
+	handler := m.inboundHandler()
 	connectCtx := context.Background()
 	if ctx != nil {
 		// Decouple long-lived adapter connections from short-lived request contexts.
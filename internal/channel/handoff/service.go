@@ -0,0 +1,363 @@
+// Package handoff lets a bot owner take over a channel route and reply as the bot while
+// the bot itself stays silent on that route.
+package handoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/conversation"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/memory"
+	messagepkg "github.com/memohai/memoh/internal/message"
+)
+
+var (
+	// ErrInvalidRequest is returned when a start or reply request fails validation.
+	ErrInvalidRequest = errors.New("invalid handoff request")
+	// ErrRouteNotFound is returned when the route does not exist or belongs to another bot.
+	ErrRouteNotFound = errors.New("route not found")
+	// ErrNotActive is returned when the route has no active handoff.
+	ErrNotActive = errors.New("no active handoff")
+)
+
+const (
+	defaultDuration = time.Hour
+	maxDuration     = 7 * 24 * time.Hour
+
+	// OperatorRole is the memory role of turns a human operator wrote as the bot.
+	OperatorRole = "operator"
+	// memoryNamespace is the shared bot memory namespace the chat resolver writes to.
+	memoryNamespace = "bot"
+)
+
+// messageSender delivers operator replies; implemented by channel.Manager.
+type messageSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// messageStore persists operator replies and reads the messages they answer.
+type messageStore interface {
+	Persist(ctx context.Context, input messagepkg.PersistInput) (messagepkg.Message, error)
+	ListSince(ctx context.Context, botID string, since time.Time) ([]messagepkg.Message, error)
+}
+
+// memoryWriter feeds handed-off turns to memory extraction.
+type memoryWriter interface {
+	Add(ctx context.Context, req memory.AddRequest) (memory.SearchResponse, error)
+}
+
+// Service manages route handoffs and delivers operator replies.
+type Service struct {
+	queries  *sqlc.Queries
+	messages messageStore
+	memory   memoryWriter
+	sender   messageSender
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewService creates a handoff service.
+func NewService(log *slog.Logger, queries *sqlc.Queries, messages messageStore, memoryService memoryWriter) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		queries:  queries,
+		messages: messages,
+		memory:   memoryService,
+		now:      time.Now,
+		logger:   log.With(slog.String("service", "handoff")),
+	}
+}
+
+// SetSender configures outbound delivery. It is set after construction because the
+// channel manager depends on the inbound processor that checks handoffs.
+func (s *Service) SetSender(sender messageSender) {
+	if s == nil {
+		return
+	}
+	s.sender = sender
+}
+
+// Start takes over a route, replacing any handoff already active on it.
+func (s *Service) Start(ctx context.Context, botID, routeID, startedBy string, req StartRequest) (Handoff, error) {
+	route, err := s.route(ctx, botID, routeID)
+	if err != nil {
+		return Handoff{}, err
+	}
+	duration := defaultDuration
+	if req.DurationMinutes != 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration > maxDuration {
+		return Handoff{}, fmt.Errorf("%w: duration_minutes must be at most %d", ErrInvalidRequest, int(maxDuration/time.Minute))
+	}
+	var expiresAt pgtype.Timestamptz
+	if duration > 0 {
+		expiresAt = pgtype.Timestamptz{Time: s.now().Add(duration), Valid: true}
+	}
+	row, err := s.queries.UpsertChannelHandoff(ctx, sqlc.UpsertChannelHandoffParams{
+		RouteID:   route.ID,
+		BotID:     route.BotID,
+		StartedBy: strings.TrimSpace(startedBy),
+		Reason:    strings.TrimSpace(req.Reason),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return Handoff{}, err
+	}
+	return toHandoff(row, route.Platform), nil
+}
+
+// Get returns the active handoff of a route.
+func (s *Service) Get(ctx context.Context, botID, routeID string) (Handoff, error) {
+	route, err := s.route(ctx, botID, routeID)
+	if err != nil {
+		return Handoff{}, err
+	}
+	row, err := s.queries.GetActiveChannelHandoff(ctx, route.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Handoff{}, ErrNotActive
+		}
+		return Handoff{}, err
+	}
+	return toHandoff(row, route.Platform), nil
+}
+
+// List returns the active handoffs of a bot, newest first.
+func (s *Service) List(ctx context.Context, botID string) ([]Handoff, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListActiveChannelHandoffsByBot(ctx, pgBotID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Handoff, 0, len(rows))
+	for _, row := range rows {
+		platform := ""
+		if route, err := s.queries.GetChatRouteByID(ctx, row.RouteID); err == nil {
+			platform = route.Platform
+		}
+		items = append(items, toHandoff(row, platform))
+	}
+	return items, nil
+}
+
+// Release ends the active handoff of a route so the bot answers again.
+func (s *Service) Release(ctx context.Context, botID, routeID string) error {
+	route, err := s.route(ctx, botID, routeID)
+	if err != nil {
+		return err
+	}
+	affected, err := s.queries.DeleteActiveChannelHandoff(ctx, route.ID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotActive
+	}
+	return nil
+}
+
+// Active reports whether a route is handed off. Lookup failures count as not handed
+// off so a database hiccup never silences the bot for good.
+func (s *Service) Active(ctx context.Context, botID, routeID string) bool {
+	if s == nil || s.queries == nil {
+		return false
+	}
+	pgRouteID, err := db.ParseUUID(routeID)
+	if err != nil {
+		return false
+	}
+	row, err := s.queries.GetActiveChannelHandoff(ctx, pgRouteID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("check handoff failed", slog.String("route_id", routeID), slog.Any("error", err))
+		}
+		return false
+	}
+	return row.BotID.String() == strings.TrimSpace(botID)
+}
+
+// Reply sends a message on a handed-off route as the bot, stores it in the bot's history
+// and hands the exchange to memory extraction with the reply tagged as an operator turn.
+func (s *Service) Reply(ctx context.Context, botID, routeID, operatorID string, req ReplyRequest) (ReplyResult, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return ReplyResult{}, fmt.Errorf("%w: text is required", ErrInvalidRequest)
+	}
+	route, err := s.route(ctx, botID, routeID)
+	if err != nil {
+		return ReplyResult{}, err
+	}
+	row, err := s.queries.GetActiveChannelHandoff(ctx, route.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ReplyResult{}, ErrNotActive
+		}
+		return ReplyResult{}, err
+	}
+	if s.sender == nil {
+		return ReplyResult{}, errors.New("handoff sender not configured")
+	}
+	target := strings.TrimSpace(db.TextToString(route.ReplyTarget))
+	if target == "" {
+		target = strings.TrimSpace(route.ConversationID)
+	}
+	sentAt := s.now()
+	recorder := channel.NewSentMessageRecorder()
+	if err := s.sender.Send(channel.WithSentMessageRecorder(ctx, recorder), route.BotID.String(), channel.ChannelType(route.Platform), channel.SendRequest{
+		Target:  target,
+		Message: channel.Message{Format: channel.MessageFormatPlain, Text: text},
+	}); err != nil {
+		return ReplyResult{}, fmt.Errorf("send operator reply: %w", err)
+	}
+	result := ReplyResult{PlatformMessageIDs: recorder.MessageIDs()}
+	if stored, err := s.persistReply(ctx, route, operatorID, text, result.PlatformMessageIDs); err != nil {
+		s.logger.Warn("persist operator reply failed", slog.String("route_id", routeID), slog.Any("error", err))
+	} else {
+		result.MessageID = stored.ID
+	}
+
+	since := db.TimeFromPg(row.CreatedAt)
+	if row.LastReplyAt.Valid {
+		since = row.LastReplyAt.Time
+	}
+	if err := s.queries.SetChannelHandoffLastReply(ctx, sqlc.SetChannelHandoffLastReplyParams{
+		LastReplyAt: pgtype.Timestamptz{Time: sentAt, Valid: true},
+		RouteID:     route.ID,
+	}); err != nil {
+		s.logger.Warn("update handoff last reply failed", slog.String("route_id", routeID), slog.Any("error", err))
+	}
+	go s.storeMemory(context.WithoutCancel(ctx), route.BotID.String(), route.ID.String(), since, sentAt, text)
+	return result, nil
+}
+
+func (s *Service) persistReply(ctx context.Context, route sqlc.GetChatRouteByIDRow, operatorID, text string, platformIDs []string) (messagepkg.Message, error) {
+	if s.messages == nil {
+		return messagepkg.Message{}, errors.New("message store not configured")
+	}
+	content, err := json.Marshal(conversation.ModelMessage{
+		Role:    "assistant",
+		Content: conversation.NewTextContent(text),
+	})
+	if err != nil {
+		return messagepkg.Message{}, err
+	}
+	externalID := ""
+	if len(platformIDs) > 0 {
+		externalID = platformIDs[0]
+	}
+	return s.messages.Persist(ctx, messagepkg.PersistInput{
+		BotID:             route.BotID.String(),
+		RouteID:           route.ID.String(),
+		Platform:          route.Platform,
+		ExternalMessageID: externalID,
+		Role:              "assistant",
+		Content:           content,
+		Metadata: map[string]any{
+			"route_id":    route.ID.String(),
+			"platform":    route.Platform,
+			"handoff":     true,
+			"operator_id": strings.TrimSpace(operatorID),
+		},
+	})
+}
+
+// storeMemory hands memory extraction the user messages the operator answered, followed
+// by the operator's reply.
+func (s *Service) storeMemory(ctx context.Context, botID, routeID string, since, until time.Time, reply string) {
+	if s.memory == nil || s.messages == nil {
+		return
+	}
+	history, err := s.messages.ListSince(ctx, botID, since)
+	if err != nil {
+		s.logger.Warn("list handoff messages failed", slog.String("route_id", routeID), slog.Any("error", err))
+		return
+	}
+	msgs := operatorTranscript(history, routeID, until, reply)
+	filters := map[string]any{
+		"namespace": memoryNamespace,
+		"scopeId":   botID,
+		"bot_id":    botID,
+	}
+	if _, err := s.memory.Add(ctx, memory.AddRequest{
+		Messages: msgs,
+		BotID:    botID,
+		Filters:  filters,
+	}); err != nil {
+		s.logger.Warn("store handoff memory failed", slog.String("route_id", routeID), slog.Any("error", err))
+	}
+}
+
+// operatorTranscript collects the route's user messages sent before until, followed by
+// the operator reply tagged with OperatorRole.
+func operatorTranscript(history []messagepkg.Message, routeID string, until time.Time, reply string) []memory.Message {
+	var msgs []memory.Message
+	for _, msg := range history {
+		if msg.RouteID != routeID || msg.Role != "user" || msg.CreatedAt.After(until) {
+			continue
+		}
+		var model conversation.ModelMessage
+		if err := json.Unmarshal(msg.Content, &model); err != nil {
+			continue
+		}
+		if text := strings.TrimSpace(model.TextContent()); text != "" {
+			msgs = append(msgs, memory.Message{Role: "user", Content: text})
+		}
+	}
+	return append(msgs, memory.Message{Role: OperatorRole, Content: reply})
+}
+
+// route loads a route and checks it belongs to the bot.
+func (s *Service) route(ctx context.Context, botID, routeID string) (sqlc.GetChatRouteByIDRow, error) {
+	pgRouteID, err := db.ParseUUID(routeID)
+	if err != nil {
+		return sqlc.GetChatRouteByIDRow{}, err
+	}
+	route, err := s.queries.GetChatRouteByID(ctx, pgRouteID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.GetChatRouteByIDRow{}, ErrRouteNotFound
+		}
+		return sqlc.GetChatRouteByIDRow{}, err
+	}
+	if route.BotID.String() != strings.TrimSpace(botID) {
+		return sqlc.GetChatRouteByIDRow{}, ErrRouteNotFound
+	}
+	return route, nil
+}
+
+func toHandoff(row sqlc.ChannelHandoff, platform string) Handoff {
+	handoff := Handoff{
+		RouteID:   row.RouteID.String(),
+		BotID:     row.BotID.String(),
+		Platform:  platform,
+		StartedBy: row.StartedBy,
+		Reason:    row.Reason,
+		StartedAt: db.TimeFromPg(row.CreatedAt),
+	}
+	if row.ExpiresAt.Valid {
+		expires := row.ExpiresAt.Time
+		handoff.ExpiresAt = &expires
+	}
+	if row.LastReplyAt.Valid {
+		last := row.LastReplyAt.Time
+		handoff.LastReplyAt = &last
+	}
+	return handoff
+}
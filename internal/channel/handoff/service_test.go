@@ -0,0 +1,44 @@
+package handoff
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/conversation"
+	messagepkg "github.com/memohai/memoh/internal/message"
+)
+
+func TestOperatorTranscript(t *testing.T) {
+	base := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	content := func(role, text string) json.RawMessage {
+		data, err := json.Marshal(conversation.ModelMessage{Role: role, Content: conversation.NewTextContent(text)})
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return data
+	}
+	history := []messagepkg.Message{
+		{RouteID: "route-1", Role: "user", Content: content("user", "where is my order?"), CreatedAt: base},
+		{RouteID: "route-2", Role: "user", Content: content("user", "other chat"), CreatedAt: base},
+		{RouteID: "route-1", Role: "assistant", Content: content("assistant", "earlier reply"), CreatedAt: base.Add(time.Second)},
+		{RouteID: "route-1", Role: "user", Content: content("user", "  "), CreatedAt: base.Add(2 * time.Second)},
+		{RouteID: "route-1", Role: "user", Content: content("user", "order 42"), CreatedAt: base.Add(3 * time.Second)},
+		{RouteID: "route-1", Role: "user", Content: content("user", "too late"), CreatedAt: base.Add(time.Minute)},
+	}
+
+	got := operatorTranscript(history, "route-1", base.Add(10*time.Second), "It ships tomorrow.")
+	want := []struct{ role, content string }{
+		{"user", "where is my order?"},
+		{"user", "order 42"},
+		{OperatorRole, "It ships tomorrow."},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d messages, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Role != w.role || got[i].Content != w.content {
+			t.Fatalf("message %d: expected %s %q, got %+v", i, w.role, w.content, got[i])
+		}
+	}
+}
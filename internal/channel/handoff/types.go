@@ -0,0 +1,42 @@
+package handoff
+
+import (
+	"time"
+)
+
+// Handoff is an active human takeover of a route. While it lasts, messages on the route
+// are stored but do not trigger the bot, and the owner replies as the bot instead.
+type Handoff struct {
+	RouteID     string     `json:"route_id"`
+	BotID       string     `json:"bot_id"`
+	Platform    string     `json:"platform,omitempty"`
+	StartedBy   string     `json:"started_by,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastReplyAt *time.Time `json:"last_reply_at,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+}
+
+// StartRequest takes over a route. DurationMinutes defaults to 60; a negative value
+// starts a handoff that lasts until it is released.
+type StartRequest struct {
+	DurationMinutes int    `json:"duration_minutes,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+}
+
+// ReplyRequest is a message the operator sends on a route as the bot.
+type ReplyRequest struct {
+	Text string `json:"text"`
+}
+
+// ReplyResult describes a delivered operator reply: the stored message and the IDs the
+// platform assigned to what was sent.
+type ReplyResult struct {
+	MessageID          string   `json:"message_id,omitempty"`
+	PlatformMessageIDs []string `json:"platform_message_ids,omitempty"`
+}
+
+// ListResponse wraps a list of handoffs.
+type ListResponse struct {
+	Items []Handoff `json:"items"`
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 )
 
-type inboundTask struct {
-	ctx context.Context
-	cfg ChannelConfig
-	msg InboundMessage
-}
+const (
+	// inboundClaimErrorDelay throttles workers while the queue backend is failing.
+	inboundClaimErrorDelay = time.Second
+	// inboundLeaseRenewInterval renews claimed jobs well before their lease expires.
+	inboundLeaseRenewInterval = inboundJobLease / 3
+)
 
 // HandleInbound enqueues an inbound message for asynchronous processing by the worker pool.
+// Workers run it through the same middleware chain as messages from adapter connections.
 func (m *Manager) HandleInbound(ctx context.Context, cfg ChannelConfig, msg InboundMessage) error {
 	if m.processor == nil {
 		return fmt.Errorf("inbound processor not configured")
@@ -24,17 +28,25 @@ func (m *Manager) HandleInbound(ctx context.Context, cfg ChannelConfig, msg Inbo
 	if m.inboundCtx != nil && m.inboundCtx.Err() != nil {
 		return fmt.Errorf("inbound dispatcher stopped")
 	}
-	task := inboundTask{
-		ctx: context.WithoutCancel(ctx),
-		cfg: cfg,
-		msg: msg,
+	return m.inboundQueue.Enqueue(ctx, cfg, msg)
+}
+
+// ListInboundDeadLetters returns jobs of the bot that exhausted their retries.
+func (m *Manager) ListInboundDeadLetters(ctx context.Context, botID string, limit int) ([]InboundDeadLetter, error) {
+	store, ok := m.inboundQueue.(InboundDeadLetterStore)
+	if !ok {
+		return nil, ErrDeadLettersUnsupported
 	}
-	select {
-	case m.inboundQueue <- task:
-		return nil
-	default:
-		return fmt.Errorf("inbound queue full")
+	return store.ListDeadLetters(ctx, botID, limit)
+}
+
+// ReplayInboundDeadLetter requeues a dead-lettered job with a fresh retry budget.
+func (m *Manager) ReplayInboundDeadLetter(ctx context.Context, botID, jobID string) error {
+	store, ok := m.inboundQueue.(InboundDeadLetterStore)
+	if !ok {
+		return ErrDeadLettersUnsupported
 	}
+	return store.ReplayDeadLetter(ctx, botID, jobID)
 }
 
 func (m *Manager) handleInbound(ctx context.Context, cfg ChannelConfig, msg InboundMessage) error {
@@ -58,23 +70,116 @@ func (m *Manager) startInboundWorkers(ctx context.Context) {
 			workerCtx = context.Background()
 		}
 		m.inboundCtx, m.inboundCancel = context.WithCancel(workerCtx)
+		dispatch := newInboundDispatcher(m.inboundWorkers, func() {
+			if waker, ok := m.inboundQueue.(inboundClaimWaker); ok {
+				waker.wakeClaim()
+			}
+		})
+		m.mu.Lock()
+		m.inboundDispatch = dispatch
+		m.mu.Unlock()
+		go m.runInboundClaimer(m.inboundCtx, dispatch)
+		if renewer, ok := m.inboundQueue.(InboundLeaseRenewer); ok {
+			go m.runInboundLeaseRenewer(m.inboundCtx, dispatch, renewer)
+		}
 		for i := 0; i < m.inboundWorkers; i++ {
-			go m.runInboundWorker(m.inboundCtx)
+			go m.runInboundWorker(m.inboundCtx, dispatch)
 		}
 	})
 }
 
-func (m *Manager) runInboundWorker(ctx context.Context) {
+// runInboundClaimer pulls jobs from the queue and hands them to the dispatcher
+// in claim order. Jobs of conversations whose lane is full stay in the queue.
+func (m *Manager) runInboundClaimer(ctx context.Context, dispatch *inboundDispatcher) {
+	for dispatch.acquire(ctx) {
+		job, err := m.inboundQueue.Claim(ctx, dispatch.busy)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			dispatch.release()
+			if m.logger != nil {
+				m.logger.Error("inbound claim failed", slog.Any("error", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(inboundClaimErrorDelay):
+			}
+			continue
+		}
+		dispatch.dispatch(job)
+	}
+}
+
+// runInboundLeaseRenewer keeps the leases of claimed jobs alive while they wait
+// in a lane or run, so they are not claimed a second time.
+func (m *Manager) runInboundLeaseRenewer(ctx context.Context, dispatch *inboundDispatcher, renewer InboundLeaseRenewer) {
+	ticker := time.NewTicker(inboundLeaseRenewInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case task := <-m.inboundQueue:
-			if err := m.handleInbound(task.ctx, task.cfg, task.msg); err != nil {
-				if m.logger != nil {
-					m.logger.Error("inbound processing failed", slog.String("channel", task.msg.Channel.String()), slog.Any("error", err))
-				}
+		case <-ticker.C:
+		}
+		jobs := dispatch.claimed()
+		if len(jobs) == 0 {
+			continue
+		}
+		if err := renewer.RenewLeases(ctx, jobs); err != nil && ctx.Err() == nil && m.logger != nil {
+			m.logger.Error("inbound lease renewal failed", slog.Int("jobs", len(jobs)), slog.Any("error", err))
+		}
+	}
+}
+
+func (m *Manager) runInboundWorker(ctx context.Context, dispatch *inboundDispatcher) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case key := <-dispatch.ready:
+			if job := dispatch.next(key); job != nil {
+				m.processInboundJob(ctx, job)
+				dispatch.done(key)
+				dispatch.release()
 			}
 		}
 	}
 }
+
+// InboundRouteDepths reports queued inbound work per conversation. An empty botID
+// returns all conversations.
+func (m *Manager) InboundRouteDepths(botID string) []InboundRouteDepth {
+	m.mu.Lock()
+	dispatch := m.inboundDispatch
+	m.mu.Unlock()
+	if dispatch == nil {
+		return []InboundRouteDepth{}
+	}
+	return dispatch.depths(strings.TrimSpace(botID))
+}
+
+// processInboundJob runs one claimed job and records its outcome in the queue.
+func (m *Manager) processInboundJob(ctx context.Context, job *InboundJob) {
+	procErr := m.inboundHandler()(job.context(), job.Config, job.Message)
+	// Record the outcome even when shutdown interrupts the worker.
+	updateCtx := context.WithoutCancel(ctx)
+	var err error
+	switch {
+	case procErr == nil:
+		err = m.inboundQueue.Complete(updateCtx, job)
+	case m.inboundRetry.Exhausted(job.Attempts):
+		err = m.inboundQueue.DeadLetter(updateCtx, job, procErr)
+	default:
+		err = m.inboundQueue.Retry(updateCtx, job, procErr, time.Now().Add(m.inboundRetry.Backoff(job.Attempts)))
+	}
+	if err != nil && m.logger != nil {
+		m.logger.Error("inbound job update failed",
+			slog.String("job_id", job.ID),
+			slog.String("channel", job.Message.Channel.String()),
+			slog.Int("attempts", job.Attempts),
+			slog.Any("error", err),
+		)
+	}
+}
@@ -3,9 +3,11 @@ package inbound
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"path/filepath"
 	"regexp"
@@ -17,12 +19,15 @@ import (
 	"github.com/memohai/memoh/internal/attachment"
 	"github.com/memohai/memoh/internal/auth"
 	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/bridge"
+	"github.com/memohai/memoh/internal/channel/render"
 	"github.com/memohai/memoh/internal/channel/route"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/conversation/flow"
 	"github.com/memohai/memoh/internal/inbox"
 	"github.com/memohai/memoh/internal/media"
 	messagepkg "github.com/memohai/memoh/internal/message"
+	"github.com/memohai/memoh/internal/speech"
 )
 
 const (
@@ -50,12 +55,53 @@ type mediaIngestor interface {
 	IngestContainerFile(ctx context.Context, botID, containerPath string) (media.Asset, error)
 }
 
+// audioTranscriber converts stored voice and audio attachments to text for chat models
+// that cannot take audio input.
+type audioTranscriber interface {
+	Transcribe(ctx context.Context, botID, contentHash string) (string, error)
+	ChatModelAcceptsAudio(ctx context.Context, botID string) bool
+}
+
+// voiceSynthesizer produces spoken versions of replies for conversations with voice replies enabled.
+type voiceSynthesizer interface {
+	VoiceReplyEnabled(ctx context.Context, botID, routeID string) bool
+	Synthesize(ctx context.Context, botID, text string) (media.Asset, error)
+}
+
+// triggerClassifier decides whether a group message that matched no explicit trigger is
+// addressed to the bot. Used by the "smart" trigger mode.
+type triggerClassifier interface {
+	IsAddressed(ctx context.Context, botID, text string, names []string) (bool, error)
+}
+
+// messageBridge relays user messages to the routes bridged with the one they arrived on.
+type messageBridge interface {
+	Relay(ctx context.Context, in bridge.RelayInput) error
+}
+
+// handoffChecker reports whether a human operator has taken over a route.
+type handoffChecker interface {
+	Active(ctx context.Context, botID, routeID string) bool
+}
+
+// turnLimiter enforces per-sender daily quotas on messages the bot is about to answer.
+// When a turn is refused, the returned message (possibly empty) is sent as the reply.
+type turnLimiter interface {
+	AllowTurn(ctx context.Context, identity InboundIdentity, msg channel.InboundMessage) (channel.Message, bool)
+}
+
 // ChannelInboundProcessor routes channel inbound messages to the chat gateway.
 type ChannelInboundProcessor struct {
 	runner        flow.Runner
 	routeResolver RouteResolver
 	message       messagepkg.Writer
 	mediaService  mediaIngestor
+	transcriber   audioTranscriber
+	synthesizer   voiceSynthesizer
+	classifier    triggerClassifier
+	bridge        messageBridge
+	handoff       handoffChecker
+	turns         turnLimiter
 	inboxService  *inbox.Service
 	registry      *channel.Registry
 	logger        *slog.Logger
@@ -63,6 +109,14 @@ type ChannelInboundProcessor struct {
 	tokenTTL      time.Duration
 	identity      *IdentityResolver
 	observer      channel.StreamObserver
+
+	// Ambient trigger state: compiled policies per config revision, last ambient reply per
+	// route for cooldowns, plus clock and randomness sources replaceable in tests.
+	triggerPolicies *channel.TriggerPolicyCache
+	triggerMu       sync.Mutex
+	lastAmbient     map[string]time.Time
+	now             func() time.Time
+	random          func() float64
 }
 
 // NewChannelInboundProcessor creates a processor with channel identity-based resolution.
@@ -88,14 +142,18 @@ func NewChannelInboundProcessor(
 	}
 	identityResolver := NewIdentityResolver(log, registry, channelIdentityService, memberService, policyService, preauthService, bindService, "", "")
 	return &ChannelInboundProcessor{
-		runner:        runner,
-		routeResolver: routeResolver,
-		message:       messageWriter,
-		registry:      registry,
-		logger:        log.With(slog.String("component", "channel_router")),
-		jwtSecret:     strings.TrimSpace(jwtSecret),
-		tokenTTL:      tokenTTL,
-		identity:      identityResolver,
+		runner:          runner,
+		routeResolver:   routeResolver,
+		message:         messageWriter,
+		registry:        registry,
+		logger:          log.With(slog.String("component", "channel_router")),
+		jwtSecret:       strings.TrimSpace(jwtSecret),
+		tokenTTL:        tokenTTL,
+		identity:        identityResolver,
+		triggerPolicies: channel.NewTriggerPolicyCache(),
+		lastAmbient:     map[string]time.Time{},
+		now:             time.Now,
+		random:          rand.Float64,
 	}
 }
 
@@ -115,6 +173,55 @@ func (p *ChannelInboundProcessor) SetMediaService(mediaService mediaIngestor) {
 	p.mediaService = mediaService
 }
 
+// SetTranscriber configures speech-to-text for inbound voice and audio attachments.
+func (p *ChannelInboundProcessor) SetTranscriber(transcriber audioTranscriber) {
+	if p == nil {
+		return
+	}
+	p.transcriber = transcriber
+}
+
+// SetSynthesizer configures text-to-speech for replies to voice messages.
+func (p *ChannelInboundProcessor) SetSynthesizer(synthesizer voiceSynthesizer) {
+	if p == nil {
+		return
+	}
+	p.synthesizer = synthesizer
+}
+
+// SetTriggerClassifier configures the model that decides "smart" group triggers.
+func (p *ChannelInboundProcessor) SetTriggerClassifier(classifier triggerClassifier) {
+	if p == nil {
+		return
+	}
+	p.classifier = classifier
+}
+
+// SetBridge configures relaying of user messages across bridged routes.
+func (p *ChannelInboundProcessor) SetBridge(bridge messageBridge) {
+	if p == nil {
+		return
+	}
+	p.bridge = bridge
+}
+
+// SetHandoff configures the check that keeps the bot silent on routes a human operator
+// has taken over.
+func (p *ChannelInboundProcessor) SetHandoff(handoff handoffChecker) {
+	if p == nil {
+		return
+	}
+	p.handoff = handoff
+}
+
+// SetTurnLimiter configures the daily turn quota checked before the bot answers a message.
+func (p *ChannelInboundProcessor) SetTurnLimiter(turns turnLimiter) {
+	if p == nil {
+		return
+	}
+	p.turns = turns
+}
+
 // SetStreamObserver configures an observer that receives copies of all stream
 // events produced for non-local channels (e.g. Telegram, Feishu). This enables
 // cross-channel visibility in the WebUI without coupling adapters to the hub.
@@ -142,10 +249,17 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	if sender == nil {
 		return fmt.Errorf("reply sender not configured")
 	}
+	if msg.IsHistoryUpdate() {
+		return p.applyHistoryUpdate(ctx, cfg, msg)
+	}
 	text := buildInboundQuery(msg.Message)
+	if msg.IsAction() {
+		text = buildActionQuery(*msg.Action)
+	}
 	if p.logger != nil {
 		p.logger.Debug("inbound handle start",
 			slog.String("channel", msg.Channel.String()),
+			slog.String("event", string(msg.EventType())),
 			slog.String("message_id", strings.TrimSpace(msg.Message.ID)),
 			slog.String("query", strings.TrimSpace(text)),
 			slog.Int("attachments", len(msg.Message.Attachments)),
@@ -211,7 +325,20 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	if activeChatID == "" {
 		activeChatID = strings.TrimSpace(resolved.ChatID)
 	}
-	if !shouldTriggerAssistantResponse(msg) && !identity.ForceReply {
+	p.relayToBridges(ctx, strings.TrimSpace(identity.BotID), resolved.RouteID, msg, resolvedAttachments)
+	if p.handoff != nil && p.handoff.Active(ctx, strings.TrimSpace(identity.BotID), resolved.RouteID) {
+		if p.logger != nil {
+			p.logger.Info(
+				"inbound not triggering assistant (route handed off to operator)",
+				slog.String("channel", msg.Channel.String()),
+				slog.String("bot_id", strings.TrimSpace(identity.BotID)),
+				slog.String("route_id", strings.TrimSpace(resolved.RouteID)),
+			)
+		}
+		p.persistInboundUser(ctx, resolved.RouteID, identity, msg, text, attachments, "handoff")
+		return nil
+	}
+	if !identity.ForceReply && !p.shouldTriggerAssistantResponse(ctx, cfg, msg, strings.TrimSpace(identity.BotID), resolved.RouteID, text) {
 		if p.logger != nil {
 			p.logger.Info(
 				"inbound not triggering assistant (group trigger condition not met)",
@@ -231,6 +358,31 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		p.createInboxItem(ctx, identity, msg, text, attachments, resolved.RouteID)
 		return nil
 	}
+	if p.turns != nil {
+		if reply, ok := p.turns.AllowTurn(ctx, identity, msg); !ok {
+			if p.logger != nil {
+				p.logger.Info(
+					"inbound not triggering assistant (sender over daily turn limit)",
+					slog.String("channel", msg.Channel.String()),
+					slog.String("bot_id", strings.TrimSpace(identity.BotID)),
+					slog.String("route_id", strings.TrimSpace(resolved.RouteID)),
+				)
+			}
+			p.persistInboundUser(ctx, resolved.RouteID, identity, msg, text, attachments, "rate_limited")
+			if reply.IsEmpty() {
+				return nil
+			}
+			return sender.Send(ctx, channel.OutboundMessage{
+				Target:  strings.TrimSpace(msg.ReplyTarget),
+				Message: reply,
+			})
+		}
+	}
+	// Only messages the assistant answers are transcribed, and only for chat models without audio input.
+	if transcribed := p.transcribeInboundAudio(ctx, strings.TrimSpace(identity.BotID), text, resolvedAttachments); transcribed != text {
+		text = transcribed
+		attachments = mapChannelToChatAttachments(resolvedAttachments)
+	}
 	userMessagePersisted := p.persistInboundUser(ctx, resolved.RouteID, identity, msg, text, attachments, "active_chat")
 
 	// Issue chat token for reply routing.
@@ -268,6 +420,13 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		token = "Bearer " + chatToken
 	}
 
+	sourceMessageID := strings.TrimSpace(msg.Message.ID)
+	// Replies to a button press quote the message that carried the button.
+	replyMessageID := sourceMessageID
+	if msg.IsAction() {
+		replyMessageID = strings.TrimSpace(msg.Action.MessageID)
+	}
+
 	var desc channel.Descriptor
 	if p.registry != nil {
 		desc, _ = p.registry.GetDescriptor(msg.Channel) //nolint:errcheck // descriptor lookup is best-effort
@@ -280,7 +439,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		UserID:            identity.UserID,
 		Query:             text,
 		ReplyTarget:       strings.TrimSpace(msg.ReplyTarget),
-		SourceMessageID:   strings.TrimSpace(msg.Message.ID),
+		SourceMessageID:   replyMessageID,
 	}
 	statusNotifier := p.resolveProcessingStatusNotifier(msg.Channel)
 	statusHandle := channel.ProcessingStatusHandle{}
@@ -302,14 +461,23 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		}
 		return err
 	}
-	sourceMessageID := strings.TrimSpace(msg.Message.ID)
 	replyRef := &channel.ReplyRef{Target: target}
-	if sourceMessageID != "" {
-		replyRef.MessageID = sourceMessageID
-	}
+	if replyMessageID != "" {
+		replyRef.MessageID = replyMessageID
+	}
+	var threadRef *channel.ThreadRef
+	if threadID := extractThreadID(msg); threadID != "" {
+		threadRef = &channel.ThreadRef{ID: threadID}
+	}
+	// Adapters report the platform IDs of the reply messages they deliver so the
+	// persisted assistant message can be linked to them.
+	sentMessages := channel.NewSentMessageRecorder()
+	ctx = channel.WithSentMessageRecorder(ctx, sentMessages)
+	roundStartedAt := time.Now()
 	stream, err := sender.OpenStream(ctx, target, channel.StreamOptions{
 		Reply:           replyRef,
-		SourceMessageID: sourceMessageID,
+		Thread:          threadRef,
+		SourceMessageID: replyMessageID,
 		Metadata: map[string]any{
 			"route_id": resolved.RouteID,
 		},
@@ -494,6 +662,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 
 	outputs := flow.ExtractAssistantOutputs(finalMessages)
 	attachmentsApplied := false
+	voiceReply := p.shouldReplyWithVoice(ctx, msg, desc.Capabilities, strings.TrimSpace(identity.BotID), resolved.RouteID)
 	for _, output := range outputs {
 		outMessage := buildChannelMessage(output, desc.Capabilities)
 		if outMessage.IsEmpty() && !(len(outboundAttachments) > 0 && !attachmentsApplied) {
@@ -506,14 +675,22 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 		if isMessagingToolDuplicate(plainText, sentTexts) {
 			continue
 		}
+		if voiceReply && plainText != "" {
+			// Only the first reply is spoken; the text stays as caption, or as the
+			// whole reply when synthesis fails.
+			voiceReply = false
+			if voice, ok := p.synthesizeVoiceReply(ctx, strings.TrimSpace(identity.BotID), plainText); ok {
+				outMessage.Attachments = append([]channel.Attachment{voice}, outMessage.Attachments...)
+			}
+		}
 		if !attachmentsApplied && len(outboundAttachments) > 0 {
 			outMessage.Attachments = append(outMessage.Attachments, outboundAttachments...)
 			attachmentsApplied = true
 		}
-		if outMessage.Reply == nil && sourceMessageID != "" {
+		if outMessage.Reply == nil && replyMessageID != "" {
 			outMessage.Reply = &channel.ReplyRef{
 				Target:    target,
-				MessageID: sourceMessageID,
+				MessageID: replyMessageID,
 			}
 		}
 		if err := stream.Push(ctx, channel.StreamEvent{
@@ -527,8 +704,8 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	}
 	if !attachmentsApplied && len(outboundAttachments) > 0 {
 		attachMsg := channel.Message{Attachments: outboundAttachments}
-		if sourceMessageID != "" {
-			attachMsg.Reply = &channel.ReplyRef{Target: target, MessageID: sourceMessageID}
+		if replyMessageID != "" {
+			attachMsg.Reply = &channel.ReplyRef{Target: target, MessageID: replyMessageID}
 		}
 		if err := stream.Push(ctx, channel.StreamEvent{
 			Type:  channel.StreamEventFinal,
@@ -543,6 +720,7 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	}); err != nil {
 		return err
 	}
+	p.linkOutboundMessages(ctx, strings.TrimSpace(identity.BotID), resolved.RouteID, roundStartedAt, sentMessages)
 	if statusNotifier != nil {
 		if notifyErr := p.notifyProcessingCompleted(ctx, statusNotifier, cfg, msg, statusInfo, statusHandle); notifyErr != nil {
 			p.logProcessingStatusError("processing_completed", msg, identity, notifyErr)
@@ -551,7 +729,187 @@ func (p *ChannelInboundProcessor) HandleInbound(ctx context.Context, cfg channel
 	return nil
 }
 
-func shouldTriggerAssistantResponse(msg channel.InboundMessage) bool {
+// shouldReplyWithVoice reports whether the reply to msg should carry a voice message: the
+// user sent a voice message, the channel can deliver media and voice replies are enabled
+// for the bot or the conversation.
+func (p *ChannelInboundProcessor) shouldReplyWithVoice(ctx context.Context, msg channel.InboundMessage, capabilities channel.ChannelCapabilities, botID, routeID string) bool {
+	if p == nil || p.synthesizer == nil || botID == "" || !capabilities.Media {
+		return false
+	}
+	hasVoice := false
+	for _, att := range msg.Message.Attachments {
+		if att.Type == channel.AttachmentVoice {
+			hasVoice = true
+			break
+		}
+	}
+	if !hasVoice {
+		return false
+	}
+	return p.synthesizer.VoiceReplyEnabled(ctx, botID, routeID)
+}
+
+// synthesizeVoiceReply speaks text and returns it as a voice attachment captioned with the text.
+func (p *ChannelInboundProcessor) synthesizeVoiceReply(ctx context.Context, botID, text string) (channel.Attachment, bool) {
+	asset, err := p.synthesizer.Synthesize(ctx, botID, text)
+	if err != nil {
+		if p.logger != nil {
+			p.logger.Warn("voice reply synthesis failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+		return channel.Attachment{}, false
+	}
+	return channel.Attachment{
+		Type:        channel.AttachmentVoice,
+		ContentHash: asset.ContentHash,
+		Mime:        asset.Mime,
+		Size:        asset.SizeBytes,
+		Caption:     text,
+		Metadata: map[string]any{
+			"bot_id":      botID,
+			"storage_key": asset.StorageKey,
+		},
+	}, true
+}
+
+// linkOutboundMessages stores the platform IDs of the delivered reply on the
+// assistant message persisted for this round, so the bot can edit or delete it later.
+func (p *ChannelInboundProcessor) linkOutboundMessages(ctx context.Context, botID, routeID string, since time.Time, sent *channel.SentMessageRecorder) {
+	linker, ok := p.message.(messagepkg.OutboundLinker)
+	if !ok || strings.TrimSpace(routeID) == "" {
+		return
+	}
+	ids := sent.MessageIDs()
+	if len(ids) == 0 {
+		return
+	}
+	if err := linker.LinkOutboundMessageIDs(ctx, messagepkg.LinkOutboundInput{
+		BotID:      botID,
+		RouteID:    routeID,
+		MessageIDs: ids,
+		Since:      since,
+	}); err != nil && p.logger != nil {
+		p.logger.Warn("link outbound message ids failed", slog.String("bot_id", botID), slog.String("route_id", routeID), slog.Any("error", err))
+	}
+}
+
+// relayToBridges hands a user message to bridged routes in the background so the bot's
+// own reply is not held up by deliveries to other platforms.
+func (p *ChannelInboundProcessor) relayToBridges(ctx context.Context, botID, routeID string, msg channel.InboundMessage, attachments []channel.Attachment) {
+	if p.bridge == nil || msg.IsAction() || botID == "" || strings.TrimSpace(routeID) == "" {
+		return
+	}
+	relayCtx := context.WithoutCancel(ctx)
+	go func() {
+		if err := p.bridge.Relay(relayCtx, bridge.RelayInput{
+			BotID:       botID,
+			RouteID:     routeID,
+			Message:     msg,
+			Attachments: attachments,
+		}); err != nil && p.logger != nil {
+			p.logger.Warn("bridge relay failed", slog.String("bot_id", botID), slog.String("route_id", routeID), slog.Any("error", err))
+		}
+	}()
+}
+
+// shouldTriggerAssistantResponse applies explicit triggers first, then the channel's group
+// trigger policy.
+func (p *ChannelInboundProcessor) shouldTriggerAssistantResponse(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, botID, routeID, text string) bool {
+	if isExplicitTrigger(msg) {
+		return true
+	}
+	policy, err := p.triggerPolicies.Policy(cfg, msg.Conversation.ID)
+	if err != nil {
+		if p.logger != nil {
+			p.logger.Warn("invalid trigger policy", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+		return false
+	}
+	return p.evaluateAmbientTrigger(ctx, policy, cfg, botID, routeID, text)
+}
+
+// evaluateAmbientTrigger decides whether to answer a group message that does not address
+// the bot explicitly: quiet hours and the per-route cooldown gate it, then the policy must
+// match and the reply probability roll must pass. The cooldown is checked early to spare
+// the classifier, and claimed atomically once the message is going to be answered.
+func (p *ChannelInboundProcessor) evaluateAmbientTrigger(ctx context.Context, policy channel.TriggerPolicy, cfg channel.ChannelConfig, botID, routeID, text string) bool {
+	now := p.now()
+	if policy.QuietHours.Contains(now) {
+		return false
+	}
+	cooldownKey := strings.TrimSpace(routeID)
+	if p.inAmbientCooldown(cooldownKey, now, policy.Cooldown) {
+		return false
+	}
+	matched := policy.Mode == channel.TriggerModeAlways || policy.MatchText(text)
+	if !matched && policy.Mode == channel.TriggerModeSmart {
+		matched = p.classifyAddressed(ctx, botID, text, triggerNames(policy, cfg))
+	}
+	if !matched {
+		return false
+	}
+	if policy.ReplyProbability < 1 && p.random() >= policy.ReplyProbability {
+		return false
+	}
+	return p.claimAmbient(cooldownKey, now, policy.Cooldown)
+}
+
+// inAmbientCooldown reports whether the route answered an ambient message within cooldown.
+func (p *ChannelInboundProcessor) inAmbientCooldown(key string, now time.Time, cooldown time.Duration) bool {
+	if cooldown <= 0 || key == "" {
+		return false
+	}
+	p.triggerMu.Lock()
+	defer p.triggerMu.Unlock()
+	last, ok := p.lastAmbient[key]
+	return ok && now.Sub(last) < cooldown
+}
+
+// claimAmbient checks the route's cooldown and records an ambient reply under one lock,
+// so of two messages arriving together only one is answered.
+func (p *ChannelInboundProcessor) claimAmbient(key string, now time.Time, cooldown time.Duration) bool {
+	if key == "" {
+		return true
+	}
+	p.triggerMu.Lock()
+	defer p.triggerMu.Unlock()
+	if last, ok := p.lastAmbient[key]; ok && cooldown > 0 && now.Sub(last) < cooldown {
+		return false
+	}
+	p.lastAmbient[key] = now
+	return true
+}
+
+func (p *ChannelInboundProcessor) classifyAddressed(ctx context.Context, botID, text string, names []string) bool {
+	if p.classifier == nil || strings.TrimSpace(text) == "" {
+		return false
+	}
+	addressed, err := p.classifier.IsAddressed(ctx, botID, text, names)
+	if err != nil {
+		if p.logger != nil {
+			p.logger.Warn("trigger classifier failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+		return false
+	}
+	return addressed
+}
+
+// triggerNames lists the names the bot goes by: configured aliases plus its platform identity.
+func triggerNames(policy channel.TriggerPolicy, cfg channel.ChannelConfig) []string {
+	names := append([]string(nil), policy.Aliases...)
+	for _, key := range []string{"name", "username", "display_name"} {
+		if value, ok := cfg.SelfIdentity[key].(string); ok && strings.TrimSpace(value) != "" {
+			names = append(names, strings.TrimSpace(value))
+		}
+	}
+	return names
+}
+
+// isExplicitTrigger reports whether a message addresses the bot directly.
+func isExplicitTrigger(msg channel.InboundMessage) bool {
+	// A button press is always addressed to the bot that sent the button.
+	if msg.IsAction() {
+		return true
+	}
 	if isDirectConversationType(msg.Conversation.Type) {
 		return true
 	}
@@ -661,26 +1019,7 @@ func (p *ChannelInboundProcessor) persistInboundUser(
 	if botID == "" {
 		return false
 	}
-	var attachmentPaths []string
-	for _, att := range attachments {
-		if ap := strings.TrimSpace(att.Path); ap != "" {
-			attachmentPaths = append(attachmentPaths, ap)
-		}
-	}
-	headerifiedQuery := flow.FormatUserHeader(
-		strings.TrimSpace(msg.Message.ID),
-		strings.TrimSpace(identity.ChannelIdentityID),
-		strings.TrimSpace(identity.DisplayName),
-		msg.Channel.String(),
-		strings.TrimSpace(msg.Conversation.Type),
-		strings.TrimSpace(msg.Conversation.Name),
-		attachmentPaths,
-		query,
-	)
-	payload, err := json.Marshal(conversation.ModelMessage{
-		Role:    "user",
-		Content: conversation.NewTextContent(headerifiedQuery),
-	})
+	payload, err := buildInboundUserContent(identity, msg, query, attachments)
 	if err != nil {
 		if p.logger != nil {
 			p.logger.Warn("marshal inbound user message failed", slog.Any("error", err))
@@ -710,6 +1049,31 @@ func (p *ChannelInboundProcessor) persistInboundUser(
 	return true
 }
 
+// buildInboundUserContent renders the persisted content of an inbound user message,
+// prefixing the query with the sender header the model sees.
+func buildInboundUserContent(identity InboundIdentity, msg channel.InboundMessage, query string, attachments []conversation.ChatAttachment) (json.RawMessage, error) {
+	var attachmentPaths []string
+	for _, att := range attachments {
+		if ap := strings.TrimSpace(att.Path); ap != "" {
+			attachmentPaths = append(attachmentPaths, ap)
+		}
+	}
+	headerifiedQuery := flow.FormatUserHeader(
+		strings.TrimSpace(msg.Message.ID),
+		strings.TrimSpace(identity.ChannelIdentityID),
+		strings.TrimSpace(identity.DisplayName),
+		msg.Channel.String(),
+		strings.TrimSpace(msg.Conversation.Type),
+		strings.TrimSpace(msg.Conversation.Name),
+		attachmentPaths,
+		query,
+	)
+	return json.Marshal(conversation.ModelMessage{
+		Role:    "user",
+		Content: conversation.NewTextContent(headerifiedQuery),
+	})
+}
+
 func (p *ChannelInboundProcessor) createInboxItem(
 	ctx context.Context,
 	ident InboundIdentity,
@@ -766,10 +1130,7 @@ func (p *ChannelInboundProcessor) createInboxItem(
 func buildChannelMessage(output conversation.AssistantOutput, capabilities channel.ChannelCapabilities) channel.Message {
 	msg := channel.Message{}
 	if strings.TrimSpace(output.Content) != "" {
-		msg.Text = strings.TrimSpace(output.Content)
-		if containsMarkdown(msg.Text) && (capabilities.Markdown || capabilities.RichText) {
-			msg.Format = channel.MessageFormatMarkdown
-		}
+		msg.Text, msg.Format = formatChannelText(strings.TrimSpace(output.Content), capabilities)
 	}
 	if len(output.Parts) == 0 {
 		return msg
@@ -805,14 +1166,24 @@ func buildChannelMessage(output conversation.AssistantOutput, capabilities chann
 		textParts = append(textParts, strings.TrimSpace(contentPartText(part)))
 	}
 	if len(textParts) > 0 {
-		msg.Text = strings.Join(textParts, "\n")
-		if msg.Format == "" && containsMarkdown(msg.Text) && (capabilities.Markdown || capabilities.RichText) {
-			msg.Format = channel.MessageFormatMarkdown
-		}
+		msg.Text, msg.Format = formatChannelText(strings.Join(textParts, "\n"), capabilities)
 	}
 	return msg
 }
 
+// formatChannelText marks markdown replies for channels that render it. Adapters turn
+// marked text into their native formatting; for channels without markdown support
+// the markup is stripped here instead.
+func formatChannelText(text string, capabilities channel.ChannelCapabilities) (string, channel.MessageFormat) {
+	if !containsMarkdown(text) {
+		return text, ""
+	}
+	if capabilities.Markdown || capabilities.RichText {
+		return text, channel.MessageFormatMarkdown
+	}
+	return render.PlainText(render.Parse(text)), ""
+}
+
 func containsMarkdown(text string) bool {
 	if strings.TrimSpace(text) == "" {
 		return false
@@ -1042,6 +1413,28 @@ func buildInboundQuery(message channel.Message) string {
 	return fmt.Sprintf("[User sent %d attachments]", count)
 }
 
+// buildActionQuery describes a button press as the user turn, so the model sees which option
+// was chosen and on which of its messages.
+func buildActionQuery(action channel.ActionEvent) string {
+	value := strings.TrimSpace(action.Value)
+	label := strings.TrimSpace(action.Label)
+	var b strings.Builder
+	b.WriteString("[User selected ")
+	switch {
+	case label != "" && value != "" && label != value:
+		fmt.Fprintf(&b, "%q (value: %q)", label, value)
+	case label != "":
+		fmt.Fprintf(&b, "%q", label)
+	default:
+		fmt.Fprintf(&b, "%q", value)
+	}
+	if messageID := strings.TrimSpace(action.MessageID); messageID != "" {
+		fmt.Fprintf(&b, " on message %s", messageID)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
 func normalizeContentPartType(raw string) channel.MessagePartType {
 	switch strings.TrimSpace(strings.ToLower(raw)) {
 	case "link":
@@ -1087,6 +1480,7 @@ type sendMessageToolArgs struct {
 	Target            string           `json:"target"`
 	ChannelIdentityID string           `json:"channel_identity_id"`
 	Text              string           `json:"text"`
+	Question          string           `json:"question"`
 	Message           *channel.Message `json:"message"`
 }
 
@@ -1098,7 +1492,7 @@ func collectMessageToolContext(registry *channel.Registry, messages []conversati
 	suppressReplies := false
 	for _, msg := range messages {
 		for _, tc := range msg.ToolCalls {
-			if tc.Function.Name != "send" && tc.Function.Name != "send_message" {
+			if tc.Function.Name != "send" && tc.Function.Name != "send_message" && tc.Function.Name != "ask" {
 				continue
 			}
 			var args sendMessageToolArgs
@@ -1137,6 +1531,9 @@ func extractSendMessageText(args sendMessageToolArgs) string {
 	if strings.TrimSpace(args.Text) != "" {
 		return strings.TrimSpace(args.Text)
 	}
+	if strings.TrimSpace(args.Question) != "" {
+		return strings.TrimSpace(args.Question)
+	}
 	if args.Message == nil {
 		return ""
 	}
@@ -1357,6 +1754,74 @@ func parseRawJSON(raw json.RawMessage) any {
 	return v
 }
 
+// transcribeInboundAudio transcribes ingested voice and audio attachments, records each
+// transcript in the attachment metadata and appends it to the query text. Nothing is
+// transcribed when the bot's chat model takes audio input itself.
+func (p *ChannelInboundProcessor) transcribeInboundAudio(ctx context.Context, botID, text string, attachments []channel.Attachment) string {
+	if p == nil || p.transcriber == nil || strings.TrimSpace(botID) == "" || !hasAudioAttachment(attachments) {
+		return text
+	}
+	if p.transcriber.ChatModelAcceptsAudio(ctx, botID) {
+		return text
+	}
+	lines := make([]string, 0, 1)
+	for i := range attachments {
+		att := &attachments[i]
+		if att.Type != channel.AttachmentVoice && att.Type != channel.AttachmentAudio {
+			continue
+		}
+		if strings.TrimSpace(att.ContentHash) == "" {
+			continue
+		}
+		transcript, err := p.transcriber.Transcribe(ctx, botID, att.ContentHash)
+		if err != nil {
+			if errors.Is(err, speech.ErrNotConfigured) {
+				break
+			}
+			if p.logger != nil {
+				p.logger.Warn(
+					"inbound audio transcription failed",
+					slog.Any("error", err),
+					slog.String("bot_id", botID),
+					slog.String("attachment_type", string(att.Type)),
+					slog.String("content_hash", att.ContentHash),
+				)
+			}
+			continue
+		}
+		transcript = strings.TrimSpace(transcript)
+		if transcript == "" {
+			continue
+		}
+		if att.Metadata == nil {
+			att.Metadata = make(map[string]any)
+		}
+		att.Metadata["transcript"] = transcript
+		label := "[Audio transcript]"
+		if att.Type == channel.AttachmentVoice {
+			label = "[Voice transcript]"
+		}
+		lines = append(lines, label+" "+transcript)
+	}
+	if len(lines) == 0 {
+		return text
+	}
+	transcripts := strings.Join(lines, "\n")
+	if strings.TrimSpace(text) == "" {
+		return transcripts
+	}
+	return text + "\n" + transcripts
+}
+
+func hasAudioAttachment(attachments []channel.Attachment) bool {
+	for _, att := range attachments {
+		if att.Type == channel.AttachmentVoice || att.Type == channel.AttachmentAudio {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *ChannelInboundProcessor) ingestInboundAttachments(
 	ctx context.Context,
 	cfg channel.ChannelConfig,
@@ -11,15 +11,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/bridge"
 	"github.com/memohai/memoh/internal/channel/identities"
 	"github.com/memohai/memoh/internal/channel/route"
 	"github.com/memohai/memoh/internal/conversation"
 	"github.com/memohai/memoh/internal/media"
 	messagepkg "github.com/memohai/memoh/internal/message"
 	"github.com/memohai/memoh/internal/schedule"
+	"github.com/memohai/memoh/internal/speech"
 )
 
 type fakeChatGateway struct {
@@ -575,6 +579,366 @@ func TestChannelInboundProcessorGroupMentionTriggersReply(t *testing.T) {
 	}
 }
 
+func TestChannelInboundProcessorGroupActionTriggersReply(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-action"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-action", RouteID: "route-action"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("Approved.")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("telegram"),
+		Event:       channel.InboundEventAction,
+		Message:     channel.Message{ID: "cb-1"},
+		Action:      &channel.ActionEvent{Value: "approve", Label: "Approve", MessageID: "42"},
+		ReplyTarget: "-100123",
+		Sender:      channel.Identity{SubjectID: "user-1"},
+		Conversation: channel.Conversation{
+			ID:   "-100123",
+			Type: "supergroup",
+		},
+	}
+
+	err := processor.HandleInbound(context.Background(), cfg, msg, sender)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gateway.gotReq.Query != `[User selected "Approve" (value: "approve") on message 42]` {
+		t.Fatalf("unexpected action query: %q", gateway.gotReq.Query)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one outbound reply, got %d", len(sender.sent))
+	}
+	if reply := sender.sent[0].Message.Reply; reply == nil || reply.MessageID != "42" {
+		t.Fatalf("expected reply to quote the button message, got %+v", reply)
+	}
+}
+
+type fakeTriggerClassifier struct {
+	addressed bool
+	calls     int
+	gotNames  []string
+}
+
+func (f *fakeTriggerClassifier) IsAddressed(ctx context.Context, botID, text string, names []string) (bool, error) {
+	f.calls++
+	f.gotNames = names
+	return f.addressed, nil
+}
+
+func TestChannelInboundProcessorGroupTriggerPolicy(t *testing.T) {
+	noon := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		trigger    map[string]any
+		text       string
+		classifier *fakeTriggerClassifier
+		random     float64
+		messages   int
+		wantCalls  int
+	}{
+		{name: "no policy", text: "hello everyone", messages: 1, wantCalls: 0},
+		{name: "keyword", trigger: map[string]any{"keywords": []any{"Deploy"}}, text: "who can deploy today?", messages: 1, wantCalls: 1},
+		{name: "alias word boundary", trigger: map[string]any{"aliases": []any{"memo"}}, text: "memorable day", messages: 1, wantCalls: 0},
+		{name: "alias", trigger: map[string]any{"aliases": []any{"memo"}}, text: "hey Memo, thoughts?", messages: 1, wantCalls: 1},
+		{name: "pattern", trigger: map[string]any{"patterns": []any{`(?i)^bot[,:]`}}, text: "Bot: status", messages: 1, wantCalls: 1},
+		{name: "always", trigger: map[string]any{"mode": "always"}, text: "hello everyone", messages: 1, wantCalls: 1},
+		{name: "route override", trigger: map[string]any{"routes": map[string]any{"oc_123": map[string]any{"mode": "always"}}}, text: "hello everyone", messages: 1, wantCalls: 1},
+		{name: "smart addressed", trigger: map[string]any{"mode": "smart", "aliases": []any{"memo"}}, text: "can you summarise this?", classifier: &fakeTriggerClassifier{addressed: true}, messages: 1, wantCalls: 1},
+		{name: "smart not addressed", trigger: map[string]any{"mode": "smart"}, text: "lunch anyone?", classifier: &fakeTriggerClassifier{}, messages: 1, wantCalls: 0},
+		{name: "quiet hours", trigger: map[string]any{"mode": "always", "quiet_hours": map[string]any{"start": "11:00", "end": "13:00"}}, text: "hello", messages: 1, wantCalls: 0},
+		{name: "probability", trigger: map[string]any{"mode": "always", "reply_probability": 0.3}, text: "hello", random: 0.5, messages: 1, wantCalls: 0},
+		{name: "cooldown", trigger: map[string]any{"mode": "always", "cooldown_seconds": 60}, text: "hello", messages: 2, wantCalls: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-trigger"}}
+			memberSvc := &fakeMemberService{isMember: true}
+			chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-trigger", RouteID: "route-trigger"}}
+			gateway := &fakeChatGateway{
+				resp: conversation.ChatResponse{
+					Messages: []conversation.ModelMessage{
+						{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+					},
+				},
+			}
+			processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+			processor.now = func() time.Time { return noon }
+			processor.random = func() float64 { return tc.random }
+			if tc.classifier != nil {
+				processor.SetTriggerClassifier(tc.classifier)
+			}
+			sender := &fakeReplySender{}
+
+			cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1", Routing: map[string]any{}}
+			if tc.trigger != nil {
+				cfg.Routing[channel.RoutingTriggerKey] = tc.trigger
+			}
+			for i := 0; i < tc.messages; i++ {
+				msg := channel.InboundMessage{
+					BotID:       "bot-1",
+					Channel:     channel.ChannelType("feishu"),
+					Message:     channel.Message{ID: fmt.Sprintf("msg-%d", i), Text: tc.text},
+					ReplyTarget: "chat_id:oc_123",
+					Sender:      channel.Identity{SubjectID: "user-1"},
+					Conversation: channel.Conversation{
+						ID:   "oc_123",
+						Type: "group",
+					},
+				}
+				if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if len(sender.sent) != tc.wantCalls {
+				t.Fatalf("expected %d replies, got %d", tc.wantCalls, len(sender.sent))
+			}
+			if tc.classifier != nil {
+				if tc.classifier.calls != 1 {
+					t.Fatalf("expected classifier to be called once, got %d", tc.classifier.calls)
+				}
+				if tc.trigger["aliases"] != nil && len(tc.classifier.gotNames) == 0 {
+					t.Fatalf("expected aliases to be passed to classifier")
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateAmbientTriggerClaimsCooldownOnce(t *testing.T) {
+	processor := NewChannelInboundProcessor(slog.Default(), nil, nil, nil, &fakeChatGateway{}, nil, nil, nil, nil, nil, "", 0)
+	policy := channel.TriggerPolicy{Mode: channel.TriggerModeAlways, ReplyProbability: 1, Cooldown: time.Minute}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	answered := 0
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if processor.evaluateAmbientTrigger(context.Background(), policy, channel.ChannelConfig{}, "bot-1", "route-1", "hello") {
+				mu.Lock()
+				answered++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if answered != 1 {
+		t.Fatalf("expected one message to pass the cooldown, got %d", answered)
+	}
+}
+
+type fakeBridge struct {
+	relayed chan bridge.RelayInput
+}
+
+func (f *fakeBridge) Relay(ctx context.Context, in bridge.RelayInput) error {
+	f.relayed <- in
+	return nil
+}
+
+func TestChannelInboundProcessorRelaysGroupMessagesToBridge(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-bridge"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-bridge", RouteID: "route-bridge"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	relay := &fakeBridge{relayed: make(chan bridge.RelayInput, 1)}
+	processor.SetBridge(relay)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("feishu"),
+		Message:     channel.Message{ID: "msg-bridge", Text: "dinner at seven"},
+		ReplyTarget: "chat_id:oc_123",
+		Sender:      channel.Identity{SubjectID: "user-1", DisplayName: "alice"},
+		Conversation: channel.Conversation{
+			ID:   "oc_123",
+			Type: "group",
+		},
+	}
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case in := <-relay.relayed:
+		if in.BotID != "bot-1" || in.RouteID != "route-bridge" || in.Message.Message.ID != "msg-bridge" {
+			t.Fatalf("unexpected relay input: %+v", in)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected message to be relayed")
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("bridged group message should not trigger a reply by itself")
+	}
+}
+
+type fakeHandoff struct {
+	activeRoutes map[string]bool
+}
+
+func (f *fakeHandoff) Active(ctx context.Context, botID, routeID string) bool {
+	return f.activeRoutes[routeID]
+}
+
+func TestChannelInboundProcessorHandoffSuppressesReply(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-handoff"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-handoff", RouteID: "route-handoff"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("AI reply")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	processor.SetHandoff(&fakeHandoff{activeRoutes: map[string]bool{"route-handoff": true}})
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:       "bot-1",
+		Channel:     channel.ChannelType("feishu"),
+		Message:     channel.Message{ID: "msg-handoff", Text: "@bot where is my order?"},
+		ReplyTarget: "chat_id:oc_123",
+		Sender:      channel.Identity{SubjectID: "user-1"},
+		Conversation: channel.Conversation{
+			ID:   "oc_123",
+			Type: "group",
+		},
+		Metadata: map[string]any{
+			"is_mentioned": true,
+		},
+	}
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gateway.gotReq.Query != "" {
+		t.Fatalf("handed-off route should not trigger chat call")
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("expected no outbound reply, got %d", len(sender.sent))
+	}
+	if len(chatSvc.persistedIn) != 1 {
+		t.Fatalf("expected inbound message to be persisted, got %d", len(chatSvc.persistedIn))
+	}
+	if mode := chatSvc.persistedIn[0].Metadata["trigger_mode"]; mode != "handoff" {
+		t.Fatalf("expected trigger_mode handoff, got %v", mode)
+	}
+}
+
+func TestBuildActionQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		action channel.ActionEvent
+		want   string
+	}{
+		{channel.ActionEvent{Value: "yes", Label: "yes"}, `[User selected "yes"]`},
+		{channel.ActionEvent{Value: "opt_b", Label: "Option B", MessageID: "7"}, `[User selected "Option B" (value: "opt_b") on message 7]`},
+		{channel.ActionEvent{Label: "Open"}, `[User selected "Open"]`},
+	}
+	for _, tc := range cases {
+		if got := buildActionQuery(tc.action); got != tc.want {
+			t.Fatalf("buildActionQuery(%+v) = %q, want %q", tc.action, got, tc.want)
+		}
+	}
+}
+
+// historyChatService records history updates applied by the processor.
+type historyChatService struct {
+	fakeChatService
+	edits     []messagepkg.EditInput
+	deletes   []messagepkg.DeleteInput
+	reactions []messagepkg.ReactionInput
+}
+
+func (f *historyChatService) ApplyEdit(_ context.Context, input messagepkg.EditInput) (bool, error) {
+	f.edits = append(f.edits, input)
+	return true, nil
+}
+
+func (f *historyChatService) ApplyDelete(_ context.Context, input messagepkg.DeleteInput) (bool, error) {
+	f.deletes = append(f.deletes, input)
+	return true, nil
+}
+
+func (f *historyChatService) ApplyReaction(_ context.Context, input messagepkg.ReactionInput) (bool, error) {
+	f.reactions = append(f.reactions, input)
+	return true, nil
+}
+
+func TestChannelInboundProcessorAppliesHistoryUpdates(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-h"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &historyChatService{}
+	gateway := &fakeChatGateway{}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-1", BotID: "bot-1"}
+	base := channel.InboundMessage{
+		BotID:        "bot-1",
+		Channel:      channel.ChannelType("telegram"),
+		ReplyTarget:  "123",
+		Sender:       channel.Identity{SubjectID: "user-1", DisplayName: "User1"},
+		Conversation: channel.Conversation{ID: "123", Type: "private"},
+	}
+
+	edited := base
+	edited.Event = channel.InboundEventMessageEdited
+	edited.Message = channel.Message{ID: "10", Text: "fixed typo"}
+	deleted := base
+	deleted.Event = channel.InboundEventMessageDeleted
+	deleted.Message = channel.Message{ID: "11"}
+	reacted := base
+	reacted.Event = channel.InboundEventReactionRemoved
+	reacted.Reaction = &channel.ReactionEvent{Emoji: "👍", MessageID: "12"}
+
+	for _, msg := range []channel.InboundMessage{edited, deleted, reacted} {
+		if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+			t.Fatalf("unexpected error for %s: %v", msg.Event, err)
+		}
+	}
+	if len(chatSvc.edits) != 1 || chatSvc.edits[0].ExternalMessageID != "10" || chatSvc.edits[0].Platform != "telegram" {
+		t.Fatalf("unexpected edits: %+v", chatSvc.edits)
+	}
+	if !strings.Contains(string(chatSvc.edits[0].Content), "fixed typo") {
+		t.Fatalf("edited content must carry the new text: %s", chatSvc.edits[0].Content)
+	}
+	if len(chatSvc.deletes) != 1 || chatSvc.deletes[0].ExternalMessageID != "11" {
+		t.Fatalf("unexpected deletes: %+v", chatSvc.deletes)
+	}
+	if len(chatSvc.reactions) != 1 {
+		t.Fatalf("unexpected reactions: %+v", chatSvc.reactions)
+	}
+	if r := chatSvc.reactions[0]; r.ExternalMessageID != "12" || r.Emoji != "👍" || !r.Removed || r.ReactorID != "channelIdentity-h" {
+		t.Fatalf("unexpected reaction: %+v", r)
+	}
+	if gateway.gotReq.Query != "" || len(sender.sent) != 0 || len(chatSvc.persisted) != 0 {
+		t.Fatalf("history updates must not trigger a reply or persist new messages")
+	}
+}
+
 func TestChannelInboundProcessorPersistsAttachmentAssetRefs(t *testing.T) {
 	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-asset"}}
 	memberSvc := &fakeMemberService{isMember: true}
@@ -634,6 +998,286 @@ func TestChannelInboundProcessorPersistsAttachmentAssetRefs(t *testing.T) {
 	}
 }
 
+type fakeTranscriber struct {
+	text         string
+	err          error
+	acceptsAudio bool
+	calls        []string
+}
+
+func (f *fakeTranscriber) Transcribe(ctx context.Context, botID, contentHash string) (string, error) {
+	f.calls = append(f.calls, botID+":"+contentHash)
+	return f.text, f.err
+}
+
+func (f *fakeTranscriber) ChatModelAcceptsAudio(context.Context, string) bool {
+	return f.acceptsAudio
+}
+
+func TestChannelInboundProcessorTranscribesVoiceAttachments(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-voice"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-voice", RouteID: "route-voice"}}
+	gateway := &fakeChatGateway{
+		resp: conversation.ChatResponse{
+			Messages: []conversation.ModelMessage{
+				{Role: "assistant", Content: conversation.NewTextContent("ok")},
+			},
+		},
+	}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	transcriber := &fakeTranscriber{text: " remind me to call mom "}
+	processor.SetTranscriber(transcriber)
+	sender := &fakeReplySender{}
+
+	cfg := channel.ChannelConfig{ID: "cfg-voice", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:   "bot-1",
+		Channel: channel.ChannelType("telegram"),
+		Message: channel.Message{
+			ID: "msg-voice-1",
+			Attachments: []channel.Attachment{
+				{Type: channel.AttachmentVoice, ContentHash: "voice-1", Mime: "audio/ogg"},
+				{Type: channel.AttachmentImage, ContentHash: "image-1", Mime: "image/png"},
+			},
+		},
+		ReplyTarget:  "123",
+		Sender:       channel.Identity{SubjectID: "ext-voice"},
+		Conversation: channel.Conversation{ID: "123", Type: "p2p"},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transcriber.calls) != 1 || transcriber.calls[0] != "bot-1:voice-1" {
+		t.Fatalf("expected one transcription of voice-1, got %v", transcriber.calls)
+	}
+	if !strings.Contains(gateway.gotReq.Query, "[Voice transcript] remind me to call mom") {
+		t.Fatalf("expected transcript in query, got %q", gateway.gotReq.Query)
+	}
+	if len(gateway.gotReq.Attachments) != 2 {
+		t.Fatalf("expected two gateway attachments, got %d", len(gateway.gotReq.Attachments))
+	}
+	if got := gateway.gotReq.Attachments[0].Metadata["transcript"]; got != "remind me to call mom" {
+		t.Fatalf("expected transcript metadata, got %v", got)
+	}
+	if _, ok := gateway.gotReq.Attachments[1].Metadata["transcript"]; ok {
+		t.Fatalf("expected no transcript on image attachment")
+	}
+}
+
+func TestChannelInboundProcessorTranscriptionNotConfigured(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-voice"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-voice", RouteID: "route-voice"}}
+	gateway := &fakeChatGateway{}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	transcriber := &fakeTranscriber{err: speech.ErrNotConfigured}
+	processor.SetTranscriber(transcriber)
+
+	cfg := channel.ChannelConfig{ID: "cfg-voice", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:   "bot-1",
+		Channel: channel.ChannelType("telegram"),
+		Message: channel.Message{
+			ID: "msg-voice-2",
+			Attachments: []channel.Attachment{
+				{Type: channel.AttachmentVoice, ContentHash: "voice-1"},
+				{Type: channel.AttachmentAudio, ContentHash: "audio-1"},
+			},
+		},
+		ReplyTarget:  "123",
+		Sender:       channel.Identity{SubjectID: "ext-voice"},
+		Conversation: channel.Conversation{ID: "123", Type: "p2p"},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, &fakeReplySender{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transcriber.calls) != 1 {
+		t.Fatalf("expected transcription to stop after not configured, got %v", transcriber.calls)
+	}
+	if strings.Contains(gateway.gotReq.Query, "transcript") {
+		t.Fatalf("expected no transcript in query, got %q", gateway.gotReq.Query)
+	}
+}
+
+func TestChannelInboundProcessorSkipsTranscriptionForAudioModel(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-voice"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-voice", RouteID: "route-voice"}}
+	gateway := &fakeChatGateway{}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	transcriber := &fakeTranscriber{text: "hello", acceptsAudio: true}
+	processor.SetTranscriber(transcriber)
+
+	cfg := channel.ChannelConfig{ID: "cfg-voice", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:   "bot-1",
+		Channel: channel.ChannelType("telegram"),
+		Message: channel.Message{
+			ID:          "msg-voice-3",
+			Attachments: []channel.Attachment{{Type: channel.AttachmentVoice, ContentHash: "voice-1"}},
+		},
+		ReplyTarget:  "123",
+		Sender:       channel.Identity{SubjectID: "ext-voice"},
+		Conversation: channel.Conversation{ID: "123", Type: "p2p"},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, &fakeReplySender{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transcriber.calls) != 0 {
+		t.Fatalf("expected no transcription for an audio-capable model, got %v", transcriber.calls)
+	}
+	if len(gateway.gotReq.Attachments) != 1 {
+		t.Fatalf("expected the voice attachment to reach the model, got %d", len(gateway.gotReq.Attachments))
+	}
+}
+
+func TestChannelInboundProcessorSkipsTranscriptionForPassiveGroupMessage(t *testing.T) {
+	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-voice"}}
+	memberSvc := &fakeMemberService{isMember: true}
+	chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-voice", RouteID: "route-voice"}}
+	gateway := &fakeChatGateway{}
+	processor := NewChannelInboundProcessor(slog.Default(), nil, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+	transcriber := &fakeTranscriber{text: "hello"}
+	processor.SetTranscriber(transcriber)
+
+	cfg := channel.ChannelConfig{ID: "cfg-voice", BotID: "bot-1"}
+	msg := channel.InboundMessage{
+		BotID:   "bot-1",
+		Channel: channel.ChannelType("telegram"),
+		Message: channel.Message{
+			ID:          "msg-voice-4",
+			Attachments: []channel.Attachment{{Type: channel.AttachmentVoice, ContentHash: "voice-1"}},
+		},
+		ReplyTarget:  "-100",
+		Sender:       channel.Identity{SubjectID: "ext-voice"},
+		Conversation: channel.Conversation{ID: "-100", Type: "group"},
+	}
+
+	if err := processor.HandleInbound(context.Background(), cfg, msg, &fakeReplySender{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transcriber.calls) != 0 {
+		t.Fatalf("expected no transcription for a message the bot does not answer, got %v", transcriber.calls)
+	}
+	if len(chatSvc.persisted) != 1 {
+		t.Fatalf("expected the passive message to be persisted, got %d", len(chatSvc.persisted))
+	}
+}
+
+type fakeMediaAdapter struct{}
+
+func (a *fakeMediaAdapter) Type() channel.ChannelType {
+	return channel.ChannelType("media-test")
+}
+
+func (a *fakeMediaAdapter) Descriptor() channel.Descriptor {
+	return channel.Descriptor{
+		Type: channel.ChannelType("media-test"),
+		Capabilities: channel.ChannelCapabilities{
+			Text:        true,
+			Attachments: true,
+			Media:       true,
+		},
+	}
+}
+
+type fakeSynthesizer struct {
+	enabled bool
+	err     error
+	texts   []string
+}
+
+func (f *fakeSynthesizer) VoiceReplyEnabled(ctx context.Context, botID, routeID string) bool {
+	return f.enabled
+}
+
+func (f *fakeSynthesizer) Synthesize(ctx context.Context, botID, text string) (media.Asset, error) {
+	f.texts = append(f.texts, text)
+	if f.err != nil {
+		return media.Asset{}, f.err
+	}
+	return media.Asset{ContentHash: "voice-reply-1", BotID: botID, Mime: "audio/ogg", SizeBytes: 42, StorageKey: "bot-1/voice-reply-1.ogg"}, nil
+}
+
+func TestChannelInboundProcessorVoiceReply(t *testing.T) {
+	newMsg := func(attType channel.AttachmentType) channel.InboundMessage {
+		return channel.InboundMessage{
+			BotID:   "bot-1",
+			Channel: channel.ChannelType("media-test"),
+			Message: channel.Message{
+				ID:          "msg-voice-reply",
+				Text:        "what time is it",
+				Attachments: []channel.Attachment{{Type: attType, ContentHash: "in-1"}},
+			},
+			ReplyTarget:  "123",
+			Sender:       channel.Identity{SubjectID: "ext-voice"},
+			Conversation: channel.Conversation{ID: "123", Type: "p2p"},
+		}
+	}
+	cases := []struct {
+		name      string
+		attType   channel.AttachmentType
+		synth     *fakeSynthesizer
+		wantCalls int
+		wantVoice bool
+	}{
+		{name: "voice in, voice out", attType: channel.AttachmentVoice, synth: &fakeSynthesizer{enabled: true}, wantCalls: 1, wantVoice: true},
+		{name: "disabled", attType: channel.AttachmentVoice, synth: &fakeSynthesizer{enabled: false}, wantCalls: 0},
+		{name: "image in", attType: channel.AttachmentImage, synth: &fakeSynthesizer{enabled: true}, wantCalls: 0},
+		{name: "synthesis fails falls back to text", attType: channel.AttachmentVoice, synth: &fakeSynthesizer{enabled: true, err: errors.New("tts down")}, wantCalls: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-voice"}}
+			memberSvc := &fakeMemberService{isMember: true}
+			chatSvc := &fakeChatService{resolveResult: route.ResolveConversationResult{ChatID: "chat-voice", RouteID: "route-voice"}}
+			gateway := &fakeChatGateway{
+				resp: conversation.ChatResponse{
+					Messages: []conversation.ModelMessage{
+						{Role: "assistant", Content: conversation.NewTextContent("It is noon.")},
+					},
+				},
+			}
+			registry := channel.NewRegistry()
+			registry.MustRegister(&fakeMediaAdapter{})
+			processor := NewChannelInboundProcessor(slog.Default(), registry, chatSvc, chatSvc, gateway, channelIdentitySvc, memberSvc, nil, nil, nil, "", 0)
+			processor.SetSynthesizer(tc.synth)
+			sender := &fakeReplySender{}
+
+			if err := processor.HandleInbound(context.Background(), channel.ChannelConfig{ID: "cfg-voice", BotID: "bot-1"}, newMsg(tc.attType), sender); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tc.synth.texts) != tc.wantCalls {
+				t.Fatalf("expected %d synthesis calls, got %v", tc.wantCalls, tc.synth.texts)
+			}
+			if len(sender.sent) != 1 {
+				t.Fatalf("expected one reply, got %d", len(sender.sent))
+			}
+			reply := sender.sent[0].Message
+			if reply.PlainText() != "It is noon." {
+				t.Fatalf("expected reply text kept, got %q", reply.PlainText())
+			}
+			if !tc.wantVoice {
+				if len(reply.Attachments) != 0 {
+					t.Fatalf("expected no attachments, got %+v", reply.Attachments)
+				}
+				return
+			}
+			if len(reply.Attachments) != 1 {
+				t.Fatalf("expected one voice attachment, got %+v", reply.Attachments)
+			}
+			voice := reply.Attachments[0]
+			if voice.Type != channel.AttachmentVoice || voice.ContentHash != "voice-reply-1" || voice.Caption != "It is noon." {
+				t.Fatalf("unexpected voice attachment %+v", voice)
+			}
+		})
+	}
+}
+
 func TestChannelInboundProcessorIngestsPlatformKeyWithResolver(t *testing.T) {
 	channelIdentitySvc := &fakeChannelIdentityService{channelIdentity: identities.ChannelIdentity{ID: "channelIdentity-resolver"}}
 	memberSvc := &fakeMemberService{isMember: true}
@@ -0,0 +1,113 @@
+package inbound
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/memohai/memoh/internal/channel"
+	messagepkg "github.com/memohai/memoh/internal/message"
+)
+
+// applyHistoryUpdate applies an edit, deletion or reaction reported by a platform
+// to the persisted history. Such events never trigger an assistant reply.
+func (p *ChannelInboundProcessor) applyHistoryUpdate(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error {
+	updater, ok := p.message.(messagepkg.HistoryUpdater)
+	if !ok {
+		if p.logger != nil {
+			p.logger.Debug("inbound history update dropped (no updater)", slog.String("event", string(msg.EventType())))
+		}
+		return nil
+	}
+	botID := strings.TrimSpace(msg.BotID)
+	if botID == "" {
+		botID = strings.TrimSpace(cfg.BotID)
+	}
+	// Deletions carry no content and platforms rarely report who deleted a
+	// message, so only edits and reactions go through identity resolution.
+	var identity InboundIdentity
+	if msg.EventType() != channel.InboundEventMessageDeleted {
+		state, err := p.requireIdentity(ctx, cfg, msg)
+		if err != nil {
+			return err
+		}
+		if state.Decision != nil && state.Decision.Stop {
+			return nil
+		}
+		identity = state.Identity
+		botID = strings.TrimSpace(identity.BotID)
+	}
+	platform := msg.Channel.String()
+	receivedAt := msg.ReceivedAt
+
+	var (
+		messageID string
+		found     bool
+		err       error
+	)
+	switch msg.EventType() {
+	case channel.InboundEventMessageEdited:
+		messageID = strings.TrimSpace(msg.Message.ID)
+		text := buildInboundQuery(msg.Message)
+		if messageID == "" || strings.TrimSpace(text) == "" {
+			return nil
+		}
+		content, err := buildInboundUserContent(identity, msg, text, nil)
+		if err != nil {
+			return err
+		}
+		found, err = updater.ApplyEdit(ctx, messagepkg.EditInput{
+			BotID:             botID,
+			Platform:          platform,
+			ExternalMessageID: messageID,
+			Content:           content,
+			EditedAt:          receivedAt,
+		})
+		if err != nil {
+			return err
+		}
+	case channel.InboundEventMessageDeleted:
+		messageID = strings.TrimSpace(msg.Message.ID)
+		if messageID == "" {
+			return nil
+		}
+		found, err = updater.ApplyDelete(ctx, messagepkg.DeleteInput{
+			BotID:             botID,
+			Platform:          platform,
+			ExternalMessageID: messageID,
+			DeletedAt:         receivedAt,
+		})
+		if err != nil {
+			return err
+		}
+	case channel.InboundEventReactionAdded, channel.InboundEventReactionRemoved:
+		if msg.Reaction == nil {
+			return nil
+		}
+		messageID = strings.TrimSpace(msg.Reaction.MessageID)
+		if messageID == "" {
+			return nil
+		}
+		found, err = updater.ApplyReaction(ctx, messagepkg.ReactionInput{
+			BotID:             botID,
+			Platform:          platform,
+			ExternalMessageID: messageID,
+			Emoji:             msg.Reaction.Emoji,
+			ReactorID:         strings.TrimSpace(identity.ChannelIdentityID),
+			Removed:           msg.EventType() == channel.InboundEventReactionRemoved,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if p.logger != nil {
+		p.logger.Debug("inbound history update applied",
+			slog.String("channel", platform),
+			slog.String("bot_id", botID),
+			slog.String("event", string(msg.EventType())),
+			slog.String("message_id", messageID),
+			slog.Bool("found", found),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// inboundJobsPerWorker bounds how many claimed jobs may wait in memory per worker.
+	inboundJobsPerWorker = 8
+	// inboundJobsPerLane bounds claimed jobs held for one conversation. Further jobs
+	// stay in the queue so a burst in one conversation cannot use up every slot.
+	inboundJobsPerLane = 4
+)
+
+// InboundRouteDepth reports queued work for one conversation in the inbound dispatcher.
+type InboundRouteDepth struct {
+	RouteKey   string `json:"route_key"`
+	BotID      string `json:"bot_id"`
+	Pending    int    `json:"pending"`
+	Processing bool   `json:"processing"`
+}
+
+// inboundDispatcher serializes claimed jobs per conversation so replies and history
+// writes keep their order, while different conversations run in parallel.
+// Ordering holds within one process; jobs that are retried run after later messages.
+type inboundDispatcher struct {
+	mu    sync.Mutex
+	lanes map[string]*inboundLane
+	// ready holds keys of lanes with pending jobs that no worker is running.
+	ready chan string
+	// slots bounds claimed but unfinished jobs. Every ready lane holds at least
+	// one slot, so sends on ready never block.
+	slots chan struct{}
+	// onRoom is called when a full lane can take another job.
+	onRoom func()
+}
+
+type inboundLane struct {
+	botID   string
+	jobs    []*InboundJob
+	current *InboundJob
+}
+
+// held counts the jobs of the lane that are claimed but unfinished.
+func (l *inboundLane) held() int {
+	if l.current != nil {
+		return len(l.jobs) + 1
+	}
+	return len(l.jobs)
+}
+
+func newInboundDispatcher(workers int, onRoom func()) *inboundDispatcher {
+	capacity := workers * inboundJobsPerWorker
+	return &inboundDispatcher{
+		lanes:  map[string]*inboundLane{},
+		ready:  make(chan string, capacity),
+		slots:  make(chan struct{}, capacity),
+		onRoom: onRoom,
+	}
+}
+
+// acquire reserves room for one more claimed job.
+func (d *inboundDispatcher) acquire(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case d.slots <- struct{}{}:
+		return true
+	}
+}
+
+func (d *inboundDispatcher) release() {
+	<-d.slots
+}
+
+// dispatch appends a claimed job to its conversation lane.
+func (d *inboundDispatcher) dispatch(job *InboundJob) {
+	key := inboundOrderKey(job)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lane, ok := d.lanes[key]
+	if !ok {
+		lane = &inboundLane{botID: job.Config.BotID}
+		d.lanes[key] = lane
+	}
+	lane.jobs = append(lane.jobs, job)
+	if len(lane.jobs) == 1 && lane.current == nil {
+		d.ready <- key
+	}
+}
+
+// next pops the oldest job of a ready lane and marks the lane as running.
+func (d *inboundDispatcher) next(key string) *InboundJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lane, ok := d.lanes[key]
+	if !ok || len(lane.jobs) == 0 {
+		return nil
+	}
+	job := lane.jobs[0]
+	lane.jobs[0] = nil
+	lane.jobs = lane.jobs[1:]
+	lane.current = job
+	return job
+}
+
+// done releases the lane after a job finished. Lanes with more jobs go back to
+// the end of the ready queue so one busy conversation cannot starve others.
+func (d *inboundDispatcher) done(key string) {
+	d.mu.Lock()
+	lane, ok := d.lanes[key]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	wasFull := lane.held() >= inboundJobsPerLane
+	lane.current = nil
+	if len(lane.jobs) > 0 {
+		d.ready <- key
+	} else {
+		delete(d.lanes, key)
+	}
+	d.mu.Unlock()
+	if wasFull && d.onRoom != nil {
+		d.onRoom()
+	}
+}
+
+// busy returns the keys of conversations that hold as many jobs as a lane may.
+func (d *inboundDispatcher) busy() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	keys := []string{}
+	for key, lane := range d.lanes {
+		if lane.held() >= inboundJobsPerLane {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// claimed returns every job that is waiting in a lane or running.
+func (d *inboundDispatcher) claimed() []*InboundJob {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	jobs := []*InboundJob{}
+	for _, lane := range d.lanes {
+		if lane.current != nil {
+			jobs = append(jobs, lane.current)
+		}
+		jobs = append(jobs, lane.jobs...)
+	}
+	return jobs
+}
+
+// depths returns queued work per conversation, optionally limited to one bot.
+func (d *inboundDispatcher) depths(botID string) []InboundRouteDepth {
+	d.mu.Lock()
+	items := make([]InboundRouteDepth, 0, len(d.lanes))
+	for key, lane := range d.lanes {
+		if botID != "" && lane.botID != botID {
+			continue
+		}
+		items = append(items, InboundRouteDepth{
+			RouteKey:   key,
+			BotID:      lane.botID,
+			Pending:    len(lane.jobs),
+			Processing: lane.current != nil,
+		})
+	}
+	d.mu.Unlock()
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Pending != items[j].Pending {
+			return items[i].Pending > items[j].Pending
+		}
+		return items[i].RouteKey < items[j].RouteKey
+	})
+	return items
+}
+
+// inboundOrderKey identifies the conversation a job belongs to.
+func inboundOrderKey(job *InboundJob) string {
+	if key := inboundRouteKey(job.Config, job.Message); key != "" {
+		return key
+	}
+	// Nothing to order against; let the job run on its own.
+	return fmt.Sprintf("job:%p", job)
+}
+
+// inboundRouteKey identifies the conversation of an inbound message, or returns
+// an empty key when the message names none. Group chats are keyed without the
+// sender because they share one history; threads and forum topics get their own key.
+func inboundRouteKey(cfg ChannelConfig, msg InboundMessage) string {
+	botID := strings.TrimSpace(msg.BotID)
+	if botID == "" {
+		botID = strings.TrimSpace(cfg.BotID)
+	}
+	channelType := msg.Channel
+	if channelType == "" {
+		channelType = cfg.ChannelType
+	}
+	conversationID := strings.TrimSpace(msg.Conversation.ID)
+	if conversationID == "" {
+		conversationID = strings.TrimSpace(msg.ReplyTarget)
+	}
+	if conversationID == "" {
+		return ""
+	}
+	key := GenerateRoutingKey(channelType.String(), botID, conversationID, "", "")
+	threadID := strings.TrimSpace(msg.Conversation.ThreadID)
+	if msg.Message.Thread != nil && strings.TrimSpace(msg.Message.Thread.ID) != "" {
+		threadID = strings.TrimSpace(msg.Message.Thread.ID)
+	}
+	if threadID != "" {
+		key += ":thread:" + threadID
+	}
+	return key
+}
@@ -0,0 +1,261 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// orderingProcessor records processed messages per conversation and flags
+// concurrent processing within one conversation.
+type orderingProcessor struct {
+	mu         sync.Mutex
+	inflight   map[string]int
+	seen       map[string][]string
+	overlap    bool
+	processed  chan struct{}
+	blockConv  string
+	blocked    chan struct{}
+	unblock    chan struct{}
+	processing time.Duration
+}
+
+func (p *orderingProcessor) HandleInbound(ctx context.Context, cfg ChannelConfig, msg InboundMessage, sender StreamReplySender) error {
+	conv := msg.Conversation.ID
+	p.mu.Lock()
+	p.inflight[conv]++
+	if p.inflight[conv] > 1 {
+		p.overlap = true
+	}
+	p.mu.Unlock()
+
+	if conv == p.blockConv {
+		select {
+		case p.blocked <- struct{}{}:
+		default:
+		}
+		<-p.unblock
+	}
+	time.Sleep(p.processing)
+
+	p.mu.Lock()
+	p.inflight[conv]--
+	p.seen[conv] = append(p.seen[conv], msg.Message.ID)
+	p.mu.Unlock()
+	p.processed <- struct{}{}
+	return nil
+}
+
+func newOrderingProcessor() *orderingProcessor {
+	return &orderingProcessor{
+		inflight:  map[string]int{},
+		seen:      map[string][]string{},
+		processed: make(chan struct{}, 64),
+		blocked:   make(chan struct{}, 1),
+		unblock:   make(chan struct{}),
+	}
+}
+
+func waitProcessed(t *testing.T, p *orderingProcessor, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-p.processed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after %d of %d messages", i, n)
+		}
+	}
+}
+
+func TestManagerInboundPreservesConversationOrder(t *testing.T) {
+	t.Parallel()
+
+	processor := newOrderingProcessor()
+	processor.processing = time.Millisecond
+	manager := NewManager(nil, NewRegistry(), &fakeConfigStore{}, processor)
+	manager.SetInboundWorkers(4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "test"}
+	for i := 0; i < 10; i++ {
+		for _, conv := range []string{"a", "b"} {
+			err := manager.HandleInbound(ctx, cfg, InboundMessage{
+				Channel:      "test",
+				BotID:        "bot-1",
+				Message:      Message{ID: fmt.Sprintf("%s-%d", conv, i)},
+				Conversation: Conversation{ID: conv, Type: "group"},
+				Sender:       Identity{SubjectID: fmt.Sprintf("user-%d", i)},
+			})
+			if err != nil {
+				t.Fatalf("enqueue: %v", err)
+			}
+		}
+	}
+	waitProcessed(t, processor, 20)
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if processor.overlap {
+		t.Fatal("messages of one conversation were processed concurrently")
+	}
+	for _, conv := range []string{"a", "b"} {
+		for i, id := range processor.seen[conv] {
+			if want := fmt.Sprintf("%s-%d", conv, i); id != want {
+				t.Fatalf("conversation %s out of order: %v", conv, processor.seen[conv])
+			}
+		}
+	}
+}
+
+func TestManagerInboundRunsConversationsInParallel(t *testing.T) {
+	t.Parallel()
+
+	processor := newOrderingProcessor()
+	processor.blockConv = "slow"
+	manager := NewManager(nil, NewRegistry(), &fakeConfigStore{}, processor)
+	manager.SetInboundWorkers(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "test"}
+	for _, conv := range []string{"slow", "slow", "fast"} {
+		err := manager.HandleInbound(ctx, cfg, InboundMessage{
+			Channel:      "test",
+			BotID:        "bot-1",
+			Conversation: Conversation{ID: conv},
+		})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	select {
+	case <-processor.blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the slow conversation to start")
+	}
+	// The fast conversation completes while the slow one is still blocked.
+	waitProcessed(t, processor, 1)
+	processor.mu.Lock()
+	fastDone := len(processor.seen["fast"])
+	processor.mu.Unlock()
+	if fastDone != 1 {
+		t.Fatal("expected fast conversation to finish first")
+	}
+
+	depths := manager.InboundRouteDepths("bot-1")
+	if len(depths) != 1 || depths[0].Pending != 1 || !depths[0].Processing {
+		t.Fatalf("unexpected depths: %+v", depths)
+	}
+	if len(manager.InboundRouteDepths("bot-2")) != 0 {
+		t.Fatal("expected no depths for other bots")
+	}
+
+	close(processor.unblock)
+	waitProcessed(t, processor, 2)
+}
+
+func TestManagerInboundBurstDoesNotBlockOtherConversations(t *testing.T) {
+	t.Parallel()
+
+	processor := newOrderingProcessor()
+	processor.blockConv = "burst"
+	processor.processed = make(chan struct{}, 128)
+	manager := NewManager(nil, NewRegistry(), &fakeConfigStore{}, processor)
+	manager.SetInboundWorkers(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "test"}
+	// The burst exceeds every slot of the dispatcher; the quiet conversation comes last.
+	convs := make([]string, 0, 41)
+	for i := 0; i < 40; i++ {
+		convs = append(convs, "burst")
+	}
+	convs = append(convs, "quiet")
+	for _, conv := range convs {
+		err := manager.HandleInbound(ctx, cfg, InboundMessage{
+			Channel:      "test",
+			BotID:        "bot-1",
+			Conversation: Conversation{ID: conv},
+		})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	waitProcessed(t, processor, 1)
+	processor.mu.Lock()
+	quietDone := len(processor.seen["quiet"])
+	processor.mu.Unlock()
+	if quietDone != 1 {
+		t.Fatal("expected quiet conversation to run while the burst is blocked")
+	}
+	for _, depth := range manager.InboundRouteDepths("bot-1") {
+		held := depth.Pending
+		if depth.Processing {
+			held++
+		}
+		if depth.RouteKey == "test:bot-1:burst" && held > inboundJobsPerLane {
+			t.Fatalf("expected burst lane capped at %d jobs, got %+v", inboundJobsPerLane, depth)
+		}
+	}
+
+	close(processor.unblock)
+	waitProcessed(t, processor, 40)
+}
+
+func TestInboundDispatcherTracksClaimedJobs(t *testing.T) {
+	t.Parallel()
+
+	dispatch := newInboundDispatcher(1, nil)
+	cfg := ChannelConfig{BotID: "bot-1", ChannelType: "test"}
+	for i := 0; i < inboundJobsPerLane; i++ {
+		dispatch.dispatch(&InboundJob{ID: fmt.Sprintf("a-%d", i), Config: cfg, Message: InboundMessage{Conversation: Conversation{ID: "a"}}})
+	}
+	dispatch.dispatch(&InboundJob{ID: "b-0", Config: cfg, Message: InboundMessage{Conversation: Conversation{ID: "b"}}})
+	if busy := dispatch.busy(); len(busy) != 1 || busy[0] != "test:bot-1:a" {
+		t.Fatalf("expected full lane to be busy, got %v", busy)
+	}
+
+	key := <-dispatch.ready
+	if job := dispatch.next(key); job == nil {
+		t.Fatal("expected a job")
+	}
+	// A running job still counts toward the lane and still needs its lease renewed.
+	if got := len(dispatch.claimed()); got != inboundJobsPerLane+1 {
+		t.Fatalf("expected %d claimed jobs, got %d", inboundJobsPerLane+1, got)
+	}
+	dispatch.done(key)
+	if got := len(dispatch.claimed()); got != inboundJobsPerLane {
+		t.Fatalf("expected %d claimed jobs after done, got %d", inboundJobsPerLane, got)
+	}
+}
+
+func TestInboundOrderKey(t *testing.T) {
+	t.Parallel()
+
+	cfg := ChannelConfig{BotID: "bot-1", ChannelType: "test"}
+	a := inboundOrderKey(&InboundJob{Config: cfg, Message: InboundMessage{Conversation: Conversation{ID: "c1", Type: "group"}, Sender: Identity{SubjectID: "u1"}}})
+	b := inboundOrderKey(&InboundJob{Config: cfg, Message: InboundMessage{Conversation: Conversation{ID: "c1", Type: "group"}, Sender: Identity{SubjectID: "u2"}}})
+	if a != b || a != "test:bot-1:c1" {
+		t.Fatalf("group members must share a key: %q %q", a, b)
+	}
+	if got := inboundOrderKey(&InboundJob{Config: cfg, Message: InboundMessage{ReplyTarget: "t1"}}); got != "test:bot-1:t1" {
+		t.Fatalf("expected reply target fallback, got %q", got)
+	}
+	topic := inboundOrderKey(&InboundJob{Config: cfg, Message: InboundMessage{Conversation: Conversation{ID: "c1", Type: "group", ThreadID: "77"}}})
+	if topic != "test:bot-1:c1:thread:77" {
+		t.Fatalf("expected threads to get their own key, got %q", topic)
+	}
+	reply := inboundOrderKey(&InboundJob{Config: cfg, Message: InboundMessage{Conversation: Conversation{ID: "c1", Type: "group"}, Message: Message{Thread: &ThreadRef{ID: "77"}}}})
+	if reply != topic {
+		t.Fatalf("expected message thread to match conversation thread, got %q", reply)
+	}
+	x := inboundOrderKey(&InboundJob{Config: cfg})
+	y := inboundOrderKey(&InboundJob{Config: cfg})
+	if x == y {
+		t.Fatal("jobs without a conversation must not be serialized together")
+	}
+}
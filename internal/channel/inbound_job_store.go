@@ -0,0 +1,320 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	// inboundJobLease is how long a claimed job stays invisible to other workers.
+	// Jobs of a crashed worker become claimable again once the lease expires.
+	inboundJobLease = 10 * time.Minute
+	// inboundJobPollInterval bounds how long a new job waits when enqueued by another process.
+	inboundJobPollInterval = 2 * time.Second
+	// inboundJobRetention keeps completed jobs long enough to deduplicate platform redeliveries.
+	inboundJobRetention     = 24 * time.Hour
+	inboundJobPruneInterval = time.Hour
+)
+
+// inboundJobQueries is the subset of sqlc queries used by InboundJobStore.
+type inboundJobQueries interface {
+	EnqueueChannelInboundJob(ctx context.Context, arg sqlc.EnqueueChannelInboundJobParams) (sqlc.ChannelInboundJob, error)
+	ClaimChannelInboundJob(ctx context.Context, arg sqlc.ClaimChannelInboundJobParams) (sqlc.ChannelInboundJob, error)
+	RenewChannelInboundJobLeases(ctx context.Context, arg sqlc.RenewChannelInboundJobLeasesParams) error
+	CompleteChannelInboundJob(ctx context.Context, id pgtype.UUID) error
+	RetryChannelInboundJob(ctx context.Context, arg sqlc.RetryChannelInboundJobParams) error
+	DeadLetterChannelInboundJob(ctx context.Context, arg sqlc.DeadLetterChannelInboundJobParams) error
+	ListDeadChannelInboundJobs(ctx context.Context, arg sqlc.ListDeadChannelInboundJobsParams) ([]sqlc.ChannelInboundJob, error)
+	ReplayChannelInboundJob(ctx context.Context, arg sqlc.ReplayChannelInboundJobParams) (int64, error)
+	DeleteCompletedChannelInboundJobs(ctx context.Context, before pgtype.Timestamptz) error
+}
+
+// InboundJobStore is a Postgres-backed InboundQueue with at-least-once delivery,
+// deduplication by platform message ID and a dead-letter state.
+type InboundJobStore struct {
+	queries    inboundJobQueries
+	configs    ConfigResolver
+	logger     *slog.Logger
+	wake       chan struct{}
+	now        func() time.Time
+	pruneMu    sync.Mutex
+	lastPruned time.Time
+}
+
+// NewInboundJobStore creates a durable inbound queue. Channel configs are resolved
+// again when a job is claimed so retries use current credentials.
+func NewInboundJobStore(log *slog.Logger, queries *sqlc.Queries, configs ConfigResolver) *InboundJobStore {
+	return newInboundJobStore(log, queries, configs)
+}
+
+func newInboundJobStore(log *slog.Logger, queries inboundJobQueries, configs ConfigResolver) *InboundJobStore {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &InboundJobStore{
+		queries: queries,
+		configs: configs,
+		logger:  log.With(slog.String("component", "inbound_jobs")),
+		wake:    make(chan struct{}, 1),
+		now:     time.Now,
+	}
+}
+
+// Enqueue persists the message. Redeliveries of an already queued platform message are ignored.
+func (s *InboundJobStore) Enqueue(ctx context.Context, cfg ChannelConfig, msg InboundMessage) error {
+	botUUID, err := db.ParseUUID(cfg.BotID)
+	if err != nil {
+		return fmt.Errorf("inbound job bot id: %w", err)
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode inbound job: %w", err)
+	}
+	channelType := msg.Channel
+	if channelType == "" {
+		channelType = cfg.ChannelType
+	}
+	// Edits, deletions and reactions reuse the ID of the message they change and
+	// are idempotent, so they are exempt from redelivery deduplication.
+	externalMessageID := strings.TrimSpace(msg.Message.ID)
+	if msg.IsHistoryUpdate() {
+		externalMessageID = ""
+	}
+	_, err = s.queries.EnqueueChannelInboundJob(ctx, sqlc.EnqueueChannelInboundJobParams{
+		BotID:             botUUID,
+		ChannelConfigID:   strings.TrimSpace(cfg.ID),
+		ChannelType:       channelType.String(),
+		ExternalMessageID: externalMessageID,
+		Payload:           payload,
+		RouteKey:          inboundRouteKey(cfg, msg),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Debug("duplicate inbound message ignored",
+			slog.String("bot_id", cfg.BotID),
+			slog.String("channel", channelType.String()),
+			slog.String("message_id", msg.Message.ID),
+		)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.wakeClaim()
+	return nil
+}
+
+func (s *InboundJobStore) wakeClaim() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Claim leases the next available job outside the busy conversations, waiting
+// until one is enqueued or ctx is done.
+func (s *InboundJobStore) Claim(ctx context.Context, busy func() []string) (*InboundJob, error) {
+	ticker := time.NewTicker(inboundJobPollInterval)
+	defer ticker.Stop()
+	for {
+		s.pruneCompleted(ctx)
+		busyRoutes := []string{}
+		if busy != nil {
+			busyRoutes = append(busyRoutes, busy()...)
+		}
+		row, err := s.queries.ClaimChannelInboundJob(ctx, sqlc.ClaimChannelInboundJobParams{
+			LockedUntil: pgtype.Timestamptz{Time: s.now().Add(inboundJobLease), Valid: true},
+			BusyRoutes:  busyRoutes,
+		})
+		if err == nil {
+			job, loadErr := s.loadJob(ctx, row)
+			if loadErr == nil {
+				return job, nil
+			}
+			s.logger.Warn("inbound job not processable", slog.String("job_id", row.ID.String()), slog.Any("error", loadErr))
+			if err := s.DeadLetter(ctx, &InboundJob{ID: row.ID.String(), Attempts: int(row.Attempts)}, loadErr); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// RenewLeases extends the leases of claimed jobs. Jobs finished in the meantime are left alone.
+func (s *InboundJobStore) RenewLeases(ctx context.Context, jobs []*InboundJob) error {
+	ids := make([]pgtype.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		id, err := db.ParseUUID(job.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.queries.RenewChannelInboundJobLeases(ctx, sqlc.RenewChannelInboundJobLeasesParams{
+		LockedUntil: pgtype.Timestamptz{Time: s.now().Add(inboundJobLease), Valid: true},
+		Ids:         ids,
+	})
+}
+
+// Complete marks a job as processed. Completed jobs are kept for deduplication and pruned later.
+func (s *InboundJobStore) Complete(ctx context.Context, job *InboundJob) error {
+	id, err := db.ParseUUID(job.ID)
+	if err != nil {
+		return err
+	}
+	return s.queries.CompleteChannelInboundJob(ctx, id)
+}
+
+// Retry releases a job to be claimed again at the given time.
+func (s *InboundJobStore) Retry(ctx context.Context, job *InboundJob, cause error, at time.Time) error {
+	id, err := db.ParseUUID(job.ID)
+	if err != nil {
+		return err
+	}
+	return s.queries.RetryChannelInboundJob(ctx, sqlc.RetryChannelInboundJobParams{
+		ID:          id,
+		AvailableAt: pgtype.Timestamptz{Time: at.UTC(), Valid: true},
+		LastError:   inboundJobError(cause),
+	})
+}
+
+// DeadLetter parks a job until it is replayed.
+func (s *InboundJobStore) DeadLetter(ctx context.Context, job *InboundJob, cause error) error {
+	id, err := db.ParseUUID(job.ID)
+	if err != nil {
+		return err
+	}
+	s.logger.Warn("inbound job dead-lettered",
+		slog.String("job_id", job.ID),
+		slog.Int("attempts", job.Attempts),
+		slog.Any("error", cause),
+	)
+	return s.queries.DeadLetterChannelInboundJob(ctx, sqlc.DeadLetterChannelInboundJobParams{
+		ID:        id,
+		LastError: inboundJobError(cause),
+	})
+}
+
+// ListDeadLetters returns the most recently failed jobs of a bot.
+func (s *InboundJobStore) ListDeadLetters(ctx context.Context, botID string, limit int) ([]InboundDeadLetter, error) {
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	rows, err := s.queries.ListDeadChannelInboundJobs(ctx, sqlc.ListDeadChannelInboundJobsParams{
+		BotID:    botUUID,
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]InboundDeadLetter, 0, len(rows))
+	for _, row := range rows {
+		item := InboundDeadLetter{
+			ID:                row.ID.String(),
+			BotID:             row.BotID.String(),
+			ConfigID:          row.ChannelConfigID,
+			ChannelType:       ChannelType(row.ChannelType),
+			ExternalMessageID: row.ExternalMessageID,
+			Attempts:          int(row.Attempts),
+			LastError:         row.LastError,
+			CreatedAt:         db.TimeFromPg(row.CreatedAt),
+			UpdatedAt:         db.TimeFromPg(row.UpdatedAt),
+		}
+		_ = json.Unmarshal(row.Payload, &item.Message)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ReplayDeadLetter makes a dead-lettered job claimable again with a fresh retry budget.
+func (s *InboundJobStore) ReplayDeadLetter(ctx context.Context, botID, jobID string) error {
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	id, err := db.ParseUUID(jobID)
+	if err != nil {
+		return ErrInboundJobNotFound
+	}
+	affected, err := s.queries.ReplayChannelInboundJob(ctx, sqlc.ReplayChannelInboundJobParams{
+		ID:    id,
+		BotID: botUUID,
+	})
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrInboundJobNotFound
+	}
+	s.wakeClaim()
+	return nil
+}
+
+func (s *InboundJobStore) loadJob(ctx context.Context, row sqlc.ChannelInboundJob) (*InboundJob, error) {
+	var msg InboundMessage
+	if err := json.Unmarshal(row.Payload, &msg); err != nil {
+		return nil, fmt.Errorf("decode inbound job: %w", err)
+	}
+	if s.configs == nil {
+		return nil, fmt.Errorf("channel config resolver not configured")
+	}
+	cfg, err := s.configs.ResolveEffectiveConfig(ctx, row.BotID.String(), ChannelType(row.ChannelType))
+	if err != nil {
+		return nil, fmt.Errorf("resolve channel config: %w", err)
+	}
+	return &InboundJob{
+		ID:       row.ID.String(),
+		Config:   cfg,
+		Message:  msg,
+		Attempts: int(row.Attempts),
+	}, nil
+}
+
+func (s *InboundJobStore) pruneCompleted(ctx context.Context) {
+	now := s.now()
+	s.pruneMu.Lock()
+	if now.Sub(s.lastPruned) < inboundJobPruneInterval {
+		s.pruneMu.Unlock()
+		return
+	}
+	s.lastPruned = now
+	s.pruneMu.Unlock()
+	before := pgtype.Timestamptz{Time: now.Add(-inboundJobRetention), Valid: true}
+	if err := s.queries.DeleteCompletedChannelInboundJobs(ctx, before); err != nil {
+		s.logger.Warn("prune inbound jobs failed", slog.Any("error", err))
+	}
+}
+
+func inboundJobError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return TruncateText(err.Error(), StoredErrorMaxLen)
+}
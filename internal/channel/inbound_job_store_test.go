@@ -0,0 +1,222 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	testJobBotID = "7f1c1a52-6f0e-4c1e-9d84-2b7f8c6c1e01"
+	testJobID    = "0b8a4d3e-2c55-4f7a-a1b2-9c3d4e5f6a70"
+)
+
+type fakeInboundJobQueries struct {
+	enqueued   []sqlc.EnqueueChannelInboundJobParams
+	claim      []sqlc.ChannelInboundJob
+	claimed    []sqlc.ClaimChannelInboundJobParams
+	renewed    []sqlc.RenewChannelInboundJobLeasesParams
+	dead       []sqlc.DeadLetterChannelInboundJobParams
+	listed     []sqlc.ChannelInboundJob
+	replayed   int64
+	enqueueErr error
+}
+
+func (f *fakeInboundJobQueries) EnqueueChannelInboundJob(_ context.Context, arg sqlc.EnqueueChannelInboundJobParams) (sqlc.ChannelInboundJob, error) {
+	f.enqueued = append(f.enqueued, arg)
+	return sqlc.ChannelInboundJob{}, f.enqueueErr
+}
+
+func (f *fakeInboundJobQueries) ClaimChannelInboundJob(_ context.Context, arg sqlc.ClaimChannelInboundJobParams) (sqlc.ChannelInboundJob, error) {
+	f.claimed = append(f.claimed, arg)
+	if len(f.claim) == 0 {
+		return sqlc.ChannelInboundJob{}, pgx.ErrNoRows
+	}
+	row := f.claim[0]
+	f.claim = f.claim[1:]
+	return row, nil
+}
+
+func (f *fakeInboundJobQueries) RenewChannelInboundJobLeases(_ context.Context, arg sqlc.RenewChannelInboundJobLeasesParams) error {
+	f.renewed = append(f.renewed, arg)
+	return nil
+}
+
+func (f *fakeInboundJobQueries) CompleteChannelInboundJob(context.Context, pgtype.UUID) error {
+	return nil
+}
+
+func (f *fakeInboundJobQueries) RetryChannelInboundJob(context.Context, sqlc.RetryChannelInboundJobParams) error {
+	return nil
+}
+
+func (f *fakeInboundJobQueries) DeadLetterChannelInboundJob(_ context.Context, arg sqlc.DeadLetterChannelInboundJobParams) error {
+	f.dead = append(f.dead, arg)
+	return nil
+}
+
+func (f *fakeInboundJobQueries) ListDeadChannelInboundJobs(context.Context, sqlc.ListDeadChannelInboundJobsParams) ([]sqlc.ChannelInboundJob, error) {
+	return f.listed, nil
+}
+
+func (f *fakeInboundJobQueries) ReplayChannelInboundJob(context.Context, sqlc.ReplayChannelInboundJobParams) (int64, error) {
+	return f.replayed, nil
+}
+
+func (f *fakeInboundJobQueries) DeleteCompletedChannelInboundJobs(context.Context, pgtype.Timestamptz) error {
+	return nil
+}
+
+type failingConfigResolver struct {
+	fakeConfigStore
+}
+
+func (f *failingConfigResolver) ResolveEffectiveConfig(context.Context, string, ChannelType) (ChannelConfig, error) {
+	return ChannelConfig{}, errors.New("channel config not found")
+}
+
+func testInboundJobRow(t *testing.T, msg InboundMessage) sqlc.ChannelInboundJob {
+	t.Helper()
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	botID, _ := db.ParseUUID(testJobBotID)
+	id, _ := db.ParseUUID(testJobID)
+	return sqlc.ChannelInboundJob{
+		ID:          id,
+		BotID:       botID,
+		ChannelType: "test",
+		Payload:     payload,
+		Attempts:    1,
+	}
+}
+
+func TestInboundJobStoreEnqueueIgnoresDuplicates(t *testing.T) {
+	t.Parallel()
+
+	queries := &fakeInboundJobQueries{enqueueErr: pgx.ErrNoRows}
+	store := newInboundJobStore(nil, queries, &fakeConfigStore{})
+	err := store.Enqueue(context.Background(), ChannelConfig{ID: "cfg-1", BotID: testJobBotID, ChannelType: "test"}, InboundMessage{
+		Message:      Message{ID: " m-1 ", Text: "hi"},
+		Conversation: Conversation{ID: "c1"},
+	})
+	if err != nil {
+		t.Fatalf("duplicate enqueue must succeed, got %v", err)
+	}
+	if len(queries.enqueued) != 1 {
+		t.Fatalf("expected one insert, got %d", len(queries.enqueued))
+	}
+	got := queries.enqueued[0]
+	if got.ChannelType != "test" || got.ExternalMessageID != "m-1" || got.ChannelConfigID != "cfg-1" || got.RouteKey != "test:"+testJobBotID+":c1" {
+		t.Fatalf("unexpected params: %+v", got)
+	}
+
+	edit := InboundMessage{Event: InboundEventMessageEdited, Message: Message{ID: "m-1", Text: "hi!"}}
+	if err := store.Enqueue(context.Background(), ChannelConfig{BotID: testJobBotID, ChannelType: "test"}, edit); err != nil {
+		t.Fatalf("enqueue edit: %v", err)
+	}
+	if got := queries.enqueued[1].ExternalMessageID; got != "" {
+		t.Fatalf("history updates must not be deduplicated by message id, got %q", got)
+	}
+
+	if err := store.Enqueue(context.Background(), ChannelConfig{BotID: "not-a-uuid"}, InboundMessage{}); err == nil {
+		t.Fatal("expected invalid bot id error")
+	}
+}
+
+func TestInboundJobStoreClaimResolvesConfig(t *testing.T) {
+	t.Parallel()
+
+	queries := &fakeInboundJobQueries{}
+	queries.claim = []sqlc.ChannelInboundJob{testInboundJobRow(t, InboundMessage{Channel: "test", Message: Message{Text: "hello"}})}
+	resolver := &fakeConfigStore{effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: testJobBotID}}
+	store := newInboundJobStore(nil, queries, resolver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	job, err := store.Claim(ctx, func() []string { return []string{"test:bot-1:busy"} })
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if job.ID != testJobID || job.Config.ID != "cfg-1" || job.Message.Message.Text != "hello" || job.Attempts != 1 {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if got := queries.claimed[0].BusyRoutes; len(got) != 1 || got[0] != "test:bot-1:busy" {
+		t.Fatalf("expected busy conversations to be skipped, got %v", got)
+	}
+
+	if err := store.RenewLeases(context.Background(), []*InboundJob{job, {ID: "not-a-uuid"}}); err != nil {
+		t.Fatalf("renew leases: %v", err)
+	}
+	if len(queries.renewed) != 1 || len(queries.renewed[0].Ids) != 1 || !queries.renewed[0].LockedUntil.Time.After(time.Now()) {
+		t.Fatalf("unexpected lease renewal: %+v", queries.renewed)
+	}
+}
+
+func TestInboundJobStoreClaimDeadLettersUnresolvableJobs(t *testing.T) {
+	t.Parallel()
+
+	queries := &fakeInboundJobQueries{}
+	queries.claim = []sqlc.ChannelInboundJob{testInboundJobRow(t, InboundMessage{Channel: "test"})}
+	store := newInboundJobStore(nil, queries, &failingConfigResolver{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := store.Claim(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected claim to wait for more jobs, got %v", err)
+	}
+	if len(queries.dead) != 1 || !strings.Contains(queries.dead[0].LastError, "channel config not found") {
+		t.Fatalf("expected job to be dead-lettered, got %+v", queries.dead)
+	}
+}
+
+func TestInboundJobStoreDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	queries := &fakeInboundJobQueries{}
+	row := testInboundJobRow(t, InboundMessage{Channel: "test", Message: Message{ID: "m-1", Text: "hello"}})
+	row.LastError = "boom"
+	queries.listed = []sqlc.ChannelInboundJob{row}
+	store := newInboundJobStore(nil, queries, &fakeConfigStore{})
+
+	items, err := store.ListDeadLetters(context.Background(), testJobBotID, 0)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != testJobID || items[0].Message.Message.Text != "hello" || items[0].LastError != "boom" {
+		t.Fatalf("unexpected dead letters: %+v", items)
+	}
+
+	if err := store.ReplayDeadLetter(context.Background(), testJobBotID, testJobID); !errors.Is(err, ErrInboundJobNotFound) {
+		t.Fatalf("expected ErrInboundJobNotFound, got %v", err)
+	}
+	if err := store.ReplayDeadLetter(context.Background(), testJobBotID, "bad-id"); !errors.Is(err, ErrInboundJobNotFound) {
+		t.Fatalf("expected ErrInboundJobNotFound for invalid id, got %v", err)
+	}
+	queries.replayed = 1
+	if err := store.ReplayDeadLetter(context.Background(), testJobBotID, testJobID); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+}
+
+func TestInboundJobErrorTruncatesOnRuneBoundary(t *testing.T) {
+	t.Parallel()
+
+	text := inboundJobError(errors.New(strings.Repeat("a", StoredErrorMaxLen-1) + "é"))
+	if text != strings.Repeat("a", StoredErrorMaxLen-1) {
+		t.Fatalf("unexpected truncation length %d", len(text))
+	}
+	if inboundJobError(nil) != "" {
+		t.Fatal("nil error must be empty")
+	}
+}
@@ -0,0 +1,205 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrInboundQueueFull is returned when an inbound message cannot be queued.
+var ErrInboundQueueFull = errors.New("inbound queue full")
+
+// ErrDeadLettersUnsupported is returned when the inbound queue keeps no dead letters.
+var ErrDeadLettersUnsupported = errors.New("inbound dead letters not supported")
+
+// ErrInboundJobNotFound indicates the requested dead-letter job does not exist.
+var ErrInboundJobNotFound = errors.New("inbound job not found")
+
+// InboundJob is one queued inbound message claimed by a worker.
+type InboundJob struct {
+	ID       string
+	Config   ChannelConfig
+	Message  InboundMessage
+	Attempts int
+
+	ctx context.Context
+}
+
+func (j *InboundJob) context() context.Context {
+	if j == nil || j.ctx == nil {
+		return context.Background()
+	}
+	return j.ctx
+}
+
+// InboundQueue buffers inbound messages between receipt and processing.
+// Claim blocks until a job is available or ctx is done. It leaves jobs of the
+// conversations reported by busy unclaimed; busy may be called repeatedly while
+// Claim waits. Claimed jobs must be finished with exactly one of Complete, Retry
+// or DeadLetter.
+type InboundQueue interface {
+	Enqueue(ctx context.Context, cfg ChannelConfig, msg InboundMessage) error
+	Claim(ctx context.Context, busy func() []string) (*InboundJob, error)
+	Complete(ctx context.Context, job *InboundJob) error
+	Retry(ctx context.Context, job *InboundJob, cause error, at time.Time) error
+	DeadLetter(ctx context.Context, job *InboundJob, cause error) error
+}
+
+// InboundDeadLetter is a job that exhausted its retries.
+type InboundDeadLetter struct {
+	ID                string         `json:"id"`
+	BotID             string         `json:"bot_id"`
+	ConfigID          string         `json:"config_id"`
+	ChannelType       ChannelType    `json:"channel_type"`
+	ExternalMessageID string         `json:"external_message_id,omitempty"`
+	Message           InboundMessage `json:"message"`
+	Attempts          int            `json:"attempts"`
+	LastError         string         `json:"last_error"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// InboundDeadLetterStore is implemented by queues that keep failed jobs for inspection and replay.
+type InboundDeadLetterStore interface {
+	ListDeadLetters(ctx context.Context, botID string, limit int) ([]InboundDeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, botID, jobID string) error
+}
+
+// InboundLeaseRenewer is implemented by queues whose claims expire. Workers renew
+// the leases of claimed jobs that are still waiting or running.
+type InboundLeaseRenewer interface {
+	RenewLeases(ctx context.Context, jobs []*InboundJob) error
+}
+
+// inboundClaimWaker is implemented by queues that can cut short a waiting Claim,
+// for example when a busy conversation can take another job.
+type inboundClaimWaker interface {
+	wakeClaim()
+}
+
+// InboundRetryPolicy bounds retries of failed inbound jobs with exponential backoff.
+type InboundRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultInboundRetryPolicy retries a failed job up to five times, starting at five seconds.
+var DefaultInboundRetryPolicy = InboundRetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   5 * time.Second,
+	MaxDelay:    5 * time.Minute,
+}
+
+// Backoff returns the delay before the next attempt after the given number of attempts.
+func (p InboundRetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Exhausted reports whether a job with the given attempts must be dead-lettered.
+func (p InboundRetryPolicy) Exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// memoryInboundQueue is the process-local fallback queue. Jobs are lost on restart.
+type memoryInboundQueue struct {
+	mu   sync.Mutex
+	jobs []*InboundJob
+	size int
+	wake chan struct{}
+}
+
+func newMemoryInboundQueue(size int) *memoryInboundQueue {
+	return &memoryInboundQueue{size: size, wake: make(chan struct{}, 1)}
+}
+
+func (q *memoryInboundQueue) Enqueue(ctx context.Context, cfg ChannelConfig, msg InboundMessage) error {
+	return q.push(&InboundJob{
+		Config:  cfg,
+		Message: msg,
+		ctx:     context.WithoutCancel(ctx),
+	})
+}
+
+func (q *memoryInboundQueue) push(job *InboundJob) error {
+	q.mu.Lock()
+	if len(q.jobs) >= q.size {
+		q.mu.Unlock()
+		return ErrInboundQueueFull
+	}
+	q.jobs = append(q.jobs, job)
+	q.mu.Unlock()
+	q.wakeClaim()
+	return nil
+}
+
+func (q *memoryInboundQueue) wakeClaim() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *memoryInboundQueue) Claim(ctx context.Context, busy func() []string) (*InboundJob, error) {
+	ticker := time.NewTicker(inboundJobPollInterval)
+	defer ticker.Stop()
+	for {
+		if job := q.pop(busy); job != nil {
+			job.Attempts++
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// pop removes the oldest job whose conversation is not busy.
+func (q *memoryInboundQueue) pop(busy func() []string) *InboundJob {
+	skip := map[string]struct{}{}
+	if busy != nil {
+		for _, key := range busy() {
+			skip[key] = struct{}{}
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, job := range q.jobs {
+		if _, ok := skip[inboundOrderKey(job)]; ok {
+			continue
+		}
+		q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+		return job
+	}
+	return nil
+}
+
+func (q *memoryInboundQueue) Complete(context.Context, *InboundJob) error {
+	return nil
+}
+
+func (q *memoryInboundQueue) Retry(_ context.Context, job *InboundJob, _ error, at time.Time) error {
+	time.AfterFunc(time.Until(at), func() {
+		_ = q.push(job)
+	})
+	return nil
+}
+
+func (q *memoryInboundQueue) DeadLetter(_ context.Context, job *InboundJob, cause error) error {
+	return fmt.Errorf("inbound job dropped after %d attempts: %w", job.Attempts, cause)
+}
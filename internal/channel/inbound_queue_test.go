@@ -0,0 +1,155 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInboundRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := InboundRetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	cases := map[int]time.Duration{0: time.Second, 1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 30: 5 * time.Second}
+	for attempts, want := range cases {
+		if got := policy.Backoff(attempts); got != want {
+			t.Fatalf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+	if policy.Exhausted(2) || !policy.Exhausted(3) {
+		t.Fatal("unexpected exhaustion result")
+	}
+	if (InboundRetryPolicy{}).Exhausted(100) {
+		t.Fatal("zero max attempts must retry forever")
+	}
+}
+
+type recordingInboundQueue struct {
+	mu        sync.Mutex
+	completed int
+	retried   []time.Time
+	dead      []error
+}
+
+func (q *recordingInboundQueue) Enqueue(context.Context, ChannelConfig, InboundMessage) error {
+	return nil
+}
+
+func (q *recordingInboundQueue) Claim(ctx context.Context, _ func() []string) (*InboundJob, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (q *recordingInboundQueue) Complete(context.Context, *InboundJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.completed++
+	return nil
+}
+
+func (q *recordingInboundQueue) Retry(_ context.Context, _ *InboundJob, _ error, at time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retried = append(q.retried, at)
+	return nil
+}
+
+func (q *recordingInboundQueue) DeadLetter(_ context.Context, _ *InboundJob, cause error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dead = append(q.dead, cause)
+	return nil
+}
+
+func TestManagerProcessInboundJobRetriesThenDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	procErr := errors.New("model unavailable")
+	manager := NewManager(log, NewRegistry(), &fakeConfigStore{}, &fakeInboundProcessor{err: procErr})
+	queue := &recordingInboundQueue{}
+	manager.SetInboundQueue(queue)
+	manager.SetInboundRetryPolicy(InboundRetryPolicy{MaxAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour})
+
+	job := &InboundJob{ID: "job-1", Message: InboundMessage{Channel: ChannelType("test")}, Attempts: 1}
+	before := time.Now()
+	manager.processInboundJob(context.Background(), job)
+	if len(queue.retried) != 1 || len(queue.dead) != 0 {
+		t.Fatalf("expected one retry, got retried=%d dead=%d", len(queue.retried), len(queue.dead))
+	}
+	if queue.retried[0].Before(before.Add(time.Minute)) {
+		t.Fatalf("retry scheduled too early: %s", queue.retried[0])
+	}
+
+	job.Attempts = 2
+	manager.processInboundJob(context.Background(), job)
+	if len(queue.dead) != 1 || !errors.Is(queue.dead[0], procErr) {
+		t.Fatalf("expected dead letter with cause, got %v", queue.dead)
+	}
+	if queue.completed != 0 {
+		t.Fatalf("failed jobs must not complete")
+	}
+}
+
+func TestManagerInboundDeadLettersUnsupportedByMemoryQueue(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager(nil, NewRegistry(), &fakeConfigStore{}, &fakeInboundProcessor{})
+	if _, err := manager.ListInboundDeadLetters(context.Background(), "bot-1", 10); !errors.Is(err, ErrDeadLettersUnsupported) {
+		t.Fatalf("expected ErrDeadLettersUnsupported, got %v", err)
+	}
+	if err := manager.ReplayInboundDeadLetter(context.Background(), "bot-1", "job-1"); !errors.Is(err, ErrDeadLettersUnsupported) {
+		t.Fatalf("expected ErrDeadLettersUnsupported, got %v", err)
+	}
+}
+
+func TestMemoryInboundQueueRetryRequeues(t *testing.T) {
+	t.Parallel()
+
+	queue := newMemoryInboundQueue(1)
+	if err := queue.Enqueue(context.Background(), ChannelConfig{ID: "cfg-1"}, InboundMessage{Message: Message{ID: "m1"}}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if err := queue.Enqueue(context.Background(), ChannelConfig{}, InboundMessage{}); !errors.Is(err, ErrInboundQueueFull) {
+		t.Fatalf("expected ErrInboundQueueFull, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	job, err := queue.Claim(ctx, nil)
+	if err != nil || job.Attempts != 1 {
+		t.Fatalf("unexpected claim: %#v %v", job, err)
+	}
+	if err := queue.Retry(ctx, job, errors.New("boom"), time.Now()); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	job, err = queue.Claim(ctx, nil)
+	if err != nil || job.Attempts != 2 || job.Message.Message.ID != "m1" {
+		t.Fatalf("unexpected reclaim: %#v %v", job, err)
+	}
+}
+
+func TestMemoryInboundQueueSkipsBusyConversations(t *testing.T) {
+	t.Parallel()
+
+	queue := newMemoryInboundQueue(4)
+	cfg := ChannelConfig{BotID: "bot-1", ChannelType: "test"}
+	for _, conv := range []string{"a", "b"} {
+		if err := queue.Enqueue(context.Background(), cfg, InboundMessage{Conversation: Conversation{ID: conv}}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	job, err := queue.Claim(ctx, func() []string { return []string{"test:bot-1:a"} })
+	if err != nil || job.Message.Conversation.ID != "b" {
+		t.Fatalf("expected job of idle conversation, got %#v %v", job, err)
+	}
+	job, err = queue.Claim(ctx, nil)
+	if err != nil || job.Message.Conversation.ID != "a" {
+		t.Fatalf("expected remaining job, got %#v %v", job, err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"testing"
+	"time"
 )
 
 // mockAdapter is used for inbound handleInbound tests.
@@ -215,3 +217,51 @@ func TestManager_handleInbound(t *testing.T) {
 		}
 	})
 }
+
+func TestManagerHandleInboundAppliesMiddleware(t *testing.T) {
+	t.Parallel()
+
+	processor := newOrderingProcessor()
+	manager := NewManager(nil, NewRegistry(), &fakeConfigStore{}, processor)
+	blocked := make(chan string, 4)
+	// A blocklist-style middleware that drops messages without calling the processor.
+	manager.Use(func(next InboundHandler) InboundHandler {
+		return func(ctx context.Context, cfg ChannelConfig, msg InboundMessage) error {
+			if strings.Contains(msg.Message.Text, "spam") {
+				blocked <- msg.Message.ID
+				return nil
+			}
+			return next(ctx, cfg, msg)
+		}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg := ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "test"}
+	for _, msg := range []Message{{ID: "m-1", Text: "buy spam now"}, {ID: "m-2", Text: "hello"}} {
+		err := manager.HandleInbound(ctx, cfg, InboundMessage{
+			Channel:      "test",
+			BotID:        "bot-1",
+			Message:      msg,
+			Conversation: Conversation{ID: "c1", Type: "group"},
+		})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+	}
+	select {
+	case id := <-blocked:
+		if id != "m-1" {
+			t.Fatalf("expected m-1 to be blocked, got %s", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the middleware")
+	}
+	waitProcessed(t, processor, 1)
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if got := processor.seen["c1"]; len(got) != 1 || got[0] != "m-2" {
+		t.Fatalf("expected only m-2 to reach the processor, got %v", got)
+	}
+}
@@ -65,16 +65,24 @@ type Manager struct {
 	refreshInterval time.Duration
 	logger          *slog.Logger
 	middlewares     []Middleware
+	// inboundChain is handleInbound wrapped in middlewares; it is rebuilt after Use.
+	inboundChain InboundHandler
 
-	inboundQueue   chan inboundTask
+	outboundLimiter *outboundLimiter
+	deliveries      OutboundDeliveryStore
+
+	inboundQueue   InboundQueue
+	inboundRetry   InboundRetryPolicy
 	inboundWorkers int
 	inboundOnce    sync.Once
-	inboundCtx     context.Context
-	inboundCancel  context.CancelFunc
-	mu             sync.Mutex
-	refreshMu      sync.Mutex
-	connections    map[string]*connectionEntry
-	connectionMeta map[string]ConnectionStatus
+	// inboundDispatch is set once the worker pool starts.
+	inboundDispatch *inboundDispatcher
+	inboundCtx      context.Context
+	inboundCancel   context.CancelFunc
+	mu              sync.Mutex
+	refreshMu       sync.Mutex
+	connections     map[string]*connectionEntry
+	connectionMeta  map[string]ConnectionStatus
 }
 
 // NewManager creates a Manager with the given logger, registry, config store, and inbound processor.
@@ -94,7 +102,9 @@ func NewManager(log *slog.Logger, registry *Registry, service ManagerStore, proc
 		connectionMeta:  map[string]ConnectionStatus{},
 		logger:          log.With(slog.String("component", "channel")),
 		middlewares:     []Middleware{},
-		inboundQueue:    make(chan inboundTask, 256),
+		outboundLimiter: newOutboundLimiter(),
+		inboundQueue:    newMemoryInboundQueue(256),
+		inboundRetry:    DefaultInboundRetryPolicy,
 		inboundWorkers:  4,
 	}
 }
@@ -104,9 +114,53 @@ func (m *Manager) Registry() *Registry {
 	return m.registry
 }
 
-// Use appends middleware to the inbound processing chain.
+// SetInboundQueue replaces the in-memory inbound queue, e.g. with a durable store.
+// It must be called before Start or the first HandleInbound.
+func (m *Manager) SetInboundQueue(queue InboundQueue) {
+	if queue != nil {
+		m.inboundQueue = queue
+	}
+}
+
+// SetOutboundDeliveryStore enables recording of outbound delivery outcomes.
+func (m *Manager) SetOutboundDeliveryStore(store OutboundDeliveryStore) {
+	m.deliveries = store
+}
+
+// SetInboundWorkers sets how many conversations are processed in parallel.
+// It must be called before Start or the first HandleInbound.
+func (m *Manager) SetInboundWorkers(workers int) {
+	if workers > 0 {
+		m.inboundWorkers = workers
+	}
+}
+
+// SetInboundRetryPolicy overrides how failed inbound jobs are retried.
+func (m *Manager) SetInboundRetryPolicy(policy InboundRetryPolicy) {
+	m.inboundRetry = policy
+}
+
+// Use appends middleware to the inbound processing chain. The chain applies to messages
+// from adapter connections and to jobs taken from the inbound queue alike.
 func (m *Manager) Use(mw ...Middleware) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.middlewares = append(m.middlewares, mw...)
+	m.inboundChain = nil
+}
+
+// inboundHandler returns handleInbound wrapped in the registered middlewares.
+func (m *Manager) inboundHandler() InboundHandler {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.inboundChain == nil {
+		handler := m.handleInbound
+		for i := len(m.middlewares) - 1; i >= 0; i-- {
+			handler = m.middlewares[i](handler)
+		}
+		m.inboundChain = handler
+	}
+	return m.inboundChain
 }
 
 // RegisterAdapter adds an adapter to the registry and logs the registration.
@@ -227,21 +281,11 @@ func (m *Manager) Send(ctx context.Context, botID string, channelType ChannelTyp
 	if m.logger != nil {
 		m.logger.Info("send outbound", slog.String("channel", channelType.String()), slog.String("bot_id", botID))
 	}
-	policy := m.resolveOutboundPolicy(channelType)
-	outbound, err := buildOutboundMessages(OutboundMessage{
-		Target:  target,
-		Message: req.Message,
-	}, policy)
-	if err != nil {
-		return err
-	}
-	for _, item := range outbound {
-		if err := m.sendWithConfig(ctx, sender, config, item, policy); err != nil {
-			if m.logger != nil {
-				m.logger.Error("send outbound failed", slog.String("channel", channelType.String()), slog.String("bot_id", botID), slog.Any("error", err))
-			}
-			return err
+	if err := m.sendOutbound(ctx, sender, config, OutboundMessage{Target: target, Message: req.Message}); err != nil {
+		if m.logger != nil {
+			m.logger.Error("send outbound failed", slog.String("channel", channelType.String()), slog.String("bot_id", botID), slog.Any("error", err))
 		}
+		return err
 	}
 	return nil
 }
@@ -0,0 +1,121 @@
+package moderation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	linkPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+	invitePattern = regexp.MustCompile(`(?i)\b(?:https?://)?(?:www\.)?(?:t\.me|telegram\.me|discord\.gg|discord(?:app)?\.com/invite|chat\.whatsapp\.com)/[^\s<>"']+`)
+)
+
+// checkRules evaluates the content rules of a policy against a message text and
+// returns the first violation.
+func checkRules(p Policy, text string) (Verdict, bool) {
+	if strings.TrimSpace(text) == "" {
+		return Verdict{}, false
+	}
+	if verdict, ok := checkBlocklist(p.Blocklist, text); ok {
+		return verdict, true
+	}
+	return checkLinks(p.Links, text)
+}
+
+func checkBlocklist(rule BlocklistRule, text string) (Verdict, bool) {
+	lower := strings.ToLower(text)
+	for _, word := range rule.Words {
+		if strings.Contains(lower, strings.ToLower(word)) {
+			return Verdict{Rule: RuleBlocklist, Reason: "blocked word: " + word, RuleAction: rule.RuleAction}, true
+		}
+	}
+	for _, pattern := range rule.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(text) {
+			return Verdict{Rule: RuleBlocklist, Reason: "blocked pattern: " + pattern, RuleAction: rule.RuleAction}, true
+		}
+	}
+	return Verdict{}, false
+}
+
+func checkLinks(rule LinkRule, text string) (Verdict, bool) {
+	if rule.BlockInvites {
+		for _, invite := range invitePattern.FindAllString(text, -1) {
+			if !isAllowedLink(invite, rule.AllowedDomains) {
+				return Verdict{Rule: RuleLinks, Reason: "invite link: " + invite, RuleAction: rule.RuleAction}, true
+			}
+		}
+	}
+	if rule.BlockLinks {
+		for _, link := range linkPattern.FindAllString(text, -1) {
+			if !isAllowedLink(link, rule.AllowedDomains) {
+				return Verdict{Rule: RuleLinks, Reason: "link: " + link, RuleAction: rule.RuleAction}, true
+			}
+		}
+	}
+	return Verdict{}, false
+}
+
+// isAllowedLink reports whether the link's host is one of the allowed domains or a
+// subdomain of one.
+func isAllowedLink(link string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return false
+	}
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(strings.TrimPrefix(parsed.Hostname(), "www."))
+	for _, domain := range allowed {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "www."))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// floodTracker counts recent messages per sender.
+type floodTracker struct {
+	mu   sync.Mutex
+	seen map[string][]time.Time
+}
+
+func newFloodTracker() *floodTracker {
+	return &floodTracker{seen: make(map[string][]time.Time)}
+}
+
+// record adds a message at now and reports whether the sender exceeded the rule, and
+// whether this message is the first over the limit in the current window.
+func (t *floodTracker) record(key string, rule FloodRule, now time.Time) (exceeded, first bool) {
+	if rule.MaxMessages <= 0 || rule.WindowSeconds <= 0 {
+		return false, false
+	}
+	window := time.Duration(rule.WindowSeconds) * time.Second
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	recent := t.seen[key][:0]
+	for _, at := range t.seen[key] {
+		if now.Sub(at) < window {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	t.seen[key] = recent
+	for other, times := range t.seen {
+		if len(times) > 0 && now.Sub(times[len(times)-1]) > window {
+			delete(t.seen, other)
+		}
+	}
+	return len(recent) > rule.MaxMessages, len(recent) == rule.MaxMessages+1
+}
@@ -0,0 +1,151 @@
+package moderation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckBlocklist(t *testing.T) {
+	rule := BlocklistRule{
+		RuleAction: RuleAction{Action: ActionWarn},
+		Words:      []string{"Free Crypto"},
+		Patterns:   []string{`(?i)\bcasino\d+\b`},
+	}
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{"get FREE crypto now", true},
+		{"visit casino777 today", true},
+		{"a normal message", false},
+	}
+	for _, tc := range cases {
+		verdict, ok := checkBlocklist(rule, tc.text)
+		if ok != tc.want {
+			t.Fatalf("checkBlocklist(%q) = %v, want %v", tc.text, ok, tc.want)
+		}
+		if ok && (verdict.Rule != RuleBlocklist || verdict.action() != ActionWarn) {
+			t.Fatalf("unexpected verdict: %+v", verdict)
+		}
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	cases := []struct {
+		name string
+		rule LinkRule
+		text string
+		want bool
+	}{
+		{"links allowed", LinkRule{}, "see https://example.com", false},
+		{"link blocked", LinkRule{BlockLinks: true}, "see https://example.com/page", true},
+		{"allowed domain", LinkRule{BlockLinks: true, AllowedDomains: []string{"example.com"}}, "see https://docs.example.com/page", false},
+		{"lookalike domain", LinkRule{BlockLinks: true, AllowedDomains: []string{"example.com"}}, "see https://badexample.com", true},
+		{"invite blocked", LinkRule{BlockInvites: true}, "join t.me/joinchat/abc", true},
+		{"discord invite blocked", LinkRule{BlockInvites: true}, "join https://discord.gg/xyz", true},
+		{"plain link with invites only", LinkRule{BlockInvites: true}, "see https://example.com", false},
+		{"allowed invite", LinkRule{BlockInvites: true, AllowedDomains: []string{"t.me"}}, "join t.me/ourgroup", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			verdict, ok := checkLinks(tc.rule, tc.text)
+			if ok != tc.want {
+				t.Fatalf("checkLinks(%q) = %v, want %v", tc.text, ok, tc.want)
+			}
+			if ok && verdict.Rule != RuleLinks {
+				t.Fatalf("unexpected rule: %s", verdict.Rule)
+			}
+		})
+	}
+}
+
+func TestCheckRulesOrder(t *testing.T) {
+	policy := Policy{
+		Blocklist: BlocklistRule{Words: []string{"spam"}},
+		Links:     LinkRule{BlockLinks: true},
+	}
+	verdict, ok := checkRules(policy, "spam at https://example.com")
+	if !ok || verdict.Rule != RuleBlocklist {
+		t.Fatalf("expected blocklist verdict first, got %+v (%v)", verdict, ok)
+	}
+	if _, ok := checkRules(policy, "   "); ok {
+		t.Fatal("empty text should not be flagged")
+	}
+}
+
+func TestFloodTracker(t *testing.T) {
+	tracker := newFloodTracker()
+	rule := FloodRule{MaxMessages: 2, WindowSeconds: 10}
+	start := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 2; i++ {
+		if exceeded, _ := tracker.record("s", rule, start.Add(time.Duration(i)*time.Second)); exceeded {
+			t.Fatalf("message %d should be within the limit", i+1)
+		}
+	}
+	exceeded, first := tracker.record("s", rule, start.Add(2*time.Second))
+	if !exceeded || !first {
+		t.Fatalf("third message: exceeded=%v first=%v, want true true", exceeded, first)
+	}
+	exceeded, first = tracker.record("s", rule, start.Add(3*time.Second))
+	if !exceeded || first {
+		t.Fatalf("fourth message: exceeded=%v first=%v, want true false", exceeded, first)
+	}
+	if exceeded, _ := tracker.record("other", rule, start.Add(3*time.Second)); exceeded {
+		t.Fatal("senders must be tracked separately")
+	}
+	if exceeded, _ := tracker.record("s", rule, start.Add(30*time.Second)); exceeded {
+		t.Fatal("window should have expired")
+	}
+}
+
+func TestFloodTrackerDisabled(t *testing.T) {
+	tracker := newFloodTracker()
+	for i := 0; i < 10; i++ {
+		if exceeded, _ := tracker.record("s", FloodRule{}, time.Now()); exceeded {
+			t.Fatal("disabled rule should never be exceeded")
+		}
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"empty", Policy{}, ""},
+		{"bad action", Policy{Links: LinkRule{RuleAction: RuleAction{Action: "ban"}}}, "links action"},
+		{"negative mute", Policy{Flood: FloodRule{RuleAction: RuleAction{MuteMinutes: -1}}}, "flood mute_minutes"},
+		{"bad pattern", Policy{Blocklist: BlocklistRule{Patterns: []string{"("}}}, "invalid blocklist pattern"},
+		{"missing window", Policy{Flood: FloodRule{MaxMessages: 3}}, "window_seconds"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRuleActionDefaults(t *testing.T) {
+	var zero RuleAction
+	if zero.action() != ActionIgnore {
+		t.Fatalf("zero action = %s, want ignore", zero.action())
+	}
+	if zero.muteDuration() != defaultMuteMinutes*time.Minute {
+		t.Fatalf("zero mute duration = %s", zero.muteDuration())
+	}
+	if got := (RuleAction{MuteMinutes: 5}).muteDuration(); got != 5*time.Minute {
+		t.Fatalf("mute duration = %s, want 5m", got)
+	}
+}
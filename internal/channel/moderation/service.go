@@ -0,0 +1,455 @@
+// Package moderation filters inbound channel messages before they reach the bot, with
+// rule-based checks and an optional model check, and keeps a per-bot event log.
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/identities"
+	"github.com/memohai/memoh/internal/channel/inbound"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+var (
+	// ErrInvalidPolicy is returned when a policy update fails validation.
+	ErrInvalidPolicy = errors.New("invalid moderation policy")
+	// ErrInvalidRequest is returned when an unmute request is incomplete.
+	ErrInvalidRequest = errors.New("invalid moderation request")
+)
+
+const (
+	defaultEventLimit = 50
+	maxEventLimit     = 500
+	// maxEventContentLen bounds, in bytes, the message excerpt stored with an event.
+	maxEventContentLen = 1000
+)
+
+// messageSender delivers warnings and owner notifications; implemented by channel.Manager.
+type messageSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// contentClassifier asks a model whether a message is spam or abuse.
+type contentClassifier interface {
+	IsFlagged(ctx context.Context, botID, text string) (bool, string, error)
+}
+
+// ownerResolver returns the owner user of a bot.
+type ownerResolver interface {
+	BotOwnerUserID(ctx context.Context, botID string) (string, error)
+}
+
+// userIdentityLister lists the channel identities linked to a user.
+type userIdentityLister interface {
+	ListUserChannelIdentities(ctx context.Context, userID string) ([]identities.ChannelIdentity, error)
+}
+
+// Service stores moderation policies and events and enforces them on inbound messages.
+type Service struct {
+	queries    *sqlc.Queries
+	owners     ownerResolver
+	identities userIdentityLister
+	sender     messageSender
+	classifier contentClassifier
+	flood      *floodTracker
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// NewService creates a moderation service.
+func NewService(log *slog.Logger, queries *sqlc.Queries, owners ownerResolver, identityLister userIdentityLister) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		queries:    queries,
+		owners:     owners,
+		identities: identityLister,
+		flood:      newFloodTracker(),
+		now:        time.Now,
+		logger:     log.With(slog.String("service", "moderation")),
+	}
+}
+
+// SetSender configures outbound delivery. It is set after construction because the
+// channel manager runs the moderation middleware.
+func (s *Service) SetSender(sender messageSender) {
+	if s == nil {
+		return
+	}
+	s.sender = sender
+}
+
+// SetClassifier configures the model used by the model rule.
+func (s *Service) SetClassifier(classifier contentClassifier) {
+	if s == nil {
+		return
+	}
+	s.classifier = classifier
+}
+
+// GetPolicy returns the moderation policy of a bot. Bots without one get a disabled policy.
+func (s *Service) GetPolicy(ctx context.Context, botID string) (Policy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Policy{}, err
+	}
+	row, err := s.queries.GetChannelModerationPolicy(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Policy{}, nil
+		}
+		return Policy{}, err
+	}
+	var policy Policy
+	if len(row.Policy) > 0 {
+		if err := json.Unmarshal(row.Policy, &policy); err != nil {
+			return Policy{}, fmt.Errorf("decode moderation policy: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// UpdatePolicy validates and stores the moderation policy of a bot.
+func (s *Service) UpdatePolicy(ctx context.Context, botID string, policy Policy) (Policy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy = policy.normalize()
+	if err := policy.validate(); err != nil {
+		return Policy{}, fmt.Errorf("%w: %s", ErrInvalidPolicy, err.Error())
+	}
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return Policy{}, err
+	}
+	if _, err := s.queries.UpsertChannelModerationPolicy(ctx, sqlc.UpsertChannelModerationPolicyParams{
+		BotID:  pgBotID,
+		Policy: payload,
+	}); err != nil {
+		return Policy{}, err
+	}
+	return policy, nil
+}
+
+// ListEvents returns the most recent moderation events of a bot.
+func (s *Service) ListEvents(ctx context.Context, botID string, limit int) ([]Event, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+	if limit > maxEventLimit {
+		limit = maxEventLimit
+	}
+	rows, err := s.queries.ListChannelModerationEvents(ctx, sqlc.ListChannelModerationEventsParams{
+		BotID:    pgBotID,
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toEvent(row))
+	}
+	return items, nil
+}
+
+// Unmute lifts the active mute of a sender and reports whether one was lifted.
+func (s *Service) Unmute(ctx context.Context, botID string, req UnmuteRequest) (bool, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return false, err
+	}
+	channelType := strings.TrimSpace(req.ChannelType)
+	subjectID := strings.TrimSpace(req.SenderSubjectID)
+	if channelType == "" || subjectID == "" {
+		return false, fmt.Errorf("%w: channel_type and sender_subject_id are required", ErrInvalidRequest)
+	}
+	affected, err := s.queries.LiftChannelModerationMutes(ctx, sqlc.LiftChannelModerationMutesParams{
+		BotID:           pgBotID,
+		ChannelType:     channelType,
+		SenderSubjectID: subjectID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Middleware returns a channel middleware that drops messages breaking the bot's
+// moderation policy. It must run after identity resolution so the owner is exempt.
+func (s *Service) Middleware() channel.Middleware {
+	return func(next channel.InboundHandler) channel.InboundHandler {
+		return func(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error {
+			if s.moderate(ctx, cfg, msg) {
+				return nil
+			}
+			return next(ctx, cfg, msg)
+		}
+	}
+}
+
+// moderate applies the policy to a message and reports whether it was dropped.
+// Lookup failures let the message through.
+func (s *Service) moderate(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) bool {
+	if s == nil || s.queries == nil || msg.EventType() != channel.InboundEventMessage {
+		return false
+	}
+	botID := strings.TrimSpace(msg.BotID)
+	if botID == "" {
+		botID = cfg.BotID
+	}
+	subjectID := strings.TrimSpace(msg.Sender.SubjectID)
+	if botID == "" || subjectID == "" {
+		return false
+	}
+	state, _ := inbound.IdentityStateFromContext(ctx)
+	if state.Decision != nil {
+		// Bind codes and other identity replies are handled before moderation applies.
+		return false
+	}
+	policy, err := s.GetPolicy(ctx, botID)
+	if err != nil {
+		s.logger.Warn("load moderation policy failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return false
+	}
+	if !policy.Enabled || s.isOwner(ctx, botID, state) {
+		return false
+	}
+	if s.isMuted(ctx, botID, msg.Channel, subjectID) {
+		s.logger.Debug("message from muted sender dropped", slog.String("bot_id", botID), slog.String("sender", subjectID))
+		return true
+	}
+
+	text := strings.TrimSpace(msg.Message.PlainText())
+	floodKey := botID + ":" + msg.Channel.String() + ":" + subjectID
+	exceeded, first := s.flood.record(floodKey, policy.Flood, s.now())
+	if exceeded {
+		if first {
+			reason := fmt.Sprintf("more than %d messages in %ds", policy.Flood.MaxMessages, policy.Flood.WindowSeconds)
+			s.enforce(ctx, cfg, msg, botID, policy, Verdict{Rule: RuleFlood, Reason: reason, RuleAction: policy.Flood.RuleAction})
+		}
+		return true
+	}
+	verdict, flagged := checkRules(policy, text)
+	if !flagged && policy.Model.Enabled {
+		verdict, flagged = s.checkModel(ctx, botID, text, policy.Model)
+	}
+	if !flagged {
+		return false
+	}
+	s.enforce(ctx, cfg, msg, botID, policy, verdict)
+	return true
+}
+
+func (s *Service) checkModel(ctx context.Context, botID, text string, rule ModelRule) (Verdict, bool) {
+	if s.classifier == nil || text == "" {
+		return Verdict{}, false
+	}
+	flagged, reason, err := s.classifier.IsFlagged(ctx, botID, text)
+	if err != nil {
+		s.logger.Warn("moderation model check failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return Verdict{}, false
+	}
+	if !flagged {
+		return Verdict{}, false
+	}
+	return Verdict{Rule: RuleModel, Reason: strings.TrimSpace(reason), RuleAction: rule.RuleAction}, true
+}
+
+func (s *Service) isOwner(ctx context.Context, botID string, state inbound.IdentityState) bool {
+	userID := strings.TrimSpace(state.Identity.UserID)
+	if userID == "" || s.owners == nil {
+		return false
+	}
+	ownerID, err := s.owners.BotOwnerUserID(ctx, botID)
+	if err != nil {
+		s.logger.Warn("resolve bot owner failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return false
+	}
+	return strings.TrimSpace(ownerID) == userID
+}
+
+func (s *Service) isMuted(ctx context.Context, botID string, channelType channel.ChannelType, subjectID string) bool {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return false
+	}
+	_, err = s.queries.GetActiveChannelModerationMute(ctx, sqlc.GetActiveChannelModerationMuteParams{
+		BotID:           pgBotID,
+		ChannelType:     channelType.String(),
+		SenderSubjectID: subjectID,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("check moderation mute failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+		return false
+	}
+	return true
+}
+
+// enforce records the violation and carries out its action.
+func (s *Service) enforce(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage, botID string, policy Policy, verdict Verdict) {
+	action := verdict.action()
+	var mutedUntil *time.Time
+	if action == ActionMute {
+		until := s.now().Add(verdict.muteDuration())
+		mutedUntil = &until
+	}
+	s.logger.Info("message moderated",
+		slog.String("bot_id", botID),
+		slog.String("channel", msg.Channel.String()),
+		slog.String("sender", msg.Sender.SubjectID),
+		slog.String("rule", verdict.Rule),
+		slog.String("action", string(action)),
+	)
+	if err := s.recordEvent(ctx, botID, msg, verdict, action, mutedUntil); err != nil {
+		s.logger.Warn("record moderation event failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+	if action == ActionWarn {
+		s.warn(ctx, botID, msg, policy)
+	}
+	if verdict.NotifyOwner {
+		s.notifyOwner(ctx, botID, msg, verdict, action, mutedUntil)
+	}
+}
+
+func (s *Service) recordEvent(ctx context.Context, botID string, msg channel.InboundMessage, verdict Verdict, action Action, mutedUntil *time.Time) error {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	var pgMutedUntil pgtype.Timestamptz
+	if mutedUntil != nil {
+		pgMutedUntil = pgtype.Timestamptz{Time: *mutedUntil, Valid: true}
+	}
+	_, err = s.queries.CreateChannelModerationEvent(ctx, sqlc.CreateChannelModerationEventParams{
+		BotID:           pgBotID,
+		ChannelType:     msg.Channel.String(),
+		ConversationID:  strings.TrimSpace(msg.Conversation.ID),
+		SenderSubjectID: strings.TrimSpace(msg.Sender.SubjectID),
+		SenderName:      strings.TrimSpace(msg.Sender.DisplayName),
+		MessageID:       strings.TrimSpace(msg.Message.ID),
+		Rule:            verdict.Rule,
+		Action:          string(action),
+		Reason:          verdict.Reason,
+		Content:         channel.TruncateText(strings.TrimSpace(msg.Message.PlainText()), maxEventContentLen),
+		MutedUntil:      pgMutedUntil,
+	})
+	return err
+}
+
+func (s *Service) warn(ctx context.Context, botID string, msg channel.InboundMessage, policy Policy) {
+	target := strings.TrimSpace(msg.ReplyTarget)
+	if s.sender == nil || target == "" {
+		return
+	}
+	text := policy.WarnText
+	if text == "" {
+		text = defaultWarnText
+	}
+	reply := channel.Message{Text: text, Thread: msg.Message.Thread}
+	if messageID := strings.TrimSpace(msg.Message.ID); messageID != "" {
+		reply.Reply = &channel.ReplyRef{Target: target, MessageID: messageID}
+	}
+	if err := s.sender.Send(ctx, botID, msg.Channel, channel.SendRequest{Target: target, Message: reply}); err != nil {
+		s.logger.Warn("send moderation warning failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+// notifyOwner messages the bot owner through their identity on the same platform.
+func (s *Service) notifyOwner(ctx context.Context, botID string, msg channel.InboundMessage, verdict Verdict, action Action, mutedUntil *time.Time) {
+	if s.sender == nil || s.owners == nil || s.identities == nil {
+		return
+	}
+	ownerID, err := s.owners.BotOwnerUserID(ctx, botID)
+	if err != nil || strings.TrimSpace(ownerID) == "" {
+		s.logger.Warn("resolve bot owner failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return
+	}
+	items, err := s.identities.ListUserChannelIdentities(ctx, ownerID)
+	if err != nil {
+		s.logger.Warn("list owner identities failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return
+	}
+	for _, item := range items {
+		if !strings.EqualFold(item.Channel, msg.Channel.String()) {
+			continue
+		}
+		err := s.sender.Send(ctx, botID, msg.Channel, channel.SendRequest{
+			ChannelIdentityID: item.ID,
+			Message:           channel.Message{Text: ownerNotice(msg, verdict, action, mutedUntil)},
+		})
+		if err == nil {
+			return
+		}
+		s.logger.Warn("notify owner failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+func ownerNotice(msg channel.InboundMessage, verdict Verdict, action Action, mutedUntil *time.Time) string {
+	sender := strings.TrimSpace(msg.Sender.DisplayName)
+	if sender == "" {
+		sender = strings.TrimSpace(msg.Sender.SubjectID)
+	}
+	where := strings.TrimSpace(msg.Conversation.Name)
+	if where == "" {
+		where = strings.TrimSpace(msg.Conversation.ID)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Moderation on %s: blocked a message from %s", msg.Channel, sender)
+	if where != "" {
+		fmt.Fprintf(&b, " in %s", where)
+	}
+	fmt.Fprintf(&b, ".\nRule: %s", verdict.Rule)
+	if verdict.Reason != "" {
+		fmt.Fprintf(&b, " (%s)", verdict.Reason)
+	}
+	fmt.Fprintf(&b, "\nAction: %s", action)
+	if mutedUntil != nil {
+		fmt.Fprintf(&b, " until %s", mutedUntil.UTC().Format(time.RFC3339))
+	}
+	if text := strings.TrimSpace(msg.Message.PlainText()); text != "" {
+		fmt.Fprintf(&b, "\nMessage: %s", channel.TruncateText(text, 400))
+	}
+	return b.String()
+}
+
+func toEvent(row sqlc.ChannelModerationEvent) Event {
+	event := Event{
+		ID:              row.ID.String(),
+		BotID:           row.BotID.String(),
+		ChannelType:     row.ChannelType,
+		ConversationID:  row.ConversationID,
+		SenderSubjectID: row.SenderSubjectID,
+		SenderName:      row.SenderName,
+		MessageID:       row.MessageID,
+		Rule:            row.Rule,
+		Action:          Action(row.Action),
+		Reason:          row.Reason,
+		Content:         row.Content,
+		CreatedAt:       db.TimeFromPg(row.CreatedAt),
+	}
+	if row.MutedUntil.Valid {
+		until := row.MutedUntil.Time
+		event.MutedUntil = &until
+	}
+	return event
+}
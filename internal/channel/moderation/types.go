@@ -0,0 +1,195 @@
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Action is what happens to a message that breaks a rule.
+type Action string
+
+const (
+	// ActionIgnore drops the message silently.
+	ActionIgnore Action = "ignore"
+	// ActionWarn drops the message and replies with a warning.
+	ActionWarn Action = "warn"
+	// ActionMute drops the message and ignores the sender for MuteMinutes.
+	ActionMute Action = "mute"
+)
+
+// Rule names recorded in the event log.
+const (
+	RuleBlocklist = "blocklist"
+	RuleLinks     = "links"
+	RuleFlood     = "flood"
+	RuleModel     = "model"
+)
+
+const (
+	defaultMuteMinutes = 10
+	defaultWarnText    = "This message was removed by moderation."
+)
+
+// RuleAction configures the response to a rule violation. The zero value ignores the
+// message without notifying anyone.
+type RuleAction struct {
+	Action      Action `json:"action,omitempty"`
+	MuteMinutes int    `json:"mute_minutes,omitempty"`
+	NotifyOwner bool   `json:"notify_owner,omitempty"`
+}
+
+// BlocklistRule flags messages containing a blocked word or phrase, or matching a pattern.
+type BlocklistRule struct {
+	RuleAction
+	// Words are matched case-insensitively as substrings.
+	Words    []string `json:"words,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// LinkRule flags messages with links. Invite links to other groups are flagged when
+// BlockInvites is set even if links in general are allowed.
+type LinkRule struct {
+	RuleAction
+	BlockLinks     bool     `json:"block_links,omitempty"`
+	BlockInvites   bool     `json:"block_invites,omitempty"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+}
+
+// FloodRule flags senders posting more than MaxMessages within WindowSeconds.
+type FloodRule struct {
+	RuleAction
+	MaxMessages   int `json:"max_messages,omitempty"`
+	WindowSeconds int `json:"window_seconds,omitempty"`
+}
+
+// ModelRule asks the bot's memory model whether a message is spam or abuse. It runs
+// after the other rules, only for messages they let through.
+type ModelRule struct {
+	RuleAction
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// Policy is the moderation configuration of a bot. It applies to messages from
+// everyone except the bot owner.
+type Policy struct {
+	Enabled   bool          `json:"enabled"`
+	Blocklist BlocklistRule `json:"blocklist"`
+	Links     LinkRule      `json:"links"`
+	Flood     FloodRule     `json:"flood"`
+	Model     ModelRule     `json:"model"`
+	// WarnText is the reply for the warn action.
+	WarnText string `json:"warn_text,omitempty"`
+}
+
+// Event is a logged moderation decision.
+type Event struct {
+	ID              string     `json:"id"`
+	BotID           string     `json:"bot_id"`
+	ChannelType     string     `json:"channel_type"`
+	ConversationID  string     `json:"conversation_id,omitempty"`
+	SenderSubjectID string     `json:"sender_subject_id"`
+	SenderName      string     `json:"sender_name,omitempty"`
+	MessageID       string     `json:"message_id,omitempty"`
+	Rule            string     `json:"rule"`
+	Action          Action     `json:"action"`
+	Reason          string     `json:"reason,omitempty"`
+	Content         string     `json:"content,omitempty"`
+	MutedUntil      *time.Time `json:"muted_until,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// ListEventsResponse wraps a list of moderation events.
+type ListEventsResponse struct {
+	Items []Event `json:"items"`
+}
+
+// UnmuteRequest lifts the active mute of a sender.
+type UnmuteRequest struct {
+	ChannelType     string `json:"channel_type"`
+	SenderSubjectID string `json:"sender_subject_id"`
+}
+
+// Verdict is a rule violation found in a message.
+type Verdict struct {
+	Rule   string
+	Reason string
+	RuleAction
+}
+
+func (a RuleAction) validate(rule string) error {
+	switch a.Action {
+	case "", ActionIgnore, ActionWarn, ActionMute:
+	default:
+		return fmt.Errorf("%s action must be ignore, warn or mute", rule)
+	}
+	if a.MuteMinutes < 0 {
+		return fmt.Errorf("%s mute_minutes must not be negative", rule)
+	}
+	return nil
+}
+
+// action returns the configured action, defaulting to ignore.
+func (a RuleAction) action() Action {
+	if a.Action == "" {
+		return ActionIgnore
+	}
+	return a.Action
+}
+
+// muteDuration returns how long the mute action silences the sender.
+func (a RuleAction) muteDuration() time.Duration {
+	if a.MuteMinutes <= 0 {
+		return defaultMuteMinutes * time.Minute
+	}
+	return time.Duration(a.MuteMinutes) * time.Minute
+}
+
+func (p Policy) validate() error {
+	actions := []struct {
+		rule   string
+		action RuleAction
+	}{
+		{RuleBlocklist, p.Blocklist.RuleAction},
+		{RuleLinks, p.Links.RuleAction},
+		{RuleFlood, p.Flood.RuleAction},
+		{RuleModel, p.Model.RuleAction},
+	}
+	for _, item := range actions {
+		if err := item.action.validate(item.rule); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range p.Blocklist.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid blocklist pattern %q: %w", pattern, err)
+		}
+	}
+	if p.Flood.MaxMessages < 0 || p.Flood.WindowSeconds < 0 {
+		return fmt.Errorf("flood limits must not be negative")
+	}
+	if p.Flood.MaxMessages > 0 && p.Flood.WindowSeconds == 0 {
+		return fmt.Errorf("flood window_seconds is required with max_messages")
+	}
+	return nil
+}
+
+// normalize trims list entries and drops empty ones.
+func (p Policy) normalize() Policy {
+	p.Blocklist.Words = trimList(p.Blocklist.Words)
+	p.Blocklist.Patterns = trimList(p.Blocklist.Patterns)
+	p.Links.AllowedDomains = trimList(p.Links.AllowedDomains)
+	p.WarnText = strings.TrimSpace(p.WarnText)
+	return p
+}
+
+func trimList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package channel
 import (
 	"path/filepath"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/memohai/memoh/internal/attachment"
 )
@@ -64,3 +65,18 @@ func NormalizeInboundChannelAttachment(att Attachment) Attachment {
 	att.Caption = strings.TrimSpace(att.Caption)
 	return att
 }
+
+// StoredErrorMaxLen caps error text persisted by channel stores.
+const StoredErrorMaxLen = 2000
+
+// TruncateText cuts text to at most maxBytes bytes without splitting a rune.
+func TruncateText(text string, maxBytes int) string {
+	if len(text) <= maxBytes {
+		return text
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
-	"time"
+	"sync"
 )
 
 // ChunkerMode selects the text chunking strategy.
@@ -35,6 +35,9 @@ type OutboundPolicy struct {
 	MediaOrder     OutboundOrder `json:"media_order,omitempty"`
 	RetryMax       int           `json:"retry_max,omitempty"`
 	RetryBackoffMs int           `json:"retry_backoff_ms,omitempty"`
+	// RateLimitPerSecond paces sends per bot account and target; zero disables pacing.
+	RateLimitPerSecond float64 `json:"rate_limit_per_second,omitempty"`
+	RateLimitBurst     int     `json:"rate_limit_burst,omitempty"`
 }
 
 // NormalizeOutboundPolicy fills zero-value fields with sensible defaults.
@@ -54,6 +57,9 @@ func NormalizeOutboundPolicy(policy OutboundPolicy) OutboundPolicy {
 	if policy.RetryBackoffMs <= 0 {
 		policy.RetryBackoffMs = 500
 	}
+	if policy.RateLimitPerSecond > 0 && policy.RateLimitBurst <= 0 {
+		policy.RateLimitBurst = 1
+	}
 	if policy.Chunker == nil {
 		policy.Chunker = DefaultChunker(policy.ChunkerMode)
 	}
@@ -308,64 +314,211 @@ func validateMessageCapabilities(registry *Registry, channelType ChannelType, ms
 	return nil
 }
 
-func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg ChannelConfig, msg OutboundMessage, policy OutboundPolicy) error {
+// sendWithConfig delivers one outbound chunk. Sends are paced per account and
+// target, and failures are retried with backoff or after the delay the platform
+// asked for. It returns the number of attempts made.
+func (m *Manager) sendWithConfig(ctx context.Context, sender Sender, cfg ChannelConfig, msg OutboundMessage, policy OutboundPolicy) (int, error) {
 	if sender == nil {
-		return fmt.Errorf("unsupported channel type: %s", cfg.ChannelType)
+		return 0, fmt.Errorf("unsupported channel type: %s", cfg.ChannelType)
 	}
 	target := strings.TrimSpace(msg.Target)
 	if target == "" {
-		return fmt.Errorf("target is required")
+		return 0, fmt.Errorf("target is required")
 	}
 	if msg.Message.IsEmpty() {
-		return fmt.Errorf("message is required")
+		return 0, fmt.Errorf("message is required")
 	}
 	normalized := msg
 	attachments, err := normalizeAttachmentRefs(msg.Message.Attachments, cfg.ChannelType)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	normalized.Message.Attachments = attachments
 	if err := validateMessageCapabilities(m.registry, cfg.ChannelType, normalized.Message); err != nil {
-		return err
+		return 0, err
 	}
-	editor, _ := m.registry.GetMessageEditor(cfg.ChannelType)
-	if strings.TrimSpace(normalized.Message.ID) != "" {
+	op := "send"
+	send := func(ctx context.Context) error {
+		return sender.Send(ctx, cfg, OutboundMessage{Target: target, Message: normalized.Message})
+	}
+	if messageID := strings.TrimSpace(normalized.Message.ID); messageID != "" {
+		editor, _ := m.registry.GetMessageEditor(cfg.ChannelType)
 		if editor == nil {
-			return fmt.Errorf("channel does not support edit")
+			return 0, fmt.Errorf("channel does not support edit")
 		}
-		var lastErr error
-		for i := 0; i < policy.RetryMax; i++ {
-			err := editor.Update(ctx, cfg, target, strings.TrimSpace(normalized.Message.ID), normalized.Message)
-			if err == nil {
-				return nil
-			}
-			lastErr = err
-			if m.logger != nil {
-				m.logger.Warn("edit outbound retry",
-					slog.String("channel", cfg.ChannelType.String()),
-					slog.Int("attempt", i+1),
-					slog.Any("error", err))
-			}
-			time.Sleep(time.Duration(i+1) * time.Duration(policy.RetryBackoffMs) * time.Millisecond)
+		op = "edit"
+		send = func(ctx context.Context) error {
+			return editor.Update(ctx, cfg, target, messageID, normalized.Message)
 		}
-		return fmt.Errorf("edit outbound failed after retries: %w", lastErr)
 	}
+	return m.retryOutbound(ctx, cfg, outboundLimiterKey(cfg, target), policy, op, send)
+}
+
+// retryOutbound runs send until it succeeds, pacing every attempt with the
+// limiter of key. Retries wait with backoff or for the delay the platform asked
+// for. Every attempt gets the same SendProgress, so adapters can skip the parts
+// an earlier attempt already delivered. It returns the number of attempts made.
+func (m *Manager) retryOutbound(ctx context.Context, cfg ChannelConfig, key string, policy OutboundPolicy, op string, send func(context.Context) error) (int, error) {
+	ctx, _ = WithSendProgress(ctx)
 	var lastErr error
-	for i := 0; i < policy.RetryMax; i++ {
-		err := sender.Send(ctx, cfg, OutboundMessage{Target: target, Message: normalized.Message})
+	attempts := 0
+	for attempts < policy.RetryMax {
+		if err := m.outboundLimiter.wait(ctx, key, policy); err != nil {
+			return attempts, err
+		}
+		attempts++
+		err := send(ctx)
 		if err == nil {
-			return nil
+			return attempts, nil
 		}
 		lastErr = err
+		if attempts >= policy.RetryMax {
+			break
+		}
+		delay := outboundRetryDelay(policy, attempts, err)
+		if delay > outboundMaxRetryAfter {
+			break
+		}
 		if m.logger != nil {
-			m.logger.Warn("send outbound retry",
+			m.logger.Warn(op+" outbound retry",
 				slog.String("channel", cfg.ChannelType.String()),
-				slog.Int("attempt", i+1),
+				slog.Int("attempt", attempts),
+				slog.Duration("delay", delay),
 				slog.Any("error", err))
 		}
-		time.Sleep(time.Duration(i+1) * time.Duration(policy.RetryBackoffMs) * time.Millisecond)
+		if RetryAfterHint(err) > 0 {
+			// Hold back every send to this target, not just this chunk.
+			m.outboundLimiter.pause(key, policy, delay)
+			continue
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return attempts, err
+		}
+	}
+	return attempts, fmt.Errorf("%s outbound failed after %d attempts: %w", op, attempts, lastErr)
+}
+
+// outboundResult summarizes the delivery of one outbound message.
+type outboundResult struct {
+	total    int
+	sent     int
+	attempts int
+	err      error
+	// messageIDs are the platform IDs of the delivered chunks.
+	messageIDs []string
+}
+
+// deliverOutbound sends the chunks of msg in order, starting with chunk start,
+// and stops at the first chunk that cannot be delivered.
+func (m *Manager) deliverOutbound(ctx context.Context, sender Sender, cfg ChannelConfig, msg OutboundMessage, start int) outboundResult {
+	policy := m.resolveOutboundPolicy(cfg.ChannelType)
+	outbound, err := buildOutboundMessages(msg, policy)
+	if err != nil {
+		return outboundResult{sent: start, err: err}
+	}
+	ctx, sent := withNestedSentMessageRecorder(ctx)
+	result := outboundResult{total: len(outbound), sent: min(start, len(outbound))}
+	for _, item := range outbound[result.sent:] {
+		attempts, err := m.sendWithConfig(ctx, sender, cfg, item, policy)
+		result.attempts += attempts
+		if err != nil {
+			result.err = err
+			break
+		}
+		result.sent++
+	}
+	result.messageIDs = sent.MessageIDs()
+	return result
+}
+
+// sendOutbound delivers msg and records the outcome when a delivery store is configured.
+func (m *Manager) sendOutbound(ctx context.Context, sender Sender, cfg ChannelConfig, msg OutboundMessage) error {
+	result := m.deliverOutbound(ctx, sender, cfg, msg, 0)
+	if result.total > 0 {
+		m.recordDelivery(ctx, cfg, msg, result)
+	}
+	return result.err
+}
+
+func (m *Manager) recordDelivery(ctx context.Context, cfg ChannelConfig, msg OutboundMessage, result outboundResult) {
+	if m.deliveries == nil {
+		return
+	}
+	delivery := OutboundDelivery{
+		BotID:              cfg.BotID,
+		ConfigID:           cfg.ID,
+		ChannelType:        cfg.ChannelType,
+		Target:             strings.TrimSpace(msg.Target),
+		Message:            msg.Message,
+		Status:             DeliveryStatusSent,
+		Attempts:           result.attempts,
+		ChunksTotal:        result.total,
+		ChunksSent:         result.sent,
+		PlatformMessageIDs: result.messageIDs,
+	}
+	if result.err != nil {
+		delivery.Status = DeliveryStatusFailed
+		delivery.LastError = result.err.Error()
+	}
+	// The reply may have been sent by a request that is already finishing.
+	if err := m.deliveries.RecordDelivery(context.WithoutCancel(ctx), delivery); err != nil && m.logger != nil {
+		m.logger.Warn("record outbound delivery failed",
+			slog.String("channel", cfg.ChannelType.String()),
+			slog.String("bot_id", cfg.BotID),
+			slog.Any("error", err))
+	}
+}
+
+// ListOutboundDeliveries returns recorded deliveries of a bot, optionally filtered by status.
+func (m *Manager) ListOutboundDeliveries(ctx context.Context, botID string, status DeliveryStatus, limit int) ([]OutboundDelivery, error) {
+	if m.deliveries == nil {
+		return nil, ErrOutboundDeliveriesUnsupported
+	}
+	return m.deliveries.ListDeliveries(ctx, botID, status, limit)
+}
+
+// ResendOutboundDelivery sends the undelivered chunks of a failed delivery again
+// using the current channel config. When the resend itself fails, the updated
+// record is returned together with the send error.
+func (m *Manager) ResendOutboundDelivery(ctx context.Context, botID, deliveryID string) (OutboundDelivery, error) {
+	if m.deliveries == nil {
+		return OutboundDelivery{}, ErrOutboundDeliveriesUnsupported
+	}
+	if m.service == nil {
+		return OutboundDelivery{}, fmt.Errorf("channel manager not configured")
+	}
+	delivery, err := m.deliveries.GetDelivery(ctx, botID, deliveryID)
+	if err != nil {
+		return OutboundDelivery{}, err
+	}
+	if delivery.Status != DeliveryStatusFailed {
+		return OutboundDelivery{}, ErrOutboundDeliveryNotFailed
 	}
-	return fmt.Errorf("send outbound failed after retries: %w", lastErr)
+	sender, ok := m.registry.GetSender(delivery.ChannelType)
+	if !ok {
+		return OutboundDelivery{}, fmt.Errorf("unsupported channel type: %s", delivery.ChannelType)
+	}
+	cfg, err := m.service.ResolveEffectiveConfig(ctx, botID, delivery.ChannelType)
+	if err != nil {
+		return OutboundDelivery{}, err
+	}
+	result := m.deliverOutbound(ctx, sender, cfg, OutboundMessage{Target: delivery.Target, Message: delivery.Message}, delivery.ChunksSent)
+	delivery.Attempts += result.attempts
+	delivery.ChunksSent = result.sent
+	delivery.PlatformMessageIDs = append(delivery.PlatformMessageIDs, result.messageIDs...)
+	if result.total > 0 {
+		delivery.ChunksTotal = result.total
+	}
+	delivery.Status = DeliveryStatusSent
+	delivery.LastError = ""
+	if result.err != nil {
+		delivery.Status = DeliveryStatusFailed
+		delivery.LastError = result.err.Error()
+	}
+	if err := m.deliveries.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		return OutboundDelivery{}, err
+	}
+	return delivery, result.err
 }
 
 func normalizeAttachmentRefs(attachments []Attachment, defaultPlatform ChannelType) ([]Attachment, error) {
@@ -482,17 +635,7 @@ func (s *managerReplySender) Send(ctx context.Context, msg OutboundMessage) erro
 	if s.manager == nil {
 		return fmt.Errorf("channel manager not configured")
 	}
-	policy := s.manager.resolveOutboundPolicy(s.channelType)
-	outbound, err := buildOutboundMessages(msg, policy)
-	if err != nil {
-		return err
-	}
-	for _, item := range outbound {
-		if err := s.manager.sendWithConfig(ctx, s.sender, s.config, item, policy); err != nil {
-			return err
-		}
-	}
-	return nil
+	return s.manager.sendOutbound(ctx, s.sender, s.config, msg)
 }
 
 func (s *managerReplySender) OpenStream(ctx context.Context, target string, opts StreamOptions) (OutboundStream, error) {
@@ -510,21 +653,49 @@ func (s *managerReplySender) OpenStream(ctx context.Context, target string, opts
 	if !caps.Streaming && !caps.BlockStreaming {
 		return nil, fmt.Errorf("channel does not support streaming")
 	}
-	stream, err := s.streamSender.OpenStream(ctx, s.config, target, opts)
+	policy := s.manager.resolveOutboundPolicy(s.channelType)
+	out := &managerOutboundStream{
+		manager:     s.manager,
+		channelType: s.channelType,
+		config:      s.config,
+		target:      target,
+		policy:      policy,
+		key:         outboundLimiterKey(s.config, target),
+		sent:        NewSentMessageRecorder(),
+	}
+	err := out.retry(ctx, "open stream", func(ctx context.Context) error {
+		stream, err := s.streamSender.OpenStream(ctx, s.config, target, opts)
+		if err != nil {
+			return err
+		}
+		out.stream = stream
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &managerOutboundStream{
-		manager:     s.manager,
-		stream:      stream,
-		channelType: s.channelType,
-	}, nil
+	return out, nil
 }
 
+// managerOutboundStream validates stream events and applies the outbound policy
+// of the channel. Opening and closing the stream and pushing final, error and
+// attachment events are paced and retried like one-shot sends. Other events pass
+// straight through: adapters throttle their own edits, and deltas accumulate, so
+// a failed delta is carried by the next one. Attachment events are pushed one
+// attachment at a time so a retry never repeats an attachment that was delivered.
 type managerOutboundStream struct {
 	manager     *Manager
 	stream      OutboundStream
 	channelType ChannelType
+	config      ChannelConfig
+	target      string
+	policy      OutboundPolicy
+	key         string
+	// sent collects the platform messages of the whole stream.
+	sent *SentMessageRecorder
+
+	mu       sync.Mutex
+	attempts int
 }
 
 func (s *managerOutboundStream) Push(ctx context.Context, event StreamEvent) error {
@@ -534,12 +705,67 @@ func (s *managerOutboundStream) Push(ctx context.Context, event StreamEvent) err
 	if err := validateStreamEvent(s.manager.registry, s.channelType, event); err != nil {
 		return err
 	}
-	return s.stream.Push(ctx, event)
+	switch event.Type {
+	case StreamEventAttachment:
+		for _, att := range event.Attachments {
+			single := event
+			single.Attachments = []Attachment{att}
+			if err := s.retry(ctx, "stream attachment", func(ctx context.Context) error {
+				return s.stream.Push(ctx, single)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	case StreamEventError:
+		return s.retry(ctx, "stream error", func(ctx context.Context) error {
+			return s.stream.Push(ctx, event)
+		})
+	case StreamEventFinal:
+		err := s.retry(ctx, "stream final", func(ctx context.Context) error {
+			return s.stream.Push(ctx, event)
+		})
+		s.recordFinal(ctx, event.Final.Message, err)
+		return err
+	default:
+		return s.stream.Push(ctx, event)
+	}
 }
 
 func (s *managerOutboundStream) Close(ctx context.Context) error {
 	if s.stream == nil {
 		return fmt.Errorf("stream is not configured")
 	}
-	return s.stream.Close(ctx)
+	return s.retry(ctx, "close stream", s.stream.Close)
+}
+
+// retry runs one stream operation through the outbound limiter and retry policy
+// and collects the platform messages it reports.
+func (s *managerOutboundStream) retry(ctx context.Context, op string, fn func(context.Context) error) error {
+	ctx, sent := withNestedSentMessageRecorder(ctx)
+	attempts, err := s.manager.retryOutbound(ctx, s.config, s.key, s.policy, op, fn)
+	for _, msg := range sent.Messages() {
+		s.sent.record(msg)
+	}
+	s.mu.Lock()
+	s.attempts += attempts
+	s.mu.Unlock()
+	return err
+}
+
+// recordFinal records the streamed reply as one delivery so a failed stream can be
+// resent like a one-shot reply.
+func (s *managerOutboundStream) recordFinal(ctx context.Context, msg Message, err error) {
+	if msg.IsEmpty() {
+		return
+	}
+	s.mu.Lock()
+	result := outboundResult{total: 1, attempts: s.attempts, err: err}
+	s.attempts = 0
+	s.mu.Unlock()
+	if err == nil {
+		result.sent = 1
+	}
+	result.messageIDs = s.sent.MessageIDs()
+	s.manager.recordDelivery(ctx, s.config, OutboundMessage{Target: s.target, Message: msg}, result)
 }
@@ -0,0 +1,260 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// outboundMaxBackoff caps the delay between retries of one outbound message.
+	outboundMaxBackoff = 30 * time.Second
+	// outboundMaxRetryAfter is the longest platform throttle a send waits for
+	// before it fails and is left for a manual resend.
+	outboundMaxRetryAfter = 2 * time.Minute
+	// outboundLimiterIdle is how long an unused per-target limiter is kept.
+	outboundLimiterIdle = 30 * time.Minute
+)
+
+// ErrOutboundDeliveryNotFound indicates the requested delivery record does not exist.
+var ErrOutboundDeliveryNotFound = errors.New("outbound delivery not found")
+
+// ErrOutboundDeliveryNotFailed is returned when resending a delivery that already succeeded.
+var ErrOutboundDeliveryNotFailed = errors.New("outbound delivery did not fail")
+
+// ErrOutboundDeliveriesUnsupported is returned when no delivery store is configured.
+var ErrOutboundDeliveriesUnsupported = errors.New("outbound delivery records not supported")
+
+// RateLimitError reports that the platform throttled a request. Adapters wrap
+// throttling errors with it so the manager can honor the retry-after hint.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+// NewRateLimitError wraps err with the delay the platform asked for.
+func NewRateLimitError(err error, retryAfter time.Duration) *RateLimitError {
+	return &RateLimitError{RetryAfter: retryAfter, Err: err}
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("rate limited, retry after %s: %v", e.RetryAfter, e.Err)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfterHint returns the retry-after delay carried by err, or zero.
+func RetryAfterHint(err error) time.Duration {
+	var rl *RateLimitError
+	if errors.As(err, &rl) && rl.RetryAfter > 0 {
+		return rl.RetryAfter
+	}
+	return 0
+}
+
+// SendProgress tracks which attachments of one outbound message were delivered, so
+// a retry of the message can skip them. It is safe for concurrent use.
+type SendProgress struct {
+	mu         sync.Mutex
+	delivered  map[int]bool
+	deliveryID string
+}
+
+// DeliveryID returns an identifier that is the same for every attempt of the
+// message, so receivers can drop duplicates of a retried request. It is empty
+// for a nil SendProgress.
+func (p *SendProgress) DeliveryID() string {
+	if p == nil {
+		return ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.deliveryID == "" {
+		p.deliveryID = uuid.NewString()
+	}
+	return p.deliveryID
+}
+
+// AttachmentSent reports whether the attachment at index was delivered by an
+// earlier attempt.
+func (p *SendProgress) AttachmentSent(index int) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.delivered[index]
+}
+
+// MarkAttachmentSent records that the attachment at index was delivered.
+func (p *SendProgress) MarkAttachmentSent(index int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.delivered == nil {
+		p.delivered = map[int]bool{}
+	}
+	p.delivered[index] = true
+}
+
+type sendProgressKey struct{}
+
+// WithSendProgress attaches a new SendProgress to ctx for the attempts of one message.
+func WithSendProgress(ctx context.Context) (context.Context, *SendProgress) {
+	progress := &SendProgress{}
+	return context.WithValue(ctx, sendProgressKey{}, progress), progress
+}
+
+// SendProgressFromContext returns the progress of the message being sent. It is nil
+// outside the manager retry loop, and a nil SendProgress reports nothing as sent.
+func SendProgressFromContext(ctx context.Context) *SendProgress {
+	if ctx == nil {
+		return nil
+	}
+	progress, _ := ctx.Value(sendProgressKey{}).(*SendProgress)
+	return progress
+}
+
+// DeliveryStatus is the outcome of an outbound delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusSent   DeliveryStatus = "sent"
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// OutboundDelivery records how one outbound message was delivered. Messages are
+// split into chunks by the outbound policy; ChunksSent counts the delivered
+// prefix so a resend continues with the first undelivered chunk.
+type OutboundDelivery struct {
+	ID                 string         `json:"id"`
+	BotID              string         `json:"bot_id"`
+	ConfigID           string         `json:"config_id"`
+	ChannelType        ChannelType    `json:"channel_type"`
+	Target             string         `json:"target"`
+	Message            Message        `json:"message"`
+	Status             DeliveryStatus `json:"status"`
+	Attempts           int            `json:"attempts"`
+	ChunksTotal        int            `json:"chunks_total"`
+	ChunksSent         int            `json:"chunks_sent"`
+	PlatformMessageIDs []string       `json:"platform_message_ids,omitempty"`
+	LastError          string         `json:"last_error,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+// OutboundDeliveryStore persists delivery outcomes.
+type OutboundDeliveryStore interface {
+	RecordDelivery(ctx context.Context, delivery OutboundDelivery) error
+	UpdateDelivery(ctx context.Context, delivery OutboundDelivery) error
+	GetDelivery(ctx context.Context, botID, deliveryID string) (OutboundDelivery, error)
+	ListDeliveries(ctx context.Context, botID string, status DeliveryStatus, limit int) ([]OutboundDelivery, error)
+}
+
+// outboundLimiter paces sends per config and target and holds back a target
+// while the platform asked to retry later.
+type outboundLimiter struct {
+	mu      sync.Mutex
+	entries map[string]*outboundLimiterEntry
+	swept   time.Time
+}
+
+type outboundLimiterEntry struct {
+	limiter     *rate.Limiter
+	pausedUntil time.Time
+	lastUsed    time.Time
+}
+
+func newOutboundLimiter() *outboundLimiter {
+	return &outboundLimiter{entries: map[string]*outboundLimiterEntry{}}
+}
+
+func outboundLimiterKey(cfg ChannelConfig, target string) string {
+	return cfg.ChannelType.String() + ":" + cfg.ID + ":" + target
+}
+
+func (l *outboundLimiter) entry(key string, policy OutboundPolicy) *outboundLimiterEntry {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.swept) > outboundLimiterIdle {
+		for k, e := range l.entries {
+			if now.Sub(e.lastUsed) > outboundLimiterIdle {
+				delete(l.entries, k)
+			}
+		}
+		l.swept = now
+	}
+	e, ok := l.entries[key]
+	if !ok {
+		limit := rate.Inf
+		if policy.RateLimitPerSecond > 0 {
+			limit = rate.Limit(policy.RateLimitPerSecond)
+		}
+		e = &outboundLimiterEntry{limiter: rate.NewLimiter(limit, max(policy.RateLimitBurst, 1))}
+		l.entries[key] = e
+	}
+	e.lastUsed = now
+	return e
+}
+
+// wait blocks until a send to key is allowed.
+func (l *outboundLimiter) wait(ctx context.Context, key string, policy OutboundPolicy) error {
+	e := l.entry(key, policy)
+	l.mu.Lock()
+	paused := time.Until(e.pausedUntil)
+	l.mu.Unlock()
+	if paused > 0 {
+		if err := sleepContext(ctx, paused); err != nil {
+			return err
+		}
+	}
+	return e.limiter.Wait(ctx)
+}
+
+// pause holds back all sends to key for d.
+func (l *outboundLimiter) pause(key string, policy OutboundPolicy, d time.Duration) {
+	e := l.entry(key, policy)
+	until := time.Now().Add(d)
+	l.mu.Lock()
+	if until.After(e.pausedUntil) {
+		e.pausedUntil = until
+	}
+	l.mu.Unlock()
+}
+
+// outboundRetryDelay returns how long to wait before the next attempt. Platform
+// hints win over the policy backoff.
+func outboundRetryDelay(policy OutboundPolicy, attempt int, err error) time.Duration {
+	if hint := RetryAfterHint(err); hint > 0 {
+		return hint
+	}
+	delay := time.Duration(policy.RetryBackoffMs) * time.Millisecond
+	for i := 1; i < attempt && delay < outboundMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboundMaxBackoff)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,188 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	// outboundDeliveryRetention keeps successful deliveries for inspection; failed ones stay until resent.
+	outboundDeliveryRetention     = 7 * 24 * time.Hour
+	outboundDeliveryPruneInterval = time.Hour
+)
+
+// outboundDeliveryQueries is the subset of sqlc queries used by OutboundDeliveryDBStore.
+type outboundDeliveryQueries interface {
+	InsertChannelOutboundDelivery(ctx context.Context, arg sqlc.InsertChannelOutboundDeliveryParams) (sqlc.ChannelOutboundDelivery, error)
+	UpdateChannelOutboundDelivery(ctx context.Context, arg sqlc.UpdateChannelOutboundDeliveryParams) error
+	GetChannelOutboundDelivery(ctx context.Context, arg sqlc.GetChannelOutboundDeliveryParams) (sqlc.ChannelOutboundDelivery, error)
+	ListChannelOutboundDeliveries(ctx context.Context, arg sqlc.ListChannelOutboundDeliveriesParams) ([]sqlc.ChannelOutboundDelivery, error)
+	DeleteSentChannelOutboundDeliveries(ctx context.Context, before pgtype.Timestamptz) error
+}
+
+// OutboundDeliveryDBStore persists outbound delivery outcomes in Postgres.
+type OutboundDeliveryDBStore struct {
+	queries    outboundDeliveryQueries
+	logger     *slog.Logger
+	pruneMu    sync.Mutex
+	lastPruned time.Time
+}
+
+// NewOutboundDeliveryDBStore creates a Postgres-backed OutboundDeliveryStore.
+func NewOutboundDeliveryDBStore(log *slog.Logger, queries *sqlc.Queries) *OutboundDeliveryDBStore {
+	return newOutboundDeliveryDBStore(log, queries)
+}
+
+func newOutboundDeliveryDBStore(log *slog.Logger, queries outboundDeliveryQueries) *OutboundDeliveryDBStore {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &OutboundDeliveryDBStore{
+		queries: queries,
+		logger:  log.With(slog.String("component", "outbound_deliveries")),
+	}
+}
+
+// RecordDelivery stores the outcome of a new outbound message.
+func (s *OutboundDeliveryDBStore) RecordDelivery(ctx context.Context, delivery OutboundDelivery) error {
+	botUUID, err := db.ParseUUID(delivery.BotID)
+	if err != nil {
+		return fmt.Errorf("outbound delivery bot id: %w", err)
+	}
+	payload, err := json.Marshal(delivery.Message)
+	if err != nil {
+		return fmt.Errorf("encode outbound delivery: %w", err)
+	}
+	s.pruneSent(ctx)
+	_, err = s.queries.InsertChannelOutboundDelivery(ctx, sqlc.InsertChannelOutboundDeliveryParams{
+		BotID:              botUUID,
+		ChannelConfigID:    delivery.ConfigID,
+		ChannelType:        delivery.ChannelType.String(),
+		Target:             delivery.Target,
+		Payload:            payload,
+		Status:             string(delivery.Status),
+		Attempts:           int32(delivery.Attempts),
+		ChunksTotal:        int32(delivery.ChunksTotal),
+		ChunksSent:         int32(delivery.ChunksSent),
+		LastError:          TruncateText(delivery.LastError, StoredErrorMaxLen),
+		PlatformMessageIds: deliveryMessageIDs(delivery.PlatformMessageIDs),
+	})
+	return err
+}
+
+// UpdateDelivery stores the outcome of a resend.
+func (s *OutboundDeliveryDBStore) UpdateDelivery(ctx context.Context, delivery OutboundDelivery) error {
+	id, err := db.ParseUUID(delivery.ID)
+	if err != nil {
+		return ErrOutboundDeliveryNotFound
+	}
+	return s.queries.UpdateChannelOutboundDelivery(ctx, sqlc.UpdateChannelOutboundDeliveryParams{
+		ID:                 id,
+		Status:             string(delivery.Status),
+		Attempts:           int32(delivery.Attempts),
+		ChunksSent:         int32(delivery.ChunksSent),
+		LastError:          TruncateText(delivery.LastError, StoredErrorMaxLen),
+		PlatformMessageIds: deliveryMessageIDs(delivery.PlatformMessageIDs),
+	})
+}
+
+// GetDelivery returns one delivery of a bot.
+func (s *OutboundDeliveryDBStore) GetDelivery(ctx context.Context, botID, deliveryID string) (OutboundDelivery, error) {
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return OutboundDelivery{}, err
+	}
+	id, err := db.ParseUUID(deliveryID)
+	if err != nil {
+		return OutboundDelivery{}, ErrOutboundDeliveryNotFound
+	}
+	row, err := s.queries.GetChannelOutboundDelivery(ctx, sqlc.GetChannelOutboundDeliveryParams{ID: id, BotID: botUUID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OutboundDelivery{}, ErrOutboundDeliveryNotFound
+	}
+	if err != nil {
+		return OutboundDelivery{}, err
+	}
+	return outboundDeliveryFromRow(row), nil
+}
+
+// ListDeliveries returns the most recent deliveries of a bot. An empty status lists all.
+func (s *OutboundDeliveryDBStore) ListDeliveries(ctx context.Context, botID string, status DeliveryStatus, limit int) ([]OutboundDelivery, error) {
+	botUUID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	rows, err := s.queries.ListChannelOutboundDeliveries(ctx, sqlc.ListChannelOutboundDeliveriesParams{
+		BotID:    botUUID,
+		Status:   string(status),
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]OutboundDelivery, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, outboundDeliveryFromRow(row))
+	}
+	return items, nil
+}
+
+func (s *OutboundDeliveryDBStore) pruneSent(ctx context.Context) {
+	now := time.Now()
+	s.pruneMu.Lock()
+	if now.Sub(s.lastPruned) < outboundDeliveryPruneInterval {
+		s.pruneMu.Unlock()
+		return
+	}
+	s.lastPruned = now
+	s.pruneMu.Unlock()
+	before := pgtype.Timestamptz{Time: now.Add(-outboundDeliveryRetention), Valid: true}
+	if err := s.queries.DeleteSentChannelOutboundDeliveries(ctx, before); err != nil {
+		s.logger.Warn("prune outbound deliveries failed", slog.Any("error", err))
+	}
+}
+
+func outboundDeliveryFromRow(row sqlc.ChannelOutboundDelivery) OutboundDelivery {
+	delivery := OutboundDelivery{
+		ID:          row.ID.String(),
+		BotID:       row.BotID.String(),
+		ConfigID:    row.ChannelConfigID,
+		ChannelType: ChannelType(row.ChannelType),
+		Target:      row.Target,
+		Status:      DeliveryStatus(row.Status),
+		Attempts:    int(row.Attempts),
+		ChunksTotal: int(row.ChunksTotal),
+		ChunksSent:  int(row.ChunksSent),
+		LastError:   row.LastError,
+		CreatedAt:   db.TimeFromPg(row.CreatedAt),
+		UpdatedAt:   db.TimeFromPg(row.UpdatedAt),
+	}
+	_ = json.Unmarshal(row.Payload, &delivery.Message)
+	_ = json.Unmarshal(row.PlatformMessageIds, &delivery.PlatformMessageIDs)
+	return delivery
+}
+
+func deliveryMessageIDs(ids []string) []byte {
+	if len(ids) == 0 {
+		return []byte("[]")
+	}
+	encoded, err := json.Marshal(ids)
+	if err != nil {
+		return []byte("[]")
+	}
+	return encoded
+}
@@ -0,0 +1,379 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakySender fails sends according to failFn and records delivered texts.
+type flakySender struct {
+	mu     sync.Mutex
+	calls  int
+	texts  []string
+	policy OutboundPolicy
+	failFn func(call int, msg OutboundMessage) error
+}
+
+func (f *flakySender) Type() ChannelType { return ChannelType("flaky") }
+
+func (f *flakySender) Descriptor() Descriptor {
+	return Descriptor{
+		Type:           ChannelType("flaky"),
+		DisplayName:    "Flaky",
+		Capabilities:   ChannelCapabilities{Text: true},
+		OutboundPolicy: f.policy,
+	}
+}
+
+func (f *flakySender) Send(ctx context.Context, cfg ChannelConfig, msg OutboundMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.failFn != nil {
+		if err := f.failFn(f.calls, msg); err != nil {
+			return err
+		}
+	}
+	f.texts = append(f.texts, msg.Message.Text)
+	ReportSentMessage(ctx, msg.Target, fmt.Sprintf("m-%d", f.calls))
+	return nil
+}
+
+type memoryDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries []OutboundDelivery
+}
+
+func (s *memoryDeliveryStore) RecordDelivery(_ context.Context, delivery OutboundDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery.ID = fmt.Sprintf("d-%d", len(s.deliveries)+1)
+	s.deliveries = append(s.deliveries, delivery)
+	return nil
+}
+
+func (s *memoryDeliveryStore) UpdateDelivery(_ context.Context, delivery OutboundDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.deliveries {
+		if s.deliveries[i].ID == delivery.ID {
+			s.deliveries[i] = delivery
+			return nil
+		}
+	}
+	return ErrOutboundDeliveryNotFound
+}
+
+func (s *memoryDeliveryStore) GetDelivery(_ context.Context, botID, deliveryID string) (OutboundDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.deliveries {
+		if d.ID == deliveryID && d.BotID == botID {
+			return d, nil
+		}
+	}
+	return OutboundDelivery{}, ErrOutboundDeliveryNotFound
+}
+
+func (s *memoryDeliveryStore) ListDeliveries(_ context.Context, botID string, status DeliveryStatus, _ int) ([]OutboundDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var items []OutboundDelivery
+	for _, d := range s.deliveries {
+		if d.BotID == botID && (status == "" || d.Status == status) {
+			items = append(items, d)
+		}
+	}
+	return items, nil
+}
+
+func newFlakyManager(sender *flakySender, store OutboundDeliveryStore) *Manager {
+	reg := NewRegistry()
+	reg.MustRegister(sender)
+	cfgStore := &fakeConfigStore{effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "flaky"}}
+	manager := NewManager(nil, reg, cfgStore, &fakeInboundProcessor{})
+	manager.SetOutboundDeliveryStore(store)
+	return manager
+}
+
+func TestRetryAfterHint(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("send: %w", NewRateLimitError(errors.New("429"), 3*time.Second))
+	if got := RetryAfterHint(err); got != 3*time.Second {
+		t.Fatalf("unexpected hint: %s", got)
+	}
+	if RetryAfterHint(errors.New("boom")) != 0 {
+		t.Fatal("plain errors carry no hint")
+	}
+	policy := OutboundPolicy{RetryBackoffMs: 100}
+	if got := outboundRetryDelay(policy, 1, err); got != 3*time.Second {
+		t.Fatalf("hint must win over backoff, got %s", got)
+	}
+	if got := outboundRetryDelay(policy, 3, errors.New("boom")); got != 400*time.Millisecond {
+		t.Fatalf("unexpected backoff: %s", got)
+	}
+	if got := outboundRetryDelay(policy, 20, errors.New("boom")); got != outboundMaxBackoff {
+		t.Fatalf("backoff must be capped, got %s", got)
+	}
+}
+
+func TestManagerSendHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakySender{
+		policy: OutboundPolicy{RetryMax: 3, RetryBackoffMs: 1},
+		failFn: func(call int, _ OutboundMessage) error {
+			if call == 1 {
+				return NewRateLimitError(errors.New("too many requests"), 80*time.Millisecond)
+			}
+			return nil
+		},
+	}
+	store := &memoryDeliveryStore{}
+	manager := newFlakyManager(sender, store)
+
+	start := time.Now()
+	recorder := NewSentMessageRecorder()
+	err := manager.Send(WithSentMessageRecorder(context.Background(), recorder), "bot-1", "flaky", SendRequest{Target: "chat-1", Message: Message{Text: "hello"}})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if ids := recorder.MessageIDs(); len(ids) != 1 || ids[0] != "m-2" {
+		t.Fatalf("caller must still see sent message ids, got %v", ids)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("retry did not wait for retry-after: %s", elapsed)
+	}
+	if len(store.deliveries) != 1 || store.deliveries[0].Status != DeliveryStatusSent || store.deliveries[0].Attempts != 2 {
+		t.Fatalf("unexpected delivery record: %+v", store.deliveries)
+	}
+	if ids := store.deliveries[0].PlatformMessageIDs; len(ids) != 1 || ids[0] != "m-2" {
+		t.Fatalf("expected delivered message ids on the record, got %v", ids)
+	}
+}
+
+func TestManagerSendRecordsFailureAndResendsRemainingChunks(t *testing.T) {
+	t.Parallel()
+
+	failing := true
+	sender := &flakySender{
+		policy: OutboundPolicy{TextChunkLimit: 5, RetryMax: 2, RetryBackoffMs: 1},
+		failFn: func(_ int, msg OutboundMessage) error {
+			if failing && msg.Message.Text == "bbbbb" {
+				return errors.New("network down")
+			}
+			return nil
+		},
+	}
+	store := &memoryDeliveryStore{}
+	manager := newFlakyManager(sender, store)
+
+	err := manager.Send(context.Background(), "bot-1", "flaky", SendRequest{Target: "chat-1", Message: Message{Text: "aaaaa\nbbbbb\nccccc"}})
+	if err == nil {
+		t.Fatal("expected send to fail")
+	}
+	if len(sender.texts) != 1 || sender.texts[0] != "aaaaa" {
+		t.Fatalf("later chunks must not be sent after a failure: %v", sender.texts)
+	}
+	failed, err := manager.ListOutboundDeliveries(context.Background(), "bot-1", DeliveryStatusFailed, 10)
+	if err != nil || len(failed) != 1 {
+		t.Fatalf("expected one failed delivery, got %v %v", failed, err)
+	}
+	record := failed[0]
+	if record.ChunksTotal != 3 || record.ChunksSent != 1 || record.Attempts != 3 || record.LastError == "" || fmt.Sprint(record.PlatformMessageIDs) != "[m-1]" {
+		t.Fatalf("unexpected failed record: %+v", record)
+	}
+
+	failing = false
+	resent, err := manager.ResendOutboundDelivery(context.Background(), "bot-1", record.ID)
+	if err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	if resent.Status != DeliveryStatusSent || resent.ChunksSent != 3 || resent.LastError != "" || fmt.Sprint(resent.PlatformMessageIDs) != "[m-1 m-4 m-5]" {
+		t.Fatalf("unexpected resent record: %+v", resent)
+	}
+	if fmt.Sprint(sender.texts) != "[aaaaa bbbbb ccccc]" {
+		t.Fatalf("resend must continue with the first undelivered chunk: %v", sender.texts)
+	}
+	if _, err := manager.ResendOutboundDelivery(context.Background(), "bot-1", record.ID); !errors.Is(err, ErrOutboundDeliveryNotFailed) {
+		t.Fatalf("expected ErrOutboundDeliveryNotFailed, got %v", err)
+	}
+}
+
+func TestOutboundLimiterPacesSends(t *testing.T) {
+	t.Parallel()
+
+	limiter := newOutboundLimiter()
+	policy := OutboundPolicy{RateLimitPerSecond: 20, RateLimitBurst: 1}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.wait(context.Background(), "k", policy); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("expected pacing, took %s", elapsed)
+	}
+
+	limiter.pause("k", policy, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx, "k", policy); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("paused key must block, got %v", err)
+	}
+	if err := limiter.wait(context.Background(), "other", policy); err != nil {
+		t.Fatalf("other keys must not be paused: %v", err)
+	}
+}
+
+// flakyMediaSender delivers attachments one at a time like adapters that send a
+// platform message per attachment, and streams through flakyStream.
+type flakyMediaSender struct {
+	mu         sync.Mutex
+	openFails  int
+	failOnce   map[string]bool
+	delivered  []string
+	streamPush []StreamEventType
+}
+
+func (f *flakyMediaSender) Type() ChannelType { return ChannelType("flaky-media") }
+
+func (f *flakyMediaSender) Descriptor() Descriptor {
+	return Descriptor{
+		Type:           ChannelType("flaky-media"),
+		DisplayName:    "Flaky media",
+		Capabilities:   ChannelCapabilities{Text: true, Attachments: true, Streaming: true},
+		OutboundPolicy: OutboundPolicy{RetryMax: 3, RetryBackoffMs: 1},
+	}
+}
+
+// deliver fails the first attempt of every URL listed in failOnce.
+func (f *flakyMediaSender) deliver(ctx context.Context, target string, att Attachment) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failOnce[att.URL] {
+		f.failOnce[att.URL] = false
+		return NewRateLimitError(errors.New("too many requests"), time.Millisecond)
+	}
+	f.delivered = append(f.delivered, att.URL)
+	ReportSentMessage(ctx, target, att.URL)
+	return nil
+}
+
+func (f *flakyMediaSender) Send(ctx context.Context, _ ChannelConfig, msg OutboundMessage) error {
+	progress := SendProgressFromContext(ctx)
+	for i, att := range msg.Message.Attachments {
+		if progress.AttachmentSent(i) {
+			continue
+		}
+		if err := f.deliver(ctx, msg.Target, att); err != nil {
+			return err
+		}
+		progress.MarkAttachmentSent(i)
+	}
+	return nil
+}
+
+func (f *flakyMediaSender) OpenStream(_ context.Context, _ ChannelConfig, target string, _ StreamOptions) (OutboundStream, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.openFails > 0 {
+		f.openFails--
+		return nil, errors.New("connection reset")
+	}
+	return &flakyStream{sender: f, target: target}, nil
+}
+
+type flakyStream struct {
+	sender *flakyMediaSender
+	target string
+}
+
+func (s *flakyStream) Push(ctx context.Context, event StreamEvent) error {
+	s.sender.mu.Lock()
+	s.sender.streamPush = append(s.sender.streamPush, event.Type)
+	s.sender.mu.Unlock()
+	switch event.Type {
+	case StreamEventAttachment:
+		for _, att := range event.Attachments {
+			if err := s.sender.deliver(ctx, s.target, att); err != nil {
+				return err
+			}
+		}
+	case StreamEventFinal:
+		return s.sender.deliver(ctx, s.target, Attachment{URL: "final"})
+	}
+	return nil
+}
+
+func (s *flakyStream) Close(context.Context) error { return nil }
+
+func newFlakyMediaManager(sender *flakyMediaSender, store OutboundDeliveryStore) *Manager {
+	reg := NewRegistry()
+	reg.MustRegister(sender)
+	cfgStore := &fakeConfigStore{effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "flaky-media"}}
+	manager := NewManager(nil, reg, cfgStore, &fakeInboundProcessor{})
+	manager.SetOutboundDeliveryStore(store)
+	return manager
+}
+
+func TestManagerSendRetriesOnlyUndeliveredAttachments(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakyMediaSender{failOnce: map[string]bool{"b": true}}
+	manager := newFlakyMediaManager(sender, &memoryDeliveryStore{})
+
+	err := manager.Send(context.Background(), "bot-1", "flaky-media", SendRequest{Target: "chat-1", Message: Message{
+		Attachments: []Attachment{{Type: AttachmentImage, URL: "a"}, {Type: AttachmentImage, URL: "b"}, {Type: AttachmentImage, URL: "c"}},
+	}})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if fmt.Sprint(sender.delivered) != "[a b c]" {
+		t.Fatalf("retry must not repeat delivered attachments: %v", sender.delivered)
+	}
+}
+
+func TestManagerStreamRetriesAndRecordsDelivery(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakyMediaSender{openFails: 1, failOnce: map[string]bool{"b": true, "final": true}}
+	store := &memoryDeliveryStore{}
+	manager := newFlakyMediaManager(sender, store)
+	reply := manager.newReplySender(ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "flaky-media"}, "flaky-media")
+
+	ctx := context.Background()
+	stream, err := reply.OpenStream(ctx, "chat-1", StreamOptions{})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	if err := stream.Push(ctx, StreamEvent{Type: StreamEventAttachment, Attachments: []Attachment{{Type: AttachmentImage, URL: "a"}, {Type: AttachmentImage, URL: "b"}}}); err != nil {
+		t.Fatalf("push attachments: %v", err)
+	}
+	if err := stream.Push(ctx, StreamEvent{Type: StreamEventFinal, Final: &StreamFinalizePayload{Message: Message{Text: "done"}}}); err != nil {
+		t.Fatalf("push final: %v", err)
+	}
+	if err := stream.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if fmt.Sprint(sender.delivered) != "[a b final]" {
+		t.Fatalf("retry must not repeat delivered attachments: %v", sender.delivered)
+	}
+	if len(store.deliveries) != 1 {
+		t.Fatalf("expected one delivery record, got %+v", store.deliveries)
+	}
+	record := store.deliveries[0]
+	// open 2, attachment a 1, attachment b 2, final 2
+	if record.Status != DeliveryStatusSent || record.Attempts != 7 || record.Message.Text != "done" {
+		t.Fatalf("unexpected delivery record: %+v", record)
+	}
+	if fmt.Sprint(record.PlatformMessageIDs) != "[a b final]" {
+		t.Fatalf("expected streamed message ids on the record, got %v", record.PlatformMessageIDs)
+	}
+}
@@ -0,0 +1,434 @@
+// Package outbox stores outbound messages scheduled for later and delivers them through
+// the channel manager once they are due.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+var (
+	// ErrInvalidRequest is returned when a schedule or list request fails validation.
+	ErrInvalidRequest = errors.New("invalid outbox request")
+	// ErrNotFound is returned when the message does not exist or belongs to another bot.
+	ErrNotFound = errors.New("scheduled message not found")
+	// ErrNotPending is returned when canceling a message that is already being sent or done.
+	ErrNotPending = errors.New("scheduled message is no longer pending")
+)
+
+const (
+	// dispatchLease is how long a claimed message stays invisible to other dispatchers.
+	// Messages of a crashed dispatcher become claimable again once the lease expires.
+	dispatchLease        = 5 * time.Minute
+	dispatchPollInterval = 5 * time.Second
+	maxAttempts          = 5
+	retryBaseDelay       = 30 * time.Second
+	retryMaxDelay        = 30 * time.Minute
+	// maxHorizon bounds how far ahead a message can be scheduled.
+	maxHorizon = 366 * 24 * time.Hour
+	// pastTolerance accepts send times slightly in the past, e.g. "now" sent with clock skew.
+	pastTolerance = time.Minute
+
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// localLayouts are the date-time formats read in the bot's timezone.
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// messageSender delivers due messages; implemented by channel.Manager.
+type messageSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// channelTypeResolver validates platform names; implemented by channel.Registry.
+type channelTypeResolver interface {
+	ParseChannelType(raw string) (channel.ChannelType, error)
+}
+
+// settingsReader provides the bot timezone.
+type settingsReader interface {
+	GetBot(ctx context.Context, botID string) (settings.Settings, error)
+}
+
+// Service schedules outbound messages and dispatches them when due.
+type Service struct {
+	queries  *sqlc.Queries
+	sender   messageSender
+	resolver channelTypeResolver
+	settings settingsReader
+	wake     chan struct{}
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewService creates an outbox service. Call Start to run the dispatcher.
+func NewService(log *slog.Logger, queries *sqlc.Queries, sender messageSender, resolver channelTypeResolver, settingsService settingsReader) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		queries:  queries,
+		sender:   sender,
+		resolver: resolver,
+		settings: settingsService,
+		wake:     make(chan struct{}, 1),
+		now:      time.Now,
+		logger:   log.With(slog.String("service", "outbox")),
+	}
+}
+
+// Schedule queues a message for delivery at the requested time.
+func (s *Service) Schedule(ctx context.Context, botID string, source Source, createdBy string, req ScheduleRequest) (Message, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Message{}, err
+	}
+	if s.resolver == nil {
+		return Message{}, errors.New("channel type resolver not configured")
+	}
+	channelType, err := s.resolver.ParseChannelType(req.Platform)
+	if err != nil {
+		return Message{}, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+	target := strings.TrimSpace(req.Target)
+	if target == "" {
+		return Message{}, fmt.Errorf("%w: target is required", ErrInvalidRequest)
+	}
+	if req.Message.IsEmpty() {
+		return Message{}, fmt.Errorf("%w: message is required", ErrInvalidRequest)
+	}
+	sendAt, err := ResolveSendAt(req.SendAt, req.Delay, s.Location(ctx, botID), s.now())
+	if err != nil {
+		return Message{}, err
+	}
+	payload, err := json.Marshal(req.Message)
+	if err != nil {
+		return Message{}, fmt.Errorf("encode message: %w", err)
+	}
+	row, err := s.queries.CreateChannelOutboxMessage(ctx, sqlc.CreateChannelOutboxMessageParams{
+		BotID:       pgBotID,
+		ChannelType: channelType.String(),
+		Target:      target,
+		Payload:     payload,
+		Source:      string(source),
+		CreatedBy:   strings.TrimSpace(createdBy),
+		SendAt:      pgtype.Timestamptz{Time: sendAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	s.notify()
+	return toMessage(row), nil
+}
+
+// Get returns a scheduled message of a bot.
+func (s *Service) Get(ctx context.Context, botID, id string) (Message, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Message{}, err
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return Message{}, ErrNotFound
+	}
+	row, err := s.queries.GetChannelOutboxMessage(ctx, sqlc.GetChannelOutboxMessageParams{ID: pgID, BotID: pgBotID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Message{}, ErrNotFound
+		}
+		return Message{}, err
+	}
+	return toMessage(row), nil
+}
+
+// List returns the scheduled messages of a bot, earliest send time first.
+func (s *Service) List(ctx context.Context, botID string, req ListRequest) ([]Message, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	status := pgtype.Text{}
+	if value := Status(strings.TrimSpace(string(req.Status))); value != "" {
+		if !value.valid() {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidRequest, value)
+		}
+		status = pgtype.Text{String: string(value), Valid: true}
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxListLimit {
+		limit = defaultListLimit
+	}
+	rows, err := s.queries.ListChannelOutboxMessages(ctx, sqlc.ListChannelOutboxMessagesParams{
+		BotID:    pgBotID,
+		Status:   status,
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Message, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, toMessage(row))
+	}
+	return items, nil
+}
+
+// Cancel stops a pending message from being sent.
+func (s *Service) Cancel(ctx context.Context, botID, id string) error {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return err
+	}
+	pgID, err := db.ParseUUID(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	affected, err := s.queries.CancelChannelOutboxMessage(ctx, sqlc.CancelChannelOutboxMessageParams{ID: pgID, BotID: pgBotID})
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	if _, err := s.Get(ctx, botID, id); err != nil {
+		return err
+	}
+	return ErrNotPending
+}
+
+// Location returns the bot's timezone, falling back to UTC when it is unset or unknown.
+func (s *Service) Location(ctx context.Context, botID string) *time.Location {
+	if s.settings == nil {
+		return time.UTC
+	}
+	botSettings, err := s.settings.GetBot(ctx, botID)
+	if err != nil {
+		s.logger.Warn("load bot timezone failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(botSettings.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Start runs the dispatcher until ctx is canceled. Messages left mid-delivery by a
+// previous process are picked up again once their lease expires, so delivery is
+// at-least-once.
+func (s *Service) Start(ctx context.Context) {
+	if s.queries == nil || s.sender == nil {
+		s.logger.Warn("outbox dispatcher not configured")
+		return
+	}
+	go s.run(ctx)
+}
+
+func (s *Service) run(ctx context.Context) {
+	ticker := time.NewTicker(dispatchPollInterval)
+	defer ticker.Stop()
+	for {
+		row, err := s.queries.ClaimChannelOutboxMessage(ctx, pgtype.Timestamptz{Time: s.now().Add(dispatchLease), Valid: true})
+		if err == nil {
+			s.deliver(ctx, row)
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("claim scheduled message failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) deliver(ctx context.Context, row sqlc.ChannelOutboxMessage) {
+	logger := s.logger.With(slog.String("message_id", row.ID.String()), slog.String("bot_id", row.BotID.String()))
+	var msg channel.Message
+	if err := json.Unmarshal(row.Payload, &msg); err != nil {
+		s.fail(ctx, logger, row, fmt.Errorf("decode message: %w", err))
+		return
+	}
+	recorder := channel.NewSentMessageRecorder()
+	err := s.sender.Send(channel.WithSentMessageRecorder(ctx, recorder), row.BotID.String(), channel.ChannelType(row.ChannelType), channel.SendRequest{
+		Target:  row.Target,
+		Message: msg,
+	})
+	if ctx.Err() != nil {
+		// Shutting down: the lease expires and the message is retried after restart.
+		return
+	}
+	if err != nil {
+		if int(row.Attempts) >= maxAttempts {
+			s.fail(ctx, logger, row, err)
+			return
+		}
+		delay := retryDelay(int(row.Attempts))
+		logger.Warn("scheduled message delivery failed, retrying", slog.Duration("delay", delay), slog.Any("error", err))
+		if err := s.queries.RetryChannelOutboxMessage(ctx, sqlc.RetryChannelOutboxMessageParams{
+			AvailableAt: pgtype.Timestamptz{Time: s.now().Add(delay).UTC(), Valid: true},
+			LastError:   channel.TruncateText(err.Error(), channel.StoredErrorMaxLen),
+			ID:          row.ID,
+		}); err != nil {
+			logger.Warn("reschedule message failed", slog.Any("error", err))
+		}
+		return
+	}
+	ids, err := json.Marshal(recorder.MessageIDs())
+	if err != nil {
+		ids = []byte("[]")
+	}
+	if err := s.queries.CompleteChannelOutboxMessage(ctx, sqlc.CompleteChannelOutboxMessageParams{
+		PlatformMessageIds: ids,
+		ID:                 row.ID,
+	}); err != nil {
+		logger.Warn("mark scheduled message sent failed", slog.Any("error", err))
+	}
+}
+
+func (s *Service) fail(ctx context.Context, logger *slog.Logger, row sqlc.ChannelOutboxMessage, cause error) {
+	logger.Warn("scheduled message failed", slog.Int("attempts", int(row.Attempts)), slog.Any("error", cause))
+	if err := s.queries.FailChannelOutboxMessage(ctx, sqlc.FailChannelOutboxMessageParams{
+		LastError: channel.TruncateText(cause.Error(), channel.StoredErrorMaxLen),
+		ID:        row.ID,
+	}); err != nil {
+		logger.Warn("mark scheduled message failed failed", slog.Any("error", err))
+	}
+}
+
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ResolveSendAt turns a send_at or delay value into an absolute time. Local date-times
+// and times of day are read in loc; a time of day that has already passed today means
+// tomorrow. With neither value set, the message is due now.
+func ResolveSendAt(sendAt, delay string, loc *time.Location, now time.Time) (time.Time, error) {
+	sendAt = strings.TrimSpace(sendAt)
+	delay = strings.TrimSpace(delay)
+	if loc == nil {
+		loc = time.UTC
+	}
+	var at time.Time
+	switch {
+	case sendAt != "" && delay != "":
+		return time.Time{}, fmt.Errorf("%w: set either send_at or delay, not both", ErrInvalidRequest)
+	case delay != "":
+		d, err := time.ParseDuration(delay)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("%w: delay must be a positive duration such as 30m or 2h", ErrInvalidRequest)
+		}
+		at = now.Add(d)
+	case sendAt != "":
+		parsed, err := parseSendAt(sendAt, loc, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		at = parsed
+	default:
+		return now, nil
+	}
+	if at.Before(now.Add(-pastTolerance)) {
+		return time.Time{}, fmt.Errorf("%w: send_at %s is in the past", ErrInvalidRequest, at.In(loc).Format(time.RFC3339))
+	}
+	if at.After(now.Add(maxHorizon)) {
+		return time.Time{}, fmt.Errorf("%w: send_at must be within a year", ErrInvalidRequest)
+	}
+	if at.Before(now) {
+		at = now
+	}
+	return at, nil
+}
+
+func parseSendAt(value string, loc *time.Location, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		clock, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		local := now.In(loc)
+		at := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+		if !at.After(now) {
+			at = time.Date(local.Year(), local.Month(), local.Day()+1, clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+		}
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: send_at %q is not a time; use RFC 3339, \"2006-01-02 15:04\" or \"15:04\"", ErrInvalidRequest, value)
+}
+
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+func (st Status) valid() bool {
+	switch st {
+	case StatusPending, StatusSending, StatusSent, StatusFailed, StatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+func toMessage(row sqlc.ChannelOutboxMessage) Message {
+	item := Message{
+		ID:        row.ID.String(),
+		BotID:     row.BotID.String(),
+		Platform:  row.ChannelType,
+		Target:    row.Target,
+		Source:    Source(row.Source),
+		CreatedBy: row.CreatedBy,
+		Status:    Status(row.Status),
+		SendAt:    db.TimeFromPg(row.SendAt),
+		Attempts:  int(row.Attempts),
+		LastError: row.LastError,
+		CreatedAt: db.TimeFromPg(row.CreatedAt),
+		UpdatedAt: db.TimeFromPg(row.UpdatedAt),
+	}
+	_ = json.Unmarshal(row.Payload, &item.Message)
+	_ = json.Unmarshal(row.PlatformMessageIds, &item.PlatformMessageIDs)
+	if row.SentAt.Valid {
+		sentAt := row.SentAt.Time
+		item.SentAt = &sentAt
+	}
+	return item
+}
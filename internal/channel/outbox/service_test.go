@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolveSendAt(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// 2026-03-10 09:30 in Tokyo.
+	now := time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		sendAt string
+		delay  string
+		want   time.Time
+	}{
+		{name: "empty is now", want: now},
+		{name: "delay", delay: "90m", want: now.Add(90 * time.Minute)},
+		{name: "rfc3339 keeps offset", sendAt: "2026-03-10T12:00:00+02:00", want: time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)},
+		{name: "local date time", sendAt: "2026-03-11 08:00", want: time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)},
+		{name: "time of day later today", sendAt: "18:00", want: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)},
+		{name: "time of day passed means tomorrow", sendAt: "08:00", want: time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)},
+		{name: "slightly past is now", sendAt: "2026-03-10T00:29:45Z", want: now},
+	}
+	for _, tc := range cases {
+		got, err := ResolveSendAt(tc.sendAt, tc.delay, tokyo, now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+
+	invalid := []struct {
+		name   string
+		sendAt string
+		delay  string
+	}{
+		{name: "both set", sendAt: "18:00", delay: "1h"},
+		{name: "negative delay", delay: "-5m"},
+		{name: "bad delay", delay: "soon"},
+		{name: "past", sendAt: "2026-03-09 10:00"},
+		{name: "too far", sendAt: "2028-01-01T00:00:00Z"},
+		{name: "garbage", sendAt: "next tuesday"},
+	}
+	for _, tc := range invalid {
+		if _, err := ResolveSendAt(tc.sendAt, tc.delay, tokyo, now); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("%s: expected ErrInvalidRequest, got %v", tc.name, err)
+		}
+	}
+}
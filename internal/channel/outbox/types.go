@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// Status is the delivery state of a scheduled message.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusSending  Status = "sending"
+	StatusSent     Status = "sent"
+	StatusFailed   Status = "failed"
+	StatusCanceled Status = "canceled"
+)
+
+// Source records who scheduled a message.
+type Source string
+
+const (
+	SourceAPI  Source = "api"
+	SourceTool Source = "tool"
+)
+
+// Message is a concrete outbound message waiting in, or delivered from, the outbox.
+type Message struct {
+	ID                 string          `json:"id"`
+	BotID              string          `json:"bot_id"`
+	Platform           string          `json:"platform"`
+	Target             string          `json:"target"`
+	Message            channel.Message `json:"message"`
+	Source             Source          `json:"source"`
+	CreatedBy          string          `json:"created_by,omitempty"`
+	Status             Status          `json:"status"`
+	SendAt             time.Time       `json:"send_at"`
+	Attempts           int             `json:"attempts"`
+	LastError          string          `json:"last_error,omitempty"`
+	PlatformMessageIDs []string        `json:"platform_message_ids,omitempty"`
+	SentAt             *time.Time      `json:"sent_at,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// ScheduleRequest queues a message to a channel target. SendAt is either an RFC 3339
+// timestamp, a local date and time ("2006-01-02 15:04") or a time of day ("15:04"),
+// both read in the bot's timezone. Delay is a duration such as "90m" counted from now.
+// At most one of them may be set; with neither the message is sent as soon as possible.
+type ScheduleRequest struct {
+	Platform string          `json:"platform"`
+	Target   string          `json:"target"`
+	Message  channel.Message `json:"message"`
+	SendAt   string          `json:"send_at,omitempty"`
+	Delay    string          `json:"delay,omitempty"`
+}
+
+// ListRequest filters scheduled messages. An empty status lists all of them.
+type ListRequest struct {
+	Status Status `json:"status,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// ListResponse wraps a list of scheduled messages.
+type ListResponse struct {
+	Items []Message `json:"items"`
+}
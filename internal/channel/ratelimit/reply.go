@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// replies holds the polite notices sent when a sender runs into a limit, by language.
+var replies = map[string]map[limitKind]string{
+	"en": {
+		limitMinute: "You're sending messages a little too fast. Please wait a minute and try again.",
+		limitDay:    "You've reached today's message limit for this bot. Please try again tomorrow.",
+	},
+	"zh": {
+		limitMinute: "你发送消息的速度有点快，请稍等一分钟后再试。",
+		limitDay:    "你今天与该机器人的对话次数已达上限，请明天再试。",
+	},
+	"ja": {
+		limitMinute: "メッセージの送信が少し速すぎます。1分ほど待ってから、もう一度お試しください。",
+		limitDay:    "本日のこのボットとのメッセージ上限に達しました。また明日お試しください。",
+	},
+	"ko": {
+		limitMinute: "메시지를 너무 빠르게 보내고 있어요. 1분 정도 기다린 후 다시 시도해 주세요.",
+		limitDay:    "오늘 이 봇과의 메시지 한도에 도달했어요. 내일 다시 시도해 주세요.",
+	},
+	"es": {
+		limitMinute: "Estás enviando mensajes demasiado rápido. Espera un minuto y vuelve a intentarlo.",
+		limitDay:    "Has alcanzado el límite diario de mensajes con este bot. Vuelve a intentarlo mañana.",
+	},
+	"fr": {
+		limitMinute: "Vous envoyez des messages un peu trop vite. Merci de patienter une minute avant de réessayer.",
+		limitDay:    "Vous avez atteint la limite de messages du jour pour ce bot. Merci de réessayer demain.",
+	},
+	"de": {
+		limitMinute: "Du sendest Nachrichten etwas zu schnell. Bitte warte eine Minute und versuche es dann erneut.",
+		limitDay:    "Du hast das heutige Nachrichtenlimit für diesen Bot erreicht. Bitte versuche es morgen erneut.",
+	},
+	"pt": {
+		limitMinute: "Você está enviando mensagens rápido demais. Aguarde um minuto e tente novamente.",
+		limitDay:    "Você atingiu o limite diário de mensagens com este bot. Tente novamente amanhã.",
+	},
+	"ru": {
+		limitMinute: "Вы отправляете сообщения слишком часто. Пожалуйста, подождите минуту и попробуйте снова.",
+		limitDay:    "Вы достигли дневного лимита сообщений для этого бота. Пожалуйста, попробуйте завтра.",
+	},
+}
+
+// languageNames maps language names a bot's language setting may hold to reply keys.
+var languageNames = map[string]string{
+	"english":    "en",
+	"chinese":    "zh",
+	"中文":         "zh",
+	"简体中文":       "zh",
+	"繁體中文":       "zh",
+	"japanese":   "ja",
+	"日本語":        "ja",
+	"korean":     "ko",
+	"한국어":        "ko",
+	"spanish":    "es",
+	"español":    "es",
+	"french":     "fr",
+	"français":   "fr",
+	"german":     "de",
+	"deutsch":    "de",
+	"portuguese": "pt",
+	"português":  "pt",
+	"russian":    "ru",
+	"русский":    "ru",
+}
+
+// replyText returns the notice for a limit in the bot's language. With the "auto"
+// language the notice follows the script of the sender's message.
+func replyText(language, text string, kind limitKind) string {
+	return replies[replyLanguage(language, text)][kind]
+}
+
+func replyLanguage(language, text string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if language != "" && language != "auto" {
+		if key, ok := languageNames[language]; ok {
+			return key
+		}
+		code, _, _ := strings.Cut(strings.ReplaceAll(language, "_", "-"), "-")
+		if _, ok := replies[code]; ok {
+			return code
+		}
+	}
+	return detectLanguage(text)
+}
+
+// detectLanguage guesses a reply language from the scripts used in a message.
+func detectLanguage(text string) string {
+	han := false
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return "ja"
+		case unicode.Is(unicode.Hangul, r):
+			return "ko"
+		case unicode.Is(unicode.Cyrillic, r):
+			return "ru"
+		case unicode.Is(unicode.Han, r):
+			han = true
+		}
+	}
+	if han {
+		return "zh"
+	}
+	return "en"
+}
@@ -0,0 +1,503 @@
+// Package ratelimit caps how often each sender may message a bot over its channels,
+// with per-role limits on messages per minute and answered turns per day, and keeps
+// daily per-sender counters for the owner.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/inbound"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/settings"
+)
+
+var (
+	// ErrInvalidPolicy is returned when a policy update fails validation.
+	ErrInvalidPolicy = errors.New("invalid rate limit policy")
+	// ErrInvalidRequest is returned when a usage query is malformed.
+	ErrInvalidRequest = errors.New("invalid rate limit request")
+)
+
+const (
+	defaultUsageLimit = 100
+	maxUsageLimit     = 1000
+	dayLayout         = "2006-01-02"
+)
+
+// ownerResolver returns the owner user of a bot.
+type ownerResolver interface {
+	BotOwnerUserID(ctx context.Context, botID string) (string, error)
+}
+
+// messageSender delivers limit notices; implemented by channel.Manager.
+type messageSender interface {
+	Send(ctx context.Context, botID string, channelType channel.ChannelType, req channel.SendRequest) error
+}
+
+// settingsReader provides the bot language and timezone.
+type settingsReader interface {
+	GetBot(ctx context.Context, botID string) (settings.Settings, error)
+}
+
+// Service stores rate limit policies and usage and enforces them on inbound messages.
+type Service struct {
+	queries  *sqlc.Queries
+	owners   ownerResolver
+	settings settingsReader
+	sender   messageSender
+	minute   *windowCounter
+	// notified remembers, per sender, the last day they were told about the daily
+	// limit so the notice is sent once.
+	notifyMu sync.Mutex
+	notified map[string]string
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewService creates a rate limit service.
+func NewService(log *slog.Logger, queries *sqlc.Queries, owners ownerResolver, settingsService settingsReader) *Service {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Service{
+		queries:  queries,
+		owners:   owners,
+		settings: settingsService,
+		minute:   newWindowCounter(time.Minute),
+		notified: make(map[string]string),
+		now:      time.Now,
+		logger:   log.With(slog.String("service", "ratelimit")),
+	}
+}
+
+// SetSender configures delivery of limit notices. It is set after construction because
+// the channel manager runs the rate limit middleware.
+func (s *Service) SetSender(sender messageSender) {
+	if s == nil {
+		return
+	}
+	s.sender = sender
+}
+
+// GetPolicy returns the rate limit policy of a bot. Bots without one get a disabled policy.
+func (s *Service) GetPolicy(ctx context.Context, botID string) (Policy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Policy{}, err
+	}
+	row, err := s.queries.GetChannelRateLimitPolicy(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Policy{}, nil
+		}
+		return Policy{}, err
+	}
+	var policy Policy
+	if len(row.Policy) > 0 {
+		if err := json.Unmarshal(row.Policy, &policy); err != nil {
+			return Policy{}, fmt.Errorf("decode rate limit policy: %w", err)
+		}
+	}
+	return policy, nil
+}
+
+// UpdatePolicy validates and stores the rate limit policy of a bot.
+func (s *Service) UpdatePolicy(ctx context.Context, botID string, policy Policy) (Policy, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return Policy{}, err
+	}
+	if err := policy.validate(); err != nil {
+		return Policy{}, fmt.Errorf("%w: %s", ErrInvalidPolicy, err.Error())
+	}
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return Policy{}, err
+	}
+	if _, err := s.queries.UpsertChannelRateLimitPolicy(ctx, sqlc.UpsertChannelRateLimitPolicyParams{
+		BotID:  pgBotID,
+		Policy: payload,
+	}); err != nil {
+		return Policy{}, err
+	}
+	return policy, nil
+}
+
+// Usage returns the per-sender counters of a bot for a day ("2006-01-02") in the bot's
+// timezone, or for today when day is empty.
+func (s *Service) Usage(ctx context.Context, botID, day string, limit int) (UsageResponse, error) {
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return UsageResponse{}, err
+	}
+	_, loc := s.botSettings(ctx, botID)
+	date := dayOf(s.now(), loc)
+	if day = strings.TrimSpace(day); day != "" {
+		date, err = time.Parse(dayLayout, day)
+		if err != nil {
+			return UsageResponse{}, fmt.Errorf("%w: day must look like %s", ErrInvalidRequest, dayLayout)
+		}
+	}
+	if limit <= 0 {
+		limit = defaultUsageLimit
+	}
+	if limit > maxUsageLimit {
+		limit = maxUsageLimit
+	}
+	policy, err := s.GetPolicy(ctx, botID)
+	if err != nil {
+		return UsageResponse{}, err
+	}
+	rows, err := s.queries.ListChannelRateUsage(ctx, sqlc.ListChannelRateUsageParams{
+		BotID:    pgBotID,
+		Day:      pgtype.Date{Time: date, Valid: true},
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return UsageResponse{}, err
+	}
+	items := make([]Usage, 0, len(rows))
+	for _, row := range rows {
+		role := Role(row.Role)
+		items = append(items, Usage{
+			ChannelIdentityID: row.ChannelIdentityID.String(),
+			ChannelType:       row.ChannelType,
+			DisplayName:       row.DisplayName,
+			Role:              role,
+			Messages:          int(row.Messages),
+			Turns:             int(row.Turns),
+			Limited:           int(row.Limited),
+			TurnsPerDay:       policy.limits(role).TurnsPerDay,
+			LastSeenAt:        db.TimeFromPg(row.LastSeenAt),
+		})
+	}
+	return UsageResponse{Day: date.Format(dayLayout), Timezone: loc.String(), Items: items}, nil
+}
+
+// quotaContextKey carries the sender's quota from the middleware to AllowTurn.
+type quotaContextKey struct{}
+
+// senderQuota is the limits that apply to one sender today.
+type senderQuota struct {
+	botID             string
+	channelIdentityID string
+	channelType       string
+	displayName       string
+	role              Role
+	limits            Limits
+	day               time.Time
+	language          string
+}
+
+// Middleware returns a channel middleware that counts messages per sender and drops
+// messages from senders over their per-minute limit, with a polite reply the first
+// time in a window. It must run after identity resolution, which it uses to tell the
+// owner, members and guests apart.
+func (s *Service) Middleware() channel.Middleware {
+	return func(next channel.InboundHandler) channel.InboundHandler {
+		return func(ctx context.Context, cfg channel.ChannelConfig, msg channel.InboundMessage) error {
+			if msg.EventType() != channel.InboundEventMessage && !msg.IsAction() {
+				return next(ctx, cfg, msg)
+			}
+			state, ok := inbound.IdentityStateFromContext(ctx)
+			if !ok || state.Decision != nil {
+				// Bind codes and other identity replies are handled before limits apply.
+				return next(ctx, cfg, msg)
+			}
+			if state.Identity.BotID == "" {
+				state.Identity.BotID = cfg.BotID
+			}
+			quota, ok := s.quota(ctx, state.Identity, msg)
+			if !ok {
+				return next(ctx, cfg, msg)
+			}
+			if s.limitMinute(ctx, quota, msg) {
+				return nil
+			}
+			return next(context.WithValue(ctx, quotaContextKey{}, quota), cfg, msg)
+		}
+	}
+}
+
+// quota loads the limits that apply to the sender of a message. It reports false when
+// the bot has no enabled policy or a lookup fails, which lets the message through.
+func (s *Service) quota(ctx context.Context, identity inbound.InboundIdentity, msg channel.InboundMessage) (senderQuota, bool) {
+	if s == nil || s.queries == nil {
+		return senderQuota{}, false
+	}
+	botID := strings.TrimSpace(identity.BotID)
+	channelIdentityID := strings.TrimSpace(identity.ChannelIdentityID)
+	if botID == "" || channelIdentityID == "" {
+		return senderQuota{}, false
+	}
+	policy, err := s.GetPolicy(ctx, botID)
+	if err != nil {
+		s.logger.Warn("load rate limit policy failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return senderQuota{}, false
+	}
+	if !policy.Enabled {
+		return senderQuota{}, false
+	}
+	role := s.resolveRole(ctx, botID, identity.UserID)
+	language, loc := s.botSettings(ctx, botID)
+	return senderQuota{
+		botID:             botID,
+		channelIdentityID: channelIdentityID,
+		channelType:       msg.Channel.String(),
+		displayName:       strings.TrimSpace(identity.DisplayName),
+		role:              role,
+		limits:            policy.limits(role),
+		day:               dayOf(s.now(), loc),
+		language:          language,
+	}, true
+}
+
+// limitMinute counts a message and reports whether it is over the per-minute limit.
+func (s *Service) limitMinute(ctx context.Context, quota senderQuota, msg channel.InboundMessage) bool {
+	if err := s.recordMessage(ctx, quota); err != nil {
+		s.logger.Warn("record rate usage failed", slog.String("bot_id", quota.botID), slog.Any("error", err))
+	}
+	exceeded, first := s.minute.record(quota.botID+":"+quota.channelIdentityID, quota.limits.MessagesPerMinute, s.now())
+	if !exceeded {
+		return false
+	}
+	s.logger.Info("sender over per-minute limit",
+		slog.String("bot_id", quota.botID),
+		slog.String("channel_identity_id", quota.channelIdentityID),
+		slog.String("role", string(quota.role)),
+	)
+	s.recordLimited(ctx, quota)
+	if first {
+		s.reply(ctx, quota.botID, msg, replyText(quota.language, msg.Message.PlainText(), limitMinute))
+	}
+	return true
+}
+
+// AllowTurn counts a message the bot is about to answer against the sender's daily
+// turn limit. When the limit is reached it returns false, with a polite reply the first
+// time that day.
+func (s *Service) AllowTurn(ctx context.Context, _ inbound.InboundIdentity, msg channel.InboundMessage) (channel.Message, bool) {
+	if s == nil || s.queries == nil || ctx == nil {
+		return channel.Message{}, true
+	}
+	quota, ok := ctx.Value(quotaContextKey{}).(senderQuota)
+	if !ok {
+		return channel.Message{}, true
+	}
+	pgBotID, err := db.ParseUUID(quota.botID)
+	if err != nil {
+		return channel.Message{}, true
+	}
+	pgIdentityID, err := db.ParseUUID(quota.channelIdentityID)
+	if err != nil {
+		return channel.Message{}, true
+	}
+	_, err = s.queries.RecordChannelRateTurn(ctx, sqlc.RecordChannelRateTurnParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+		Day:               pgtype.Date{Time: quota.day, Valid: true},
+		ChannelType:       quota.channelType,
+		DisplayName:       quota.displayName,
+		Role:              string(quota.role),
+		MaxTurns:          int32(quota.limits.TurnsPerDay),
+	})
+	if err == nil {
+		return channel.Message{}, true
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Warn("record rate turn failed", slog.String("bot_id", quota.botID), slog.Any("error", err))
+		return channel.Message{}, true
+	}
+	s.logger.Info("sender over daily turn limit",
+		slog.String("bot_id", quota.botID),
+		slog.String("channel_identity_id", quota.channelIdentityID),
+		slog.String("role", string(quota.role)),
+	)
+	s.recordLimited(ctx, quota)
+	if !s.firstNotice(quota) {
+		return channel.Message{}, false
+	}
+	return channel.Message{Text: replyText(quota.language, msg.Message.PlainText(), limitDay)}, false
+}
+
+// reply answers a message with a limit notice.
+func (s *Service) reply(ctx context.Context, botID string, msg channel.InboundMessage, text string) {
+	target := strings.TrimSpace(msg.ReplyTarget)
+	if s.sender == nil || target == "" {
+		return
+	}
+	reply := channel.Message{Text: text, Thread: msg.Message.Thread}
+	if messageID := strings.TrimSpace(msg.Message.ID); messageID != "" {
+		reply.Reply = &channel.ReplyRef{Target: target, MessageID: messageID}
+	}
+	if err := s.sender.Send(ctx, botID, msg.Channel, channel.SendRequest{Target: target, Message: reply}); err != nil {
+		s.logger.Warn("send rate limit notice failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+// resolveRole tells the owner, admins and members apart from guests.
+func (s *Service) resolveRole(ctx context.Context, botID, userID string) Role {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return RoleGuest
+	}
+	if s.owners != nil {
+		ownerID, err := s.owners.BotOwnerUserID(ctx, botID)
+		if err != nil {
+			s.logger.Warn("resolve bot owner failed", slog.String("bot_id", botID), slog.Any("error", err))
+		} else if strings.TrimSpace(ownerID) == userID {
+			return RoleOwner
+		}
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return RoleGuest
+	}
+	pgUserID, err := db.ParseUUID(userID)
+	if err != nil {
+		return RoleGuest
+	}
+	member, err := s.queries.GetBotMember(ctx, sqlc.GetBotMemberParams{BotID: pgBotID, UserID: pgUserID})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("resolve bot member failed", slog.String("bot_id", botID), slog.Any("error", err))
+		}
+		return RoleGuest
+	}
+	switch Role(member.Role) {
+	case RoleOwner, RoleAdmin:
+		return Role(member.Role)
+	default:
+		return RoleMember
+	}
+}
+
+// botSettings returns the bot language and timezone, falling back to automatic
+// language and UTC.
+func (s *Service) botSettings(ctx context.Context, botID string) (string, *time.Location) {
+	if s.settings == nil {
+		return settings.DefaultLanguage, time.UTC
+	}
+	botSettings, err := s.settings.GetBot(ctx, botID)
+	if err != nil {
+		s.logger.Warn("load bot settings failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return settings.DefaultLanguage, time.UTC
+	}
+	loc, err := time.LoadLocation(botSettings.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return botSettings.Language, loc
+}
+
+func (s *Service) recordMessage(ctx context.Context, quota senderQuota) error {
+	pgBotID, err := db.ParseUUID(quota.botID)
+	if err != nil {
+		return err
+	}
+	pgIdentityID, err := db.ParseUUID(quota.channelIdentityID)
+	if err != nil {
+		return err
+	}
+	return s.queries.RecordChannelRateMessage(ctx, sqlc.RecordChannelRateMessageParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+		Day:               pgtype.Date{Time: quota.day, Valid: true},
+		ChannelType:       quota.channelType,
+		DisplayName:       quota.displayName,
+		Role:              string(quota.role),
+	})
+}
+
+func (s *Service) recordLimited(ctx context.Context, quota senderQuota) {
+	pgBotID, err := db.ParseUUID(quota.botID)
+	if err != nil {
+		return
+	}
+	pgIdentityID, err := db.ParseUUID(quota.channelIdentityID)
+	if err != nil {
+		return
+	}
+	if _, err := s.queries.RecordChannelRateLimited(ctx, sqlc.RecordChannelRateLimitedParams{
+		BotID:             pgBotID,
+		ChannelIdentityID: pgIdentityID,
+		Day:               pgtype.Date{Time: quota.day, Valid: true},
+		ChannelType:       quota.channelType,
+		DisplayName:       quota.displayName,
+		Role:              string(quota.role),
+	}); err != nil {
+		s.logger.Warn("record rate limit hit failed", slog.String("bot_id", quota.botID), slog.Any("error", err))
+	}
+}
+
+// firstNotice reports whether the sender has not yet been told about the daily limit
+// today, and remembers that they now have.
+func (s *Service) firstNotice(quota senderQuota) bool {
+	key := quota.botID + ":" + quota.channelIdentityID
+	day := quota.day.Format(dayLayout)
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	if s.notified[key] == day {
+		return false
+	}
+	for other, seen := range s.notified {
+		if seen < day {
+			delete(s.notified, other)
+		}
+	}
+	s.notified[key] = day
+	return true
+}
+
+// dayOf returns the calendar day of t in loc, as midnight UTC of that date.
+func dayOf(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// windowCounter counts recent messages per key over a sliding window.
+type windowCounter struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string][]time.Time
+}
+
+func newWindowCounter(window time.Duration) *windowCounter {
+	return &windowCounter{window: window, seen: make(map[string][]time.Time)}
+}
+
+// record adds a message at now and reports whether the key exceeded limit messages in
+// the window, and whether this message is the first over the limit.
+func (c *windowCounter) record(key string, limit int, now time.Time) (exceeded, first bool) {
+	if limit <= 0 {
+		return false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	recent := c.seen[key][:0]
+	for _, at := range c.seen[key] {
+		if now.Sub(at) < c.window {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	c.seen[key] = recent
+	for other, times := range c.seen {
+		if len(times) > 0 && now.Sub(times[len(times)-1]) > c.window {
+			delete(c.seen, other)
+		}
+	}
+	return len(recent) > limit, len(recent) == limit+1
+}
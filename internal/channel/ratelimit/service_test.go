@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	valid := Policy{
+		Enabled: true,
+		Member:  Limits{MessagesPerMinute: 20},
+		Guest:   Limits{MessagesPerMinute: 5, TurnsPerDay: 30},
+	}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("expected valid policy, got %v", err)
+	}
+	if got := valid.limits(RoleGuest).TurnsPerDay; got != 30 {
+		t.Fatalf("expected guest turns 30, got %d", got)
+	}
+	if got := valid.limits(RoleOwner); got != (Limits{}) {
+		t.Fatalf("expected owner unlimited, got %+v", got)
+	}
+
+	invalid := []Policy{
+		{Guest: Limits{MessagesPerMinute: -1}},
+		{Admin: Limits{TurnsPerDay: maxTurnsPerDay + 1}},
+		{Owner: Limits{MessagesPerMinute: maxMessagesPerMinute + 1}},
+	}
+	for i, policy := range invalid {
+		if err := policy.validate(); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestWindowCounter(t *testing.T) {
+	counter := newWindowCounter(time.Minute)
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if exceeded, _ := counter.record("a", 3, start.Add(time.Duration(i)*time.Second)); exceeded {
+			t.Fatalf("message %d: unexpected limit", i+1)
+		}
+	}
+	exceeded, first := counter.record("a", 3, start.Add(3*time.Second))
+	if !exceeded || !first {
+		t.Fatalf("expected first message over the limit, got exceeded=%v first=%v", exceeded, first)
+	}
+	exceeded, first = counter.record("a", 3, start.Add(4*time.Second))
+	if !exceeded || first {
+		t.Fatalf("expected repeated limit without notice, got exceeded=%v first=%v", exceeded, first)
+	}
+	if exceeded, _ := counter.record("b", 3, start.Add(4*time.Second)); exceeded {
+		t.Fatal("expected senders to be counted separately")
+	}
+	if exceeded, _ := counter.record("a", 3, start.Add(2*time.Minute)); exceeded {
+		t.Fatal("expected the window to slide")
+	}
+	if exceeded, _ := counter.record("a", 0, start); exceeded {
+		t.Fatal("expected zero to mean no limit")
+	}
+}
+
+func TestFirstNotice(t *testing.T) {
+	s := NewService(nil, nil, nil, nil)
+	quota := senderQuota{botID: "bot", channelIdentityID: "sender", day: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)}
+	if !s.firstNotice(quota) {
+		t.Fatal("expected first notice of the day")
+	}
+	if s.firstNotice(quota) {
+		t.Fatal("expected a single notice per day")
+	}
+	quota.day = quota.day.AddDate(0, 0, 1)
+	if !s.firstNotice(quota) {
+		t.Fatal("expected a new notice the next day")
+	}
+}
+
+func TestDayOf(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	now := time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC)
+	if got, want := dayOf(now, tokyo), time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got, want := dayOf(now, time.UTC), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestReplyLanguage(t *testing.T) {
+	cases := []struct {
+		language string
+		text     string
+		want     string
+	}{
+		{language: "auto", text: "hello there", want: "en"},
+		{language: "", text: "你好，在吗", want: "zh"},
+		{language: "auto", text: "こんにちは", want: "ja"},
+		{language: "auto", text: "안녕하세요", want: "ko"},
+		{language: "auto", text: "привет", want: "ru"},
+		{language: "zh-CN", text: "hello", want: "zh"},
+		{language: "pt_BR", text: "hello", want: "pt"},
+		{language: "Deutsch", text: "hello", want: "de"},
+		{language: "klingon", text: "hola", want: "en"},
+	}
+	for _, tc := range cases {
+		if got := replyLanguage(tc.language, tc.text); got != tc.want {
+			t.Fatalf("replyLanguage(%q, %q) = %q, want %q", tc.language, tc.text, got, tc.want)
+		}
+	}
+	for language, texts := range replies {
+		if texts[limitMinute] == "" || texts[limitDay] == "" {
+			t.Fatalf("missing notices for %s", language)
+		}
+	}
+}
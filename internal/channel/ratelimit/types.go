@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+)
+
+// Role is the relation of a sender to a bot, used to pick the limits that apply.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	// RoleGuest is any sender who is neither the owner nor a member, e.g. when the bot
+	// allows guests.
+	RoleGuest Role = "guest"
+)
+
+const (
+	maxMessagesPerMinute = 600
+	maxTurnsPerDay       = 100000
+)
+
+// Limits caps the traffic of one sender. Zero means no limit.
+type Limits struct {
+	// MessagesPerMinute caps the messages a sender may send in any 60-second window.
+	MessagesPerMinute int `json:"messages_per_minute,omitempty"`
+	// TurnsPerDay caps the messages a sender may have the bot answer per day in the
+	// bot's timezone.
+	TurnsPerDay int `json:"turns_per_day,omitempty"`
+}
+
+func (l Limits) validate(role Role) error {
+	if l.MessagesPerMinute < 0 || l.MessagesPerMinute > maxMessagesPerMinute {
+		return fmt.Errorf("%s messages_per_minute must be between 0 and %d", role, maxMessagesPerMinute)
+	}
+	if l.TurnsPerDay < 0 || l.TurnsPerDay > maxTurnsPerDay {
+		return fmt.Errorf("%s turns_per_day must be between 0 and %d", role, maxTurnsPerDay)
+	}
+	return nil
+}
+
+// Policy is the rate limit policy of a bot, with limits per sender role.
+type Policy struct {
+	Enabled bool   `json:"enabled"`
+	Owner   Limits `json:"owner"`
+	Admin   Limits `json:"admin"`
+	Member  Limits `json:"member"`
+	Guest   Limits `json:"guest"`
+}
+
+func (p Policy) validate() error {
+	for _, role := range []Role{RoleOwner, RoleAdmin, RoleMember, RoleGuest} {
+		if err := p.limits(role).validate(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limits returns the limits for a role.
+func (p Policy) limits(role Role) Limits {
+	switch role {
+	case RoleOwner:
+		return p.Owner
+	case RoleAdmin:
+		return p.Admin
+	case RoleMember:
+		return p.Member
+	default:
+		return p.Guest
+	}
+}
+
+// Usage is the traffic of one sender on one day.
+type Usage struct {
+	ChannelIdentityID string    `json:"channel_identity_id"`
+	ChannelType       string    `json:"channel_type"`
+	DisplayName       string    `json:"display_name,omitempty"`
+	Role              Role      `json:"role"`
+	Messages          int       `json:"messages"`
+	Turns             int       `json:"turns"`
+	Limited           int       `json:"limited"`
+	TurnsPerDay       int       `json:"turns_per_day,omitempty"`
+	LastSeenAt        time.Time `json:"last_seen_at"`
+}
+
+// UsageResponse lists the senders of a bot on one day, most active first.
+type UsageResponse struct {
+	Day      string  `json:"day"`
+	Timezone string  `json:"timezone"`
+	Items    []Usage `json:"items"`
+}
+
+// limitKind names the limit a sender ran into.
+type limitKind int
+
+const (
+	limitMinute limitKind = iota
+	limitDay
+)
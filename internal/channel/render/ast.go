@@ -0,0 +1,115 @@
+// Package render parses assistant markdown into a small document tree and renders
+// it in the native formatting of each platform: Telegram HTML, Discord markdown with
+// table embeds, Feishu post and card JSON, general HTML for Matrix and e-mail, and
+// plain text.
+//
+// The parser covers the markdown models produce in chat replies (headings, lists,
+// block quotes, fenced code, tables, emphasis, links). It never fails: anything it
+// does not recognize is kept as text, and unterminated constructs such as an open
+// code fence in a partial stream are closed at the end of the input.
+package render
+
+// BlockKind identifies the type of a block node.
+type BlockKind int
+
+const (
+	BlockParagraph BlockKind = iota
+	BlockHeading
+	BlockCode
+	BlockList
+	BlockQuote
+	BlockTable
+	BlockRule
+)
+
+// Block is a block-level node of a document.
+type Block struct {
+	Kind BlockKind
+	// Level is the heading level, 1 to 6.
+	Level int
+	// Inlines holds the content of paragraphs and headings.
+	Inlines []Inline
+	// Language and Code hold a fenced code block.
+	Language string
+	Code     string
+	// Ordered, Start and Items hold a list. Each item is a sequence of blocks.
+	Ordered bool
+	Start   int
+	Items   [][]Block
+	// Children holds the content of a block quote.
+	Children []Block
+	// Table holds a table.
+	Table *Table
+}
+
+// Align is the column alignment of a table.
+type Align int
+
+const (
+	AlignNone Align = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// Table is a GitHub-flavored markdown table. Every row has len(Header) cells.
+type Table struct {
+	Header []Cell
+	Align  []Align
+	Rows   [][]Cell
+}
+
+// Cell is the inline content of a table cell.
+type Cell []Inline
+
+// InlineKind identifies the type of an inline node.
+type InlineKind int
+
+const (
+	InlineText InlineKind = iota
+	InlineStrong
+	InlineEmphasis
+	InlineStrike
+	InlineCode
+	InlineLink
+	InlineBreak
+)
+
+// Inline is an inline node. Text and Code carry Text; Link carries URL and Children;
+// the emphasis kinds carry Children.
+type Inline struct {
+	Kind     InlineKind
+	Text     string
+	URL      string
+	Children []Inline
+}
+
+// Document is a parsed markdown text.
+type Document struct {
+	Blocks []Block
+}
+
+// HasTable reports whether the document contains a table at any depth.
+func (d Document) HasTable() bool {
+	return blocksHaveTable(d.Blocks)
+}
+
+func blocksHaveTable(blocks []Block) bool {
+	for _, block := range blocks {
+		switch block.Kind {
+		case BlockTable:
+			return true
+		case BlockQuote:
+			if blocksHaveTable(block.Children) {
+				return true
+			}
+		case BlockList:
+			for _, item := range block.Items {
+				if blocksHaveTable(item) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
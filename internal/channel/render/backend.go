@@ -0,0 +1,108 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+)
+
+// backend renders nodes for a text-based output format. renderBlocks and
+// renderInlines walk the tree and combine the pieces.
+type backend interface {
+	text(s string) string
+	strong(inner string) string
+	emphasis(inner string) string
+	strike(inner string) string
+	code(s string) string
+	link(label, url string) string
+	lineBreak() string
+
+	heading(level int, inner string) string
+	codeBlock(language, code string) string
+	quote(inner string) string
+	// table returns the rendering of a table, or "" when the backend emits it
+	// out of band.
+	table(t *Table) string
+	rule() string
+	bullet() string
+}
+
+func renderInlines(b backend, inlines []Inline) string {
+	var buf strings.Builder
+	for _, node := range inlines {
+		switch node.Kind {
+		case InlineText:
+			buf.WriteString(b.text(node.Text))
+		case InlineStrong:
+			buf.WriteString(b.strong(renderInlines(b, node.Children)))
+		case InlineEmphasis:
+			buf.WriteString(b.emphasis(renderInlines(b, node.Children)))
+		case InlineStrike:
+			buf.WriteString(b.strike(renderInlines(b, node.Children)))
+		case InlineCode:
+			buf.WriteString(b.code(node.Text))
+		case InlineLink:
+			buf.WriteString(b.link(renderInlines(b, node.Children), node.URL))
+		case InlineBreak:
+			buf.WriteString(b.lineBreak())
+		}
+	}
+	return buf.String()
+}
+
+// renderBlocks renders blocks separated by blank lines. Lists render their items on
+// consecutive lines, with continuation lines indented under the item text.
+func renderBlocks(b backend, blocks []Block) string {
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if rendered := renderBlock(b, block); rendered != "" {
+			parts = append(parts, rendered)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func renderBlock(b backend, block Block) string {
+	switch block.Kind {
+	case BlockParagraph:
+		return renderInlines(b, block.Inlines)
+	case BlockHeading:
+		return b.heading(block.Level, renderInlines(b, block.Inlines))
+	case BlockCode:
+		return b.codeBlock(block.Language, block.Code)
+	case BlockQuote:
+		return b.quote(renderBlocks(b, block.Children))
+	case BlockTable:
+		return b.table(block.Table)
+	case BlockRule:
+		return b.rule()
+	case BlockList:
+		lines := make([]string, 0, len(block.Items))
+		for i, item := range block.Items {
+			marker := listMarker(b, block, i)
+			body := renderListItem(b, item)
+			indent := strings.Repeat(" ", len([]rune(marker)))
+			lines = append(lines, marker+strings.ReplaceAll(body, "\n", "\n"+indent))
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
+
+// renderListItem renders the blocks of a list item without blank lines between them,
+// so nested lists stay attached to their parent item.
+func renderListItem(b backend, item []Block) string {
+	parts := make([]string, 0, len(item))
+	for _, block := range item {
+		if rendered := renderBlock(b, block); rendered != "" {
+			parts = append(parts, rendered)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func listMarker(b backend, block Block, index int) string {
+	if block.Ordered {
+		return strconv.Itoa(block.Start+index) + ". "
+	}
+	return b.bullet()
+}
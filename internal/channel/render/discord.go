@@ -0,0 +1,248 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord embed limits used when deciding whether a table fits into an embed.
+const (
+	discordMaxEmbeds         = 10
+	discordMaxEmbedColumns   = 3
+	discordMaxFieldNameRunes = 256
+	discordMaxFieldRunes     = 1024
+	discordMaxEmbedRunes     = 6000
+)
+
+// DiscordMessage is a document rendered for Discord. Tables that fit are moved into
+// embeds, which Discord shows below the content.
+type DiscordMessage struct {
+	Content string
+	Embeds  []DiscordEmbed
+}
+
+// DiscordEmbed is an embed holding one table, one inline field per column.
+type DiscordEmbed struct {
+	Fields []DiscordEmbedField
+}
+
+// DiscordEmbedField is a field of a DiscordEmbed.
+type DiscordEmbedField struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// Discord renders a document as Discord markdown. Headings up to level 3 use
+// Discord's native headings; small tables become embeds and larger ones code blocks.
+func Discord(doc Document) DiscordMessage {
+	b := &discordBackend{}
+	content := strings.TrimSpace(renderBlocks(b, doc.Blocks))
+	return DiscordMessage{Content: content, Embeds: b.embeds}
+}
+
+type discordBackend struct {
+	embeds []DiscordEmbed
+}
+
+func (*discordBackend) text(s string) string         { return escapeMarkdown(s) }
+func (*discordBackend) strong(inner string) string   { return "**" + inner + "**" }
+func (*discordBackend) emphasis(inner string) string { return "*" + inner + "*" }
+func (*discordBackend) strike(inner string) string   { return "~~" + inner + "~~" }
+func (*discordBackend) code(s string) string         { return markdownCodeSpan(s) }
+func (*discordBackend) lineBreak() string            { return "\n" }
+func (*discordBackend) bullet() string               { return "- " }
+func (*discordBackend) rule() string                 { return "──────────" }
+
+func (*discordBackend) link(label, url string) string {
+	if label == "" || label == escapeMarkdown(url) {
+		return url
+	}
+	return "[" + label + "](" + url + ")"
+}
+
+func (*discordBackend) heading(level int, inner string) string {
+	if level <= 3 {
+		return strings.Repeat("#", level) + " " + inner
+	}
+	return "**" + inner + "**"
+}
+
+func (*discordBackend) codeBlock(language, code string) string {
+	return markdownCodeBlock(language, code)
+}
+
+func (*discordBackend) quote(inner string) string {
+	return "> " + strings.ReplaceAll(inner, "\n", "\n> ")
+}
+
+func (b *discordBackend) table(t *Table) string {
+	if embed, ok := discordTableEmbed(t); ok && len(b.embeds) < discordMaxEmbeds {
+		b.embeds = append(b.embeds, embed)
+		return ""
+	}
+	return markdownCodeBlock("", TableText(t))
+}
+
+// discordTableEmbed lays a table out as inline embed fields, one per column. Discord
+// places up to three inline fields side by side, so wider tables do not fit.
+func discordTableEmbed(t *Table) (DiscordEmbed, bool) {
+	if t == nil || len(t.Header) == 0 || len(t.Header) > discordMaxEmbedColumns || len(t.Rows) == 0 {
+		return DiscordEmbed{}, false
+	}
+	cell := &discordBackend{}
+	embed := DiscordEmbed{Fields: make([]DiscordEmbedField, len(t.Header))}
+	total := 0
+	for col := range t.Header {
+		name := strings.ReplaceAll(renderInlines(cell, t.Header[col]), "\n", " ")
+		values := make([]string, len(t.Rows))
+		for i, row := range t.Rows {
+			value := strings.ReplaceAll(renderInlines(cell, row[col]), "\n", " ")
+			if strings.TrimSpace(value) == "" {
+				value = "-"
+			}
+			values[i] = value
+		}
+		value := strings.Join(values, "\n")
+		if strings.TrimSpace(name) == "" {
+			name = "\u200b"
+		}
+		nameRunes, valueRunes := utf8.RuneCountInString(name), utf8.RuneCountInString(value)
+		if nameRunes > discordMaxFieldNameRunes || valueRunes > discordMaxFieldRunes {
+			return DiscordEmbed{}, false
+		}
+		total += nameRunes + valueRunes
+		embed.Fields[col] = DiscordEmbedField{Name: name, Value: value, Inline: true}
+	}
+	if total > discordMaxEmbedRunes {
+		return DiscordEmbed{}, false
+	}
+	return embed, true
+}
+
+// escapeMarkdown escapes characters that would start formatting. Words containing
+// "://" are left alone so links stay clickable.
+func escapeMarkdown(text string) string {
+	if !strings.ContainsAny(text, "\\*_~`|") {
+		return text
+	}
+	var buf strings.Builder
+	for i, word := range strings.Split(text, " ") {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		if strings.Contains(word, "://") {
+			buf.WriteString(word)
+			continue
+		}
+		for _, r := range word {
+			if strings.ContainsRune("\\*_~`|", r) {
+				buf.WriteByte('\\')
+			}
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// markdownCodeSpan wraps s in enough backticks to contain any backticks in s.
+func markdownCodeSpan(s string) string {
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+func markdownCodeBlock(language, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + language + "\n" + strings.TrimRight(code, "\n") + "\n" + fence
+}
+
+// SplitMarkdown splits markdown text into chunks of at most limit runes, breaking
+// between lines where possible. A code block cut in two is closed at the end of one
+// chunk and reopened at the start of the next, so both halves render as code.
+func SplitMarkdown(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		if text == "" {
+			return nil
+		}
+		return []string{text}
+	}
+	var (
+		chunks []string
+		chunk  strings.Builder
+		size   int
+		fence  string // opening fence line of the code block we are in, if any
+	)
+	closing := func() string {
+		if fence == "" {
+			return ""
+		}
+		return "\n" + fenceMarker(fence)
+	}
+	flush := func() {
+		body := strings.TrimRight(chunk.String(), "\n")
+		if strings.TrimSpace(body) != "" {
+			chunks = append(chunks, body+closing())
+		}
+		chunk.Reset()
+		size = 0
+		if fence != "" {
+			chunk.WriteString(fence + "\n")
+			size = utf8.RuneCountInString(fence) + 1
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		lineRunes := utf8.RuneCountInString(line)
+		reserve := utf8.RuneCountInString(closing())
+		if fence == "" && reFence.MatchString(line) {
+			// Leave room to close the block this line opens.
+			reserve = len(fenceMarker(line)) + 1
+		}
+		if size > 0 && size+lineRunes+1+reserve > limit {
+			flush()
+		}
+		// Hard-wrap lines that do not fit into an empty chunk.
+		for size+lineRunes+utf8.RuneCountInString(closing()) > limit {
+			room := limit - size - utf8.RuneCountInString(closing())
+			if room <= 0 {
+				break
+			}
+			runes := []rune(line)
+			chunk.WriteString(string(runes[:room]))
+			size += room
+			line = string(runes[room:])
+			lineRunes = len(runes) - room
+			flush()
+		}
+		chunk.WriteString(line + "\n")
+		size += lineRunes + 1
+		if m := reFence.FindStringSubmatch(line); m != nil {
+			if fence == "" {
+				fence = strings.TrimSpace(line)
+			} else if strings.HasPrefix(strings.TrimSpace(line), fenceMarker(fence)) && strings.Trim(strings.TrimSpace(line), fenceMarker(fence)[:1]) == "" {
+				fence = ""
+			}
+		}
+	}
+	fence = ""
+	flush()
+	return chunks
+}
+
+// fenceMarker returns the backtick or tilde run that opens a fence line.
+func fenceMarker(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return ""
+	}
+	return line[:runLength(line, 0, len(line), line[0])]
+}
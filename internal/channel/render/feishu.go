@@ -0,0 +1,240 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// feishuMaxTablePageSize is the largest page size of a card table component.
+const feishuMaxTablePageSize = 10
+
+// FeishuPost renders a document as the content JSON of a Feishu "post" message. Post
+// messages have styled text, links, code blocks and rules but no tables, so tables
+// are sent as code blocks.
+func FeishuPost(doc Document) (string, error) {
+	w := &feishuPostWriter{}
+	w.blocks(doc.Blocks, "", "")
+	content := map[string]any{
+		"zh_cn": map[string]any{
+			"title":   "",
+			"content": w.rows,
+		},
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("marshal feishu post: %w", err)
+	}
+	return string(data), nil
+}
+
+type feishuPostWriter struct {
+	rows [][]map[string]any
+}
+
+// blocks writes blocks as post rows. The first row is prefixed with first and later
+// rows with rest, which is how list markers and quote bars are drawn.
+func (w *feishuPostWriter) blocks(blocks []Block, first, rest string) {
+	for i, block := range blocks {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		switch block.Kind {
+		case BlockParagraph:
+			w.lines(block.Inlines, nil, prefix, rest)
+		case BlockHeading:
+			w.lines(block.Inlines, []string{"bold"}, prefix, rest)
+		case BlockCode:
+			w.rows = append(w.rows, []map[string]any{feishuCodeBlock(block.Language, block.Code)})
+		case BlockTable:
+			w.rows = append(w.rows, []map[string]any{feishuCodeBlock("", TableText(block.Table))})
+		case BlockRule:
+			w.rows = append(w.rows, []map[string]any{{"tag": "hr"}})
+		case BlockQuote:
+			w.blocks(block.Children, prefix+"> ", rest+"> ")
+		case BlockList:
+			for n, item := range block.Items {
+				marker := "• "
+				if block.Ordered {
+					marker = fmt.Sprintf("%d. ", block.Start+n)
+				}
+				itemPrefix := rest
+				if n == 0 {
+					itemPrefix = prefix
+				}
+				w.blocks(item, itemPrefix+marker, rest+strings.Repeat(" ", len([]rune(marker))+2))
+			}
+		}
+	}
+}
+
+// lines writes inline content as one row per line.
+func (w *feishuPostWriter) lines(inlines []Inline, style []string, first, rest string) {
+	row := []map[string]any{}
+	if first != "" {
+		row = append(row, map[string]any{"tag": "text", "text": first})
+	}
+	var walk func(nodes []Inline, style []string)
+	walk = func(nodes []Inline, style []string) {
+		for _, node := range nodes {
+			switch node.Kind {
+			case InlineText, InlineCode:
+				row = append(row, feishuTextElement(node.Text, style))
+			case InlineStrong:
+				walk(node.Children, append(style[:len(style):len(style)], "bold"))
+			case InlineEmphasis:
+				walk(node.Children, append(style[:len(style):len(style)], "italic"))
+			case InlineStrike:
+				walk(node.Children, append(style[:len(style):len(style)], "lineThrough"))
+			case InlineLink:
+				element := map[string]any{"tag": "a", "text": renderInlines(plainBackend{}, node.Children), "href": node.URL}
+				if len(style) > 0 {
+					element["style"] = style
+				}
+				row = append(row, element)
+			case InlineBreak:
+				w.rows = append(w.rows, row)
+				row = []map[string]any{}
+				if rest != "" {
+					row = append(row, map[string]any{"tag": "text", "text": rest})
+				}
+			}
+		}
+	}
+	walk(inlines, style)
+	w.rows = append(w.rows, row)
+}
+
+func feishuTextElement(text string, style []string) map[string]any {
+	element := map[string]any{"tag": "text", "text": text}
+	if len(style) > 0 {
+		element["style"] = style
+	}
+	return element
+}
+
+func feishuCodeBlock(language, code string) map[string]any {
+	element := map[string]any{"tag": "code_block", "text": strings.TrimRight(code, "\n")}
+	if language != "" {
+		element["language"] = strings.ToUpper(language)
+	}
+	return element
+}
+
+// FeishuCardElements renders a document as interactive card elements: markdown
+// elements for text, table components for top-level tables and hr elements for rules.
+func FeishuCardElements(doc Document) []map[string]any {
+	var (
+		elements []map[string]any
+		pending  []Block
+	)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		if content := strings.TrimSpace(renderBlocks(feishuCardBackend{}, pending)); content != "" {
+			elements = append(elements, map[string]any{"tag": "markdown", "content": content})
+		}
+		pending = nil
+	}
+	for _, block := range doc.Blocks {
+		switch block.Kind {
+		case BlockTable:
+			flush()
+			elements = append(elements, feishuCardTable(block.Table))
+		case BlockRule:
+			flush()
+			elements = append(elements, map[string]any{"tag": "hr"})
+		default:
+			pending = append(pending, block)
+		}
+	}
+	flush()
+	return elements
+}
+
+func feishuCardTable(t *Table) map[string]any {
+	columns := make([]map[string]any, len(t.Header))
+	for col, header := range t.Header {
+		align := "left"
+		switch alignOf(t, col) {
+		case AlignCenter:
+			align = "center"
+		case AlignRight:
+			align = "right"
+		}
+		columns[col] = map[string]any{
+			"name":             fmt.Sprintf("c%d", col),
+			"display_name":     renderInlines(plainBackend{}, header),
+			"data_type":        "lark_md",
+			"width":            "auto",
+			"horizontal_align": align,
+		}
+	}
+	rows := make([]map[string]any, len(t.Rows))
+	for i, row := range t.Rows {
+		values := make(map[string]any, len(row))
+		for col, cell := range row {
+			values[fmt.Sprintf("c%d", col)] = renderInlines(feishuCardBackend{}, cell)
+		}
+		rows[i] = values
+	}
+	return map[string]any{
+		"tag":          "table",
+		"page_size":    min(max(len(rows), 1), feishuMaxTablePageSize),
+		"row_height":   "low",
+		"header_style": map[string]any{"bold": true, "background_style": "grey"},
+		"columns":      columns,
+		"rows":         rows,
+	}
+}
+
+// feishuCardBackend renders card markdown. Card markdown has no headings, so they
+// become bold lines; nested tables fall back to code blocks.
+type feishuCardBackend struct{}
+
+func (feishuCardBackend) strong(inner string) string   { return "**" + inner + "**" }
+func (feishuCardBackend) emphasis(inner string) string { return "*" + inner + "*" }
+func (feishuCardBackend) strike(inner string) string   { return "~~" + inner + "~~" }
+func (feishuCardBackend) code(s string) string         { return markdownCodeSpan(s) }
+func (feishuCardBackend) lineBreak() string            { return "\n" }
+func (feishuCardBackend) bullet() string               { return "- " }
+func (feishuCardBackend) rule() string                 { return "---" }
+
+// feishuCardEscaper escapes card markdown with HTML entities; card markdown does not
+// support backslash escapes.
+var feishuCardEscaper = strings.NewReplacer(
+	"*", "&#42;",
+	"~", "&#126;",
+	"`", "&#96;",
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+func (feishuCardBackend) text(s string) string {
+	return feishuCardEscaper.Replace(s)
+}
+
+func (feishuCardBackend) link(label, url string) string {
+	if label == "" {
+		label = url
+	}
+	return "[" + label + "](" + url + ")"
+}
+
+func (feishuCardBackend) heading(_ int, inner string) string {
+	return "**" + inner + "**"
+}
+
+func (feishuCardBackend) codeBlock(language, code string) string {
+	return markdownCodeBlock(language, code)
+}
+
+func (feishuCardBackend) quote(inner string) string {
+	return "> " + strings.ReplaceAll(inner, "\n", "\n> ")
+}
+
+func (feishuCardBackend) table(t *Table) string {
+	return markdownCodeBlock("", TableText(t))
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HTML renders a document as a conservative HTML fragment for platforms that accept
+// general HTML, such as Matrix formatted bodies and e-mail. Unlike the text-based
+// backends, paragraphs, lists and tables keep their HTML structure.
+func HTML(doc Document) string {
+	return htmlBlocks(doc.Blocks)
+}
+
+type htmlBackend struct{}
+
+func (htmlBackend) text(s string) string         { return escapeHTML(s) }
+func (htmlBackend) strong(inner string) string   { return "<strong>" + inner + "</strong>" }
+func (htmlBackend) emphasis(inner string) string { return "<em>" + inner + "</em>" }
+func (htmlBackend) strike(inner string) string   { return "<del>" + inner + "</del>" }
+func (htmlBackend) code(s string) string         { return "<code>" + escapeHTML(s) + "</code>" }
+func (htmlBackend) lineBreak() string            { return "<br>" }
+func (htmlBackend) bullet() string               { return "" }
+func (htmlBackend) rule() string                 { return "<hr>" }
+
+func (htmlBackend) link(label, url string) string {
+	return `<a href="` + escapeHTMLAttr(url) + `">` + label + "</a>"
+}
+
+func (htmlBackend) heading(level int, inner string) string {
+	tag := "h" + strconv.Itoa(min(max(level, 1), 6))
+	return "<" + tag + ">" + inner + "</" + tag + ">"
+}
+
+func (htmlBackend) codeBlock(language, code string) string {
+	escaped := escapeHTML(strings.TrimRight(code, "\n"))
+	if language != "" {
+		return `<pre><code class="language-` + escapeHTMLAttr(language) + `">` + escaped + "</code></pre>"
+	}
+	return "<pre><code>" + escaped + "</code></pre>"
+}
+
+func (htmlBackend) quote(inner string) string {
+	return "<blockquote>" + inner + "</blockquote>"
+}
+
+func (htmlBackend) table(t *Table) string {
+	var buf strings.Builder
+	buf.WriteString("<table><thead><tr>")
+	for col, cell := range t.Header {
+		buf.WriteString(htmlCell("th", cell, alignOf(t, col)))
+	}
+	buf.WriteString("</tr></thead><tbody>")
+	for _, row := range t.Rows {
+		buf.WriteString("<tr>")
+		for col, cell := range row {
+			buf.WriteString(htmlCell("td", cell, alignOf(t, col)))
+		}
+		buf.WriteString("</tr>")
+	}
+	buf.WriteString("</tbody></table>")
+	return buf.String()
+}
+
+func htmlCell(tag string, cell Cell, align Align) string {
+	open := "<" + tag + ">"
+	switch align {
+	case AlignLeft:
+		open = "<" + tag + ` align="left">`
+	case AlignCenter:
+		open = "<" + tag + ` align="center">`
+	case AlignRight:
+		open = "<" + tag + ` align="right">`
+	}
+	return open + renderInlines(htmlBackend{}, cell) + "</" + tag + ">"
+}
+
+// htmlBlocks renders blocks back to back; HTML block elements need no separators.
+func htmlBlocks(blocks []Block) string {
+	var buf strings.Builder
+	for _, block := range blocks {
+		buf.WriteString(htmlBlock(block))
+	}
+	return buf.String()
+}
+
+func htmlBlock(block Block) string {
+	b := htmlBackend{}
+	switch block.Kind {
+	case BlockParagraph:
+		return "<p>" + renderInlines(b, block.Inlines) + "</p>"
+	case BlockQuote:
+		return b.quote(htmlBlocks(block.Children))
+	case BlockList:
+		open, closing := "<ul>", "</ul>"
+		if block.Ordered {
+			open, closing = "<ol>", "</ol>"
+			if block.Start != 1 {
+				open = `<ol start="` + strconv.Itoa(block.Start) + `">`
+			}
+		}
+		var buf strings.Builder
+		buf.WriteString(open)
+		for _, item := range block.Items {
+			buf.WriteString("<li>" + htmlListItem(item) + "</li>")
+		}
+		buf.WriteString(closing)
+		return buf.String()
+	}
+	return renderBlock(b, block)
+}
+
+// htmlListItem renders the paragraphs of a list item without <p> so tight lists stay
+// compact. Consecutive paragraphs are separated by a line break.
+func htmlListItem(item []Block) string {
+	var buf strings.Builder
+	for i, block := range item {
+		if block.Kind != BlockParagraph {
+			buf.WriteString(htmlBlock(block))
+			continue
+		}
+		if i > 0 && item[i-1].Kind == BlockParagraph {
+			buf.WriteString("<br>")
+		}
+		buf.WriteString(renderInlines(htmlBackend{}, block.Inlines))
+	}
+	return buf.String()
+}
@@ -0,0 +1,547 @@
+package render
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	reHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	reFence      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`\\s]*)[^`]*$")
+	reRule       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	reListItem   = regexp.MustCompile(`^( *)([-+*]|\d{1,9}[.)])(?:[ \t]+(.*))?$`)
+	reQuote      = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	reTableDelim = regexp.MustCompile(`^ *\|? *:?-+:? *(?:\| *:?-+:? *)*\|? *$`)
+)
+
+// Parse parses markdown text into a document.
+func Parse(text string) Document {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return Document{Blocks: parseBlocks(strings.Split(text, "\n"))}
+}
+
+func parseBlocks(lines []string) []Block {
+	var blocks []Block
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			i++
+			continue
+		}
+		if m := reFence.FindStringSubmatch(line); m != nil {
+			block, next := parseFence(lines, i, len(m[1]), m[2], m[3])
+			blocks = append(blocks, block)
+			i = next
+			continue
+		}
+		if m := reHeading.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, Block{Kind: BlockHeading, Level: len(m[1]), Inlines: parseInlines(strings.TrimSpace(m[2]))})
+			i++
+			continue
+		}
+		if reRule.MatchString(line) {
+			blocks = append(blocks, Block{Kind: BlockRule})
+			i++
+			continue
+		}
+		if reQuote.MatchString(line) {
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := reQuote.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quoted = append(quoted, m[1])
+			}
+			blocks = append(blocks, Block{Kind: BlockQuote, Children: parseBlocks(quoted)})
+			continue
+		}
+		if isTableStart(lines, i) {
+			block, next := parseTable(lines, i)
+			blocks = append(blocks, block)
+			i = next
+			continue
+		}
+		if reListItem.MatchString(line) {
+			block, next := parseList(lines, i)
+			blocks = append(blocks, block)
+			i = next
+			continue
+		}
+		var paragraph []string
+		for ; i < len(lines); i++ {
+			if len(paragraph) > 0 && startsBlock(lines, i) {
+				break
+			}
+			paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+		}
+		blocks = append(blocks, Block{Kind: BlockParagraph, Inlines: parseLines(paragraph)})
+	}
+	return blocks
+}
+
+// startsBlock reports whether line i ends a paragraph.
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	if strings.TrimSpace(line) == "" {
+		return true
+	}
+	return reFence.MatchString(line) ||
+		reHeading.MatchString(line) ||
+		reRule.MatchString(line) ||
+		reQuote.MatchString(line) ||
+		reListItem.MatchString(line) ||
+		isTableStart(lines, i)
+}
+
+// parseLines parses paragraph lines, keeping line breaks. Chat markdown treats a
+// single newline as a break rather than as a space.
+func parseLines(lines []string) []Inline {
+	var inlines []Inline
+	for i, line := range lines {
+		if i > 0 {
+			inlines = append(inlines, Inline{Kind: InlineBreak})
+		}
+		inlines = append(inlines, parseInlines(line)...)
+	}
+	return inlines
+}
+
+func parseFence(lines []string, start, indent int, fence, language string) (Block, int) {
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence[:3]) && strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence) {
+			i++
+			break
+		}
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+	return Block{Kind: BlockCode, Language: language, Code: strings.Join(code, "\n")}, i
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !reTableDelim.MatchString(lines[i+1]) {
+		return false
+	}
+	header := splitTableRow(lines[i])
+	return len(header) > 0 && len(header) == len(splitTableRow(lines[i+1]))
+}
+
+func parseTable(lines []string, start int) (Block, int) {
+	header := splitTableRow(lines[start])
+	table := &Table{
+		Header: make([]Cell, len(header)),
+		Align:  make([]Align, len(header)),
+	}
+	for col, raw := range header {
+		table.Header[col] = Cell(parseInlines(raw))
+	}
+	for col, raw := range splitTableRow(lines[start+1]) {
+		left := strings.HasPrefix(raw, ":")
+		right := strings.HasSuffix(raw, ":")
+		switch {
+		case left && right:
+			table.Align[col] = AlignCenter
+		case right:
+			table.Align[col] = AlignRight
+		case left:
+			table.Align[col] = AlignLeft
+		}
+	}
+	i := start + 2
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || !strings.Contains(line, "|") {
+			break
+		}
+		cells := splitTableRow(line)
+		row := make([]Cell, len(header))
+		for col := range row {
+			if col < len(cells) {
+				row[col] = Cell(parseInlines(cells[col]))
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return Block{Kind: BlockTable, Table: table}, i
+}
+
+// splitTableRow splits a table row into trimmed cells, honoring escaped pipes and
+// pipes inside code spans.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var (
+		cells  []string
+		cell   strings.Builder
+		inCode bool
+	)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case c == '`':
+			inCode = !inCode
+			cell.WriteByte(c)
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func parseList(lines []string, start int) (Block, int) {
+	first := reListItem.FindStringSubmatch(lines[start])
+	baseIndent := len(first[1])
+	ordered := isOrderedMarker(first[2])
+	block := Block{Kind: BlockList, Ordered: ordered, Start: 1}
+	if ordered {
+		block.Start, _ = strconv.Atoi(strings.TrimRight(first[2], ".)"))
+	}
+	i := start
+	for i < len(lines) {
+		m := reListItem.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) != baseIndent || isOrderedMarker(m[2]) != ordered {
+			break
+		}
+		contentIndent := len(m[1]) + len(m[2]) + 1
+		item := []string{m[3]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line continues the item only when indented content follows.
+				if i+1 < len(lines) && indentOf(lines[i+1]) >= contentIndent && strings.TrimSpace(lines[i+1]) != "" {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if indentOf(line) >= contentIndent {
+				item = append(item, line[contentIndent:])
+				i++
+				continue
+			}
+			if indentOf(line) > baseIndent && reListItem.MatchString(line) {
+				// Nested list indented less than the content column.
+				item = append(item, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			if reListItem.MatchString(line) || startsBlock(lines, i) {
+				break
+			}
+			// Lazy continuation of the item's paragraph.
+			item = append(item, strings.TrimSpace(line))
+			i++
+		}
+		block.Items = append(block.Items, parseBlocks(item))
+		// Skip blank lines between items of the same list.
+		next := i
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next > i && next < len(lines) {
+			if m := reListItem.FindStringSubmatch(lines[next]); m != nil && len(m[1]) == baseIndent && isOrderedMarker(m[2]) == ordered {
+				i = next
+			}
+		}
+	}
+	return block, i
+}
+
+func isOrderedMarker(marker string) bool {
+	return marker != "" && marker[0] >= '0' && marker[0] <= '9'
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// parseInlines parses the inline content of a single line.
+func parseInlines(text string) []Inline {
+	p := inlineParser{text: text}
+	return p.parse(0, len(text))
+}
+
+type inlineParser struct {
+	text string
+}
+
+// parse parses text[start:end].
+func (p *inlineParser) parse(start, end int) []Inline {
+	var (
+		out  []Inline
+		text strings.Builder
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			out = append(out, Inline{Kind: InlineText, Text: text.String()})
+			text.Reset()
+		}
+	}
+	s := p.text
+	for i := start; i < end; {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < end && isASCIIPunct(s[i+1]):
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			if node, next, ok := p.codeSpan(i, end); ok {
+				flush()
+				out = append(out, node)
+				i = next
+				continue
+			}
+			run := runLength(s, i, end, '`')
+			text.WriteString(s[i : i+run])
+			i += run
+			continue
+		case c == '!' && i+1 < end && s[i+1] == '[':
+			if node, next, ok := p.link(i+1, end); ok {
+				flush()
+				out = append(out, node)
+				i = next
+				continue
+			}
+		case c == '[':
+			if node, next, ok := p.link(i, end); ok {
+				flush()
+				out = append(out, node)
+				i = next
+				continue
+			}
+		case c == '<':
+			if node, next, ok := p.autolink(i, end); ok {
+				flush()
+				out = append(out, node)
+				i = next
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			if node, next, ok := p.emphasis(i, end); ok {
+				flush()
+				out = append(out, node)
+				i = next
+				continue
+			}
+			run := runLength(s, i, end, c)
+			text.WriteString(s[i : i+run])
+			i += run
+			continue
+		}
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+	return out
+}
+
+func (p *inlineParser) codeSpan(start, end int) (Inline, int, bool) {
+	s := p.text
+	run := runLength(s, start, end, '`')
+	for i := start + run; i < end; {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		closing := runLength(s, i, end, '`')
+		if closing == run {
+			code := s[start+run : i]
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			return Inline{Kind: InlineCode, Text: code}, i + closing, true
+		}
+		i += closing
+	}
+	return Inline{}, 0, false
+}
+
+// link parses [label](url) starting at the opening bracket.
+func (p *inlineParser) link(start, end int) (Inline, int, bool) {
+	s := p.text
+	depth := 0
+	labelEnd := -1
+	for i := start; i < end; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '`':
+			if _, next, ok := p.codeSpan(i, end); ok {
+				i = next - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				labelEnd = i
+			}
+		}
+		if labelEnd >= 0 {
+			break
+		}
+	}
+	if labelEnd < 0 || labelEnd+1 >= end || s[labelEnd+1] != '(' {
+		return Inline{}, 0, false
+	}
+	depth = 0
+	for i := labelEnd + 1; i < end; i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				target := strings.TrimSpace(s[labelEnd+2 : i])
+				// Drop an optional "title".
+				if idx := strings.IndexAny(target, " \t"); idx > 0 {
+					target = target[:idx]
+				}
+				target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+				if target == "" {
+					return Inline{}, 0, false
+				}
+				children := p.parse(start+1, labelEnd)
+				return Inline{Kind: InlineLink, URL: target, Children: children}, i + 1, true
+			}
+		}
+	}
+	return Inline{}, 0, false
+}
+
+// autolink parses <https://...>.
+func (p *inlineParser) autolink(start, end int) (Inline, int, bool) {
+	s := p.text
+	closing := strings.IndexByte(s[start:end], '>')
+	if closing < 0 {
+		return Inline{}, 0, false
+	}
+	target := s[start+1 : start+closing]
+	if strings.ContainsAny(target, " <") || !(strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "mailto:")) {
+		return Inline{}, 0, false
+	}
+	return Inline{Kind: InlineLink, URL: target, Children: []Inline{{Kind: InlineText, Text: target}}}, start + closing + 1, true
+}
+
+// emphasis parses **strong**, *emphasis*, __strong__, _emphasis_ and ~~strike~~.
+func (p *inlineParser) emphasis(start, end int) (Inline, int, bool) {
+	s := p.text
+	c := s[start]
+	run := runLength(s, start, end, c)
+	var (
+		kind  InlineKind
+		width int
+	)
+	switch {
+	case c == '~' && run == 2:
+		kind, width = InlineStrike, 2
+	case c == '~':
+		return Inline{}, 0, false
+	case run >= 3:
+		// ***text*** is strong emphasis around emphasis.
+		if node, next, ok := p.delimited(start+1, end, c, 2, InlineStrong); ok && next < end && s[next] == c {
+			return Inline{Kind: InlineEmphasis, Children: []Inline{node}}, next + 1, true
+		}
+		kind, width = InlineStrong, 2
+	case run == 2:
+		kind, width = InlineStrong, 2
+	default:
+		kind, width = InlineEmphasis, 1
+	}
+	if c == '_' && wordBefore(s, start) {
+		return Inline{}, 0, false
+	}
+	return p.delimited(start, end, c, width, kind)
+}
+
+// delimited parses a span opened by width delimiter characters at start.
+func (p *inlineParser) delimited(start, end int, c byte, width int, kind InlineKind) (Inline, int, bool) {
+	s := p.text
+	contentStart := start + width
+	if contentStart >= end || s[contentStart] == ' ' || s[contentStart] == c {
+		return Inline{}, 0, false
+	}
+	for i := contentStart; i < end; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+			continue
+		case '`':
+			if _, next, ok := p.codeSpan(i, end); ok {
+				i = next - 1
+			}
+			continue
+		}
+		if s[i] != c || i == contentStart {
+			continue
+		}
+		closing := runLength(s, i, end, c)
+		if s[i-1] == ' ' {
+			i += closing - 1
+			continue
+		}
+		if width == 1 && closing == 2 && c != '~' {
+			// A nested strong span inside emphasis.
+			if _, next, ok := p.delimited(i, end, c, 2, InlineStrong); ok {
+				i = next - 1
+				continue
+			}
+		}
+		if closing < width {
+			continue
+		}
+		if c == '_' && i+width < end && wordAt(s, i+width) {
+			i += closing - 1
+			continue
+		}
+		return Inline{Kind: kind, Children: p.parse(contentStart, i)}, i + width, true
+	}
+	return Inline{}, 0, false
+}
+
+func runLength(s string, start, end int, c byte) int {
+	n := 0
+	for start+n < end && s[start+n] == c {
+		n++
+	}
+	return n
+}
+
+// wordBefore reports whether the rune before s[i] is a letter or digit, which keeps
+// underscores in snake_case from opening emphasis.
+func wordBefore(s string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(s[:i])
+	return size > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// wordAt reports whether the rune at s[i] is a letter or digit.
+func wordAt(s string, i int) bool {
+	r, size := utf8.DecodeRuneInString(s[i:])
+	return size > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"testing"
+)
+
+func TestParseBlocks(t *testing.T) {
+	doc := Parse("# Title\n\nfirst line\nsecond line\n\n- a\n- b\n  1. nested\n\n> quoted\n\n---\n\n```go\nfmt.Println(1)\n```")
+	kinds := []BlockKind{BlockHeading, BlockParagraph, BlockList, BlockQuote, BlockRule, BlockCode}
+	if len(doc.Blocks) != len(kinds) {
+		t.Fatalf("expected %d blocks, got %d: %+v", len(kinds), len(doc.Blocks), doc.Blocks)
+	}
+	for i, kind := range kinds {
+		if doc.Blocks[i].Kind != kind {
+			t.Fatalf("block %d: expected kind %d, got %d", i, kind, doc.Blocks[i].Kind)
+		}
+	}
+	if doc.Blocks[0].Level != 1 {
+		t.Fatalf("expected heading level 1, got %d", doc.Blocks[0].Level)
+	}
+	if got := doc.Blocks[1].Inlines; len(got) != 3 || got[1].Kind != InlineBreak {
+		t.Fatalf("expected paragraph lines joined by a break, got %+v", got)
+	}
+	list := doc.Blocks[2]
+	if list.Ordered || len(list.Items) != 2 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	if nested := list.Items[1]; len(nested) != 2 || nested[1].Kind != BlockList || !nested[1].Ordered {
+		t.Fatalf("expected nested ordered list in second item, got %+v", nested)
+	}
+	code := doc.Blocks[5]
+	if code.Language != "go" || code.Code != "fmt.Println(1)" {
+		t.Fatalf("unexpected code block: %+v", code)
+	}
+}
+
+func TestParseUnclosedFence(t *testing.T) {
+	doc := Parse("intro\n```python\nprint(1)")
+	if len(doc.Blocks) != 2 || doc.Blocks[1].Kind != BlockCode || doc.Blocks[1].Code != "print(1)" {
+		t.Fatalf("expected open fence to run to the end, got %+v", doc.Blocks)
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	doc := Parse("| Name | Qty |\n|:---|---:|\n| apple | 3 |\n| `a|b` | \\| |\nafter")
+	if len(doc.Blocks) != 2 || doc.Blocks[0].Kind != BlockTable {
+		t.Fatalf("expected table and paragraph, got %+v", doc.Blocks)
+	}
+	table := doc.Blocks[0].Table
+	if len(table.Header) != 2 || len(table.Rows) != 2 {
+		t.Fatalf("unexpected table shape: %+v", table)
+	}
+	if table.Align[0] != AlignLeft || table.Align[1] != AlignRight {
+		t.Fatalf("unexpected alignment: %+v", table.Align)
+	}
+	if cell := table.Rows[1][0]; len(cell) != 1 || cell[0].Kind != InlineCode || cell[0].Text != "a|b" {
+		t.Fatalf("expected pipe inside code span to stay in the cell, got %+v", cell)
+	}
+	if cell := table.Rows[1][1]; len(cell) != 1 || cell[0].Text != "|" {
+		t.Fatalf("expected escaped pipe, got %+v", cell)
+	}
+	if !doc.HasTable() {
+		t.Fatal("expected HasTable")
+	}
+}
+
+func TestParseNotTable(t *testing.T) {
+	doc := Parse("a | b\nnot a delimiter")
+	if doc.HasTable() {
+		t.Fatalf("expected no table, got %+v", doc.Blocks)
+	}
+}
+
+func TestParseInlines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []InlineKind
+	}{
+		{"strong", "**bold**", []InlineKind{InlineStrong}},
+		{"emphasis", "*it* and _it_", []InlineKind{InlineEmphasis, InlineText, InlineEmphasis}},
+		{"strike", "~~gone~~", []InlineKind{InlineStrike}},
+		{"code", "use `x*y*z`", []InlineKind{InlineText, InlineCode}},
+		{"link", "[docs](https://example.com)", []InlineKind{InlineLink}},
+		{"image", "![alt](https://example.com/a.png)", []InlineKind{InlineLink}},
+		{"autolink", "<https://example.com>", []InlineKind{InlineLink}},
+		{"snake case", "snake_case_name", []InlineKind{InlineText}},
+		{"spaced stars", "2 * 3 * 4", []InlineKind{InlineText}},
+		{"unclosed", "**open", []InlineKind{InlineText}},
+		{"escaped", `\*literal\*`, []InlineKind{InlineText}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseInlines(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d inlines, got %+v", len(tt.want), got)
+			}
+			for i, kind := range tt.want {
+				if got[i].Kind != kind {
+					t.Fatalf("inline %d: expected kind %d, got %+v", i, kind, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseNestedInlines(t *testing.T) {
+	got := parseInlines("**bold [link](https://example.com) *and it* done**")
+	if len(got) != 1 || got[0].Kind != InlineStrong {
+		t.Fatalf("expected one strong node, got %+v", got)
+	}
+	children := got[0].Children
+	if len(children) != 5 || children[1].Kind != InlineLink || children[3].Kind != InlineEmphasis {
+		t.Fatalf("unexpected children: %+v", children)
+	}
+	if children[1].URL != "https://example.com" {
+		t.Fatalf("unexpected link url: %q", children[1].URL)
+	}
+}
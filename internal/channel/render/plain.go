@@ -0,0 +1,35 @@
+package render
+
+import "strings"
+
+// PlainText renders a document as plain text for channels without markup. Headings
+// and paragraphs keep their text, lists keep their markers and tables are laid out
+// as aligned columns.
+func PlainText(doc Document) string {
+	return strings.TrimSpace(renderBlocks(plainBackend{}, doc.Blocks))
+}
+
+type plainBackend struct{}
+
+func (plainBackend) text(s string) string               { return s }
+func (plainBackend) strong(inner string) string         { return inner }
+func (plainBackend) emphasis(inner string) string       { return inner }
+func (plainBackend) strike(inner string) string         { return inner }
+func (plainBackend) code(s string) string               { return s }
+func (plainBackend) lineBreak() string                  { return "\n" }
+func (plainBackend) heading(_ int, inner string) string { return inner }
+func (plainBackend) codeBlock(_, code string) string    { return code }
+func (plainBackend) table(t *Table) string              { return TableText(t) }
+func (plainBackend) bullet() string                     { return "• " }
+func (plainBackend) rule() string                       { return "----------" }
+
+func (plainBackend) link(label, url string) string {
+	if label == "" || label == url || strings.TrimPrefix(url, "mailto:") == label {
+		return url
+	}
+	return label + " (" + url + ")"
+}
+
+func (plainBackend) quote(inner string) string {
+	return "> " + strings.ReplaceAll(inner, "\n", "\n> ")
+}
@@ -0,0 +1,183 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sampleTable = "| Name | Qty |\n|------|----:|\n| apple | 3 |\n| 香蕉 | 12 |"
+
+func TestTableText(t *testing.T) {
+	got := TableText(Parse(sampleTable).Blocks[0].Table)
+	want := "Name  | Qty\n------+----\napple |   3\n香蕉  |  12"
+	if got != want {
+		t.Fatalf("unexpected table text:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText(Parse("# Title\n\n**Hello** [docs](https://docs.io) and `code`\n\n1. one\n2. two"))
+	want := "Title\n\nHello docs (https://docs.io) and code\n\n1. one\n2. two"
+	if got != want {
+		t.Fatalf("unexpected plain text:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestTelegramHTML(t *testing.T) {
+	got := TelegramHTML(Parse("## Sum\n\na < b **bold**\n\n" + sampleTable))
+	for _, want := range []string{"<b>Sum</b>", "a &lt; b <b>bold</b>", "<pre>Name  | Qty\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestDiscordTableEmbed(t *testing.T) {
+	msg := Discord(Parse("# Report\n\n" + sampleTable + "\n\n#### Notes\n\nuse snake_case"))
+	if len(msg.Embeds) != 1 {
+		t.Fatalf("expected one embed, got %+v", msg.Embeds)
+	}
+	fields := msg.Embeds[0].Fields
+	if len(fields) != 2 || fields[0].Name != "Name" || fields[0].Value != "apple\n香蕉" || !fields[1].Inline {
+		t.Fatalf("unexpected embed fields: %+v", fields)
+	}
+	want := "# Report\n\n**Notes**\n\nuse snake\\_case"
+	if msg.Content != want {
+		t.Fatalf("unexpected content:\n%q\nwant:\n%q", msg.Content, want)
+	}
+}
+
+func TestDiscordWideTableFallsBackToCodeBlock(t *testing.T) {
+	msg := Discord(Parse("| a | b | c | d |\n|---|---|---|---|\n| 1 | 2 | 3 | 4 |"))
+	if len(msg.Embeds) != 0 {
+		t.Fatalf("expected no embeds, got %+v", msg.Embeds)
+	}
+	if !strings.HasPrefix(msg.Content, "```\na | b | c | d\n") {
+		t.Fatalf("expected table code block, got:\n%s", msg.Content)
+	}
+}
+
+func TestSplitMarkdown(t *testing.T) {
+	code := strings.Repeat("line of code\n", 10)
+	text := "intro\n```go\n" + code + "```\nafter"
+	chunks := SplitMarkdown(text, 60)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	var rejoined []string
+	for i, chunk := range chunks {
+		if n := len([]rune(chunk)); n > 60 {
+			t.Fatalf("chunk %d has %d runes", i, n)
+		}
+		if strings.Count(chunk, "```")%2 != 0 {
+			t.Fatalf("chunk %d has an unbalanced fence:\n%s", i, chunk)
+		}
+		rejoined = append(rejoined, chunk)
+	}
+	if !strings.HasPrefix(chunks[1], "```go\n") {
+		t.Fatalf("expected fence to be reopened with language, got:\n%s", chunks[1])
+	}
+	if got := strings.Count(strings.Join(rejoined, "\n"), "line of code"); got != 10 {
+		t.Fatalf("expected all code lines to survive, got %d", got)
+	}
+}
+
+func TestSplitMarkdownShortAndLongLines(t *testing.T) {
+	if got := SplitMarkdown("short", 10); len(got) != 1 || got[0] != "short" {
+		t.Fatalf("unexpected split: %q", got)
+	}
+	if got := SplitMarkdown("  ", 10); got != nil {
+		t.Fatalf("expected nil for blank text, got %q", got)
+	}
+	got := SplitMarkdown(strings.Repeat("x", 25), 10)
+	if len(got) != 3 || got[2] != "xxxxx" {
+		t.Fatalf("expected hard-wrapped chunks, got %q", got)
+	}
+}
+
+func TestFeishuPost(t *testing.T) {
+	content, err := FeishuPost(Parse("# Title\n\n**bold** [docs](https://docs.io)\n\n- item\n\n```go\nx := 1\n```\n\n" + sampleTable))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var post struct {
+		ZhCn struct {
+			Content [][]map[string]any `json:"content"`
+		} `json:"zh_cn"`
+	}
+	if err := json.Unmarshal([]byte(content), &post); err != nil {
+		t.Fatalf("decode post: %v", err)
+	}
+	rows := post.ZhCn.Content
+	if len(rows) != 5 {
+		t.Fatalf("expected 5 rows, got %d: %s", len(rows), content)
+	}
+	if rows[0][0]["text"] != "Title" || rows[0][0]["style"].([]any)[0] != "bold" {
+		t.Fatalf("unexpected heading row: %+v", rows[0])
+	}
+	if rows[1][2]["tag"] != "a" || rows[1][2]["href"] != "https://docs.io" {
+		t.Fatalf("unexpected link element: %+v", rows[1])
+	}
+	if rows[2][0]["text"] != "• " || rows[2][1]["text"] != "item" {
+		t.Fatalf("unexpected list row: %+v", rows[2])
+	}
+	if rows[3][0]["tag"] != "code_block" || rows[3][0]["language"] != "GO" {
+		t.Fatalf("unexpected code row: %+v", rows[3])
+	}
+	if rows[4][0]["tag"] != "code_block" || !strings.Contains(rows[4][0]["text"].(string), "apple |   3") {
+		t.Fatalf("expected table as code block: %+v", rows[4])
+	}
+}
+
+func TestFeishuCardElements(t *testing.T) {
+	elements := FeishuCardElements(Parse("## Result\n\n" + sampleTable + "\n\n---\n\n2 * 3 <b>"))
+	if len(elements) != 4 {
+		t.Fatalf("expected 4 elements, got %d: %+v", len(elements), elements)
+	}
+	if elements[0]["tag"] != "markdown" || elements[0]["content"] != "**Result**" {
+		t.Fatalf("unexpected heading element: %+v", elements[0])
+	}
+	table := elements[1]
+	if table["tag"] != "table" || table["page_size"] != 2 {
+		t.Fatalf("unexpected table element: %+v", table)
+	}
+	columns := table["columns"].([]map[string]any)
+	if columns[0]["display_name"] != "Name" || columns[1]["horizontal_align"] != "right" {
+		t.Fatalf("unexpected columns: %+v", columns)
+	}
+	rows := table["rows"].([]map[string]any)
+	if rows[1]["c0"] != "香蕉" || rows[1]["c1"] != "12" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if elements[2]["tag"] != "hr" {
+		t.Fatalf("expected hr, got %+v", elements[2])
+	}
+	if elements[3]["content"] != "2 &#42; 3 &lt;b&gt;" {
+		t.Fatalf("expected escaped text, got %+v", elements[3])
+	}
+}
+
+func TestHTML(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "  ", ""},
+		{"paragraph", "hello <world>", "<p>hello &lt;world&gt;</p>"},
+		{"inline", "**bold** *it* ~~del~~ `a<b`", "<p><strong>bold</strong> <em>it</em> <del>del</del> <code>a&lt;b</code></p>"},
+		{"link", "see [docs](https://example.com)", `<p>see <a href="https://example.com">docs</a></p>`},
+		{"heading", "## Title", "<h2>Title</h2>"},
+		{"list", "- a\n- b\n\n3. c", `<ul><li>a</li><li>b</li></ul><ol start="3"><li>c</li></ol>`},
+		{"code", "```go\nx := 1 < 2\n```", `<pre><code class="language-go">x := 1 &lt; 2</code></pre>`},
+		{"quote", "> quoted\n\nafter", "<blockquote><p>quoted</p></blockquote><p>after</p>"},
+		{"lines", "a\nb", "<p>a<br>b</p>"},
+		{"table", "| a | b |\n|---|--:|\n| 1 | 2 |", `<table><thead><tr><th>a</th><th align="right">b</th></tr></thead><tbody><tr><td>1</td><td align="right">2</td></tr></tbody></table>`},
+	}
+	for _, tc := range cases {
+		if got := HTML(Parse(tc.in)); got != tc.want {
+			t.Fatalf("%s: HTML(%q) = %q, want %q", tc.name, tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package render
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TableText lays a table out as monospace text with aligned columns. It is the
+// fallback for platforms without native tables, sent inside a code block so the
+// columns line up.
+func TableText(t *Table) string {
+	if t == nil || len(t.Header) == 0 {
+		return ""
+	}
+	rows := make([][]string, 0, len(t.Rows)+1)
+	rows = append(rows, cellTexts(t.Header))
+	for _, row := range t.Rows {
+		rows = append(rows, cellTexts(row))
+	}
+	widths := make([]int, len(t.Header))
+	for _, row := range rows {
+		for col, cell := range row {
+			widths[col] = max(widths[col], displayWidth(cell))
+		}
+	}
+	var buf strings.Builder
+	writeRow := func(row []string) {
+		for col, cell := range row {
+			if col > 0 {
+				buf.WriteString(" | ")
+			}
+			buf.WriteString(padCell(cell, widths[col], alignOf(t, col), col == len(row)-1))
+		}
+		buf.WriteString("\n")
+	}
+	writeRow(rows[0])
+	for col, width := range widths {
+		if col > 0 {
+			buf.WriteString("-+-")
+		}
+		buf.WriteString(strings.Repeat("-", max(width, 1)))
+	}
+	buf.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func cellTexts(cells []Cell) []string {
+	texts := make([]string, len(cells))
+	for i, cell := range cells {
+		texts[i] = strings.ReplaceAll(renderInlines(plainBackend{}, cell), "\n", " ")
+	}
+	return texts
+}
+
+func alignOf(t *Table, col int) Align {
+	if col < len(t.Align) {
+		return t.Align[col]
+	}
+	return AlignNone
+}
+
+// padCell pads a cell to width. The last column is not padded on the right to
+// avoid trailing spaces.
+func padCell(cell string, width int, align Align, last bool) string {
+	gap := width - displayWidth(cell)
+	if gap <= 0 {
+		return cell
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", gap) + cell
+	case AlignCenter:
+		left := gap / 2
+		if last {
+			return strings.Repeat(" ", left) + cell
+		}
+		return strings.Repeat(" ", left) + cell + strings.Repeat(" ", gap-left)
+	default:
+		if last {
+			return cell
+		}
+		return cell + strings.Repeat(" ", gap)
+	}
+}
+
+// displayWidth approximates the monospace width of text: wide East Asian characters
+// and emoji take two columns, combining marks none.
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Mn, r) || r == '\u200d' || (r >= 0xfe00 && r <= 0xfe0f):
+		case isWide(r):
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115f) ||
+		(r >= 0x2e80 && r <= 0xa4cf && r != 0x303f) ||
+		(r >= 0xac00 && r <= 0xd7a3) ||
+		(r >= 0xf900 && r <= 0xfaff) ||
+		(r >= 0xfe30 && r <= 0xfe4f) ||
+		(r >= 0xff00 && r <= 0xff60) ||
+		(r >= 0xffe0 && r <= 0xffe6) ||
+		(r >= 0x1f300 && r <= 0x1f64f) ||
+		(r >= 0x1f900 && r <= 0x1f9ff) ||
+		(r >= 0x20000 && r <= 0x3fffd)
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TelegramHTML renders a document in the HTML subset accepted by the Bot API with
+// parse_mode HTML. Headings become bold lines and tables are sent as preformatted
+// text, since Telegram has neither.
+func TelegramHTML(doc Document) string {
+	return strings.TrimSpace(renderBlocks(telegramBackend{}, doc.Blocks))
+}
+
+type telegramBackend struct{}
+
+func (telegramBackend) text(s string) string         { return escapeHTML(s) }
+func (telegramBackend) strong(inner string) string   { return "<b>" + inner + "</b>" }
+func (telegramBackend) emphasis(inner string) string { return "<i>" + inner + "</i>" }
+func (telegramBackend) strike(inner string) string   { return "<s>" + inner + "</s>" }
+func (telegramBackend) code(s string) string         { return "<code>" + escapeHTML(s) + "</code>" }
+func (telegramBackend) lineBreak() string            { return "\n" }
+func (telegramBackend) bullet() string               { return "• " }
+func (telegramBackend) rule() string                 { return "──────────" }
+
+func (telegramBackend) link(label, url string) string {
+	return `<a href="` + escapeHTMLAttr(url) + `">` + label + "</a>"
+}
+
+func (telegramBackend) heading(_ int, inner string) string {
+	return "<b>" + inner + "</b>"
+}
+
+func (telegramBackend) codeBlock(language, code string) string {
+	escaped := escapeHTML(strings.TrimRight(code, "\n"))
+	if language != "" {
+		return fmt.Sprintf(`<pre><code class="language-%s">%s</code></pre>`, escapeHTMLAttr(language), escaped)
+	}
+	return "<pre>" + escaped + "</pre>"
+}
+
+func (telegramBackend) quote(inner string) string {
+	return "<blockquote>" + inner + "</blockquote>"
+}
+
+func (telegramBackend) table(t *Table) string {
+	return "<pre>" + escapeHTML(TableText(t)) + "</pre>"
+}
+
+// escapeHTML escapes the characters Telegram requires to be escaped in HTML text.
+func escapeHTML(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+	return text
+}
+
+func escapeHTMLAttr(text string) string {
+	return strings.ReplaceAll(escapeHTML(text), `"`, "&quot;")
+}
@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// MetadataVoiceReply is the route metadata key holding a per-conversation override of the
+// bot's voice reply setting (true or false; absent means the bot setting applies).
+const MetadataVoiceReply = "voice_reply"
+
 // Route maps external channel conversations to an internal conversation.
 type Route struct {
 	ID               string         `json:"id"`
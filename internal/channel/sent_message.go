@@ -0,0 +1,158 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// ErrMessageEditUnsupported is returned when a channel cannot edit or delete sent messages.
+var ErrMessageEditUnsupported = errors.New("channel does not support editing sent messages")
+
+// SentMessage identifies a message the bot delivered to a platform.
+type SentMessage struct {
+	Target    string `json:"target"`
+	MessageID string `json:"message_id"`
+}
+
+// SentMessageRecorder collects the platform IDs of messages sent within one
+// scope, such as a single reply or tool call. It is safe for concurrent use.
+type SentMessageRecorder struct {
+	mu       sync.Mutex
+	messages []SentMessage
+	// parent also receives every recorded message.
+	parent *SentMessageRecorder
+}
+
+// NewSentMessageRecorder creates an empty recorder.
+func NewSentMessageRecorder() *SentMessageRecorder {
+	return &SentMessageRecorder{}
+}
+
+// Messages returns the recorded messages in send order.
+func (r *SentMessageRecorder) Messages() []SentMessage {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SentMessage(nil), r.messages...)
+}
+
+// MessageIDs returns the recorded platform message IDs in send order.
+func (r *SentMessageRecorder) MessageIDs() []string {
+	messages := r.Messages()
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.MessageID)
+	}
+	return ids
+}
+
+func (r *SentMessageRecorder) record(msg SentMessage) {
+	if r.parent != nil {
+		r.parent.record(msg)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.messages {
+		if existing == msg {
+			return
+		}
+	}
+	r.messages = append(r.messages, msg)
+}
+
+type sentMessageRecorderKey struct{}
+
+// WithSentMessageRecorder returns a context whose sends are reported to recorder.
+func WithSentMessageRecorder(ctx context.Context, recorder *SentMessageRecorder) context.Context {
+	return context.WithValue(ctx, sentMessageRecorderKey{}, recorder)
+}
+
+// withNestedSentMessageRecorder attaches a new recorder to ctx that also reports to
+// the recorder already carried by ctx, if any.
+func withNestedSentMessageRecorder(ctx context.Context) (context.Context, *SentMessageRecorder) {
+	parent, _ := ctx.Value(sentMessageRecorderKey{}).(*SentMessageRecorder)
+	recorder := &SentMessageRecorder{parent: parent}
+	return WithSentMessageRecorder(ctx, recorder), recorder
+}
+
+// ReportSentMessage records a delivered platform message on the recorder carried
+// by ctx. Adapters call it after every successful send, including the first
+// message of a stream; it is a no-op when no recorder is attached.
+func ReportSentMessage(ctx context.Context, target, messageID string) {
+	if ctx == nil {
+		return
+	}
+	recorder, _ := ctx.Value(sentMessageRecorderKey{}).(*SentMessageRecorder)
+	messageID = strings.TrimSpace(messageID)
+	if recorder == nil || messageID == "" {
+		return
+	}
+	recorder.record(SentMessage{Target: strings.TrimSpace(target), MessageID: messageID})
+}
+
+// EditMessage replaces the content of a message the bot sent earlier.
+func (m *Manager) EditMessage(ctx context.Context, botID string, channelType ChannelType, req EditMessageRequest) error {
+	editor, config, target, messageID, err := m.resolveMessageEditor(ctx, botID, channelType, req.Target, req.MessageID)
+	if err != nil {
+		return err
+	}
+	if req.Message.IsEmpty() {
+		return fmt.Errorf("message is required")
+	}
+	if m.logger != nil {
+		m.logger.Info("edit outbound",
+			slog.String("channel", channelType.String()),
+			slog.String("bot_id", botID),
+			slog.String("message_id", messageID),
+		)
+	}
+	return editor.Update(ctx, config, target, messageID, req.Message)
+}
+
+// DeleteMessage retracts a message the bot sent earlier.
+func (m *Manager) DeleteMessage(ctx context.Context, botID string, channelType ChannelType, req DeleteMessageRequest) error {
+	editor, config, target, messageID, err := m.resolveMessageEditor(ctx, botID, channelType, req.Target, req.MessageID)
+	if err != nil {
+		return err
+	}
+	if m.logger != nil {
+		m.logger.Info("delete outbound",
+			slog.String("channel", channelType.String()),
+			slog.String("bot_id", botID),
+			slog.String("message_id", messageID),
+		)
+	}
+	return editor.Unsend(ctx, config, target, messageID)
+}
+
+func (m *Manager) resolveMessageEditor(ctx context.Context, botID string, channelType ChannelType, target, messageID string) (MessageEditor, ChannelConfig, string, string, error) {
+	if m.service == nil {
+		return nil, ChannelConfig{}, "", "", fmt.Errorf("channel manager not configured")
+	}
+	editor, ok := m.registry.GetMessageEditor(channelType)
+	if !ok {
+		return nil, ChannelConfig{}, "", "", fmt.Errorf("%w: %s", ErrMessageEditUnsupported, channelType)
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, ChannelConfig{}, "", "", fmt.Errorf("target is required")
+	}
+	if normalized, ok := m.registry.NormalizeTarget(channelType, target); ok {
+		target = normalized
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return nil, ChannelConfig{}, "", "", fmt.Errorf("message_id is required")
+	}
+	config, err := m.service.ResolveEffectiveConfig(ctx, botID, channelType)
+	if err != nil {
+		return nil, ChannelConfig{}, "", "", err
+	}
+	return editor, config, target, messageID, nil
+}
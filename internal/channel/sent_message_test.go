@@ -0,0 +1,77 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type editingAdapter struct {
+	flakySender
+	updated  []string
+	unsent   []string
+	lastText string
+}
+
+func (a *editingAdapter) Update(_ context.Context, _ ChannelConfig, target, messageID string, msg Message) error {
+	a.updated = append(a.updated, target+"/"+messageID)
+	a.lastText = msg.Text
+	return nil
+}
+
+func (a *editingAdapter) Unsend(_ context.Context, _ ChannelConfig, target, messageID string) error {
+	a.unsent = append(a.unsent, target+"/"+messageID)
+	return nil
+}
+
+func TestReportSentMessage(t *testing.T) {
+	t.Parallel()
+
+	// Reporting without a recorder is a no-op.
+	ReportSentMessage(context.Background(), "chat-1", "1")
+
+	recorder := NewSentMessageRecorder()
+	ctx := WithSentMessageRecorder(context.Background(), recorder)
+	ReportSentMessage(ctx, "chat-1", "1")
+	ReportSentMessage(ctx, "chat-1", " ")
+	ReportSentMessage(ctx, "chat-1", "1")
+	ReportSentMessage(ctx, "chat-1", "2")
+	got := recorder.MessageIDs()
+	if len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Fatalf("unexpected ids: %v", got)
+	}
+}
+
+func TestManagerEditAndDeleteMessage(t *testing.T) {
+	t.Parallel()
+
+	adapter := &editingAdapter{}
+	reg := NewRegistry()
+	reg.MustRegister(adapter)
+	cfgStore := &fakeConfigStore{effectiveConfig: ChannelConfig{ID: "cfg-1", BotID: "bot-1", ChannelType: "flaky"}}
+	manager := NewManager(nil, reg, cfgStore, &fakeInboundProcessor{})
+
+	ctx := context.Background()
+	err := manager.EditMessage(ctx, "bot-1", "flaky", EditMessageRequest{Target: "chat-1", MessageID: "m1", Message: Message{Text: "fixed"}})
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if err := manager.DeleteMessage(ctx, "bot-1", "flaky", DeleteMessageRequest{Target: "chat-1", MessageID: "m2"}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(adapter.updated) != 1 || adapter.updated[0] != "chat-1/m1" || adapter.lastText != "fixed" {
+		t.Fatalf("unexpected updates: %v %q", adapter.updated, adapter.lastText)
+	}
+	if len(adapter.unsent) != 1 || adapter.unsent[0] != "chat-1/m2" {
+		t.Fatalf("unexpected deletes: %v", adapter.unsent)
+	}
+	if err := manager.DeleteMessage(ctx, "bot-1", "flaky", DeleteMessageRequest{Target: "chat-1"}); err == nil {
+		t.Fatal("expected error without message id")
+	}
+
+	plain := newFlakyManager(&flakySender{}, nil)
+	err = plain.EditMessage(ctx, "bot-1", "flaky", EditMessageRequest{Target: "chat-1", MessageID: "m1", Message: Message{Text: "x"}})
+	if !errors.Is(err, ErrMessageEditUnsupported) {
+		t.Fatalf("expected ErrMessageEditUnsupported, got %v", err)
+	}
+}
@@ -18,6 +18,9 @@ import (
 // ErrChannelConfigNotFound indicates the bot has no persisted config for the channel type.
 var ErrChannelConfigNotFound = errors.New("channel config not found")
 
+// ErrInvalidRouting is returned when a channel config's routing settings fail validation.
+var ErrInvalidRouting = errors.New("invalid routing config")
+
 // Store provides CRUD operations for channel configurations, user bindings, and sessions.
 type Store struct {
 	queries  *sqlc.Queries
@@ -75,6 +78,9 @@ func (s *Store) UpsertConfig(ctx context.Context, botID string, channelType Chan
 	if routing == nil {
 		routing = map[string]any{}
 	}
+	if err := ValidateTriggerPolicy(routing); err != nil {
+		return ChannelConfig{}, fmt.Errorf("%w: %v", ErrInvalidRouting, err)
+	}
 	routingPayload, err := json.Marshal(routing)
 	if err != nil {
 		return ChannelConfig{}, err
@@ -0,0 +1,387 @@
+package channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// RoutingTriggerKey is the key of the group trigger policy in a channel config's routing map.
+const RoutingTriggerKey = "trigger"
+
+// TriggerMode selects which group messages the bot answers without being addressed explicitly.
+type TriggerMode string
+
+const (
+	// TriggerModeMention answers mentions, replies to the bot, commands and configured
+	// keywords, patterns and aliases.
+	TriggerModeMention TriggerMode = "mention"
+	// TriggerModeSmart additionally asks a classifier model whether the bot is addressed.
+	TriggerModeSmart TriggerMode = "smart"
+	// TriggerModeAlways answers every group message.
+	TriggerModeAlways TriggerMode = "always"
+)
+
+// TriggerPolicyConfig is the JSON form of the group trigger policy stored under
+// routing.trigger. Routes holds overrides keyed by platform conversation ID; set fields
+// of an override replace the bot-wide values.
+type TriggerPolicyConfig struct {
+	Mode             TriggerMode                    `json:"mode,omitempty"`
+	Keywords         []string                       `json:"keywords,omitempty"`
+	Patterns         []string                       `json:"patterns,omitempty"`
+	Aliases          []string                       `json:"aliases,omitempty"`
+	CooldownSeconds  *int                           `json:"cooldown_seconds,omitempty"`
+	ReplyProbability *float64                       `json:"reply_probability,omitempty"`
+	QuietHours       *QuietHoursConfig              `json:"quiet_hours,omitempty"`
+	Routes           map[string]TriggerPolicyConfig `json:"routes,omitempty"`
+}
+
+// QuietHoursConfig is a daily window ("22:00" to "07:00") in an IANA time zone (UTC when empty).
+type QuietHoursConfig struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// TriggerPolicy is a compiled trigger policy for one conversation. Its keyword, pattern,
+// alias and mode triggers are "ambient": they apply to group messages that do not mention
+// or reply to the bot, and are subject to the cooldown, reply probability and quiet hours.
+type TriggerPolicy struct {
+	Mode             TriggerMode
+	Keywords         []string
+	Patterns         []*regexp.Regexp
+	Aliases          []string
+	Cooldown         time.Duration
+	ReplyProbability float64
+	QuietHours       *QuietHours
+}
+
+// QuietHours is a compiled daily quiet window.
+type QuietHours struct {
+	start    int // minutes after midnight
+	end      int
+	location *time.Location
+}
+
+// DefaultTriggerPolicy answers explicit addressing only.
+func DefaultTriggerPolicy() TriggerPolicy {
+	return TriggerPolicy{Mode: TriggerModeMention, ReplyProbability: 1}
+}
+
+// ParseTriggerPolicy reads the trigger policy from a routing map and applies the override
+// for conversationID. A missing policy yields DefaultTriggerPolicy.
+func ParseTriggerPolicy(routing map[string]any, conversationID string) (TriggerPolicy, error) {
+	cfg, err := decodeTriggerPolicyConfig(routing)
+	if err != nil {
+		return TriggerPolicy{}, err
+	}
+	if cfg == nil {
+		return DefaultTriggerPolicy(), nil
+	}
+	merged := *cfg
+	if override, ok := cfg.Routes[strings.TrimSpace(conversationID)]; ok {
+		merged = mergeTriggerPolicyConfig(merged, override)
+	}
+	return compileTriggerPolicy(merged)
+}
+
+// TriggerPolicyCache keeps the compiled trigger policies of each channel config revision,
+// so group messages do not decode the policy and compile its patterns again. A revision is
+// identified by the config's UpdatedAt; configs without an ID or UpdatedAt are not cached.
+// It is safe for concurrent use.
+type TriggerPolicyCache struct {
+	mu      sync.Mutex
+	entries map[string]triggerPolicyEntry // keyed by config ID
+}
+
+// triggerPolicyEntry holds the compiled policies of one config revision.
+type triggerPolicyEntry struct {
+	updatedAt time.Time
+	base      compiledTriggerPolicy
+	routes    map[string]compiledTriggerPolicy // keyed by conversation ID
+}
+
+type compiledTriggerPolicy struct {
+	policy TriggerPolicy
+	err    error
+}
+
+// NewTriggerPolicyCache creates an empty TriggerPolicyCache.
+func NewTriggerPolicyCache() *TriggerPolicyCache {
+	return &TriggerPolicyCache{entries: make(map[string]triggerPolicyEntry)}
+}
+
+// Policy returns the trigger policy of cfg for conversationID, as ParseTriggerPolicy does,
+// compiling it only when the config revision changed.
+func (c *TriggerPolicyCache) Policy(cfg ChannelConfig, conversationID string) (TriggerPolicy, error) {
+	configID := strings.TrimSpace(cfg.ID)
+	if c == nil || configID == "" || cfg.UpdatedAt.IsZero() {
+		return ParseTriggerPolicy(cfg.Routing, conversationID)
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[configID]
+	if !ok || !entry.updatedAt.Equal(cfg.UpdatedAt) {
+		entry = compileTriggerPolicyEntry(cfg)
+		c.entries[configID] = entry
+	}
+	c.mu.Unlock()
+	compiled, ok := entry.routes[strings.TrimSpace(conversationID)]
+	if !ok {
+		compiled = entry.base
+	}
+	return compiled.policy, compiled.err
+}
+
+func compileTriggerPolicyEntry(cfg ChannelConfig) triggerPolicyEntry {
+	entry := triggerPolicyEntry{updatedAt: cfg.UpdatedAt}
+	decoded, err := decodeTriggerPolicyConfig(cfg.Routing)
+	if err != nil {
+		entry.base.err = err
+		return entry
+	}
+	if decoded == nil {
+		entry.base.policy = DefaultTriggerPolicy()
+		return entry
+	}
+	entry.base.policy, entry.base.err = compileTriggerPolicy(*decoded)
+	if len(decoded.Routes) > 0 {
+		entry.routes = make(map[string]compiledTriggerPolicy, len(decoded.Routes))
+		for conversationID, override := range decoded.Routes {
+			var compiled compiledTriggerPolicy
+			compiled.policy, compiled.err = compileTriggerPolicy(mergeTriggerPolicyConfig(*decoded, override))
+			entry.routes[conversationID] = compiled
+		}
+	}
+	return entry
+}
+
+// ValidateTriggerPolicy checks the trigger policy of a routing map, including every
+// per-conversation override.
+func ValidateTriggerPolicy(routing map[string]any) error {
+	cfg, err := decodeTriggerPolicyConfig(routing)
+	if err != nil || cfg == nil {
+		return err
+	}
+	if _, err := compileTriggerPolicy(*cfg); err != nil {
+		return err
+	}
+	for conversationID, override := range cfg.Routes {
+		if _, err := compileTriggerPolicy(mergeTriggerPolicyConfig(*cfg, override)); err != nil {
+			return fmt.Errorf("trigger route %s: %w", conversationID, err)
+		}
+	}
+	return nil
+}
+
+func decodeTriggerPolicyConfig(routing map[string]any) (*TriggerPolicyConfig, error) {
+	raw, ok := routing[RoutingTriggerKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encode trigger policy: %w", err)
+	}
+	var cfg TriggerPolicyConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid trigger policy: %w", err)
+	}
+	return &cfg, nil
+}
+
+func mergeTriggerPolicyConfig(base, override TriggerPolicyConfig) TriggerPolicyConfig {
+	merged := base
+	merged.Routes = nil
+	if override.Mode != "" {
+		merged.Mode = override.Mode
+	}
+	if override.Keywords != nil {
+		merged.Keywords = override.Keywords
+	}
+	if override.Patterns != nil {
+		merged.Patterns = override.Patterns
+	}
+	if override.Aliases != nil {
+		merged.Aliases = override.Aliases
+	}
+	if override.CooldownSeconds != nil {
+		merged.CooldownSeconds = override.CooldownSeconds
+	}
+	if override.ReplyProbability != nil {
+		merged.ReplyProbability = override.ReplyProbability
+	}
+	if override.QuietHours != nil {
+		merged.QuietHours = override.QuietHours
+	}
+	return merged
+}
+
+func compileTriggerPolicy(cfg TriggerPolicyConfig) (TriggerPolicy, error) {
+	policy := DefaultTriggerPolicy()
+	switch mode := TriggerMode(strings.ToLower(strings.TrimSpace(string(cfg.Mode)))); mode {
+	case "":
+	case TriggerModeMention, TriggerModeSmart, TriggerModeAlways:
+		policy.Mode = mode
+	default:
+		return TriggerPolicy{}, fmt.Errorf("invalid trigger mode %q", cfg.Mode)
+	}
+	policy.Keywords = normalizeTriggerWords(cfg.Keywords)
+	policy.Aliases = normalizeTriggerWords(cfg.Aliases)
+	for _, raw := range cfg.Patterns {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return TriggerPolicy{}, fmt.Errorf("invalid trigger pattern %q: %w", raw, err)
+		}
+		policy.Patterns = append(policy.Patterns, re)
+	}
+	if cfg.CooldownSeconds != nil {
+		if *cfg.CooldownSeconds < 0 {
+			return TriggerPolicy{}, fmt.Errorf("cooldown_seconds must not be negative")
+		}
+		policy.Cooldown = time.Duration(*cfg.CooldownSeconds) * time.Second
+	}
+	if cfg.ReplyProbability != nil {
+		if *cfg.ReplyProbability < 0 || *cfg.ReplyProbability > 1 {
+			return TriggerPolicy{}, fmt.Errorf("reply_probability must be between 0 and 1")
+		}
+		policy.ReplyProbability = *cfg.ReplyProbability
+	}
+	if cfg.QuietHours != nil {
+		quiet, err := compileQuietHours(*cfg.QuietHours)
+		if err != nil {
+			return TriggerPolicy{}, err
+		}
+		policy.QuietHours = quiet
+	}
+	return policy, nil
+}
+
+func normalizeTriggerWords(words []string) []string {
+	result := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			result = append(result, word)
+		}
+	}
+	return result
+}
+
+func compileQuietHours(cfg QuietHoursConfig) (*QuietHours, error) {
+	start, err := parseClockMinutes(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet_hours start: %w", err)
+	}
+	end, err := parseClockMinutes(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet_hours end: %w", err)
+	}
+	location := time.UTC
+	if tz := strings.TrimSpace(cfg.Timezone); tz != "" {
+		location, err = time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quiet_hours timezone: %w", err)
+		}
+	}
+	return &QuietHours{start: start, end: end, location: location}, nil
+}
+
+// parseClockMinutes parses "HH:MM" into minutes after midnight.
+func parseClockMinutes(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return h*60 + m, nil
+}
+
+// Contains reports whether t falls in the quiet window. Windows may wrap past midnight;
+// equal start and end mean no quiet time.
+func (q *QuietHours) Contains(t time.Time) bool {
+	if q == nil || q.start == q.end {
+		return false
+	}
+	local := t.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+	if q.start < q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// MatchText reports whether text hits a keyword (substring), an alias (whole word) or a
+// pattern. Keywords and aliases are matched case-insensitively.
+func (p TriggerPolicy) MatchText(text string) bool {
+	if strings.TrimSpace(text) == "" {
+		return false
+	}
+	lower := strings.ToLower(text)
+	for _, keyword := range p.Keywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	for _, alias := range p.Aliases {
+		if containsWord(lower, alias) {
+			return true
+		}
+	}
+	for _, re := range p.Patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether word occurs in text delimited by non-alphanumeric runes.
+func containsWord(text, word string) bool {
+	for offset := 0; offset < len(text); {
+		idx := strings.Index(text[offset:], word)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(word)
+		if !isWordRuneBefore(text, start) && !isWordRuneAfter(text, end) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+func isWordRuneBefore(text string, idx int) bool {
+	if idx == 0 {
+		return false
+	}
+	r := []rune(text[:idx])
+	last := r[len(r)-1]
+	return unicode.IsLetter(last) || unicode.IsDigit(last)
+}
+
+func isWordRuneAfter(text string, idx int) bool {
+	if idx >= len(text) {
+		return false
+	}
+	for _, r := range text[idx:] {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
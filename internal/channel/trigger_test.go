@@ -0,0 +1,159 @@
+package channel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/channel"
+)
+
+func TestParseTriggerPolicyDefault(t *testing.T) {
+	policy, err := channel.ParseTriggerPolicy(map[string]any{}, "chat-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.Mode != channel.TriggerModeMention || policy.ReplyProbability != 1 || policy.QuietHours != nil {
+		t.Fatalf("unexpected default policy: %+v", policy)
+	}
+	if policy.MatchText("hello") {
+		t.Fatalf("default policy should not match ambient text")
+	}
+}
+
+func TestParseTriggerPolicyRouteOverride(t *testing.T) {
+	routing := map[string]any{
+		channel.RoutingTriggerKey: map[string]any{
+			"mode":             "smart",
+			"keywords":         []any{"deploy"},
+			"cooldown_seconds": 30,
+			"routes": map[string]any{
+				"chat-2": map[string]any{"mode": "always", "keywords": []any{}},
+			},
+		},
+	}
+	base, err := channel.ParseTriggerPolicy(routing, "chat-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if base.Mode != channel.TriggerModeSmart || base.Cooldown != 30*time.Second || len(base.Keywords) != 1 {
+		t.Fatalf("unexpected base policy: %+v", base)
+	}
+	override, err := channel.ParseTriggerPolicy(routing, "chat-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if override.Mode != channel.TriggerModeAlways || len(override.Keywords) != 0 || override.Cooldown != 30*time.Second {
+		t.Fatalf("unexpected override policy: %+v", override)
+	}
+}
+
+func TestTriggerPolicyCache(t *testing.T) {
+	cache := channel.NewTriggerPolicyCache()
+	revision := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := channel.ChannelConfig{
+		ID:        "cfg-1",
+		UpdatedAt: revision,
+		Routing: map[string]any{
+			channel.RoutingTriggerKey: map[string]any{
+				"keywords": []any{"deploy"},
+				"routes": map[string]any{
+					"chat-2": map[string]any{"mode": "always"},
+				},
+			},
+		},
+	}
+	policy, err := cache.Policy(cfg, "chat-1")
+	if err != nil || !policy.MatchText("deploy now") {
+		t.Fatalf("unexpected policy %+v err=%v", policy, err)
+	}
+	override, err := cache.Policy(cfg, "chat-2")
+	if err != nil || override.Mode != channel.TriggerModeAlways {
+		t.Fatalf("unexpected override %+v err=%v", override, err)
+	}
+
+	// The same revision is served from the cache.
+	cfg.Routing[channel.RoutingTriggerKey] = map[string]any{"patterns": []any{"("}}
+	if _, err := cache.Policy(cfg, "chat-1"); err != nil {
+		t.Fatalf("expected cached policy, got %v", err)
+	}
+	// A new revision is compiled again.
+	cfg.UpdatedAt = revision.Add(time.Second)
+	if _, err := cache.Policy(cfg, "chat-1"); err == nil {
+		t.Fatalf("expected the new revision to be compiled")
+	}
+}
+
+func TestValidateTriggerPolicy(t *testing.T) {
+	cases := map[string]map[string]any{
+		"mode":        {"mode": "sometimes"},
+		"pattern":     {"patterns": []any{"("}},
+		"cooldown":    {"cooldown_seconds": -1},
+		"probability": {"reply_probability": 1.5},
+		"quiet start": {"quiet_hours": map[string]any{"start": "25:00", "end": "07:00"}},
+		"timezone":    {"quiet_hours": map[string]any{"start": "22:00", "end": "07:00", "timezone": "Mars/Base"}},
+		"route":       {"routes": map[string]any{"chat-1": map[string]any{"patterns": []any{"["}}}},
+	}
+	for name, trigger := range cases {
+		if err := channel.ValidateTriggerPolicy(map[string]any{channel.RoutingTriggerKey: trigger}); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	valid := map[string]any{
+		"mode":              "smart",
+		"patterns":          []any{`(?i)\bhelp\b`},
+		"reply_probability": 0.5,
+		"quiet_hours":       map[string]any{"start": "22:00", "end": "07:00", "timezone": "Europe/Berlin"},
+	}
+	if err := channel.ValidateTriggerPolicy(map[string]any{channel.RoutingTriggerKey: valid}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTriggerPolicyMatchText(t *testing.T) {
+	policy, err := channel.ParseTriggerPolicy(map[string]any{
+		channel.RoutingTriggerKey: map[string]any{
+			"keywords": []any{"Release"},
+			"aliases":  []any{"memo"},
+			"patterns": []any{`^!ask\b`},
+		},
+	}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := map[string]bool{
+		"when is the RELEASE?": true,
+		"memo, are you there":  true,
+		"ok Memo":              true,
+		"memorandum attached":  false,
+		"!ask about the docs":  true,
+		"please !ask later":    false,
+		"":                     false,
+	}
+	for text, want := range cases {
+		if got := policy.MatchText(text); got != want {
+			t.Errorf("MatchText(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	policy, err := channel.ParseTriggerPolicy(map[string]any{
+		channel.RoutingTriggerKey: map[string]any{
+			"quiet_hours": map[string]any{"start": "22:00", "end": "07:00", "timezone": "Asia/Tokyo"},
+		},
+	}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	cases := map[int]bool{21: false, 22: true, 23: true, 0: true, 6: true, 7: false, 12: false}
+	for hour, want := range cases {
+		at := time.Date(2026, 3, 1, hour, 30, 0, 0, tokyo)
+		if got := policy.QuietHours.Contains(at.UTC()); got != want {
+			t.Errorf("Contains(%02d:30 Tokyo) = %v, want %v", hour, got, want)
+		}
+	}
+}
@@ -39,10 +39,44 @@ type Conversation struct {
 	Metadata map[string]any
 }
 
+// InboundEventType classifies an inbound event. The zero value is treated as a regular message.
+type InboundEventType string
+
+const (
+	InboundEventMessage         InboundEventType = "message"
+	InboundEventAction          InboundEventType = "action"
+	InboundEventMessageEdited   InboundEventType = "message_edited"
+	InboundEventMessageDeleted  InboundEventType = "message_deleted"
+	InboundEventReactionAdded   InboundEventType = "reaction_added"
+	InboundEventReactionRemoved InboundEventType = "reaction_removed"
+)
+
+// ActionEvent describes a press of an interactive button on a message sent by the bot.
+type ActionEvent struct {
+	// Value is the Action.Value of the pressed button.
+	Value string `json:"value"`
+	// Label is the button label, when the platform reports it.
+	Label string `json:"label,omitempty"`
+	// MessageID is the platform ID of the message carrying the button.
+	MessageID string `json:"message_id,omitempty"`
+}
+
+// ReactionEvent describes an emoji reaction added to or removed from a message.
+type ReactionEvent struct {
+	Emoji string `json:"emoji"`
+	// MessageID is the platform ID of the reacted message.
+	MessageID string `json:"message_id"`
+}
+
 // InboundMessage is a message received from an external channel.
+// For message_edited events Message carries the edited message with its new content;
+// for message_deleted events only Message.ID is set.
 type InboundMessage struct {
 	Channel      ChannelType
+	Event        InboundEventType
 	Message      Message
+	Action       *ActionEvent
+	Reaction     *ReactionEvent
 	BotID        string
 	ReplyTarget  string
 	RouteKey     string
@@ -53,6 +87,30 @@ type InboundMessage struct {
 	Metadata     map[string]any
 }
 
+// EventType returns the inbound event type, defaulting to InboundEventMessage.
+func (m InboundMessage) EventType() InboundEventType {
+	if m.Event == "" {
+		return InboundEventMessage
+	}
+	return m.Event
+}
+
+// IsAction reports whether the event is a button press carrying an ActionEvent.
+func (m InboundMessage) IsAction() bool {
+	return m.EventType() == InboundEventAction && m.Action != nil
+}
+
+// IsHistoryUpdate reports whether the event changes an earlier message (edit,
+// deletion or reaction) instead of carrying a new one.
+func (m InboundMessage) IsHistoryUpdate() bool {
+	switch m.EventType() {
+	case InboundEventMessageEdited, InboundEventMessageDeleted, InboundEventReactionAdded, InboundEventReactionRemoved:
+		return true
+	default:
+		return false
+	}
+}
+
 // RoutingKey returns a stable identifier used for reply routing.
 // Format: platform:bot_id:conversation_id[:sender_id].
 func (m InboundMessage) RoutingKey() string {
@@ -152,6 +210,7 @@ type StreamEvent struct {
 // StreamOptions configures how an outbound stream is initialized.
 type StreamOptions struct {
 	Reply           *ReplyRef      `json:"reply,omitempty"`
+	Thread          *ThreadRef     `json:"thread,omitempty"`
 	SourceMessageID string         `json:"source_message_id,omitempty"`
 	Metadata        map[string]any `json:"metadata,omitempty"`
 }
@@ -251,9 +310,11 @@ type Action struct {
 	URL   string `json:"url,omitempty"`
 }
 
-// ThreadRef references a conversation thread by ID.
+// ThreadRef references a conversation thread by ID. A name without an ID asks
+// adapters that support it to open a new thread with that title.
 type ThreadRef struct {
-	ID string `json:"id"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
 }
 
 // ReplyRef points to a message being replied to.
@@ -396,3 +457,16 @@ type ReactRequest struct {
 	Emoji     string `json:"emoji"`
 	Remove    bool   `json:"remove,omitempty"`
 }
+
+// EditMessageRequest is the input for replacing the content of a sent message.
+type EditMessageRequest struct {
+	Target    string  `json:"target"`
+	MessageID string  `json:"message_id"`
+	Message   Message `json:"message"`
+}
+
+// DeleteMessageRequest is the input for retracting a sent message.
+type DeleteMessageRequest struct {
+	Target    string `json:"target"`
+	MessageID string `json:"message_id"`
+}
@@ -25,6 +25,7 @@ const (
 	DefaultPGSSLMode        = "disable"
 	DefaultQdrantURL        = "http://127.0.0.1:6334"
 	DefaultQdrantCollection = "memory"
+	DefaultInboundWorkers   = 4
 )
 
 type Config struct {
@@ -37,6 +38,7 @@ type Config struct {
 	Postgres     PostgresConfig     `toml:"postgres"`
 	Qdrant       QdrantConfig       `toml:"qdrant"`
 	AgentGateway AgentGatewayConfig `toml:"agent_gateway"`
+	Channel      ChannelConfig      `toml:"channel"`
 }
 
 type LogConfig struct {
@@ -76,6 +78,26 @@ type MCPConfig struct {
 	DataRoot     string `toml:"data_root"`
 	CNIBinaryDir string `toml:"cni_bin_dir"`
 	CNIConfigDir string `toml:"cni_conf_dir"`
+	// Resources are the default limits of bot containers; bots may override them.
+	Resources ContainerResourcesConfig `toml:"resources"`
+}
+
+// ContainerResourcesConfig limits a bot container. Zero leaves a resource unlimited.
+type ContainerResourcesConfig struct {
+	// CPUShares is the relative CPU weight (1024 is the default share).
+	CPUShares uint64 `toml:"cpu_shares"`
+	// CPUs caps CPU time in cores, e.g. 1.5.
+	CPUs float64 `toml:"cpus"`
+	// MemoryMB caps memory.
+	MemoryMB int64 `toml:"memory_mb"`
+	// SwapMB is the swap allowed on top of MemoryMB. Zero disables swap when memory is
+	// capped.
+	SwapMB int64 `toml:"swap_mb"`
+	// PidsLimit caps the number of processes and threads.
+	PidsLimit int64 `toml:"pids_limit"`
+	// DiskMB is the quota of the bot data directory. Once it is exceeded the directory
+	// is mounted read-only when the container is next created.
+	DiskMB int64 `toml:"disk_mb"`
 }
 
 type PostgresConfig struct {
@@ -94,6 +116,12 @@ type QdrantConfig struct {
 	TimeoutSeconds int    `toml:"timeout_seconds"`
 }
 
+type ChannelConfig struct {
+	// InboundWorkers is the number of conversations processed in parallel.
+	// Messages within one conversation are always processed in order.
+	InboundWorkers int `toml:"inbound_workers"`
+}
+
 type AgentGatewayConfig struct {
 	Host string `toml:"host"`
 	Port int    `toml:"port"`
@@ -153,6 +181,9 @@ func Load(path string) (Config, error) {
 			Host: "127.0.0.1",
 			Port: 8081,
 		},
+		Channel: ChannelConfig{
+			InboundWorkers: DefaultInboundWorkers,
+		},
 	}
 
 	if path == "" {
@@ -0,0 +1,228 @@
+package containerd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// EgressMode selects how much outbound network access a container gets.
+type EgressMode string
+
+const (
+	// EgressFull leaves outbound traffic unrestricted.
+	EgressFull EgressMode = "full"
+	// EgressNone blocks all outbound traffic except replies to inbound connections.
+	EgressNone EgressMode = "none"
+	// EgressAllowlist only lets traffic to the allowed prefixes and DNS servers out.
+	EgressAllowlist EgressMode = "allowlist"
+)
+
+// EgressLogPrefix starts the kernel log prefix of denied packets. The prefix continues
+// with the EgressRules tag.
+const EgressLogPrefix = "memoh-egress:"
+
+const (
+	kmsgPath       = "/dev/kmsg"
+	nfLogAllNetns  = "/proc/sys/net/netfilter/nf_log_all_netns"
+	egressLogLimit = "30/min"
+)
+
+// EgressRules are the firewall rules applied inside the network namespace of a
+// container.
+type EgressRules struct {
+	Mode EgressMode
+	// Allow lists the destinations reachable in allowlist mode.
+	Allow []netip.Prefix
+	// Nameservers are reachable on port 53 in allowlist mode so allowed domains resolve.
+	Nameservers []netip.Addr
+	// Tag identifies the container in the kernel log of denied packets. It must be at
+	// most 15 characters.
+	Tag string
+}
+
+// EgressDenial is an outbound packet dropped by the egress rules of a container.
+type EgressDenial struct {
+	Tag         string
+	Destination string
+	Port        int
+	Protocol    string
+	Time        time.Time
+}
+
+// applyEgressRules replaces the filter tables of a network namespace with the rules.
+func applyEgressRules(ctx context.Context, netnsPath string, rules EgressRules) error {
+	if len(rules.Tag) > 15 {
+		return fmt.Errorf("%w: egress tag %q is too long", ErrInvalidArgument, rules.Tag)
+	}
+	if err := restoreTable(ctx, netnsPath, "iptables-restore", egressTable(rules, false)); err != nil {
+		return fmt.Errorf("apply ipv4 egress rules: %w", err)
+	}
+	if err := restoreTable(ctx, netnsPath, "ip6tables-restore", egressTable(rules, true)); err != nil {
+		// Hosts without ip6tables do not route IPv6 to containers either.
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("apply ipv6 egress rules: %w", err)
+	}
+	return nil
+}
+
+func restoreTable(ctx context.Context, netnsPath, restore, table string) error {
+	if _, err := exec.LookPath(restore); err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "nsenter", "--net="+netnsPath, restore, "--wait")
+	cmd.Stdin = strings.NewReader(table)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w: %s", restore, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// egressTable renders the iptables-restore input of the filter table.
+func egressTable(rules EgressRules, ipv6 bool) string {
+	var b strings.Builder
+	b.WriteString("*filter\n:INPUT ACCEPT [0:0]\n:FORWARD ACCEPT [0:0]\n:OUTPUT ACCEPT [0:0]\n")
+	if rules.Mode == EgressFull || rules.Mode == "" {
+		b.WriteString("COMMIT\n")
+		return b.String()
+	}
+	b.WriteString("-A OUTPUT -o lo -j ACCEPT\n")
+	b.WriteString("-A OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT\n")
+	if rules.Mode == EgressAllowlist {
+		for _, ns := range rules.Nameservers {
+			if ns.Is6() != ipv6 || ns.IsLoopback() {
+				continue
+			}
+			prefix := netip.PrefixFrom(ns, ns.BitLen())
+			fmt.Fprintf(&b, "-A OUTPUT -d %s -p udp --dport 53 -j ACCEPT\n", prefix)
+			fmt.Fprintf(&b, "-A OUTPUT -d %s -p tcp --dport 53 -j ACCEPT\n", prefix)
+		}
+		for _, prefix := range rules.Allow {
+			if prefix.Addr().Is6() != ipv6 {
+				continue
+			}
+			fmt.Fprintf(&b, "-A OUTPUT -d %s -j ACCEPT\n", prefix.Masked())
+		}
+	}
+	if rules.Tag != "" {
+		fmt.Fprintf(&b, "-A OUTPUT -m limit --limit %s --limit-burst 10 -j LOG --log-prefix \"%s%s \"\n", egressLogLimit, EgressLogPrefix, rules.Tag)
+	}
+	b.WriteString("-A OUTPUT -j REJECT\n")
+	b.WriteString("COMMIT\n")
+	return b.String()
+}
+
+// watchEgressDenials reads the kernel log for packets dropped by egress rules until ctx
+// is canceled. Logging from container network namespaces is enabled on the way.
+func watchEgressDenials(ctx context.Context, handle func(EgressDenial)) error {
+	if handle == nil {
+		return ErrInvalidArgument
+	}
+	if err := os.WriteFile(nfLogAllNetns, []byte("1"), 0o644); err != nil {
+		return fmt.Errorf("enable container netfilter logging: %w", err)
+	}
+	kmsg, err := os.Open(kmsgPath)
+	if err != nil {
+		return err
+	}
+	// Start from the end so denials logged before a restart are not recorded twice.
+	if _, err := kmsg.Seek(0, io.SeekEnd); err != nil {
+		_ = kmsg.Close()
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = kmsg.Close()
+	}()
+
+	buf := make([]byte, 8192)
+	for {
+		n, err := kmsg.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// EPIPE reports records overwritten before they were read.
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+			return err
+		}
+		if denial, ok := parseEgressDenial(string(buf[:n])); ok {
+			denial.Time = time.Now()
+			handle(denial)
+		}
+	}
+}
+
+// parseEgressDenial parses a /dev/kmsg record written by an egress LOG rule.
+func parseEgressDenial(record string) (EgressDenial, bool) {
+	_, message, ok := strings.Cut(record, ";")
+	if !ok {
+		return EgressDenial{}, false
+	}
+	message, _, _ = strings.Cut(message, "\n")
+	rest, ok := strings.CutPrefix(message, EgressLogPrefix)
+	if !ok {
+		return EgressDenial{}, false
+	}
+	tag, fields, ok := strings.Cut(rest, " ")
+	if !ok || tag == "" {
+		return EgressDenial{}, false
+	}
+	denial := EgressDenial{Tag: tag}
+	scanner := bufio.NewScanner(strings.NewReader(fields))
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "DST":
+			denial.Destination = value
+		case "PROTO":
+			denial.Protocol = strings.ToLower(value)
+		case "DPT":
+			denial.Port, _ = strconv.Atoi(value)
+		}
+	}
+	if denial.Destination == "" {
+		return EgressDenial{}, false
+	}
+	return denial, true
+}
+
+// Nameservers returns the nameserver addresses listed in a resolv.conf file.
+func Nameservers(path string) ([]netip.Addr, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []netip.Addr
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		host, _, _ := strings.Cut(fields[1], "%")
+		if addr, err := netip.ParseAddr(host); err == nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
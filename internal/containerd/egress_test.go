@@ -0,0 +1,84 @@
+package containerd
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEgressTable(t *testing.T) {
+	full := egressTable(EgressRules{Mode: EgressFull, Tag: "abc"}, false)
+	if strings.Contains(full, "-A OUTPUT") {
+		t.Fatalf("expected no rules in full mode, got:\n%s", full)
+	}
+
+	none := egressTable(EgressRules{Mode: EgressNone, Tag: "abc"}, false)
+	for _, want := range []string{"-A OUTPUT -o lo -j ACCEPT", `--log-prefix "memoh-egress:abc "`, "-A OUTPUT -j REJECT", "COMMIT"} {
+		if !strings.Contains(none, want) {
+			t.Fatalf("expected %q in none mode, got:\n%s", want, none)
+		}
+	}
+	if strings.Contains(none, "--dport 53") {
+		t.Fatalf("expected no DNS in none mode, got:\n%s", none)
+	}
+
+	rules := EgressRules{
+		Mode:        EgressAllowlist,
+		Allow:       []netip.Prefix{netip.MustParsePrefix("10.1.2.3/16"), netip.MustParsePrefix("2001:db8::1/128")},
+		Nameservers: []netip.Addr{netip.MustParseAddr("1.1.1.1"), netip.MustParseAddr("127.0.0.53")},
+		Tag:         "abc",
+	}
+	v4 := egressTable(rules, false)
+	for _, want := range []string{"-A OUTPUT -d 10.1.0.0/16 -j ACCEPT", "-A OUTPUT -d 1.1.1.1/32 -p udp --dport 53 -j ACCEPT"} {
+		if !strings.Contains(v4, want) {
+			t.Fatalf("expected %q in allowlist mode, got:\n%s", want, v4)
+		}
+	}
+	if strings.Contains(v4, "2001:db8") || strings.Contains(v4, "127.0.0.53") {
+		t.Fatalf("unexpected address in ipv4 rules:\n%s", v4)
+	}
+	if v6 := egressTable(rules, true); !strings.Contains(v6, "-A OUTPUT -d 2001:db8::1/128 -j ACCEPT") {
+		t.Fatalf("expected ipv6 allow rule, got:\n%s", v6)
+	}
+	if strings.Index(v4, "-j LOG") > strings.Index(v4, "-j REJECT") {
+		t.Fatalf("expected log before reject, got:\n%s", v4)
+	}
+}
+
+func TestParseEgressDenial(t *testing.T) {
+	record := "4,1289,48120391,-;memoh-egress:3f1c6a2e8d4b IN= OUT=eth0 SRC=10.88.0.5 DST=93.184.216.34 LEN=60 TOS=0x00 PREC=0x00 TTL=64 ID=1 DF PROTO=TCP SPT=40312 DPT=443 WINDOW=64240 RES=0x00 SYN URGP=0 \n"
+	denial, ok := parseEgressDenial(record)
+	if !ok {
+		t.Fatal("expected denial")
+	}
+	if denial.Tag != "3f1c6a2e8d4b" || denial.Destination != "93.184.216.34" || denial.Port != 443 || denial.Protocol != "tcp" {
+		t.Fatalf("unexpected denial: %+v", denial)
+	}
+
+	for _, other := range []string{
+		"6,1290,48120400,-;eth0: link up",
+		"4,1291,48120401,-;memoh-egress: IN= OUT=eth0 DST=1.1.1.1",
+		"no separator",
+	} {
+		if _, ok := parseEgressDenial(other); ok {
+			t.Fatalf("expected %q to be ignored", other)
+		}
+	}
+}
+
+func TestNameservers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resolv.conf")
+	content := "# comment\nnameserver 1.1.1.1\nnameserver fe80::1%eth0\nsearch local\nnameserver bogus\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := Nameservers(path)
+	if err != nil {
+		t.Fatalf("nameservers: %v", err)
+	}
+	if len(addrs) != 2 || addrs[0].String() != "1.1.1.1" || addrs[1].String() != "fe80::1" {
+		t.Fatalf("unexpected nameservers: %v", addrs)
+	}
+}
@@ -1,10 +1,15 @@
 package containerd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/mount"
 )
 
@@ -106,3 +111,26 @@ func MountSnapshot(ctx context.Context, service Service, snapshotter, key string
 
 	return dir, cleanup, nil
 }
+
+// remountContainerPath remounts a bind mount inside the mount namespace of a task.
+func remountContainerPath(ctx context.Context, task client.Task, destination string, readOnly bool) error {
+	if task == nil || !filepath.IsAbs(destination) {
+		return ErrInvalidArgument
+	}
+	pid := task.Pid()
+	if pid == 0 {
+		return fmt.Errorf("task pid not available for %s", task.ID())
+	}
+	mode := "rw"
+	if readOnly {
+		mode = "ro"
+	}
+	mntnsPath := filepath.Join("/proc", fmt.Sprint(pid), "ns", "mnt")
+	cmd := exec.CommandContext(ctx, "nsenter", "--mount="+mntnsPath, "mount", "-o", "remount,bind,"+mode, destination)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("remount %s %s: %w: %s", destination, mode, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
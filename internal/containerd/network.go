@@ -2,6 +2,7 @@ package containerd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,7 +12,7 @@ import (
 	gocni "github.com/containerd/go-cni"
 )
 
-func setupCNINetwork(ctx context.Context, task client.Task, containerID string, CNIBinDir string, CNIConfDir string) error {
+func setupCNINetwork(ctx context.Context, task client.Task, containerID string, CNIBinDir string, CNIConfDir string, egress *EgressRules) error {
 	if task == nil {
 		return ErrInvalidArgument
 	}
@@ -61,9 +62,33 @@ func setupCNINetwork(ctx context.Context, task client.Task, containerID string,
 			return err
 		}
 	}
+	if egress != nil {
+		if err := applyEgressRules(ctx, netnsPath, *egress); err != nil {
+			// Fail closed: a container whose egress rules did not apply gets no network.
+			if rmErr := cni.Remove(ctx, containerID, netnsPath); rmErr != nil {
+				return errors.Join(err, rmErr)
+			}
+			return err
+		}
+	}
 	return nil
 }
 
+func applyContainerEgress(ctx context.Context, task client.Task, rules EgressRules) error {
+	if task == nil {
+		return ErrInvalidArgument
+	}
+	pid := task.Pid()
+	if pid == 0 {
+		return fmt.Errorf("task pid not available for %s", task.ID())
+	}
+	netnsPath := filepath.Join("/proc", fmt.Sprint(pid), "ns", "net")
+	if _, err := os.Stat(netnsPath); err != nil {
+		return fmt.Errorf("netns not found: %s: %w", netnsPath, err)
+	}
+	return applyEgressRules(ctx, netnsPath, rules)
+}
+
 func removeCNINetwork(ctx context.Context, task client.Task, containerID string, CNIBinDir string, CNIConfDir string) error {
 	if task == nil {
 		return ErrInvalidArgument
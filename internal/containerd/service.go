@@ -14,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	apievents "github.com/containerd/containerd/api/events"
 	tasksv1 "github.com/containerd/containerd/api/services/tasks/v1"
 	tasktypes "github.com/containerd/containerd/api/types/task"
 	containerd "github.com/containerd/containerd/v2/client"
@@ -25,12 +26,17 @@ import (
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/platforms"
+	"github.com/containerd/typeurl/v2"
 	"github.com/memohai/memoh/internal/config"
 	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/identity"
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
+// DefaultCPUPeriod is the CFS period in microseconds used when a CPU quota is set
+// without one.
+const DefaultCPUPeriod uint64 = 100000
+
 var (
 	ErrInvalidArgument = errors.New("invalid argument")
 	ErrTaskStopTimeout = errors.New("timeout waiting for task to stop")
@@ -105,6 +111,17 @@ type Service interface {
 
 	SetupNetwork(ctx context.Context, req NetworkSetupRequest) error
 	RemoveNetwork(ctx context.Context, req NetworkSetupRequest) error
+	// ApplyEgress replaces the egress rules of a running container.
+	ApplyEgress(ctx context.Context, containerID string, rules EgressRules) error
+	// WatchEgressDenials calls handle for every packet dropped by egress rules until ctx
+	// is canceled.
+	WatchEgressDenials(ctx context.Context, handle func(EgressDenial)) error
+	// RemountReadOnly switches a bind mount of a running container between read-only
+	// and read-write.
+	RemountReadOnly(ctx context.Context, containerID, destination string, readOnly bool) error
+
+	// WatchOOM calls handle for every OOM kill in the namespace until ctx is canceled.
+	WatchOOM(ctx context.Context, handle func(OOMEvent)) error
 
 	CommitSnapshot(ctx context.Context, snapshotter, name, key string) error
 	ListSnapshots(ctx context.Context, snapshotter string) ([]SnapshotInfo, error)
@@ -219,7 +236,32 @@ func specOptsFromSpec(spec ContainerSpec) []oci.SpecOpts {
 		}
 		opts = append(opts, oci.WithMounts(mounts))
 	}
+	opts = append(opts, resourceSpecOpts(spec.Resources)...)
+
+	return opts
+}
 
+func resourceSpecOpts(limits ResourceLimits) []oci.SpecOpts {
+	var opts []oci.SpecOpts
+	if limits.CPUShares > 0 {
+		opts = append(opts, oci.WithCPUShares(limits.CPUShares))
+	}
+	if limits.CPUQuota > 0 {
+		period := limits.CPUPeriod
+		if period == 0 {
+			period = DefaultCPUPeriod
+		}
+		opts = append(opts, oci.WithCPUCFS(limits.CPUQuota, period))
+	}
+	if limits.MemoryLimit > 0 {
+		opts = append(opts, oci.WithMemoryLimit(uint64(limits.MemoryLimit)))
+	}
+	if limits.MemorySwapLimit > 0 {
+		opts = append(opts, oci.WithMemorySwap(limits.MemorySwapLimit))
+	}
+	if limits.PidsLimit > 0 {
+		opts = append(opts, oci.WithPidsLimit(limits.PidsLimit))
+	}
 	return opts
 }
 
@@ -227,6 +269,9 @@ func (s *DefaultService) CreateContainer(ctx context.Context, req CreateContaine
 	if req.ID == "" || req.ImageRef == "" {
 		return ContainerInfo{}, ErrInvalidArgument
 	}
+	if err := req.Spec.Resources.Validate(); err != nil {
+		return ContainerInfo{}, err
+	}
 
 	ctx = s.withNamespace(ctx)
 	ctx, done, err := s.client.WithLease(ctx)
@@ -892,6 +937,9 @@ func (s *DefaultService) CreateContainerFromSnapshot(ctx context.Context, req Cr
 	if req.ID == "" || req.SnapshotID == "" {
 		return ContainerInfo{}, ErrInvalidArgument
 	}
+	if err := req.Spec.Resources.Validate(); err != nil {
+		return ContainerInfo{}, err
+	}
 
 	ctx = s.withNamespace(ctx)
 
@@ -971,7 +1019,7 @@ func (s *DefaultService) SetupNetwork(ctx context.Context, req NetworkSetupReque
 	if err != nil {
 		return err
 	}
-	return setupCNINetwork(ctx, task, req.ContainerID, req.CNIBinDir, req.CNIConfDir)
+	return setupCNINetwork(ctx, task, req.ContainerID, req.CNIBinDir, req.CNIConfDir, req.Egress)
 }
 
 func (s *DefaultService) RemoveNetwork(ctx context.Context, req NetworkSetupRequest) error {
@@ -983,6 +1031,68 @@ func (s *DefaultService) RemoveNetwork(ctx context.Context, req NetworkSetupRequ
 	return removeCNINetwork(ctx, task, req.ContainerID, req.CNIBinDir, req.CNIConfDir)
 }
 
+// ApplyEgress replaces the egress rules of a running container.
+func (s *DefaultService) ApplyEgress(ctx context.Context, containerID string, rules EgressRules) error {
+	ctx = s.withNamespace(ctx)
+	task, err := s.getTask(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return applyContainerEgress(ctx, task, rules)
+}
+
+// WatchEgressDenials calls handle for every packet dropped by egress rules until ctx is
+// canceled. Denials are read from the kernel log, which needs a privileged host.
+func (s *DefaultService) WatchEgressDenials(ctx context.Context, handle func(EgressDenial)) error {
+	return watchEgressDenials(ctx, handle)
+}
+
+// RemountReadOnly switches a bind mount of a running container between read-only and
+// read-write in the mount namespace of its task.
+func (s *DefaultService) RemountReadOnly(ctx context.Context, containerID, destination string, readOnly bool) error {
+	ctx = s.withNamespace(ctx)
+	task, err := s.getTask(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return remountContainerPath(ctx, task, destination, readOnly)
+}
+
+// WatchOOM subscribes to task OOM events of the service namespace. It returns nil when
+// ctx is canceled and an error when the subscription breaks.
+func (s *DefaultService) WatchOOM(ctx context.Context, handle func(OOMEvent)) error {
+	if handle == nil {
+		return ErrInvalidArgument
+	}
+	ctx = s.withNamespace(ctx)
+	envelopes, errs := s.client.Subscribe(ctx, `topic=="/tasks/oom",namespace==`+s.namespace)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		case envelope := <-envelopes:
+			if envelope == nil || envelope.Event == nil {
+				continue
+			}
+			decoded, err := typeurl.UnmarshalAny(envelope.Event)
+			if err != nil {
+				s.logger.Warn("decode oom event failed", slog.Any("error", err))
+				continue
+			}
+			oom, ok := decoded.(*apievents.TaskOOM)
+			if !ok {
+				continue
+			}
+			handle(OOMEvent{ContainerID: oom.ContainerID, Time: envelope.Timestamp})
+		}
+	}
+}
+
 func (s *DefaultService) withNamespace(ctx context.Context) context.Context {
 	return namespaces.WithNamespace(ctx, s.namespace)
 }
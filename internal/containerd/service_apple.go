@@ -403,9 +403,39 @@ func (s *AppleService) ExecTaskStreaming(context.Context, string, ExecTaskReques
 // Network (no-op — Apple Container handles networking natively)
 // ---------------------------------------------------------------------------
 
-func (s *AppleService) SetupNetwork(context.Context, NetworkSetupRequest) error  { return nil }
+func (s *AppleService) SetupNetwork(_ context.Context, req NetworkSetupRequest) error {
+	if req.Egress != nil && req.Egress.Mode != EgressFull {
+		return fmt.Errorf("egress policies: %w", ErrNotSupported)
+	}
+	return nil
+}
+
 func (s *AppleService) RemoveNetwork(context.Context, NetworkSetupRequest) error { return nil }
 
+func (s *AppleService) ApplyEgress(context.Context, string, EgressRules) error {
+	return ErrNotSupported
+}
+
+func (s *AppleService) WatchEgressDenials(context.Context, func(EgressDenial)) error {
+	return ErrNotSupported
+}
+
+// ---------------------------------------------------------------------------
+// Mounts (not supported — containers run inside a VM)
+// ---------------------------------------------------------------------------
+
+func (s *AppleService) RemountReadOnly(context.Context, string, string, bool) error {
+	return ErrNotSupported
+}
+
+// ---------------------------------------------------------------------------
+// Events (not supported — socktainer does not report OOM kills)
+// ---------------------------------------------------------------------------
+
+func (s *AppleService) WatchOOM(context.Context, func(OOMEvent)) error {
+	return ErrNotSupported
+}
+
 // ---------------------------------------------------------------------------
 // Snapshots (not supported on Apple Container)
 // ---------------------------------------------------------------------------
@@ -430,6 +460,8 @@ func (s *AppleService) SnapshotMounts(context.Context, string, string) ([]MountI
 // Helpers
 // ---------------------------------------------------------------------------
 
+// specToCreateOpts maps a container spec to socktainer options. Resource limits are
+// not mapped: each Apple container runs in its own VM sized by the container runtime.
 func specToCreateOpts(req CreateContainerRequest) []acgo.CreateOpt {
 	var opts []acgo.CreateOpt
 	opts = append(opts, acgo.WithImage(req.ImageRef))
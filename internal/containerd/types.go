@@ -2,6 +2,7 @@ package containerd
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"time"
 )
@@ -86,13 +87,52 @@ type MountSpec struct {
 }
 
 type ContainerSpec struct {
-	Cmd     []string
-	Env     []string
-	WorkDir string
-	User    string
-	Mounts  []MountSpec
-	DNS     []string
-	TTY     bool
+	Cmd       []string
+	Env       []string
+	WorkDir   string
+	User      string
+	Mounts    []MountSpec
+	DNS       []string
+	TTY       bool
+	Resources ResourceLimits
+}
+
+// ResourceLimits are the cgroup limits of a container. Zero values leave a resource
+// unlimited.
+type ResourceLimits struct {
+	// CPUShares is the relative CPU weight (1024 is the default share).
+	CPUShares uint64
+	// CPUQuota and CPUPeriod cap CPU time to CPUQuota microseconds every CPUPeriod
+	// microseconds.
+	CPUQuota  int64
+	CPUPeriod uint64
+	// MemoryLimit caps memory in bytes.
+	MemoryLimit int64
+	// MemorySwapLimit caps memory plus swap in bytes. It needs a MemoryLimit and must not
+	// be below it.
+	MemorySwapLimit int64
+	// PidsLimit caps the number of processes and threads.
+	PidsLimit int64
+}
+
+// Validate reports limits the runtime would reject or silently ignore.
+func (l ResourceLimits) Validate() error {
+	switch {
+	case l.CPUQuota < 0 || l.MemoryLimit < 0 || l.MemorySwapLimit < 0 || l.PidsLimit < 0:
+		return fmt.Errorf("%w: resource limits must not be negative", ErrInvalidArgument)
+	case l.MemorySwapLimit > 0 && l.MemoryLimit == 0:
+		return fmt.Errorf("%w: memory swap limit requires a memory limit", ErrInvalidArgument)
+	case l.MemorySwapLimit > 0 && l.MemorySwapLimit < l.MemoryLimit:
+		return fmt.Errorf("%w: memory swap limit is below the memory limit", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// OOMEvent reports that the kernel killed a process of a container for exceeding its
+// memory limit.
+type OOMEvent struct {
+	ContainerID string
+	Time        time.Time
 }
 
 type NetworkSetupRequest struct {
@@ -100,6 +140,8 @@ type NetworkSetupRequest struct {
 	PID         uint32
 	CNIBinDir   string
 	CNIConfDir  string
+	// Egress restricts outbound traffic once the network is up. Nil leaves it open.
+	Egress *EgressRules
 }
 
 type ExecTaskRequest struct {
@@ -0,0 +1,31 @@
+package containerd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResourceLimitsValidate(t *testing.T) {
+	valid := []ResourceLimits{
+		{},
+		{MemoryLimit: 64 << 20},
+		{MemoryLimit: 64 << 20, MemorySwapLimit: 64 << 20},
+		{MemoryLimit: 64 << 20, MemorySwapLimit: 128 << 20},
+	}
+	for i, limits := range valid {
+		if err := limits.Validate(); err != nil {
+			t.Fatalf("case %d: expected valid limits, got %v", i, err)
+		}
+	}
+	invalid := []ResourceLimits{
+		{MemoryLimit: -1},
+		{PidsLimit: -1},
+		{MemorySwapLimit: 64 << 20},
+		{MemoryLimit: 128 << 20, MemorySwapLimit: 64 << 20},
+	}
+	for i, limits := range invalid {
+		if err := limits.Validate(); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("case %d: expected ErrInvalidArgument, got %v", i, err)
+		}
+	}
+}
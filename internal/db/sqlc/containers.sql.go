@@ -20,6 +20,17 @@ func (q *Queries) DeleteContainerByBotID(ctx context.Context, botID pgtype.UUID)
 	return err
 }
 
+const getBotContainerResources = `-- name: GetBotContainerResources :one
+SELECT bot_id, resources, updated_at FROM bot_container_resources WHERE bot_id = $1
+`
+
+func (q *Queries) GetBotContainerResources(ctx context.Context, botID pgtype.UUID) (BotContainerResource, error) {
+	row := q.db.QueryRow(ctx, getBotContainerResources, botID)
+	var i BotContainerResource
+	err := row.Scan(&i.BotID, &i.Resources, &i.UpdatedAt)
+	return i, err
+}
+
 const getContainerByBotID = `-- name: GetContainerByBotID :one
 SELECT id, bot_id, container_id, container_name, image, status, namespace, auto_start, host_path, container_path, created_at, updated_at, last_started_at, last_stopped_at FROM containers WHERE bot_id = $1 ORDER BY updated_at DESC LIMIT 1
 `
@@ -123,6 +134,27 @@ func (q *Queries) UpdateContainerStopped(ctx context.Context, botID pgtype.UUID)
 	return err
 }
 
+const upsertBotContainerResources = `-- name: UpsertBotContainerResources :one
+INSERT INTO bot_container_resources (bot_id, resources)
+VALUES ($1, $2)
+ON CONFLICT (bot_id) DO UPDATE
+SET resources = EXCLUDED.resources,
+    updated_at = now()
+RETURNING bot_id, resources, updated_at
+`
+
+type UpsertBotContainerResourcesParams struct {
+	BotID     pgtype.UUID `json:"bot_id"`
+	Resources []byte      `json:"resources"`
+}
+
+func (q *Queries) UpsertBotContainerResources(ctx context.Context, arg UpsertBotContainerResourcesParams) (BotContainerResource, error) {
+	row := q.db.QueryRow(ctx, upsertBotContainerResources, arg.BotID, arg.Resources)
+	var i BotContainerResource
+	err := row.Scan(&i.BotID, &i.Resources, &i.UpdatedAt)
+	return i, err
+}
+
 const upsertContainer = `-- name: UpsertContainer :exec
 INSERT INTO containers (
   bot_id, container_id, container_name, image, status, namespace, auto_start,
@@ -7,8 +7,37 @@ package sqlc
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countRecentLifecycleEvents = `-- name: CountRecentLifecycleEvents :one
+SELECT count(*)::int AS event_count, max(created_at)::timestamptz AS last_at
+FROM lifecycle_events
+WHERE container_id = $1
+  AND event_type = $2
+  AND created_at >= $3
+`
+
+type CountRecentLifecycleEventsParams struct {
+	ContainerID string             `json:"container_id"`
+	EventType   string             `json:"event_type"`
+	Since       pgtype.Timestamptz `json:"since"`
+}
+
+type CountRecentLifecycleEventsRow struct {
+	EventCount int32              `json:"event_count"`
+	LastAt     pgtype.Timestamptz `json:"last_at"`
+}
+
+// Counts the events of one type recorded for a container since a point in time.
+func (q *Queries) CountRecentLifecycleEvents(ctx context.Context, arg CountRecentLifecycleEventsParams) (CountRecentLifecycleEventsRow, error) {
+	row := q.db.QueryRow(ctx, countRecentLifecycleEvents, arg.ContainerID, arg.EventType, arg.Since)
+	var i CountRecentLifecycleEventsRow
+	err := row.Scan(&i.EventCount, &i.LastAt)
+	return i, err
+}
+
 const insertLifecycleEvent = `-- name: InsertLifecycleEvent :exec
 INSERT INTO lifecycle_events (id, container_id, event_type, payload)
 VALUES (
@@ -127,6 +127,182 @@ func (q *Queries) DeleteMessagesByBot(ctx context.Context, botID pgtype.UUID) er
 	return err
 }
 
+const editMessageBySource = `-- name: EditMessageBySource :execrows
+UPDATE bot_history_messages
+SET content = $1,
+    metadata = metadata || jsonb_build_object(
+      'edited_at', $2::timestamptz,
+      'original_content', COALESCE(metadata->'original_content', content)
+    )
+WHERE bot_id = $3
+  AND channel_type = $4::text
+  AND source_message_id = $5::text
+  AND role = 'user'
+`
+
+type EditMessageBySourceParams struct {
+	Content           []byte             `json:"content"`
+	EditedAt          pgtype.Timestamptz `json:"edited_at"`
+	BotID             pgtype.UUID        `json:"bot_id"`
+	Platform          string             `json:"platform"`
+	ExternalMessageID string             `json:"external_message_id"`
+}
+
+func (q *Queries) EditMessageBySource(ctx context.Context, arg EditMessageBySourceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, editMessageBySource,
+		arg.Content,
+		arg.EditedAt,
+		arg.BotID,
+		arg.Platform,
+		arg.ExternalMessageID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getLatestAssistantExternalMessageID = `-- name: GetLatestAssistantExternalMessageID :one
+SELECT m.source_message_id AS external_message_id
+FROM bot_history_messages m
+JOIN bot_channel_routes r ON r.id = m.route_id
+WHERE m.bot_id = $1
+  AND m.role = 'assistant'
+  AND m.channel_type = $2::text
+  AND r.default_reply_target = $3::text
+  AND m.source_message_id IS NOT NULL
+ORDER BY m.created_at DESC
+LIMIT 1
+`
+
+type GetLatestAssistantExternalMessageIDParams struct {
+	BotID       pgtype.UUID `json:"bot_id"`
+	Platform    string      `json:"platform"`
+	ReplyTarget string      `json:"reply_target"`
+}
+
+func (q *Queries) GetLatestAssistantExternalMessageID(ctx context.Context, arg GetLatestAssistantExternalMessageIDParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getLatestAssistantExternalMessageID, arg.BotID, arg.Platform, arg.ReplyTarget)
+	var external_message_id pgtype.Text
+	err := row.Scan(&external_message_id)
+	return external_message_id, err
+}
+
+const getMessageMetadataBySource = `-- name: GetMessageMetadataBySource :one
+SELECT m.id, m.metadata
+FROM bot_history_messages m
+WHERE m.bot_id = $1
+  AND m.channel_type = $2::text
+  AND (
+    m.source_message_id = $3::text
+    OR m.metadata->'platform_message_ids' @> jsonb_build_array($3::text)
+  )
+ORDER BY m.created_at DESC
+LIMIT 1
+`
+
+type GetMessageMetadataBySourceParams struct {
+	BotID             pgtype.UUID `json:"bot_id"`
+	Platform          string      `json:"platform"`
+	ExternalMessageID string      `json:"external_message_id"`
+}
+
+type GetMessageMetadataBySourceRow struct {
+	ID       pgtype.UUID `json:"id"`
+	Metadata []byte      `json:"metadata"`
+}
+
+func (q *Queries) GetMessageMetadataBySource(ctx context.Context, arg GetMessageMetadataBySourceParams) (GetMessageMetadataBySourceRow, error) {
+	row := q.db.QueryRow(ctx, getMessageMetadataBySource, arg.BotID, arg.Platform, arg.ExternalMessageID)
+	var i GetMessageMetadataBySourceRow
+	err := row.Scan(&i.ID, &i.Metadata)
+	return i, err
+}
+
+const isBotOutboundMessage = `-- name: IsBotOutboundMessage :one
+SELECT (
+  EXISTS (
+    SELECT 1
+    FROM bot_history_messages m
+    JOIN bot_channel_routes r ON r.id = m.route_id
+    WHERE m.bot_id = $1
+      AND m.role = 'assistant'
+      AND m.channel_type = $2::text
+      AND r.default_reply_target = $3::text
+      AND (
+        m.source_message_id = $4::text
+        OR m.metadata->'platform_message_ids' @> jsonb_build_array($4::text)
+      )
+  )
+  OR EXISTS (
+    SELECT 1
+    FROM channel_outbound_deliveries d
+    WHERE d.bot_id = $1
+      AND d.channel_type = $2::text
+      AND d.target = $3::text
+      AND d.platform_message_ids @> jsonb_build_array($4::text)
+  )
+)::boolean AS is_outbound
+`
+
+type IsBotOutboundMessageParams struct {
+	BotID             pgtype.UUID `json:"bot_id"`
+	Platform          string      `json:"platform"`
+	Target            string      `json:"target"`
+	ExternalMessageID string      `json:"external_message_id"`
+}
+
+func (q *Queries) IsBotOutboundMessage(ctx context.Context, arg IsBotOutboundMessageParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isBotOutboundMessage,
+		arg.BotID,
+		arg.Platform,
+		arg.Target,
+		arg.ExternalMessageID,
+	)
+	var is_outbound bool
+	err := row.Scan(&is_outbound)
+	return is_outbound, err
+}
+
+const linkLatestAssistantMessageExternalIDs = `-- name: LinkLatestAssistantMessageExternalIDs :execrows
+UPDATE bot_history_messages
+SET source_message_id = $1::text,
+    metadata = metadata || jsonb_build_object('platform_message_ids', $2::jsonb)
+WHERE id = (
+  SELECT m.id
+  FROM bot_history_messages m
+  WHERE m.bot_id = $3
+    AND m.route_id = $4
+    AND m.role = 'assistant'
+    AND m.source_message_id IS NULL
+    AND m.created_at >= $5
+  ORDER BY m.created_at DESC
+  LIMIT 1
+)
+`
+
+type LinkLatestAssistantMessageExternalIDsParams struct {
+	ExternalMessageID  string             `json:"external_message_id"`
+	PlatformMessageIds []byte             `json:"platform_message_ids"`
+	BotID              pgtype.UUID        `json:"bot_id"`
+	RouteID            pgtype.UUID        `json:"route_id"`
+	Since              pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) LinkLatestAssistantMessageExternalIDs(ctx context.Context, arg LinkLatestAssistantMessageExternalIDsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, linkLatestAssistantMessageExternalIDs,
+		arg.ExternalMessageID,
+		arg.PlatformMessageIds,
+		arg.BotID,
+		arg.RouteID,
+		arg.Since,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const listActiveMessagesSince = `-- name: ListActiveMessagesSince :many
 SELECT
   m.id,
@@ -149,6 +325,7 @@ LEFT JOIN channel_identities ci ON ci.id = m.sender_channel_identity_id
 WHERE m.bot_id = $1
   AND m.created_at >= $2
   AND (m.metadata->>'trigger_mode' IS NULL OR m.metadata->>'trigger_mode' != 'passive_sync')
+  AND m.metadata->>'deleted_at' IS NULL
 ORDER BY m.created_at ASC
 `
 
@@ -539,3 +716,51 @@ func (q *Queries) ListMessagesSince(ctx context.Context, arg ListMessagesSincePa
 	}
 	return items, nil
 }
+
+const softDeleteMessagesBySource = `-- name: SoftDeleteMessagesBySource :execrows
+UPDATE bot_history_messages
+SET metadata = metadata || jsonb_build_object('deleted_at', $1::timestamptz)
+WHERE bot_id = $2
+  AND channel_type = $3::text
+  AND (
+    source_message_id = $4::text
+    OR metadata->'platform_message_ids' @> jsonb_build_array($4::text)
+  )
+  AND metadata->>'deleted_at' IS NULL
+`
+
+type SoftDeleteMessagesBySourceParams struct {
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	BotID             pgtype.UUID        `json:"bot_id"`
+	Platform          string             `json:"platform"`
+	ExternalMessageID string             `json:"external_message_id"`
+}
+
+func (q *Queries) SoftDeleteMessagesBySource(ctx context.Context, arg SoftDeleteMessagesBySourceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteMessagesBySource,
+		arg.DeletedAt,
+		arg.BotID,
+		arg.Platform,
+		arg.ExternalMessageID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateMessageMetadata = `-- name: UpdateMessageMetadata :exec
+UPDATE bot_history_messages
+SET metadata = $1
+WHERE id = $2
+`
+
+type UpdateMessageMetadataParams struct {
+	Metadata []byte      `json:"metadata"`
+	ID       pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateMessageMetadata(ctx context.Context, arg UpdateMessageMetadataParams) error {
+	_, err := q.db.Exec(ctx, updateMessageMetadata, arg.Metadata, arg.ID)
+	return err
+}
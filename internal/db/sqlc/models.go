@@ -9,31 +9,36 @@ import (
 )
 
 type Bot struct {
-	ID                 pgtype.UUID        `json:"id"`
-	OwnerUserID        pgtype.UUID        `json:"owner_user_id"`
-	Type               string             `json:"type"`
-	DisplayName        pgtype.Text        `json:"display_name"`
-	AvatarUrl          pgtype.Text        `json:"avatar_url"`
-	IsActive           bool               `json:"is_active"`
-	Status             string             `json:"status"`
-	MaxContextLoadTime int32              `json:"max_context_load_time"`
-	MaxContextTokens   int32              `json:"max_context_tokens"`
-	Language           string             `json:"language"`
-	AllowGuest         bool               `json:"allow_guest"`
-	ReasoningEnabled   bool               `json:"reasoning_enabled"`
-	ReasoningEffort    string             `json:"reasoning_effort"`
-	MaxInboxItems      int32              `json:"max_inbox_items"`
-	ChatModelID        pgtype.UUID        `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID        `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID        `json:"embedding_model_id"`
-	SearchProviderID   pgtype.UUID        `json:"search_provider_id"`
-	HeartbeatEnabled   bool               `json:"heartbeat_enabled"`
-	HeartbeatInterval  int32              `json:"heartbeat_interval"`
-	HeartbeatPrompt    string             `json:"heartbeat_prompt"`
-	HeartbeatModelID   pgtype.UUID        `json:"heartbeat_model_id"`
-	Metadata           []byte             `json:"metadata"`
-	CreatedAt          pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	ID                   pgtype.UUID        `json:"id"`
+	OwnerUserID          pgtype.UUID        `json:"owner_user_id"`
+	Type                 string             `json:"type"`
+	DisplayName          pgtype.Text        `json:"display_name"`
+	AvatarUrl            pgtype.Text        `json:"avatar_url"`
+	IsActive             bool               `json:"is_active"`
+	Status               string             `json:"status"`
+	MaxContextLoadTime   int32              `json:"max_context_load_time"`
+	MaxContextTokens     int32              `json:"max_context_tokens"`
+	Language             string             `json:"language"`
+	AllowGuest           bool               `json:"allow_guest"`
+	ReasoningEnabled     bool               `json:"reasoning_enabled"`
+	ReasoningEffort      string             `json:"reasoning_effort"`
+	MaxInboxItems        int32              `json:"max_inbox_items"`
+	ChatModelID          pgtype.UUID        `json:"chat_model_id"`
+	MemoryModelID        pgtype.UUID        `json:"memory_model_id"`
+	EmbeddingModelID     pgtype.UUID        `json:"embedding_model_id"`
+	SearchProviderID     pgtype.UUID        `json:"search_provider_id"`
+	HeartbeatEnabled     bool               `json:"heartbeat_enabled"`
+	HeartbeatInterval    int32              `json:"heartbeat_interval"`
+	HeartbeatPrompt      string             `json:"heartbeat_prompt"`
+	HeartbeatModelID     pgtype.UUID        `json:"heartbeat_model_id"`
+	TranscriptionModelID pgtype.UUID        `json:"transcription_model_id"`
+	SpeechModelID        pgtype.UUID        `json:"speech_model_id"`
+	SpeechVoice          string             `json:"speech_voice"`
+	VoiceReplyEnabled    bool               `json:"voice_reply_enabled"`
+	Timezone             string             `json:"timezone"`
+	Metadata             []byte             `json:"metadata"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
 
 type BotChannelConfig struct {
@@ -65,6 +70,28 @@ type BotChannelRoute struct {
 	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
 }
 
+type BotContainerResource struct {
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Resources []byte             `json:"resources"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type BotEgressDenial struct {
+	BotID       pgtype.UUID        `json:"bot_id"`
+	Destination string             `json:"destination"`
+	Port        int32              `json:"port"`
+	Protocol    string             `json:"protocol"`
+	Hits        int32              `json:"hits"`
+	FirstSeenAt pgtype.Timestamptz `json:"first_seen_at"`
+	LastSeenAt  pgtype.Timestamptz `json:"last_seen_at"`
+}
+
+type BotEgressPolicy struct {
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Policy    []byte             `json:"policy"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type BotHeartbeatLog struct {
 	ID           pgtype.UUID        `json:"id"`
 	BotID        pgtype.UUID        `json:"bot_id"`
@@ -137,6 +164,44 @@ type BotStorageBinding struct {
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
 }
 
+type ChannelBridge struct {
+	ID        pgtype.UUID        `json:"id"`
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Name      string             `json:"name"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChannelBridgeMessage struct {
+	BridgeID        pgtype.UUID        `json:"bridge_id"`
+	SourceRouteID   pgtype.UUID        `json:"source_route_id"`
+	SourceMessageID string             `json:"source_message_id"`
+	TargetRouteID   pgtype.UUID        `json:"target_route_id"`
+	TargetMessageID string             `json:"target_message_id"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type ChannelBridgeRoute struct {
+	BridgeID       pgtype.UUID        `json:"bridge_id"`
+	RouteID        pgtype.UUID        `json:"route_id"`
+	RelayOutgoing  bool               `json:"relay_outgoing"`
+	RelayIncoming  bool               `json:"relay_incoming"`
+	OutgoingFilter []byte             `json:"outgoing_filter"`
+	IncomingFilter []byte             `json:"incoming_filter"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type ChannelHandoff struct {
+	RouteID     pgtype.UUID        `json:"route_id"`
+	BotID       pgtype.UUID        `json:"bot_id"`
+	StartedBy   string             `json:"started_by"`
+	Reason      string             `json:"reason"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	LastReplyAt pgtype.Timestamptz `json:"last_reply_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 type ChannelIdentity struct {
 	ID               pgtype.UUID        `json:"id"`
 	UserID           pgtype.UUID        `json:"user_id"`
@@ -160,6 +225,123 @@ type ChannelIdentityBindCode struct {
 	CreatedAt               pgtype.Timestamptz `json:"created_at"`
 }
 
+type ChannelInboundJob struct {
+	ID                pgtype.UUID        `json:"id"`
+	BotID             pgtype.UUID        `json:"bot_id"`
+	ChannelConfigID   string             `json:"channel_config_id"`
+	ChannelType       string             `json:"channel_type"`
+	ExternalMessageID string             `json:"external_message_id"`
+	Payload           []byte             `json:"payload"`
+	Status            string             `json:"status"`
+	Attempts          int32              `json:"attempts"`
+	LastError         string             `json:"last_error"`
+	AvailableAt       pgtype.Timestamptz `json:"available_at"`
+	LockedUntil       pgtype.Timestamptz `json:"locked_until"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	RouteKey          string             `json:"route_key"`
+}
+
+type ChannelModerationEvent struct {
+	ID              pgtype.UUID        `json:"id"`
+	BotID           pgtype.UUID        `json:"bot_id"`
+	ChannelType     string             `json:"channel_type"`
+	ConversationID  string             `json:"conversation_id"`
+	SenderSubjectID string             `json:"sender_subject_id"`
+	SenderName      string             `json:"sender_name"`
+	MessageID       string             `json:"message_id"`
+	Rule            string             `json:"rule"`
+	Action          string             `json:"action"`
+	Reason          string             `json:"reason"`
+	Content         string             `json:"content"`
+	MutedUntil      pgtype.Timestamptz `json:"muted_until"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type ChannelModerationPolicy struct {
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Policy    []byte             `json:"policy"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChannelOutboundApproval struct {
+	ID              pgtype.UUID        `json:"id"`
+	BotID           pgtype.UUID        `json:"bot_id"`
+	ChannelType     string             `json:"channel_type"`
+	Target          string             `json:"target"`
+	Payload         []byte             `json:"payload"`
+	SendAt          pgtype.Timestamptz `json:"send_at"`
+	RequestedBy     string             `json:"requested_by"`
+	Status          string             `json:"status"`
+	DecidedBy       string             `json:"decided_by"`
+	DecidedAt       pgtype.Timestamptz `json:"decided_at"`
+	OutboxMessageID pgtype.UUID        `json:"outbox_message_id"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+}
+
+type ChannelOutboundDelivery struct {
+	ID                 pgtype.UUID        `json:"id"`
+	BotID              pgtype.UUID        `json:"bot_id"`
+	ChannelConfigID    string             `json:"channel_config_id"`
+	ChannelType        string             `json:"channel_type"`
+	Target             string             `json:"target"`
+	Payload            []byte             `json:"payload"`
+	Status             string             `json:"status"`
+	Attempts           int32              `json:"attempts"`
+	ChunksTotal        int32              `json:"chunks_total"`
+	ChunksSent         int32              `json:"chunks_sent"`
+	LastError          string             `json:"last_error"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+	PlatformMessageIds []byte             `json:"platform_message_ids"`
+}
+
+type ChannelOutboundPolicy struct {
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Policy    []byte             `json:"policy"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChannelOutboxMessage struct {
+	ID                 pgtype.UUID        `json:"id"`
+	BotID              pgtype.UUID        `json:"bot_id"`
+	ChannelType        string             `json:"channel_type"`
+	Target             string             `json:"target"`
+	Payload            []byte             `json:"payload"`
+	Source             string             `json:"source"`
+	CreatedBy          string             `json:"created_by"`
+	Status             string             `json:"status"`
+	SendAt             pgtype.Timestamptz `json:"send_at"`
+	AvailableAt        pgtype.Timestamptz `json:"available_at"`
+	Attempts           int32              `json:"attempts"`
+	LastError          string             `json:"last_error"`
+	LockedUntil        pgtype.Timestamptz `json:"locked_until"`
+	PlatformMessageIds []byte             `json:"platform_message_ids"`
+	SentAt             pgtype.Timestamptz `json:"sent_at"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChannelRateLimitPolicy struct {
+	BotID     pgtype.UUID        `json:"bot_id"`
+	Policy    []byte             `json:"policy"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChannelRateUsage struct {
+	BotID             pgtype.UUID        `json:"bot_id"`
+	ChannelIdentityID pgtype.UUID        `json:"channel_identity_id"`
+	Day               pgtype.Date        `json:"day"`
+	ChannelType       string             `json:"channel_type"`
+	DisplayName       string             `json:"display_name"`
+	Role              string             `json:"role"`
+	Messages          int32              `json:"messages"`
+	Turns             int32              `json:"turns"`
+	Limited           int32              `json:"limited"`
+	LastSeenAt        pgtype.Timestamptz `json:"last_seen_at"`
+}
+
 type Container struct {
 	ID            pgtype.UUID        `json:"id"`
 	BotID         pgtype.UUID        `json:"bot_id"`
@@ -231,6 +413,14 @@ type MediaAsset struct {
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 }
 
+type MediaTranscription struct {
+	BotID       pgtype.UUID        `json:"bot_id"`
+	ContentHash string             `json:"content_hash"`
+	ModelID     pgtype.UUID        `json:"model_id"`
+	Text        string             `json:"text"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
 type Model struct {
 	ID                pgtype.UUID        `json:"id"`
 	ModelID           string             `json:"model_id"`
@@ -27,6 +27,11 @@ SET max_context_load_time = 1440,
     memory_model_id = NULL,
     embedding_model_id = NULL,
     heartbeat_model_id = NULL,
+    transcription_model_id = NULL,
+    speech_model_id = NULL,
+    speech_voice = 'alloy',
+    voice_reply_enabled = false,
+    timezone = 'UTC',
     search_provider_id = NULL,
     updated_at = now()
 WHERE id = $1
@@ -54,33 +59,45 @@ SELECT
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
   heartbeat_models.id AS heartbeat_model_id,
+  transcription_models.id AS transcription_model_id,
+  speech_models.id AS speech_model_id,
+  bots.speech_voice,
+  bots.voice_reply_enabled,
+  bots.timezone,
   search_providers.id AS search_provider_id
 FROM bots
 LEFT JOIN models AS chat_models ON chat_models.id = bots.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = bots.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = bots.embedding_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = bots.heartbeat_model_id
+LEFT JOIN models AS transcription_models ON transcription_models.id = bots.transcription_model_id
+LEFT JOIN models AS speech_models ON speech_models.id = bots.speech_model_id
 LEFT JOIN search_providers ON search_providers.id = bots.search_provider_id
 WHERE bots.id = $1
 `
 
 type GetSettingsByBotIDRow struct {
-	BotID              pgtype.UUID `json:"bot_id"`
-	MaxContextLoadTime int32       `json:"max_context_load_time"`
-	MaxContextTokens   int32       `json:"max_context_tokens"`
-	MaxInboxItems      int32       `json:"max_inbox_items"`
-	Language           string      `json:"language"`
-	AllowGuest         bool        `json:"allow_guest"`
-	ReasoningEnabled   bool        `json:"reasoning_enabled"`
-	ReasoningEffort    string      `json:"reasoning_effort"`
-	HeartbeatEnabled   bool        `json:"heartbeat_enabled"`
-	HeartbeatInterval  int32       `json:"heartbeat_interval"`
-	HeartbeatPrompt    string      `json:"heartbeat_prompt"`
-	ChatModelID        pgtype.UUID `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID `json:"embedding_model_id"`
-	HeartbeatModelID   pgtype.UUID `json:"heartbeat_model_id"`
-	SearchProviderID   pgtype.UUID `json:"search_provider_id"`
+	BotID                pgtype.UUID `json:"bot_id"`
+	MaxContextLoadTime   int32       `json:"max_context_load_time"`
+	MaxContextTokens     int32       `json:"max_context_tokens"`
+	MaxInboxItems        int32       `json:"max_inbox_items"`
+	Language             string      `json:"language"`
+	AllowGuest           bool        `json:"allow_guest"`
+	ReasoningEnabled     bool        `json:"reasoning_enabled"`
+	ReasoningEffort      string      `json:"reasoning_effort"`
+	HeartbeatEnabled     bool        `json:"heartbeat_enabled"`
+	HeartbeatInterval    int32       `json:"heartbeat_interval"`
+	HeartbeatPrompt      string      `json:"heartbeat_prompt"`
+	ChatModelID          pgtype.UUID `json:"chat_model_id"`
+	MemoryModelID        pgtype.UUID `json:"memory_model_id"`
+	EmbeddingModelID     pgtype.UUID `json:"embedding_model_id"`
+	HeartbeatModelID     pgtype.UUID `json:"heartbeat_model_id"`
+	TranscriptionModelID pgtype.UUID `json:"transcription_model_id"`
+	SpeechModelID        pgtype.UUID `json:"speech_model_id"`
+	SpeechVoice          string      `json:"speech_voice"`
+	VoiceReplyEnabled    bool        `json:"voice_reply_enabled"`
+	Timezone             string      `json:"timezone"`
+	SearchProviderID     pgtype.UUID `json:"search_provider_id"`
 }
 
 func (q *Queries) GetSettingsByBotID(ctx context.Context, id pgtype.UUID) (GetSettingsByBotIDRow, error) {
@@ -102,6 +119,11 @@ func (q *Queries) GetSettingsByBotID(ctx context.Context, id pgtype.UUID) (GetSe
 		&i.MemoryModelID,
 		&i.EmbeddingModelID,
 		&i.HeartbeatModelID,
+		&i.TranscriptionModelID,
+		&i.SpeechModelID,
+		&i.SpeechVoice,
+		&i.VoiceReplyEnabled,
+		&i.Timezone,
 		&i.SearchProviderID,
 	)
 	return i, err
@@ -124,10 +146,15 @@ WITH updated AS (
       memory_model_id = COALESCE($12::uuid, bots.memory_model_id),
       embedding_model_id = COALESCE($13::uuid, bots.embedding_model_id),
       heartbeat_model_id = COALESCE($14::uuid, bots.heartbeat_model_id),
-      search_provider_id = COALESCE($15::uuid, bots.search_provider_id),
+      transcription_model_id = COALESCE($15::uuid, bots.transcription_model_id),
+      speech_model_id = COALESCE($16::uuid, bots.speech_model_id),
+      speech_voice = COALESCE($17::text, bots.speech_voice),
+      voice_reply_enabled = COALESCE($18::boolean, bots.voice_reply_enabled),
+      timezone = COALESCE($19::text, bots.timezone),
+      search_provider_id = COALESCE($20::uuid, bots.search_provider_id),
       updated_at = now()
-  WHERE bots.id = $16
-  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.heartbeat_model_id, bots.search_provider_id
+  WHERE bots.id = $21
+  RETURNING bots.id, bots.max_context_load_time, bots.max_context_tokens, bots.max_inbox_items, bots.language, bots.allow_guest, bots.reasoning_enabled, bots.reasoning_effort, bots.heartbeat_enabled, bots.heartbeat_interval, bots.heartbeat_prompt, bots.chat_model_id, bots.memory_model_id, bots.embedding_model_id, bots.heartbeat_model_id, bots.transcription_model_id, bots.speech_model_id, bots.speech_voice, bots.voice_reply_enabled, bots.timezone, bots.search_provider_id
 )
 SELECT
   updated.id AS bot_id,
@@ -145,51 +172,68 @@ SELECT
   memory_models.id AS memory_model_id,
   embedding_models.id AS embedding_model_id,
   heartbeat_models.id AS heartbeat_model_id,
+  transcription_models.id AS transcription_model_id,
+  speech_models.id AS speech_model_id,
+  updated.speech_voice,
+  updated.voice_reply_enabled,
+  updated.timezone,
   search_providers.id AS search_provider_id
 FROM updated
 LEFT JOIN models AS chat_models ON chat_models.id = updated.chat_model_id
 LEFT JOIN models AS memory_models ON memory_models.id = updated.memory_model_id
 LEFT JOIN models AS embedding_models ON embedding_models.id = updated.embedding_model_id
 LEFT JOIN models AS heartbeat_models ON heartbeat_models.id = updated.heartbeat_model_id
+LEFT JOIN models AS transcription_models ON transcription_models.id = updated.transcription_model_id
+LEFT JOIN models AS speech_models ON speech_models.id = updated.speech_model_id
 LEFT JOIN search_providers ON search_providers.id = updated.search_provider_id
 `
 
 type UpsertBotSettingsParams struct {
-	MaxContextLoadTime int32       `json:"max_context_load_time"`
-	MaxContextTokens   int32       `json:"max_context_tokens"`
-	MaxInboxItems      int32       `json:"max_inbox_items"`
-	Language           string      `json:"language"`
-	AllowGuest         bool        `json:"allow_guest"`
-	ReasoningEnabled   bool        `json:"reasoning_enabled"`
-	ReasoningEffort    string      `json:"reasoning_effort"`
-	HeartbeatEnabled   bool        `json:"heartbeat_enabled"`
-	HeartbeatInterval  int32       `json:"heartbeat_interval"`
-	HeartbeatPrompt    string      `json:"heartbeat_prompt"`
-	ChatModelID        pgtype.UUID `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID `json:"embedding_model_id"`
-	HeartbeatModelID   pgtype.UUID `json:"heartbeat_model_id"`
-	SearchProviderID   pgtype.UUID `json:"search_provider_id"`
-	ID                 pgtype.UUID `json:"id"`
+	MaxContextLoadTime   int32       `json:"max_context_load_time"`
+	MaxContextTokens     int32       `json:"max_context_tokens"`
+	MaxInboxItems        int32       `json:"max_inbox_items"`
+	Language             string      `json:"language"`
+	AllowGuest           bool        `json:"allow_guest"`
+	ReasoningEnabled     bool        `json:"reasoning_enabled"`
+	ReasoningEffort      string      `json:"reasoning_effort"`
+	HeartbeatEnabled     bool        `json:"heartbeat_enabled"`
+	HeartbeatInterval    int32       `json:"heartbeat_interval"`
+	HeartbeatPrompt      string      `json:"heartbeat_prompt"`
+	ChatModelID          pgtype.UUID `json:"chat_model_id"`
+	MemoryModelID        pgtype.UUID `json:"memory_model_id"`
+	EmbeddingModelID     pgtype.UUID `json:"embedding_model_id"`
+	HeartbeatModelID     pgtype.UUID `json:"heartbeat_model_id"`
+	TranscriptionModelID pgtype.UUID `json:"transcription_model_id"`
+	SpeechModelID        pgtype.UUID `json:"speech_model_id"`
+	SpeechVoice          pgtype.Text `json:"speech_voice"`
+	VoiceReplyEnabled    pgtype.Bool `json:"voice_reply_enabled"`
+	Timezone             pgtype.Text `json:"timezone"`
+	SearchProviderID     pgtype.UUID `json:"search_provider_id"`
+	ID                   pgtype.UUID `json:"id"`
 }
 
 type UpsertBotSettingsRow struct {
-	BotID              pgtype.UUID `json:"bot_id"`
-	MaxContextLoadTime int32       `json:"max_context_load_time"`
-	MaxContextTokens   int32       `json:"max_context_tokens"`
-	MaxInboxItems      int32       `json:"max_inbox_items"`
-	Language           string      `json:"language"`
-	AllowGuest         bool        `json:"allow_guest"`
-	ReasoningEnabled   bool        `json:"reasoning_enabled"`
-	ReasoningEffort    string      `json:"reasoning_effort"`
-	HeartbeatEnabled   bool        `json:"heartbeat_enabled"`
-	HeartbeatInterval  int32       `json:"heartbeat_interval"`
-	HeartbeatPrompt    string      `json:"heartbeat_prompt"`
-	ChatModelID        pgtype.UUID `json:"chat_model_id"`
-	MemoryModelID      pgtype.UUID `json:"memory_model_id"`
-	EmbeddingModelID   pgtype.UUID `json:"embedding_model_id"`
-	HeartbeatModelID   pgtype.UUID `json:"heartbeat_model_id"`
-	SearchProviderID   pgtype.UUID `json:"search_provider_id"`
+	BotID                pgtype.UUID `json:"bot_id"`
+	MaxContextLoadTime   int32       `json:"max_context_load_time"`
+	MaxContextTokens     int32       `json:"max_context_tokens"`
+	MaxInboxItems        int32       `json:"max_inbox_items"`
+	Language             string      `json:"language"`
+	AllowGuest           bool        `json:"allow_guest"`
+	ReasoningEnabled     bool        `json:"reasoning_enabled"`
+	ReasoningEffort      string      `json:"reasoning_effort"`
+	HeartbeatEnabled     bool        `json:"heartbeat_enabled"`
+	HeartbeatInterval    int32       `json:"heartbeat_interval"`
+	HeartbeatPrompt      string      `json:"heartbeat_prompt"`
+	ChatModelID          pgtype.UUID `json:"chat_model_id"`
+	MemoryModelID        pgtype.UUID `json:"memory_model_id"`
+	EmbeddingModelID     pgtype.UUID `json:"embedding_model_id"`
+	HeartbeatModelID     pgtype.UUID `json:"heartbeat_model_id"`
+	TranscriptionModelID pgtype.UUID `json:"transcription_model_id"`
+	SpeechModelID        pgtype.UUID `json:"speech_model_id"`
+	SpeechVoice          string      `json:"speech_voice"`
+	VoiceReplyEnabled    bool        `json:"voice_reply_enabled"`
+	Timezone             string      `json:"timezone"`
+	SearchProviderID     pgtype.UUID `json:"search_provider_id"`
 }
 
 func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsParams) (UpsertBotSettingsRow, error) {
@@ -208,6 +252,11 @@ func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsPa
 		arg.MemoryModelID,
 		arg.EmbeddingModelID,
 		arg.HeartbeatModelID,
+		arg.TranscriptionModelID,
+		arg.SpeechModelID,
+		arg.SpeechVoice,
+		arg.VoiceReplyEnabled,
+		arg.Timezone,
 		arg.SearchProviderID,
 		arg.ID,
 	)
@@ -228,6 +277,11 @@ func (q *Queries) UpsertBotSettings(ctx context.Context, arg UpsertBotSettingsPa
 		&i.MemoryModelID,
 		&i.EmbeddingModelID,
 		&i.HeartbeatModelID,
+		&i.TranscriptionModelID,
+		&i.SpeechModelID,
+		&i.SpeechVoice,
+		&i.VoiceReplyEnabled,
+		&i.Timezone,
 		&i.SearchProviderID,
 	)
 	return i, err
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/approval"
+)
+
+// ChannelApprovalHandler manages the outbound policy of a bot and the messages it holds
+// for owner approval.
+type ChannelApprovalHandler struct {
+	service        *approval.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelApprovalHandler(log *slog.Logger, service *approval.Service, botService *bots.Service, accountService *accounts.Service) *ChannelApprovalHandler {
+	return &ChannelApprovalHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_approval")),
+	}
+}
+
+func (h *ChannelApprovalHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/outbound")
+	group.GET("/policy", h.GetPolicy)
+	group.PUT("/policy", h.UpdatePolicy)
+	group.GET("/approvals", h.List)
+	group.GET("/approvals/:id", h.Get)
+	group.POST("/approvals/:id/decision", h.Decide)
+}
+
+// GetPolicy godoc
+// @Summary Get outbound policy
+// @Description Get the policy for messages a bot sends on its own to other conversations
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} approval.Policy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbound/policy [get]
+func (h *ChannelApprovalHandler) GetPolicy(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	policy, err := h.service.GetPolicy(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// UpdatePolicy godoc
+// @Summary Update outbound policy
+// @Description Replace the outbound policy of a bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param payload body approval.Policy true "Outbound policy"
+// @Success 200 {object} approval.Policy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbound/policy [put]
+func (h *ChannelApprovalHandler) UpdatePolicy(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req approval.Policy
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	policy, err := h.service.UpdatePolicy(c.Request().Context(), botID, req)
+	if err != nil {
+		return approvalHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// List godoc
+// @Summary List held messages
+// @Description List the most recent messages held for owner approval
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param limit query int false "Maximum number of messages"
+// @Success 200 {object} approval.ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbound/approvals [get]
+func (h *ChannelApprovalHandler) List(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	limit := 0
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+	}
+	items, err := h.service.List(c.Request().Context(), botID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, approval.ListResponse{Items: items})
+}
+
+// Get godoc
+// @Summary Get held message
+// @Description Get a message held for owner approval
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Approval ID"
+// @Success 200 {object} approval.Approval
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbound/approvals/{id} [get]
+func (h *ChannelApprovalHandler) Get(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	item, err := h.service.Get(c.Request().Context(), botID, strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return approvalHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Decide godoc
+// @Summary Approve or deny a held message
+// @Description Answer a held message. Approved messages are delivered through the outbox; "always" also allowlists the target.
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Approval ID"
+// @Param payload body approval.DecideRequest true "Decision"
+// @Success 200 {object} approval.Approval
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbound/approvals/{id}/decision [post]
+func (h *ChannelApprovalHandler) Decide(c echo.Context) error {
+	botID, channelIdentityID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req approval.DecideRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.Decide(c.Request().Context(), botID, strings.TrimSpace(c.Param("id")), channelIdentityID, req.Decision)
+	if err != nil {
+		return approvalHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *ChannelApprovalHandler) requireBot(c echo.Context) (string, string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", "", err
+	}
+	return botID, channelIdentityID, nil
+}
+
+func (h *ChannelApprovalHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func approvalHTTPError(err error) error {
+	switch {
+	case errors.Is(err, approval.ErrInvalidPolicy), errors.Is(err, approval.ErrInvalidRequest):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, approval.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, approval.ErrNotPending):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/bridge"
+)
+
+// ChannelBridgeHandler manages bridges that relay messages between a bot's channel routes.
+type ChannelBridgeHandler struct {
+	service        *bridge.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelBridgeHandler(log *slog.Logger, service *bridge.Service, botService *bots.Service, accountService *accounts.Service) *ChannelBridgeHandler {
+	return &ChannelBridgeHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_bridges")),
+	}
+}
+
+func (h *ChannelBridgeHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/channel-bridges")
+	group.POST("", h.Create)
+	group.GET("", h.List)
+	group.GET("/:id", h.Get)
+	group.PUT("/:id", h.Update)
+	group.DELETE("/:id", h.Delete)
+}
+
+// Create godoc
+// @Summary Create channel bridge
+// @Description Link two or more channel routes so user messages are relayed between them
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param payload body bridge.CreateRequest true "Bridge payload"
+// @Success 201 {object} bridge.Bridge
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-bridges [post]
+func (h *ChannelBridgeHandler) Create(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req bridge.CreateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.service.Create(c.Request().Context(), botID, req)
+	if err != nil {
+		return bridgeHTTPError(err)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// List godoc
+// @Summary List channel bridges
+// @Description List the channel bridges of a bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} bridge.ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-bridges [get]
+func (h *ChannelBridgeHandler) List(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.service.List(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, bridge.ListResponse{Items: items})
+}
+
+// Get godoc
+// @Summary Get channel bridge
+// @Description Get a channel bridge by ID
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Bridge ID"
+// @Success 200 {object} bridge.Bridge
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-bridges/{id} [get]
+func (h *ChannelBridgeHandler) Get(c echo.Context) error {
+	item, err := h.requireBridge(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Update godoc
+// @Summary Update channel bridge
+// @Description Rename, enable or disable a channel bridge, or replace its routes
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Bridge ID"
+// @Param payload body bridge.UpdateRequest true "Bridge payload"
+// @Success 200 {object} bridge.Bridge
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-bridges/{id} [put]
+func (h *ChannelBridgeHandler) Update(c echo.Context) error {
+	item, err := h.requireBridge(c)
+	if err != nil {
+		return err
+	}
+	var req bridge.UpdateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.service.Update(c.Request().Context(), item.ID, req)
+	if err != nil {
+		return bridgeHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Delete godoc
+// @Summary Delete channel bridge
+// @Description Delete a channel bridge by ID
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Bridge ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-bridges/{id} [delete]
+func (h *ChannelBridgeHandler) Delete(c echo.Context) error {
+	item, err := h.requireBridge(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), item.ID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ChannelBridgeHandler) requireBot(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+func (h *ChannelBridgeHandler) requireBridge(c echo.Context) (bridge.Bridge, error) {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return bridge.Bridge{}, err
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		return bridge.Bridge{}, echo.NewHTTPError(http.StatusBadRequest, "id is required")
+	}
+	item, err := h.service.Get(c.Request().Context(), id)
+	if err != nil {
+		return bridge.Bridge{}, bridgeHTTPError(err)
+	}
+	if !strings.EqualFold(item.BotID, botID) {
+		return bridge.Bridge{}, echo.NewHTTPError(http.StatusNotFound, "bridge not found")
+	}
+	return item, nil
+}
+
+func (h *ChannelBridgeHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func bridgeHTTPError(err error) error {
+	switch {
+	case errors.Is(err, bridge.ErrBridgeNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, bridge.ErrInvalidBridge):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// ChannelDeliveryHandler exposes recorded outbound deliveries and resends failed ones.
+type ChannelDeliveryHandler struct {
+	manager        *channel.Manager
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelDeliveryHandler(log *slog.Logger, manager *channel.Manager, botService *bots.Service, accountService *accounts.Service) *ChannelDeliveryHandler {
+	return &ChannelDeliveryHandler{
+		manager:        manager,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_delivery")),
+	}
+}
+
+func (h *ChannelDeliveryHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/channel-deliveries")
+	group.GET("", h.List)
+	group.POST("/:id/resend", h.Resend)
+}
+
+// List godoc
+// @Summary List outbound deliveries
+// @Description List recorded outbound channel deliveries of a bot, newest first
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param status query string false "Filter by status (sent/failed)"
+// @Param limit query int false "Max items to return" default(50)
+// @Success 200 {array} channel.OutboundDelivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-deliveries [get]
+func (h *ChannelDeliveryHandler) List(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	status := channel.DeliveryStatus(strings.ToLower(strings.TrimSpace(c.QueryParam("status"))))
+	switch status {
+	case "", channel.DeliveryStatusSent, channel.DeliveryStatusFailed:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "status must be sent or failed")
+	}
+	items, err := h.manager.ListOutboundDeliveries(c.Request().Context(), botID, status, parseIntOr(c.QueryParam("limit"), 50))
+	if err != nil {
+		return deliveryHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// Resend godoc
+// @Summary Resend a failed outbound delivery
+// @Description Send the undelivered part of a failed outbound message again
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} channel.OutboundDelivery
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-deliveries/{id}/resend [post]
+func (h *ChannelDeliveryHandler) Resend(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	deliveryID := strings.TrimSpace(c.Param("id"))
+	if deliveryID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "delivery id is required")
+	}
+	delivery, err := h.manager.ResendOutboundDelivery(c.Request().Context(), botID, deliveryID)
+	if err != nil {
+		if delivery.ID != "" {
+			// The resend ran and failed again; the record carries the new error.
+			return echo.NewHTTPError(http.StatusBadGateway, err.Error())
+		}
+		return deliveryHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, delivery)
+}
+
+func (h *ChannelDeliveryHandler) requireBot(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+func (h *ChannelDeliveryHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func deliveryHTTPError(err error) error {
+	switch {
+	case errors.Is(err, channel.ErrOutboundDeliveryNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, channel.ErrOutboundDeliveryNotFailed):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, channel.ErrOutboundDeliveriesUnsupported):
+		return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/handoff"
+)
+
+// ChannelHandoffHandler lets a bot owner take over channel routes and reply as the bot.
+type ChannelHandoffHandler struct {
+	service        *handoff.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelHandoffHandler(log *slog.Logger, service *handoff.Service, botService *bots.Service, accountService *accounts.Service) *ChannelHandoffHandler {
+	return &ChannelHandoffHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_handoff")),
+	}
+}
+
+func (h *ChannelHandoffHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id")
+	group.GET("/channel-handoffs", h.List)
+	group.GET("/channel-routes/:id/handoff", h.Get)
+	group.POST("/channel-routes/:id/handoff", h.Start)
+	group.DELETE("/channel-routes/:id/handoff", h.Release)
+	group.POST("/channel-routes/:id/handoff/reply", h.Reply)
+}
+
+// List godoc
+// @Summary List channel handoffs
+// @Description List the routes of a bot currently taken over by a human operator
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} handoff.ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-handoffs [get]
+func (h *ChannelHandoffHandler) List(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.service.List(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, handoff.ListResponse{Items: items})
+}
+
+// Get godoc
+// @Summary Get channel handoff
+// @Description Get the active handoff of a route
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Route ID"
+// @Success 200 {object} handoff.Handoff
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-routes/{id}/handoff [get]
+func (h *ChannelHandoffHandler) Get(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	item, err := h.service.Get(c.Request().Context(), botID, strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return handoffHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Start godoc
+// @Summary Start channel handoff
+// @Description Take over a route: the bot stops replying there until the handoff expires or is released
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Route ID"
+// @Param payload body handoff.StartRequest false "Handoff options"
+// @Success 200 {object} handoff.Handoff
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-routes/{id}/handoff [post]
+func (h *ChannelHandoffHandler) Start(c echo.Context) error {
+	botID, channelIdentityID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req handoff.StartRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.Start(c.Request().Context(), botID, strings.TrimSpace(c.Param("id")), channelIdentityID, req)
+	if err != nil {
+		return handoffHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Release godoc
+// @Summary Release channel handoff
+// @Description End the handoff of a route so the bot replies there again
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Route ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-routes/{id}/handoff [delete]
+func (h *ChannelHandoffHandler) Release(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Release(c.Request().Context(), botID, strings.TrimSpace(c.Param("id"))); err != nil {
+		return handoffHTTPError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Reply godoc
+// @Summary Reply as the bot
+// @Description Send a message on a handed-off route as the bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Route ID"
+// @Param payload body handoff.ReplyRequest true "Reply"
+// @Success 200 {object} handoff.ReplyResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-routes/{id}/handoff/reply [post]
+func (h *ChannelHandoffHandler) Reply(c echo.Context) error {
+	botID, channelIdentityID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req handoff.ReplyRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	result, err := h.service.Reply(c.Request().Context(), botID, strings.TrimSpace(c.Param("id")), channelIdentityID, req)
+	if err != nil {
+		if errors.Is(err, handoff.ErrNotActive) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return handoffHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *ChannelHandoffHandler) requireBot(c echo.Context) (string, string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", "", err
+	}
+	return botID, channelIdentityID, nil
+}
+
+func (h *ChannelHandoffHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func handoffHTTPError(err error) error {
+	switch {
+	case errors.Is(err, handoff.ErrInvalidRequest):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, handoff.ErrRouteNotFound), errors.Is(err, handoff.ErrNotActive):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
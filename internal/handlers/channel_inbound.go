@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel"
+)
+
+// ChannelInboundHandler exposes inbound queue depth and the dead-letter view of the durable inbound queue.
+type ChannelInboundHandler struct {
+	manager        *channel.Manager
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelInboundHandler(log *slog.Logger, manager *channel.Manager, botService *bots.Service, accountService *accounts.Service) *ChannelInboundHandler {
+	return &ChannelInboundHandler{
+		manager:        manager,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_inbound")),
+	}
+}
+
+func (h *ChannelInboundHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/channel-inbound")
+	group.GET("/routes", h.ListRouteDepths)
+	group.GET("/dead-letters", h.ListDeadLetters)
+	group.POST("/dead-letters/:id/replay", h.ReplayDeadLetter)
+}
+
+// ListRouteDepths godoc
+// @Summary List inbound queue depth per conversation
+// @Description List conversations of a bot with inbound messages waiting for or in processing
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {array} channel.InboundRouteDepth
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-inbound/routes [get]
+func (h *ChannelInboundHandler) ListRouteDepths(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.manager.InboundRouteDepths(botID))
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered inbound messages
+// @Description List inbound channel messages of a bot that exhausted their processing retries
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param limit query int false "Max items to return" default(50)
+// @Success 200 {array} channel.InboundDeadLetter
+// @Failure 400 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-inbound/dead-letters [get]
+func (h *ChannelInboundHandler) ListDeadLetters(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.manager.ListInboundDeadLetters(c.Request().Context(), botID, parseIntOr(c.QueryParam("limit"), 50))
+	if err != nil {
+		return inboundQueueHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// ReplayDeadLetter godoc
+// @Summary Replay a dead-lettered inbound message
+// @Description Requeue a dead-lettered inbound message with a fresh retry budget
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Inbound job ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 501 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-inbound/dead-letters/{id}/replay [post]
+func (h *ChannelInboundHandler) ReplayDeadLetter(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	jobID := strings.TrimSpace(c.Param("id"))
+	if jobID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "job id is required")
+	}
+	if err := h.manager.ReplayInboundDeadLetter(c.Request().Context(), botID, jobID); err != nil {
+		return inboundQueueHTTPError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ChannelInboundHandler) requireBot(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+func (h *ChannelInboundHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func inboundQueueHTTPError(err error) error {
+	switch {
+	case errors.Is(err, channel.ErrInboundJobNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, channel.ErrDeadLettersUnsupported):
+		return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/moderation"
+)
+
+// ChannelModerationHandler manages a bot's inbound moderation policy and event log.
+type ChannelModerationHandler struct {
+	service        *moderation.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelModerationHandler(log *slog.Logger, service *moderation.Service, botService *bots.Service, accountService *accounts.Service) *ChannelModerationHandler {
+	return &ChannelModerationHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_moderation")),
+	}
+}
+
+func (h *ChannelModerationHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/moderation")
+	group.GET("/policy", h.GetPolicy)
+	group.PUT("/policy", h.UpdatePolicy)
+	group.GET("/events", h.ListEvents)
+	group.POST("/unmute", h.Unmute)
+}
+
+// GetPolicy godoc
+// @Summary Get moderation policy
+// @Description Get the inbound message moderation policy of a bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} moderation.Policy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/moderation/policy [get]
+func (h *ChannelModerationHandler) GetPolicy(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	policy, err := h.service.GetPolicy(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// UpdatePolicy godoc
+// @Summary Update moderation policy
+// @Description Replace the inbound message moderation policy of a bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param payload body moderation.Policy true "Moderation policy"
+// @Success 200 {object} moderation.Policy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/moderation/policy [put]
+func (h *ChannelModerationHandler) UpdatePolicy(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req moderation.Policy
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	policy, err := h.service.UpdatePolicy(c.Request().Context(), botID, req)
+	if err != nil {
+		return moderationHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// ListEvents godoc
+// @Summary List moderation events
+// @Description List the most recent messages moderation acted on, newest first
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param limit query int false "Maximum number of events (default 50, max 500)"
+// @Success 200 {object} moderation.ListEventsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/moderation/events [get]
+func (h *ChannelModerationHandler) ListEvents(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	limit := 0
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+	}
+	items, err := h.service.ListEvents(c.Request().Context(), botID, limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, moderation.ListEventsResponse{Items: items})
+}
+
+// Unmute godoc
+// @Summary Unmute sender
+// @Description Lift the active moderation mute of a sender
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param payload body moderation.UnmuteRequest true "Sender to unmute"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/moderation/unmute [post]
+func (h *ChannelModerationHandler) Unmute(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req moderation.UnmuteRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	lifted, err := h.service.Unmute(c.Request().Context(), botID, req)
+	if err != nil {
+		return moderationHTTPError(err)
+	}
+	if !lifted {
+		return echo.NewHTTPError(http.StatusNotFound, "sender is not muted")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ChannelModerationHandler) requireBot(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+func (h *ChannelModerationHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func moderationHTTPError(err error) error {
+	if errors.Is(err, moderation.ErrInvalidPolicy) || errors.Is(err, moderation.ErrInvalidRequest) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+}
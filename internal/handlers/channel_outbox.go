@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/outbox"
+)
+
+// ChannelOutboxHandler schedules outbound messages of a bot for later delivery.
+type ChannelOutboxHandler struct {
+	service        *outbox.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelOutboxHandler(log *slog.Logger, service *outbox.Service, botService *bots.Service, accountService *accounts.Service) *ChannelOutboxHandler {
+	return &ChannelOutboxHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_outbox")),
+	}
+}
+
+func (h *ChannelOutboxHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/outbox")
+	group.POST("", h.Schedule)
+	group.GET("", h.List)
+	group.GET("/:id", h.Get)
+	group.DELETE("/:id", h.Cancel)
+}
+
+// Schedule godoc
+// @Summary Schedule an outbound message
+// @Description Queue a message to a channel target for delivery at send_at or after delay. Local times are read in the bot's timezone.
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param payload body outbox.ScheduleRequest true "Scheduled message"
+// @Success 201 {object} outbox.Message
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbox [post]
+func (h *ChannelOutboxHandler) Schedule(c echo.Context) error {
+	botID, channelIdentityID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req outbox.ScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	item, err := h.service.Schedule(c.Request().Context(), botID, outbox.SourceAPI, channelIdentityID, req)
+	if err != nil {
+		return outboxHTTPError(err)
+	}
+	return c.JSON(http.StatusCreated, item)
+}
+
+// List godoc
+// @Summary List scheduled messages
+// @Description List the outbox of a bot, earliest send time first
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param status query string false "Filter by status (pending, sending, sent, failed, canceled)"
+// @Param limit query int false "Maximum number of messages"
+// @Success 200 {object} outbox.ListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbox [get]
+func (h *ChannelOutboxHandler) List(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	req := outbox.ListRequest{Status: outbox.Status(strings.TrimSpace(c.QueryParam("status")))}
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+		req.Limit = limit
+	}
+	items, err := h.service.List(c.Request().Context(), botID, req)
+	if err != nil {
+		return outboxHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, outbox.ListResponse{Items: items})
+}
+
+// Get godoc
+// @Summary Get scheduled message
+// @Description Get a message from the outbox of a bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Scheduled message ID"
+// @Success 200 {object} outbox.Message
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbox/{id} [get]
+func (h *ChannelOutboxHandler) Get(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	item, err := h.service.Get(c.Request().Context(), botID, strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		return outboxHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// Cancel godoc
+// @Summary Cancel scheduled message
+// @Description Cancel a pending message so it is never sent
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Scheduled message ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/outbox/{id} [delete]
+func (h *ChannelOutboxHandler) Cancel(c echo.Context) error {
+	botID, _, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Cancel(c.Request().Context(), botID, strings.TrimSpace(c.Param("id"))); err != nil {
+		return outboxHTTPError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *ChannelOutboxHandler) requireBot(c echo.Context) (string, string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", "", err
+	}
+	return botID, channelIdentityID, nil
+}
+
+func (h *ChannelOutboxHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func outboxHTTPError(err error) error {
+	switch {
+	case errors.Is(err, outbox.ErrInvalidRequest):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, outbox.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, outbox.ErrNotPending):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/ratelimit"
+)
+
+// ChannelRateLimitHandler manages the per-sender rate limits of a bot and shows the
+// daily counters they are enforced against.
+type ChannelRateLimitHandler struct {
+	service        *ratelimit.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+func NewChannelRateLimitHandler(log *slog.Logger, service *ratelimit.Service, botService *bots.Service, accountService *accounts.Service) *ChannelRateLimitHandler {
+	return &ChannelRateLimitHandler{
+		service:        service,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_rate_limit")),
+	}
+}
+
+func (h *ChannelRateLimitHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/rate-limits")
+	group.GET("/policy", h.GetPolicy)
+	group.PUT("/policy", h.UpdatePolicy)
+	group.GET("/usage", h.Usage)
+}
+
+// GetPolicy godoc
+// @Summary Get rate limit policy
+// @Description Get the per-role message and turn limits of a bot
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} ratelimit.Policy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/rate-limits/policy [get]
+func (h *ChannelRateLimitHandler) GetPolicy(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	policy, err := h.service.GetPolicy(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// UpdatePolicy godoc
+// @Summary Update rate limit policy
+// @Description Replace the rate limit policy of a bot. Zero limits are unlimited.
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param payload body ratelimit.Policy true "Rate limit policy"
+// @Success 200 {object} ratelimit.Policy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/rate-limits/policy [put]
+func (h *ChannelRateLimitHandler) UpdatePolicy(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	var req ratelimit.Policy
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	policy, err := h.service.UpdatePolicy(c.Request().Context(), botID, req)
+	if err != nil {
+		return rateLimitHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// Usage godoc
+// @Summary Get sender usage
+// @Description List the message, turn and limit counters of each sender for a day in the bot's timezone, most active first
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param day query string false "Day as YYYY-MM-DD (defaults to today)"
+// @Param limit query int false "Maximum number of senders"
+// @Success 200 {object} ratelimit.UsageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/rate-limits/usage [get]
+func (h *ChannelRateLimitHandler) Usage(c echo.Context) error {
+	botID, err := h.requireBot(c)
+	if err != nil {
+		return err
+	}
+	limit := 0
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+		}
+	}
+	usage, err := h.service.Usage(c.Request().Context(), botID, c.QueryParam("day"), limit)
+	if err != nil {
+		return rateLimitHTTPError(err)
+	}
+	return c.JSON(http.StatusOK, usage)
+}
+
+func (h *ChannelRateLimitHandler) requireBot(c echo.Context) (string, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return "", err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return "", err
+	}
+	return botID, nil
+}
+
+func (h *ChannelRateLimitHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func rateLimitHTTPError(err error) error {
+	switch {
+	case errors.Is(err, ratelimit.ErrInvalidPolicy), errors.Is(err, ratelimit.ErrInvalidRequest):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
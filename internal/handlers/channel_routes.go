@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/memohai/memoh/internal/accounts"
+	"github.com/memohai/memoh/internal/bots"
+	"github.com/memohai/memoh/internal/channel/route"
+)
+
+// ChannelRouteHandler exposes per-conversation settings stored on channel routes.
+type ChannelRouteHandler struct {
+	routeService   route.Service
+	botService     *bots.Service
+	accountService *accounts.Service
+	logger         *slog.Logger
+}
+
+// ChannelRouteSettings holds the per-conversation overrides of bot settings. A nil field
+// means the bot setting applies.
+type ChannelRouteSettings struct {
+	VoiceReply *bool `json:"voice_reply"`
+}
+
+func NewChannelRouteHandler(log *slog.Logger, routeService *route.DBService, botService *bots.Service, accountService *accounts.Service) *ChannelRouteHandler {
+	return &ChannelRouteHandler{
+		routeService:   routeService,
+		botService:     botService,
+		accountService: accountService,
+		logger:         log.With(slog.String("handler", "channel_routes")),
+	}
+}
+
+func (h *ChannelRouteHandler) Register(e *echo.Echo) {
+	group := e.Group("/bots/:bot_id/channel-routes")
+	group.GET("/:id/settings", h.GetSettings)
+	group.PUT("/:id/settings", h.UpdateSettings)
+}
+
+// GetSettings godoc
+// @Summary Get conversation settings
+// @Description Get the per-conversation setting overrides of a channel route
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Route ID"
+// @Success 200 {object} ChannelRouteSettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-routes/{id}/settings [get]
+func (h *ChannelRouteHandler) GetSettings(c echo.Context) error {
+	r, err := h.requireRoute(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, routeSettingsFromMetadata(r.Metadata))
+}
+
+// UpdateSettings godoc
+// @Summary Update conversation settings
+// @Description Replace the per-conversation setting overrides of a channel route; null clears an override
+// @Tags channel
+// @Param bot_id path string true "Bot ID"
+// @Param id path string true "Route ID"
+// @Param payload body ChannelRouteSettings true "Conversation settings"
+// @Success 200 {object} ChannelRouteSettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/channel-routes/{id}/settings [put]
+func (h *ChannelRouteHandler) UpdateSettings(c echo.Context) error {
+	r, err := h.requireRoute(c)
+	if err != nil {
+		return err
+	}
+	var req ChannelRouteSettings
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	metadata := make(map[string]any, len(r.Metadata)+1)
+	for k, v := range r.Metadata {
+		metadata[k] = v
+	}
+	if req.VoiceReply != nil {
+		metadata[route.MetadataVoiceReply] = *req.VoiceReply
+	} else {
+		delete(metadata, route.MetadataVoiceReply)
+	}
+	if err := h.routeService.UpdateMetadata(c.Request().Context(), r.ID, metadata); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, routeSettingsFromMetadata(metadata))
+}
+
+func (h *ChannelRouteHandler) requireRoute(c echo.Context) (route.Route, error) {
+	channelIdentityID, err := RequireChannelIdentityID(c)
+	if err != nil {
+		return route.Route{}, err
+	}
+	botID := strings.TrimSpace(c.Param("bot_id"))
+	if botID == "" {
+		return route.Route{}, echo.NewHTTPError(http.StatusBadRequest, "bot id is required")
+	}
+	routeID := strings.TrimSpace(c.Param("id"))
+	if routeID == "" {
+		return route.Route{}, echo.NewHTTPError(http.StatusBadRequest, "route id is required")
+	}
+	if _, err := h.authorizeBotAccess(c.Request().Context(), channelIdentityID, botID); err != nil {
+		return route.Route{}, err
+	}
+	r, err := h.routeService.GetByID(c.Request().Context(), routeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return route.Route{}, echo.NewHTTPError(http.StatusNotFound, "route not found")
+		}
+		return route.Route{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if !strings.EqualFold(r.BotID, botID) {
+		return route.Route{}, echo.NewHTTPError(http.StatusNotFound, "route not found")
+	}
+	return r, nil
+}
+
+func (h *ChannelRouteHandler) authorizeBotAccess(ctx context.Context, channelIdentityID, botID string) (bots.Bot, error) {
+	return AuthorizeBotAccess(ctx, h.botService, h.accountService, channelIdentityID, botID, bots.AccessPolicy{AllowPublicMember: false})
+}
+
+func routeSettingsFromMetadata(metadata map[string]any) ChannelRouteSettings {
+	settings := ChannelRouteSettings{}
+	if value, ok := metadata[route.MetadataVoiceReply].(bool); ok {
+		settings.VoiceReply = &value
+	}
+	return settings
+}
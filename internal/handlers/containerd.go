@@ -126,6 +126,11 @@ func (h *ContainerdHandler) Register(e *echo.Echo) {
 	group.POST("/stop", h.StopContainer)
 	group.POST("/snapshots", h.CreateSnapshot)
 	group.GET("/snapshots", h.ListSnapshots)
+	group.GET("/resources", h.GetContainerResources)
+	group.PUT("/resources", h.UpdateContainerResources)
+	group.GET("/egress", h.GetContainerEgress)
+	group.PUT("/egress", h.UpdateContainerEgress)
+	group.GET("/egress/denials", h.ListContainerEgressDenials)
 	group.GET("/skills", h.ListSkills)
 	group.POST("/skills", h.UpsertSkills)
 	group.DELETE("/skills", h.DeleteSkills)
@@ -195,6 +200,11 @@ func (h *ContainerdHandler) CreateContainer(c echo.Context) error {
 	}
 
 	spec := h.buildMCPContainerSpec(dataDir, dataMount, resolvPath)
+	if h.manager != nil {
+		if err := h.manager.ApplyResources(ctx, botID, &spec); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
 
 	_, err = h.service.CreateContainer(ctx, ctr.CreateContainerRequest{
 		ID:          containerID,
@@ -238,11 +248,7 @@ func (h *ContainerdHandler) CreateContainer(c echo.Context) error {
 		UseStdio: false,
 	}); err == nil {
 		started = true
-		if netErr := h.service.SetupNetwork(ctx, ctr.NetworkSetupRequest{
-			ContainerID: containerID,
-			CNIBinDir:   h.cfg.CNIBinaryDir,
-			CNIConfDir:  h.cfg.CNIConfigDir,
-		}); netErr != nil {
+		if netErr := h.service.SetupNetwork(ctx, h.networkRequest(ctx, botID, containerID)); netErr != nil {
 			h.logger.Warn("mcp container network setup failed, task kept running",
 				slog.String("container_id", containerID),
 				slog.Any("error", netErr),
@@ -295,11 +301,7 @@ func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containe
 	}
 	if len(tasks) > 0 {
 		if tasks[0].Status == ctr.TaskStatusRunning {
-			if netErr := h.service.SetupNetwork(ctx, ctr.NetworkSetupRequest{
-				ContainerID: containerID,
-				CNIBinDir:   h.cfg.CNIBinaryDir,
-				CNIConfDir:  h.cfg.CNIConfigDir,
-			}); netErr != nil {
+			if netErr := h.service.SetupNetwork(ctx, h.networkRequest(ctx, botID, containerID)); netErr != nil {
 				h.logger.Warn("network re-setup failed for running task",
 					slog.String("container_id", containerID), slog.Any("error", netErr))
 			}
@@ -315,11 +317,7 @@ func (h *ContainerdHandler) ensureContainerAndTask(ctx context.Context, containe
 	}); err != nil {
 		return err
 	}
-	if netErr := h.service.SetupNetwork(ctx, ctr.NetworkSetupRequest{
-		ContainerID: containerID,
-		CNIBinDir:   h.cfg.CNIBinaryDir,
-		CNIConfDir:  h.cfg.CNIConfigDir,
-	}); netErr != nil {
+	if netErr := h.service.SetupNetwork(ctx, h.networkRequest(ctx, botID, containerID)); netErr != nil {
 		h.logger.Warn("network setup failed, task kept running",
 			slog.String("container_id", containerID), slog.Any("error", netErr))
 	}
@@ -748,6 +746,143 @@ func snapshotLineage(root string, all []ctr.SnapshotInfo) ([]ctr.SnapshotInfo, b
 
 // ---------- auth helpers ----------
 
+// GetContainerResources godoc
+// @Summary Get container resource limits
+// @Description Get the default, overridden and effective CPU, memory, PID and disk limits of a bot container
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} mcp.ResourcesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/resources [get]
+func (h *ContainerdHandler) GetContainerResources(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "container manager not configured")
+	}
+	resp, err := h.manager.GetResources(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateContainerResources godoc
+// @Summary Update container resource limits
+// @Description Replace the limit overrides of a bot container. Omitted fields inherit the instance defaults and zero removes a limit. Changes apply when the container is next created or recreated.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param payload body mcp.ResourceOverrides true "Resource overrides"
+// @Success 200 {object} mcp.ResourcesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/resources [put]
+func (h *ContainerdHandler) UpdateContainerResources(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "container manager not configured")
+	}
+	var req mcp.ResourceOverrides
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resp, err := h.manager.UpdateResources(c.Request().Context(), botID, req)
+	if err != nil {
+		if errors.Is(err, mcp.ErrInvalidResources) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetContainerEgress godoc
+// @Summary Get container egress policy
+// @Description Get the outbound network access of a bot container: full, none or an allowlist of domains and CIDRs
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Success 200 {object} mcp.EgressPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/egress [get]
+func (h *ContainerdHandler) GetContainerEgress(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "container manager not configured")
+	}
+	policy, err := h.manager.GetEgressPolicy(c.Request().Context(), botID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// UpdateContainerEgress godoc
+// @Summary Update container egress policy
+// @Description Replace the egress policy of a bot container. It applies to the running container right away.
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param payload body mcp.EgressPolicy true "Egress policy"
+// @Success 200 {object} mcp.EgressPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/egress [put]
+func (h *ContainerdHandler) UpdateContainerEgress(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "container manager not configured")
+	}
+	var req mcp.EgressPolicy
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	policy, err := h.manager.UpdateEgressPolicy(c.Request().Context(), botID, req)
+	if err != nil {
+		if errors.Is(err, mcp.ErrInvalidEgressPolicy) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, policy)
+}
+
+// ListContainerEgressDenials godoc
+// @Summary List denied egress
+// @Description List the destinations a bot container tried to reach and was denied by its egress policy, most recent first
+// @Tags containerd
+// @Param bot_id path string true "Bot ID"
+// @Param limit query int false "Maximum number of destinations"
+// @Success 200 {array} mcp.EgressDenial
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /bots/{bot_id}/container/egress/denials [get]
+func (h *ContainerdHandler) ListContainerEgressDenials(c echo.Context) error {
+	botID, err := h.requireBotAccess(c)
+	if err != nil {
+		return err
+	}
+	if h.manager == nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "container manager not configured")
+	}
+	items, err := h.manager.ListEgressDenials(c.Request().Context(), botID, parseIntOr(c.QueryParam("limit"), 100))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
 func (h *ContainerdHandler) mcpImageRef() string {
 	if h.cfg.Image != "" {
 		return h.cfg.Image
@@ -827,6 +962,11 @@ func (h *ContainerdHandler) SetupBotContainer(ctx context.Context, botID string)
 	}
 
 	spec := h.buildMCPContainerSpec(dataDir, dataMount, resolvPath)
+	if h.manager != nil {
+		if err := h.manager.ApplyResources(ctx, botID, &spec); err != nil {
+			return err
+		}
+	}
 
 	_, err = h.service.CreateContainer(ctx, ctr.CreateContainerRequest{
 		ID:          containerID,
@@ -868,11 +1008,7 @@ func (h *ContainerdHandler) SetupBotContainer(ctx context.Context, botID string)
 	if err := h.service.StartContainer(ctx, containerID, &ctr.StartTaskOptions{
 		UseStdio: false,
 	}); err == nil {
-		if netErr := h.service.SetupNetwork(ctx, ctr.NetworkSetupRequest{
-			ContainerID: containerID,
-			CNIBinDir:   h.cfg.CNIBinaryDir,
-			CNIConfDir:  h.cfg.CNIConfigDir,
-		}); netErr != nil {
+		if netErr := h.service.SetupNetwork(ctx, h.networkRequest(ctx, botID, containerID)); netErr != nil {
 			h.logger.Warn("setup bot container: network setup failed, task kept running",
 				slog.String("bot_id", botID),
 				slog.String("container_id", containerID),
@@ -1029,11 +1165,7 @@ func (h *ContainerdHandler) ReconcileContainers(ctx context.Context) {
 						slog.String("bot_id", botID), slog.Any("error", dbErr))
 				}
 			}
-			if netErr := h.service.SetupNetwork(ctx, ctr.NetworkSetupRequest{
-				ContainerID: containerID,
-				CNIBinDir:   h.cfg.CNIBinaryDir,
-				CNIConfDir:  h.cfg.CNIConfigDir,
-			}); netErr != nil {
+			if netErr := h.service.SetupNetwork(ctx, h.networkRequest(ctx, botID, containerID)); netErr != nil {
 				h.logger.Warn("reconcile: network re-setup failed for running task",
 					slog.String("bot_id", botID),
 					slog.String("container_id", containerID),
@@ -1064,6 +1196,21 @@ func (h *ContainerdHandler) ReconcileContainers(ctx context.Context) {
 	h.logger.Info("reconcile: completed")
 }
 
+// networkRequest builds the network setup request of a bot container, including the
+// egress rules of the bot.
+func (h *ContainerdHandler) networkRequest(ctx context.Context, botID, containerID string) ctr.NetworkSetupRequest {
+	if h.manager == nil {
+		return ctr.NetworkSetupRequest{
+			ContainerID: containerID,
+			CNIBinDir:   h.cfg.CNIBinaryDir,
+			CNIConfDir:  h.cfg.CNIConfigDir,
+		}
+	}
+	req := h.manager.NetworkRequest(ctx, botID)
+	req.ContainerID = containerID
+	return req
+}
+
 func (h *ContainerdHandler) buildMCPContainerSpec(dataDir, dataMount, resolvPath string) ctr.ContainerSpec {
 	mounts := []ctr.MountSpec{
 		{
@@ -847,7 +847,7 @@ func (h *UsersHandler) UpsertBotChannelConfig(c echo.Context) error {
 	resp, err := h.channelLifecycle.UpsertBotChannelConfig(c.Request().Context(), botID, channelType, req)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if errors.Is(err, channel.ErrEnableChannelFailed) {
+		if errors.Is(err, channel.ErrEnableChannelFailed) || errors.Is(err, channel.ErrInvalidRouting) {
 			status = http.StatusBadRequest
 		}
 		return echo.NewHTTPError(status, err.Error())
@@ -0,0 +1,142 @@
+package containerchecker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/memohai/memoh/internal/healthcheck"
+	"github.com/memohai/memoh/internal/mcp"
+)
+
+const (
+	checkTypeContainerMemory = "container.memory"
+	titleKeyContainerMemory  = "bots.checks.titles.containerMemory"
+	checkTypeContainerDisk   = "container.disk"
+	titleKeyContainerDisk    = "bots.checks.titles.containerDisk"
+	// diskWarnRatio is the share of the disk quota above which the disk check warns.
+	diskWarnRatio = 0.9
+)
+
+// ResourceReader reports the resource limits and pressure of a bot container.
+type ResourceReader interface {
+	ResourceStatus(ctx context.Context, botID string) (mcp.ResourceStatus, error)
+}
+
+// Checker evaluates container memory and disk health checks.
+type Checker struct {
+	logger    *slog.Logger
+	resources ResourceReader
+}
+
+// NewChecker creates a container resource health checker.
+func NewChecker(log *slog.Logger, resources ResourceReader) *Checker {
+	if log == nil {
+		log = slog.Default()
+	}
+	return &Checker{
+		logger:    log.With(slog.String("checker", "healthcheck_container")),
+		resources: resources,
+	}
+}
+
+// ListChecks reports recent OOM kills and disk quota usage of a bot container. Checks
+// are only listed for limits that are set or were hit.
+func (c *Checker) ListChecks(ctx context.Context, botID string) []healthcheck.CheckResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	botID = strings.TrimSpace(botID)
+	if botID == "" || c.resources == nil {
+		return []healthcheck.CheckResult{}
+	}
+	status, err := c.resources.ResourceStatus(ctx, botID)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn(
+				"container healthcheck read resources failed",
+				slog.String("bot_id", botID),
+				slog.Any("error", err),
+			)
+		}
+		return []healthcheck.CheckResult{
+			{
+				ID:       checkTypeContainerMemory + ".status",
+				Type:     checkTypeContainerMemory,
+				TitleKey: titleKeyContainerMemory,
+				Status:   healthcheck.StatusUnknown,
+				Summary:  "Failed to read container resource usage.",
+				Detail:   err.Error(),
+			},
+		}
+	}
+
+	results := []healthcheck.CheckResult{}
+	if item, ok := memoryCheck(status); ok {
+		results = append(results, item)
+	}
+	if item, ok := diskCheck(status); ok {
+		results = append(results, item)
+	}
+	return results
+}
+
+func memoryCheck(status mcp.ResourceStatus) (healthcheck.CheckResult, bool) {
+	limitMB := status.Resources.MemoryMB
+	if limitMB <= 0 && status.OOMKills == 0 {
+		return healthcheck.CheckResult{}, false
+	}
+	item := healthcheck.CheckResult{
+		ID:       checkTypeContainerMemory,
+		Type:     checkTypeContainerMemory,
+		TitleKey: titleKeyContainerMemory,
+		Status:   healthcheck.StatusOK,
+		Summary:  fmt.Sprintf("No OOM kills in the last 24 hours (limit %d MB).", limitMB),
+		Metadata: map[string]any{
+			"memory_mb": limitMB,
+			"oom_kills": status.OOMKills,
+		},
+	}
+	if status.OOMKills > 0 {
+		item.Status = healthcheck.StatusWarn
+		item.Summary = fmt.Sprintf("The container ran out of memory %d time(s) in the last 24 hours.", status.OOMKills)
+		item.Detail = "Processes in the container were killed for exceeding the memory limit. Raise memory_mb or reduce the workload."
+		if !status.LastOOMAt.IsZero() {
+			item.Metadata["last_oom_at"] = status.LastOOMAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return item, true
+}
+
+func diskCheck(status mcp.ResourceStatus) (healthcheck.CheckResult, bool) {
+	quotaMB := status.Resources.DiskMB
+	if quotaMB <= 0 {
+		return healthcheck.CheckResult{}, false
+	}
+	quota := quotaMB << 20
+	usedMB := float64(status.DiskUsedBytes) / (1 << 20)
+	ratio := float64(status.DiskUsedBytes) / float64(quota)
+	item := healthcheck.CheckResult{
+		ID:       checkTypeContainerDisk,
+		Type:     checkTypeContainerDisk,
+		TitleKey: titleKeyContainerDisk,
+		Status:   healthcheck.StatusOK,
+		Summary:  fmt.Sprintf("Data directory uses %.1f of %d MB.", usedMB, quotaMB),
+		Metadata: map[string]any{
+			"disk_mb":    quotaMB,
+			"used_bytes": status.DiskUsedBytes,
+		},
+	}
+	switch {
+	case status.DiskUsedBytes >= quota:
+		item.Status = healthcheck.StatusError
+		item.Summary = fmt.Sprintf("Data directory exceeds its quota (%.1f of %d MB).", usedMB, quotaMB)
+		item.Detail = "The data directory is made read-only, or the container is stopped where that is not possible. Free up space or raise disk_mb."
+	case ratio >= diskWarnRatio:
+		item.Status = healthcheck.StatusWarn
+		item.Summary = fmt.Sprintf("Data directory is nearly full (%.1f of %d MB).", usedMB, quotaMB)
+	}
+	return item, true
+}
@@ -0,0 +1,90 @@
+package containerchecker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/memohai/memoh/internal/mcp"
+)
+
+type fakeResourceReader struct {
+	status mcp.ResourceStatus
+	err    error
+}
+
+func (f *fakeResourceReader) ResourceStatus(ctx context.Context, botID string) (mcp.ResourceStatus, error) {
+	return f.status, f.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestCheckerListChecks(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceReader{
+		status: mcp.ResourceStatus{
+			Resources:     mcp.Resources{MemoryMB: 512, DiskMB: 100},
+			DiskUsedBytes: 95 << 20,
+			OOMKills:      2,
+			LastOOMAt:     time.Now(),
+		},
+	})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(items))
+	}
+	if items[0].ID != "container.memory" || items[0].Status != "warn" {
+		t.Fatalf("expected memory warning, got %s %s", items[0].ID, items[0].Status)
+	}
+	if items[1].ID != "container.disk" || items[1].Status != "warn" {
+		t.Fatalf("expected disk warning, got %s %s", items[1].ID, items[1].Status)
+	}
+}
+
+func TestCheckerDiskOverQuota(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceReader{
+		status: mcp.ResourceStatus{
+			Resources:     mcp.Resources{DiskMB: 10},
+			DiskUsedBytes: 11 << 20,
+		},
+	})
+
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(items))
+	}
+	if items[0].Status != "error" {
+		t.Fatalf("expected error, got %s", items[0].Status)
+	}
+}
+
+func TestCheckerNoLimits(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceReader{})
+	if items := checker.ListChecks(context.Background(), "bot-1"); len(items) != 0 {
+		t.Fatalf("expected no checks, got %d", len(items))
+	}
+}
+
+func TestCheckerReadError(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(newTestLogger(), &fakeResourceReader{err: errors.New("db down")})
+	items := checker.ListChecks(context.Background(), "bot-1")
+	if len(items) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(items))
+	}
+	if items[0].Status != "unknown" || items[0].Detail != "db down" {
+		t.Fatalf("unexpected check: %+v", items[0])
+	}
+}
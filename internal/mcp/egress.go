@@ -0,0 +1,420 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/db"
+	dbsqlc "github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	maxEgressAllow      = 256
+	egressLookupTimeout = 5 * time.Second
+	// egressRefresh is how often allowlisted domains are resolved again, so rules follow
+	// DNS changes of the allowed services.
+	egressRefresh = 10 * time.Minute
+	egressRetry   = 30 * time.Second
+	egressTagLen  = 12
+)
+
+// ErrInvalidEgressPolicy is returned when an egress policy has an unknown mode or an
+// unparsable allowlist entry.
+var ErrInvalidEgressPolicy = errors.New("invalid egress policy")
+
+// EgressPolicy is the outbound network access of a bot container.
+type EgressPolicy struct {
+	Mode ctr.EgressMode `json:"mode"`
+	// Allow lists the domains, IP addresses and CIDRs reachable in allowlist mode.
+	// Domains are resolved when the rules are applied and every 10 minutes after.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// EgressDenial is an outbound destination a bot container tried to reach and was
+// denied, with how often it happened.
+type EgressDenial struct {
+	Destination string    `json:"destination"`
+	Port        int       `json:"port,omitempty"`
+	Protocol    string    `json:"protocol,omitempty"`
+	Hits        int       `json:"hits"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// normalize validates the policy and returns it with lowercased, deduplicated entries.
+func (p EgressPolicy) normalize() (EgressPolicy, error) {
+	switch p.Mode {
+	case "":
+		p.Mode = ctr.EgressFull
+	case ctr.EgressFull, ctr.EgressNone, ctr.EgressAllowlist:
+	default:
+		return EgressPolicy{}, fmt.Errorf("%w: unknown mode %q", ErrInvalidEgressPolicy, p.Mode)
+	}
+	if len(p.Allow) > maxEgressAllow {
+		return EgressPolicy{}, fmt.Errorf("%w: at most %d allow entries", ErrInvalidEgressPolicy, maxEgressAllow)
+	}
+	seen := make(map[string]struct{}, len(p.Allow))
+	allow := make([]string, 0, len(p.Allow))
+	for _, entry := range p.Allow {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if _, _, err := parseEgressEntry(entry); err != nil {
+			return EgressPolicy{}, err
+		}
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		allow = append(allow, entry)
+	}
+	p.Allow = allow
+	return p, nil
+}
+
+// parseEgressEntry parses an allowlist entry as a prefix, or returns the domain to
+// resolve.
+func parseEgressEntry(entry string) (netip.Prefix, string, error) {
+	if prefix, err := netip.ParsePrefix(entry); err == nil {
+		return prefix.Masked(), "", nil
+	}
+	if addr, err := netip.ParseAddr(entry); err == nil {
+		return netip.PrefixFrom(addr, addr.BitLen()), "", nil
+	}
+	if !validDomain(entry) {
+		return netip.Prefix{}, "", fmt.Errorf("%w: %q is not a domain, IP address or CIDR", ErrInvalidEgressPolicy, entry)
+	}
+	return netip.Prefix{}, entry, nil
+}
+
+func validDomain(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) == 0 || len(name) > 253 || !strings.Contains(name, ".") {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// egressTag is the short bot identifier written to the kernel log of denied packets.
+func egressTag(botID string) string {
+	tag := strings.ReplaceAll(botID, "-", "")
+	if len(tag) > egressTagLen {
+		tag = tag[:egressTagLen]
+	}
+	return tag
+}
+
+// GetEgressPolicy returns the egress policy of a bot. Bots without one have full access.
+func (m *Manager) GetEgressPolicy(ctx context.Context, botID string) (EgressPolicy, error) {
+	if err := validateBotID(botID); err != nil {
+		return EgressPolicy{}, err
+	}
+	if m.queries == nil {
+		return EgressPolicy{Mode: ctr.EgressFull}, nil
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return EgressPolicy{}, err
+	}
+	row, err := m.queries.GetBotEgressPolicy(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return EgressPolicy{Mode: ctr.EgressFull}, nil
+		}
+		return EgressPolicy{}, err
+	}
+	return decodeEgressPolicy(row.Policy)
+}
+
+// UpdateEgressPolicy stores the egress policy of a bot and applies it to the running
+// container right away.
+func (m *Manager) UpdateEgressPolicy(ctx context.Context, botID string, policy EgressPolicy) (EgressPolicy, error) {
+	if err := validateBotID(botID); err != nil {
+		return EgressPolicy{}, err
+	}
+	if m.queries == nil {
+		return EgressPolicy{}, fmt.Errorf("db is not configured")
+	}
+	policy, err := policy.normalize()
+	if err != nil {
+		return EgressPolicy{}, err
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return EgressPolicy{}, err
+	}
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		return EgressPolicy{}, err
+	}
+	if _, err := m.queries.UpsertBotEgressPolicy(ctx, dbsqlc.UpsertBotEgressPolicyParams{
+		BotID:  pgBotID,
+		Policy: payload,
+	}); err != nil {
+		return EgressPolicy{}, err
+	}
+	if err := m.applyEgress(ctx, botID, policy); err != nil {
+		return policy, fmt.Errorf("policy saved but not applied to the running container: %w", err)
+	}
+	return policy, nil
+}
+
+// ListEgressDenials returns the destinations a bot container was denied, most recent
+// first.
+func (m *Manager) ListEgressDenials(ctx context.Context, botID string, limit int) ([]EgressDenial, error) {
+	if err := validateBotID(botID); err != nil {
+		return nil, err
+	}
+	if m.queries == nil {
+		return []EgressDenial{}, nil
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := m.queries.ListBotEgressDenials(ctx, dbsqlc.ListBotEgressDenialsParams{
+		BotID:    pgBotID,
+		MaxCount: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]EgressDenial, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, EgressDenial{
+			Destination: row.Destination,
+			Port:        int(row.Port),
+			Protocol:    row.Protocol,
+			Hits:        int(row.Hits),
+			FirstSeenAt: row.FirstSeenAt.Time,
+			LastSeenAt:  row.LastSeenAt.Time,
+		})
+	}
+	return items, nil
+}
+
+// NetworkRequest returns the network setup request of a bot container with its egress
+// rules. When the policy cannot be loaded the container gets no egress at all.
+func (m *Manager) NetworkRequest(ctx context.Context, botID string) ctr.NetworkSetupRequest {
+	req := ctr.NetworkSetupRequest{
+		ContainerID: m.containerID(botID),
+		CNIBinDir:   m.cfg.CNIBinaryDir,
+		CNIConfDir:  m.cfg.CNIConfigDir,
+	}
+	policy, err := m.GetEgressPolicy(ctx, botID)
+	if err != nil {
+		m.logger.Error("load egress policy failed, blocking egress", slog.String("bot_id", botID), slog.Any("error", err))
+		policy = EgressPolicy{Mode: ctr.EgressNone}
+	}
+	if policy.Mode != ctr.EgressFull {
+		rules := m.egressRules(ctx, botID, policy)
+		req.Egress = &rules
+	}
+	return req
+}
+
+// WatchEgress records the connections denied by egress rules and keeps the resolved
+// addresses of allowlisted domains current. It returns when ctx is canceled or the host
+// cannot enforce egress policies.
+func (m *Manager) WatchEgress(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go m.refreshEgress(ctx)
+	for {
+		err := m.service.WatchEgressDenials(ctx, func(denial ctr.EgressDenial) {
+			m.recordEgressDenial(ctx, denial)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, ctr.ErrNotSupported) {
+			return err
+		}
+		m.logger.Warn("egress denial log unavailable, retrying", slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(egressRetry):
+		}
+	}
+}
+
+func (m *Manager) refreshEgress(ctx context.Context) {
+	if m.queries == nil {
+		return
+	}
+	ticker := time.NewTicker(egressRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		rows, err := m.queries.ListBotEgressPolicies(ctx)
+		if err != nil {
+			m.logger.Warn("list egress policies failed", slog.Any("error", err))
+			continue
+		}
+		for _, row := range rows {
+			policy, err := decodeEgressPolicy(row.Policy)
+			if err != nil || policy.Mode != ctr.EgressAllowlist || !hasDomains(policy) {
+				continue
+			}
+			botID := uuidString(row.BotID)
+			if err := m.applyEgress(ctx, botID, policy); err != nil {
+				m.logger.Warn("refresh egress rules failed", slog.String("bot_id", botID), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// applyEgress replaces the rules of the running container of a bot. Bots without a
+// running container pick the policy up when it starts.
+func (m *Manager) applyEgress(ctx context.Context, botID string, policy EgressPolicy) error {
+	err := m.service.ApplyEgress(ctx, m.containerID(botID), m.egressRules(ctx, botID, policy))
+	if err == nil || errdefs.IsNotFound(err) {
+		return nil
+	}
+	if errors.Is(err, ctr.ErrNotSupported) && policy.Mode == ctr.EgressFull {
+		return nil
+	}
+	return err
+}
+
+func (m *Manager) egressRules(ctx context.Context, botID string, policy EgressPolicy) ctr.EgressRules {
+	tag := egressTag(botID)
+	m.egressMu.Lock()
+	m.egressTags[tag] = botID
+	m.egressMu.Unlock()
+
+	rules := ctr.EgressRules{Mode: policy.Mode, Tag: tag}
+	if policy.Mode != ctr.EgressAllowlist {
+		return rules
+	}
+	for _, entry := range policy.Allow {
+		prefix, domain, err := parseEgressEntry(entry)
+		if err != nil {
+			continue
+		}
+		if domain == "" {
+			rules.Allow = append(rules.Allow, prefix)
+			continue
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, egressLookupTimeout)
+		addrs, err := net.DefaultResolver.LookupNetIP(lookupCtx, "ip", domain)
+		cancel()
+		if err != nil {
+			m.logger.Warn("resolve allowed domain failed", slog.String("bot_id", botID), slog.String("domain", domain), slog.Any("error", err))
+			continue
+		}
+		for _, addr := range addrs {
+			addr = addr.Unmap()
+			rules.Allow = append(rules.Allow, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	if dataDir, err := m.DataDir(botID); err == nil {
+		if resolvPath, err := ctr.ResolveConfSource(dataDir); err == nil {
+			rules.Nameservers, _ = ctr.Nameservers(resolvPath)
+		}
+	}
+	return rules
+}
+
+func (m *Manager) recordEgressDenial(ctx context.Context, denial ctr.EgressDenial) {
+	botID, ok := m.egressBot(ctx, denial.Tag)
+	if !ok || m.queries == nil {
+		return
+	}
+	m.logger.Info("bot container egress denied",
+		slog.String("bot_id", botID),
+		slog.String("destination", denial.Destination),
+		slog.Int("port", denial.Port),
+		slog.String("protocol", denial.Protocol),
+	)
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return
+	}
+	if err := m.queries.RecordBotEgressDenial(ctx, dbsqlc.RecordBotEgressDenialParams{
+		BotID:       pgBotID,
+		Destination: denial.Destination,
+		Port:        int32(denial.Port),
+		Protocol:    denial.Protocol,
+		SeenAt:      pgtype.Timestamptz{Time: denial.Time, Valid: true},
+	}); err != nil {
+		m.logger.Warn("record egress denial failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+// egressBot maps a kernel log tag back to its bot, reloading the policies when the tag
+// was applied before a restart.
+func (m *Manager) egressBot(ctx context.Context, tag string) (string, bool) {
+	m.egressMu.Lock()
+	botID, ok := m.egressTags[tag]
+	m.egressMu.Unlock()
+	if ok || m.queries == nil {
+		return botID, ok
+	}
+	rows, err := m.queries.ListBotEgressPolicies(ctx)
+	if err != nil {
+		m.logger.Warn("list egress policies failed", slog.Any("error", err))
+		return "", false
+	}
+	m.egressMu.Lock()
+	defer m.egressMu.Unlock()
+	for _, row := range rows {
+		id := uuidString(row.BotID)
+		m.egressTags[egressTag(id)] = id
+	}
+	botID, ok = m.egressTags[tag]
+	return botID, ok
+}
+
+func decodeEgressPolicy(raw []byte) (EgressPolicy, error) {
+	policy := EgressPolicy{Mode: ctr.EgressFull}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			return EgressPolicy{}, err
+		}
+	}
+	if policy.Mode == "" {
+		policy.Mode = ctr.EgressFull
+	}
+	return policy, nil
+}
+
+func hasDomains(policy EgressPolicy) bool {
+	for _, entry := range policy.Allow {
+		if _, domain, err := parseEgressEntry(entry); err == nil && domain != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+func TestEgressPolicyNormalize(t *testing.T) {
+	policy, err := EgressPolicy{
+		Mode:  ctr.EgressAllowlist,
+		Allow: []string{" API.OpenAI.com ", "10.0.0.0/8", "1.1.1.1", "api.openai.com", "", "2001:db8::/32"},
+	}.normalize()
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	want := []string{"api.openai.com", "10.0.0.0/8", "1.1.1.1", "2001:db8::/32"}
+	if len(policy.Allow) != len(want) {
+		t.Fatalf("expected %v, got %v", want, policy.Allow)
+	}
+	for i := range want {
+		if policy.Allow[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, policy.Allow)
+		}
+	}
+
+	if policy, err := (EgressPolicy{}).normalize(); err != nil || policy.Mode != ctr.EgressFull {
+		t.Fatalf("expected empty mode to mean full, got %+v %v", policy, err)
+	}
+
+	invalid := []EgressPolicy{
+		{Mode: "open"},
+		{Mode: ctr.EgressAllowlist, Allow: []string{"*.example.com"}},
+		{Mode: ctr.EgressAllowlist, Allow: []string{"localhost"}},
+		{Mode: ctr.EgressAllowlist, Allow: []string{"bad_host.example.com"}},
+	}
+	for i, policy := range invalid {
+		if _, err := policy.normalize(); !errors.Is(err, ErrInvalidEgressPolicy) {
+			t.Fatalf("case %d: expected ErrInvalidEgressPolicy, got %v", i, err)
+		}
+	}
+}
+
+func TestEgressTag(t *testing.T) {
+	tag := egressTag("3f1c6a2e-8d4b-4c1e-9a7f-2b5d6e8f0a1c")
+	if tag != "3f1c6a2e8d4b" {
+		t.Fatalf("unexpected tag %q", tag)
+	}
+	if len(ctr.EgressLogPrefix+tag+" ") > 29 {
+		t.Fatalf("log prefix too long for iptables: %d", len(ctr.EgressLogPrefix+tag+" "))
+	}
+}
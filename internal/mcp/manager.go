@@ -3,6 +3,7 @@ package mcp
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -55,6 +56,10 @@ type Manager struct {
 	logger          *slog.Logger
 	containerLockMu sync.Mutex
 	containerLocks  map[string]*sync.Mutex
+	egressMu        sync.Mutex
+	egressTags      map[string]string
+	diskMu          sync.Mutex
+	diskStates      map[string]diskMountState
 }
 
 func NewManager(log *slog.Logger, service ctr.Service, cfg config.MCPConfig, namespace string, conn *pgxpool.Pool) *Manager {
@@ -69,6 +74,8 @@ func NewManager(log *slog.Logger, service ctr.Service, cfg config.MCPConfig, nam
 		queries:        dbsqlc.New(conn),
 		logger:         log.With(slog.String("component", "mcp")),
 		containerLocks: make(map[string]*sync.Mutex),
+		egressTags:     make(map[string]string),
+		diskStates:     make(map[string]diskMountState),
 		containerID: func(botID string) string {
 			return ContainerPrefix + botID
 		},
@@ -132,6 +139,13 @@ func (m *Manager) EnsureBot(ctx context.Context, botID string) error {
 	}
 	tzMounts, tzEnv := ctr.TimezoneSpec()
 	mounts = append(mounts, tzMounts...)
+	spec := ctr.ContainerSpec{
+		Mounts: mounts,
+		Env:    tzEnv,
+	}
+	if err := m.ApplyResources(ctx, botID, &spec); err != nil {
+		return err
+	}
 
 	_, err = m.service.CreateContainer(ctx, ctr.CreateContainerRequest{
 		ID:          m.containerID(botID),
@@ -140,10 +154,7 @@ func (m *Manager) EnsureBot(ctx context.Context, botID string) error {
 		Labels: map[string]string{
 			BotLabelKey: botID,
 		},
-		Spec: ctr.ContainerSpec{
-			Mounts: mounts,
-			Env:    tzEnv,
-		},
+		Spec: spec,
 	})
 	if err == nil {
 		return nil
@@ -184,16 +195,20 @@ func (m *Manager) Start(ctx context.Context, botID string) error {
 	}); err != nil {
 		return err
 	}
-	if err := m.service.SetupNetwork(ctx, ctr.NetworkSetupRequest{
-		ContainerID: m.containerID(botID),
-		CNIBinDir:   m.cfg.CNIBinaryDir,
-		CNIConfDir:  m.cfg.CNIConfigDir,
-	}); err != nil {
+	if err := m.service.SetupNetwork(ctx, m.NetworkRequest(ctx, botID)); err != nil {
 		if stopErr := m.service.StopContainer(ctx, m.containerID(botID), &ctr.StopTaskOptions{Force: true}); stopErr != nil {
 			m.logger.Warn("cleanup: stop task failed", slog.String("container_id", m.containerID(botID)), slog.Any("error", stopErr))
 		}
 		return err
 	}
+	// An existing container keeps the data mount it was created with, so the disk quota
+	// is enforced again on the new task.
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		if errors.Is(err, ErrDiskQuotaExceeded) {
+			return err
+		}
+		m.logger.Warn("enforce disk quota failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
 	return nil
 }
 
@@ -7,14 +7,24 @@ import (
 	"log/slog"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/approval"
+	"github.com/memohai/memoh/internal/channel/outbox"
 	mcpgw "github.com/memohai/memoh/internal/mcp"
 )
 
 const (
-	toolSend  = "send"
-	toolReact = "react"
+	toolSend          = "send"
+	toolReact         = "react"
+	toolAsk           = "ask"
+	toolReplyTo       = "reply_to"
+	toolEditMessage   = "edit_message"
+	toolDeleteMessage = "delete_message"
+
+	toolListScheduled   = "list_scheduled_messages"
+	toolCancelScheduled = "cancel_scheduled_message"
 )
 
 // Sender sends outbound messages through channel manager.
@@ -27,6 +37,33 @@ type Reactor interface {
 	React(ctx context.Context, botID string, channelType channel.ChannelType, req channel.ReactRequest) error
 }
 
+// Editor edits and deletes messages the bot sent earlier through channel manager.
+type Editor interface {
+	EditMessage(ctx context.Context, botID string, channelType channel.ChannelType, req channel.EditMessageRequest) error
+	DeleteMessage(ctx context.Context, botID string, channelType channel.ChannelType, req channel.DeleteMessageRequest) error
+}
+
+// SentMessageLookup finds messages the bot delivered to a platform: the latest reply in a
+// conversation, and whether a platform message ID belongs to the bot.
+type SentMessageLookup interface {
+	LatestOutboundMessageID(ctx context.Context, botID, platform, replyTarget string) (string, error)
+	IsOutboundMessageID(ctx context.Context, botID, platform, target, messageID string) (bool, error)
+}
+
+// Scheduler queues messages in the channel outbox for delivery at a later time.
+type Scheduler interface {
+	Schedule(ctx context.Context, botID string, source outbox.Source, createdBy string, req outbox.ScheduleRequest) (outbox.Message, error)
+	List(ctx context.Context, botID string, req outbox.ListRequest) ([]outbox.Message, error)
+	Cancel(ctx context.Context, botID, id string) error
+	Location(ctx context.Context, botID string) *time.Location
+}
+
+// OutboundGuard applies the bot's outbound policy to messages sent outside the current
+// conversation.
+type OutboundGuard interface {
+	Authorize(ctx context.Context, req approval.Request) (approval.Result, error)
+}
+
 // ChannelTypeResolver parses platform name to channel type.
 type ChannelTypeResolver interface {
 	ParseChannelType(raw string) (channel.ChannelType, error)
@@ -48,30 +85,55 @@ type AssetResolver interface {
 	IngestContainerFile(ctx context.Context, botID, containerPath string) (AssetMeta, error)
 }
 
-// Executor exposes send and react as MCP tools.
+// Executor exposes send, react, ask, reply_to, edit_message and delete_message as MCP
+// tools, plus list_scheduled_messages and cancel_scheduled_message when a scheduler is set.
 type Executor struct {
 	sender        Sender
 	reactor       Reactor
+	editor        Editor
 	resolver      ChannelTypeResolver
 	assetResolver AssetResolver
+	sentMessages  SentMessageLookup
+	scheduler     Scheduler
+	guard         OutboundGuard
 	logger        *slog.Logger
 }
 
 // NewExecutor creates a message tool executor.
-// reactor and assetResolver may be nil.
-func NewExecutor(log *slog.Logger, sender Sender, reactor Reactor, resolver ChannelTypeResolver, assetResolver AssetResolver) *Executor {
+// reactor, editor and assetResolver may be nil.
+func NewExecutor(log *slog.Logger, sender Sender, reactor Reactor, editor Editor, resolver ChannelTypeResolver, assetResolver AssetResolver) *Executor {
 	if log == nil {
 		log = slog.Default()
 	}
 	return &Executor{
 		sender:        sender,
 		reactor:       reactor,
+		editor:        editor,
 		resolver:      resolver,
 		assetResolver: assetResolver,
 		logger:        log.With(slog.String("provider", "message_tool")),
 	}
 }
 
+// SetSentMessageLookup lets edit_message and delete_message verify that a message was
+// sent by the bot, and default to its latest reply in the current conversation when no
+// message_id is given. Without a lookup both tools refuse to run.
+func (p *Executor) SetSentMessageLookup(lookup SentMessageLookup) {
+	p.sentMessages = lookup
+}
+
+// SetScheduler enables send_at and delay on the send tool and the tools that list and
+// cancel scheduled messages.
+func (p *Executor) SetScheduler(scheduler Scheduler) {
+	p.scheduler = scheduler
+}
+
+// SetOutboundGuard makes send and ask check messages to other conversations against
+// the bot's outbound policy.
+func (p *Executor) SetOutboundGuard(guard OutboundGuard) {
+	p.guard = guard
+}
+
 func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionContext) ([]mcpgw.ToolDescriptor, error) {
 	var tools []mcpgw.ToolDescriptor
 	if p.sender != nil && p.resolver != nil {
@@ -101,6 +163,14 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 						"type":        "string",
 						"description": "Message ID to reply to. The reply will reference this message on the platform.",
 					},
+					"thread_id": map[string]any{
+						"type":        "string",
+						"description": "Thread ID to post into, on platforms that support threads",
+					},
+					"thread_name": map[string]any{
+						"type":        "string",
+						"description": "Title of a new thread to open for this message (started from reply_to when given), on platforms that support threads",
+					},
 					"attachments": map[string]any{
 						"type":        "array",
 						"description": "File paths or URLs to attach. Each item is a container path (e.g. /data/media/ab/file.jpg), an HTTP URL, or an object with {path, url, type, name}.",
@@ -114,6 +184,17 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 				"required": []string{},
 			},
 		})
+		if p.scheduler != nil {
+			properties := tools[len(tools)-1].InputSchema["properties"].(map[string]any)
+			properties["send_at"] = map[string]any{
+				"type":        "string",
+				"description": "Schedule the message instead of sending it now. An RFC 3339 timestamp, a local date and time (2006-01-02 15:04) or a time of day (15:04, the next occurrence). Local times use the bot's timezone.",
+			}
+			properties["delay"] = map[string]any{
+				"type":        "string",
+				"description": "Schedule the message to be sent after this duration, e.g. 30m or 2h. Do not combine with send_at.",
+			}
+		}
 	}
 	if p.reactor != nil && p.resolver != nil {
 		tools = append(tools, mcpgw.ToolDescriptor{
@@ -151,6 +232,180 @@ func (p *Executor) ListTools(ctx context.Context, session mcpgw.ToolSessionConte
 			},
 		})
 	}
+	if p.sender != nil && p.resolver != nil {
+		tools = append(tools, mcpgw.ToolDescriptor{
+			Name:        toolAsk,
+			Description: "Ask the user a multiple-choice question rendered as buttons. The selection arrives later as a new message, so STOP after calling this tool.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"bot_id": map[string]any{
+						"type":        "string",
+						"description": "Bot ID, optional and defaults to current bot",
+					},
+					"platform": map[string]any{
+						"type":        "string",
+						"description": "Channel platform name. Defaults to current session platform.",
+					},
+					"target": map[string]any{
+						"type":        "string",
+						"description": "Channel target (chat/group ID). Defaults to current session reply target.",
+					},
+					"question": map[string]any{
+						"type":        "string",
+						"description": "Question text shown above the buttons",
+					},
+					"options": map[string]any{
+						"type":        "array",
+						"description": "Choices to offer. Each item is a string or an object with {label, value}.",
+						"items":       map[string]any{},
+					},
+				},
+				"required": []string{"question", "options"},
+			},
+		})
+	}
+	if p.sender != nil && p.resolver != nil {
+		tools = append(tools, mcpgw.ToolDescriptor{
+			Name:        toolReplyTo,
+			Description: "Reply to a specific earlier message in the current conversation. The reply quotes that message on the platform.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"bot_id": map[string]any{
+						"type":        "string",
+						"description": "Bot ID, optional and defaults to current bot",
+					},
+					"platform": map[string]any{
+						"type":        "string",
+						"description": "Channel platform name. Defaults to current session platform.",
+					},
+					"target": map[string]any{
+						"type":        "string",
+						"description": "Channel target (chat/group ID). Defaults to current session reply target.",
+					},
+					"message_id": map[string]any{
+						"type":        "string",
+						"description": "The message ID to reply to",
+					},
+					"text": map[string]any{
+						"type":        "string",
+						"description": "Reply text",
+					},
+					"thread_name": map[string]any{
+						"type":        "string",
+						"description": "Open a new thread from that message with this title and reply inside it, on platforms that support threads",
+					},
+					"attachments": map[string]any{
+						"type":        "array",
+						"description": "File paths or URLs to attach, same format as the send tool.",
+						"items":       map[string]any{},
+					},
+				},
+				"required": []string{"message_id"},
+			},
+		})
+	}
+	if p.editor != nil && p.resolver != nil {
+		tools = append(tools, mcpgw.ToolDescriptor{
+			Name:        toolEditMessage,
+			Description: "Correct a message you sent earlier by replacing its text. Without message_id, edits your latest reply in the current conversation.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"bot_id": map[string]any{
+						"type":        "string",
+						"description": "Bot ID, optional and defaults to current bot",
+					},
+					"platform": map[string]any{
+						"type":        "string",
+						"description": "Channel platform name. Defaults to current session platform.",
+					},
+					"target": map[string]any{
+						"type":        "string",
+						"description": "Channel target (chat/group ID). Defaults to current session reply target.",
+					},
+					"message_id": map[string]any{
+						"type":        "string",
+						"description": "ID of your own message to edit, as returned by send or reply_to. Defaults to your latest reply here.",
+					},
+					"text": map[string]any{
+						"type":        "string",
+						"description": "The new message text",
+					},
+				},
+				"required": []string{"text"},
+			},
+		})
+		tools = append(tools, mcpgw.ToolDescriptor{
+			Name:        toolDeleteMessage,
+			Description: "Retract a message you sent earlier. Without message_id, deletes your latest reply in the current conversation.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"bot_id": map[string]any{
+						"type":        "string",
+						"description": "Bot ID, optional and defaults to current bot",
+					},
+					"platform": map[string]any{
+						"type":        "string",
+						"description": "Channel platform name. Defaults to current session platform.",
+					},
+					"target": map[string]any{
+						"type":        "string",
+						"description": "Channel target (chat/group ID). Defaults to current session reply target.",
+					},
+					"message_id": map[string]any{
+						"type":        "string",
+						"description": "ID of your own message to delete, as returned by send or reply_to. Defaults to your latest reply here.",
+					},
+				},
+				"required": []string{},
+			},
+		})
+	}
+	if p.scheduler != nil {
+		tools = append(tools, mcpgw.ToolDescriptor{
+			Name:        toolListScheduled,
+			Description: "List messages you scheduled with send_at or delay, earliest first. Only pending messages are listed unless a status is given.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"bot_id": map[string]any{
+						"type":        "string",
+						"description": "Bot ID, optional and defaults to current bot",
+					},
+					"status": map[string]any{
+						"type":        "string",
+						"description": "Filter by status: pending, sending, sent, failed, canceled or all. Defaults to pending.",
+					},
+					"limit": map[string]any{
+						"type":        "integer",
+						"description": "Maximum number of messages to return. Defaults to 50.",
+					},
+				},
+				"required": []string{},
+			},
+		})
+		tools = append(tools, mcpgw.ToolDescriptor{
+			Name:        toolCancelScheduled,
+			Description: "Cancel a scheduled message that has not been sent yet",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"bot_id": map[string]any{
+						"type":        "string",
+						"description": "Bot ID, optional and defaults to current bot",
+					},
+					"id": map[string]any{
+						"type":        "string",
+						"description": "ID of the scheduled message, as returned by send or list_scheduled_messages",
+					},
+				},
+				"required": []string{"id"},
+			},
+		})
+	}
 	return tools, nil
 }
 
@@ -160,6 +415,18 @@ func (p *Executor) CallTool(ctx context.Context, session mcpgw.ToolSessionContex
 		return p.callSend(ctx, session, arguments)
 	case toolReact:
 		return p.callReact(ctx, session, arguments)
+	case toolAsk:
+		return p.callAsk(ctx, session, arguments)
+	case toolReplyTo:
+		return p.callReplyTo(ctx, session, arguments)
+	case toolEditMessage:
+		return p.callEditMessage(ctx, session, arguments)
+	case toolDeleteMessage:
+		return p.callDeleteMessage(ctx, session, arguments)
+	case toolListScheduled:
+		return p.callListScheduled(ctx, session, arguments)
+	case toolCancelScheduled:
+		return p.callCancelScheduled(ctx, session, arguments)
 	default:
 		return nil, mcpgw.ErrToolNotFound
 	}
@@ -206,6 +473,11 @@ func (p *Executor) callSend(ctx context.Context, session mcpgw.ToolSessionContex
 	if replyTo := mcpgw.FirstStringArg(arguments, "reply_to"); replyTo != "" {
 		outboundMessage.Reply = &channel.ReplyRef{MessageID: replyTo}
 	}
+	threadID := mcpgw.FirstStringArg(arguments, "thread_id")
+	threadName := mcpgw.FirstStringArg(arguments, "thread_name")
+	if threadID != "" || threadName != "" {
+		outboundMessage.Thread = &channel.ThreadRef{ID: threadID, Name: threadName}
+	}
 
 	target := mcpgw.FirstStringArg(arguments, "target")
 	if target == "" {
@@ -215,11 +487,24 @@ func (p *Executor) callSend(ctx context.Context, session mcpgw.ToolSessionContex
 		return mcpgw.BuildToolErrorResult("target is required"), nil
 	}
 
+	sendAt := mcpgw.FirstStringArg(arguments, "send_at")
+	delay := mcpgw.FirstStringArg(arguments, "delay")
+	if (sendAt != "" || delay != "") && p.scheduler == nil {
+		return mcpgw.BuildToolErrorResult("scheduled messages not available"), nil
+	}
+	if result, held := p.guardOutbound(ctx, session, botID, channelType, target, outboundMessage, sendAt, delay); held {
+		return result, nil
+	}
+	if sendAt != "" || delay != "" {
+		return p.scheduleSend(ctx, session, botID, channelType, target, outboundMessage, sendAt, delay)
+	}
+
 	sendReq := channel.SendRequest{
 		Target:  target,
 		Message: outboundMessage,
 	}
-	if err := p.sender.Send(ctx, botID, channelType, sendReq); err != nil {
+	sent := channel.NewSentMessageRecorder()
+	if err := p.sender.Send(channel.WithSentMessageRecorder(ctx, sent), botID, channelType, sendReq); err != nil {
 		p.logger.Warn("send failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
 		return mcpgw.BuildToolErrorResult(err.Error()), nil
 	}
@@ -229,11 +514,285 @@ func (p *Executor) callSend(ctx context.Context, session mcpgw.ToolSessionContex
 		"bot_id":      botID,
 		"platform":    channelType.String(),
 		"target":      target,
+		"message_ids": sent.MessageIDs(),
 		"instruction": "Message delivered successfully. You have completed your response. Please STOP now and do not call any more tools.",
 	}
 	return mcpgw.BuildToolSuccessResult(payload), nil
 }
 
+func (p *Executor) scheduleSend(ctx context.Context, session mcpgw.ToolSessionContext, botID string, channelType channel.ChannelType, target string, msg channel.Message, sendAt, delay string) (map[string]any, error) {
+	item, err := p.scheduler.Schedule(ctx, botID, outbox.SourceTool, session.ChannelIdentityID, outbox.ScheduleRequest{
+		Platform: channelType.String(),
+		Target:   target,
+		Message:  msg,
+		SendAt:   sendAt,
+		Delay:    delay,
+	})
+	if err != nil {
+		p.logger.Warn("schedule failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	payload := map[string]any{
+		"ok":          true,
+		"bot_id":      botID,
+		"platform":    channelType.String(),
+		"target":      target,
+		"scheduled":   true,
+		"id":          item.ID,
+		"send_at":     item.SendAt.In(p.scheduler.Location(ctx, botID)).Format(time.RFC3339),
+		"instruction": "Message scheduled. It will be delivered at send_at; use cancel_scheduled_message with this id to cancel it.",
+	}
+	return mcpgw.BuildToolSuccessResult(payload), nil
+}
+
+// guardOutbound checks a message to a conversation other than the current one against
+// the outbound policy. It returns the tool result and true unless the message may be
+// sent right away.
+func (p *Executor) guardOutbound(ctx context.Context, session mcpgw.ToolSessionContext, botID string, channelType channel.ChannelType, target string, msg channel.Message, sendAt, delay string) (map[string]any, bool) {
+	if p.guard == nil {
+		return nil, false
+	}
+	if strings.EqualFold(strings.TrimSpace(session.CurrentPlatform), channelType.String()) && target == strings.TrimSpace(session.ReplyTarget) {
+		return nil, false
+	}
+	result, err := p.guard.Authorize(ctx, approval.Request{
+		BotID:       botID,
+		Platform:    channelType.String(),
+		Target:      target,
+		Message:     msg,
+		SendAt:      sendAt,
+		Delay:       delay,
+		RequestedBy: session.ChannelIdentityID,
+	})
+	if err != nil {
+		p.logger.Warn("outbound policy check failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
+		return mcpgw.BuildToolErrorResult(err.Error()), true
+	}
+	switch result.Outcome {
+	case approval.OutcomeAllowed:
+		return nil, false
+	case approval.OutcomeDenied:
+		return mcpgw.BuildToolErrorResult("message not sent: " + result.Reason), true
+	case approval.OutcomeApproved:
+		return mcpgw.BuildToolSuccessResult(map[string]any{
+			"ok":          true,
+			"bot_id":      botID,
+			"platform":    channelType.String(),
+			"target":      target,
+			"approval_id": result.ApprovalID,
+			"id":          result.OutboxMessageID,
+			"instruction": "The owner approved this message and it was queued for delivery. Please STOP now and do not call any more tools.",
+		}), true
+	default:
+		return mcpgw.BuildToolSuccessResult(map[string]any{
+			"ok":          true,
+			"held":        true,
+			"bot_id":      botID,
+			"platform":    channelType.String(),
+			"target":      target,
+			"approval_id": result.ApprovalID,
+			"reason":      result.Reason,
+			"instruction": "This target needs the owner's approval. The message is held and will be delivered only if they approve it. Do not try to send it another way. Please STOP now.",
+		}), true
+	}
+}
+
+// --- list_scheduled_messages / cancel_scheduled_message ---
+
+func (p *Executor) callListScheduled(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
+	if p.scheduler == nil {
+		return mcpgw.BuildToolErrorResult("scheduled messages not available"), nil
+	}
+	botID, err := p.resolveBotID(arguments, session)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	req := outbox.ListRequest{Status: outbox.StatusPending}
+	switch status := strings.ToLower(mcpgw.FirstStringArg(arguments, "status")); status {
+	case "":
+	case "all":
+		req.Status = ""
+	default:
+		req.Status = outbox.Status(status)
+	}
+	limit, _, err := mcpgw.IntArg(arguments, "limit")
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	req.Limit = limit
+	items, err := p.scheduler.List(ctx, botID, req)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	loc := p.scheduler.Location(ctx, botID)
+	messages := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		entry := map[string]any{
+			"id":       item.ID,
+			"platform": item.Platform,
+			"target":   item.Target,
+			"text":     item.Message.PlainText(),
+			"status":   string(item.Status),
+			"send_at":  item.SendAt.In(loc).Format(time.RFC3339),
+		}
+		if item.LastError != "" {
+			entry["last_error"] = item.LastError
+		}
+		messages = append(messages, entry)
+	}
+	return mcpgw.BuildToolSuccessResult(map[string]any{
+		"bot_id":   botID,
+		"timezone": loc.String(),
+		"messages": messages,
+	}), nil
+}
+
+func (p *Executor) callCancelScheduled(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
+	if p.scheduler == nil {
+		return mcpgw.BuildToolErrorResult("scheduled messages not available"), nil
+	}
+	botID, err := p.resolveBotID(arguments, session)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	id := mcpgw.FirstStringArg(arguments, "id")
+	if id == "" {
+		return mcpgw.BuildToolErrorResult("id is required"), nil
+	}
+	if err := p.scheduler.Cancel(ctx, botID, id); err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	return mcpgw.BuildToolSuccessResult(map[string]any{
+		"ok":     true,
+		"bot_id": botID,
+		"id":     id,
+		"action": "canceled",
+	}), nil
+}
+
+// --- reply_to ---
+
+func (p *Executor) callReplyTo(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
+	messageID := mcpgw.FirstStringArg(arguments, "message_id")
+	if messageID == "" {
+		return mcpgw.BuildToolErrorResult("message_id is required"), nil
+	}
+	sendArgs := make(map[string]any, len(arguments)+1)
+	for k, v := range arguments {
+		sendArgs[k] = v
+	}
+	sendArgs["reply_to"] = messageID
+	return p.callSend(ctx, session, sendArgs)
+}
+
+// --- edit_message / delete_message ---
+
+func (p *Executor) callEditMessage(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
+	if p.editor == nil || p.resolver == nil {
+		return mcpgw.BuildToolErrorResult("message editing not available"), nil
+	}
+	botID, channelType, target, messageID, err := p.resolveOwnMessage(ctx, session, arguments)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	text := mcpgw.FirstStringArg(arguments, "text")
+	if text == "" {
+		return mcpgw.BuildToolErrorResult("text is required"), nil
+	}
+	req := channel.EditMessageRequest{
+		Target:    target,
+		MessageID: messageID,
+		Message:   channel.Message{Text: text},
+	}
+	if err := p.editor.EditMessage(ctx, botID, channelType, req); err != nil {
+		p.logger.Warn("edit failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	payload := map[string]any{
+		"ok":         true,
+		"bot_id":     botID,
+		"platform":   channelType.String(),
+		"target":     target,
+		"message_id": messageID,
+		"action":     "edited",
+	}
+	return mcpgw.BuildToolSuccessResult(payload), nil
+}
+
+func (p *Executor) callDeleteMessage(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
+	if p.editor == nil || p.resolver == nil {
+		return mcpgw.BuildToolErrorResult("message editing not available"), nil
+	}
+	botID, channelType, target, messageID, err := p.resolveOwnMessage(ctx, session, arguments)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	req := channel.DeleteMessageRequest{
+		Target:    target,
+		MessageID: messageID,
+	}
+	if err := p.editor.DeleteMessage(ctx, botID, channelType, req); err != nil {
+		p.logger.Warn("delete failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	payload := map[string]any{
+		"ok":         true,
+		"bot_id":     botID,
+		"platform":   channelType.String(),
+		"target":     target,
+		"message_id": messageID,
+		"action":     "deleted",
+	}
+	return mcpgw.BuildToolSuccessResult(payload), nil
+}
+
+// resolveOwnMessage resolves the target and message of an edit or delete. Without an
+// explicit message_id it falls back to the bot's latest reply in the current conversation.
+// Explicit IDs must be recorded as sent by the bot, so a bot with admin rights cannot be
+// talked into changing messages of other members.
+func (p *Executor) resolveOwnMessage(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (string, channel.ChannelType, string, string, error) {
+	botID, err := p.resolveBotID(arguments, session)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	channelType, err := p.resolvePlatform(arguments, session)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	target := mcpgw.FirstStringArg(arguments, "target")
+	if target == "" {
+		target = strings.TrimSpace(session.ReplyTarget)
+	}
+	if target == "" {
+		return "", "", "", "", fmt.Errorf("target is required")
+	}
+	if p.sentMessages == nil {
+		return "", "", "", "", fmt.Errorf("sent messages cannot be verified")
+	}
+	messageID := mcpgw.FirstStringArg(arguments, "message_id")
+	if messageID != "" {
+		own, err := p.sentMessages.IsOutboundMessageID(ctx, botID, channelType.String(), target, messageID)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		if !own {
+			return "", "", "", "", fmt.Errorf("message %s was not sent by this bot", messageID)
+		}
+		return botID, channelType, target, messageID, nil
+	}
+	if target != strings.TrimSpace(session.ReplyTarget) || channelType.String() != strings.TrimSpace(session.CurrentPlatform) {
+		return "", "", "", "", fmt.Errorf("message_id is required")
+	}
+	messageID, err = p.sentMessages.LatestOutboundMessageID(ctx, botID, channelType.String(), target)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	if messageID == "" {
+		return "", "", "", "", fmt.Errorf("no earlier reply found in this conversation, message_id is required")
+	}
+	return botID, channelType, target, messageID, nil
+}
+
 // --- react ---
 
 func (p *Executor) callReact(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
@@ -293,6 +852,99 @@ func (p *Executor) callReact(ctx context.Context, session mcpgw.ToolSessionConte
 	return mcpgw.BuildToolSuccessResult(payload), nil
 }
 
+// --- ask ---
+
+func (p *Executor) callAsk(ctx context.Context, session mcpgw.ToolSessionContext, arguments map[string]any) (map[string]any, error) {
+	if p.sender == nil || p.resolver == nil {
+		return mcpgw.BuildToolErrorResult("message service not available"), nil
+	}
+
+	botID, err := p.resolveBotID(arguments, session)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+	channelType, err := p.resolvePlatform(arguments, session)
+	if err != nil {
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+
+	question := mcpgw.FirstStringArg(arguments, "question")
+	if question == "" {
+		return mcpgw.BuildToolErrorResult("question is required"), nil
+	}
+	actions := parseAskOptions(arguments["options"])
+	if len(actions) == 0 {
+		return mcpgw.BuildToolErrorResult("options are required"), nil
+	}
+
+	target := mcpgw.FirstStringArg(arguments, "target")
+	if target == "" {
+		target = strings.TrimSpace(session.ReplyTarget)
+	}
+	if target == "" {
+		return mcpgw.BuildToolErrorResult("target is required"), nil
+	}
+
+	sendReq := channel.SendRequest{
+		Target: target,
+		Message: channel.Message{
+			Text:    question,
+			Actions: actions,
+		},
+	}
+	if result, held := p.guardOutbound(ctx, session, botID, channelType, target, sendReq.Message, "", ""); held {
+		return result, nil
+	}
+	if err := p.sender.Send(ctx, botID, channelType, sendReq); err != nil {
+		p.logger.Warn("ask failed", slog.Any("error", err), slog.String("bot_id", botID), slog.String("platform", string(channelType)))
+		return mcpgw.BuildToolErrorResult(err.Error()), nil
+	}
+
+	options := make([]string, 0, len(actions))
+	for _, action := range actions {
+		options = append(options, action.Value)
+	}
+	payload := map[string]any{
+		"ok":          true,
+		"bot_id":      botID,
+		"platform":    channelType.String(),
+		"target":      target,
+		"options":     options,
+		"instruction": "Question delivered. The user's choice will arrive as a new message like [User selected \"...\"]. Please STOP now and wait for it.",
+	}
+	return mcpgw.BuildToolSuccessResult(payload), nil
+}
+
+// parseAskOptions converts ask options (strings or {label, value} objects) into button actions.
+func parseAskOptions(raw any) []channel.Action {
+	items, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+	actions := make([]channel.Action, 0, len(items))
+	for _, item := range items {
+		var label, value string
+		switch v := item.(type) {
+		case string:
+			label = strings.TrimSpace(v)
+		case map[string]any:
+			label = mcpgw.FirstStringArg(v, "label")
+			value = mcpgw.FirstStringArg(v, "value")
+		}
+		if value == "" {
+			value = label
+		}
+		if label == "" {
+			label = value
+		}
+		if value == "" {
+			continue
+		}
+		actions = append(actions, channel.Action{Type: "button", Label: label, Value: value})
+	}
+	return actions
+}
+
 // --- shared helpers ---
 
 func (p *Executor) resolveBotID(arguments map[string]any, session mcpgw.ToolSessionContext) (string, error) {
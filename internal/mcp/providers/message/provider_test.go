@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/memohai/memoh/internal/channel"
+	"github.com/memohai/memoh/internal/channel/approval"
+	"github.com/memohai/memoh/internal/channel/outbox"
 	mcpgw "github.com/memohai/memoh/internal/mcp"
 )
 
@@ -29,6 +32,67 @@ func (f *fakeReactor) React(ctx context.Context, botID string, channelType chann
 	return f.err
 }
 
+type fakeEditor struct {
+	err        error
+	lastEdit   channel.EditMessageRequest
+	lastDelete channel.DeleteMessageRequest
+}
+
+func (f *fakeEditor) EditMessage(ctx context.Context, botID string, channelType channel.ChannelType, req channel.EditMessageRequest) error {
+	f.lastEdit = req
+	return f.err
+}
+
+func (f *fakeEditor) DeleteMessage(ctx context.Context, botID string, channelType channel.ChannelType, req channel.DeleteMessageRequest) error {
+	f.lastDelete = req
+	return f.err
+}
+
+type fakeSentMessages struct {
+	id string
+}
+
+func (f *fakeSentMessages) LatestOutboundMessageID(ctx context.Context, botID, platform, replyTarget string) (string, error) {
+	return f.id, nil
+}
+
+func (f *fakeSentMessages) IsOutboundMessageID(ctx context.Context, botID, platform, target, messageID string) (bool, error) {
+	return target == "123" && messageID == f.id, nil
+}
+
+type fakeScheduler struct {
+	lastReq  outbox.ScheduleRequest
+	canceled string
+}
+
+func (f *fakeScheduler) Schedule(ctx context.Context, botID string, source outbox.Source, createdBy string, req outbox.ScheduleRequest) (outbox.Message, error) {
+	f.lastReq = req
+	return outbox.Message{ID: "job1", SendAt: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)}, nil
+}
+
+func (f *fakeScheduler) List(ctx context.Context, botID string, req outbox.ListRequest) ([]outbox.Message, error) {
+	return nil, nil
+}
+
+func (f *fakeScheduler) Cancel(ctx context.Context, botID, id string) error {
+	f.canceled = id
+	return nil
+}
+
+func (f *fakeScheduler) Location(ctx context.Context, botID string) *time.Location {
+	return time.FixedZone("UTC+9", 9*60*60)
+}
+
+type fakeGuard struct {
+	result approval.Result
+	calls  []approval.Request
+}
+
+func (f *fakeGuard) Authorize(ctx context.Context, req approval.Request) (approval.Result, error) {
+	f.calls = append(f.calls, req)
+	return f.result, nil
+}
+
 type fakeResolver struct {
 	ct  channel.ChannelType
 	err error
@@ -44,7 +108,7 @@ func (f *fakeResolver) ParseChannelType(raw string) (channel.ChannelType, error)
 // --- send tests ---
 
 func TestExecutor_ListTools_NilDeps(t *testing.T) {
-	exec := NewExecutor(nil, nil, nil, nil, nil)
+	exec := NewExecutor(nil, nil, nil, nil, nil, nil)
 	tools, err := exec.ListTools(context.Background(), mcpgw.ToolSessionContext{})
 	if err != nil {
 		t.Fatal(err)
@@ -58,13 +122,13 @@ func TestExecutor_ListTools(t *testing.T) {
 	sender := &fakeSender{}
 	reactor := &fakeReactor{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, reactor, resolver, nil)
+	exec := NewExecutor(nil, sender, reactor, nil, resolver, nil)
 	tools, err := exec.ListTools(context.Background(), mcpgw.ToolSessionContext{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(tools) != 2 {
-		t.Fatalf("expected 2 tools, got %d", len(tools))
+	if len(tools) != 4 {
+		t.Fatalf("expected 4 tools, got %d", len(tools))
 	}
 	if tools[0].Name != toolSend {
 		t.Errorf("tool[0] name = %q, want %q", tools[0].Name, toolSend)
@@ -72,28 +136,40 @@ func TestExecutor_ListTools(t *testing.T) {
 	if tools[1].Name != toolReact {
 		t.Errorf("tool[1] name = %q, want %q", tools[1].Name, toolReact)
 	}
+	if tools[2].Name != toolAsk {
+		t.Errorf("tool[2] name = %q, want %q", tools[2].Name, toolAsk)
+	}
+	if tools[3].Name != toolReplyTo {
+		t.Errorf("tool[3] name = %q, want %q", tools[3].Name, toolReplyTo)
+	}
 }
 
 func TestExecutor_ListTools_OnlySender(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	tools, err := exec.ListTools(context.Background(), mcpgw.ToolSessionContext{})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(tools) != 1 {
-		t.Fatalf("expected 1 tool, got %d", len(tools))
+	if len(tools) != 3 {
+		t.Fatalf("expected 3 tools, got %d", len(tools))
 	}
 	if tools[0].Name != toolSend {
 		t.Errorf("tool name = %q, want %q", tools[0].Name, toolSend)
 	}
+	if tools[1].Name != toolAsk {
+		t.Errorf("tool name = %q, want %q", tools[1].Name, toolAsk)
+	}
+	if tools[2].Name != toolReplyTo {
+		t.Errorf("tool name = %q, want %q", tools[2].Name, toolReplyTo)
+	}
 }
 
 func TestExecutor_CallTool_NotFound(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	_, err := exec.CallTool(context.Background(), mcpgw.ToolSessionContext{BotID: "bot1"}, "other_tool", nil)
 	if err != mcpgw.ErrToolNotFound {
 		t.Errorf("expected ErrToolNotFound, got %v", err)
@@ -101,7 +177,7 @@ func TestExecutor_CallTool_NotFound(t *testing.T) {
 }
 
 func TestExecutor_CallTool_NilDeps(t *testing.T) {
-	exec := NewExecutor(nil, nil, nil, nil, nil)
+	exec := NewExecutor(nil, nil, nil, nil, nil, nil)
 	result, err := exec.CallTool(context.Background(), mcpgw.ToolSessionContext{BotID: "bot1"}, toolSend, map[string]any{
 		"platform": "feishu", "target": "t1", "text": "hi",
 	})
@@ -116,7 +192,7 @@ func TestExecutor_CallTool_NilDeps(t *testing.T) {
 func TestExecutor_CallTool_NoBotID(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	result, err := exec.CallTool(context.Background(), mcpgw.ToolSessionContext{}, toolSend, map[string]any{
 		"platform": "feishu", "target": "t1", "text": "hi",
 	})
@@ -131,7 +207,7 @@ func TestExecutor_CallTool_NoBotID(t *testing.T) {
 func TestExecutor_CallTool_BotIDMismatch(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"bot_id": "other", "platform": "feishu", "target": "t1", "text": "hi",
@@ -147,7 +223,7 @@ func TestExecutor_CallTool_BotIDMismatch(t *testing.T) {
 func TestExecutor_CallTool_NoPlatform(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"target": "t1", "text": "hi",
@@ -163,7 +239,7 @@ func TestExecutor_CallTool_NoPlatform(t *testing.T) {
 func TestExecutor_CallTool_PlatformParseError(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{err: errors.New("unknown platform")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "feishu"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"platform": "bad", "target": "t1", "text": "hi",
@@ -179,7 +255,7 @@ func TestExecutor_CallTool_PlatformParseError(t *testing.T) {
 func TestExecutor_CallTool_NoMessage(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"platform": "feishu", "target": "t1",
@@ -195,7 +271,7 @@ func TestExecutor_CallTool_NoMessage(t *testing.T) {
 func TestExecutor_CallTool_NoTarget(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"platform": "feishu", "text": "hi",
@@ -211,7 +287,7 @@ func TestExecutor_CallTool_NoTarget(t *testing.T) {
 func TestExecutor_CallTool_SendError(t *testing.T) {
 	sender := &fakeSender{err: errors.New("send failed")}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", ReplyTarget: "t1"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"platform": "feishu", "text": "hi",
@@ -227,7 +303,7 @@ func TestExecutor_CallTool_SendError(t *testing.T) {
 func TestExecutor_CallTool_Success(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("feishu")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "feishu", ReplyTarget: "chat1"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"text": "hello",
@@ -253,7 +329,7 @@ func TestExecutor_CallTool_Success(t *testing.T) {
 func TestExecutor_CallTool_ReplyTo(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"text":     "reply text",
@@ -276,7 +352,7 @@ func TestExecutor_CallTool_ReplyTo(t *testing.T) {
 func TestExecutor_CallTool_NoReplyTo(t *testing.T) {
 	sender := &fakeSender{}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, sender, nil, resolver, nil)
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
 	result, err := exec.CallTool(context.Background(), session, toolSend, map[string]any{
 		"text": "no reply",
@@ -292,10 +368,83 @@ func TestExecutor_CallTool_NoReplyTo(t *testing.T) {
 	}
 }
 
+func TestExecutor_CallTool_Thread(t *testing.T) {
+	sender := &fakeSender{}
+	resolver := &fakeResolver{ct: channel.ChannelType("discord")}
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "discord", ReplyTarget: "123"}
+	result, err := exec.CallTool(context.Background(), session, toolReplyTo, map[string]any{
+		"text":        "let's continue here",
+		"message_id":  "msg-1",
+		"thread_name": "Follow-up",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	thread := sender.lastReq.Message.Thread
+	if thread == nil || thread.Name != "Follow-up" || thread.ID != "" {
+		t.Fatalf("unexpected thread: %+v", thread)
+	}
+	if sender.lastReq.Message.Reply == nil || sender.lastReq.Message.Reply.MessageID != "msg-1" {
+		t.Fatalf("expected reply to msg-1, got %+v", sender.lastReq.Message.Reply)
+	}
+}
+
+// --- ask tests ---
+
+func TestExecutor_Ask_Success(t *testing.T) {
+	sender := &fakeSender{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+	result, err := exec.CallTool(context.Background(), session, toolAsk, map[string]any{
+		"question": "Deploy now?",
+		"options":  []any{"Yes", map[string]any{"label": "Later", "value": "later"}, map[string]any{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if sender.lastReq.Target != "123" || sender.lastReq.Message.Text != "Deploy now?" {
+		t.Fatalf("unexpected request: %#v", sender.lastReq)
+	}
+	actions := sender.lastReq.Message.Actions
+	if len(actions) != 2 {
+		t.Fatalf("expected 2 actions, got %d", len(actions))
+	}
+	if actions[0].Label != "Yes" || actions[0].Value != "Yes" {
+		t.Errorf("actions[0] = %#v", actions[0])
+	}
+	if actions[1].Label != "Later" || actions[1].Value != "later" {
+		t.Errorf("actions[1] = %#v", actions[1])
+	}
+}
+
+func TestExecutor_Ask_NoOptions(t *testing.T) {
+	sender := &fakeSender{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+	result, err := exec.CallTool(context.Background(), session, toolAsk, map[string]any{
+		"question": "Deploy now?",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Error("expected error when options are missing")
+	}
+}
+
 // --- react tests ---
 
 func TestExecutor_React_NilReactor(t *testing.T) {
-	exec := NewExecutor(nil, nil, nil, nil, nil)
+	exec := NewExecutor(nil, nil, nil, nil, nil, nil)
 	result, err := exec.CallTool(context.Background(), mcpgw.ToolSessionContext{BotID: "bot1"}, toolReact, map[string]any{
 		"platform": "telegram", "target": "123", "message_id": "456", "emoji": "👍",
 	})
@@ -310,7 +459,7 @@ func TestExecutor_React_NilReactor(t *testing.T) {
 func TestExecutor_React_NoMessageID(t *testing.T) {
 	reactor := &fakeReactor{}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, nil, reactor, resolver, nil)
+	exec := NewExecutor(nil, nil, reactor, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
 	result, err := exec.CallTool(context.Background(), session, toolReact, map[string]any{
 		"emoji": "👍",
@@ -326,7 +475,7 @@ func TestExecutor_React_NoMessageID(t *testing.T) {
 func TestExecutor_React_NoTarget(t *testing.T) {
 	reactor := &fakeReactor{}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, nil, reactor, resolver, nil)
+	exec := NewExecutor(nil, nil, reactor, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram"}
 	result, err := exec.CallTool(context.Background(), session, toolReact, map[string]any{
 		"message_id": "456", "emoji": "👍",
@@ -342,7 +491,7 @@ func TestExecutor_React_NoTarget(t *testing.T) {
 func TestExecutor_React_Success(t *testing.T) {
 	reactor := &fakeReactor{}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, nil, reactor, resolver, nil)
+	exec := NewExecutor(nil, nil, reactor, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
 	result, err := exec.CallTool(context.Background(), session, toolReact, map[string]any{
 		"message_id": "456", "emoji": "👍",
@@ -371,7 +520,7 @@ func TestExecutor_React_Success(t *testing.T) {
 func TestExecutor_React_Remove(t *testing.T) {
 	reactor := &fakeReactor{}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, nil, reactor, resolver, nil)
+	exec := NewExecutor(nil, nil, reactor, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
 	result, err := exec.CallTool(context.Background(), session, toolReact, map[string]any{
 		"message_id": "456", "remove": true,
@@ -394,7 +543,7 @@ func TestExecutor_React_Remove(t *testing.T) {
 func TestExecutor_React_Error(t *testing.T) {
 	reactor := &fakeReactor{err: errors.New("reaction failed")}
 	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
-	exec := NewExecutor(nil, nil, reactor, resolver, nil)
+	exec := NewExecutor(nil, nil, reactor, nil, resolver, nil)
 	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
 	result, err := exec.CallTool(context.Background(), session, toolReact, map[string]any{
 		"message_id": "456", "emoji": "👍",
@@ -407,6 +556,199 @@ func TestExecutor_React_Error(t *testing.T) {
 	}
 }
 
+// --- reply_to / edit_message / delete_message tests ---
+
+func TestExecutor_ReplyTo(t *testing.T) {
+	sender := &fakeSender{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+	result, err := exec.CallTool(context.Background(), session, toolReplyTo, map[string]any{
+		"message_id": "42",
+		"text":       "answer",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if sender.lastReq.Target != "123" || sender.lastReq.Message.Reply == nil || sender.lastReq.Message.Reply.MessageID != "42" {
+		t.Errorf("unexpected request: %+v", sender.lastReq)
+	}
+
+	result, _ = exec.CallTool(context.Background(), session, toolReplyTo, map[string]any{"text": "answer"})
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Error("expected error without message_id")
+	}
+}
+
+func TestExecutor_ListTools_Editor(t *testing.T) {
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, nil, nil, &fakeEditor{}, resolver, nil)
+	tools, err := exec.ListTools(context.Background(), mcpgw.ToolSessionContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 2 || tools[0].Name != toolEditMessage || tools[1].Name != toolDeleteMessage {
+		t.Fatalf("unexpected tools: %+v", tools)
+	}
+}
+
+func TestExecutor_EditMessage(t *testing.T) {
+	editor := &fakeEditor{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, nil, nil, editor, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+	args := map[string]any{"message_id": "7", "text": "fixed"}
+	result, _ := exec.CallTool(context.Background(), session, toolEditMessage, args)
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Fatal("expected error when sent messages cannot be verified")
+	}
+
+	exec.SetSentMessageLookup(&fakeSentMessages{id: "7"})
+	result, err := exec.CallTool(context.Background(), session, toolEditMessage, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if editor.lastEdit.Target != "123" || editor.lastEdit.MessageID != "7" || editor.lastEdit.Message.Text != "fixed" {
+		t.Errorf("unexpected edit: %+v", editor.lastEdit)
+	}
+
+	editor.lastEdit = channel.EditMessageRequest{}
+	result, _ = exec.CallTool(context.Background(), session, toolEditMessage, map[string]any{"message_id": "8", "text": "gone"})
+	if isErr, _ := result["isError"].(bool); !isErr || editor.lastEdit.MessageID != "" {
+		t.Error("expected messages of other members to be rejected")
+	}
+}
+
+func TestExecutor_DeleteMessageDefaultsToLatestReply(t *testing.T) {
+	editor := &fakeEditor{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, nil, nil, editor, resolver, nil)
+	exec.SetSentMessageLookup(&fakeSentMessages{id: "99"})
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+	result, err := exec.CallTool(context.Background(), session, toolDeleteMessage, map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if editor.lastDelete.Target != "123" || editor.lastDelete.MessageID != "99" {
+		t.Errorf("unexpected delete: %+v", editor.lastDelete)
+	}
+
+	// The latest reply is only a default for the current conversation.
+	result, _ = exec.CallTool(context.Background(), session, toolDeleteMessage, map[string]any{"target": "456"})
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Error("expected error for another target without message_id")
+	}
+
+	// Message IDs are checked against the conversation they were sent to.
+	editor.lastDelete = channel.DeleteMessageRequest{}
+	result, _ = exec.CallTool(context.Background(), session, toolDeleteMessage, map[string]any{"target": "456", "message_id": "99"})
+	if isErr, _ := result["isError"].(bool); !isErr || editor.lastDelete.MessageID != "" {
+		t.Error("expected error for a message sent to another target")
+	}
+}
+
+func TestExecutor_ScheduledSend(t *testing.T) {
+	sender := &fakeSender{}
+	scheduler := &fakeScheduler{}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+
+	result, _ := exec.CallTool(context.Background(), session, toolSend, map[string]any{"text": "later", "delay": "1h"})
+	if isErr, _ := result["isError"].(bool); !isErr {
+		t.Fatal("expected error when scheduling is not configured")
+	}
+
+	exec.SetScheduler(scheduler)
+	tools, err := exec.ListTools(context.Background(), session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 5 || tools[3].Name != toolListScheduled || tools[4].Name != toolCancelScheduled {
+		t.Fatalf("unexpected tools: %+v", tools)
+	}
+	result, err = exec.CallTool(context.Background(), session, toolSend, map[string]any{"text": "later", "send_at": "18:00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if sender.lastReq.Message.Text != "" {
+		t.Fatalf("scheduled message was sent immediately: %+v", sender.lastReq)
+	}
+	if scheduler.lastReq.Target != "123" || scheduler.lastReq.SendAt != "18:00" || scheduler.lastReq.Message.Text != "later" {
+		t.Fatalf("unexpected schedule request: %+v", scheduler.lastReq)
+	}
+	content, _ := result["structuredContent"].(map[string]any)
+	if content["id"] != "job1" || content["send_at"] != "2026-03-10T18:00:00+09:00" {
+		t.Fatalf("unexpected result: %+v", content)
+	}
+
+	result, _ = exec.CallTool(context.Background(), session, toolCancelScheduled, map[string]any{"id": "job1"})
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if scheduler.canceled != "job1" {
+		t.Fatalf("expected job1 canceled, got %q", scheduler.canceled)
+	}
+}
+
+func TestExecutor_OutboundGuard(t *testing.T) {
+	sender := &fakeSender{}
+	guard := &fakeGuard{result: approval.Result{Outcome: approval.OutcomePending, ApprovalID: "a1"}}
+	resolver := &fakeResolver{ct: channel.ChannelType("telegram")}
+	exec := NewExecutor(nil, sender, nil, nil, resolver, nil)
+	exec.SetOutboundGuard(guard)
+	session := mcpgw.ToolSessionContext{BotID: "bot1", CurrentPlatform: "telegram", ReplyTarget: "123"}
+
+	// Replies in the current conversation skip the policy.
+	result, _ := exec.CallTool(context.Background(), session, toolSend, map[string]any{"text": "hi"})
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if len(guard.calls) != 0 || sender.lastReq.Target != "123" {
+		t.Fatalf("expected direct send, guard calls %d, sent %+v", len(guard.calls), sender.lastReq)
+	}
+
+	sender.lastReq = channel.SendRequest{}
+	result, _ = exec.CallTool(context.Background(), session, toolSend, map[string]any{"text": "hi", "target": "456"})
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := result["structuredContent"].(map[string]any)
+	if content["held"] != true || content["approval_id"] != "a1" {
+		t.Fatalf("expected held result, got %+v", content)
+	}
+	if sender.lastReq.Target != "" || len(guard.calls) != 1 || guard.calls[0].Target != "456" {
+		t.Fatalf("held message must not be sent: sent %+v, calls %+v", sender.lastReq, guard.calls)
+	}
+
+	guard.result = approval.Result{Outcome: approval.OutcomeDenied, Reason: "not allowed"}
+	result, _ = exec.CallTool(context.Background(), session, toolSend, map[string]any{"text": "hi", "target": "456"})
+	if isErr, _ := result["isError"].(bool); !isErr || sender.lastReq.Target != "" {
+		t.Fatalf("expected denied send to fail without sending, got %+v", result)
+	}
+
+	guard.result = approval.Result{Outcome: approval.OutcomeAllowed}
+	result, _ = exec.CallTool(context.Background(), session, toolSend, map[string]any{"text": "hi", "target": "456"})
+	if err := mcpgw.PayloadError(result); err != nil {
+		t.Fatal(err)
+	}
+	if sender.lastReq.Target != "456" {
+		t.Fatalf("expected allowed send, got %+v", sender.lastReq)
+	}
+}
+
 // --- parseOutboundMessage tests ---
 
 func TestParseOutboundMessage(t *testing.T) {
@@ -0,0 +1,544 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/containerd/errdefs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/config"
+	ctr "github.com/memohai/memoh/internal/containerd"
+	"github.com/memohai/memoh/internal/db"
+	dbsqlc "github.com/memohai/memoh/internal/db/sqlc"
+)
+
+const (
+	// EventOOMKill is the lifecycle event recorded when the kernel kills a process of a
+	// bot container for exceeding its memory limit.
+	EventOOMKill = "oom_kill"
+	// EventDiskQuotaExceeded is the lifecycle event recorded when a running bot container
+	// loses write access to its data directory for exceeding its disk quota.
+	EventDiskQuotaExceeded = "disk_quota_exceeded"
+)
+
+const (
+	maxCPUShares = 262144
+	maxCPUs      = 1024
+	// minMemoryMB is the smallest memory limit runc accepts.
+	minMemoryMB = 6
+	oomWindow   = 24 * time.Hour
+	oomRetry    = 5 * time.Second
+	// diskCheckInterval is how often the data usage of running containers is checked
+	// against their disk quota.
+	diskCheckInterval = time.Minute
+)
+
+var (
+	// ErrInvalidResources is returned when container resource limits are out of range.
+	ErrInvalidResources = errors.New("invalid container resources")
+	// ErrDiskQuotaExceeded is returned when a container over its disk quota was stopped
+	// because its data mount could not be made read-only.
+	ErrDiskQuotaExceeded = errors.New("container disk quota exceeded")
+)
+
+// diskMountState is the data mount mode last set on a running container task.
+type diskMountState struct {
+	pid      uint32
+	readOnly bool
+}
+
+// Resources are the resource limits of a bot container. Zero leaves a resource
+// unlimited, except SwapMB where zero disables swap when memory is capped.
+type Resources struct {
+	CPUShares uint64  `json:"cpu_shares"`
+	CPUs      float64 `json:"cpus"`
+	MemoryMB  int64   `json:"memory_mb"`
+	SwapMB    int64   `json:"swap_mb"`
+	PidsLimit int64   `json:"pids_limit"`
+	DiskMB    int64   `json:"disk_mb"`
+}
+
+// ResourceOverrides are the limits a bot sets on top of the instance defaults. A nil
+// field inherits the default.
+type ResourceOverrides struct {
+	CPUShares *uint64  `json:"cpu_shares,omitempty"`
+	CPUs      *float64 `json:"cpus,omitempty"`
+	MemoryMB  *int64   `json:"memory_mb,omitempty"`
+	SwapMB    *int64   `json:"swap_mb,omitempty"`
+	PidsLimit *int64   `json:"pids_limit,omitempty"`
+	DiskMB    *int64   `json:"disk_mb,omitempty"`
+}
+
+// ResourcesResponse shows the limits of a bot container and where they come from.
+// Changes apply when the container is next created or recreated.
+type ResourcesResponse struct {
+	Defaults  Resources         `json:"defaults"`
+	Overrides ResourceOverrides `json:"overrides"`
+	Effective Resources         `json:"effective"`
+	UpdatedAt time.Time         `json:"updated_at,omitempty"`
+}
+
+// ResourceStatus is the recent resource pressure of a bot container.
+type ResourceStatus struct {
+	Resources     Resources
+	DiskUsedBytes int64
+	// OOMKills counts the OOM kills of the last 24 hours.
+	OOMKills  int
+	LastOOMAt time.Time
+}
+
+func resourcesFromConfig(cfg config.ContainerResourcesConfig) Resources {
+	return Resources{
+		CPUShares: cfg.CPUShares,
+		CPUs:      cfg.CPUs,
+		MemoryMB:  cfg.MemoryMB,
+		SwapMB:    cfg.SwapMB,
+		PidsLimit: cfg.PidsLimit,
+		DiskMB:    cfg.DiskMB,
+	}
+}
+
+func (r Resources) validate() error {
+	switch {
+	case r.CPUShares == 1 || r.CPUShares > maxCPUShares:
+		return fmt.Errorf("%w: cpu_shares must be 0 or between 2 and %d", ErrInvalidResources, maxCPUShares)
+	case r.CPUs < 0 || r.CPUs > maxCPUs:
+		return fmt.Errorf("%w: cpus must be between 0 and %d", ErrInvalidResources, maxCPUs)
+	case r.MemoryMB < 0 || (r.MemoryMB > 0 && r.MemoryMB < minMemoryMB):
+		return fmt.Errorf("%w: memory_mb must be 0 or at least %d", ErrInvalidResources, minMemoryMB)
+	case r.SwapMB < 0:
+		return fmt.Errorf("%w: swap_mb must not be negative", ErrInvalidResources)
+	case r.SwapMB > 0 && r.MemoryMB == 0:
+		return fmt.Errorf("%w: swap_mb requires memory_mb", ErrInvalidResources)
+	case r.PidsLimit < 0:
+		return fmt.Errorf("%w: pids_limit must not be negative", ErrInvalidResources)
+	case r.DiskMB < 0:
+		return fmt.Errorf("%w: disk_mb must not be negative", ErrInvalidResources)
+	}
+	return nil
+}
+
+// merge applies the overrides to r.
+func (r Resources) merge(o ResourceOverrides) Resources {
+	if o.CPUShares != nil {
+		r.CPUShares = *o.CPUShares
+	}
+	if o.CPUs != nil {
+		r.CPUs = *o.CPUs
+	}
+	if o.MemoryMB != nil {
+		r.MemoryMB = *o.MemoryMB
+	}
+	if o.SwapMB != nil {
+		r.SwapMB = *o.SwapMB
+	}
+	if o.PidsLimit != nil {
+		r.PidsLimit = *o.PidsLimit
+	}
+	if o.DiskMB != nil {
+		r.DiskMB = *o.DiskMB
+	}
+	return r
+}
+
+// limits converts the resources to cgroup limits.
+func (r Resources) limits() ctr.ResourceLimits {
+	limits := ctr.ResourceLimits{
+		CPUShares: r.CPUShares,
+		PidsLimit: r.PidsLimit,
+	}
+	if r.CPUs > 0 {
+		limits.CPUPeriod = ctr.DefaultCPUPeriod
+		limits.CPUQuota = int64(r.CPUs * float64(ctr.DefaultCPUPeriod))
+	}
+	if r.MemoryMB > 0 {
+		limits.MemoryLimit = r.MemoryMB << 20
+		limits.MemorySwapLimit = (r.MemoryMB + r.SwapMB) << 20
+	}
+	return limits
+}
+
+// GetResources returns the default, overridden and effective limits of a bot container.
+func (m *Manager) GetResources(ctx context.Context, botID string) (ResourcesResponse, error) {
+	if err := validateBotID(botID); err != nil {
+		return ResourcesResponse{}, err
+	}
+	overrides, updatedAt, err := m.resourceOverrides(ctx, botID)
+	if err != nil {
+		return ResourcesResponse{}, err
+	}
+	return m.resourcesResponse(overrides, updatedAt), nil
+}
+
+// UpdateResources replaces the limit overrides of a bot. They apply when the container
+// is next created or recreated, except the disk quota, which WatchDisk also enforces on
+// the running container.
+func (m *Manager) UpdateResources(ctx context.Context, botID string, overrides ResourceOverrides) (ResourcesResponse, error) {
+	if err := validateBotID(botID); err != nil {
+		return ResourcesResponse{}, err
+	}
+	if m.queries == nil {
+		return ResourcesResponse{}, fmt.Errorf("db is not configured")
+	}
+	if err := resourcesFromConfig(m.cfg.Resources).merge(overrides).validate(); err != nil {
+		return ResourcesResponse{}, err
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return ResourcesResponse{}, err
+	}
+	payload, err := json.Marshal(overrides)
+	if err != nil {
+		return ResourcesResponse{}, err
+	}
+	row, err := m.queries.UpsertBotContainerResources(ctx, dbsqlc.UpsertBotContainerResourcesParams{
+		BotID:     pgBotID,
+		Resources: payload,
+	})
+	if err != nil {
+		return ResourcesResponse{}, err
+	}
+	return m.resourcesResponse(overrides, row.UpdatedAt), nil
+}
+
+// ApplyResources sets the effective limits of a bot on a container spec. When the bot
+// data directory has outgrown its disk quota, the data mount is made read-only so the
+// bot can still read its files but not grow them further. WatchDisk does the same for
+// containers that are already running.
+func (m *Manager) ApplyResources(ctx context.Context, botID string, spec *ctr.ContainerSpec) error {
+	if err := validateBotID(botID); err != nil {
+		return err
+	}
+	resources, err := m.effectiveResources(ctx, botID)
+	if err != nil {
+		return err
+	}
+	if err := resources.validate(); err != nil {
+		return err
+	}
+	spec.Resources = resources.limits()
+	if resources.DiskMB <= 0 {
+		return nil
+	}
+	used, err := m.DiskUsage(ctx, botID)
+	if err != nil {
+		m.logger.Warn("measure bot data usage failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return nil
+	}
+	if used < resources.DiskMB<<20 {
+		return nil
+	}
+	m.logger.Warn("bot data exceeds disk quota, mounting read-only",
+		slog.String("bot_id", botID),
+		slog.Int64("used_bytes", used),
+		slog.Int64("quota_mb", resources.DiskMB),
+	)
+	for i, mount := range spec.Mounts {
+		if mount.Destination != m.dataMount() {
+			continue
+		}
+		options := make([]string, 0, len(mount.Options))
+		for _, option := range mount.Options {
+			if option == "rw" {
+				option = "ro"
+			}
+			options = append(options, option)
+		}
+		spec.Mounts[i].Options = options
+	}
+	return nil
+}
+
+// DiskUsage returns the bytes used by the data directory of a bot.
+func (m *Manager) DiskUsage(ctx context.Context, botID string) (int64, error) {
+	dir, err := m.DataDir(botID)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	err = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// ResourceStatus reports the effective limits, disk usage and recent OOM kills of a
+// bot container.
+func (m *Manager) ResourceStatus(ctx context.Context, botID string) (ResourceStatus, error) {
+	resources, err := m.effectiveResources(ctx, botID)
+	if err != nil {
+		return ResourceStatus{}, err
+	}
+	status := ResourceStatus{Resources: resources}
+	if resources.DiskMB > 0 {
+		if status.DiskUsedBytes, err = m.DiskUsage(ctx, botID); err != nil {
+			return ResourceStatus{}, err
+		}
+	}
+	if m.queries == nil {
+		return status, nil
+	}
+	row, err := m.queries.CountRecentLifecycleEvents(ctx, dbsqlc.CountRecentLifecycleEventsParams{
+		ContainerID: m.containerID(botID),
+		EventType:   EventOOMKill,
+		Since:       pgtype.Timestamptz{Time: time.Now().Add(-oomWindow), Valid: true},
+	})
+	if err != nil {
+		return ResourceStatus{}, err
+	}
+	status.OOMKills = int(row.EventCount)
+	if row.LastAt.Valid {
+		status.LastOOMAt = row.LastAt.Time
+	}
+	return status, nil
+}
+
+// WatchOOM records an oom_kill lifecycle event for every OOM kill in a bot container.
+// It resubscribes when the event stream breaks and returns when ctx is canceled or the
+// container backend cannot report OOM kills.
+func (m *Manager) WatchOOM(ctx context.Context) error {
+	for {
+		err := m.service.WatchOOM(ctx, func(event ctr.OOMEvent) {
+			m.recordOOM(ctx, event)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, ctr.ErrNotSupported) {
+			return err
+		}
+		m.logger.Warn("oom event stream broken, resubscribing", slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(oomRetry):
+		}
+	}
+}
+
+func (m *Manager) recordOOM(ctx context.Context, event ctr.OOMEvent) {
+	if !strings.HasPrefix(event.ContainerID, ContainerPrefix) {
+		return
+	}
+	botID := strings.TrimPrefix(event.ContainerID, ContainerPrefix)
+	m.logger.Warn("bot container OOM kill", slog.String("bot_id", botID), slog.String("container_id", event.ContainerID))
+	if m.queries == nil {
+		return
+	}
+	if err := m.insertEvent(ctx, event.ContainerID, EventOOMKill, map[string]any{
+		"bot_id": botID,
+		"time":   event.Time,
+	}); err != nil {
+		m.logger.Warn("record oom kill failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+// WatchDisk enforces the disk quota of running bot containers until ctx is canceled.
+// The data mount of a container over its quota is remounted read-only and made
+// writable again once usage drops below the quota. A container whose mount cannot be
+// switched is stopped instead.
+func (m *Manager) WatchDisk(ctx context.Context) error {
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		botIDs, err := m.ListBots(ctx)
+		if err != nil {
+			m.logger.Warn("list bot containers failed", slog.Any("error", err))
+			continue
+		}
+		for _, botID := range botIDs {
+			if ctx.Err() != nil {
+				return nil
+			}
+			unlock := m.lockContainer(m.containerID(botID))
+			err := m.enforceDisk(ctx, botID)
+			unlock()
+			if err != nil && !errors.Is(err, ErrDiskQuotaExceeded) {
+				m.logger.Warn("enforce disk quota failed", slog.String("bot_id", botID), slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// enforceDisk switches the data mount of the running container of a bot to match its
+// disk usage. It returns ErrDiskQuotaExceeded when the container had to be stopped.
+func (m *Manager) enforceDisk(ctx context.Context, botID string) error {
+	resources, err := m.effectiveResources(ctx, botID)
+	if err != nil {
+		return err
+	}
+	state, known := m.diskState(botID)
+	if resources.DiskMB <= 0 && !known {
+		return nil
+	}
+	containerID := m.containerID(botID)
+	task, err := m.service.GetTaskInfo(ctx, containerID)
+	if err != nil || task.Status != ctr.TaskStatusRunning {
+		m.setDiskState(botID, nil)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	var used int64
+	if resources.DiskMB > 0 {
+		if used, err = m.DiskUsage(ctx, botID); err != nil {
+			return err
+		}
+	}
+	readOnly := resources.DiskMB > 0 && used >= resources.DiskMB<<20
+	if known && state.pid == task.PID && state.readOnly == readOnly {
+		return nil
+	}
+
+	err = m.service.RemountReadOnly(ctx, containerID, m.dataMount(), readOnly)
+	if err == nil {
+		m.setDiskState(botID, &diskMountState{pid: task.PID, readOnly: readOnly})
+		if readOnly {
+			m.logger.Warn("bot data exceeds disk quota, data mount is now read-only",
+				slog.String("bot_id", botID),
+				slog.Int64("used_bytes", used),
+				slog.Int64("quota_mb", resources.DiskMB),
+			)
+			m.recordDiskQuota(ctx, botID, used, resources.DiskMB, "read_only")
+		}
+		return nil
+	}
+	if !readOnly {
+		// A container created over quota starts read-only, so a failed remount to
+		// writable is retried on the next pass. Without remount support the task keeps
+		// the mode it was created with until it restarts.
+		if errors.Is(err, ctr.ErrNotSupported) {
+			m.setDiskState(botID, &diskMountState{pid: task.PID})
+			return nil
+		}
+		return err
+	}
+
+	m.logger.Warn("remount bot data read-only failed, stopping container",
+		slog.String("bot_id", botID),
+		slog.Int64("used_bytes", used),
+		slog.Int64("quota_mb", resources.DiskMB),
+		slog.Any("error", err),
+	)
+	if err := m.service.StopContainer(ctx, containerID, &ctr.StopTaskOptions{Force: true}); err != nil {
+		return err
+	}
+	m.setDiskState(botID, nil)
+	m.recordDiskQuota(ctx, botID, used, resources.DiskMB, "stopped")
+	return ErrDiskQuotaExceeded
+}
+
+func (m *Manager) recordDiskQuota(ctx context.Context, botID string, used, quotaMB int64, action string) {
+	if m.queries == nil {
+		return
+	}
+	if err := m.insertEvent(ctx, m.containerID(botID), EventDiskQuotaExceeded, map[string]any{
+		"bot_id":     botID,
+		"used_bytes": used,
+		"quota_mb":   quotaMB,
+		"action":     action,
+	}); err != nil {
+		m.logger.Warn("record disk quota event failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+}
+
+func (m *Manager) diskState(botID string) (diskMountState, bool) {
+	m.diskMu.Lock()
+	defer m.diskMu.Unlock()
+	state, ok := m.diskStates[botID]
+	return state, ok
+}
+
+// setDiskState records the mount mode of a running task, or forgets it when state is nil.
+func (m *Manager) setDiskState(botID string, state *diskMountState) {
+	m.diskMu.Lock()
+	defer m.diskMu.Unlock()
+	if state == nil {
+		delete(m.diskStates, botID)
+		return
+	}
+	if m.diskStates == nil {
+		m.diskStates = make(map[string]diskMountState)
+	}
+	m.diskStates[botID] = *state
+}
+
+func (m *Manager) effectiveResources(ctx context.Context, botID string) (Resources, error) {
+	overrides, _, err := m.resourceOverrides(ctx, botID)
+	if err != nil {
+		return Resources{}, err
+	}
+	return resourcesFromConfig(m.cfg.Resources).merge(overrides), nil
+}
+
+func (m *Manager) resourceOverrides(ctx context.Context, botID string) (ResourceOverrides, pgtype.Timestamptz, error) {
+	var overrides ResourceOverrides
+	if m.queries == nil {
+		return overrides, pgtype.Timestamptz{}, nil
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return overrides, pgtype.Timestamptz{}, err
+	}
+	row, err := m.queries.GetBotContainerResources(ctx, pgBotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return overrides, pgtype.Timestamptz{}, nil
+		}
+		return overrides, pgtype.Timestamptz{}, err
+	}
+	if len(row.Resources) > 0 {
+		if err := json.Unmarshal(row.Resources, &overrides); err != nil {
+			return overrides, pgtype.Timestamptz{}, err
+		}
+	}
+	return overrides, row.UpdatedAt, nil
+}
+
+func (m *Manager) resourcesResponse(overrides ResourceOverrides, updatedAt pgtype.Timestamptz) ResourcesResponse {
+	defaults := resourcesFromConfig(m.cfg.Resources)
+	resp := ResourcesResponse{
+		Defaults:  defaults,
+		Overrides: overrides,
+		Effective: defaults.merge(overrides),
+	}
+	if updatedAt.Valid {
+		resp.UpdatedAt = updatedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/errdefs"
+
+	"github.com/memohai/memoh/internal/config"
+	ctr "github.com/memohai/memoh/internal/containerd"
+)
+
+func TestResourcesMergeAndLimits(t *testing.T) {
+	defaults := resourcesFromConfig(config.ContainerResourcesConfig{CPUs: 2, MemoryMB: 512, SwapMB: 256, PidsLimit: 128})
+	memory := int64(1024)
+	noPids := int64(0)
+	effective := defaults.merge(ResourceOverrides{MemoryMB: &memory, PidsLimit: &noPids})
+
+	if effective.CPUs != 2 || effective.MemoryMB != 1024 || effective.SwapMB != 256 || effective.PidsLimit != 0 {
+		t.Fatalf("unexpected effective resources: %+v", effective)
+	}
+	limits := effective.limits()
+	if limits.CPUQuota != 200000 || limits.CPUPeriod != ctr.DefaultCPUPeriod {
+		t.Fatalf("unexpected cpu limits: %+v", limits)
+	}
+	if limits.MemoryLimit != 1024<<20 || limits.MemorySwapLimit != 1280<<20 {
+		t.Fatalf("unexpected memory limits: %+v", limits)
+	}
+	if limits.PidsLimit != 0 {
+		t.Fatalf("expected pids unlimited, got %d", limits.PidsLimit)
+	}
+	if got := (Resources{}).limits(); got != (ctr.ResourceLimits{}) {
+		t.Fatalf("expected no limits, got %+v", got)
+	}
+}
+
+func TestResourcesValidate(t *testing.T) {
+	valid := Resources{CPUShares: 512, CPUs: 0.5, MemoryMB: 256, PidsLimit: 64, DiskMB: 1024}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("expected valid resources, got %v", err)
+	}
+	invalid := []Resources{
+		{CPUShares: 1},
+		{CPUs: -1},
+		{MemoryMB: 2},
+		{SwapMB: -1},
+		{SwapMB: 64},
+		{PidsLimit: -1},
+		{DiskMB: -1},
+	}
+	for i, resources := range invalid {
+		if err := resources.validate(); !errors.Is(err, ErrInvalidResources) {
+			t.Fatalf("case %d: expected ErrInvalidResources, got %v", i, err)
+		}
+	}
+}
+
+func TestApplyResourcesDiskQuota(t *testing.T) {
+	botID := "3f1c6a2e-8d4b-4c1e-9a7f-2b5d6e8f0a1c"
+	root := t.TempDir()
+	m := &Manager{
+		cfg:    config.MCPConfig{DataRoot: root, Resources: config.ContainerResourcesConfig{MemoryMB: 64, DiskMB: 1}},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	dataDir := filepath.Join(root, "bots", botID)
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	newSpec := func() ctr.ContainerSpec {
+		return ctr.ContainerSpec{Mounts: []ctr.MountSpec{
+			{Destination: config.DefaultDataMount, Type: "bind", Source: dataDir, Options: []string{"rbind", "rw"}},
+			{Destination: "/etc/resolv.conf", Type: "bind", Source: "/etc/resolv.conf", Options: []string{"rbind", "ro"}},
+		}}
+	}
+
+	spec := newSpec()
+	if err := m.ApplyResources(context.Background(), botID, &spec); err != nil {
+		t.Fatalf("apply resources: %v", err)
+	}
+	if spec.Resources.MemoryLimit != 64<<20 {
+		t.Fatalf("expected memory limit, got %+v", spec.Resources)
+	}
+	if spec.Mounts[0].Options[1] != "rw" {
+		t.Fatalf("expected writable data mount under quota, got %v", spec.Mounts[0].Options)
+	}
+
+	if err := os.WriteFile(filepath.Join(dataDir, "big"), make([]byte, 2<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec = newSpec()
+	if err := m.ApplyResources(context.Background(), botID, &spec); err != nil {
+		t.Fatalf("apply resources: %v", err)
+	}
+	if spec.Mounts[0].Options[1] != "ro" {
+		t.Fatalf("expected read-only data mount over quota, got %v", spec.Mounts[0].Options)
+	}
+}
+
+type diskFakeService struct {
+	ctr.Service
+	task       ctr.TaskInfo
+	remountErr error
+	remounts   []bool
+	stops      int
+}
+
+func (s *diskFakeService) GetTaskInfo(context.Context, string) (ctr.TaskInfo, error) {
+	if s.task.Status != ctr.TaskStatusRunning {
+		return ctr.TaskInfo{}, errdefs.ErrNotFound
+	}
+	return s.task, nil
+}
+
+func (s *diskFakeService) RemountReadOnly(_ context.Context, _ string, _ string, readOnly bool) error {
+	s.remounts = append(s.remounts, readOnly)
+	return s.remountErr
+}
+
+func (s *diskFakeService) StopContainer(context.Context, string, *ctr.StopTaskOptions) error {
+	s.stops++
+	s.task.Status = ctr.TaskStatusStopped
+	return nil
+}
+
+func TestEnforceDiskQuota(t *testing.T) {
+	botID := "3f1c6a2e-8d4b-4c1e-9a7f-2b5d6e8f0a1c"
+	root := t.TempDir()
+	service := &diskFakeService{task: ctr.TaskInfo{PID: 42, Status: ctr.TaskStatusRunning}}
+	m := &Manager{
+		service:     service,
+		cfg:         config.MCPConfig{DataRoot: root, Resources: config.ContainerResourcesConfig{DiskMB: 1}},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		containerID: func(botID string) string { return ContainerPrefix + botID },
+	}
+	dataDir := filepath.Join(root, "bots", botID)
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	big := filepath.Join(dataDir, "big")
+	ctx := context.Background()
+
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		t.Fatalf("enforce under quota: %v", err)
+	}
+	if err := os.WriteFile(big, make([]byte, 2<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		t.Fatalf("enforce over quota: %v", err)
+	}
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		t.Fatalf("enforce over quota again: %v", err)
+	}
+	if err := os.Remove(big); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		t.Fatalf("enforce after cleanup: %v", err)
+	}
+	if want := []bool{false, true, false}; len(service.remounts) != len(want) ||
+		service.remounts[0] != want[0] || service.remounts[1] != want[1] || service.remounts[2] != want[2] {
+		t.Fatalf("expected remounts %v, got %v", want, service.remounts)
+	}
+
+	// A task created read-only over quota stays unsettled until the remount to writable succeeds.
+	service.task.PID = 43
+	service.remounts = nil
+	service.remountErr = errors.New("remount failed")
+	if err := m.enforceDisk(ctx, botID); err == nil {
+		t.Fatal("expected the failed remount to be reported")
+	}
+	service.remountErr = nil
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		t.Fatalf("enforce after remount recovers: %v", err)
+	}
+	if err := m.enforceDisk(ctx, botID); err != nil {
+		t.Fatalf("enforce once writable: %v", err)
+	}
+	if want := []bool{false, false}; len(service.remounts) != len(want) || service.remounts[0] != want[0] || service.remounts[1] != want[1] {
+		t.Fatalf("expected the writable remount to be retried once, got %v", service.remounts)
+	}
+
+	service.remountErr = ctr.ErrNotSupported
+	if err := os.WriteFile(big, make([]byte, 2<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.enforceDisk(ctx, botID); !errors.Is(err, ErrDiskQuotaExceeded) {
+		t.Fatalf("expected ErrDiskQuotaExceeded, got %v", err)
+	}
+	if service.stops != 1 {
+		t.Fatalf("expected the container to be stopped once, got %d", service.stops)
+	}
+}
@@ -137,7 +137,7 @@ func (m *Manager) CreateVersion(ctx context.Context, botID string) (*VersionInfo
 		return nil, err
 	}
 
-	spec, err := m.buildVersionSpec(botID)
+	spec, err := m.buildVersionSpec(ctx, botID)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +250,7 @@ func (m *Manager) RollbackVersion(ctx context.Context, botID string, version int
 		return err
 	}
 
-	spec, err := m.buildVersionSpec(botID)
+	spec, err := m.buildVersionSpec(ctx, botID)
 	if err != nil {
 		return err
 	}
@@ -289,7 +289,7 @@ func (m *Manager) VersionSnapshotName(ctx context.Context, botID string, version
 	})
 }
 
-func (m *Manager) buildVersionSpec(botID string) (ctr.ContainerSpec, error) {
+func (m *Manager) buildVersionSpec(ctx context.Context, botID string) (ctr.ContainerSpec, error) {
 	dataDir, err := m.ensureBotDir(botID)
 	if err != nil {
 		return ctr.ContainerSpec{}, err
@@ -315,10 +315,14 @@ func (m *Manager) buildVersionSpec(botID string) (ctr.ContainerSpec, error) {
 	}
 	tzMounts, tzEnv := ctr.TimezoneSpec()
 	mounts = append(mounts, tzMounts...)
-	return ctr.ContainerSpec{
+	spec := ctr.ContainerSpec{
 		Mounts: mounts,
 		Env:    tzEnv,
-	}, nil
+	}
+	if err := m.ApplyResources(ctx, botID, &spec); err != nil {
+		return ctr.ContainerSpec{}, err
+	}
+	return spec, nil
 }
 
 func (m *Manager) safeStopTask(ctx context.Context, containerID string) error {
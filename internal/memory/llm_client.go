@@ -185,6 +185,53 @@ func (c *LLMClient) DetectLanguage(ctx context.Context, text string) (string, er
 	return lang, nil
 }
 
+// IsAddressed reports whether a group chat message is directed at the assistant, which
+// goes by the given names.
+func (c *LLMClient) IsAddressed(ctx context.Context, text string, names []string) (bool, error) {
+	if strings.TrimSpace(text) == "" {
+		return false, fmt.Errorf("text is required")
+	}
+	systemPrompt, userPrompt := getAddressedDetectionMessages(text, names)
+	content, err := c.callChat(ctx, []chatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	})
+	if err != nil {
+		return false, err
+	}
+	var parsed struct {
+		Addressed bool `json:"addressed"`
+	}
+	if err := json.Unmarshal([]byte(removeCodeBlocks(content)), &parsed); err != nil {
+		return false, err
+	}
+	return parsed.Addressed, nil
+}
+
+// Moderate reports whether a message posted to the bot is spam, scam or abuse, with a
+// short reason when it is.
+func (c *LLMClient) Moderate(ctx context.Context, text string) (bool, string, error) {
+	if strings.TrimSpace(text) == "" {
+		return false, "", fmt.Errorf("text is required")
+	}
+	systemPrompt, userPrompt := getModerationMessages(text)
+	content, err := c.callChat(ctx, []chatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	})
+	if err != nil {
+		return false, "", err
+	}
+	var parsed struct {
+		Flagged bool   `json:"flagged"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(removeCodeBlocks(content)), &parsed); err != nil {
+		return false, "", err
+	}
+	return parsed.Flagged, strings.TrimSpace(parsed.Reason), nil
+}
+
 type chatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
@@ -34,3 +34,55 @@ func TestLLMClientExtract(t *testing.T) {
 		t.Fatalf("unexpected response: %+v", resp)
 	}
 }
+
+func TestLLMClientIsAddressed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"addressed\":true}"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewLLMClient(nil, server.URL, "test-key", "gpt-4.1-nano-2025-04-14", 0)
+	if err != nil {
+		t.Fatalf("new llm client: %v", err)
+	}
+	addressed, err := client.IsAddressed(context.Background(), "memo, can you check the build?", []string{"memo"})
+	if err != nil {
+		t.Fatalf("is addressed: %v", err)
+	}
+	if !addressed {
+		t.Fatalf("expected message to be addressed")
+	}
+}
+
+func TestLLMClientModerate(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"flagged\":true,\"reason\":\"crypto scam\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewLLMClient(nil, server.URL, "test-key", "gpt-4.1-nano-2025-04-14", 0)
+	if err != nil {
+		t.Fatalf("new llm client: %v", err)
+	}
+	flagged, reason, err := client.Moderate(context.Background(), "Double your BTC in 24h, DM me")
+	if err != nil {
+		t.Fatalf("moderate: %v", err)
+	}
+	if !flagged || reason != "crypto scam" {
+		t.Fatalf("unexpected result flagged=%v reason=%q", flagged, reason)
+	}
+}
@@ -53,6 +53,7 @@ Remember the following:
 - If the user asks where you fetched my information, answer that you found from publicly available sources on internet.
 - If you do not find anything relevant in the below conversation, you can return an empty list corresponding to the "facts" key.
 - Create the facts based on the user and assistant messages only. Do not pick anything from the system messages.
+- Messages with the role "operator" were written by a human operator replying on the assistant's behalf. Treat them like assistant messages.
 - Make sure to return the response in the JSON format mentioned in the examples. The response should be in JSON with a key as "facts" and corresponding value will be a list of strings.
 - DO NOT RETURN ANYTHING ELSE OTHER THAN THE JSON FORMAT.
 - DO NOT ADD ANY ADDITIONAL TEXT OR CODEBLOCK IN THE JSON FIELDS WHICH MAKE IT INVALID SUCH AS "%s" OR "%s".
@@ -156,6 +157,31 @@ Before finalizing, verify the value is one of the allowed codes.`
 	return systemPrompt, userPrompt
 }
 
+func getAddressedDetectionMessages(text string, names []string) (string, string) {
+	systemPrompt := `You decide whether a message posted in a group chat is directed at the assistant.
+Return a JSON object with a single boolean key "addressed".
+Answer true when the message asks the assistant something, gives it an instruction, or refers to it by one of its names.
+Answer false for conversation between other participants, general remarks, and messages that only mention the assistant in passing.
+Do not include any extra keys, comments, or formatting. Output must be valid JSON only.`
+	nameList := "(none)"
+	if len(names) > 0 {
+		nameList = strings.Join(names, ", ")
+	}
+	userPrompt := fmt.Sprintf("Assistant names: %s\nMessage:\n%s", nameList, text)
+	return systemPrompt, userPrompt
+}
+
+func getModerationMessages(text string) (string, string) {
+	systemPrompt := `You moderate messages sent to an assistant in a chat.
+Return a JSON object with a boolean key "flagged" and a string key "reason".
+Flag spam, advertising, scams, phishing, harassment, hate speech and sexual content involving minors.
+Do not flag ordinary conversation, criticism, profanity without a target, or questions about sensitive topics.
+When flagged, "reason" is a few words naming the category; otherwise it is an empty string.
+Do not include any extra keys, comments, or formatting. Output must be valid JSON only.`
+	userPrompt := fmt.Sprintf("Message:\n%s", text)
+	return systemPrompt, userPrompt
+}
+
 func removeCodeBlocks(text string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(text, "```json", ""), "```", "")
 }
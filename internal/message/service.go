@@ -3,11 +3,13 @@ package message
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	dbpkg "github.com/memohai/memoh/internal/db"
@@ -231,6 +233,202 @@ func (s *DBService) DeleteByBot(ctx context.Context, botID string) error {
 	return s.queries.DeleteMessagesByBot(ctx, pgBotID)
 }
 
+// LinkOutboundMessageIDs stores the platform IDs of a delivered reply on the latest
+// assistant message of the route persisted since input.Since. The first ID becomes
+// the message's external ID; all IDs are kept in metadata.platform_message_ids.
+func (s *DBService) LinkOutboundMessageIDs(ctx context.Context, input LinkOutboundInput) error {
+	if len(input.MessageIDs) == 0 {
+		return nil
+	}
+	pgBotID, err := dbpkg.ParseUUID(input.BotID)
+	if err != nil {
+		return fmt.Errorf("invalid bot id: %w", err)
+	}
+	pgRouteID, err := dbpkg.ParseUUID(input.RouteID)
+	if err != nil {
+		return fmt.Errorf("invalid route id: %w", err)
+	}
+	ids, err := json.Marshal(input.MessageIDs)
+	if err != nil {
+		return fmt.Errorf("marshal platform message ids: %w", err)
+	}
+	linked, err := s.queries.LinkLatestAssistantMessageExternalIDs(ctx, sqlc.LinkLatestAssistantMessageExternalIDsParams{
+		ExternalMessageID:  input.MessageIDs[0],
+		PlatformMessageIds: ids,
+		BotID:              pgBotID,
+		RouteID:            pgRouteID,
+		Since:              pgtype.Timestamptz{Time: input.Since, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if linked == 0 {
+		s.logger.Debug("no assistant message to link outbound ids", slog.String("route_id", input.RouteID))
+	}
+	return nil
+}
+
+// LatestOutboundMessageID returns the platform message ID of the bot's latest
+// delivered reply in a conversation, or an empty string when none is recorded.
+func (s *DBService) LatestOutboundMessageID(ctx context.Context, botID, platform, replyTarget string) (string, error) {
+	pgBotID, err := dbpkg.ParseUUID(botID)
+	if err != nil {
+		return "", fmt.Errorf("invalid bot id: %w", err)
+	}
+	id, err := s.queries.GetLatestAssistantExternalMessageID(ctx, sqlc.GetLatestAssistantExternalMessageIDParams{
+		BotID:       pgBotID,
+		Platform:    strings.TrimSpace(platform),
+		ReplyTarget: strings.TrimSpace(replyTarget),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return dbpkg.TextToString(id), nil
+}
+
+// IsOutboundMessageID reports whether a platform message ID was delivered by the bot
+// to the target, either as a reply in history or as a recorded outbound delivery.
+func (s *DBService) IsOutboundMessageID(ctx context.Context, botID, platform, target, messageID string) (bool, error) {
+	pgBotID, err := dbpkg.ParseUUID(botID)
+	if err != nil {
+		return false, fmt.Errorf("invalid bot id: %w", err)
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return false, nil
+	}
+	return s.queries.IsBotOutboundMessage(ctx, sqlc.IsBotOutboundMessageParams{
+		BotID:             pgBotID,
+		Platform:          strings.TrimSpace(platform),
+		Target:            strings.TrimSpace(target),
+		ExternalMessageID: messageID,
+	})
+}
+
+// ApplyEdit replaces the content of a user message that was edited on its platform.
+// The first persisted content is kept in metadata.original_content.
+func (s *DBService) ApplyEdit(ctx context.Context, input EditInput) (bool, error) {
+	pgBotID, err := dbpkg.ParseUUID(input.BotID)
+	if err != nil {
+		return false, fmt.Errorf("invalid bot id: %w", err)
+	}
+	if len(input.Content) == 0 {
+		return false, fmt.Errorf("content is required")
+	}
+	updated, err := s.queries.EditMessageBySource(ctx, sqlc.EditMessageBySourceParams{
+		Content:           input.Content,
+		EditedAt:          toPgTimestamptz(input.EditedAt),
+		BotID:             pgBotID,
+		Platform:          strings.TrimSpace(input.Platform),
+		ExternalMessageID: strings.TrimSpace(input.ExternalMessageID),
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// ApplyDelete soft-deletes the messages matching a platform message ID by setting
+// metadata.deleted_at. Deleted messages stay listed but leave the active context.
+func (s *DBService) ApplyDelete(ctx context.Context, input DeleteInput) (bool, error) {
+	pgBotID, err := dbpkg.ParseUUID(input.BotID)
+	if err != nil {
+		return false, fmt.Errorf("invalid bot id: %w", err)
+	}
+	deleted, err := s.queries.SoftDeleteMessagesBySource(ctx, sqlc.SoftDeleteMessagesBySourceParams{
+		DeletedAt:         toPgTimestamptz(input.DeletedAt),
+		BotID:             pgBotID,
+		Platform:          strings.TrimSpace(input.Platform),
+		ExternalMessageID: strings.TrimSpace(input.ExternalMessageID),
+	})
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// ApplyReaction records a reaction in metadata.reactions of the reacted message,
+// keyed by emoji with the reacting channel identities as values.
+func (s *DBService) ApplyReaction(ctx context.Context, input ReactionInput) (bool, error) {
+	pgBotID, err := dbpkg.ParseUUID(input.BotID)
+	if err != nil {
+		return false, fmt.Errorf("invalid bot id: %w", err)
+	}
+	row, err := s.queries.GetMessageMetadataBySource(ctx, sqlc.GetMessageMetadataBySourceParams{
+		BotID:             pgBotID,
+		Platform:          strings.TrimSpace(input.Platform),
+		ExternalMessageID: strings.TrimSpace(input.ExternalMessageID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	meta := nonNilMap(parseJSONMap(row.Metadata))
+	if !applyReaction(meta, input.Emoji, input.ReactorID, input.Removed) {
+		return true, nil
+	}
+	payload, err := json.Marshal(meta)
+	if err != nil {
+		return false, fmt.Errorf("marshal metadata: %w", err)
+	}
+	if err := s.queries.UpdateMessageMetadata(ctx, sqlc.UpdateMessageMetadataParams{
+		Metadata: payload,
+		ID:       row.ID,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// applyReaction adds or removes reactor under meta["reactions"][emoji] and reports
+// whether meta changed.
+func applyReaction(meta map[string]any, emoji, reactor string, removed bool) bool {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" {
+		return false
+	}
+	reactor = coalesce(strings.TrimSpace(reactor), "unknown")
+	reactions, _ := meta["reactions"].(map[string]any)
+	if reactions == nil {
+		reactions = map[string]any{}
+	}
+	var reactors []any
+	if existing, ok := reactions[emoji].([]any); ok {
+		reactors = existing
+	}
+	index := -1
+	for i, item := range reactors {
+		if value, ok := item.(string); ok && value == reactor {
+			index = i
+			break
+		}
+	}
+	switch {
+	case removed && index < 0, !removed && index >= 0:
+		return false
+	case removed:
+		reactors = append(reactors[:index], reactors[index+1:]...)
+	default:
+		reactors = append(reactors, reactor)
+	}
+	if len(reactors) == 0 {
+		delete(reactions, emoji)
+	} else {
+		reactions[emoji] = reactors
+	}
+	if len(reactions) == 0 {
+		delete(meta, "reactions")
+	} else {
+		meta["reactions"] = reactions
+	}
+	return true
+}
+
 func toMessageFromCreate(row sqlc.CreateMessageRow) Message {
 	return toMessageFields(
 		row.ID,
@@ -459,6 +657,13 @@ func coalesce(values ...string) string {
 	return ""
 }
 
+func toPgTimestamptz(t time.Time) pgtype.Timestamptz {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
 func toPgInt8(v int64) pgtype.Int8 {
 	if v == 0 {
 		return pgtype.Int8{}
@@ -64,6 +64,42 @@ type PersistInput struct {
 	Assets                  []AssetRef
 }
 
+// LinkOutboundInput carries the platform IDs of a delivered assistant reply.
+type LinkOutboundInput struct {
+	BotID      string
+	RouteID    string
+	MessageIDs []string
+	Since      time.Time
+}
+
+// EditInput carries the new content of a message that was edited on its platform.
+type EditInput struct {
+	BotID             string
+	Platform          string
+	ExternalMessageID string
+	Content           json.RawMessage
+	EditedAt          time.Time
+}
+
+// DeleteInput identifies a message that was deleted on its platform.
+type DeleteInput struct {
+	BotID             string
+	Platform          string
+	ExternalMessageID string
+	DeletedAt         time.Time
+}
+
+// ReactionInput describes a reaction added to or removed from a message.
+type ReactionInput struct {
+	BotID             string
+	Platform          string
+	ExternalMessageID string
+	Emoji             string
+	// ReactorID is the channel identity ID of the user who reacted.
+	ReactorID string
+	Removed   bool
+}
+
 // Writer defines write behavior needed by the inbound router.
 type Writer interface {
 	Persist(ctx context.Context, input PersistInput) (Message, error)
@@ -79,3 +115,16 @@ type Service interface {
 	ListBefore(ctx context.Context, botID string, before time.Time, limit int32) ([]Message, error)
 	DeleteByBot(ctx context.Context, botID string) error
 }
+
+// OutboundLinker attaches delivered platform message IDs to persisted assistant replies.
+type OutboundLinker interface {
+	LinkOutboundMessageIDs(ctx context.Context, input LinkOutboundInput) error
+}
+
+// HistoryUpdater applies platform-side edits, deletions and reactions to persisted
+// messages. Each method reports whether a matching message was found.
+type HistoryUpdater interface {
+	ApplyEdit(ctx context.Context, input EditInput) (bool, error)
+	ApplyDelete(ctx context.Context, input DeleteInput) (bool, error)
+	ApplyReaction(ctx context.Context, input ReactionInput) (bool, error)
+}
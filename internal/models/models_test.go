@@ -203,6 +203,24 @@ func TestModel_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid transcription model",
+			model: models.Model{
+				ModelID:       "whisper-1",
+				LlmProviderID: "11111111-1111-1111-1111-111111111111",
+				Type:          models.ModelTypeTranscription,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid speech model",
+			model: models.Model{
+				ModelID:       "tts-1",
+				LlmProviderID: "11111111-1111-1111-1111-111111111111",
+				Type:          models.ModelTypeSpeech,
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid input modality",
 			model: models.Model{
@@ -283,6 +301,8 @@ func TestModelTypes(t *testing.T) {
 	t.Run("ModelType constants", func(t *testing.T) {
 		assert.Equal(t, models.ModelType("chat"), models.ModelTypeChat)
 		assert.Equal(t, models.ModelType("embedding"), models.ModelTypeEmbedding)
+		assert.Equal(t, models.ModelType("transcription"), models.ModelTypeTranscription)
+		assert.Equal(t, models.ModelType("speech"), models.ModelTypeSpeech)
 	})
 
 	t.Run("ClientType constants", func(t *testing.T) {
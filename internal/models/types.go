@@ -12,6 +12,12 @@ type ModelType string
 const (
 	ModelTypeChat      ModelType = "chat"
 	ModelTypeEmbedding ModelType = "embedding"
+	// ModelTypeTranscription is a speech-to-text model served by an OpenAI-compatible
+	// /audio/transcriptions endpoint.
+	ModelTypeTranscription ModelType = "transcription"
+	// ModelTypeSpeech is a text-to-speech model served by an OpenAI-compatible
+	// /audio/speech endpoint.
+	ModelTypeSpeech ModelType = "speech"
 )
 
 const (
@@ -58,7 +64,7 @@ func (m *Model) Validate() error {
 	if _, err := uuid.Parse(m.LlmProviderID); err != nil {
 		return errors.New("llm provider ID must be a valid UUID")
 	}
-	if m.Type != ModelTypeChat && m.Type != ModelTypeEmbedding {
+	if !isValidModelType(m.Type) {
 		return errors.New("invalid model type")
 	}
 	if m.Type == ModelTypeChat {
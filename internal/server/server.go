@@ -78,7 +78,13 @@ func shouldSkipJWT(path string) bool {
 	if strings.HasPrefix(path, "/api/docs") {
 		return true
 	}
-	if strings.HasPrefix(path, "/channels/feishu/webhook/") {
+	if strings.HasPrefix(path, "/channels/feishu/webhook/") || strings.HasPrefix(path, "/channels/telegram/webhook/") {
+		return true
+	}
+	if strings.HasPrefix(path, "/channels/webhook/") {
+		return true
+	}
+	if strings.HasPrefix(path, "/channels/wecom/callback/") || strings.HasPrefix(path, "/channels/dingtalk/callback/") {
 		return true
 	}
 	return false
@@ -21,3 +21,28 @@ func TestShouldSkipJWT_FeishuWebhookPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestShouldSkipJWT_SignedWebhookPaths(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{path: "/channels/webhook/cfg-1", want: true},
+		{path: "/channels/webhook", want: false},
+		{path: "/api/channels/webhook/cfg-1", want: false},
+		{path: "/channels/wecom/callback/cfg-1", want: true},
+		{path: "/channels/dingtalk/callback/cfg-1", want: true},
+		{path: "/channels/wecom/cfg-1", want: false},
+		{path: "/channels/telegram/webhook/cfg-1", want: true},
+		{path: "/channels/telegram/webhook", want: false},
+	}
+
+	for _, tc := range cases {
+		got := shouldSkipJWT(tc.path)
+		if got != tc.want {
+			t.Fatalf("path=%q want=%v got=%v", tc.path, tc.want, got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,6 +24,7 @@ type Service struct {
 var ErrPersonalBotGuestAccessUnsupported = errors.New("personal bots do not support guest access")
 var ErrModelIDAmbiguous = errors.New("model_id is ambiguous across providers")
 var ErrInvalidModelRef = errors.New("invalid model reference")
+var ErrInvalidTimezone = errors.New("invalid timezone")
 
 func NewService(log *slog.Logger, queries *sqlc.Queries) *Service {
 	return &Service{
@@ -122,6 +124,38 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 		}
 		heartbeatModelUUID = modelID
 	}
+	transcriptionModelUUID := pgtype.UUID{}
+	if value := strings.TrimSpace(req.TranscriptionModelID); value != "" {
+		modelID, err := s.resolveModelUUID(ctx, value)
+		if err != nil {
+			return Settings{}, err
+		}
+		transcriptionModelUUID = modelID
+	}
+	speechModelUUID := pgtype.UUID{}
+	if value := strings.TrimSpace(req.SpeechModelID); value != "" {
+		modelID, err := s.resolveModelUUID(ctx, value)
+		if err != nil {
+			return Settings{}, err
+		}
+		speechModelUUID = modelID
+	}
+	speechVoice := pgtype.Text{}
+	if req.SpeechVoice != nil && strings.TrimSpace(*req.SpeechVoice) != "" {
+		speechVoice = pgtype.Text{String: strings.TrimSpace(*req.SpeechVoice), Valid: true}
+	}
+	voiceReplyEnabled := pgtype.Bool{}
+	if req.VoiceReplyEnabled != nil {
+		voiceReplyEnabled = pgtype.Bool{Bool: *req.VoiceReplyEnabled, Valid: true}
+	}
+	timezone := pgtype.Text{}
+	if req.Timezone != nil && strings.TrimSpace(*req.Timezone) != "" {
+		name := strings.TrimSpace(*req.Timezone)
+		if _, err := time.LoadLocation(name); err != nil {
+			return Settings{}, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+		}
+		timezone = pgtype.Text{String: name, Valid: true}
+	}
 	searchProviderUUID := pgtype.UUID{}
 	if value := strings.TrimSpace(req.SearchProviderID); value != "" {
 		providerID, err := db.ParseUUID(value)
@@ -147,6 +181,11 @@ func (s *Service) UpsertBot(ctx context.Context, botID string, req UpsertRequest
 		MemoryModelID:      memoryModelUUID,
 		EmbeddingModelID:   embeddingModelUUID,
 		HeartbeatModelID:   heartbeatModelUUID,
+		TranscriptionModelID: transcriptionModelUUID,
+		SpeechModelID:        speechModelUUID,
+		SpeechVoice:          speechVoice,
+		VoiceReplyEnabled:    voiceReplyEnabled,
+		Timezone:             timezone,
 		SearchProviderID:   searchProviderUUID,
 	})
 	if err != nil {
@@ -223,6 +262,11 @@ func normalizeBotSettingsReadRow(row sqlc.GetSettingsByBotIDRow) Settings {
 		row.MemoryModelID,
 		row.EmbeddingModelID,
 		row.HeartbeatModelID,
+		row.TranscriptionModelID,
+		row.SpeechModelID,
+		row.SpeechVoice,
+		row.VoiceReplyEnabled,
+		row.Timezone,
 		row.SearchProviderID,
 	)
 }
@@ -242,6 +286,11 @@ func normalizeBotSettingsWriteRow(row sqlc.UpsertBotSettingsRow) Settings {
 		row.MemoryModelID,
 		row.EmbeddingModelID,
 		row.HeartbeatModelID,
+		row.TranscriptionModelID,
+		row.SpeechModelID,
+		row.SpeechVoice,
+		row.VoiceReplyEnabled,
+		row.Timezone,
 		row.SearchProviderID,
 	)
 }
@@ -260,6 +309,11 @@ func normalizeBotSettingsFields(
 	memoryModelID pgtype.UUID,
 	embeddingModelID pgtype.UUID,
 	heartbeatModelID pgtype.UUID,
+	transcriptionModelID pgtype.UUID,
+	speechModelID pgtype.UUID,
+	speechVoice string,
+	voiceReplyEnabled bool,
+	timezone string,
 	searchProviderID pgtype.UUID,
 ) Settings {
 	settings := normalizeBotSetting(maxContextLoadTime, maxContextTokens, maxInboxItems, language, allowGuest, reasoningEnabled, reasoningEffort, heartbeatEnabled, heartbeatInterval)
@@ -275,6 +329,21 @@ func normalizeBotSettingsFields(
 	if heartbeatModelID.Valid {
 		settings.HeartbeatModelID = uuid.UUID(heartbeatModelID.Bytes).String()
 	}
+	if transcriptionModelID.Valid {
+		settings.TranscriptionModelID = uuid.UUID(transcriptionModelID.Bytes).String()
+	}
+	if speechModelID.Valid {
+		settings.SpeechModelID = uuid.UUID(speechModelID.Bytes).String()
+	}
+	settings.SpeechVoice = strings.TrimSpace(speechVoice)
+	if settings.SpeechVoice == "" {
+		settings.SpeechVoice = DefaultSpeechVoice
+	}
+	settings.VoiceReplyEnabled = voiceReplyEnabled
+	settings.Timezone = strings.TrimSpace(timezone)
+	if settings.Timezone == "" {
+		settings.Timezone = DefaultTimezone
+	}
 	if searchProviderID.Valid {
 		settings.SearchProviderID = uuid.UUID(searchProviderID.Bytes).String()
 	}
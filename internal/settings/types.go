@@ -6,6 +6,8 @@ const (
 	DefaultLanguage           = "auto"
 	DefaultReasoningEffort    = "medium"
 	DefaultHeartbeatInterval  = 30
+	DefaultSpeechVoice        = "alloy"
+	DefaultTimezone           = "UTC"
 )
 
 type Settings struct {
@@ -23,6 +25,11 @@ type Settings struct {
 	HeartbeatEnabled  bool   `json:"heartbeat_enabled"`
 	HeartbeatInterval int    `json:"heartbeat_interval"`
 	HeartbeatModelID  string `json:"heartbeat_model_id"`
+	TranscriptionModelID string `json:"transcription_model_id"`
+	SpeechModelID        string `json:"speech_model_id"`
+	SpeechVoice          string `json:"speech_voice"`
+	VoiceReplyEnabled    bool   `json:"voice_reply_enabled"`
+	Timezone             string `json:"timezone"`
 }
 
 type UpsertRequest struct {
@@ -40,4 +47,9 @@ type UpsertRequest struct {
 	HeartbeatEnabled  *bool  `json:"heartbeat_enabled,omitempty"`
 	HeartbeatInterval *int   `json:"heartbeat_interval,omitempty"`
 	HeartbeatModelID  string `json:"heartbeat_model_id,omitempty"`
+	TranscriptionModelID string  `json:"transcription_model_id,omitempty"`
+	SpeechModelID        string  `json:"speech_model_id,omitempty"`
+	SpeechVoice          *string `json:"speech_voice,omitempty"`
+	VoiceReplyEnabled    *bool   `json:"voice_reply_enabled,omitempty"`
+	Timezone             *string `json:"timezone,omitempty"`
 }
@@ -0,0 +1,167 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIClient calls the audio endpoints of an OpenAI-compatible provider.
+type OpenAIClient struct {
+	apiKey  string
+	baseURL string
+	model   string
+	http    *http.Client
+}
+
+type openAITranscriptionResponse struct {
+	Text string `json:"text"`
+}
+
+type openAISpeechRequest struct {
+	Model          string `json:"model"`
+	Input          string `json:"input"`
+	Voice          string `json:"voice"`
+	ResponseFormat string `json:"response_format"`
+}
+
+func NewOpenAIClient(apiKey, baseURL, model string, timeout time.Duration) (*OpenAIClient, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		return nil, fmt.Errorf("openai speech: base url is required")
+	}
+	if strings.TrimSpace(model) == "" {
+		return nil, fmt.Errorf("openai speech: model is required")
+	}
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &OpenAIClient{
+		apiKey:  strings.TrimSpace(apiKey),
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		model:   strings.TrimSpace(model),
+		http: &http.Client{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+// Transcribe uploads audio to /audio/transcriptions and returns the recognised text.
+// The file name extension tells the provider which container format to decode.
+func (c *OpenAIClient) Transcribe(ctx context.Context, audio io.Reader, filename string) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("model", c.model); err != nil {
+		return "", err
+	}
+	if err := form.WriteField("response_format", "json"); err != nil {
+		return "", err
+	}
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return "", fmt.Errorf("read audio: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/audio/transcriptions", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("openai transcription error (%d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var parsed openAITranscriptionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("decode transcription response: %w", err)
+	}
+	return strings.TrimSpace(parsed.Text), nil
+}
+
+// Synthesize posts text to /audio/speech and returns Ogg/Opus audio, the format messengers
+// play back as voice notes.
+func (c *OpenAIClient) Synthesize(ctx context.Context, text, voice string) ([]byte, error) {
+	payload, err := json.Marshal(openAISpeechRequest{
+		Model:          c.model,
+		Input:          text,
+		Voice:          voice,
+		ResponseFormat: "opus",
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/audio/speech", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("openai speech error (%d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, MaxSpeechBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read speech response: %w", err)
+	}
+	if len(audio) > MaxSpeechBytes {
+		return nil, fmt.Errorf("openai speech: audio exceeds %d bytes", MaxSpeechBytes)
+	}
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("openai speech: empty audio")
+	}
+	return audio, nil
+}
+
+// audioFilename picks an upload file name whose extension matches the audio mime type.
+func audioFilename(mime string) string {
+	mime = strings.ToLower(strings.TrimSpace(mime))
+	switch {
+	case strings.HasPrefix(mime, "audio/ogg"), strings.HasPrefix(mime, "audio/opus"):
+		return "audio.ogg"
+	case strings.HasPrefix(mime, "audio/mpeg"), strings.HasPrefix(mime, "audio/mp3"):
+		return "audio.mp3"
+	case strings.HasPrefix(mime, "audio/mp4"), strings.HasPrefix(mime, "audio/m4a"), strings.HasPrefix(mime, "audio/x-m4a"), strings.HasPrefix(mime, "audio/aac"):
+		return "audio.m4a"
+	case strings.HasPrefix(mime, "audio/wav"), strings.HasPrefix(mime, "audio/x-wav"), strings.HasPrefix(mime, "audio/wave"):
+		return "audio.wav"
+	case strings.HasPrefix(mime, "audio/webm"), strings.HasPrefix(mime, "video/webm"):
+		return "audio.webm"
+	case strings.HasPrefix(mime, "audio/flac"), strings.HasPrefix(mime, "audio/x-flac"):
+		return "audio.flac"
+	case strings.HasPrefix(mime, "video/mp4"):
+		return "audio.mp4"
+	default:
+		// Ogg/Opus is what most messengers use for voice notes.
+		return "audio.ogg"
+	}
+}
@@ -0,0 +1,116 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAIClient_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sk-test" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		if got := r.FormValue("model"); got != "whisper-1" {
+			t.Errorf("unexpected model %q", got)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		defer file.Close()
+		if header.Filename != "audio.mp3" {
+			t.Errorf("unexpected filename %q", header.Filename)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "fake-audio" {
+			t.Errorf("unexpected file content %q", data)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":" hello world "}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewOpenAIClient("sk-test", srv.URL+"/v1/", "whisper-1", time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	text, err := client.Transcribe(context.Background(), strings.NewReader("fake-audio"), audioFilename("audio/mpeg"))
+	if err != nil {
+		t.Fatalf("transcribe: %v", err)
+	}
+	if text != "hello world" {
+		t.Fatalf("unexpected text %q", text)
+	}
+}
+
+func TestOpenAIClient_TranscribeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad audio", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	client, err := NewOpenAIClient("", srv.URL, "whisper-1", time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := client.Transcribe(context.Background(), strings.NewReader("x"), "audio.ogg"); err == nil || !strings.Contains(err.Error(), "bad audio") {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+}
+
+func TestOpenAIClient_Synthesize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req openAISpeechRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Model != "tts-1" || req.Input != "hello" || req.Voice != "nova" || req.ResponseFormat != "opus" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		w.Header().Set("Content-Type", "audio/ogg")
+		_, _ = w.Write([]byte("OggS-fake"))
+	}))
+	defer srv.Close()
+
+	client, err := NewOpenAIClient("sk-test", srv.URL, "tts-1", time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	audio, err := client.Synthesize(context.Background(), "hello", "nova")
+	if err != nil {
+		t.Fatalf("synthesize: %v", err)
+	}
+	if string(audio) != "OggS-fake" {
+		t.Fatalf("unexpected audio %q", audio)
+	}
+}
+
+func TestAudioFilename(t *testing.T) {
+	cases := map[string]string{
+		"audio/ogg; codecs=opus": "audio.ogg",
+		"audio/mpeg":             "audio.mp3",
+		"audio/x-m4a":            "audio.m4a",
+		"audio/wav":              "audio.wav",
+		"":                       "audio.ogg",
+	}
+	for mime, want := range cases {
+		if got := audioFilename(mime); got != want {
+			t.Errorf("audioFilename(%q) = %q, want %q", mime, got, want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package speech
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/memohai/memoh/internal/channel/route"
+	"github.com/memohai/memoh/internal/db"
+	"github.com/memohai/memoh/internal/db/sqlc"
+	"github.com/memohai/memoh/internal/media"
+	"github.com/memohai/memoh/internal/models"
+)
+
+const (
+	// MaxTranscriptionBytes is the largest audio file sent for transcription (the OpenAI limit).
+	MaxTranscriptionBytes = 25 << 20
+	// MaxSpeechInputChars is the longest text synthesized in one request (the OpenAI limit).
+	MaxSpeechInputChars = 4096
+	// MaxSpeechBytes caps the size of synthesized audio.
+	MaxSpeechBytes = 16 << 20
+	// SpeechMime is the mime type of synthesized audio (Ogg/Opus).
+	SpeechMime = "audio/ogg"
+)
+
+// ErrNotConfigured is returned when the bot has no transcription or speech model selected.
+var ErrNotConfigured = errors.New("speech: model not configured")
+
+// assetStore reads and writes stored media bytes.
+type assetStore interface {
+	Open(ctx context.Context, botID, contentHash string) (io.ReadCloser, media.Asset, error)
+	Ingest(ctx context.Context, input media.IngestInput) (media.Asset, error)
+}
+
+// Service transcribes stored audio with the bot's configured transcription model and
+// synthesizes voice replies with its speech model. Transcripts are cached per bot,
+// content hash and model.
+type Service struct {
+	queries *sqlc.Queries
+	assets  assetStore
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+func NewService(log *slog.Logger, queries *sqlc.Queries, assets assetStore) *Service {
+	return &Service{
+		queries: queries,
+		assets:  assets,
+		timeout: 60 * time.Second,
+		logger:  log.With(slog.String("service", "speech")),
+	}
+}
+
+// Transcribe returns the transcript of a stored audio asset.
+func (s *Service) Transcribe(ctx context.Context, botID, contentHash string) (string, error) {
+	if s == nil || s.queries == nil {
+		return "", ErrNotConfigured
+	}
+	contentHash = strings.TrimSpace(contentHash)
+	if contentHash == "" {
+		return "", errors.New("speech: content hash is required")
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return "", err
+	}
+	settings, err := s.queries.GetSettingsByBotID(ctx, pgBotID)
+	if err != nil {
+		return "", fmt.Errorf("speech: load settings: %w", err)
+	}
+	if !settings.TranscriptionModelID.Valid {
+		return "", ErrNotConfigured
+	}
+	model, err := s.queries.GetModelByID(ctx, settings.TranscriptionModelID)
+	if err != nil {
+		return "", fmt.Errorf("speech: load model: %w", err)
+	}
+	if models.ModelType(model.Type) != models.ModelTypeTranscription {
+		return "", fmt.Errorf("speech: model %s is not a transcription model", model.ModelID)
+	}
+
+	cached, err := s.queries.GetMediaTranscription(ctx, sqlc.GetMediaTranscriptionParams{
+		BotID:       pgBotID,
+		ContentHash: contentHash,
+		ModelID:     model.ID,
+	})
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		s.logger.Warn("transcription cache lookup failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+
+	if s.assets == nil {
+		return "", errors.New("speech: media service not configured")
+	}
+	reader, asset, err := s.assets.Open(ctx, botID, contentHash)
+	if err != nil {
+		return "", fmt.Errorf("speech: open audio: %w", err)
+	}
+	defer reader.Close()
+	if asset.SizeBytes > MaxTranscriptionBytes {
+		return "", fmt.Errorf("speech: audio too large (%d bytes)", asset.SizeBytes)
+	}
+
+	provider, err := s.queries.GetLlmProviderByID(ctx, model.LlmProviderID)
+	if err != nil {
+		return "", fmt.Errorf("speech: load provider: %w", err)
+	}
+	client, err := NewOpenAIClient(provider.ApiKey, provider.BaseUrl, model.ModelID, s.timeout)
+	if err != nil {
+		return "", err
+	}
+	text, err := client.Transcribe(ctx, io.LimitReader(reader, MaxTranscriptionBytes), audioFilename(asset.Mime))
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.queries.UpsertMediaTranscription(ctx, sqlc.UpsertMediaTranscriptionParams{
+		BotID:       pgBotID,
+		ContentHash: contentHash,
+		ModelID:     model.ID,
+		Text:        text,
+	}); err != nil {
+		s.logger.Warn("transcription cache store failed", slog.String("bot_id", botID), slog.Any("error", err))
+	}
+	return text, nil
+}
+
+// ChatModelAcceptsAudio reports whether the chat model answering in the bot's chat takes
+// audio input, so voice messages can be passed to it without a transcript. The chat's
+// model override wins over the bot's chat model, as in the conversation resolver.
+func (s *Service) ChatModelAcceptsAudio(ctx context.Context, botID string) bool {
+	if s == nil || s.queries == nil {
+		return false
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return false
+	}
+	modelID := pgtype.UUID{}
+	if row, err := s.queries.GetChatSettings(ctx, pgBotID); err == nil {
+		modelID = row.ModelID
+	}
+	if !modelID.Valid {
+		settings, err := s.queries.GetSettingsByBotID(ctx, pgBotID)
+		if err != nil {
+			s.logger.Warn("load chat model settings failed", slog.String("bot_id", botID), slog.Any("error", err))
+			return false
+		}
+		modelID = settings.ChatModelID
+	}
+	if !modelID.Valid {
+		return false
+	}
+	model, err := s.queries.GetModelByID(ctx, modelID)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(model.InputModalities, models.ModelInputAudio)
+}
+
+// VoiceReplyEnabled reports whether replies in a conversation should carry a synthesized
+// voice message. The route's voice_reply metadata overrides the bot setting; a speech
+// model must be configured either way.
+func (s *Service) VoiceReplyEnabled(ctx context.Context, botID, routeID string) bool {
+	if s == nil || s.queries == nil {
+		return false
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return false
+	}
+	settings, err := s.queries.GetSettingsByBotID(ctx, pgBotID)
+	if err != nil {
+		s.logger.Warn("load voice reply settings failed", slog.String("bot_id", botID), slog.Any("error", err))
+		return false
+	}
+	if !settings.SpeechModelID.Valid {
+		return false
+	}
+	enabled := settings.VoiceReplyEnabled
+	if override, ok := s.routeVoiceReply(ctx, routeID); ok {
+		enabled = override
+	}
+	return enabled
+}
+
+func (s *Service) routeVoiceReply(ctx context.Context, routeID string) (bool, bool) {
+	if strings.TrimSpace(routeID) == "" {
+		return false, false
+	}
+	pgRouteID, err := db.ParseUUID(routeID)
+	if err != nil {
+		return false, false
+	}
+	row, err := s.queries.GetChatRouteByID(ctx, pgRouteID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("load route voice reply override failed", slog.String("route_id", routeID), slog.Any("error", err))
+		}
+		return false, false
+	}
+	if len(row.Metadata) == 0 {
+		return false, false
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+		return false, false
+	}
+	value, ok := metadata[route.MetadataVoiceReply].(bool)
+	return value, ok
+}
+
+// Synthesize converts text to speech with the bot's speech model and stores the audio
+// in the bot's media store.
+func (s *Service) Synthesize(ctx context.Context, botID, text string) (media.Asset, error) {
+	if s == nil || s.queries == nil {
+		return media.Asset{}, ErrNotConfigured
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return media.Asset{}, errors.New("speech: text is required")
+	}
+	if len([]rune(text)) > MaxSpeechInputChars {
+		return media.Asset{}, fmt.Errorf("speech: text exceeds %d characters", MaxSpeechInputChars)
+	}
+	pgBotID, err := db.ParseUUID(botID)
+	if err != nil {
+		return media.Asset{}, err
+	}
+	settings, err := s.queries.GetSettingsByBotID(ctx, pgBotID)
+	if err != nil {
+		return media.Asset{}, fmt.Errorf("speech: load settings: %w", err)
+	}
+	if !settings.SpeechModelID.Valid {
+		return media.Asset{}, ErrNotConfigured
+	}
+	model, err := s.queries.GetModelByID(ctx, settings.SpeechModelID)
+	if err != nil {
+		return media.Asset{}, fmt.Errorf("speech: load model: %w", err)
+	}
+	if models.ModelType(model.Type) != models.ModelTypeSpeech {
+		return media.Asset{}, fmt.Errorf("speech: model %s is not a speech model", model.ModelID)
+	}
+	provider, err := s.queries.GetLlmProviderByID(ctx, model.LlmProviderID)
+	if err != nil {
+		return media.Asset{}, fmt.Errorf("speech: load provider: %w", err)
+	}
+	client, err := NewOpenAIClient(provider.ApiKey, provider.BaseUrl, model.ModelID, s.timeout)
+	if err != nil {
+		return media.Asset{}, err
+	}
+	voice := strings.TrimSpace(settings.SpeechVoice)
+	if voice == "" {
+		voice = "alloy"
+	}
+	audio, err := client.Synthesize(ctx, text, voice)
+	if err != nil {
+		return media.Asset{}, err
+	}
+	if s.assets == nil {
+		return media.Asset{}, errors.New("speech: media service not configured")
+	}
+	asset, err := s.assets.Ingest(ctx, media.IngestInput{
+		BotID:       botID,
+		Mime:        SpeechMime,
+		Reader:      bytes.NewReader(audio),
+		MaxBytes:    MaxSpeechBytes,
+		OriginalExt: ".ogg",
+	})
+	if err != nil {
+		return media.Asset{}, fmt.Errorf("speech: store audio: %w", err)
+	}
+	return asset, nil
+}
@@ -102,6 +102,10 @@ Use ${quote('search_memory')} to recall earlier conversations beyond the current
 
 **${quote('react')} tool:** Add or remove an emoji reaction on a specific message (any channel).
 
+**${quote('reply_to')} tool:** Quote-reply a specific message by its ${quote('message_id')} on platforms that support threaded replies. Pass ${quote('thread_name')} to open a new thread from that message instead (Discord); ${quote('send')} also accepts ${quote('thread_id')} to post into an existing thread.
+
+**${quote('edit_message')} / ${quote('delete_message')} tools:** Correct or retract a message you sent earlier. ${quote('send')} and ${quote('reply_to')} return the ${quote('message_ids')} they delivered; omit ${quote('message_id')} to target your latest reply in the current conversation.
+
 ### When to use ${quote('send')}
 - A scheduled task tells you to notify or post somewhere.
 - You want to forward information to a different group or person.